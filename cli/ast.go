@@ -53,7 +53,8 @@ func assembleOutFile(file string, tag string) string {
 
 func printJsAst(src, file string, perf bool) (string, error) {
 	opts := parser.NewParserOpts()
-	s := span.NewSource("", src)
+	opts.Auto = true
+	s := span.NewSource(file, src)
 	p := parser.NewParser(s, opts)
 
 	var ast parser.Node
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"plugin"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/transform"
+)
+
+// applies the transforms to the files in a directory, for example:
+//
+//	mole codemod -t cjs-to-esm -dry ./src
+//
+// the transforms are either the built-in ones or the ones registered by the go plugins
+// specified by `-plugin`, the plugins register their transforms by `transform.Register`
+// in their `init` functions
+type Codemod struct {
+}
+
+func (c *Codemod) Process(opts *Options) bool {
+	if flag.Arg(0) != "codemod" {
+		return false
+	}
+
+	fs := flag.NewFlagSet("codemod", flag.ExitOnError)
+	names := fs.String("t", "", "the comma-separated names of the transforms to apply in order")
+	plugins := fs.String("plugin", "", "the comma-separated paths of the go plugins which register the transforms")
+	dry := fs.Bool("dry", false, "print the unified diffs instead of writing the files")
+	concurrent := fs.Int("j", 0, "the number of the files transformed concurrently, defaults to the number of CPUs")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mole codemod -t <transforms> [options] [dir]\n\nThe built-in transforms: %s\n\n",
+			strings.Join(transform.Registered(), ", "))
+		fs.PrintDefaults()
+	}
+	fs.Parse(flag.Args()[1:])
+
+	for _, p := range splitList(*plugins) {
+		if _, err := plugin.Open(p); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to load plugin %s: %v\n", p, err)
+			os.Exit(1)
+		}
+	}
+
+	ts := make([]transform.Transformer, 0)
+	for _, name := range splitList(*names) {
+		t, ok := transform.Lookup(name)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown transform: %s\n", name)
+			os.Exit(1)
+		}
+		ts = append(ts, t...)
+	}
+	if len(ts) == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	dir := opts.dir
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+	cmOpts := transform.NewCodemodOpts()
+	cmOpts.DryRun = *dry
+	if *concurrent > 0 {
+		cmOpts.Concurrent = *concurrent
+	}
+
+	var rets []*transform.CodemodResult
+	var err error
+	if info, e := os.Stat(dir); e == nil && !info.IsDir() {
+		rets = []*transform.CodemodResult{transform.CodemodFile(dir, cmOpts, ts...)}
+	} else {
+		rets, err = transform.Codemod(dir, cmOpts, ts...)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	changed, errs := 0, 0
+	for _, ret := range rets {
+		for _, w := range ret.Warnings {
+			fmt.Fprintf(os.Stderr, "%s: warning: %s\n", ret.File, w.Error())
+		}
+		if ret.Err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", ret.File, ret.Err)
+			continue
+		}
+		if ret.Changed {
+			changed += 1
+			if *dry {
+				fmt.Print(ret.Diff)
+			}
+		}
+	}
+
+	verb := "changed"
+	if *dry {
+		verb = "to be changed"
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d files %s, %d errors\n", changed, len(rets), verb, errs)
+	if errs > 0 {
+		os.Exit(1)
+	}
+	return true
+}
+
+func splitList(s string) []string {
+	ret := make([]string, 0)
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			ret = append(ret, item)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/hsiaosiyuan0/mole/ecma/compat"
+)
+
+// checks the files or the files in the directories against the browsers and runtimes to
+// support, for example:
+//
+//	mole compat -targets "chrome >= 80, safari >= 13.1" ./src
+//	mole compat ./src/index.js
+//
+// the targets are read from the `.browserslistrc` in the project directory if `-targets` is
+// not specified, the usages of the unsupported features are reported with the minimum versions
+// they require and the command exits with status 1 if there are any
+type Compat struct {
+}
+
+func (c *Compat) Process(opts *Options) bool {
+	if flag.Arg(0) != "compat" {
+		return false
+	}
+
+	fs := flag.NewFlagSet("compat", flag.ExitOnError)
+	targets := fs.String("targets", "", "the browserslist-like queries separated by commas, e.g. `chrome >= 80, node >= 14`")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mole compat [options] [path ...]\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(flag.Args()[1:])
+
+	queries := *targets
+	if queries == "" {
+		b, err := os.ReadFile(filepath.Join(opts.dir, ".browserslistrc"))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error: no targets specified, use `-targets` or the `.browserslistrc`")
+			os.Exit(1)
+		}
+		queries = string(b)
+	}
+
+	compatOpts := compat.NewOpts()
+	ts, err := compat.ParseTargets(queries)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	compatOpts.Targets = ts
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{opts.dir}
+	}
+	files, err := fmtFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	errs := 0
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+		issues, err := compat.Check(file, string(b), compatOpts)
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+		for _, issue := range issues {
+			errs += 1
+			fmt.Printf("%s: %s\n", file, issue)
+		}
+	}
+
+	if errs > 0 {
+		os.Exit(1)
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/doc"
+)
+
+// extracts the documentation of the exported symbols of the package, for example:
+//
+//	mole doc ./packages/foo
+//	mole doc -o ./docs ./packages/foo
+//	mole doc -entry src/index.ts,src/utils.ts
+//
+// the result is printed to stdout in JSON unless `-o` is specified, in that case the JSON is
+// written to `api.json` in the output directory alongside with the skeleton of the Markdown site
+type Doc struct {
+}
+
+func (c *Doc) Process(opts *Options) bool {
+	if flag.Arg(0) != "doc" {
+		return false
+	}
+
+	fs := flag.NewFlagSet("doc", flag.ExitOnError)
+	out := fs.String("o", "", "the output directory of `api.json` and the Markdown files")
+	entries := fs.String("entry", "", "the comma-separated entry modules relative to the package directory, they're read from the package.json by default")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mole doc [options] [dir]\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(flag.Args()[1:])
+
+	dir := opts.dir
+	if fs.NArg() > 0 {
+		dir = fs.Arg(0)
+	}
+
+	docOpts := doc.NewOpts()
+	if *entries != "" {
+		for _, e := range strings.Split(*entries, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				docOpts.Entries = append(docOpts.Entries, e)
+			}
+		}
+	}
+
+	pkg, err := doc.Extract(dir, docOpts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	for _, w := range pkg.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	if *out == "" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(pkg); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return true
+	}
+
+	if err := writeDoc(*out, pkg); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	return true
+}
+
+func writeDoc(dir string, pkg *doc.Package) error {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(pkg); err != nil {
+		return err
+	}
+
+	files := doc.Markdown(pkg)
+	files["api.json"] = b.String()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(files[name]), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
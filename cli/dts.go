@@ -0,0 +1,138 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/dts"
+	"github.com/hsiaosiyuan0/mole/ecma/tsconfig"
+)
+
+// emits the declaration files of the typescript files or the ones in the directories, for example:
+//
+//	mole dts ./src/index.ts
+//	mole dts -o ./types ./src
+//	mole dts -p ./tsconfig.json -o ./types
+//
+// the declarations of a single file is printed to stdout unless `-o` is specified, in that
+// case the declaration files are written to the output directory in the same layout as the
+// sources, the exports whose types cannot be inferred without the type checker are reported
+// and the command exits with status 1 if there are any
+type Dts struct {
+}
+
+func (c *Dts) Process(opts *Options) bool {
+	if flag.Arg(0) != "dts" {
+		return false
+	}
+
+	fs := flag.NewFlagSet("dts", flag.ExitOnError)
+	out := fs.String("o", "", "the output directory of the declaration files")
+	project := fs.String("p", "", "the tsconfig.json or the directory contains it, the files of the project are processed")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mole dts [options] [path ...]\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(flag.Args()[1:])
+
+	dtsOpts := dts.NewOpts()
+	paths := fs.Args()
+	var files []string
+	var err error
+	if *project != "" {
+		cfg, err := tsconfig.Load(*project)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		dtsOpts.ParserOpts = cfg.ParserOpts()
+		if files, err = cfg.FileNames(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		root := cfg.CompilerOptions.RootDir
+		if root == "" {
+			root = cfg.Dir()
+		}
+		paths = []string{root}
+	} else {
+		if len(paths) == 0 {
+			paths = []string{opts.dir}
+		}
+		if files, err = fmtFiles(paths); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	errs := 0
+	for _, file := range files {
+		if !isDtsSource(file) {
+			continue
+		}
+		b, err := os.ReadFile(file)
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+
+		ret, err := dts.Emit(file, string(b), dtsOpts)
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+		for _, e := range ret.Errors {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, e)
+		}
+
+		if *out == "" {
+			fmt.Print(ret.Code)
+			continue
+		}
+		target := filepath.Join(*out, dts.DtsFile(dtsRel(paths, file)))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+		if err := os.WriteFile(target, []byte(ret.Code), 0644); err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+		}
+	}
+
+	if errs > 0 {
+		os.Exit(1)
+	}
+	return true
+}
+
+func isDtsSource(file string) bool {
+	if strings.HasSuffix(file, ".d.ts") || strings.HasSuffix(file, ".d.mts") || strings.HasSuffix(file, ".d.cts") {
+		return false
+	}
+	switch filepath.Ext(file) {
+	case ".ts", ".tsx", ".mts", ".cts":
+		return true
+	}
+	return false
+}
+
+// the path of the file relative to the directory in `paths` which contains it, the name of the
+// file is used if it's specified directly
+func dtsRel(paths []string, file string) string {
+	for _, pth := range paths {
+		if info, err := os.Stat(pth); err == nil && info.IsDir() {
+			if rel, err := filepath.Rel(pth, file); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+		}
+	}
+	return filepath.Base(file)
+}
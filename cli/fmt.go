@@ -0,0 +1,190 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hsiaosiyuan0/mole/ecma/format"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// formats the files or the files in the directories, for example:
+//
+//	mole fmt -w ./src
+//	mole fmt -check ./src
+//	mole fmt -range 120:360 ./src/index.js
+//
+// the formatted code of a single file is printed to stdout unless `-w` is specified,
+// `-check` lists the files which are not formatted and exits with status 1 if there are any
+type Fmt struct {
+}
+
+var fmtExts = []string{".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx"}
+
+func (c *Fmt) Process(opts *Options) bool {
+	if flag.Arg(0) != "fmt" {
+		return false
+	}
+
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := fs.Bool("w", false, "write the formatted code to the files instead of printing it")
+	check := fs.Bool("check", false, "list the files which are not formatted and exit with status 1 if there are any")
+	rng := fs.String("range", "", "only format the statements intersect the byte range `lo:hi` of a single file")
+	width := fs.Int("width", 80, "the line width")
+	indent := fs.Int("indent", 2, "the number of spaces per indentation")
+	tabs := fs.Bool("tabs", false, "indent the lines by tabs")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: mole fmt [options] [path ...]\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(flag.Args()[1:])
+
+	fmtOpts := format.NewOpts()
+	fmtOpts.LineWidth = *width
+	fmtOpts.IndentWidth = *indent
+	fmtOpts.UseTabs = *tabs
+
+	paths := fs.Args()
+	if len(paths) == 0 {
+		paths = []string{opts.dir}
+	}
+	files, err := fmtFiles(paths)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
+	lo, hi := uint32(0), uint32(0)
+	if *rng != "" {
+		if lo, hi, err = parseRange(*rng); err != nil || len(files) != 1 {
+			fmt.Fprintln(os.Stderr, "-range requires a single file and the range in the form of `lo:hi`")
+			os.Exit(2)
+		}
+	}
+
+	unformatted, errs := 0, 0
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+		code := string(b)
+
+		var out string
+		if *rng != "" {
+			out, err = format.FormatRange(file, code, lo, hi, fmtOpts)
+		} else {
+			out, err = format.Format(file, code, fmtOpts)
+		}
+		if err != nil {
+			errs += 1
+			fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+			continue
+		}
+
+		switch {
+		case *check:
+			if out != code {
+				unformatted += 1
+				fmt.Println(file)
+			}
+		case *write:
+			if out != code {
+				info, _ := os.Stat(file)
+				if err := os.WriteFile(file, []byte(out), info.Mode().Perm()); err != nil {
+					errs += 1
+					fmt.Fprintf(os.Stderr, "%s: error: %v\n", file, err)
+				}
+			}
+		default:
+			fmt.Print(out)
+		}
+	}
+
+	if errs > 0 || *check && unformatted > 0 {
+		os.Exit(1)
+	}
+	return true
+}
+
+// the files to be formatted, the directories are walked to find the source files in them
+// except the ones in `node_modules` and the hidden directories
+func fmtFiles(paths []string) ([]string, error) {
+	ret := make([]string, 0)
+	for _, pth := range paths {
+		info, err := os.Stat(pth)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			ret = append(ret, pth)
+			continue
+		}
+
+		files := make([]string, 0)
+		var lock sync.Mutex
+		w := util.NewDirWalker(pth, 0, func(file string, isDir bool, dw *util.DirWalker) {
+			if isDir || !acceptFmtFile(pth, file) {
+				return
+			}
+			lock.Lock()
+			files = append(files, file)
+			lock.Unlock()
+		})
+		w.Walk()
+		if w.Err() != nil {
+			return nil, w.Err()
+		}
+		sort.Strings(files)
+		ret = append(ret, files...)
+	}
+	return ret, nil
+}
+
+func acceptFmtFile(dir, file string) bool {
+	ok := false
+	ext := filepath.Ext(file)
+	for _, e := range fmtExts {
+		if e == ext {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return false
+	}
+	rel, err := filepath.Rel(dir, file)
+	if err != nil {
+		return false
+	}
+	for _, seg := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+		if seg == "node_modules" || len(seg) > 1 && seg[0] == '.' && seg != ".." {
+			return false
+		}
+	}
+	return true
+}
+
+func parseRange(s string) (uint32, uint32, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid range: %s", s)
+	}
+	lo, err := strconv.ParseUint(parts[0], 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	hi, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uint32(lo), uint32(hi), nil
+}
@@ -47,7 +47,7 @@ type SubCommand interface {
 
 func main() {
 	opts := newOptions()
-	cmds := &[]SubCommand{&AstInspector{}}
+	cmds := &[]SubCommand{&AstInspector{}, &Codemod{}, &Fmt{}, &Doc{}, &Dts{}, &Compat{}}
 	for _, cmd := range *cmds {
 		if cmd.Process(opts) {
 			return
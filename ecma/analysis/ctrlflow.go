@@ -80,6 +80,97 @@ func (a *AnalysisCtx) newExit(astNode parser.Node, info string) *Block {
 	return b
 }
 
+// the resources declared by `using` and `await using` are disposed implicitly in the reverse
+// order of their declarations when the control leaves the enclosing scope, the disposals
+// are recorded as info nodes ahead of the exit of the scope
+func (a *AnalysisCtx) newScopeExit(astNode parser.Node, body []parser.Node) *Block {
+	b := a.graph.newBasicBlk()
+	addDisposes(b, body)
+	b.addNode(newInfoNode(astNode, false, ""))
+	return b
+}
+
+func addDisposes(b *Block, body []parser.Node) {
+	for i := len(body) - 1; i >= 0; i-- {
+		if body[i].Type() != parser.N_STMT_VAR_DEC {
+			continue
+		}
+		n := body[i].(*parser.VarDecStmt)
+		if !n.IsUsing() {
+			continue
+		}
+		info := "Dispose"
+		if n.Kind() == "await using" {
+			info = "AwaitDispose"
+		}
+		decs := n.DecList()
+		for j := len(decs) - 1; j >= 0; j-- {
+			id := decs[j].(*parser.VarDec).Id().(*parser.Ident)
+			b.addNode(newInfoNode(id, false, info+"("+id.Val()+")"))
+		}
+	}
+}
+
+// the abrupt completions like `break` and `throw` leave the enclosing scopes without passing
+// their exits, so the resources declared before the jump in the scopes being left are disposed
+// ahead of the exit of the jump statement, `target` is the node the control is transferred to
+// and it's nil for the unlabelled `break` and `return`
+func (a *AnalysisCtx) newAbruptExit(ctx *walk.VisitorCtx, target parser.Node) *Block {
+	b := a.graph.newBasicBlk()
+	if lb, ok := target.(*parser.LabelStmt); ok && isLoop(lb.Body().Type()) {
+		target = lb.Body()
+	}
+
+	jmp := ctx.Node
+	child := jmp
+	for vc := ctx.Parent; vc != nil; child, vc = vc.Node, vc.Parent {
+		node := vc.Node
+		typ := node.Type()
+		if isFn(typ) || typ == parser.N_STATIC_BLOCK {
+			break
+		}
+		if target == nil && jmp.Type() == parser.N_STMT_BRK && (isLoop(typ) || typ == parser.N_STMT_SWITCH) {
+			target = node
+		}
+
+		switch typ {
+		case parser.N_PROG:
+			addDisposes(b, stmtsBefore(node.(*parser.Prog).Body(), child))
+		case parser.N_STMT_BLOCK:
+			addDisposes(b, stmtsBefore(node.(*parser.BlockStmt).Body(), child))
+		case parser.N_STMT_FOR_IN_OF:
+			// the binding in the loop head is disposed when the iteration ends, which also
+			// happens when the loop itself is continued or broken
+			if n := node.(*parser.ForInOfStmt); child == n.Body() {
+				addDisposes(b, []parser.Node{n.Left()})
+			}
+		case parser.N_STMT_FOR:
+			// the bindings in the init live across the iterations, they are disposed by the
+			// loop exit if the loop itself is broken
+			if n := node.(*parser.ForStmt); child == n.Body() && node != target && n.Init() != nil {
+				addDisposes(b, []parser.Node{n.Init()})
+			}
+		}
+
+		if node == target {
+			break
+		}
+	}
+
+	b.addNode(newInfoNode(jmp, false, ""))
+	return b
+}
+
+// the statements in `body` ahead of `stmt`
+func stmtsBefore(body []parser.Node, stmt parser.Node) []parser.Node {
+	for i, n := range body {
+		if n == stmt {
+			return body[:i]
+		}
+	}
+	return body
+}
+
 func (a *AnalysisCtx) pushStmt(b *Block) {
 	a.stmtStack = append(a.stmtStack, b)
 }
@@ -175,6 +266,18 @@ func pushAtomNode(node parser.Node, key string, ctx *walk.VisitorCtx) *Block {
 	return b
 }
 
+// pops the module specifier and its following import attributes, the attributes are linked
+// after the specifier and they are grouped as a whole
+func linkAttrs(ac *AnalysisCtx, cnt int) *Block {
+	head, tail := ac.popExprsAndLink(cnt)
+	src := ac.popExpr()
+	if head == nil {
+		return src
+	}
+	link(ac, src, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, head, LF_NONE)
+	return grpBlock(ac, src, tail)
+}
+
 func handleBefore(node parser.Node, key string, ctx *walk.VisitorCtx) {
 	ac := analysisCtx(ctx)
 
@@ -353,6 +456,10 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		ac.pushExpr(grpBlock(ac, vn, exit))
 
 	case parser.N_IMPORT_CALL:
+		var opts *Block
+		if node.(*parser.ImportCall).Opts() != nil {
+			opts = ac.popExpr()
+		}
 		expr := ac.popExpr()
 		enter := ac.popExpr()
 		exit := ac.newExit(node, "")
@@ -360,6 +467,10 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		link(ac, enter, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, expr, LF_NONE)
 
 		prev := grpBlock(ac, enter, expr)
+		if opts != nil {
+			link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, opts, LF_NONE)
+			prev = grpBlock(ac, prev, opts)
+		}
 		link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, exit, LF_NONE)
 
 		ac.pushExpr(grpBlock(ac, enter, exit))
@@ -656,6 +767,18 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 
 		ac.pushExpr(grpBlock(ac, enter, exit))
 
+	case parser.N_IMPORT_ATTR:
+		val := ac.popExpr()
+		key := ac.popExpr()
+		enter := ac.popExpr()
+		exit := ac.newExit(node, "")
+
+		link(ac, enter, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, key, LF_NONE)
+		link(ac, key, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, val, LF_NONE)
+		link(ac, val, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, exit, LF_NONE)
+
+		ac.pushExpr(grpBlock(ac, enter, exit))
+
 	case parser.N_JSX_EXPR_SPAN, parser.N_JSX_CHILD_SPREAD, parser.N_JSX_ATTR_SPREAD:
 		expr := ac.popExpr()
 		enter := ac.popExpr()
@@ -831,7 +954,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 
 	case parser.N_PROG:
 		prev := ac.popStmt()
-		exit := ac.newExit(node, "")
+		exit := ac.newScopeExit(node, node.(*parser.Prog).Body())
 
 		link(ac, prev, EK_JMP, ET_NONE, EK_JMP, ET_NONE, exit, LF_NONE)
 		link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, exit, LF_NONE)
@@ -839,7 +962,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 
 	case parser.N_STMT_BLOCK:
 		prev := ac.popStmt()
-		exit := ac.newExit(node, "")
+		exit := ac.newScopeExit(node, node.(*parser.BlockStmt).Body())
 		link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, exit, LF_NONE)
 		ac.pushStmt(grpBlock(ac, prev, exit))
 
@@ -957,7 +1080,13 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		}
 		vn.addOutlets(body.xOutEdges())
 
-		exit := ac.newExit(node, "")
+		// the bindings declared by `using` in the init are disposed when the loop ends
+		var exit *Block
+		if n.Init() != nil {
+			exit = ac.newScopeExit(node, []parser.Node{n.Init()})
+		} else {
+			exit = ac.newExit(node, "")
+		}
 		link(ac, vn, EK_JMP, ET_NONE, EK_JMP, ET_NONE, exit, LF_NONE)
 		link(ac, vn, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, exit, LF_NONE)
 
@@ -1063,6 +1192,14 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		rhs.newJmpOut(ET_JMP_F)
 		link(ac, rhs, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, body, LF_NONE)
 
+		// the binding declared by `using` in the loop head is disposed at the end of each iteration
+		if left := node.(*parser.ForInOfStmt).Left(); left.Type() == parser.N_STMT_VAR_DEC && left.(*parser.VarDecStmt).IsUsing() {
+			dispose := ac.graph.newBasicBlk()
+			addDisposes(dispose, []parser.Node{left})
+			link(ac, body, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, dispose, LF_NONE)
+			body = grpBlock(ac, body, dispose)
+		}
+
 		body.mrkSeqOutAsLoop()
 		link(ac, body, EK_JMP, ET_LOOP, EK_JMP, ET_LOOP, lhs, LF_NONE)
 
@@ -1240,7 +1377,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		n := node.(*parser.ThrowStmt)
 		prev := ac.popStmt()
 		expr := ac.popExpr()
-		exit := ac.newExit(node, "")
+		exit := ac.newAbruptExit(ctx, n.Target())
 
 		link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, expr, LF_NONE)
 
@@ -1389,11 +1526,11 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		ac.pushStmt(grpBlock(ac, enter, exit))
 
 	case parser.N_STMT_CONT:
+		n := node.(*parser.ContStmt)
 		prev := ac.popStmt()
-		exit := ac.newExit(node, "")
+		exit := ac.newAbruptExit(ctx, n.Target())
 		exit.newJmpOut(ET_LOOP)
 
-		n := node.(*parser.ContStmt)
 		var target *Block
 		if n.Label() != nil {
 			name := ac.popExpr()
@@ -1416,10 +1553,10 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		ac.pushStmt(vn)
 
 	case parser.N_STMT_BRK:
+		n := node.(*parser.BrkStmt)
 		prev := ac.popStmt()
-		exit := ac.newExit(node, "")
+		exit := ac.newAbruptExit(ctx, n.Target())
 
-		n := node.(*parser.BrkStmt)
 		if n.Label() != nil {
 			name := ac.popExpr()
 			link(ac, prev, EK_SEQ, ET_NONE, EK_SEQ, ET_NONE, name, LF_NONE)
@@ -1508,7 +1645,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		prev := ac.popStmt()
 		origPrev := prev
 
-		exit := ac.newExit(node, "")
+		exit := ac.newAbruptExit(ctx, nil)
 		exit.newJmpOut(ET_JMP_U)
 
 		n := node.(*parser.RetStmt)
@@ -1558,7 +1695,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 		enter := ac.popStmt()
 		exit := ac.newExit(node, "")
 
-		src := ac.popExpr()
+		src := linkAttrs(ac, len(n.Attrs()))
 		head, tail := ac.popExprsAndLink(len(n.Specs()))
 
 		if head != nil {
@@ -1606,7 +1743,7 @@ func handleAfter(node parser.Node, key string, ctx *walk.VisitorCtx) {
 
 		var src *Block
 		if n.Src() != nil {
-			src = ac.popExpr()
+			src = linkAttrs(ac, len(n.Attrs()))
 		}
 
 		var head, tail *Block
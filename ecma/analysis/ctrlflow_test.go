@@ -5028,6 +5028,51 @@ initial->b0 [xlabel="",color="black"];
 `, ana.Graph().Dot(), "should be ok")
 }
 
+func TestCtrlflow_ImportAttrs(t *testing.T) {
+	p, ast, symtab, err := compile(`
+  import a from "a" with { type: "json" }
+  export * from "b" with { type: "json" }
+  `, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nImportDec:enter\nImportSpec(Default):enter\nIdent(a)\nImportSpec(Default):exit\nStrLit\nImportAttr:enter\nIdent(type)\nStrLit\nImportAttr:exit\nImportDec:exit\nExportDec(All):enter\nStrLit\nImportAttr:enter\nIdent(type)\nStrLit\nImportAttr:exit\nExportDec(All):exit\nProg:exit\n"];
+b0->final [xlabel="",color="black"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_ImportCallOpts(t *testing.T) {
+	p, ast, symtab, err := compile(`
+  import("a", b); c
+  `, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nExprStmt:enter\nImportCall:enter\nStrLit\nIdent(b)\nImportCall:exit\nExprStmt:exit\nExprStmt:enter\nIdent(c)\nExprStmt:exit\nProg:exit\n"];
+b0->final [xlabel="",color="black"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
 func TestCtrlflow_ExportIndividual(t *testing.T) {
 	p, ast, symtab, err := compile(`
   export let a, b, c;
@@ -6817,3 +6862,205 @@ initial->b0 [xlabel="",color="black"];
 
 // 	// `, ana.Graph().Dot(), "should be ok")
 // }
+
+func TestCtrlflow_UsingDispose(t *testing.T) {
+	p, ast, symtab, err := compile(`{ using a = b(), c = d(); e; await using f = g() }`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nBlockStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(a)\nCallExpr:enter\nIdent(b)\nCallExpr:exit\nVarDec:exit\nVarDec:enter\nIdent(c)\nCallExpr:enter\nIdent(d)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nExprStmt:enter\nIdent(e)\nExprStmt:exit\nVarDecStmt:enter\nVarDec:enter\nIdent(f)\nCallExpr:enter\nIdent(g)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nAwaitDispose(f)\nDispose(c)\nDispose(a)\nBlockStmt:exit\nProg:exit\n"];
+b0->final [xlabel="",color="black"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeForOf(t *testing.T) {
+	p, ast, symtab, err := compile(`for (using x of y) z`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nForInOfStmt:enter\n"];
+b12[label="ExprStmt:enter\nIdent(z)\nExprStmt:exit\nDispose(x)\n"];
+b18[label="ForInOfStmt:exit\nProg:exit\n"];
+b4[label="VarDecStmt:enter\nVarDec:enter\nIdent(x)\nVarDec:exit\nVarDecStmt:exit\nIdent(y)\n"];
+b0->b4 [xlabel="",color="black"];
+b12:s->b4:ne [xlabel="L",color="orange"];
+b18->final [xlabel="",color="black"];
+b4->b12 [xlabel="",color="black"];
+b4->b18 [xlabel="F",color="orange"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeFor(t *testing.T) {
+	p, ast, symtab, err := compile(`for (using x = a(); b; c) d`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nForStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(x)\nCallExpr:enter\nIdent(a)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\n"];
+b15[label="Ident(b)\n"];
+b17[label="ExprStmt:enter\nIdent(d)\nExprStmt:exit\nIdent(c)\n"];
+b23[label="Dispose(x)\nForStmt:exit\nProg:exit\n"];
+b0->b15 [xlabel="",color="black"];
+b15->b17 [xlabel="",color="black"];
+b15->b23 [xlabel="F",color="orange"];
+b17:s->b15:ne [xlabel="L",color="orange"];
+b23->final [xlabel="",color="black"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeThrow(t *testing.T) {
+	p, ast, symtab, err := compile(`{ using x = a(); if (c) throw e; }`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nBlockStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(x)\nCallExpr:enter\nIdent(a)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nIfStmt:enter\nIdent(c)\n"];
+b16[label="ThrowStmt:enter\nIdent(e)\n"];
+b18[label="Dispose(x)\nThrowStmt:exit\n"];
+b21[label="IfStmt:exit\nDispose(x)\nBlockStmt:exit\n"];
+b23[label="Prog:exit\n"];
+b0->b16 [xlabel="",color="black"];
+b0->b21 [xlabel="F",color="orange"];
+b16->b18 [xlabel="",color="black"];
+b16->b23 [xlabel="E",color="orange"];
+b18->b21 [xlabel="",color="red"];
+b18->b23 [xlabel="U",color="orange"];
+b21->b23 [xlabel="",color="black"];
+b23->final [xlabel="",color="black"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeBreak(t *testing.T) {
+	p, ast, symtab, err := compile(`for(;;){ using x = a(); if (c) break; }`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nForStmt:enter\n"];
+b18[label="BrkStmt:enter\nDispose(x)\nBrkStmt:exit\n"];
+b22[label="IfStmt:exit\nDispose(x)\nBlockStmt:exit\n"];
+b25[label="ForStmt:exit\nProg:exit\n"];
+b4[label="BlockStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(x)\nCallExpr:enter\nIdent(a)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nIfStmt:enter\nIdent(c)\n"];
+b0->b4 [xlabel="",color="black"];
+b18->b22 [xlabel="",color="red"];
+b18->b25 [xlabel="U",color="orange"];
+b22->b25 [xlabel="",color="red"];
+b22:s->b4:ne [xlabel="L",color="orange"];
+b25->final [xlabel="",color="black"];
+b4->b18 [xlabel="",color="black"];
+b4->b22 [xlabel="F",color="orange"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeContinue(t *testing.T) {
+	p, ast, symtab, err := compile(`for (using x of y) { using z = a(); if (c) continue; }`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b0[label="Prog:enter\nForInOfStmt:enter\n"];
+b12[label="BlockStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(z)\nCallExpr:enter\nIdent(a)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nIfStmt:enter\nIdent(c)\n"];
+b26[label="ContStmt:enter\nDispose(z)\nDispose(x)\nContStmt:exit\n"];
+b31[label="IfStmt:exit\nDispose(z)\nBlockStmt:exit\nDispose(x)\n"];
+b34[label="ForInOfStmt:exit\nProg:exit\n"];
+b4[label="VarDecStmt:enter\nVarDec:enter\nIdent(x)\nVarDec:exit\nVarDecStmt:exit\nIdent(y)\n"];
+b0->b4 [xlabel="",color="black"];
+b12->b26 [xlabel="",color="black"];
+b12->b31 [xlabel="F",color="orange"];
+b26->b31 [xlabel="",color="red"];
+b26:s->b4:ne [xlabel="L",color="orange"];
+b31:s->b4:ne [xlabel="L",color="orange"];
+b34->final [xlabel="",color="black"];
+b4->b12 [xlabel="",color="black"];
+b4->b34 [xlabel="F",color="orange"];
+initial->b0 [xlabel="",color="black"];
+}
+`, ana.Graph().Dot(), "should be ok")
+}
+
+func TestCtrlflow_UsingDisposeReturn(t *testing.T) {
+	p, ast, symtab, err := compile(`function f() { using x = a(); if (c) return; using y = b() }`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ana := NewAnalysis(ast, symtab, p.Source())
+	ana.Analyze()
+
+	fn := ast.(*parser.Prog).Body()[0]
+	fnGraph := ana.AnalysisCtx().GraphOf(fn)
+
+	AssertEqualString(t, `
+digraph G {
+node[shape=box,style="rounded,filled",fillcolor=white,fontname="Consolas",fontsize=10];
+edge[fontname="Consolas",fontsize=10]
+initial[label="",shape=circle,style=filled,fillcolor=black,width=0.25,height=0.25];
+final[label="",shape=doublecircle,style=filled,fillcolor=black,width=0.25,height=0.25];
+b17[label="RetStmt:enter\nDispose(x)\nRetStmt:exit\n"];
+b21[label="IfStmt:exit\nVarDecStmt:enter\n"];
+b23[label="VarDec:enter\nIdent(y)\nCallExpr:enter\nIdent(b)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nDispose(y)\nDispose(x)\nBlockStmt:exit\n"];
+b33[label="FnDec:enter\nIdent(f)\nBlockStmt:enter\nVarDecStmt:enter\nVarDec:enter\nIdent(x)\nCallExpr:enter\nIdent(a)\nCallExpr:exit\nVarDec:exit\nVarDecStmt:exit\nIfStmt:enter\nIdent(c)\n"];
+b34[label="FnDec:exit\n"];
+b17->b21 [xlabel="",color="red"];
+b17->b34 [xlabel="U",color="orange"];
+b21->b23 [xlabel="",color="black"];
+b23->b34 [xlabel="",color="black"];
+b33->b17 [xlabel="",color="black"];
+b33->b21 [xlabel="F",color="orange"];
+b34->final [xlabel="",color="black"];
+initial->b33 [xlabel="",color="black"];
+}
+`, fnGraph.Dot(), "should be ok")
+}
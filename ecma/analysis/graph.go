@@ -486,8 +486,14 @@ func (b *Block) mrkSeqOutAsLoop() {
 	b.mrkSeqOutAsJmp(ET_LOOP)
 }
 
+// the cut edge grows from the tail of the loop body which is the source of the loop edge,
+// the other outlets like the jumps of `break` are skipped
 func (b *Block) addCutOutEdge() {
-	blk := util.PickOne(b.Outlets).Src
+	out := b.OutJmpEdge(ET_LOOP)
+	if out == nil {
+		out = util.PickOne(b.Outlets)
+	}
+	blk := out.Src
 	edge := &Edge{EK_SEQ, ET_NONE, blk, nil}
 	edge.Tag |= ET_CUT
 	blk.Outlets[edge] = edge
@@ -101,7 +101,7 @@ func NamesInDecNode(node parser.Node) (ret []string, all bool) {
 			} else if spec.NameSpace() {
 				all = true
 			} else {
-				ret = append(ret, spec.Id().(*parser.Ident).Val())
+				ret = append(ret, moduleExportName(spec.Id()))
 			}
 		}
 	case parser.N_STMT_EXPORT:
@@ -118,7 +118,7 @@ func NamesInDecNode(node parser.Node) (ret []string, all bool) {
 				if sp.NameSpace() {
 					all = true
 				} else {
-					ret = append(ret, sp.Id().(*parser.Ident).Val())
+					ret = append(ret, moduleExportName(sp.Id()))
 				}
 			}
 		}
@@ -327,6 +327,24 @@ func IsIdentBoundInImportDec(ident *parser.Ident, scope *parser.Scope) *parser.I
 	return nil
 }
 
+// whether the identifier is a reference rather than the name of a property like the `b` in
+// `a.b` and `{ b: 1 }`, `parent` is the parent node of the identifier
+func IsRefIdent(id *parser.Ident, parent parser.Node) bool {
+	switch p := parent.(type) {
+	case *parser.MemberExpr:
+		return p.Compute() || p.Prop() != id
+	case *parser.Prop:
+		return p.Computed() || p.Key() != id || p.Shorthand()
+	case *parser.Method:
+		return p.Computed() || p.Key() != id
+	case *parser.Field:
+		return p.Computed() || p.Key() != id
+	case *parser.MetaProp:
+		return false
+	}
+	return true
+}
+
 func IsFn(node parser.Node) bool {
 	typ := node.Type()
 	return typ == parser.N_STMT_FN || typ == parser.N_EXPR_FN || typ == parser.N_EXPR_ARROW
@@ -502,8 +520,20 @@ func IdOfLocalInImportDec(local string, n *parser.ImportDec) string {
 	for _, s := range n.Specs() {
 		spec := s.(*parser.ImportSpec)
 		if spec.Local().(*parser.Ident).Val() == local {
-			return spec.Id().(*parser.Ident).Val()
+			return moduleExportName(spec.Id())
 		}
 	}
 	return ""
 }
+
+// the name of the identifier or the string literal in the specifiers, the latter is used for
+// the names which are not valid identifiers like `export { a as "a-b" }`
+func moduleExportName(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.StrLit:
+		return n.Val()
+	}
+	return ""
+}
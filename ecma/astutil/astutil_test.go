@@ -1,9 +1,11 @@
 package astutil
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
 	"github.com/hsiaosiyuan0/mole/span"
 	"github.com/hsiaosiyuan0/mole/util"
 )
@@ -22,6 +24,24 @@ func compile(code string, opts *parser.ParserOpts) (*parser.Parser, parser.Node,
 	return p, prg, err
 }
 
+func TestIsRefIdent(t *testing.T) {
+	p, ast, err := compile("a.b; a[c]; ({ d: e, f, [g]: 1, h() {} }); class A { i = j; [k] = 1 }; function F() { new.target }", nil)
+	util.AssertEqual(t, nil, err, "should be prog ok")
+
+	refs := make([]string, 0)
+	ctx := walk.NewWalkCtx(ast, p.Symtab())
+	walk.AddNodeBeforeListener(&ctx.Listeners, parser.N_NAME, &walk.Listener{
+		Id: "refs",
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			if id := node.(*parser.Ident); IsRefIdent(id, vc.ParentNode()) {
+				refs = append(refs, id.Val())
+			}
+		},
+	})
+	walk.VisitNode(ast, "", ctx.VisitorCtx())
+	util.AssertEqual(t, "a a c e f f g A j k F", strings.Join(refs, " "), "should be ok")
+}
+
 func TestIfStmtToSwitchBranches(t *testing.T) {
 	_, ast, err := compile(`
   if (a) {
@@ -0,0 +1,587 @@
+// Package compat checks the sources against the browsers and runtimes to support, the syntax
+// features and the well-known global APIs used by the sources are looked up in the bundled
+// compat table and the ones not supported by the targets are reported with the minimum
+// versions they require
+package compat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/astutil"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// the version of the browser or runtime like `13.1`, the omitted parts are regarded as `0`
+type Version []int
+
+func ParseVersion(s string) (Version, error) {
+	parts := strings.Split(s, ".")
+	v := make(Version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version `%s`", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+func (v Version) Compare(o Version) int {
+	for i := 0; i < len(v) || i < len(o); i++ {
+		a, b := 0, 0
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		if a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func (v Version) String() string {
+	parts := make([]string, len(v))
+	for i, n := range v {
+		parts[i] = strconv.Itoa(n)
+	}
+	return strings.Join(parts, ".")
+}
+
+var browsers = []string{"chrome", "edge", "firefox", "safari", "opera", "node"}
+
+// the names used by browserslist for the mobile browsers which share the versions of the
+// desktop ones in the compat table
+var browserAlias = map[string]string{
+	"ios_saf": "safari",
+	"ios":     "safari",
+	"and_chr": "chrome",
+	"and_ff":  "firefox",
+	"nodejs":  "node",
+}
+
+type Target struct {
+	Browser string
+	Version Version
+}
+
+func (t *Target) String() string {
+	return t.Browser + " " + t.Version.String()
+}
+
+// parses the browserslist-like queries, the queries are separated by commas or newlines and
+// each one is in the form of `chrome >= 80` or `safari 13.1`, the lowest version is kept if
+// a browser is queried multiple times, the comments start with `#` are ignored
+//
+// the queries like `defaults` or `last 2 versions` are rejected as unsupported since they are
+// resolved against the release data of the browsers which is not bundled
+func ParseTargets(queries string) ([]*Target, error) {
+	min := map[string]Version{}
+	for _, line := range strings.Split(queries, "\n") {
+		if i := strings.Index(line, "#"); i != -1 {
+			line = line[:i]
+		}
+		for _, q := range strings.Split(line, ",") {
+			q = strings.TrimSpace(q)
+			if q == "" {
+				continue
+			}
+			fields := strings.Fields(strings.Replace(q, ">=", " ", 1))
+			if len(fields) != 2 {
+				return nil, fmt.Errorf("unsupported query `%s`, only `<browser> <version>` and `<browser> >= <version>` are supported", q)
+			}
+			name := strings.ToLower(fields[0])
+			if alias, ok := browserAlias[name]; ok {
+				name = alias
+			}
+			if !util.Includes(browsers, name) {
+				return nil, fmt.Errorf("unknown browser `%s` in query `%s`", fields[0], q)
+			}
+			v, err := ParseVersion(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s in query `%s`", err.Error(), q)
+			}
+			if cur, ok := min[name]; !ok || v.Compare(cur) < 0 {
+				min[name] = v
+			}
+		}
+	}
+	if len(min) == 0 {
+		return nil, errors.New("no targets specified")
+	}
+
+	ret := make([]*Target, 0, len(min))
+	for _, name := range browsers {
+		if v, ok := min[name]; ok {
+			ret = append(ret, &Target{name, v})
+		}
+	}
+	return ret, nil
+}
+
+type Kind uint8
+
+const (
+	KIND_SYNTAX Kind = iota
+	KIND_API
+)
+
+type Feature struct {
+	Name string
+	Kind Kind
+
+	// the flag of the syntax feature in the parser, it's `FEAT_NONE` for the apis and the
+	// syntax features which are not gated by the parser like the arrow functions
+	Feat parser.Feature
+
+	// the first versions of the browsers support the feature
+	Support map[string]Version
+
+	support string
+}
+
+var featureByName = map[string]*Feature{}
+var featureByFlag = map[parser.Feature]*Feature{}
+
+// the method name to the prototype members have that name
+var protoMethods = map[string][]*Feature{}
+
+func init() {
+	for _, fs := range [][]*Feature{syntaxFeatures, apiFeatures} {
+		for _, f := range fs {
+			targets, err := ParseTargets(f.support)
+			if err != nil {
+				panic(err)
+			}
+			f.Support = map[string]Version{}
+			for _, t := range targets {
+				f.Support[t.Browser] = t.Version
+			}
+			featureByName[f.Name] = f
+		}
+	}
+	for _, f := range syntaxFeatures {
+		if f.Feat != parser.FEAT_NONE {
+			featureByFlag[f.Feat] = f
+		}
+	}
+	for _, f := range apiFeatures {
+		f.Kind = KIND_API
+		if i := strings.Index(f.Name, ".prototype."); i != -1 {
+			name := f.Name[i+len(".prototype."):]
+			protoMethods[name] = append(protoMethods[name], f)
+		}
+	}
+}
+
+// the features in the compat table, the syntax features come first
+func Features() []*Feature {
+	ret := make([]*Feature, 0, len(syntaxFeatures)+len(apiFeatures))
+	ret = append(ret, syntaxFeatures...)
+	return append(ret, apiFeatures...)
+}
+
+func FeatureOf(name string) *Feature {
+	return featureByName[name]
+}
+
+// the targets which don't support the feature with the versions they require, the browsers
+// absent from the compat table of the feature are regarded as unknown and not reported
+func (f *Feature) Unsupported(targets []*Target) []*Target {
+	ret := make([]*Target, 0)
+	for _, t := range targets {
+		if v, ok := f.Support[t.Browser]; ok && t.Version.Compare(v) < 0 {
+			ret = append(ret, &Target{t.Browser, v})
+		}
+	}
+	return ret
+}
+
+// the usage of the feature not supported by the targets, `Required` are the minimum versions
+// of the targets to support the feature
+type Issue struct {
+	src      *span.Source
+	Rng      span.Range
+	Feature  *Feature
+	Required []*Target
+}
+
+func (i *Issue) String() string {
+	req := make([]string, len(i.Required))
+	for j, t := range i.Required {
+		req[j] = t.String()
+	}
+	name := i.Feature.Name
+	if i.Feature.Kind == KIND_API {
+		name = "`" + name + "`"
+	}
+	pos := i.src.OfstLineCol(i.Rng.Lo)
+	return fmt.Sprintf("%s requires %s at (%d:%d)", name, strings.Join(req, ", "), pos.Line, pos.Col)
+}
+
+type Opts struct {
+	ParserOpts *parser.ParserOpts
+	Targets    []*Target
+}
+
+func NewOpts() *Opts {
+	return &Opts{
+		ParserOpts: parser.NewParserOpts(),
+		Targets:    make([]*Target, 0),
+	}
+}
+
+func (o *Opts) parserOptsOf(file string) *parser.ParserOpts {
+	opts := o.ParserOpts.OfFile(file)
+	// all the syntax should be accepted to be reported
+	opts.Version = 0
+	return opts
+}
+
+// checks the source against the targets, the returned error is the syntax error of the source
+// and the issues are sorted by their positions
+func Check(file, code string, opts *Opts) ([]*Issue, error) {
+	if opts == nil {
+		opts = NewOpts()
+	}
+	p := parser.NewParser(span.NewSource(file, code), opts.parserOptsOf(file))
+	ast, err := p.Prog()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &checker{p: p, targets: opts.Targets, issues: make([]*Issue, 0)}
+	if len(c.targets) > 0 {
+		c.run(ast)
+	}
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		return c.issues[i].Rng.Lo < c.issues[j].Rng.Lo
+	})
+	return c.issues, nil
+}
+
+type checker struct {
+	p       *parser.Parser
+	targets []*Target
+	issues  []*Issue
+
+	module  bool       // whether the module syntax has been reported
+	pattern span.Range // the range of the last reported destructuring pattern
+	ambient span.Range // the range of the last type-only node, the nodes in it are skipped
+}
+
+func (c *checker) report(rng span.Range, f *Feature) {
+	if f == nil {
+		return
+	}
+	if req := f.Unsupported(c.targets); len(req) > 0 {
+		c.issues = append(c.issues, &Issue{c.p.Source(), rng, f, req})
+	}
+}
+
+func (c *checker) reportFlag(rng span.Range, flag parser.Feature) {
+	c.report(rng, featureByFlag[flag])
+}
+
+func (c *checker) reportName(rng span.Range, name string) {
+	c.report(rng, featureByName[name])
+}
+
+func (c *checker) run(ast parser.Node) {
+	ctx := walk.NewWalkCtx(ast, c.p.Symtab())
+	walk.AddBeforeListener(&ctx.Listeners, &walk.Listener{
+		Id:     "compat",
+		Handle: c.onNode,
+	})
+	walk.VisitNode(ast, "", ctx.VisitorCtx())
+
+	// the hashbang is kept as a comment rather than a node
+	if cmts := c.p.Comments(); len(cmts) > 0 && strings.HasPrefix(c.p.RngText(cmts[0]), "#!") {
+		c.reportFlag(cmts[0], parser.FEAT_HASHBANG)
+	}
+}
+
+func isTsNode(node parser.Node) bool {
+	return node != nil && node.Type() > parser.N_TS_BEGIN && node.Type() < parser.N_TS_END
+}
+
+// whether the node is erased by the typescript compiler, the typescript nodes contain the
+// runtime code such as the enums and the namespaces are not the ambient ones
+func isAmbient(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.ClassDec:
+		return n.Declare()
+	case *parser.FnDec:
+		return n.Body() == nil
+	case *parser.TsTypAssert, *parser.TsEnum, *parser.TsEnumMember, *parser.TsNS, *parser.TsImportAlias,
+		*parser.TsImportRequire, *parser.TsExportAssign, *parser.TsNoNull:
+		return false
+	}
+	return isTsNode(node)
+}
+
+// whether the name of the import or export specifier is a string literal like `"a-b"`
+func isStrName(name parser.Node) bool {
+	return name != nil && name.Type() == parser.N_LIT_STR
+}
+
+func isPrivate(key parser.Node) bool {
+	id, ok := key.(*parser.Ident)
+	return ok && id.IsPrivate()
+}
+
+// the name of the global referenced by the node if it's not shadowed by the local bindings
+func (c *checker) globalName(node parser.Node, vc *walk.VisitorCtx) string {
+	id, ok := node.(*parser.Ident)
+	if !ok || id.IsPrivate() {
+		return ""
+	}
+	if vc.Scope().BindingOf(id.Val()) != nil {
+		return ""
+	}
+	return id.Val()
+}
+
+func (c *checker) onNode(node parser.Node, key string, vc *walk.VisitorCtx) {
+	parent := vc.ParentNode()
+	rng := node.Range()
+	if !c.ambient.Empty() && rng.Lo >= c.ambient.Lo && rng.Hi <= c.ambient.Hi {
+		return
+	}
+	if isAmbient(node) {
+		c.ambient = rng
+		return
+	}
+
+	switch n := node.(type) {
+	case *parser.VarDecStmt:
+		if n.Kind() == "let" || n.Kind() == "const" {
+			c.reportFlag(rng, parser.FEAT_LET_CONST)
+		} else if n.IsUsing() {
+			c.reportFlag(rng, parser.FEAT_USING)
+		}
+	case *parser.ArrowFn:
+		c.reportName(rng, "Arrow function")
+		if n.Async() {
+			c.reportFlag(rng, parser.FEAT_ASYNC_AWAIT)
+		}
+	case *parser.FnDec:
+		if n.Async() && n.Generator() {
+			c.reportFlag(rng, parser.FEAT_ASYNC_GENERATOR)
+		} else if n.Async() {
+			c.reportFlag(rng, parser.FEAT_ASYNC_AWAIT)
+		} else if n.Generator() {
+			c.reportName(rng, "Generator")
+		}
+	case *parser.ClassDec:
+		c.reportName(rng, "Class")
+	case *parser.TplExpr:
+		c.reportName(rng, "Template literal")
+	case *parser.ForInOfStmt:
+		if !n.In() {
+			c.reportName(rng, "for...of")
+		}
+		if n.Await() {
+			c.reportFlag(rng, parser.FEAT_ASYNC_ITERATION)
+		}
+	case *parser.ObjPat, *parser.ArrPat:
+		if c.pattern.Empty() || rng.Lo >= c.pattern.Hi {
+			c.pattern = rng
+			c.reportFlag(rng, parser.FEAT_BINDING_PATTERN)
+		}
+	case *parser.RestPat:
+		if _, ok := parent.(*parser.ObjPat); ok {
+			c.reportName(rng, "Object rest and spread properties")
+		} else {
+			c.reportFlag(rng, parser.FEAT_SPREAD)
+		}
+	case *parser.Spread:
+		if _, ok := parent.(*parser.ObjLit); ok {
+			c.reportName(rng, "Object rest and spread properties")
+		} else {
+			c.reportFlag(rng, parser.FEAT_SPREAD)
+		}
+	case *parser.ImportDec:
+		if !n.TsTyp() {
+			c.reportModule(rng, parent)
+		}
+		if !n.AttrKw().Empty() {
+			c.reportFlag(n.AttrKw(), parser.FEAT_IMPORT_ATTRS)
+		}
+	case *parser.ExportDec:
+		if n.Kind() != "type" {
+			c.reportModule(rng, parent)
+		}
+		if n.All() && len(n.Specs()) > 0 {
+			c.reportFlag(rng, parser.FEAT_EXPORT_ALL_AS_NS)
+		}
+		if !n.AttrKw().Empty() {
+			c.reportFlag(n.AttrKw(), parser.FEAT_IMPORT_ATTRS)
+		}
+	case *parser.ImportSpec:
+		if isStrName(n.Id()) {
+			c.reportFlag(n.Id().Range(), parser.FEAT_MODULE_STR_NAME)
+		}
+	case *parser.ExportSpec:
+		if isStrName(n.Local()) {
+			c.reportFlag(n.Local().Range(), parser.FEAT_MODULE_STR_NAME)
+		} else if isStrName(n.Id()) {
+			c.reportFlag(n.Id().Range(), parser.FEAT_MODULE_STR_NAME)
+		}
+	case *parser.MetaProp:
+		if n.Meta().(*parser.Ident).Val() == "new" {
+			c.reportFlag(rng, parser.FEAT_META_PROPERTY)
+		} else {
+			c.reportName(rng, "import.meta")
+		}
+	case *parser.ImportCall:
+		c.reportFlag(rng, parser.FEAT_DYNAMIC_IMPORT)
+		if n.Opts() != nil {
+			c.reportFlag(n.Opts().Range(), parser.FEAT_IMPORT_ATTRS)
+		}
+	case *parser.BinExpr:
+		switch n.Op() {
+		case parser.T_POW:
+			c.reportFlag(rng, parser.FEAT_POW)
+		case parser.T_NULLISH:
+			c.reportFlag(rng, parser.FEAT_NULLISH)
+		case parser.T_IN:
+			if isPrivate(n.Lhs()) {
+				c.reportFlag(rng, parser.FEAT_CLASS_PRIV_IN)
+			}
+		}
+	case *parser.AssignExpr:
+		switch n.Op() {
+		case parser.T_ASSIGN_POW:
+			c.reportFlag(rng, parser.FEAT_POW)
+		case parser.T_ASSIGN_AND, parser.T_ASSIGN_OR, parser.T_ASSIGN_NULLISH:
+			c.reportFlag(rng, parser.FEAT_LOGIC_ASSIGN)
+		}
+	case *parser.UnaryExpr:
+		if n.Op() == parser.T_AWAIT && vc.Scope().UpperFn().IsKind(parser.SPK_GLOBAL) {
+			c.reportFlag(rng, parser.FEAT_GLOBAL_ASYNC)
+		}
+	case *parser.ChainExpr:
+		c.reportFlag(rng, parser.FEAT_OPT_EXPR)
+	case *parser.NumLit:
+		if parser.NodeIsBigint(n, c.p.Source()) {
+			c.reportFlag(rng, parser.FEAT_BIGINT)
+		}
+		if strings.Contains(c.p.RngText(rng), "_") {
+			c.reportFlag(rng, parser.FEAT_NUM_SEP)
+		}
+	case *parser.RegLit:
+		for _, f := range n.Flags() {
+			switch f {
+			case 'u':
+				c.reportFlag(rng, parser.FEAT_REGEXP_UNICODE)
+			case 'y':
+				c.reportFlag(rng, parser.FEAT_REGEXP_STICKY)
+			case 's':
+				c.reportFlag(rng, parser.FEAT_REGEXP_DOT_ALL)
+			case 'd':
+				c.reportFlag(rng, parser.FEAT_REGEXP_HAS_INDICES)
+			case 'v':
+				c.reportFlag(rng, parser.FEAT_REGEXP_UNICODE_SETS)
+			}
+		}
+	case *parser.Catch:
+		if n.Param() == nil {
+			c.reportFlag(rng, parser.FEAT_OPT_CATCH_PARAM)
+		}
+	case *parser.Field:
+		if n.IsTsSig() || n.TypInfo() != nil && n.TypInfo().Declare() {
+			return
+		}
+		if isPrivate(n.Key()) {
+			c.reportFlag(rng, parser.FEAT_CLASS_PRIV_FIELD)
+		} else {
+			c.reportFlag(rng, parser.FEAT_CLASS_PUB_FIELD)
+		}
+	case *parser.Method:
+		if isPrivate(n.Key()) {
+			c.reportFlag(rng, parser.FEAT_CLASS_PRV)
+		}
+	case *parser.StaticBlock:
+		c.reportFlag(rng, parser.FEAT_CLASS_STATIC_BLOCK)
+	case *parser.Ident:
+		// the names in the typescript nodes are the types or the ones erased by the compiler
+		if astutil.IsRefIdent(n, parent) && !isTsNode(parent) {
+			if name := c.globalName(n, vc); name != "" {
+				c.report(rng, apiOf(name))
+			}
+		}
+	case *parser.MemberExpr:
+		if n.Compute() {
+			return
+		}
+		if obj := c.globalName(n.Obj(), vc); obj != "" {
+			c.report(rng, apiOf(obj+"."+n.Prop().(*parser.Ident).Val()))
+		}
+	case *parser.CallExpr:
+		if m, ok := n.Callee().(*parser.MemberExpr); ok && !m.Compute() {
+			c.reportMethod(m.Obj(), m.Prop())
+		}
+	}
+}
+
+func apiOf(name string) *Feature {
+	if f := featureByName[name]; f != nil && f.Kind == KIND_API {
+		return f
+	}
+	return nil
+}
+
+// the module syntax is reported once at the first module statement, the exports in the
+// typescript namespaces are not the module statements
+func (c *checker) reportModule(rng span.Range, parent parser.Node) {
+	if _, ok := parent.(*parser.Prog); ok && !c.module {
+		c.module = true
+		c.reportFlag(rng, parser.FEAT_MODULE)
+	}
+}
+
+// the constructor of the receiver if it's a literal, it's empty for the other receivers since
+// their types are unknown
+func receiverOf(obj parser.Node) string {
+	switch n := obj.(type) {
+	case *parser.StrLit:
+		return "String"
+	case *parser.TplExpr:
+		if n.Tag() == nil {
+			return "String"
+		}
+	case *parser.ArrLit:
+		return "Array"
+	}
+	return ""
+}
+
+// the prototype members named by the method are narrowed by the receiver if it's a literal,
+// otherwise all of them not supported by the targets are reported since any of them may be
+// the one called
+func (c *checker) reportMethod(obj, prop parser.Node) {
+	id, ok := prop.(*parser.Ident)
+	if !ok || id.IsPrivate() {
+		return
+	}
+	recv := receiverOf(obj)
+	for _, f := range protoMethods[id.Val()] {
+		if recv == "" || strings.HasPrefix(f.Name, recv+".prototype.") {
+			c.report(id.Range(), f)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package compat
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func check(t *testing.T, file, code, targets string) string {
+	opts := NewOpts()
+	ts, err := ParseTargets(targets)
+	AssertEqual(t, nil, err, "should be ok")
+	opts.Targets = ts
+
+	issues, err := Check(file, code, opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+	msgs := make([]string, len(issues))
+	for i, issue := range issues {
+		msgs[i] = issue.String()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func TestParseTargets(t *testing.T) {
+	ts, err := ParseTargets(`
+# the supported browsers
+chrome >= 80, Safari 13.1
+ios_saf >= 12, node>=14.8
+chrome 79`)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, 3, len(ts), "should be ok")
+	AssertEqual(t, "chrome 79", ts[0].String(), "should be ok")
+	AssertEqual(t, "safari 12", ts[1].String(), "should be ok")
+	AssertEqual(t, "node 14.8", ts[2].String(), "should be ok")
+
+	_, err = ParseTargets("ie 11")
+	AssertEqual(t, "unknown browser `ie` in query `ie 11`", err.Error(), "should be failed")
+	_, err = ParseTargets("last 2 versions")
+	AssertEqual(t, "unsupported query `last 2 versions`, only `<browser> <version>` and `<browser> >= <version>` are supported", err.Error(), "should be failed")
+	_, err = ParseTargets("defaults, chrome 80")
+	AssertEqual(t, "unsupported query `defaults`, only `<browser> <version>` and `<browser> >= <version>` are supported", err.Error(), "should be failed")
+	_, err = ParseTargets("chrome 8x")
+	AssertEqual(t, "invalid version `8x` in query `chrome 8x`", err.Error(), "should be failed")
+}
+
+func TestSyntax(t *testing.T) {
+	AssertEqual(t, `Nullish coalescing requires chrome 80, safari 13.1 at (1:10)
+Optional chaining requires chrome 80, safari 13.1 at (1:10)
+BigInt literal requires chrome 67, safari 14 at (1:18)
+Private class field requires chrome 74, safari 14.1 at (2:10)
+Class field requires chrome 72, safari 14 at (2:18)
+Private method requires chrome 84, safari 15 at (2:25)
+Class static block requires chrome 94, safari 16.4 at (2:33)
+Optional catch binding requires chrome 66, safari 11.1 at (3:7)
+Logical assignment requires chrome 85, safari 14 at (4:0)
+Top-level await requires chrome 89, safari 15 at (5:0)
+Module requires chrome 61 at (6:0)
+`+"`export * as ns` requires chrome 72, safari 14.1 at (6:0)"+`
+RegExp flag `+"`d`"+` requires chrome 90, safari 15 at (7:10)
+Numeric separator requires chrome 75, safari 13 at (7:20)
+Object rest and spread properties requires safari 11.1 at (7:32)`, check(t, "a.js", `const a = x?.y ?? 1n
+class A { #x = 1; y = 2; #m() {} static { } }
+try {} catch {}
+a ||= `+"`${b}`"+`
+await f()
+export * as ns from "x"
+const r = /a/d, n = 1_000, o = {...a}
+async function f() { for (const [a, [b]] of c) {} }`, "chrome >= 60, safari 12, ios_saf 11"), "should be ok")
+}
+
+func TestApis(t *testing.T) {
+	AssertEqual(t, "`structuredClone` requires chrome 98, safari 15.4 at (1:10)\n"+
+		"`Array.prototype.at` requires chrome 92, safari 15.4 at (2:61)\n"+
+		"`Object.hasOwn` requires chrome 93, safari 15.4 at (3:0)\n"+
+		"`String.prototype.replaceAll` requires chrome 85 at (3:27)",
+		check(t, "a.js", `const a = structuredClone(b)
+function f(structuredClone) { structuredClone(1); return [1].at(-1) }
+Object.hasOwn(a, "b"); a.b.replaceAll("x", "y"); a.structuredClone()`, "chrome 80, safari 14"), "should be ok")
+}
+
+func TestProtoMethods(t *testing.T) {
+	AssertEqual(t, "`String.prototype.at` requires chrome 92 at (1:6)\n"+
+		"`Array.prototype.at` requires chrome 92 at (1:21)\n"+
+		"`String.prototype.at` requires chrome 92 at (1:36)\n"+
+		"`Array.prototype.at` requires chrome 92 at (1:45)\n"+
+		"`String.prototype.at` requires chrome 92 at (1:45)",
+		check(t, "a.js", "\"abc\".at(-1); [1, 2].at(-1); `${a}`.at(0); a.at(0)", "chrome 80"), "should be ok")
+}
+
+func TestTs(t *testing.T) {
+	AssertEqual(t, "Optional chaining requires chrome 80 at (7:8)", check(t, "a.ts", `let p: Promise<Map<string, number>> = new Promise(r => r())
+declare class X { y: number }
+interface I { m(...a: string[]): void }
+import type { T } from "t"
+function g(Map: any) { return new Map() }
+namespace N { export const x = 1 }
+let z = (a as any)!.b?.c
+declare module "m" { export const a: Map<string, string> }`, "chrome 70"), "should be ok")
+}
+
+func TestES2022(t *testing.T) {
+	AssertEqual(t, `Hashbang comment requires chrome 74 at (1:0)
+Private class field requires chrome 74 at (2:10)
+Class static block requires chrome 94 at (2:14)
+Private name in `+"`in`"+` expression requires chrome 91 at (2:39)
+String module export name requires chrome 88 at (3:14)`, check(t, "a.js", `#!/usr/bin/env node
+class A { #x; static { } m(o) { return #x in o } }
+export { A as "a b" }`, "chrome 72"), "should be ok")
+}
+
+func TestUsing(t *testing.T) {
+	AssertEqual(t, `Using declaration requires chrome 134 at (1:2)
+Using declaration requires chrome 134 at (2:5)`, check(t, "a.js", `{ using a = b() }
+for (using x of y);`, "chrome 120, safari 17"), "should be ok")
+}
+
+func TestImportAttrs(t *testing.T) {
+	AssertEqual(t, `Import attributes requires chrome 123 at (1:25)
+Import attributes requires chrome 123 at (2:29)`, check(t, "a.js", `import a from "./a.json" with { type: "json" }
+const b = import("./b.json", { with: { type: "json" } })`, "chrome 120"), "should be ok")
+}
+
+func TestRegexpUnicodeSets(t *testing.T) {
+	AssertEqual(t, "RegExp flag `v` requires chrome 112, safari 17 at (1:10)",
+		check(t, "a.js", `const a = /[\p{L}--[a-z]]/v`, "chrome 110, safari 16"), "should be ok")
+}
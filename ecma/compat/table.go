@@ -0,0 +1,98 @@
+package compat
+
+import "github.com/hsiaosiyuan0/mole/ecma/parser"
+
+// the bundled compat table, the support of each feature is the first versions of the browsers
+// and runtimes which support it without flags, the data is collected from MDN and caniuse
+
+var syntaxFeatures = []*Feature{
+	{Name: "Lexical declaration", Feat: parser.FEAT_LET_CONST, support: "chrome 49, edge 14, firefox 44, safari 10, opera 36, node 6"},
+	{Name: "Arrow function", support: "chrome 45, edge 12, firefox 22, safari 10, opera 32, node 4"},
+	{Name: "Class", support: "chrome 49, edge 13, firefox 45, safari 9, opera 36, node 6"},
+	{Name: "Template literal", support: "chrome 41, edge 12, firefox 34, safari 9, opera 28, node 4"},
+	{Name: "Generator", support: "chrome 39, edge 13, firefox 26, safari 10, opera 26, node 4"},
+	{Name: "for...of", support: "chrome 38, edge 12, firefox 13, safari 7, opera 25, node 0.12"},
+	{Name: "Destructuring pattern", Feat: parser.FEAT_BINDING_PATTERN, support: "chrome 49, edge 14, firefox 41, safari 8, opera 36, node 6"},
+	{Name: "Spread and rest syntax", Feat: parser.FEAT_SPREAD, support: "chrome 47, edge 12, firefox 27, safari 10, opera 34, node 6"},
+	{Name: "Module", Feat: parser.FEAT_MODULE, support: "chrome 61, edge 16, firefox 60, safari 10.1, opera 48, node 12.17"},
+	{Name: "new.target", Feat: parser.FEAT_META_PROPERTY, support: "chrome 46, edge 14, firefox 41, safari 10, opera 33, node 5"},
+	{Name: "RegExp flag `u`", Feat: parser.FEAT_REGEXP_UNICODE, support: "chrome 50, edge 13, firefox 46, safari 10, opera 37, node 6"},
+	{Name: "RegExp flag `y`", Feat: parser.FEAT_REGEXP_STICKY, support: "chrome 49, edge 13, firefox 3, safari 10, opera 36, node 6"},
+	{Name: "Exponentiation operator", Feat: parser.FEAT_POW, support: "chrome 52, edge 14, firefox 52, safari 10.1, opera 39, node 7"},
+	{Name: "Async function", Feat: parser.FEAT_ASYNC_AWAIT, support: "chrome 55, edge 15, firefox 52, safari 10.1, opera 42, node 7.6"},
+	{Name: "Async generator", Feat: parser.FEAT_ASYNC_GENERATOR, support: "chrome 63, edge 79, firefox 55, safari 12, opera 50, node 10"},
+	{Name: "Async iteration", Feat: parser.FEAT_ASYNC_ITERATION, support: "chrome 63, edge 79, firefox 57, safari 12, opera 50, node 10"},
+	{Name: "Object rest and spread properties", support: "chrome 60, edge 79, firefox 55, safari 11.1, opera 47, node 8.3"},
+	{Name: "RegExp flag `s`", Feat: parser.FEAT_REGEXP_DOT_ALL, support: "chrome 62, edge 79, firefox 78, safari 11.1, opera 49, node 8.10"},
+	{Name: "Optional catch binding", Feat: parser.FEAT_OPT_CATCH_PARAM, support: "chrome 66, edge 79, firefox 58, safari 11.1, opera 53, node 10"},
+	{Name: "Optional chaining", Feat: parser.FEAT_OPT_EXPR, support: "chrome 80, edge 80, firefox 74, safari 13.1, opera 67, node 14"},
+	{Name: "Nullish coalescing", Feat: parser.FEAT_NULLISH, support: "chrome 80, edge 80, firefox 72, safari 13.1, opera 67, node 14"},
+	{Name: "BigInt literal", Feat: parser.FEAT_BIGINT, support: "chrome 67, edge 79, firefox 68, safari 14, opera 54, node 10.4"},
+	{Name: "Dynamic import", Feat: parser.FEAT_DYNAMIC_IMPORT, support: "chrome 63, edge 79, firefox 67, safari 11.1, opera 50, node 13.2"},
+	{Name: "import.meta", support: "chrome 64, edge 79, firefox 62, safari 11.1, opera 51, node 10.4"},
+	{Name: "`export * as ns`", Feat: parser.FEAT_EXPORT_ALL_AS_NS, support: "chrome 72, edge 79, firefox 80, safari 14.1, opera 60, node 12"},
+	{Name: "Numeric separator", Feat: parser.FEAT_NUM_SEP, support: "chrome 75, edge 79, firefox 70, safari 13, opera 62, node 12.5"},
+	{Name: "Logical assignment", Feat: parser.FEAT_LOGIC_ASSIGN, support: "chrome 85, edge 85, firefox 79, safari 14, opera 71, node 15"},
+	{Name: "Class field", Feat: parser.FEAT_CLASS_PUB_FIELD, support: "chrome 72, edge 79, firefox 69, safari 14, opera 60, node 12"},
+	{Name: "Private class field", Feat: parser.FEAT_CLASS_PRIV_FIELD, support: "chrome 74, edge 79, firefox 90, safari 14.1, opera 62, node 12"},
+	{Name: "Private method", Feat: parser.FEAT_CLASS_PRV, support: "chrome 84, edge 84, firefox 90, safari 15, opera 70, node 14.6"},
+	{Name: "Class static block", Feat: parser.FEAT_CLASS_STATIC_BLOCK, support: "chrome 94, edge 94, firefox 93, safari 16.4, opera 80, node 16.11"},
+	{Name: "Private name in `in` expression", Feat: parser.FEAT_CLASS_PRIV_IN, support: "chrome 91, edge 91, firefox 90, safari 15, opera 77, node 16.4"},
+	{Name: "String module export name", Feat: parser.FEAT_MODULE_STR_NAME, support: "chrome 88, edge 88, firefox 87, safari 14.1, opera 74, node 16"},
+	{Name: "Top-level await", Feat: parser.FEAT_GLOBAL_ASYNC, support: "chrome 89, edge 89, firefox 89, safari 15, opera 75, node 14.8"},
+	{Name: "RegExp flag `d`", Feat: parser.FEAT_REGEXP_HAS_INDICES, support: "chrome 90, edge 90, firefox 88, safari 15, opera 76, node 16"},
+	{Name: "RegExp flag `v`", Feat: parser.FEAT_REGEXP_UNICODE_SETS, support: "chrome 112, edge 112, firefox 116, safari 17, opera 98, node 20"},
+	{Name: "Using declaration", Feat: parser.FEAT_USING, support: "chrome 134, edge 134, firefox 141, node 24"},
+	{Name: "Import attributes", Feat: parser.FEAT_IMPORT_ATTRS, support: "chrome 123, edge 123, firefox 138, safari 17.2, opera 109, node 20.10"},
+	{Name: "Hashbang comment", Feat: parser.FEAT_HASHBANG, support: "chrome 74, edge 79, firefox 67, safari 13.1, opera 62, node 0.10"},
+}
+
+// the global objects and the static members are resolved by their names if they are not
+// shadowed by the local bindings, the prototype members are matched by the names of the
+// called methods since the types of the receivers are unknown
+var apiFeatures = []*Feature{
+	{Name: "Symbol", support: "chrome 38, edge 12, firefox 36, safari 9, opera 25, node 0.12"},
+	{Name: "Promise", support: "chrome 32, edge 12, firefox 29, safari 8, opera 19, node 0.12"},
+	{Name: "Map", support: "chrome 38, edge 12, firefox 13, safari 8, opera 25, node 0.12"},
+	{Name: "Set", support: "chrome 38, edge 12, firefox 13, safari 8, opera 25, node 0.12"},
+	{Name: "WeakMap", support: "chrome 36, edge 12, firefox 6, safari 8, opera 23, node 0.12"},
+	{Name: "WeakSet", support: "chrome 36, edge 12, firefox 34, safari 9, opera 23, node 0.12"},
+	{Name: "Proxy", support: "chrome 49, edge 12, firefox 18, safari 10, opera 36, node 6"},
+	{Name: "Reflect", support: "chrome 49, edge 12, firefox 42, safari 10, opera 36, node 6"},
+	{Name: "fetch", support: "chrome 42, edge 14, firefox 39, safari 10.1, opera 29, node 18"},
+	{Name: "BigInt", support: "chrome 67, edge 79, firefox 68, safari 14, opera 54, node 10.4"},
+	{Name: "globalThis", support: "chrome 71, edge 79, firefox 65, safari 12.1, opera 58, node 12"},
+	{Name: "queueMicrotask", support: "chrome 71, edge 79, firefox 69, safari 12.1, opera 58, node 11"},
+	{Name: "AbortController", support: "chrome 66, edge 16, firefox 57, safari 12.1, opera 53, node 15"},
+	{Name: "WeakRef", support: "chrome 84, edge 84, firefox 79, safari 14.1, opera 70, node 14.6"},
+	{Name: "FinalizationRegistry", support: "chrome 84, edge 84, firefox 79, safari 14.1, opera 70, node 14.6"},
+	{Name: "AggregateError", support: "chrome 85, edge 85, firefox 79, safari 14, opera 71, node 15"},
+	{Name: "structuredClone", support: "chrome 98, edge 98, firefox 94, safari 15.4, opera 84, node 17"},
+
+	{Name: "Array.from", support: "chrome 45, edge 12, firefox 32, safari 9, opera 32, node 4"},
+	{Name: "Object.assign", support: "chrome 45, edge 12, firefox 34, safari 9, opera 32, node 4"},
+	{Name: "Object.entries", support: "chrome 54, edge 14, firefox 47, safari 10.1, opera 41, node 7"},
+	{Name: "Object.values", support: "chrome 54, edge 14, firefox 47, safari 10.1, opera 41, node 7"},
+	{Name: "Object.fromEntries", support: "chrome 73, edge 79, firefox 63, safari 12.1, opera 60, node 12"},
+	{Name: "Object.hasOwn", support: "chrome 93, edge 93, firefox 92, safari 15.4, opera 79, node 16.9"},
+	{Name: "Object.groupBy", support: "chrome 117, edge 117, firefox 119, safari 17.4, opera 103, node 21"},
+	{Name: "Promise.allSettled", support: "chrome 76, edge 79, firefox 71, safari 13, opera 63, node 12.9"},
+	{Name: "Promise.any", support: "chrome 85, edge 85, firefox 79, safari 14, opera 71, node 15"},
+	{Name: "Promise.withResolvers", support: "chrome 119, edge 119, firefox 121, safari 17.4, opera 105, node 22"},
+
+	{Name: "Array.prototype.includes", support: "chrome 47, edge 14, firefox 43, safari 9, opera 34, node 6"},
+	{Name: "Array.prototype.flat", support: "chrome 69, edge 79, firefox 62, safari 12, opera 56, node 11"},
+	{Name: "Array.prototype.flatMap", support: "chrome 69, edge 79, firefox 62, safari 12, opera 56, node 11"},
+	{Name: "Array.prototype.at", support: "chrome 92, edge 92, firefox 90, safari 15.4, opera 78, node 16.6"},
+	{Name: "Array.prototype.findLast", support: "chrome 97, edge 97, firefox 104, safari 15.4, opera 83, node 18"},
+	{Name: "Array.prototype.findLastIndex", support: "chrome 97, edge 97, firefox 104, safari 15.4, opera 83, node 18"},
+	{Name: "Array.prototype.toSorted", support: "chrome 110, edge 110, firefox 115, safari 16, opera 96, node 20"},
+	{Name: "Array.prototype.toReversed", support: "chrome 110, edge 110, firefox 115, safari 16, opera 96, node 20"},
+	{Name: "String.prototype.at", support: "chrome 92, edge 92, firefox 90, safari 15.4, opera 78, node 16.6"},
+	{Name: "String.prototype.padStart", support: "chrome 57, edge 15, firefox 48, safari 10, opera 44, node 8"},
+	{Name: "String.prototype.padEnd", support: "chrome 57, edge 15, firefox 48, safari 10, opera 44, node 8"},
+	{Name: "String.prototype.trimStart", support: "chrome 66, edge 79, firefox 61, safari 12, opera 53, node 10"},
+	{Name: "String.prototype.trimEnd", support: "chrome 66, edge 79, firefox 61, safari 12, opera 53, node 10"},
+	{Name: "String.prototype.matchAll", support: "chrome 73, edge 79, firefox 67, safari 13, opera 60, node 12"},
+	{Name: "String.prototype.replaceAll", support: "chrome 85, edge 85, firefox 77, safari 13.1, opera 71, node 15"},
+}
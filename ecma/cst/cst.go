@@ -0,0 +1,386 @@
+// the lossless concrete syntax tree, it's built on top of the AST and the tokens consumed by
+// the parser, every byte of the source is owned by exactly one element of the tree, for example:
+//
+//	a = f( /* b */ 1, 2 );
+//
+// the `CallExpr` owns its tokens `(`, `,` and `)` as well as the comment and the whitespaces
+// between them, while `f`, `1` and `2` are owned by the child nodes, so printing the tree
+// reproduces the source byte-for-byte
+//
+// the edits are made by `Replace` on the nodes and the tokens, the untouched subtrees are
+// printed as they are written and the modified nodes are re-emitted in place, the trivia around
+// them belongs to their parents so the surrounding formatting is preserved
+package cst
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// an element of the tree, it's either a `*Node` or a `*Token`
+type Elem interface {
+	Range() span.Range
+	Parent() *Node
+	Text() string
+}
+
+// the token or the trivia run owned by a node, the comments and the whitespaces are the
+// trivia whose values are `T_COMMENT` and `T_WHITESPACE`
+type Token struct {
+	val    parser.TokenValue
+	rng    span.Range
+	parent *Node
+	repl   *string
+}
+
+func (t *Token) Val() parser.TokenValue {
+	return t.val
+}
+
+func (t *Token) Range() span.Range {
+	return t.rng
+}
+
+func (t *Token) Parent() *Node {
+	return t.parent
+}
+
+func (t *Token) IsTrivia() bool {
+	return t.val == parser.T_COMMENT || t.val == parser.T_WHITESPACE
+}
+
+// the original text of the token
+func (t *Token) Raw() string {
+	return t.parent.tree.src.RngText(t.rng)
+}
+
+// the text of the token with the edit applied
+func (t *Token) Text() string {
+	if t.repl != nil {
+		return *t.repl
+	}
+	return t.Raw()
+}
+
+func (t *Token) Replace(text string) {
+	t.repl = &text
+	t.parent.markDirty()
+}
+
+func (t *Token) Modified() bool {
+	return t.repl != nil
+}
+
+type Node struct {
+	tree   *Tree
+	ast    parser.Node
+	rng    span.Range
+	parent *Node
+	elems  []Elem
+
+	repl  *string
+	dirty bool // whether there are edits in the subtree
+}
+
+func (n *Node) Ast() parser.Node {
+	return n.ast
+}
+
+func (n *Node) Type() parser.NodeType {
+	return n.ast.Type()
+}
+
+// the range of the source covered by the elements of the node, it's the range of the AST node
+// except for the root which covers the entire source
+func (n *Node) Range() span.Range {
+	return n.rng
+}
+
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// the child nodes and the tokens owned by the node in the order of their positions
+func (n *Node) Elems() []Elem {
+	return n.elems
+}
+
+func (n *Node) Children() []*Node {
+	ret := make([]*Node, 0)
+	for _, e := range n.elems {
+		if c, ok := e.(*Node); ok {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// the tokens owned by the node itself, the trivia is excluded
+func (n *Node) Tokens() []*Token {
+	ret := make([]*Token, 0)
+	for _, e := range n.elems {
+		if t, ok := e.(*Token); ok && !t.IsTrivia() {
+			ret = append(ret, t)
+		}
+	}
+	return ret
+}
+
+func (n *Node) index() int {
+	if n.parent == nil {
+		return -1
+	}
+	for i, e := range n.parent.elems {
+		if e == Elem(n) {
+			return i
+		}
+	}
+	return -1
+}
+
+// the trivia owned by the parent right before the node
+func (n *Node) LeadingTrivia() []*Token {
+	i := n.index()
+	if i == -1 {
+		return nil
+	}
+	j := i
+	for j > 0 {
+		if t, ok := n.parent.elems[j-1].(*Token); ok && t.IsTrivia() {
+			j--
+			continue
+		}
+		break
+	}
+	return triviaOf(n.parent.elems[j:i])
+}
+
+// the trivia owned by the parent right after the node
+func (n *Node) TrailingTrivia() []*Token {
+	i := n.index()
+	if i == -1 {
+		return nil
+	}
+	j := i + 1
+	for j < len(n.parent.elems) {
+		if t, ok := n.parent.elems[j].(*Token); ok && t.IsTrivia() {
+			j++
+			continue
+		}
+		break
+	}
+	return triviaOf(n.parent.elems[i+1 : j])
+}
+
+func triviaOf(elems []Elem) []*Token {
+	ret := make([]*Token, len(elems))
+	for i, e := range elems {
+		ret[i] = e.(*Token)
+	}
+	return ret
+}
+
+type Tree struct {
+	src   *span.Source
+	root  *Node
+	nodes map[parser.Node]*Node
+}
+
+func (t *Tree) Source() *span.Source {
+	return t.src
+}
+
+func (t *Tree) Root() *Node {
+	return t.root
+}
+
+// the node of the tree which wraps the AST node, it's `nil` if the AST node has no tokens
+// such as the omitted elements of array, or it's not nested in its parent by range
+func (t *Tree) Node(node parser.Node) *Node {
+	return t.nodes[node]
+}
+
+// parses the source and builds the tree, `opts.Tokens` is turned on implicitly
+func Parse(src *span.Source, opts *parser.ParserOpts) (*Tree, error) {
+	if opts == nil {
+		opts = parser.NewParserOpts()
+	}
+	opts = opts.Clone()
+	opts.Tokens = true
+
+	p := parser.NewParser(src, opts)
+	ast, err := p.Prog()
+	if err != nil {
+		return nil, err
+	}
+	return Build(p, ast)
+}
+
+// builds the tree from the program parsed by `p`, the parser should be created with the
+// `Tokens` option turned on
+func Build(p *parser.Parser, prog parser.Node) (*Tree, error) {
+	src := p.Source()
+	toks := p.Tokens()
+	if len(toks) == 0 && src.Len() > 0 && prog != nil && len(parser.ChildNodes(prog)) > 0 {
+		return nil, errors.New("the tokens are not recorded, turn on the `Tokens` option of the parser")
+	}
+
+	t := &Tree{src: src, nodes: map[parser.Node]*Node{}}
+	t.root = &Node{tree: t, ast: prog, rng: span.Range{Lo: 0, Hi: uint32(src.Len())}}
+	t.nodes[prog] = t.root
+
+	b := &builder{tree: t}
+	b.nest(prog)
+	b.lex(toks, p.Comments())
+	b.fill(t.root)
+	return t, nil
+}
+
+type builder struct {
+	tree  *Tree
+	elems []*Token
+	i     int
+}
+
+// builds the skeleton of the tree by the ranges of the AST nodes, the nodes are nested by
+// their ranges instead of the relationships in AST, since a few nodes are not covered by their
+// parents, for instance the type annotations of the identifiers, such nodes are attached to
+// the innermost node which covers them and the ones overlap their siblings are dropped
+func (b *builder) nest(prog parser.Node) {
+	nodes := make([]parser.Node, 0)
+	seen := map[parser.Node]bool{prog: true}
+	var collect func(node parser.Node)
+	collect = func(node parser.Node) {
+		for _, c := range parser.ChildNodes(node) {
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			if c.Range().Lo < c.Range().Hi {
+				nodes = append(nodes, c)
+			}
+			collect(c)
+		}
+	}
+	collect(prog)
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i].Range(), nodes[j].Range()
+		if a.Lo != b.Lo {
+			return a.Lo < b.Lo
+		}
+		return a.Hi > b.Hi
+	})
+
+	stk := []*Node{b.tree.root}
+	for _, node := range nodes {
+		rng := node.Range()
+		for len(stk) > 1 {
+			top := stk[len(stk)-1].rng
+			if rng.Lo >= top.Lo && rng.Hi <= top.Hi {
+				break
+			}
+			stk = stk[:len(stk)-1]
+		}
+		top := stk[len(stk)-1]
+		if rng.Hi > top.rng.Hi {
+			continue
+		}
+		if n := len(top.elems); n > 0 && top.elems[n-1].Range().Hi > rng.Lo {
+			continue
+		}
+		c := &Node{tree: b.tree, ast: node, rng: rng, parent: top}
+		top.elems = append(top.elems, c)
+		b.tree.nodes[node] = c
+		stk = append(stk, c)
+	}
+}
+
+// splits the source into the tokens and the trivia runs between them, the trivia runs are
+// the comments and the text between the tokens and the comments
+func (b *builder) lex(toks []parser.Token, cmts []span.Range) {
+	sorted := make([]span.Range, 0, len(toks))
+	vals := make(map[uint32]parser.TokenValue, len(toks))
+	for i := range toks {
+		rng := toks[i].Range()
+		sorted = append(sorted, rng)
+		vals[rng.Lo] = toks[i].Val()
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Lo < sorted[j].Lo
+	})
+
+	b.elems = make([]*Token, 0, len(toks)*2)
+	ci := 0
+	trivia := func(lo, hi uint32) {
+		for ; ci < len(cmts) && cmts[ci].Lo < hi; ci++ {
+			cmt := cmts[ci]
+			if cmt.Lo < lo || cmt.Hi > hi {
+				continue
+			}
+			b.add(parser.T_WHITESPACE, lo, cmt.Lo)
+			b.add(parser.T_COMMENT, cmt.Lo, cmt.Hi)
+			lo = cmt.Hi
+		}
+		b.add(parser.T_WHITESPACE, lo, hi)
+	}
+
+	var ofst uint32
+	for _, rng := range sorted {
+		if rng.Lo < ofst || rng.Lo >= rng.Hi {
+			continue
+		}
+		trivia(ofst, rng.Lo)
+		b.add(vals[rng.Lo], rng.Lo, rng.Hi)
+		ofst = rng.Hi
+	}
+	trivia(ofst, uint32(b.tree.src.Len()))
+}
+
+func (b *builder) add(val parser.TokenValue, lo, hi uint32) {
+	if lo < hi {
+		b.elems = append(b.elems, &Token{val: val, rng: span.Range{Lo: lo, Hi: hi}})
+	}
+}
+
+// distributes the tokens to the innermost nodes which cover them, the range of the node is
+// adjusted to the elements it finally owns, so printing the unchanged node by its range is
+// always identical to printing its elements
+func (b *builder) fill(n *Node) {
+	children := n.elems
+	n.elems = make([]Elem, 0, len(children)*2+1)
+
+	lo := n.rng.Lo
+	if b.i < len(b.elems) && b.elems[b.i].rng.Lo < lo {
+		lo = b.elems[b.i].rng.Lo
+	}
+	for _, e := range children {
+		c := e.(*Node)
+		b.take(n, c.rng.Lo)
+		n.elems = append(n.elems, c)
+		b.fill(c)
+	}
+	b.take(n, n.rng.Hi)
+
+	hi := lo
+	if len(n.elems) > 0 {
+		hi = n.elems[len(n.elems)-1].Range().Hi
+		lo = n.elems[0].Range().Lo
+	}
+	if n.parent == nil {
+		n.rng = span.Range{Lo: 0, Hi: uint32(b.tree.src.Len())}
+	} else {
+		n.rng = span.Range{Lo: lo, Hi: hi}
+	}
+}
+
+func (b *builder) take(n *Node, before uint32) {
+	for ; b.i < len(b.elems) && b.elems[b.i].rng.Lo < before; b.i++ {
+		tok := b.elems[b.i]
+		tok.parent = n
+		n.elems = append(n.elems, tok)
+	}
+}
@@ -0,0 +1,103 @@
+package cst
+
+import (
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func parse(t *testing.T, code string, ts bool) *Tree {
+	opts := parser.NewParserOpts()
+	if ts {
+		opts.Feature = opts.Feature.On(parser.FEAT_TS)
+	}
+	tree, err := Parse(span.NewSource("", code), opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+	return tree
+}
+
+func stmt(tree *Tree, i int) *Node {
+	return tree.Root().Children()[i]
+}
+
+func texts(toks []*Token) []string {
+	ret := make([]string, len(toks))
+	for i, tok := range toks {
+		ret[i] = tok.Text()
+	}
+	return ret
+}
+
+func TestLossless(t *testing.T) {
+	codes := []string{
+		"#!/usr/bin/env node\n// a\nvar a = f( /* b */ 1, 2 ) ;\n\n",
+		"let [x, , y] = { a, b: c, ...d }\r\nclass A { #a = 1; static { this.#a } }",
+		"if (a) /b/g.test(`c${d}e`); else {}\n/* tail */",
+		"x = <a b={c}>d {/* e */} <f.g {...h} /></a>;",
+		"",
+		"  \n",
+	}
+	for _, code := range codes {
+		tree := parse(t, code, false)
+		AssertEqual(t, code, tree.String(), "should be lossless")
+	}
+
+	code := "@d class A<T> implements B { @e m(a?: T = 1): void {} }\nlet c = <T,>(d: T) => d as unknown"
+	AssertEqual(t, code, parse(t, code, true).String(), "should be lossless")
+}
+
+func TestOwnership(t *testing.T) {
+	tree := parse(t, "a = f( /* b */ 1, 2 ); // c\n", false)
+
+	call := stmt(tree, 0).Children()[0].Children()[1]
+	AssertEqual(t, parser.N_EXPR_CALL, call.Type(), "should be ok")
+	AssertEqual(t, []string{"(", ",", ")"}, texts(call.Tokens()), "should be ok")
+	AssertEqual(t, 11, len(call.Elems()), "should be ok")
+	AssertEqual(t, []string{" ", "/* b */", " "}, texts(call.Children()[1].LeadingTrivia()), "should be ok")
+
+	AssertEqual(t, []string{";"}, texts(stmt(tree, 0).Tokens()), "should be ok")
+	AssertEqual(t, []string{" ", "// c", "\n"}, texts(stmt(tree, 0).TrailingTrivia()), "should be ok")
+
+	ast := stmt(tree, 0).Ast()
+	AssertEqual(t, stmt(tree, 0), tree.Node(ast), "should be ok")
+}
+
+func TestReplace(t *testing.T) {
+	code := "var a = f( /* b */ 1,\n  2 ); // c\nlet d"
+	tree := parse(t, code, false)
+
+	dec := stmt(tree, 0)
+	dec.Tokens()[0].Replace("const")
+	call := dec.Children()[0].Children()[1]
+	call.Children()[1].Replace("x + 1")
+	AssertEqual(t, "const a = f( /* b */ x + 1,\n  2 ); // c\nlet d", tree.String(), "should be ok")
+	AssertEqual(t, true, dec.Dirty(), "should be ok")
+	AssertEqual(t, false, dec.Modified(), "should be ok")
+	AssertEqual(t, false, stmt(tree, 1).Dirty(), "should be ok")
+
+	// the replacement of the ancestor supersedes the edits inside it
+	call.Replace("g()")
+	AssertEqual(t, "const a = g(); // c\nlet d", tree.String(), "should be ok")
+	AssertEqual(t, "f( /* b */ 1,\n  2 )", call.Raw(), "should be ok")
+
+	stmt(tree, 1).Replace("")
+	AssertEqual(t, "const a = g(); // c\n", tree.String(), "should be ok")
+}
+
+func TestIndent(t *testing.T) {
+	tree := parse(t, "function f() {\n\t  return 1\n}", false)
+	body := stmt(tree, 0).Children()[1]
+	AssertEqual(t, "\t  ", body.Children()[0].Indent(), "should be ok")
+	AssertEqual(t, "", body.Indent(), "should be ok")
+}
+
+func TestBuildWithoutTokens(t *testing.T) {
+	p := parser.NewParser(span.NewSource("", "a"), parser.NewParserOpts())
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	_, err = Build(p, ast)
+	AssertEqual(t, "the tokens are not recorded, turn on the `Tokens` option of the parser", err.Error(), "should be ok")
+}
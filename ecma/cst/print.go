@@ -0,0 +1,76 @@
+package cst
+
+import (
+	"strings"
+)
+
+func (n *Node) markDirty() {
+	for p := n; p != nil && !p.dirty; p = p.parent {
+		p.dirty = true
+	}
+}
+
+// replaces the node with `text`, the elements of the node are discarded when printing while the
+// trivia around it is kept since it's owned by the parent
+func (n *Node) Replace(text string) {
+	n.repl = &text
+	n.markDirty()
+}
+
+// whether the node itself is replaced
+func (n *Node) Modified() bool {
+	return n.repl != nil
+}
+
+// whether there are edits in the subtree of the node
+func (n *Node) Dirty() bool {
+	return n.dirty
+}
+
+// the original text of the node
+func (n *Node) Raw() string {
+	return n.tree.src.RngText(n.rng)
+}
+
+// the text of the node with the edits applied
+func (n *Node) Text() string {
+	if !n.dirty {
+		return n.Raw()
+	}
+	var sb strings.Builder
+	n.print(&sb)
+	return sb.String()
+}
+
+func (n *Node) print(sb *strings.Builder) {
+	if n.repl != nil {
+		sb.WriteString(*n.repl)
+		return
+	}
+	if !n.dirty {
+		sb.WriteString(n.Raw())
+		return
+	}
+	for _, e := range n.elems {
+		switch e := e.(type) {
+		case *Node:
+			e.print(sb)
+		case *Token:
+			sb.WriteString(e.Text())
+		}
+	}
+}
+
+// prints the entire source with the edits applied
+func (t *Tree) String() string {
+	return t.root.Text()
+}
+
+// the indentation of the line where the node begins, it's useful to re-indent the multiline
+// text before replacing the node with it
+func (n *Node) Indent() string {
+	code := n.tree.src.Text(0, n.rng.Lo)
+	i := strings.LastIndexAny(code, "\r\n") + 1
+	line := code[i:]
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,848 @@
+// extracts the API documentation of a package, the exported functions, classes, interfaces,
+// type aliases, enums, namespaces and variables of the entry modules are collected with their
+// signatures and the leading JSDoc comments, the re-exports such as `export * from "./x"` are
+// followed to the modules they refer to
+//
+// the signatures are rendered from the AST, so the bodies and the initial values are omitted
+// and the types are rendered in the canonical form, the result can be emitted as JSON or as
+// the Markdown files by `Markdown`
+package doc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/jsdoc"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type SymKind string
+
+const (
+	SYM_FUNCTION  SymKind = "function"
+	SYM_CLASS     SymKind = "class"
+	SYM_INTERFACE SymKind = "interface"
+	SYM_TYPE      SymKind = "type"
+	SYM_ENUM      SymKind = "enum"
+	SYM_NAMESPACE SymKind = "namespace"
+	SYM_CONST     SymKind = "const"
+	SYM_VARIABLE  SymKind = "variable" // the `let` and `var` declarations and the default exported expressions
+
+	// the kinds of the members
+	SYM_CONSTRUCTOR SymKind = "constructor"
+	SYM_METHOD      SymKind = "method"
+	SYM_PROPERTY    SymKind = "property"
+	SYM_GETTER      SymKind = "getter"
+	SYM_SETTER      SymKind = "setter"
+	SYM_CALL        SymKind = "call"      // the call signatures of the interfaces
+	SYM_CONSTRUCT   SymKind = "construct" // the construct signatures of the interfaces
+	SYM_INDEX       SymKind = "index"     // the index signatures
+	SYM_ENUM_MEMBER SymKind = "member"
+)
+
+type Symbol struct {
+	Name      string   `json:"name"`
+	Kind      SymKind  `json:"kind"`
+	Signature string   `json:"signature"`
+	Overloads []string `json:"overloads,omitempty"` // the overload signatures, `Signature` is the first one
+	Default   bool     `json:"default,omitempty"`   // exported as the default export
+	Doc       *Doc     `json:"doc,omitempty"`
+	File      string   `json:"file"` // the path relative to the package directory
+	Line      int      `json:"line"`
+
+	// the members of the classes, interfaces, enums and namespaces
+	Members []*Symbol `json:"members,omitempty"`
+}
+
+func (s *Symbol) clone(name string) *Symbol {
+	c := *s
+	c.Name = name
+	return &c
+}
+
+type ParamDoc struct {
+	Name        string `json:"name"`
+	Type        string `json:"type,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+	Default     string `json:"default,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type ReturnsDoc struct {
+	Type        string `json:"type,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+type TagDoc struct {
+	Tag  string `json:"tag"`
+	Text string `json:"text,omitempty"`
+}
+
+// the JSDoc comment of the symbol, the tags other than the params, the returns, the
+// deprecation and the examples are kept in `Tags` as they are
+type Doc struct {
+	Description string      `json:"description,omitempty"`
+	Params      []*ParamDoc `json:"params,omitempty"`
+	Returns     *ReturnsDoc `json:"returns,omitempty"`
+	Deprecated  bool        `json:"deprecated,omitempty"`
+	Deprecation string      `json:"deprecation,omitempty"` // the reason of the deprecation
+	Examples    []string    `json:"examples,omitempty"`
+	Tags        []*TagDoc   `json:"tags,omitempty"`
+}
+
+type Module struct {
+	Entry   string    `json:"entry"` // the subpath of the entry such as `.` and `./utils`
+	File    string    `json:"file"`  // the path relative to the package directory
+	Symbols []*Symbol `json:"symbols"`
+}
+
+type Package struct {
+	Name        string    `json:"name,omitempty"`
+	Version     string    `json:"version,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Modules     []*Module `json:"modules"`
+
+	// the problems which do not stop the extraction, such as the re-exports refer to the
+	// modules which cannot be resolved
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+type Opts struct {
+	// the entry modules relative to the package directory, they're read from the `exports`,
+	// `types`, `module` and `main` of the `package.json` if it's empty
+	Entries []string
+
+	ParserOpts *parser.ParserOpts
+}
+
+func NewOpts() *Opts {
+	return &Opts{
+		Entries:    make([]string, 0),
+		ParserOpts: parser.NewParserOpts(),
+	}
+}
+
+type pkgJson struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version"`
+	Description string          `json:"description"`
+	Exports     json.RawMessage `json:"exports"`
+	Types       string          `json:"types"`
+	Typings     string          `json:"typings"`
+	Source      string          `json:"source"`
+	Module      string          `json:"module"`
+	Main        string          `json:"main"`
+}
+
+// extracts the documentation of the package in the directory
+func Extract(dir string, opts *Opts) (*Package, error) {
+	if opts == nil {
+		opts = NewOpts()
+	}
+
+	pkg := &Package{Modules: make([]*Module, 0)}
+	var pj pkgJson
+	if b, err := os.ReadFile(filepath.Join(dir, "package.json")); err == nil {
+		if err := json.Unmarshal(b, &pj); err != nil {
+			return nil, fmt.Errorf("package.json: %w", err)
+		}
+		pkg.Name, pkg.Version, pkg.Description = pj.Name, pj.Version, pj.Description
+	}
+
+	var entries []*entry
+	if len(opts.Entries) > 0 {
+		entries = make([]*entry, 0, len(opts.Entries))
+		for _, e := range opts.Entries {
+			file := resolveFile(filepath.Join(dir, e))
+			if file == "" {
+				return nil, fmt.Errorf("cannot resolve the entry: %s", e)
+			}
+			entries = append(entries, &entry{"./" + strings.TrimPrefix(filepath.ToSlash(e), "./"), file})
+		}
+	} else {
+		entries = entriesOf(dir, &pj)
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("cannot find the entry modules of the package in %s", dir)
+		}
+	}
+
+	x := &extractor{dir: dir, opts: opts, pkg: pkg, mods: map[string]*module{}}
+	for _, e := range entries {
+		mod, err := x.module(e.file)
+		if err != nil {
+			return nil, err
+		}
+		syms, err := x.exportsOf(mod)
+		if err != nil {
+			return nil, err
+		}
+		pkg.Modules = append(pkg.Modules, &Module{e.subpath, mod.rel, syms})
+	}
+	return pkg, nil
+}
+
+type entry struct {
+	subpath string
+	file    string
+}
+
+// the conditions of the `exports` in the order of preference, the declaration files are
+// preferred since they have the types
+var conditions = []string{"types", "typings", "import", "module", "default", "require", "node"}
+
+func entriesOf(dir string, pj *pkgJson) []*entry {
+	ret := make([]*entry, 0)
+	seen := map[string]bool{}
+	add := func(subpath, target string) {
+		if target == "" || seen[subpath] || strings.Contains(subpath, "*") {
+			return
+		}
+		if file := resolveFile(filepath.Join(dir, target)); file != "" {
+			seen[subpath] = true
+			ret = append(ret, &entry{subpath, file})
+		}
+	}
+
+	if len(pj.Exports) > 0 {
+		var exports interface{}
+		if json.Unmarshal(pj.Exports, &exports) == nil {
+			switch v := exports.(type) {
+			case string:
+				add(".", v)
+			case map[string]interface{}:
+				subpaths := false
+				for k := range v {
+					if strings.HasPrefix(k, ".") {
+						subpaths = true
+						break
+					}
+				}
+				if !subpaths {
+					add(".", condTarget(v))
+				} else {
+					for _, k := range sortedKeys(v) {
+						add(k, condTarget(v[k]))
+					}
+				}
+			}
+		}
+	}
+
+	for _, target := range []string{pj.Types, pj.Typings, pj.Source, pj.Module, pj.Main} {
+		add(".", target)
+	}
+	for _, target := range []string{"index", "src/index"} {
+		add(".", target)
+	}
+
+	// the main entry comes first
+	for i, e := range ret {
+		if e.subpath == "." && i > 0 {
+			copy(ret[1:i+1], ret[:i])
+			ret[0] = e
+		}
+	}
+	return ret
+}
+
+// the target of the conditional exports, e.g. `{ "types": "./index.d.ts", "import": "./index.mjs" }`
+func condTarget(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case map[string]interface{}:
+		for _, c := range conditions {
+			if sub, ok := t[c]; ok {
+				if target := condTarget(sub); target != "" {
+					return target
+				}
+			}
+		}
+	case []interface{}:
+		for _, sub := range t {
+			if target := condTarget(sub); target != "" {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+var resolveExts = []string{".ts", ".tsx", ".d.ts", ".mts", ".cts", ".js", ".jsx", ".mjs", ".cjs"}
+
+func isFile(file string) bool {
+	info, err := os.Stat(file)
+	return err == nil && !info.IsDir()
+}
+
+// resolves the module path to the file, the extensions and the `index` files are tried in the
+// same way as the typescript, the declaration file beside the javascript file is preferred
+func resolveFile(base string) string {
+	if isFile(base) {
+		ext := filepath.Ext(base)
+		if ext == ".js" || ext == ".mjs" || ext == ".cjs" {
+			dts := strings.TrimSuffix(base, ext) + ".d" + strings.Replace(ext, "j", "t", 1)
+			if isFile(dts) {
+				return dts
+			}
+		}
+		return base
+	}
+
+	// `./x.js` refers to `./x.ts` in the typescript modules
+	switch ext := filepath.Ext(base); ext {
+	case ".js", ".jsx", ".mjs", ".cjs":
+		trimmed := strings.TrimSuffix(base, ext)
+		for _, e := range []string{".ts", ".tsx", ".d.ts", strings.Replace(ext, "j", "t", 1)} {
+			if isFile(trimmed + e) {
+				return trimmed + e
+			}
+		}
+	}
+
+	for _, e := range resolveExts {
+		if isFile(base + e) {
+			return base + e
+		}
+	}
+	for _, e := range resolveExts {
+		if file := filepath.Join(base, "index"+e); isFile(file) {
+			return file
+		}
+	}
+	return ""
+}
+
+type extractor struct {
+	dir  string
+	opts *Opts
+	pkg  *Package
+	mods map[string]*module
+}
+
+type module struct {
+	file string
+	rel  string
+	p    *parser.Parser
+	prog *parser.Prog
+	r    *renderer
+
+	exports  []*Symbol
+	visiting bool
+}
+
+func (x *extractor) module(file string) (*module, error) {
+	if mod, ok := x.mods[file]; ok {
+		return mod, nil
+	}
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := parser.NewParser(span.NewSource(file, string(b)), x.opts.ParserOpts.OfFile(file))
+	ast, err := p.Prog()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	rel, err := filepath.Rel(x.dir, file)
+	if err != nil {
+		rel = file
+	}
+	mod := &module{file: file, rel: filepath.ToSlash(rel), p: p, prog: ast.(*parser.Prog), r: &renderer{p}}
+	x.mods[file] = mod
+	return mod, nil
+}
+
+func (x *extractor) warn(mod *module, node parser.Node, format string, args ...interface{}) {
+	pos := mod.p.Source().OfstLineCol(node.Range().Lo)
+	x.pkg.Warnings = append(x.pkg.Warnings, fmt.Sprintf("%s:%d: %s", mod.rel, pos.Line, fmt.Sprintf(format, args...)))
+}
+
+// the module the relative source refers to, nil is returned with a warning if it cannot be
+// resolved, the packages are not followed
+func (x *extractor) imported(mod *module, src parser.Node) *module {
+	path := nameOf(src)
+	if !strings.HasPrefix(path, ".") {
+		x.warn(mod, src, "the exports of the package `%s` are not followed", path)
+		return nil
+	}
+	file := resolveFile(filepath.Join(filepath.Dir(mod.file), path))
+	if file == "" {
+		x.warn(mod, src, "cannot resolve `%s`", path)
+		return nil
+	}
+	target, err := x.module(file)
+	if err != nil {
+		x.warn(mod, src, "%v", err)
+		return nil
+	}
+	return target
+}
+
+func nameOf(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.StrLit:
+		return n.Val()
+	}
+	return ""
+}
+
+func findSym(syms []*Symbol, name string) *Symbol {
+	for _, s := range syms {
+		if s.Name == name || name == "default" && s.Default {
+			return s
+		}
+	}
+	return nil
+}
+
+// the exported symbols of the module in the order of their appearance
+func (x *extractor) exportsOf(mod *module) ([]*Symbol, error) {
+	if mod.exports != nil {
+		return mod.exports, nil
+	}
+	if mod.visiting {
+		// the circular re-exports
+		return []*Symbol{}, nil
+	}
+	mod.visiting = true
+	defer func() { mod.visiting = false }()
+
+	syms := make([]*Symbol, 0)
+	for _, stmt := range mod.prog.Body() {
+		exp, ok := stmt.(*parser.ExportDec)
+		if !ok {
+			continue
+		}
+
+		if exp.Src() != nil {
+			target := x.imported(mod, exp.Src())
+			if target == nil {
+				continue
+			}
+			tsyms, err := x.exportsOf(target)
+			if err != nil {
+				return nil, err
+			}
+
+			if exp.All() {
+				if len(exp.Specs()) == 1 {
+					// `export * as ns from "./x"`
+					name := nameOf(exp.Specs()[0].(*parser.ExportSpec).Local())
+					syms = append(syms, x.nsSym(mod, exp, name, tsyms))
+					continue
+				}
+				for _, s := range tsyms {
+					if !s.Default && findSym(syms, s.Name) == nil {
+						syms = append(syms, s)
+					}
+				}
+				continue
+			}
+
+			for _, spec := range exp.Specs() {
+				spec := spec.(*parser.ExportSpec)
+				local, id := nameOf(spec.Local()), nameOf(spec.Id())
+				if s := findSym(tsyms, local); s != nil {
+					s = s.clone(id)
+					s.Default = id == "default"
+					syms = append(syms, s)
+				} else {
+					x.warn(mod, spec, "`%s` is not exported by `%s`", local, nameOf(exp.Src()))
+				}
+			}
+			continue
+		}
+
+		if dec := exp.Dec(); dec != nil {
+			syms = x.decSyms(mod, exp, dec, exp.Default(), syms)
+			continue
+		}
+
+		for _, spec := range exp.Specs() {
+			spec := spec.(*parser.ExportSpec)
+			local, id := nameOf(spec.Local()), nameOf(spec.Id())
+			if s := x.localSym(mod, local); s != nil {
+				s = s.clone(id)
+				s.Default = id == "default"
+				syms = append(syms, s)
+			} else {
+				x.warn(mod, spec, "cannot find the declaration of `%s`", local)
+			}
+		}
+	}
+
+	mod.exports = trimOverloads(syms)
+	return mod.exports, nil
+}
+
+// the symbol of the local binding exported by `export { name }`, the bindings imported from
+// the relative modules are followed
+func (x *extractor) localSym(mod *module, name string) *Symbol {
+	for _, stmt := range mod.prog.Body() {
+		if imp, ok := stmt.(*parser.ImportDec); ok {
+			for _, spec := range imp.Specs() {
+				spec := spec.(*parser.ImportSpec)
+				if nameOf(spec.Local()) != name {
+					continue
+				}
+				target := x.imported(mod, imp.Src())
+				if target == nil {
+					return nil
+				}
+				tsyms, err := x.exportsOf(target)
+				if err != nil {
+					return nil
+				}
+				switch {
+				case spec.Default():
+					return findSym(tsyms, "default")
+				case spec.NameSpace():
+					return x.nsSym(mod, imp, name, tsyms)
+				}
+				return findSym(tsyms, nameOf(spec.Id()))
+			}
+			continue
+		}
+
+		dec := stmt
+		if exp, ok := stmt.(*parser.ExportDec); ok && exp.Dec() != nil {
+			dec = exp.Dec()
+		}
+		for _, s := range x.decSyms(mod, stmt, dec, false, nil) {
+			if s.Name == name {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+func (x *extractor) nsSym(mod *module, node parser.Node, name string, members []*Symbol) *Symbol {
+	s := x.sym(mod, node, name, SYM_NAMESPACE, "namespace "+name)
+	s.Members = members
+	return s
+}
+
+func (x *extractor) sym(mod *module, node parser.Node, name string, kind SymKind, sig string) *Symbol {
+	return &Symbol{
+		Name:      name,
+		Kind:      kind,
+		Signature: sig,
+		Doc:       docOf(jsdoc.Of(mod.p, node)),
+		File:      mod.rel,
+		Line:      int(mod.p.Source().OfstLineCol(node.Range().Lo).Line),
+	}
+}
+
+// the symbols declared by the declaration, `stmt` is the statement contains the declaration
+// whose leading comment is the doc of the symbols, the overloads of the functions are merged
+// into the last symbol of the same name in `syms`
+func (x *extractor) decSyms(mod *module, stmt, dec parser.Node, def bool, syms []*Symbol) []*Symbol {
+	r := mod.r
+	declare := ""
+	if td, ok := dec.(*parser.TsDec); ok {
+		if td.Inner() == nil {
+			return syms
+		}
+		declare = "declare "
+		dec = td.Inner()
+	}
+
+	name := func(id parser.Node) string {
+		if id == nil || def {
+			return "default"
+		}
+		return nameOf(id)
+	}
+
+	add := func(s *Symbol) {
+		s.Default = def
+		syms = append(syms, s)
+	}
+
+	switch n := dec.(type) {
+	case *parser.FnDec:
+		fname := nameOf(n.Id())
+		sig := declare + r.fn("function", fname, n)
+		if len(syms) > 0 {
+			last := syms[len(syms)-1]
+			if last.Kind == SYM_FUNCTION && last.Name == name(n.Id()) && last.Overloads != nil {
+				// the implementation of the overloads is not the part of the API
+				if n.Body() == nil {
+					last.Overloads = append(last.Overloads, sig)
+				}
+				return syms
+			}
+		}
+		s := x.sym(mod, stmt, name(n.Id()), SYM_FUNCTION, sig)
+		if n.Body() == nil && declare == "" {
+			s.Overloads = []string{sig}
+		}
+		add(s)
+	case *parser.ArrowFn:
+		s := x.sym(mod, stmt, "default", SYM_FUNCTION, r.arrow(n))
+		add(s)
+	case *parser.ClassDec:
+		s := x.sym(mod, stmt, name(n.Id()), SYM_CLASS, declare+r.class(n))
+		s.Members = x.classMembers(mod, n)
+		add(s)
+	case *parser.TsInterface:
+		sig := declare + "interface " + nameOf(n.Id()) + r.typ(n.TypParams())
+		if supers := n.Supers(); len(supers) > 0 {
+			sig += " extends " + r.join(supers, ", ", r.typ)
+		}
+		s := x.sym(mod, stmt, name(n.Id()), SYM_INTERFACE, sig)
+		s.Members = x.itfMembers(mod, n.Body().(*parser.TsInterfaceBody).Body())
+		add(s)
+	case *parser.TsTypDec:
+		sig := declare + "type " + nameOf(n.Id()) + r.typ(n.TypParams()) + " = " + r.typ(n.TypInfo().TypAnnot())
+		add(x.sym(mod, stmt, name(n.Id()), SYM_TYPE, sig))
+	case *parser.TsEnum:
+		sig := declare + "enum " + nameOf(n.Id())
+		if n.Const() {
+			sig = declare + "const enum " + nameOf(n.Id())
+		}
+		s := x.sym(mod, stmt, name(n.Id()), SYM_ENUM, sig)
+		s.Members = make([]*Symbol, 0, len(n.Members()))
+		for _, m := range n.Members() {
+			m := m.(*parser.TsEnumMember)
+			msig := nameOf(m.Key())
+			if msig == "" {
+				msig = r.text(m.Key())
+			}
+			if m.Val() != nil {
+				msig += " = " + r.text(m.Val())
+			}
+			s.Members = append(s.Members, x.sym(mod, m, nameOf(m.Key()), SYM_ENUM_MEMBER, msig))
+		}
+		add(s)
+	case *parser.TsNS:
+		s := x.sym(mod, stmt, name(n.Id()), SYM_NAMESPACE, declare+"namespace "+r.text(n.Id()))
+		s.Members = make([]*Symbol, 0)
+		if body, ok := n.Body().(*parser.BlockStmt); ok {
+			for _, stmt := range body.Body() {
+				if exp, ok := stmt.(*parser.ExportDec); ok && exp.Dec() != nil {
+					s.Members = x.decSyms(mod, exp, exp.Dec(), false, s.Members)
+				}
+			}
+		}
+		trimOverloads(s.Members)
+		add(s)
+	case *parser.VarDecStmt:
+		kind := SYM_VARIABLE
+		if n.Kind() == "const" {
+			kind = SYM_CONST
+		}
+		for _, vd := range n.DecList() {
+			vd := vd.(*parser.VarDec)
+			vname := nameOf(vd.Id())
+			if vname == "" {
+				// the destructuring patterns are rendered as a whole
+				vname = r.binding(vd.Id())
+			}
+			add(x.sym(mod, stmt, vname, kind, declare+n.Kind()+" "+r.varDec(vd)))
+		}
+	default:
+		if def {
+			// `export default expr`
+			add(x.sym(mod, stmt, "default", SYM_VARIABLE, "export default "+r.text(dec)))
+		}
+	}
+	return syms
+}
+
+func isPrivate(key parser.Node, ti *parser.TypInfo) bool {
+	if id, ok := key.(*parser.Ident); ok && id.IsPrivate() {
+		return true
+	}
+	return ti != nil && ti.AccMod() == parser.ACC_MOD_PRI
+}
+
+// the public and protected members of the class, the overloads of the methods are merged
+func (x *extractor) classMembers(mod *module, n *parser.ClassDec) []*Symbol {
+	r := mod.r
+	ret := make([]*Symbol, 0)
+	for _, elem := range n.Body().(*parser.ClassBody).Elems() {
+		switch m := elem.(type) {
+		case *parser.Method:
+			ti := m.TypInfo()
+			if isPrivate(m.Key(), ti) {
+				continue
+			}
+			fn := m.Val().(*parser.FnDec)
+			key := r.propKey(m.Key(), m.Computed())
+			kind := SYM_METHOD
+			sig := r.fn("", key, fn)
+			switch m.PropKind() {
+			case parser.PK_CTOR:
+				kind = SYM_CONSTRUCTOR
+				sig = "constructor" + sig[len(key):]
+			case parser.PK_GETTER:
+				kind = SYM_GETTER
+				sig = "get " + sig
+			case parser.PK_SETTER:
+				kind = SYM_SETTER
+				sig = "set " + sig
+			}
+			sig = modifiers(ti, m.Static()) + sig
+
+			if len(ret) > 0 {
+				last := ret[len(ret)-1]
+				if last.Kind == kind && last.Name == key && last.Overloads != nil {
+					if !fn.IsSig() && fn.Body() != nil {
+						continue
+					}
+					last.Overloads = append(last.Overloads, sig)
+					continue
+				}
+			}
+			s := x.sym(mod, m, key, kind, sig)
+			if fn.Body() == nil && (ti == nil || !ti.Abstract()) {
+				s.Overloads = []string{sig}
+			}
+			ret = append(ret, s)
+		case *parser.Field:
+			ti := m.TypInfo()
+			if isPrivate(m.Key(), ti) {
+				continue
+			}
+			if m.IsTsSig() {
+				ret = append(ret, x.sym(mod, m, r.text(m.Key()), SYM_INDEX, r.text(m)))
+				continue
+			}
+			key := r.propKey(m.Key(), m.Computed())
+			sig := key
+			kti := parser.TypInfoOf(m.Key())
+			if ti != nil && ti.Optional() || kti != nil && kti.Optional() {
+				sig += "?"
+			}
+			annot := r.annotOf(ti)
+			if annot == "" {
+				annot = r.annotOf(kti)
+			}
+			sig = modifiers(ti, m.Static()) + sig + annot
+			ret = append(ret, x.sym(mod, m, key, SYM_PROPERTY, sig))
+		}
+	}
+	return trimOverloads(ret)
+}
+
+// the single signature is not regarded as the overloads
+func trimOverloads(syms []*Symbol) []*Symbol {
+	for _, s := range syms {
+		if len(s.Overloads) == 1 {
+			s.Overloads = nil
+		}
+	}
+	return syms
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func modifiers(ti *parser.TypInfo, static bool) string {
+	s := ""
+	if ti != nil && ti.AccMod() == parser.ACC_MOD_PRO {
+		s += "protected "
+	}
+	if static {
+		s += "static "
+	}
+	if ti != nil && ti.Abstract() {
+		s += "abstract "
+	}
+	if ti != nil && ti.Readonly() {
+		s += "readonly "
+	}
+	return s
+}
+
+func (x *extractor) itfMembers(mod *module, body []parser.Node) []*Symbol {
+	r := mod.r
+	ret := make([]*Symbol, 0, len(body))
+	for _, m := range body {
+		name, kind := "", SYM_PROPERTY
+		switch n := m.(type) {
+		case *parser.TsProp:
+			name = r.propKey(n.Key(), n.Computed())
+			if n.IsMethod() {
+				kind = SYM_METHOD
+				switch n.Kind() {
+				case parser.PK_GETTER:
+					kind = SYM_GETTER
+				case parser.PK_SETTER:
+					kind = SYM_SETTER
+				}
+			}
+		case *parser.TsCallSig:
+			kind = SYM_CALL
+		case *parser.TsNewSig:
+			kind = SYM_CONSTRUCT
+		case *parser.TsIdxSig:
+			kind = SYM_INDEX
+		}
+		ret = append(ret, x.sym(mod, m, name, kind, r.member(m)))
+	}
+	return ret
+}
+
+func docOf(d *jsdoc.JSDoc) *Doc {
+	if d == nil {
+		return nil
+	}
+	ret := &Doc{Description: d.Desc}
+	for _, tag := range d.Tags {
+		switch t := tag.(type) {
+		case *jsdoc.ParamTag:
+			if t.Kind() == jsdoc.TAG_PARAM {
+				ret.Params = append(ret.Params, &ParamDoc{t.Name, typText(t.Typ), t.Optional, t.Default, t.Desc})
+				continue
+			}
+			ret.Tags = append(ret.Tags, &TagDoc{t.TagName(), strings.TrimSpace(typText(t.Typ) + " " + t.Name + " " + t.Desc)})
+		case *jsdoc.TypTag:
+			if t.Kind() == jsdoc.TAG_RETURNS {
+				ret.Returns = &ReturnsDoc{typText(t.Typ), t.Desc}
+				continue
+			}
+			ret.Tags = append(ret.Tags, &TagDoc{t.TagName(), strings.TrimSpace(typText(t.Typ) + " " + t.Desc)})
+		case *jsdoc.TextTag:
+			switch t.Kind() {
+			case jsdoc.TAG_DEPRECATED:
+				ret.Deprecated = true
+				ret.Deprecation = t.Text
+			case jsdoc.TAG_EXAMPLE:
+				ret.Examples = append(ret.Examples, t.Text)
+			default:
+				ret.Tags = append(ret.Tags, &TagDoc{t.TagName(), t.Text})
+			}
+		case *jsdoc.TypedefTag:
+			ret.Tags = append(ret.Tags, &TagDoc{t.TagName(), strings.TrimSpace(typText(t.Typ) + " " + t.Name + " " + t.Desc)})
+		case *jsdoc.TemplateTag:
+			ret.Tags = append(ret.Tags, &TagDoc{t.TagName(), strings.TrimSpace(strings.Join(t.Names, ", ") + " " + t.Desc)})
+		}
+	}
+	return ret
+}
+
+func typText(t *jsdoc.Type) string {
+	if t == nil {
+		return ""
+	}
+	return strings.TrimSpace(t.Raw)
+}
@@ -0,0 +1,189 @@
+package doc
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func writePkg(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, code := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func extract(t *testing.T, files map[string]string) *Package {
+	pkg, err := Extract(writePkg(t, files), nil)
+	AssertEqual(t, nil, err, "should be ok")
+	return pkg
+}
+
+func TestEntries(t *testing.T) {
+	pkg := extract(t, map[string]string{
+		"package.json": `{
+  "name": "demo",
+  "version": "1.0.0",
+  "exports": {
+    "./utils": { "types": "./lib/utils.d.ts", "import": "./lib/utils.mjs" },
+    ".": "./lib/index.js",
+    "./*": "./lib/*.js"
+  }
+}`,
+		"lib/index.js":   "export const a = 1",
+		"lib/utils.mjs":  "export const b = 1",
+		"lib/utils.d.ts": "export declare const b: number",
+	})
+	AssertEqual(t, "demo", pkg.Name, "should be ok")
+	AssertEqual(t, 2, len(pkg.Modules), "should be ok")
+	AssertEqual(t, ".", pkg.Modules[0].Entry, "should be ok")
+	AssertEqual(t, "lib/index.js", pkg.Modules[0].File, "should be ok")
+	AssertEqual(t, "./utils", pkg.Modules[1].Entry, "should be ok")
+	AssertEqual(t, "lib/utils.d.ts", pkg.Modules[1].File, "should be ok")
+	AssertEqual(t, "declare const b: number", pkg.Modules[1].Symbols[0].Signature, "should be ok")
+}
+
+func TestSignatures(t *testing.T) {
+	pkg := extract(t, map[string]string{
+		"package.json": `{ "name": "demo", "types": "./src/index.ts" }`,
+		"src/index.ts": `
+/**
+ * Adds two numbers.
+ * @param {number} a - the first
+ * @param [b=1] the second
+ * @returns the sum
+ */
+export function add(a: number, b = 1): number { return a + b }
+
+/** Overloaded. */
+export function pick(a: string): string;
+export function pick(a: number): number;
+export function pick(a: any) { return a }
+
+function hidden() {}
+
+export default abstract class Box<T> extends Base<T> implements Sized {
+  /** the size */
+  readonly size: number = 0
+  private secret = 1
+  #hidden = 2
+  constructor(public name: string) { super() }
+  get area(): number { return 1 }
+  async *items(fn: (v: T) => void, ...rest: Array<string | number>): AsyncGenerator<T> {}
+}
+
+export interface Shape<T> extends Sized {
+  kind: "circle" | "square"
+  area(x: number): number
+  [k: string]: unknown
+}
+
+export type Pair<K extends string, V = K> = { key: K; val?: V[] }
+
+export enum Color { Red = 1, Green }
+
+export const version = "1.0", handler = async (e: Event): Promise<void> => {}
+`,
+	})
+	syms := pkg.Modules[0].Symbols
+
+	add := syms[0]
+	AssertEqual(t, SYM_FUNCTION, add.Kind, "should be ok")
+	AssertEqual(t, "function add(a: number, b?): number", add.Signature, "should be ok")
+	AssertEqual(t, "Adds two numbers.", add.Doc.Description, "should be ok")
+	AssertEqual(t, "number", add.Doc.Params[0].Type, "should be ok")
+	AssertEqual(t, "1", add.Doc.Params[1].Default, "should be ok")
+	AssertEqual(t, "the sum", add.Doc.Returns.Description, "should be ok")
+	AssertEqual(t, 8, add.Line, "should be ok")
+
+	pick := syms[1]
+	AssertEqual(t, 2, len(pick.Overloads), "should be ok")
+	AssertEqual(t, "function pick(a: number): number", pick.Overloads[1], "should be ok")
+	AssertEqual(t, "Overloaded.", pick.Doc.Description, "should be ok")
+
+	box := syms[2]
+	AssertEqual(t, "default", box.Name, "should be ok")
+	AssertEqual(t, true, box.Default, "should be ok")
+	AssertEqual(t, "abstract class Box<T> extends Base<T> implements Sized", box.Signature, "should be ok")
+	members := make([]string, len(box.Members))
+	for i, m := range box.Members {
+		members[i] = m.Signature
+	}
+	AssertEqual(t, strings.Join([]string{
+		"readonly size: number",
+		"constructor(public name: string)",
+		"get area(): number",
+		"async *items(fn: (v: T) => void, ...rest: Array<string | number>): AsyncGenerator<T>",
+	}, "\n"), strings.Join(members, "\n"), "should be ok")
+	AssertEqual(t, "the size", box.Members[0].Doc.Description, "should be ok")
+
+	shape := syms[3]
+	AssertEqual(t, "interface Shape<T> extends Sized", shape.Signature, "should be ok")
+	AssertEqual(t, `kind: "circle" | "square"`, shape.Members[0].Signature, "should be ok")
+	AssertEqual(t, SYM_INDEX, shape.Members[2].Kind, "should be ok")
+
+	AssertEqual(t, "type Pair<K extends string, V = K> = { key: K; val?: V[] }", syms[4].Signature, "should be ok")
+	AssertEqual(t, "enum Color", syms[5].Signature, "should be ok")
+	AssertEqual(t, "Green", syms[5].Members[1].Name, "should be ok")
+	AssertEqual(t, `const version = "1.0"`, syms[6].Signature, "should be ok")
+	AssertEqual(t, "const handler = async (e: Event): Promise<void> => ...", syms[7].Signature, "should be ok")
+	AssertEqual(t, 8, len(syms), "should be ok")
+}
+
+func TestReexports(t *testing.T) {
+	pkg := extract(t, map[string]string{
+		"package.json": `{ "main": "index.js" }`,
+		"index.js": `
+import { b as c } from "./b"
+export * from "./a"
+export * as ns from "./a"
+export { c, c as d }
+export { default as e } from "./b"
+export * from "pkg"
+`,
+		"a.js": "/** a */\nexport function a() {}",
+		"b.js": "export const b = 1\nexport default function () {}",
+	})
+	syms := pkg.Modules[0].Symbols
+	names := make([]string, len(syms))
+	for i, s := range syms {
+		names[i] = s.Name
+	}
+	AssertEqual(t, "a,ns,c,d,e", strings.Join(names, ","), "should be ok")
+	AssertEqual(t, "a", syms[0].Doc.Description, "should be ok")
+	AssertEqual(t, "a.js", syms[0].File, "should be ok")
+	AssertEqual(t, SYM_NAMESPACE, syms[1].Kind, "should be ok")
+	AssertEqual(t, "a", syms[1].Members[0].Name, "should be ok")
+	AssertEqual(t, SYM_CONST, syms[3].Kind, "should be ok")
+	AssertEqual(t, SYM_FUNCTION, syms[4].Kind, "should be ok")
+	AssertEqual(t, 1, len(pkg.Warnings), "should be ok")
+	AssertEqual(t, "index.js:7: the exports of the package `pkg` are not followed", pkg.Warnings[0], "should be ok")
+}
+
+func TestMarkdown(t *testing.T) {
+	pkg := extract(t, map[string]string{
+		"package.json": `{ "name": "demo", "exports": { ".": "./index.js", "./utils/fs": "./fs.js" } }`,
+		"index.js":     "/**\n * @deprecated use b\n */\nexport function a(x) {}",
+		"fs.js":        "export class B {}",
+	})
+	files := Markdown(pkg)
+	AssertEqual(t, 3, len(files), "should be ok")
+
+	index := files["index.md"]
+	AssertEqual(t, true, strings.Contains(index, "### [demo/utils/fs](modules/utils-fs.md)"), "should be ok")
+	AssertEqual(t, true, strings.Contains(index, "- [a](modules/index.md#function-a) function"), "should be ok")
+
+	page := files["modules/index.md"]
+	AssertEqual(t, "# demo\n\nSource: `index.js`\n\n## function a\n\n> **Deprecated** use b\n\n```ts\nfunction a(x)\n```\n", page, "should be ok")
+	AssertEqual(t, true, strings.Contains(files["modules/utils-fs.md"], "## class B"), "should be ok")
+}
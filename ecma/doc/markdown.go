@@ -0,0 +1,164 @@
+package doc
+
+import (
+	"fmt"
+	"strings"
+)
+
+// the file name of the module page relative to the output directory, the main entry is named
+// `index` and the other entries are named by their subpaths, e.g. `./utils/fs` to `utils-fs`
+func (m *Module) PageName() string {
+	name := strings.Trim(strings.TrimPrefix(m.Entry, "."), "/")
+	if name == "" {
+		name = "index"
+	}
+	name = strings.NewReplacer("/", "-", "\\", "-", ".", "-").Replace(name)
+	return "modules/" + name + ".md"
+}
+
+// renders the skeleton of the documentation site in Markdown, the keys of the result are the
+// file paths relative to the output directory, `index.md` is the entry of the site which links
+// to the pages of the modules
+func Markdown(pkg *Package) map[string]string {
+	ret := map[string]string{}
+
+	var b strings.Builder
+	title := pkg.Name
+	if title == "" {
+		title = "API Reference"
+	}
+	fmt.Fprintf(&b, "# %s\n\n", title)
+	if pkg.Version != "" {
+		fmt.Fprintf(&b, "Version: %s\n\n", pkg.Version)
+	}
+	if pkg.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", pkg.Description)
+	}
+	b.WriteString("## Modules\n")
+	for _, m := range pkg.Modules {
+		fmt.Fprintf(&b, "\n### [%s](%s)\n\n", moduleTitle(pkg, m), m.PageName())
+		if len(m.Symbols) == 0 {
+			b.WriteString("No exports.\n")
+		}
+		for _, s := range m.Symbols {
+			fmt.Fprintf(&b, "- [%s](%s#%s) %s\n", s.Name, m.PageName(), anchor(s), s.Kind)
+		}
+		ret[m.PageName()] = modulePage(pkg, m)
+	}
+	ret["index.md"] = b.String()
+	return ret
+}
+
+func moduleTitle(pkg *Package, m *Module) string {
+	if pkg.Name == "" || !strings.HasPrefix(m.Entry, ".") {
+		return m.Entry
+	}
+	return pkg.Name + strings.TrimPrefix(m.Entry, ".")
+}
+
+// the anchor of the heading of the symbol, the same as the one generated by GitHub
+func anchor(s *Symbol) string {
+	var b strings.Builder
+	for _, c := range strings.ToLower(headingOf(s)) {
+		switch {
+		case c == ' ':
+			b.WriteRune('-')
+		case c == '-' || c == '_' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9':
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+func headingOf(s *Symbol) string {
+	return fmt.Sprintf("%s %s", s.Kind, s.Name)
+}
+
+func modulePage(pkg *Package, m *Module) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", moduleTitle(pkg, m))
+	fmt.Fprintf(&b, "Source: `%s`\n\n", m.File)
+	for _, s := range m.Symbols {
+		writeSymbol(&b, s, 2)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func writeSymbol(b *strings.Builder, s *Symbol, level int) {
+	fmt.Fprintf(b, "%s %s\n\n", strings.Repeat("#", level), headingOf(s))
+	if s.Doc != nil && s.Doc.Deprecated {
+		b.WriteString("> **Deprecated**")
+		if s.Doc.Deprecation != "" {
+			b.WriteString(" " + oneLine(s.Doc.Deprecation))
+		}
+		b.WriteString("\n\n")
+	}
+
+	b.WriteString("```ts\n")
+	if len(s.Overloads) > 0 {
+		b.WriteString(strings.Join(s.Overloads, "\n"))
+	} else {
+		b.WriteString(s.Signature)
+	}
+	b.WriteString("\n```\n\n")
+
+	if d := s.Doc; d != nil {
+		if d.Description != "" {
+			b.WriteString(d.Description + "\n\n")
+		}
+		if len(d.Params) > 0 {
+			b.WriteString("| Parameter | Type | Description |\n| --- | --- | --- |\n")
+			for _, p := range d.Params {
+				name := p.Name
+				if p.Optional {
+					name += "?"
+				}
+				desc := oneLine(p.Description)
+				if p.Default != "" {
+					desc = strings.TrimSpace(desc + " Defaults to `" + p.Default + "`.")
+				}
+				fmt.Fprintf(b, "| `%s` | %s | %s |\n", name, code(p.Type), cell(desc))
+			}
+			b.WriteString("\n")
+		}
+		if d.Returns != nil {
+			b.WriteString("Returns")
+			if d.Returns.Type != "" {
+				b.WriteString(" " + code(d.Returns.Type))
+			}
+			if d.Returns.Description != "" {
+				b.WriteString(" " + oneLine(d.Returns.Description))
+			}
+			b.WriteString("\n\n")
+		}
+		for _, ex := range d.Examples {
+			b.WriteString("```js\n" + ex + "\n```\n\n")
+		}
+		for _, t := range d.Tags {
+			fmt.Fprintf(b, "- **@%s** %s\n", t.Tag, oneLine(t.Text))
+		}
+		if len(d.Tags) > 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	for _, m := range s.Members {
+		writeSymbol(b, m, level+1)
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func code(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "`" + cell(s) + "`"
+}
+
+// escapes the text in the table cells
+func cell(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
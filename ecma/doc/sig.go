@@ -0,0 +1,362 @@
+package doc
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// renders the signatures of the declarations from their AST, the bodies and the initial values
+// are omitted and the types are rendered in the canonical form regardless of how they are
+// written in the source, for example `Array < string >` is rendered as `Array<string>`
+type renderer struct {
+	p *parser.Parser
+}
+
+// the source text of the node with the whitespaces collapsed, it's used for the nodes which
+// are rendered as they are such as the literals and the unsupported constructs
+func (r *renderer) text(node parser.Node) string {
+	return strings.Join(strings.Fields(r.p.RngText(node.Range())), " ")
+}
+
+func (r *renderer) join(nodes []parser.Node, sep string, fn func(parser.Node) string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = fn(node)
+	}
+	return strings.Join(parts, sep)
+}
+
+type parenNode interface {
+	OuterParen() span.Range
+}
+
+// renders the type, the parentheses around the type in the source are kept since they are
+// required by the precedence in most cases, e.g. `(A | B)[]`
+func (r *renderer) typ(node parser.Node) string {
+	if node == nil {
+		return ""
+	}
+	s := r.typInner(node)
+	if pn, ok := node.(parenNode); ok && node.Type() != parser.N_TS_PAREN {
+		// the parentheses of the params of the function types are not the outer ones
+		if opa := pn.OuterParen(); !opa.Empty() && opa.Lo < node.Range().Lo {
+			return "(" + s + ")"
+		}
+	}
+	return s
+}
+
+func (r *renderer) typInner(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.TsTypAnnot:
+		return r.typ(n.TsTyp())
+	case *parser.Ident:
+		return n.Val()
+	case *parser.TsRef:
+		return r.typ(n.Name()) + r.typ(n.ParamsInst())
+	case *parser.TsNsName:
+		return r.typ(n.Lhs()) + "." + r.typ(n.Rhs())
+	case *parser.TsParamsInst:
+		return "<" + r.join(n.Params(), ", ", r.typ) + ">"
+	case *parser.TsParamsDec:
+		return "<" + r.join(n.Params(), ", ", r.typ) + ">"
+	case *parser.TsParam:
+		s := r.typ(n.Name())
+		if n.In() && n.Out() {
+			s = "in out " + s
+		} else if n.In() {
+			s = "in " + s
+		} else if n.Out() {
+			s = "out " + s
+		}
+		if n.Const() {
+			s = "const " + s
+		}
+		if n.Cons() != nil {
+			s += " extends " + r.typ(n.Cons())
+		}
+		if n.Default() != nil {
+			s += " = " + r.typ(n.Default())
+		}
+		return s
+	case *parser.TsTypQuery:
+		return "typeof " + r.typ(n.Arg())
+	case *parser.TsParen:
+		return "(" + r.typInner(n.Arg()) + ")"
+	case *parser.TsArr:
+		return r.typ(n.Arg()) + "[]"
+	case *parser.TsIdxAccess:
+		return r.typ(n.Obj()) + "[" + r.typ(n.Idx()) + "]"
+	case *parser.TsTuple:
+		return "[" + r.join(n.Args(), ", ", r.typ) + "]"
+	case *parser.TsRest:
+		return "..." + r.typ(n.Arg())
+	case *parser.TsOpt:
+		return r.typ(n.Arg()) + "?"
+	case *parser.TsTupleNamedMember:
+		s := r.typ(n.Label())
+		if n.Opt() {
+			s += "?"
+		}
+		return s + ": " + r.typ(n.Val())
+	case *parser.TsObj:
+		if len(n.Props()) == 0 {
+			return "{}"
+		}
+		return "{ " + r.join(n.Props(), "; ", r.member) + " }"
+	case *parser.TsFnTyp:
+		return r.typ(n.TypParams()) + "(" + r.params(n.Params()) + ") => " + r.typ(n.RetTyp())
+	case *parser.TsNewSig:
+		s := ""
+		if n.Abstract() {
+			s = "abstract "
+		}
+		if n.Type() == parser.N_TS_NEW {
+			return s + "new " + r.typ(n.TypParams()) + "(" + r.params(n.Params()) + ") => " + r.typ(n.RetTyp())
+		}
+		return r.member(n)
+	case *parser.TsUnionTyp:
+		return r.join(n.Elems(), " | ", r.typ)
+	case *parser.TsIntersectTyp:
+		return r.join(n.Elems(), " & ", r.typ)
+	case *parser.TsTypPredicate:
+		s := r.typ(n.Name())
+		if n.Asserts() {
+			s = "asserts " + s
+		}
+		if n.Typ() != nil {
+			s += " is " + r.typ(n.Typ())
+		}
+		return s
+	case *parser.TsImportType:
+		s := "import(" + r.text(n.Arg()) + ")"
+		if n.Qualifier() != nil {
+			s += "." + r.typ(n.Qualifier())
+		}
+		return s + r.typ(n.TypArg())
+	case *parser.TsCondType:
+		return r.typ(n.CheckTyp()) + " extends " + r.typ(n.ExtTyp()) + " ? " + r.typ(n.TrueTyp()) + " : " + r.typ(n.FalseTyp())
+	case *parser.TsTypInfer:
+		return "infer " + r.typ(n.Arg())
+	case *parser.TsTypOp:
+		return n.Op() + " " + r.typ(n.Arg())
+	case *parser.TsMapped:
+		s := "{ " + [...]string{"", "readonly ", "+readonly ", "-readonly "}[n.Readonly()]
+		key := n.Key().(*parser.TsParam)
+		s += "[" + r.typ(key.Name()) + " in " + r.typ(key.Cons())
+		if n.Name() != nil {
+			s += " as " + r.typ(n.Name())
+		}
+		s += "]" + [...]string{"", "?", "+?", "-?"}[n.Optional()]
+		if n.Val() != nil {
+			s += ": " + r.typ(n.Val())
+		}
+		return s + " }"
+	}
+	return r.text(node)
+}
+
+// renders the members of the object types and the interfaces
+func (r *renderer) member(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.TsProp:
+		key := r.propKey(n.Key(), n.Computed())
+		if n.Optional() {
+			key += "?"
+		}
+		if n.IsMethod() {
+			sig := n.Method()
+			switch n.Kind() {
+			case parser.PK_GETTER:
+				key = "get " + key
+			case parser.PK_SETTER:
+				key = "set " + key
+			}
+			return key + r.typ(sig.TypParams()) + "(" + r.params(sig.Params()) + ")" + r.annot(sig.RetTyp())
+		}
+		if n.Readonly() {
+			key = "readonly " + key
+		}
+		return key + r.annot(n.Val())
+	case *parser.TsCallSig:
+		return r.typ(n.TypParams()) + "(" + r.params(n.Params()) + ")" + r.annot(n.RetTyp())
+	case *parser.TsNewSig:
+		return "new " + r.typ(n.TypParams()) + "(" + r.params(n.Params()) + ")" + r.annot(n.RetTyp())
+	case *parser.TsIdxSig:
+		s := "[" + r.typ(n.Key()) + ": " + r.typ(n.KeyType()) + "]"
+		if n.Optional() {
+			s += "?"
+		}
+		return s + r.annot(n.Val())
+	}
+	return r.text(node)
+}
+
+func (r *renderer) propKey(key parser.Node, computed bool) string {
+	var s string
+	switch key.Type() {
+	case parser.N_NAME:
+		s = key.(*parser.Ident).Val()
+	default:
+		s = r.text(key)
+	}
+	if computed {
+		return "[" + s + "]"
+	}
+	return s
+}
+
+// the type annotation in the form of `: Type`, it's empty if the type is absent
+func (r *renderer) annot(typ parser.Node) string {
+	if typ == nil {
+		return ""
+	}
+	if ta, ok := typ.(*parser.TsTypAnnot); ok && ta.TsTyp() == nil {
+		return ""
+	}
+	return ": " + r.typ(typ)
+}
+
+func (r *renderer) annotOf(ti *parser.TypInfo) string {
+	if ti == nil || ti.TypAnnot() == nil {
+		return ""
+	}
+	return r.annot(ti.TypAnnot())
+}
+
+func (r *renderer) params(params []parser.Node) string {
+	return r.join(params, ", ", r.param)
+}
+
+// renders the param of the functions, the default value is omitted and the param becomes
+// optional instead, the same as the declaration files
+func (r *renderer) param(node parser.Node) string {
+	ti := parser.TypInfoOf(node)
+	prefix := ""
+	if ti != nil {
+		if acc := ti.AccMod(); acc != parser.ACC_MOD_NONE {
+			prefix += acc.String() + " "
+		}
+		if ti.Readonly() {
+			prefix += "readonly "
+		}
+	}
+
+	switch n := node.(type) {
+	case *parser.Ident:
+		s := prefix + n.Val()
+		if ti != nil && ti.Optional() {
+			s += "?"
+		}
+		return s + r.annotOf(ti)
+	case *parser.AssignPat:
+		lhs := n.Lhs()
+		lti := parser.TypInfoOf(lhs)
+		s := prefix + r.binding(lhs) + "?"
+		if annot := r.annotOf(lti); annot != "" {
+			return s + annot
+		}
+		return s + r.annotOf(ti)
+	case *parser.RestPat:
+		annot := r.annotOf(ti)
+		if annot == "" {
+			annot = r.annotOf(parser.TypInfoOf(n.Arg()))
+		}
+		return prefix + "..." + r.binding(n.Arg()) + annot
+	case *parser.ObjPat, *parser.ArrPat:
+		return prefix + r.binding(node) + r.annotOf(ti)
+	}
+	return prefix + r.text(node)
+}
+
+// the binding name or the pattern without the type annotation
+func (r *renderer) binding(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.ObjPat, *parser.ArrPat:
+		s := r.text(n)
+		if ti := parser.TypInfoOf(n); ti != nil && ti.TypAnnot() != nil && ti.TypAnnot().Range().Lo < n.Range().Hi {
+			s = strings.Join(strings.Fields(r.p.RngText(span.Range{Lo: n.Range().Lo, Hi: ti.TypAnnot().Range().Lo})), " ")
+		}
+		return s
+	}
+	return r.text(node)
+}
+
+// the signature of the function, `kw` is `function` for the function declarations and it's
+// empty for the methods
+func (r *renderer) fn(kw, name string, n *parser.FnDec) string {
+	s := kw
+	if n.Generator() {
+		s += "*"
+	}
+	if kw != "" && name != "" {
+		s += " "
+	}
+	s += name
+	if n.Async() {
+		s = "async " + s
+	}
+	ti := n.TypInfo()
+	if ti != nil {
+		s += r.typ(ti.TypParams())
+	}
+	return s + "(" + r.params(n.Params()) + ")" + r.annotOf(ti)
+}
+
+func (r *renderer) arrow(n *parser.ArrowFn) string {
+	s := "("
+	if n.Async() {
+		s = "async ("
+	}
+	ti := n.TypInfo()
+	if ti != nil {
+		s = r.typ(ti.TypParams()) + s
+	}
+	return s + r.params(n.Params()) + ")" + r.annotOf(ti) + " => ..."
+}
+
+func (r *renderer) class(n *parser.ClassDec) string {
+	s := "class"
+	if n.Abstract() {
+		s = "abstract class"
+	}
+	if n.Id() != nil {
+		s += " " + nameOf(n.Id())
+	}
+	s += r.typ(n.TypParams())
+	if n.Super() != nil {
+		s += " extends " + r.text(n.Super()) + r.typ(n.SuperTypArgs())
+	}
+	if impls := n.Implements(); len(impls) > 0 {
+		s += " implements " + r.join(impls, ", ", r.typ)
+	}
+	return s
+}
+
+// the variable declarator without the `const`, `let` or `var`, the initial value is kept only
+// if it's a literal or a function whose signature can be rendered
+func (r *renderer) varDec(n *parser.VarDec) string {
+	id := n.Id()
+	ti := parser.TypInfoOf(id)
+	s := r.binding(id)
+	if ti != nil && ti.Definite() {
+		s += "!"
+	}
+	if annot := r.annotOf(ti); annot != "" {
+		return s + annot
+	}
+
+	switch init := n.Init().(type) {
+	case *parser.NumLit, *parser.StrLit, *parser.BoolLit, *parser.NullLit:
+		return s + " = " + r.text(init)
+	case *parser.ArrowFn:
+		return s + " = " + r.arrow(init)
+	case *parser.FnDec:
+		return s + " = " + r.fn("function", nameOf(init.Id()), init)
+	}
+	return s
+}
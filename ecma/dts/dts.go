@@ -0,0 +1,378 @@
+// Package dts emits the TypeScript declaration files from the sources whose exports carry the
+// explicit type annotations, in the same manner as the `isolatedDeclarations` of tsc, the types
+// are never inferred across the declarations, only the trivial ones such as the types of the
+// literals are inferred and the others which require the type checker are reported as errors
+package dts
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/jsdoc"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type Opts struct {
+	ParserOpts *parser.ParserOpts
+
+	// the indentation of the members of the classes, enums and namespaces
+	Indent string
+}
+
+func NewOpts() *Opts {
+	return &Opts{
+		ParserOpts: parser.NewParserOpts(),
+		Indent:     "    ",
+	}
+}
+
+// the problem of the declaration which cannot be emitted without the type checker, the `any`
+// is emitted in place of the type which cannot be inferred
+type Error struct {
+	src *span.Source
+	Rng span.Range
+	Msg string
+}
+
+func (e *Error) Error() string {
+	pos := e.src.OfstLineCol(e.Rng.Lo)
+	return fmt.Sprintf("%s at (%d:%d)", e.Msg, pos.Line, pos.Col)
+}
+
+type Result struct {
+	Code   string
+	Errors []*Error
+}
+
+// the name of the declaration file of the source file, e.g. `a.ts` to `a.d.ts` and `a.mts`
+// to `a.d.mts`
+func DtsFile(file string) string {
+	for _, ext := range []string{".d.ts", ".d.mts", ".d.cts"} {
+		if strings.HasSuffix(file, ext) {
+			return file
+		}
+	}
+	for _, ext := range []string{".mts", ".cts", ".mjs", ".cjs"} {
+		if strings.HasSuffix(file, ext) {
+			return strings.TrimSuffix(file, ext) + ".d." + ext[1:2] + "ts"
+		}
+	}
+	for _, ext := range []string{".tsx", ".ts", ".jsx", ".js"} {
+		if strings.HasSuffix(file, ext) {
+			return strings.TrimSuffix(file, ext) + ".d.ts"
+		}
+	}
+	return file + ".d.ts"
+}
+
+// emits the declaration file of the source, the returned error is the syntax error of the
+// source and the problems of the declarations are reported in `Result.Errors`
+func Emit(file, code string, opts *Opts) (*Result, error) {
+	if opts == nil {
+		opts = NewOpts()
+	}
+	p := parser.NewParser(span.NewSource(file, code), opts.ParserOpts.OfFile(file))
+	ast, err := p.Prog()
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasSuffix(file, ".d.ts") {
+		return &Result{Code: code, Errors: make([]*Error, 0)}, nil
+	}
+
+	e := &emitter{
+		p:      p,
+		indent: opts.Indent,
+		errs:   make([]*Error, 0),
+		refs:   map[string]bool{},
+	}
+	code = e.prog(ast.(*parser.Prog))
+	sort.SliceStable(e.errs, func(i, j int) bool {
+		return e.errs[i].Rng.Lo < e.errs[j].Rng.Lo
+	})
+	return &Result{Code: code, Errors: e.errs}, nil
+}
+
+type emitter struct {
+	p      *parser.Parser
+	indent string
+	errs   []*Error
+
+	// the names referenced by the emitted declarations, the imports and the local declarations
+	// of these names are emitted as well
+	refs map[string]bool
+}
+
+func (e *emitter) error(node parser.Node, format string, args ...interface{}) {
+	e.errs = append(e.errs, &Error{e.p.Source(), node.Range(), fmt.Sprintf(format, args...)})
+}
+
+func isModuleStmt(stmt parser.Node) bool {
+	switch n := stmt.(type) {
+	case *parser.ImportDec, *parser.ExportDec, *parser.TsExportAssign, *parser.TsImportRequire:
+		return true
+	case *parser.TsImportAlias:
+		return n.Export()
+	}
+	return false
+}
+
+func (e *emitter) prog(prog *parser.Prog) string {
+	stmts := prog.Body()
+	isModule := false
+	for _, stmt := range stmts {
+		if isModuleStmt(stmt) {
+			isModule = true
+			break
+		}
+	}
+
+	outs := make([]string, len(stmts))
+	done := make([]bool, len(stmts))
+	emit := func(i int) {
+		if !done[i] {
+			done[i] = true
+			outs[i] = e.stmt(stmts, i, "", false)
+		}
+	}
+
+	// the local declarations indexed by their names, they're emitted only if they're referenced
+	// by the exported ones in the modules, all the declarations in the scripts are global
+	locals := map[string][]int{}
+	for i, stmt := range stmts {
+		switch n := stmt.(type) {
+		case *parser.ImportDec:
+			continue
+		case *parser.TsDec:
+			if n.Type() == parser.N_TS_DEC_MODULE || n.Type() == parser.N_TS_DEC_GLOBAL {
+				// `declare module "x" {}` and `declare global {}`
+				emit(i)
+				continue
+			}
+		case *parser.ExportDec, *parser.TsExportAssign:
+			emit(i)
+			continue
+		case *parser.TsImportAlias:
+			if n.Export() {
+				emit(i)
+				continue
+			}
+		}
+		if !isModule {
+			emit(i)
+			continue
+		}
+		for _, name := range declaredNames(stmt) {
+			locals[name] = append(locals[name], i)
+		}
+	}
+
+	for {
+		changed := false
+		for name := range e.refs {
+			for _, i := range locals[name] {
+				if !done[i] {
+					emit(i)
+					changed = true
+				}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	// the imports are emitted after all the references are collected
+	hasModuleStmt := false
+	for i, stmt := range stmts {
+		if imp, ok := stmt.(*parser.ImportDec); ok {
+			outs[i] = e.importDec(imp)
+		}
+		if outs[i] != "" && isModuleStmt(stmt) {
+			hasModuleStmt = true
+		}
+	}
+
+	var b strings.Builder
+	for _, out := range outs {
+		if out != "" {
+			b.WriteString(out)
+			b.WriteString("\n")
+		}
+	}
+	if isModule && !hasModuleStmt {
+		// keeps the output as a module
+		b.WriteString("export {};\n")
+	}
+	return b.String()
+}
+
+// the names declared by the statement in the scope it belongs to
+func declaredNames(stmt parser.Node) []string {
+	if td, ok := stmt.(*parser.TsDec); ok {
+		stmt = td.Inner()
+	}
+	switch n := stmt.(type) {
+	case *parser.FnDec:
+		return []string{nameOf(n.Id())}
+	case *parser.ClassDec:
+		return []string{nameOf(n.Id())}
+	case *parser.TsInterface:
+		return []string{nameOf(n.Id())}
+	case *parser.TsTypDec:
+		return []string{nameOf(n.Id())}
+	case *parser.TsEnum:
+		return []string{nameOf(n.Id())}
+	case *parser.TsNS:
+		return []string{leftmost(n.Id())}
+	case *parser.TsImportRequire:
+		return []string{nameOf(n.Name())}
+	case *parser.TsImportAlias:
+		return []string{nameOf(n.Name())}
+	case *parser.VarDecStmt:
+		ret := make([]string, 0, len(n.DecList()))
+		for _, vd := range n.DecList() {
+			ret = append(ret, nameOf(vd.(*parser.VarDec).Id()))
+		}
+		return ret
+	}
+	return nil
+}
+
+func nameOf(node parser.Node) string {
+	if id, ok := node.(*parser.Ident); ok {
+		return id.Val()
+	}
+	return ""
+}
+
+// the leftmost name of the entity name such as `a` of `a.b.c`
+func leftmost(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.TsNsName:
+		return leftmost(n.Lhs())
+	case *parser.MemberExpr:
+		return leftmost(n.Obj())
+	case *parser.TsRef:
+		return leftmost(n.Name())
+	}
+	return ""
+}
+
+// whether the node is an entity name such as `a` and `a.b.c`
+func isEntityName(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return true
+	case *parser.MemberExpr:
+		return !n.Compute() && isEntityName(n.Obj())
+	}
+	return false
+}
+
+func (e *emitter) ref(name string) {
+	if name != "" {
+		e.refs[name] = true
+	}
+}
+
+// collects the names referenced by the types in the node
+func (e *emitter) refsOf(node parser.Node) {
+	if node == nil {
+		return
+	}
+	switch n := node.(type) {
+	case *parser.TsRef:
+		e.ref(leftmost(n.Name()))
+	case *parser.TsTypQuery:
+		e.ref(leftmost(n.Arg()))
+	}
+	for _, c := range parser.ChildNodes(node) {
+		e.refsOf(c)
+	}
+}
+
+// the source text of the node, the trailing semicolon is excluded
+func (e *emitter) text(node parser.Node) string {
+	return strings.TrimRight(strings.TrimSpace(e.p.RngText(node.Range())), ";")
+}
+
+// the source text of the type whose referenced names are collected
+func (e *emitter) typ(node parser.Node) string {
+	if ta, ok := node.(*parser.TsTypAnnot); ok {
+		node = ta.TsTyp()
+	}
+	if node == nil {
+		return ""
+	}
+	e.refsOf(node)
+	return e.text(node)
+}
+
+// the leading JSDoc comment of the node re-indented by `ind`
+func (e *emitter) leading(node parser.Node, ind string) string {
+	doc := jsdoc.Of(e.p, node)
+	if doc == nil {
+		return ""
+	}
+	lines := strings.Split(e.p.RngText(doc.Rng), "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if i > 0 {
+			line = " " + line
+		}
+		lines[i] = ind + line
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// the import declaration with only the referenced specifiers
+func (e *emitter) importDec(n *parser.ImportDec) string {
+	var def, ns string
+	named := make([]string, 0)
+	for _, spec := range n.Specs() {
+		spec := spec.(*parser.ImportSpec)
+		local := nameOf(spec.Local())
+		if !e.refs[local] {
+			continue
+		}
+		switch {
+		case spec.Default():
+			def = local
+		case spec.NameSpace():
+			ns = "* as " + local
+		default:
+			named = append(named, e.text(spec))
+		}
+	}
+
+	parts := make([]string, 0, 2)
+	if def != "" {
+		parts = append(parts, def)
+	}
+	if ns != "" {
+		parts = append(parts, ns)
+	}
+	if len(named) > 0 {
+		parts = append(parts, "{ "+strings.Join(named, ", ")+" }")
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+
+	kw := "import "
+	if n.TsTyp() {
+		kw = "import type "
+	}
+	src := e.text(n.Src())
+	if !n.AttrKw().Empty() {
+		// the import attributes like `with { type: "json" }` are kept along with the module specifier
+		src = strings.TrimRight(strings.TrimSpace(e.p.RngText(span.Range{Lo: n.Src().Range().Lo, Hi: n.Range().Hi})), ";")
+	}
+	return kw + strings.Join(parts, ", ") + " from " + src + ";"
+}
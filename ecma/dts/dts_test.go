@@ -0,0 +1,229 @@
+package dts
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func emit(t *testing.T, code string) *Result {
+	ret, err := Emit("a.ts", code, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	return ret
+}
+
+func assertDts(t *testing.T, code, expect string) {
+	ret := emit(t, code)
+	AssertEqual(t, 0, len(ret.Errors), "should be ok")
+	AssertEqual(t, strings.TrimLeft(expect, "\n"), ret.Code, "should be ok")
+}
+
+func errsOf(ret *Result) string {
+	msgs := make([]string, len(ret.Errors))
+	for i, err := range ret.Errors {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func TestFn(t *testing.T) {
+	assertDts(t, `
+/**
+ * Adds.
+ */
+export function add(a: number, b = 1, ...rest: string[]): number { return a + b }
+export function skip(a = 1, b: string) { if (a) return }
+
+export function pick(a: string): string;
+export function pick(a: number): number;
+export function pick(a: any) { return a }
+
+export async function get<T extends object>({ a, b: [c, d = 1] }: T): Promise<T> { return a }
+`, `
+/**
+ * Adds.
+ */
+export declare function add(a: number, b?: number, ...rest: string[]): number;
+export declare function skip(a: number | undefined, b: string): void;
+export declare function pick(a: string): string;
+export declare function pick(a: number): number;
+export declare function get<T extends object>({ a, b: [c, d] }: T): Promise<T>;
+`)
+}
+
+func TestVar(t *testing.T) {
+	assertDts(t, `
+export const a = 1, b = "s", c = -2n, d: Array<string | number> = []
+export let e = true, f = (x: number): string => "", g = <T>(x: T) => {}
+export var h = foo as Foo, i = "x" as const
+`, `
+export declare const a = 1, b = "s", c = -2n, d: Array<string | number>;
+export declare let e: boolean, f: (x: number) => string, g: <T>(x: T) => void;
+export declare var h: Foo, i: "x";
+`)
+}
+
+func TestClass(t *testing.T) {
+	assertDts(t, `
+/** A box. */
+export default abstract class Box<T> extends Base<T> implements Sized {
+  #hidden = 2
+  /** the size */
+  readonly size = 0
+  private secret = 1
+  static count?: number
+  prop = "str"
+  constructor(public name: string, private readonly x?: number) { super() }
+  get area(): number { return 1 }
+  set area(v) {}
+  m(a: string): void;
+  m(a: number): void;
+  m(a: any) {}
+  abstract n(): T
+  async *items(): AsyncGenerator<T> {}
+  private p(a: number) {}
+  [k: string]: any
+}
+`, `
+/** A box. */
+export default abstract class Box<T> extends Base<T> implements Sized {
+    #private;
+    /** the size */
+    readonly size = 0;
+    private secret;
+    static count?: number;
+    prop: string;
+    name: string;
+    private readonly x?;
+    constructor(name: string, x?: number);
+    get area(): number;
+    set area(v: number);
+    m(a: string): void;
+    m(a: number): void;
+    abstract n(): T;
+    items(): AsyncGenerator<T>;
+    private p;
+    [k: string]: any;
+}
+`)
+}
+
+func TestEnumNs(t *testing.T) {
+	assertDts(t, `
+export enum E { A, B, C = 1 << 4, D, F = "s" }
+export const enum CE { X = 2, Y = X * 2, Z = ~Y }
+export namespace N.M {
+  export const x = 1
+  interface I {}
+  export function f(a: I): void {}
+  const hidden = 2
+}
+declare global { interface Window { x: number } }
+`, `
+export declare enum E {
+    A = 0,
+    B = 1,
+    C = 16,
+    D = 17,
+    F = "s",
+}
+export declare const enum CE {
+    X = 2,
+    Y = 4,
+    Z = -5,
+}
+export declare namespace N.M {
+    export const x = 1;
+    interface I {}
+    export function f(a: I): void;
+}
+declare global { interface Window { x: number } }
+`)
+}
+
+func TestRefs(t *testing.T) {
+	assertDts(t, `
+import { Foo, Unused } from "./foo"
+import Def, * as NS from "./ns"
+import type { T1 } from "./t"
+import "./side-effect"
+
+interface Local { a: Foo }
+type Alias = NS.X | T1
+const secret = 1
+function helper(): void {}
+
+export function f(x: Local, y: Alias): typeof Def { helper() }
+export { secret as s2 }
+export * from "./other"
+export type { Q } from "./q"
+`, `
+import { Foo } from "./foo";
+import Def, * as NS from "./ns";
+import type { T1 } from "./t";
+interface Local { a: Foo }
+type Alias = NS.X | T1;
+declare const secret = 1;
+export declare function f(x: Local, y: Alias): typeof Def;
+export { secret as s2 };
+export * from "./other";
+export type { Q } from "./q";
+`)
+
+	assertDts(t, `
+import x from "y"
+const a = x
+`, `
+export {};
+`)
+
+	assertDts(t, `
+import data from "./data.json" with { type: "json" }
+export const d: typeof data = data
+export * from "./other.json" with { type: "json" }
+`, `
+import data from "./data.json" with { type: "json" };
+export declare const d: typeof data;
+export * from "./other.json" with { type: "json" };
+`)
+
+	assertDts(t, `
+export default (a: number): void => {}
+`, `
+declare const _default: (a: number) => void;
+export default _default;
+`)
+
+	assertDts(t, `
+class A { constructor(a = 1) {} }
+export = A
+`, `
+declare class A {
+    constructor(a?: number);
+}
+export = A;
+`)
+}
+
+func TestErrors(t *testing.T) {
+	ret := emit(t, `export function bad(x) { return x }
+export let g = [1]
+export class B { get x() { return 1 } }
+export default {}`)
+	AssertEqual(t, `the function requires an explicit return type annotation at (1:7)
+the parameter requires an explicit type annotation at (1:20)
+the variable `+"`g`"+` requires an explicit type annotation at (2:11)
+the getter `+"`x`"+` requires an explicit return type annotation at (3:21)
+the type of the default export cannot be inferred, assign it to a variable with the type annotation at (4:15)`, errsOf(ret), "should be ok")
+	AssertEqual(t, true, strings.Contains(ret.Code, "export declare function bad(x: any): any;"), "should be ok")
+}
+
+func TestDtsFile(t *testing.T) {
+	AssertEqual(t, "a.d.ts", DtsFile("a.ts"), "should be ok")
+	AssertEqual(t, "a.d.ts", DtsFile("a.tsx"), "should be ok")
+	AssertEqual(t, "a.d.mts", DtsFile("a.mts"), "should be ok")
+	AssertEqual(t, "a.d.cts", DtsFile("a.cts"), "should be ok")
+	AssertEqual(t, "a.d.mts", DtsFile("a.mjs"), "should be ok")
+	AssertEqual(t, "a.d.ts", DtsFile("a.d.ts"), "should be ok")
+}
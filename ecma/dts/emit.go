@@ -0,0 +1,805 @@
+package dts
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+)
+
+// the declaration of `stmts[i]`, it's empty if the statement declares nothing, `ambient` is
+// true in the namespaces where the `declare` keyword is not allowed
+func (e *emitter) stmt(stmts []parser.Node, i int, ind string, ambient bool) string {
+	stmt := stmts[i]
+	declare := "declare "
+	if ambient {
+		declare = ""
+	}
+
+	switch n := stmt.(type) {
+	case *parser.ExportDec:
+		if n.Src() != nil {
+			return ind + e.text(n) + ";"
+		}
+		if dec := n.Dec(); dec != nil {
+			if n.Default() {
+				return e.defaultDec(stmt, dec, ind, declare)
+			}
+			if isOverloaded(stmts, i) {
+				return ""
+			}
+			return e.leading(stmt, ind) + e.dec(dec, ind, "export ", declare, ambient)
+		}
+		for _, spec := range n.Specs() {
+			e.ref(nameOf(spec.(*parser.ExportSpec).Local()))
+		}
+		return ind + e.text(n) + ";"
+	case *parser.TsExportAssign:
+		if !isEntityName(n.Expr()) {
+			e.error(n.Expr(), "the expression of `export =` must be an identifier or a qualified name")
+			return ""
+		}
+		e.ref(leftmost(n.Expr()))
+		return ind + e.text(n) + ";"
+	case *parser.TsImportRequire:
+		return ind + e.text(n) + ";"
+	case *parser.TsImportAlias:
+		e.ref(leftmost(n.Val()))
+		return ind + e.text(n) + ";"
+	}
+	if isOverloaded(stmts, i) {
+		return ""
+	}
+	return e.leading(stmt, ind) + e.dec(stmt, ind, "", declare, ambient)
+}
+
+func fnOf(stmt parser.Node) *parser.FnDec {
+	if exp, ok := stmt.(*parser.ExportDec); ok {
+		stmt = exp.Dec()
+	}
+	if fn, ok := stmt.(*parser.FnDec); ok {
+		return fn
+	}
+	return nil
+}
+
+// whether `stmts[i]` is the implementation of the overloads, which is not the part of the
+// declarations
+func isOverloaded(stmts []parser.Node, i int) bool {
+	fn := fnOf(stmts[i])
+	if fn == nil || fn.Body() == nil || i == 0 {
+		return false
+	}
+	prev := fnOf(stmts[i-1])
+	return prev != nil && prev.Body() == nil && nameOf(prev.Id()) == nameOf(fn.Id())
+}
+
+// the declaration in the form of `export default ...`
+func (e *emitter) defaultDec(stmt, dec parser.Node, ind, declare string) string {
+	lead := e.leading(stmt, ind)
+	switch n := dec.(type) {
+	case *parser.FnDec, *parser.ClassDec, *parser.TsInterface:
+		return lead + e.dec(n, ind, "export default ", "", false)
+	case *parser.Ident:
+		e.ref(n.Val())
+		return ind + "export default " + n.Val() + ";"
+	}
+
+	typ := e.exprTyp(dec, true)
+	if typ == "" {
+		e.error(dec, "the type of the default export cannot be inferred, assign it to a variable with the type annotation")
+		typ = "any"
+	}
+	return lead + ind + declare + "const _default: " + typ + ";\n" + ind + "export default _default;"
+}
+
+// the declaration of the node, `prefix` is `export ` or `export default ` if the declaration is
+// exported and `declare` is the keyword for the ambient context
+func (e *emitter) dec(node parser.Node, ind, prefix, declare string, ambient bool) string {
+	switch n := node.(type) {
+	case *parser.FnDec:
+		if n.Id() == nil && prefix != "export default " {
+			return ""
+		}
+		return ind + prefix + declare + e.fnSig("function "+nameOf(n.Id()), n, n.TypInfo()) + ";"
+	case *parser.ClassDec:
+		return e.class(n, ind, prefix+declare)
+	case *parser.VarDecStmt:
+		return e.varDecStmt(n, ind, prefix+declare)
+	case *parser.TsInterface, *parser.TsTypDec:
+		e.refsOf(n)
+		s := e.text(n)
+		if n.Type() == parser.N_TS_TYP_DEC {
+			s += ";"
+		}
+		return ind + prefix + s
+	case *parser.TsEnum:
+		return e.enum(n, ind, prefix+declare)
+	case *parser.TsNS:
+		return e.ns(n, ind, prefix+declare)
+	case *parser.TsDec:
+		// the ambient declarations are kept as they are
+		e.refsOf(n)
+		s := e.text(n)
+		if !strings.HasSuffix(s, "}") {
+			s += ";"
+		}
+		if ambient {
+			s = strings.TrimPrefix(s, "declare ")
+		}
+		return ind + prefix + s
+	}
+	return ""
+}
+
+// the signature of the function in the form of `name<T>(params): R`
+func (e *emitter) fnSig(name string, n *parser.FnDec, ti *parser.TypInfo) string {
+	s := name + e.typParams(ti) + "(" + e.params(n.Params(), nil) + ")"
+	return s + ": " + e.retTyp(n, n.Async() || n.Generator(), n.Rets(), ti)
+}
+
+func (e *emitter) typParams(ti *parser.TypInfo) string {
+	if ti == nil || ti.TypParams() == nil {
+		return ""
+	}
+	return e.typ(ti.TypParams())
+}
+
+// the return type of the function, it's inferred as `void` only if the function returns
+// nothing, otherwise it's required to be annotated
+func (e *emitter) retTyp(fn parser.Node, asyncOrGen bool, rets []parser.Node, ti *parser.TypInfo) string {
+	if ti != nil && ti.TypAnnot() != nil {
+		return e.typ(ti.TypAnnot())
+	}
+	if !asyncOrGen {
+		void := true
+		for _, ret := range rets {
+			if r, ok := ret.(*parser.RetStmt); !ok || r.Arg() != nil {
+				void = false
+				break
+			}
+		}
+		if void {
+			if fd, ok := fn.(*parser.FnDec); !ok || fd.Body() != nil {
+				return "void"
+			}
+		}
+	}
+	e.error(fn, "the function requires an explicit return type annotation")
+	return "any"
+}
+
+func annotOf(node parser.Node) parser.Node {
+	if ti := parser.TypInfoOf(node); ti != nil && ti.TypAnnot() != nil && ti.TypAnnot().TsTyp() != nil {
+		return ti.TypAnnot()
+	}
+	return nil
+}
+
+// the params of the function, the params with the default values become optional unless they
+// are followed by the required ones, `props` collects the parameter properties of the
+// constructors
+func (e *emitter) params(params []parser.Node, props *[]parser.Node) string {
+	lastRequired := -1
+	for i, param := range params {
+		switch param.(type) {
+		case *parser.AssignPat, *parser.RestPat:
+			continue
+		}
+		if ti := parser.TypInfoOf(param); ti == nil || !ti.Optional() {
+			lastRequired = i
+		}
+	}
+
+	parts := make([]string, len(params))
+	for i, param := range params {
+		ti := parser.TypInfoOf(param)
+		if props != nil && ti != nil && (ti.AccMod() != parser.ACC_MOD_NONE || ti.Readonly()) {
+			*props = append(*props, param)
+		}
+
+		switch n := param.(type) {
+		case *parser.AssignPat:
+			name := e.pattern(n.Lhs())
+			typ := e.paramTyp(n)
+			if i < lastRequired {
+				parts[i] = name + ": " + typ + " | undefined"
+			} else {
+				parts[i] = name + "?: " + typ
+			}
+		case *parser.RestPat:
+			typ := annotOf(n)
+			if typ == nil {
+				typ = annotOf(n.Arg())
+			}
+			s := "..." + e.pattern(n.Arg()) + ": "
+			if typ == nil {
+				e.error(n, "the rest parameter requires an explicit type annotation")
+				parts[i] = s + "any[]"
+			} else {
+				parts[i] = s + e.typ(typ)
+			}
+		default:
+			name := e.pattern(n)
+			if ti != nil && ti.Optional() {
+				name += "?"
+			}
+			parts[i] = name + ": " + e.paramTyp(n)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (e *emitter) paramTyp(param parser.Node) string {
+	if typ := annotOf(param); typ != nil {
+		return e.typ(typ)
+	}
+	if ap, ok := param.(*parser.AssignPat); ok {
+		if typ := annotOf(ap.Lhs()); typ != nil {
+			return e.typ(typ)
+		}
+		if _, widened := e.literal(ap.Rhs()); widened != "" {
+			return widened
+		}
+	}
+	if id, ok := param.(*parser.Ident); ok && id.Val() == "this" {
+		e.error(param, "the `this` parameter requires an explicit type annotation")
+	} else {
+		e.error(param, "the parameter requires an explicit type annotation")
+	}
+	return "any"
+}
+
+// the binding pattern without the default values and the type annotations
+func (e *emitter) pattern(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.AssignPat:
+		return e.pattern(n.Lhs())
+	case *parser.RestPat:
+		return "..." + e.pattern(n.Arg())
+	case *parser.ObjPat:
+		props := make([]string, len(n.Props()))
+		for i, prop := range n.Props() {
+			switch p := prop.(type) {
+			case *parser.Prop:
+				if p.Shorthand() {
+					props[i] = e.pattern(p.Val())
+				} else {
+					props[i] = e.propKey(p.Key(), p.Computed()) + ": " + e.pattern(p.Val())
+				}
+			default:
+				props[i] = e.pattern(prop)
+			}
+		}
+		if len(props) == 0 {
+			return "{}"
+		}
+		return "{ " + strings.Join(props, ", ") + " }"
+	case *parser.ArrPat:
+		elems := make([]string, len(n.Elems()))
+		for i, elem := range n.Elems() {
+			if elem != nil {
+				elems[i] = e.pattern(elem)
+			}
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+	return e.text(node)
+}
+
+func (e *emitter) propKey(key parser.Node, computed bool) string {
+	if computed {
+		if isEntityName(key) {
+			e.ref(leftmost(key))
+		}
+		return "[" + e.text(key) + "]"
+	}
+	if id, ok := key.(*parser.Ident); ok {
+		return id.Val()
+	}
+	return e.text(key)
+}
+
+// the literal type of the node and its widened type such as `1` and `number`, they're empty
+// if the node is not a literal
+func (e *emitter) literal(node parser.Node) (string, string) {
+	switch n := node.(type) {
+	case *parser.NumLit:
+		if parser.NodeIsBigint(n, e.p.Source()) {
+			return e.text(n), "bigint"
+		}
+		return e.text(n), "number"
+	case *parser.StrLit:
+		return e.text(n), "string"
+	case *parser.BoolLit:
+		return e.text(n), "boolean"
+	case *parser.UnaryExpr:
+		if n.OpText() == "-" {
+			if lit, widened := e.literal(n.Arg()); widened == "number" || widened == "bigint" {
+				return "-" + lit, widened
+			}
+		}
+	}
+	return "", ""
+}
+
+// the type of the expression which can be inferred locally, `readonly` indicates whether the
+// literal types are kept instead of being widened, it's empty if the type cannot be inferred
+func (e *emitter) exprTyp(node parser.Node, readonly bool) string {
+	if lit, widened := e.literal(node); lit != "" {
+		if readonly {
+			return lit
+		}
+		return widened
+	}
+
+	switch n := node.(type) {
+	case *parser.ArrowFn:
+		ti := n.TypInfo()
+		s := e.typParams(ti) + "(" + e.params(n.Params(), nil) + ") => "
+		if n.Expr() && (ti == nil || ti.TypAnnot() == nil) {
+			e.error(n, "the function requires an explicit return type annotation")
+			return s + "any"
+		}
+		return s + e.retTyp(n, n.Async(), n.Rets(), ti)
+	case *parser.FnDec:
+		ti := n.TypInfo()
+		return e.typParams(ti) + "(" + e.params(n.Params(), nil) + ") => " + e.retTyp(n, n.Async() || n.Generator(), n.Rets(), ti)
+	case *parser.BinExpr:
+		if n.Op() == parser.T_TS_AS {
+			if nameOf(leftmostTyp(n.Rhs())) == "const" {
+				if lit, _ := e.literal(n.Lhs()); lit != "" {
+					return lit
+				}
+				return ""
+			}
+			return e.typ(n.Rhs())
+		}
+		// `satisfies` checks the expression against the type without changing its type
+		if n.Op() == parser.T_TS_SATISFIES {
+			return e.exprTyp(n.Lhs(), readonly)
+		}
+	case *parser.TsTypAssert:
+		return e.typ(n.Typ())
+	}
+	return ""
+}
+
+func leftmostTyp(node parser.Node) parser.Node {
+	if ref, ok := node.(*parser.TsRef); ok && ref.ParamsInst() == nil {
+		return ref.Name()
+	}
+	return nil
+}
+
+func (e *emitter) varDecStmt(n *parser.VarDecStmt, ind, prefix string) string {
+	kind := n.Kind()
+	decs := make([]string, 0, len(n.DecList()))
+	for _, vd := range n.DecList() {
+		vd := vd.(*parser.VarDec)
+		id, ok := vd.Id().(*parser.Ident)
+		if !ok {
+			e.error(vd.Id(), "the destructuring declarations are not supported, declare the bindings individually")
+			continue
+		}
+
+		if typ := annotOf(id); typ != nil {
+			decs = append(decs, id.Val()+": "+e.typ(typ))
+			continue
+		}
+		if kind == "const" {
+			if lit, _ := e.literal(vd.Init()); lit != "" {
+				decs = append(decs, id.Val()+" = "+lit)
+				continue
+			}
+		}
+		typ := ""
+		if vd.Init() != nil {
+			typ = e.exprTyp(vd.Init(), false)
+		}
+		if typ == "" {
+			e.error(id, "the variable `%s` requires an explicit type annotation", id.Val())
+			typ = "any"
+		}
+		decs = append(decs, id.Val()+": "+typ)
+	}
+	if len(decs) == 0 {
+		return ""
+	}
+	return ind + prefix + kind + " " + strings.Join(decs, ", ") + ";"
+}
+
+func (e *emitter) class(n *parser.ClassDec, ind, prefix string) string {
+	s := ind + prefix
+	if n.Abstract() {
+		s += "abstract "
+	}
+	s += "class"
+	if n.Id() != nil {
+		s += " " + nameOf(n.Id())
+	}
+	if ti := n.TypInfo(); ti != nil {
+		s += e.typParams(ti)
+	}
+	if sup := n.Super(); sup != nil {
+		if isEntityName(sup) {
+			e.ref(leftmost(sup))
+			s += " extends " + e.text(sup)
+			if n.SuperTypArgs() != nil {
+				s += e.typ(n.SuperTypArgs())
+			}
+		} else {
+			e.error(sup, "the super class must be an identifier or a qualified name")
+		}
+	}
+	if impls := n.Implements(); len(impls) > 0 {
+		parts := make([]string, len(impls))
+		for i, impl := range impls {
+			parts[i] = e.typ(impl)
+		}
+		s += " implements " + strings.Join(parts, ", ")
+	}
+
+	members := e.classMembers(n, ind+e.indent)
+	if len(members) == 0 {
+		return s + " {\n" + ind + "}"
+	}
+	return s + " {\n" + strings.Join(members, "\n") + "\n" + ind + "}"
+}
+
+func accMod(ti *parser.TypInfo) string {
+	if ti == nil {
+		return ""
+	}
+	switch ti.AccMod() {
+	case parser.ACC_MOD_PRI:
+		return "private "
+	case parser.ACC_MOD_PRO:
+		return "protected "
+	}
+	return ""
+}
+
+func modifiers(ti *parser.TypInfo, static bool) string {
+	s := accMod(ti)
+	if static {
+		s += "static "
+	}
+	if ti != nil && ti.Abstract() {
+		s += "abstract "
+	}
+	if ti != nil && ti.Readonly() {
+		s += "readonly "
+	}
+	if ti != nil && ti.Accessor() {
+		s += "accessor "
+	}
+	return s
+}
+
+// the type of the accessor `key` from the annotation of its counterpart, e.g. the type of the
+// getter is inferred from the param of the setter
+func (e *emitter) accessorTyp(elems []parser.Node, key string, kind parser.PropKind) string {
+	for _, elem := range elems {
+		m, ok := elem.(*parser.Method)
+		if !ok || m.PropKind() != kind || e.propKey(m.Key(), m.Computed()) != key {
+			continue
+		}
+		fn := m.Val().(*parser.FnDec)
+		if kind == parser.PK_GETTER {
+			if ti := fn.TypInfo(); ti != nil && ti.TypAnnot() != nil {
+				return e.typ(ti.TypAnnot())
+			}
+		} else if len(fn.Params()) == 1 {
+			if typ := annotOf(fn.Params()[0]); typ != nil {
+				return e.typ(typ)
+			}
+		}
+	}
+	return ""
+}
+
+func (e *emitter) classMembers(n *parser.ClassDec, ind string) []string {
+	elems := n.Body().(*parser.ClassBody).Elems()
+	ret := make([]string, 0, len(elems))
+	hasPrivateName := false
+	for i, elem := range elems {
+		switch m := elem.(type) {
+		case *parser.Method:
+			if id, ok := m.Key().(*parser.Ident); ok && id.IsPrivate() {
+				hasPrivateName = true
+				continue
+			}
+			fn := m.Val().(*parser.FnDec)
+			ti := m.TypInfo()
+			key := e.propKey(m.Key(), m.Computed())
+			if fn.Body() != nil && i > 0 {
+				// the implementation of the overloads
+				if prev, ok := elems[i-1].(*parser.Method); ok && prev.PropKind() == m.PropKind() &&
+					prev.Val().(*parser.FnDec).Body() == nil && e.propKey(prev.Key(), prev.Computed()) == key {
+					continue
+				}
+			}
+
+			lead := e.leading(m, ind)
+			mods := modifiers(ti, m.Static())
+			if ti != nil && ti.AccMod() == parser.ACC_MOD_PRI {
+				// the types of the private members are not the part of the API
+				switch m.PropKind() {
+				case parser.PK_GETTER:
+					ret = append(ret, lead+ind+mods+"get "+key+"();")
+				case parser.PK_SETTER:
+					ret = append(ret, lead+ind+mods+"set "+key+"(value);")
+				case parser.PK_CTOR:
+					ret = append(ret, lead+ind+"private constructor();")
+				default:
+					ret = append(ret, lead+ind+mods+key+";")
+				}
+				continue
+			}
+
+			switch m.PropKind() {
+			case parser.PK_CTOR:
+				props := make([]parser.Node, 0)
+				sig := "constructor(" + e.params(fn.Params(), &props) + ");"
+				for _, prop := range props {
+					ret = append(ret, ind+e.paramProp(prop))
+				}
+				ret = append(ret, lead+ind+accMod(ti)+sig)
+			case parser.PK_GETTER:
+				typ := ""
+				if fti := fn.TypInfo(); fti != nil && fti.TypAnnot() != nil {
+					typ = e.typ(fti.TypAnnot())
+				} else if typ = e.accessorTyp(elems, key, parser.PK_SETTER); typ == "" {
+					e.error(m.Key(), "the getter `%s` requires an explicit return type annotation", key)
+					typ = "any"
+				}
+				ret = append(ret, lead+ind+mods+"get "+key+"(): "+typ+";")
+			case parser.PK_SETTER:
+				params := fn.Params()
+				if len(params) == 1 && annotOf(params[0]) == nil {
+					if typ := e.accessorTyp(elems, key, parser.PK_GETTER); typ != "" {
+						ret = append(ret, lead+ind+mods+"set "+key+"("+e.pattern(params[0])+": "+typ+");")
+						continue
+					}
+				}
+				ret = append(ret, lead+ind+mods+"set "+key+"("+e.params(params, nil)+");")
+			default:
+				if ti != nil && ti.Optional() {
+					key += "?"
+				}
+				ret = append(ret, lead+ind+mods+e.fnSig(key, fn, fn.TypInfo())+";")
+			}
+		case *parser.Field:
+			ti := m.TypInfo()
+			if id, ok := m.Key().(*parser.Ident); ok && id.IsPrivate() {
+				hasPrivateName = true
+				continue
+			}
+			lead := e.leading(m, ind)
+			if m.IsTsSig() {
+				e.refsOf(m)
+				ret = append(ret, lead+ind+e.text(m)+";")
+				continue
+			}
+
+			key := e.propKey(m.Key(), m.Computed())
+			if kti := parser.TypInfoOf(m.Key()); ti != nil && ti.Optional() || kti != nil && kti.Optional() {
+				key += "?"
+			}
+			mods := modifiers(ti, m.Static())
+			if ti != nil && ti.AccMod() == parser.ACC_MOD_PRI {
+				ret = append(ret, lead+ind+mods+key+";")
+				continue
+			}
+
+			typ := annotOf(m)
+			if typ == nil {
+				typ = annotOf(m.Key())
+			}
+			if typ != nil {
+				ret = append(ret, lead+ind+mods+key+": "+e.typ(typ)+";")
+				continue
+			}
+			readonly := ti != nil && ti.Readonly()
+			if lit, _ := e.literal(m.Val()); lit != "" && readonly {
+				ret = append(ret, lead+ind+mods+key+" = "+lit+";")
+				continue
+			}
+			s := ""
+			if m.Val() != nil {
+				s = e.exprTyp(m.Val(), false)
+			}
+			if s == "" {
+				e.error(m.Key(), "the property `%s` requires an explicit type annotation", key)
+				s = "any"
+			}
+			ret = append(ret, lead+ind+mods+key+": "+s+";")
+		}
+	}
+	if hasPrivateName {
+		ret = append([]string{ind + "#private;"}, ret...)
+	}
+	return ret
+}
+
+// the property declared by the parameter property of the constructor
+func (e *emitter) paramProp(param parser.Node) string {
+	ti := parser.TypInfoOf(param)
+	mods := modifiers(ti, false)
+	if ap, ok := param.(*parser.AssignPat); ok {
+		name := e.pattern(ap.Lhs()) + "?"
+		if ti.AccMod() == parser.ACC_MOD_PRI {
+			return mods + name + ";"
+		}
+		return mods + name + ": " + e.paramTyp(ap) + ";"
+	}
+	name := e.pattern(param)
+	if ti.Optional() {
+		name += "?"
+	}
+	if ti.AccMod() == parser.ACC_MOD_PRI {
+		return mods + name + ";"
+	}
+	return mods + name + ": " + e.paramTyp(param) + ";"
+}
+
+// the members of the enum with their values, the values of the members without the
+// initializers are computed as the tsc does if the preceding value is a constant number
+func (e *emitter) enum(n *parser.TsEnum, ind, prefix string) string {
+	s := ind + prefix
+	if n.Const() {
+		s += "const "
+	}
+	s += "enum " + nameOf(n.Id()) + " {"
+
+	vals := map[string]float64{}
+	next, ok := 0.0, true
+	mind := ind + e.indent
+	lines := make([]string, 0, len(n.Members()))
+	for _, m := range n.Members() {
+		m := m.(*parser.TsEnumMember)
+		key := nameOf(m.Key())
+		if key == "" {
+			key = e.text(m.Key())
+		}
+
+		line := e.leading(m, mind) + mind + key
+		if m.Val() == nil {
+			if !ok {
+				e.error(m, "the enum member `%s` requires an initializer", key)
+			} else {
+				line += " = " + fmtNum(next)
+				vals[key] = next
+				next++
+			}
+		} else if v, isNum := e.constNum(m.Val(), vals); isNum {
+			line += " = " + fmtNum(v)
+			vals[key] = v
+			next, ok = v+1, true
+		} else {
+			line += " = " + e.text(m.Val())
+			ok = false
+		}
+		lines = append(lines, line+",")
+	}
+	if len(lines) == 0 {
+		return s + "\n" + ind + "}"
+	}
+	return s + "\n" + strings.Join(lines, "\n") + "\n" + ind + "}"
+}
+
+func fmtNum(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func toInt32(v float64) int32 {
+	return int32(uint32(int64(v)))
+}
+
+// evaluates the constant numeric expression in the initializer of the enum member, the names
+// refer to the preceding members
+func (e *emitter) constNum(node parser.Node, vals map[string]float64) (float64, bool) {
+	switch n := node.(type) {
+	case *parser.NumLit:
+		if parser.NodeIsBigint(n, e.p.Source()) {
+			return 0, false
+		}
+		return parser.NodeToFloat(n, e.p.Source()), true
+	case *parser.Ident:
+		v, ok := vals[n.Val()]
+		return v, ok
+	case *parser.ParenExpr:
+		return e.constNum(n.Expr(), vals)
+	case *parser.UnaryExpr:
+		v, ok := e.constNum(n.Arg(), vals)
+		if !ok {
+			return 0, false
+		}
+		switch n.OpText() {
+		case "-":
+			return -v, true
+		case "+":
+			return v, true
+		case "~":
+			return float64(^toInt32(v)), true
+		}
+	case *parser.BinExpr:
+		l, ok := e.constNum(n.Lhs(), vals)
+		if !ok {
+			return 0, false
+		}
+		r, ok := e.constNum(n.Rhs(), vals)
+		if !ok {
+			return 0, false
+		}
+		switch n.OpText() {
+		case "+":
+			return l + r, true
+		case "-":
+			return l - r, true
+		case "*":
+			return l * r, true
+		case "/":
+			return l / r, true
+		case "%":
+			return math.Mod(l, r), true
+		case "**":
+			return math.Pow(l, r), true
+		case "|":
+			return float64(toInt32(l) | toInt32(r)), true
+		case "&":
+			return float64(toInt32(l) & toInt32(r)), true
+		case "^":
+			return float64(toInt32(l) ^ toInt32(r)), true
+		case "<<":
+			return float64(toInt32(l) << (uint32(toInt32(r)) & 31)), true
+		case ">>":
+			return float64(toInt32(l) >> (uint32(toInt32(r)) & 31)), true
+		case ">>>":
+			return float64(uint32(toInt32(l)) >> (uint32(toInt32(r)) & 31)), true
+		}
+	}
+	return 0, false
+}
+
+// the namespace whose exported members are emitted, the interfaces and the type aliases are
+// emitted even if they're not exported since they may be referenced by the exported ones
+func (e *emitter) ns(n *parser.TsNS, ind, prefix string) string {
+	name := e.text(n.Id())
+	inner := n.Body()
+	for {
+		// `namespace a.b {}` is nested as `namespace a { namespace b {} }` in the AST
+		ns, ok := inner.(*parser.TsNS)
+		if !ok {
+			break
+		}
+		name += "." + e.text(ns.Id())
+		inner = ns.Body()
+	}
+
+	s := ind + prefix + "namespace " + name + " {"
+	body, ok := inner.(*parser.BlockStmt)
+	if !ok {
+		return s + "\n" + ind + "}"
+	}
+
+	mind := ind + e.indent
+	lines := make([]string, 0)
+	stmts := body.Body()
+	for i, stmt := range stmts {
+		switch stmt.(type) {
+		case *parser.ExportDec, *parser.TsInterface, *parser.TsTypDec, *parser.TsNS, *parser.TsImportAlias:
+			if out := e.stmt(stmts, i, mind, true); out != "" {
+				lines = append(lines, out)
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return s + "\n" + ind + "}"
+	}
+	return s + "\n" + strings.Join(lines, "\n") + "\n" + ind + "}"
+}
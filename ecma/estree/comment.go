@@ -0,0 +1,152 @@
+package estree
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// https://github.com/estree/estree/blob/master/es5.md#comments
+type Comment struct {
+	Type  string  `json:"type"` // "Line" | "Block"
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Value string  `json:"value"`
+}
+
+// the comments attached to the node, it's embedded in all the nodes and it's nil unless
+// `ConvertCtx.Comments` is turned on and the node has the comments around it
+type NodeComments struct {
+	LeadingComments  []*Comment `json:"leadingComments,omitempty"`
+	TrailingComments []*Comment `json:"trailingComments,omitempty"`
+	InnerComments    []*Comment `json:"innerComments,omitempty"`
+}
+
+func comment(rng span.Range, ctx *ConvertCtx) *Comment {
+	text := ctx.Parser.RngText(rng)
+	c := &Comment{
+		Start: int(rng.Lo),
+		End:   int(rng.Hi),
+		Loc:   locOfRng(rng, ctx.Parser.Source(), ctx),
+	}
+	// the hashbang is reported as a line comment like acorn does
+	if strings.HasPrefix(text, "//") || strings.HasPrefix(text, "#!") {
+		c.Type = "Line"
+		c.Value = text[2:]
+	} else {
+		c.Type = "Block"
+		c.Value = text[2 : len(text)-2]
+	}
+	return c
+}
+
+func comments(rngs []span.Range, ctx *ConvertCtx) []*Comment {
+	if len(rngs) == 0 {
+		return nil
+	}
+	ret := make([]*Comment, len(rngs))
+	for i, rng := range rngs {
+		ret[i] = comment(rng, ctx)
+	}
+	return ret
+}
+
+// the adapter of the ESTree node to `parser.Node`, so the comments can be attached to the
+// ESTree nodes by `parser.AttachComments`, the ESTree nodes are used instead of the nodes
+// of the parser since some of them are built from the tokens rather than the nodes, such as
+// the `TemplateElement`, and their ranges follow the ESTree conventions
+type cmtNode struct {
+	val reflect.Value // the pointer to the ESTree node
+	rng span.Range
+}
+
+func (n *cmtNode) Type() parser.NodeType {
+	return parser.N_ILLEGAL
+}
+
+func (n *cmtNode) Range() span.Range {
+	return n.rng
+}
+
+type cmtTree struct {
+	nodes map[uintptr]*cmtNode
+}
+
+func (t *cmtTree) node(v reflect.Value) *cmtNode {
+	if n, ok := t.nodes[v.Pointer()]; ok {
+		return n
+	}
+	s := v.Elem()
+	n := &cmtNode{v, span.Range{Lo: uint32(s.FieldByName("Start").Int()), Hi: uint32(s.FieldByName("End").Int())}}
+	t.nodes[v.Pointer()] = n
+	return n
+}
+
+// whether the value is the pointer to an ESTree node which has the ranges and the comments
+func isCmtNode(v reflect.Value) bool {
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return false
+	}
+	_, ok := v.Elem().Type().FieldByName("NodeComments")
+	return ok
+}
+
+func (t *cmtTree) collect(v reflect.Value, ret []parser.Node) []parser.Node {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			return t.collect(v.Elem(), ret)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			ret = t.collect(v.Index(i), ret)
+		}
+	case reflect.Ptr:
+		if isCmtNode(v) {
+			n := t.node(v)
+			if !n.rng.Empty() {
+				ret = append(ret, n)
+			}
+		}
+	}
+	return ret
+}
+
+func (t *cmtTree) children(node parser.Node) []parser.Node {
+	s := node.(*cmtNode).val.Elem()
+	ret := make([]parser.Node, 0)
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Type().Field(i)
+		if f.Anonymous || f.Name == "Loc" {
+			continue
+		}
+		ret = t.collect(s.Field(i), ret)
+	}
+	return ret
+}
+
+// attaches the comments to the ESTree nodes of the program, the comments are attached in the
+// same way as `parser.Parser.NodeComments` except that the ranges of the ESTree nodes are used
+func attachComments(prog *Program, cmts []span.Range, ctx *ConvertCtx) {
+	t := &cmtTree{map[uintptr]*cmtNode{}}
+	root := t.node(reflect.ValueOf(prog))
+	src := ctx.Parser.Source()
+	attached := parser.AttachComments(root, cmts, src.Text(0, uint32(src.Len())), t.children)
+
+	nc := reflect.TypeOf(NodeComments{})
+	for _, n := range t.nodes {
+		leading, trailing, inner := attached.Leading(n), attached.Trailing(n), attached.Inner(n)
+		if len(leading) == 0 && len(trailing) == 0 && len(inner) == 0 {
+			continue
+		}
+		f := n.val.Elem().FieldByName(nc.Name())
+		f.Set(reflect.ValueOf(&NodeComments{
+			LeadingComments:  comments(leading, ctx),
+			TrailingComments: comments(trailing, ctx),
+			InnerComments:    comments(inner, ctx),
+		}))
+	}
+}
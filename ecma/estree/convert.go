@@ -33,13 +33,22 @@ func ConvertProg(n *parser.Prog, ctx *ConvertCtx) *Program {
 	for i, s := range stmts {
 		body[i] = Convert(s, ctx)
 	}
-	return &Program{
+	prog := &Program{
 		Type:  "Program",
 		Start: int(n.Range().Lo),
 		End:   int(n.Range().Hi),
 		Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
 		Body:  body,
 	}
+	if ctx.Comments {
+		cmts := ctx.Parser.Comments()
+		prog.Comments = comments(cmts, ctx)
+		attachComments(prog, cmts, ctx)
+	}
+	if toks := ctx.Parser.Tokens(); len(toks) > 0 {
+		prog.Tokens = tokens(toks, ctx)
+	}
+	return prog
 }
 
 func arrExpr(n *parser.ArrLit, ctx *ConvertCtx) *ArrayExpression {
@@ -138,6 +147,9 @@ func declarations(decList []parser.Node, ctx *ConvertCtx) []*VariableDeclarator
 			Id:    Convert(dc.Id(), ctx),
 			Init:  Convert(dc.Init(), ctx),
 		}
+		if wt, ok := dc.Id().(parser.NodeWithTypInfo); ok && wt.TypInfo() != nil {
+			s[i].Definite = wt.TypInfo().Definite()
+		}
 	}
 	return s
 }
@@ -238,15 +250,26 @@ func exportAll(node *parser.ExportDec, ctx *ConvertCtx) Node {
 		spec = node.Specs()[0].(*parser.ExportSpec).Local()
 	}
 	return &ExportAllDeclaration{
-		Type:     "ExportAllDeclaration",
-		Start:    int(node.Range().Lo),
-		End:      int(node.Range().Hi),
-		Loc:      locOfNode(node, ctx.Parser.Source(), ctx),
-		Source:   Convert(node.Src(), ctx),
-		Exported: Convert(spec, ctx),
+		Type:       "ExportAllDeclaration",
+		Start:      int(node.Range().Lo),
+		End:        int(node.Range().Hi),
+		Loc:        locOfNode(node, ctx.Parser.Source(), ctx),
+		Source:     Convert(node.Src(), ctx),
+		Exported:   Convert(spec, ctx),
+		Attributes: elems(node.Attrs(), ctx),
+		ExportKind: node.Kind(),
+		Extra:      modExtra(node.AttrKw(), ctx),
 	}
 }
 
+// the legacy form `assert { type: "json" }` is marked by `extra.deprecatedAssertSyntax` as babel does
+func modExtra(kw span.Range, ctx *ConvertCtx) *ModExtra {
+	if kw.Empty() || ctx.Parser.RngText(kw) != "assert" {
+		return nil
+	}
+	return &ModExtra{DeprecatedAssertSyntax: true}
+}
+
 func exportDefault(node *parser.ExportDec, ctx *ConvertCtx) Node {
 	return &ExportDefaultDeclaration{
 		Type:        "ExportDefaultDeclaration",
@@ -283,7 +306,9 @@ func exportNamed(node *parser.ExportDec, ctx *ConvertCtx) Node {
 		Declaration: Convert(node.Dec(), ctx),
 		Specifiers:  exportSpecs(node.Specs(), ctx),
 		Source:      Convert(node.Src(), ctx),
+		Attributes:  elems(node.Attrs(), ctx),
 		ExportKind:  node.Kind(),
+		Extra:       modExtra(node.AttrKw(), ctx),
 	}
 }
 
@@ -359,7 +384,9 @@ func locWithTypeInfo(node parser.Node, includeParamProp bool, s *span.Source, ct
 
 	firstRng, firstLoc := FirstLoc(s, ctx, starLocList...)
 	rng.Lo = firstRng.Lo
-	loc.Start = firstLoc.Start
+	if loc != nil {
+		loc.Start = firstLoc.Start
+	}
 
 	endLocList := []span.Range{}
 	if ti.TypAnnot() != nil {
@@ -369,14 +396,16 @@ func locWithTypeInfo(node parser.Node, includeParamProp bool, s *span.Source, ct
 
 	endRng, endLoc := LastLoc(s, ctx, endLocList...)
 	rng.Hi = endRng.Hi
-	loc.End = endLoc.End
+	if loc != nil {
+		loc.End = endLoc.End
+	}
 
 	return
 }
 
 func TplLocWithTag(n *parser.TplExpr, s *span.Source, ctx *ConvertCtx) *SrcLoc {
 	loc := CalcLoc(n, s, ctx)
-	if n.Tag() != nil {
+	if n.Tag() != nil && loc != nil {
 		tl := CalcLoc(n.Tag(), s, ctx)
 		loc.Start = tl.Start
 		loc.End = tl.End
@@ -426,8 +455,9 @@ func locWithDecorator(node parser.Node, ds []parser.Node, s *span.Source, ctx *C
 		return
 	}
 	d := ds[0]
-	dLoc := locOfNode(d, s, ctx)
-	loc.Start = dLoc.Start
+	if loc != nil {
+		loc.Start = locOfNode(d, s, ctx).Start
+	}
 	rng.Lo = d.Range().Lo
 	return
 }
@@ -436,6 +466,10 @@ type ConvertCtx struct {
 	Parser  *parser.Parser
 	Scope   *ConvertScope
 	LineCol bool
+
+	// outputs the comments in the `comments` of the program, and attaches them to the nodes
+	// as their `leadingComments`, `trailingComments` and `innerComments`
+	Comments bool
 }
 
 func NewConvertCtx(p *parser.Parser) *ConvertCtx {
@@ -458,6 +492,11 @@ func (c *ConvertCtx) leave() {
 	c.Scope = scope.Up
 }
 
+// the type annotations are converted to the flow nodes if the parser is in flow mode
+func (c *ConvertCtx) flow() bool {
+	return c.Parser.Feature()&parser.FEAT_FLOW != 0
+}
+
 type ConvertScopeFlag uint64
 
 const (
@@ -582,12 +621,14 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 		}
 	case parser.N_LIT_REGEXP:
 		regexp := node.(*parser.RegLit)
+		regex := &Regexp{regexp.Pattern(), regexp.Flags()}
 		return &RegExpLiteral{
 			Type:   "Literal",
 			Start:  int(node.Range().Lo),
 			End:    int(node.Range().Hi),
 			Loc:    locOfNode(node, ctx.Parser.Source(), ctx),
-			Regexp: &Regexp{regexp.Pattern(), regexp.Flags()},
+			Regex:  regex,
+			Regexp: regex,
 		}
 	case parser.N_EXPR_BIN:
 		bin := node.(*parser.BinExpr)
@@ -617,6 +658,16 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 				TypeAnnotation: rhs,
 			}
 		}
+		if opv == parser.T_TS_SATISFIES {
+			return &TSSatisfiesExpression{
+				Type:           "TSSatisfiesExpression",
+				Start:          int(node.Range().Lo),
+				End:            int(node.Range().Hi),
+				Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+				Expression:     lhs,
+				TypeAnnotation: rhs,
+			}
+		}
 		return &BinaryExpression{
 			Type:     "BinaryExpression",
 			Start:    int(node.Range().Lo),
@@ -1129,11 +1180,12 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 	case parser.N_IMPORT_CALL:
 		stmt := node.(*parser.ImportCall)
 		return &ImportExpression{
-			Type:   "ImportExpression",
-			Start:  int(stmt.Range().Lo),
-			End:    int(stmt.Range().Hi),
-			Loc:    locOfNode(stmt, ctx.Parser.Source(), ctx),
-			Source: Convert(stmt.Src(), ctx),
+			Type:    "ImportExpression",
+			Start:   int(stmt.Range().Lo),
+			End:     int(stmt.Range().Hi),
+			Loc:     locOfNode(stmt, ctx.Parser.Source(), ctx),
+			Source:  Convert(stmt.Src(), ctx),
+			Options: Convert(stmt.Opts(), ctx),
 		}
 	case parser.N_META_PROP:
 		stmt := node.(*parser.MetaProp)
@@ -1161,7 +1213,7 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 				TypeParameters:      ConvertTsTyp(typParams, ctx),
 				SuperClass:          Convert(stmt.Super(), ctx),
 				SuperTypeParameters: ConvertTsTyp(superTypArgs, ctx),
-				Implements:          elems(implements, ctx),
+				Implements:          clsImplements(implements, ctx),
 				Body:                Convert(stmt.Body(), ctx),
 				Abstract:            stmt.Abstract(),
 				Declare:             stmt.Declare(),
@@ -1185,30 +1237,33 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 		superTypArgs := stmt.SuperTypArgs()
 		typParams := stmt.TypParams()
 		implements := stmt.Implements()
+		rng, loc := locWithDecorator(stmt, parser.DecoratorsOf(stmt), ctx.Parser.Source(), ctx)
 		if superTypArgs != nil || typParams != nil || implements != nil {
 			return &TSClassExpression{
 				Type:                "ClassExpression",
-				Start:               int(stmt.Range().Lo),
-				End:                 int(stmt.Range().Hi),
-				Loc:                 locOfNode(stmt, ctx.Parser.Source(), ctx),
+				Start:               int(rng.Lo),
+				End:                 int(rng.Hi),
+				Loc:                 loc,
 				Id:                  Convert(stmt.Id(), ctx),
 				TypeParameters:      ConvertTsTyp(typParams, ctx),
 				SuperClass:          Convert(stmt.Super(), ctx),
 				SuperTypeParameters: ConvertTsTyp(superTypArgs, ctx),
-				Implements:          elems(implements, ctx),
+				Implements:          clsImplements(implements, ctx),
 				Body:                Convert(stmt.Body(), ctx),
 				Abstract:            stmt.Abstract(),
+				Decorators:          elems(parser.DecoratorsOf(stmt), ctx),
 			}
 		}
 		return &ClassExpression{
 			Type:       "ClassExpression",
-			Start:      int(stmt.Range().Lo),
-			End:        int(stmt.Range().Hi),
-			Loc:        locOfNode(stmt, ctx.Parser.Source(), ctx),
+			Start:      int(rng.Lo),
+			End:        int(rng.Hi),
+			Loc:        loc,
 			Id:         Convert(stmt.Id(), ctx),
 			SuperClass: Convert(stmt.Super(), ctx),
 			Body:       Convert(stmt.Body(), ctx),
 			Abstract:   stmt.Abstract(),
+			Decorators: elems(parser.DecoratorsOf(stmt), ctx),
 		}
 	case parser.N_CLASS_BODY:
 		stmt := node.(*parser.ClassBody)
@@ -1279,8 +1334,13 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 				}
 			}
 
+			// the auto-accessor field `accessor x = 1` is `ClassAccessorProperty` in babel
+			typ := "PropertyDefinition"
+			if ti.Accessor() {
+				typ = "ClassAccessorProperty"
+			}
 			return &TSPropertyDefinition{
-				Type:           "PropertyDefinition",
+				Type:           typ,
 				Start:          int(rng.Lo),
 				End:            int(rng.Hi),
 				Loc:            loc,
@@ -1297,6 +1357,7 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 				Accessibility:  ti.AccMod().String(),
 				TypeAnnotation: typAnnot(ti, ctx),
 				Decorators:     elems(parser.DecoratorsOf(n), ctx),
+				Variance:       variance(ti.Variance(), ctx),
 			}
 		}
 		return &PropertyDefinition{
@@ -1357,7 +1418,19 @@ func Convert(node parser.Node, ctx *ConvertCtx) Node {
 			Loc:        locOfNode(stmt, ctx.Parser.Source(), ctx),
 			Specifiers: importSpecs(stmt.Specs(), ctx),
 			Source:     Convert(stmt.Src(), ctx),
+			Attributes: elems(stmt.Attrs(), ctx),
 			ImportKind: stmt.Kind(),
+			Extra:      modExtra(stmt.AttrKw(), ctx),
+		}
+	case parser.N_IMPORT_ATTR:
+		attr := node.(*parser.ImportAttr)
+		return &ImportAttribute{
+			Type:  "ImportAttribute",
+			Start: int(attr.Range().Lo),
+			End:   int(attr.Range().Hi),
+			Loc:   locOfNode(attr, ctx.Parser.Source(), ctx),
+			Key:   Convert(attr.Key(), ctx),
+			Value: Convert(attr.Val(), ctx),
 		}
 	case parser.N_STMT_EXPORT:
 		stmt := node.(*parser.ExportDec)
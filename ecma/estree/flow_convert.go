@@ -0,0 +1,799 @@
+package estree
+
+import (
+	"math"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// the type annotations of flow are parsed by the facilities of typescript, this method
+// converts them to the flow nodes, nil is returned for the nodes which have no flow
+// counterparts and the caller should fallback to `ConvertTsTyp`
+func flowConvert(node parser.Node, ctx *ConvertCtx) Node {
+	switch node.Type() {
+	case parser.N_TS_TYP_ANNOT:
+		n := node.(*parser.TsTypAnnot)
+		return &TypeAnnotation{
+			Type:           "TypeAnnotation",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			TypeAnnotation: flowTyp(n.TsTyp(), ctx),
+		}
+	case parser.N_TS_NUM:
+		return flowKeyword("NumberTypeAnnotation", node, ctx)
+	case parser.N_TS_STR:
+		return flowKeyword("StringTypeAnnotation", node, ctx)
+	case parser.N_TS_ANY:
+		return flowKeyword("AnyTypeAnnotation", node, ctx)
+	case parser.N_TS_BOOL:
+		return flowKeyword("BooleanTypeAnnotation", node, ctx)
+	case parser.N_TS_VOID:
+		return flowKeyword("VoidTypeAnnotation", node, ctx)
+	case parser.N_TS_SYM:
+		return flowKeyword("SymbolTypeAnnotation", node, ctx)
+	case parser.N_TS_BIGINT:
+		return flowKeyword("BigIntTypeAnnotation", node, ctx)
+	case parser.N_TS_NULL:
+		return flowKeyword("NullLiteralTypeAnnotation", node, ctx)
+	case parser.N_TS_THIS:
+		return flowKeyword("ThisTypeAnnotation", node, ctx)
+	case parser.N_TS_NEVER, parser.N_TS_UNKNOWN, parser.N_TS_OBJ, parser.N_TS_UNDEF, parser.N_TS_INTRINSIC:
+		// they are the keywords of typescript but the ordinary names in flow
+		return &GenericTypeAnnotation{
+			Type:  "GenericTypeAnnotation",
+			Start: int(node.Range().Lo),
+			End:   int(node.Range().Hi),
+			Loc:   locOfNode(node, ctx.Parser.Source(), ctx),
+			Id:    flowIdent(node, ctx),
+		}
+	case parser.N_TS_REF:
+		n := node.(*parser.TsRef)
+		if n.Name().Type() == parser.N_NAME && n.ParamsInst() == nil {
+			switch ctx.Parser.NodeText(n.Name()) {
+			case "mixed":
+				return flowKeyword("MixedTypeAnnotation", node, ctx)
+			case "empty":
+				return flowKeyword("EmptyTypeAnnotation", node, ctx)
+			case "bool":
+				return flowKeyword("BooleanTypeAnnotation", node, ctx)
+			}
+		}
+		return &GenericTypeAnnotation{
+			Type:           "GenericTypeAnnotation",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			Id:             flowTypId(n.Name(), ctx),
+			TypeParameters: ConvertTsTyp(n.ParamsInst(), ctx),
+		}
+	case parser.N_TS_PARAM_INST:
+		n := node.(*parser.TsParamsInst)
+		return &TypeParameterInstantiation{
+			Type:   "TypeParameterInstantiation",
+			Start:  int(n.Range().Lo),
+			End:    int(n.Range().Hi),
+			Loc:    locOfNode(n, ctx.Parser.Source(), ctx),
+			Params: flowTyps(n.Params(), ctx),
+		}
+	case parser.N_TS_PARAM_DEC:
+		n := node.(*parser.TsParamsDec)
+		return &TypeParameterDeclaration{
+			Type:   "TypeParameterDeclaration",
+			Start:  int(n.Range().Lo),
+			End:    int(n.Range().Hi),
+			Loc:    locOfNode(n, ctx.Parser.Source(), ctx),
+			Params: flowTyps(n.Params(), ctx),
+		}
+	case parser.N_TS_PARAM:
+		n := node.(*parser.TsParam)
+		return &TypeParameter{
+			Type:     "TypeParameter",
+			Start:    int(n.Range().Lo),
+			End:      int(n.Range().Hi),
+			Loc:      locOfNode(n, ctx.Parser.Source(), ctx),
+			Name:     ctx.Parser.NodeText(n.Name()),
+			Bound:    ConvertTsTyp(n.Cons(), ctx),
+			Variance: variance(n.Variance(), ctx),
+			Default:  flowTyp(n.Default(), ctx),
+		}
+	case parser.N_TS_ARR:
+		n := node.(*parser.TsArr)
+		return &ArrayTypeAnnotation{
+			Type:        "ArrayTypeAnnotation",
+			Start:       int(n.Range().Lo),
+			End:         int(n.Range().Hi),
+			Loc:         locOfNode(n, ctx.Parser.Source(), ctx),
+			ElementType: flowTyp(n.Arg(), ctx),
+		}
+	case parser.N_TS_TUPLE:
+		n := node.(*parser.TsTuple)
+		return flowTypes("TupleTypeAnnotation", n, n.Args(), ctx)
+	case parser.N_TS_UNION_TYP:
+		n := node.(*parser.TsUnionTyp)
+		return flowTypes("UnionTypeAnnotation", n, n.Elems(), ctx)
+	case parser.N_TS_INTERSECT_TYP:
+		n := node.(*parser.TsIntersectTyp)
+		return flowTypes("IntersectionTypeAnnotation", n, n.Elems(), ctx)
+	case parser.N_TS_TYP_OP:
+		n := node.(*parser.TsTypOp)
+		if n.Op() != "?" {
+			return nil
+		}
+		return &NullableTypeAnnotation{
+			Type:           "NullableTypeAnnotation",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			TypeAnnotation: flowTyp(n.Arg(), ctx),
+		}
+	case parser.N_TS_TYP_QUERY:
+		n := node.(*parser.TsTypQuery)
+		arg := n.Arg()
+		return &TypeofTypeAnnotation{
+			Type:  "TypeofTypeAnnotation",
+			Start: int(n.Range().Lo),
+			End:   int(n.Range().Hi),
+			Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+			Argument: &GenericTypeAnnotation{
+				Type:  "GenericTypeAnnotation",
+				Start: int(arg.Range().Lo),
+				End:   int(arg.Range().Hi),
+				Loc:   locOfNode(arg, ctx.Parser.Source(), ctx),
+				Id:    flowTypId(arg, ctx),
+			},
+		}
+	case parser.N_TS_IDX_ACCESS:
+		n := node.(*parser.TsIdxAccess)
+		return &IndexedAccessType{
+			Type:       "IndexedAccessType",
+			Start:      int(n.Range().Lo),
+			End:        int(n.Range().Hi),
+			Loc:        locOfNode(n, ctx.Parser.Source(), ctx),
+			ObjectType: flowTyp(n.Obj(), ctx),
+			IndexType:  flowTyp(n.Idx(), ctx),
+		}
+	case parser.N_TS_PAREN:
+		return flowTyp(node.(*parser.TsParen).Arg(), ctx)
+	case parser.N_TS_LIT:
+		return flowLit(node.(*parser.TsLit), ctx)
+	case parser.N_TS_LIT_OBJ:
+		n := node.(*parser.TsObj)
+		return flowObj(n, n.Props(), n.Exact(), n.Inexact(), ctx)
+	case parser.N_TS_FN_TYP:
+		n := node.(*parser.TsFnTyp)
+		return flowFnTyp(n.Range(), n.TypParams(), n.Params(), n.RetTyp(), ctx)
+	case parser.N_TS_TYP_DEC:
+		return flowTypDec(node.(*parser.TsTypDec), node, "", ctx)
+	case parser.N_TS_DEC_TYP_DEC:
+		n := node.(*parser.TsDec)
+		return flowTypDec(n.Inner().(*parser.TsTypDec), n, "Declare", ctx)
+	case parser.N_TS_INTERFACE:
+		n := node.(*parser.TsInterface)
+		if n.Id() == nil {
+			b := n.Body().(*parser.TsInterfaceBody)
+			return &InterfaceTypeAnnotation{
+				Type:    "InterfaceTypeAnnotation",
+				Start:   int(n.Range().Lo),
+				End:     int(n.Range().Hi),
+				Loc:     locOfNode(n, ctx.Parser.Source(), ctx),
+				Extends: flowExtends("InterfaceExtends", n.Supers(), ctx),
+				Body:    flowObj(b, b.Body(), false, false, ctx),
+			}
+		}
+		return flowItf(n, node, "InterfaceDeclaration", ctx)
+	case parser.N_TS_DEC_INTERFACE:
+		n := node.(*parser.TsDec)
+		return flowItf(n.Inner().(*parser.TsInterface), n, "DeclareInterface", ctx)
+	case parser.N_TS_DEC_VAR_DEC:
+		n := node.(*parser.TsDec)
+		varDec, ok := n.Inner().(*parser.VarDecStmt)
+		if !ok || len(varDec.DecList()) != 1 {
+			return nil
+		}
+		return &DeclareVariable{
+			Type:  "DeclareVariable",
+			Start: int(n.Range().Lo),
+			End:   int(n.Range().Hi),
+			Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+			Id:    Convert(varDec.DecList()[0].(*parser.VarDec).Id(), ctx),
+			Kind:  varDec.Kind(),
+		}
+	case parser.N_TS_DEC_FN:
+		n := node.(*parser.TsDec)
+		return flowDecFn(n, n.Inner().(*parser.FnDec), ctx)
+	case parser.N_TS_DEC_CLASS:
+		n := node.(*parser.TsDec)
+		return flowDecClass(n, n.Inner().(*parser.ClassDec), ctx)
+	case parser.N_TS_DEC_MODULE:
+		n := node.(*parser.TsDec)
+		kind := "CommonJS"
+		if blk, ok := n.Inner().(*parser.BlockStmt); ok {
+			for _, stmt := range blk.Body() {
+				if stmt.Type() == parser.N_TS_DEC_EXPORT {
+					kind = "ES"
+					break
+				}
+			}
+		}
+		return &DeclareModule{
+			Type:  "DeclareModule",
+			Start: int(n.Range().Lo),
+			End:   int(n.Range().Hi),
+			Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+			Id:    Convert(n.Name(), ctx),
+			Body:  Convert(n.Inner(), ctx),
+			Kind:  kind,
+		}
+	case parser.N_TS_DEC_EXPORT:
+		n := node.(*parser.TsDec)
+		return flowDecExport(n, n.Inner().(*parser.ExportDec), ctx)
+	case parser.N_TS_DEC_MOD_EXPS:
+		n := node.(*parser.TsDec)
+		return &TypeAnnotation{
+			Type:           "DeclareModuleExports",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			TypeAnnotation: Convert(n.Inner(), ctx),
+		}
+	case parser.N_TS_TYP_ASSERT:
+		n := node.(*parser.TsTypAssert)
+		if !n.Cast() {
+			return nil
+		}
+		return &TypeCastExpression{
+			Type:           "TypeCastExpression",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			Expression:     Convert(n.Expr(), ctx),
+			TypeAnnotation: ConvertTsTyp(n.Typ(), ctx),
+		}
+	}
+	return nil
+}
+
+// converts the type without the wrapping `TypeAnnotation`
+func flowTyp(node parser.Node, ctx *ConvertCtx) Node {
+	if node == nil || util.IsNilPtr(node) {
+		return nil
+	}
+	if node.Type() == parser.N_TS_TYP_ANNOT {
+		node = node.(*parser.TsTypAnnot).TsTyp()
+	}
+	return ConvertTsTyp(node, ctx)
+}
+
+func flowTyps(nodes []parser.Node, ctx *ConvertCtx) []Node {
+	ret := make([]Node, len(nodes))
+	for i, node := range nodes {
+		ret[i] = flowTyp(node, ctx)
+	}
+	return ret
+}
+
+func flowTypes(typ string, node parser.Node, types []parser.Node, ctx *ConvertCtx) Node {
+	return &FlowTypesAnnotation{
+		Type:  typ,
+		Start: int(node.Range().Lo),
+		End:   int(node.Range().Hi),
+		Loc:   locOfNode(node, ctx.Parser.Source(), ctx),
+		Types: flowTyps(types, ctx),
+	}
+}
+
+func flowKeyword(typ string, node parser.Node, ctx *ConvertCtx) Node {
+	return &FlowKeywordTypeAnnotation{
+		Type:  typ,
+		Start: int(node.Range().Lo),
+		End:   int(node.Range().Hi),
+		Loc:   locOfNode(node, ctx.Parser.Source(), ctx),
+	}
+}
+
+// the identifier without the type annotation
+func flowIdent(node parser.Node, ctx *ConvertCtx) Node {
+	if node == nil {
+		return nil
+	}
+	return &Identifier{
+		Type:  "Identifier",
+		Start: int(node.Range().Lo),
+		End:   int(node.Range().Hi),
+		Loc:   locOfNode(node, ctx.Parser.Source(), ctx),
+		Name:  ctx.Parser.NodeText(node),
+	}
+}
+
+// the keys of the object type properties are the identifiers or the string literals
+func flowKey(node parser.Node, ctx *ConvertCtx) Node {
+	if node.Type() == parser.N_NAME {
+		return flowIdent(node, ctx)
+	}
+	return Convert(node, ctx)
+}
+
+// the name of the generic types like `A` and `A.B.C`
+func flowTypId(node parser.Node, ctx *ConvertCtx) Node {
+	if node.Type() == parser.N_TS_NS_NAME {
+		n := node.(*parser.TsNsName)
+		return &QualifiedTypeIdentifier{
+			Type:          "QualifiedTypeIdentifier",
+			Start:         int(n.Range().Lo),
+			End:           int(n.Range().Hi),
+			Loc:           locOfNode(n, ctx.Parser.Source(), ctx),
+			Qualification: flowTypId(n.Lhs(), ctx),
+			Id:            flowTypId(n.Rhs(), ctx),
+		}
+	}
+	return flowIdent(node, ctx)
+}
+
+func variance(rng span.Range, ctx *ConvertCtx) Node {
+	if rng.Empty() {
+		return nil
+	}
+	kind := "plus"
+	if ctx.Parser.RngText(rng) == "-" {
+		kind = "minus"
+	}
+	return &Variance{
+		Type:  "Variance",
+		Start: int(rng.Lo),
+		End:   int(rng.Hi),
+		Loc:   locOfRng(rng, ctx.Parser.Source(), ctx),
+		Kind:  kind,
+	}
+}
+
+func flowLit(n *parser.TsLit, ctx *ConvertCtx) Node {
+	lit := n.Lit()
+	var typ string
+	var val interface{}
+	switch lit.Type() {
+	case parser.N_LIT_STR:
+		typ = "StringLiteralTypeAnnotation"
+		val = parser.NodeText(lit, ctx.Parser.Source())
+	case parser.N_LIT_BOOL:
+		typ = "BooleanLiteralTypeAnnotation"
+		val = lit.(*parser.BoolLit).Val()
+	case parser.N_LIT_NUM:
+		typ = "NumberLiteralTypeAnnotation"
+		if parser.NodeIsBigint(lit.(*parser.NumLit), ctx.Parser.Source()) {
+			typ = "BigIntLiteralTypeAnnotation"
+		}
+		val = flowNum(lit, ctx)
+	case parser.N_EXPR_UNARY:
+		// the negative numbers like `-1`
+		arg := lit.(*parser.UnaryExpr).Arg()
+		typ = "NumberLiteralTypeAnnotation"
+		if parser.NodeIsBigint(arg.(*parser.NumLit), ctx.Parser.Source()) {
+			typ = "BigIntLiteralTypeAnnotation"
+		}
+		val = -flowNum(arg, ctx)
+	default:
+		return nil
+	}
+	return &FlowLiteralTypeAnnotation{
+		Type:  typ,
+		Start: int(n.Range().Lo),
+		End:   int(n.Range().Hi),
+		Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+		Value: val,
+		Raw:   ctx.Parser.RngText(n.Range()),
+	}
+}
+
+func flowNum(node parser.Node, ctx *ConvertCtx) float64 {
+	f := parser.NodeToFloat(node, ctx.Parser.Source())
+	if math.IsInf(f, 0) {
+		f = 0
+	}
+	return f
+}
+
+func flowObj(node parser.Node, members []parser.Node, exact, inexact bool, ctx *ConvertCtx) Node {
+	props := make([]Node, 0, len(members))
+	idxSigs := make([]Node, 0)
+	calls := make([]Node, 0)
+	for _, m := range members {
+		switch m.Type() {
+		case parser.N_TS_PROP:
+			props = append(props, flowProp(m.(*parser.TsProp), ctx))
+		case parser.N_TS_REST:
+			n := m.(*parser.TsRest)
+			props = append(props, &ObjectTypeSpreadProperty{
+				Type:     "ObjectTypeSpreadProperty",
+				Start:    int(n.Range().Lo),
+				End:      int(n.Range().Hi),
+				Loc:      locOfNode(n, ctx.Parser.Source(), ctx),
+				Argument: flowTyp(n.Arg(), ctx),
+			})
+		case parser.N_TS_IDX_SIG:
+			n := m.(*parser.TsIdxSig)
+			idxSigs = append(idxSigs, &ObjectTypeIndexer{
+				Type:     "ObjectTypeIndexer",
+				Start:    int(n.Range().Lo),
+				End:      int(n.Range().Hi),
+				Loc:      locOfNode(n, ctx.Parser.Source(), ctx),
+				Id:       flowIdent(n.KeyName(), ctx),
+				Key:      flowTyp(n.KeyType(), ctx),
+				Value:    flowTyp(n.Val(), ctx),
+				Variance: variance(n.Variance(), ctx),
+			})
+		case parser.N_TS_CALL_SIG:
+			n := m.(*parser.TsCallSig)
+			calls = append(calls, &ObjectTypeCallProperty{
+				Type:  "ObjectTypeCallProperty",
+				Start: int(n.Range().Lo),
+				End:   int(n.Range().Hi),
+				Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+				Value: flowFnTyp(n.Range(), n.TypParams(), n.Params(), n.RetTyp(), ctx),
+			})
+		default:
+			props = append(props, Convert(m, ctx))
+		}
+	}
+	return &ObjectTypeAnnotation{
+		Type:           "ObjectTypeAnnotation",
+		Start:          int(node.Range().Lo),
+		End:            int(node.Range().Hi),
+		Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+		Properties:     props,
+		Indexers:       idxSigs,
+		CallProperties: calls,
+		InternalSlots:  []Node{},
+		Exact:          exact,
+		Inexact:        inexact,
+	}
+}
+
+func flowProp(n *parser.TsProp, ctx *ConvertCtx) Node {
+	var val Node
+	kind := n.Kind().ToString()
+	if n.IsMethod() {
+		sig := n.Val().(*parser.TsCallSig)
+		val = flowFnTyp(sig.Range(), sig.TypParams(), sig.Params(), sig.RetTyp(), ctx)
+		if kind == "method" {
+			kind = "init"
+		}
+	} else {
+		val = flowTyp(n.Val(), ctx)
+	}
+	return &ObjectTypeProperty{
+		Type:     "ObjectTypeProperty",
+		Start:    int(n.Range().Lo),
+		End:      int(n.Range().Hi),
+		Loc:      locOfNode(n, ctx.Parser.Source(), ctx),
+		Key:      flowKey(n.Key(), ctx),
+		Value:    val,
+		Method:   n.IsMethod() && n.Kind() == parser.PK_METHOD,
+		Optional: n.Optional(),
+		Variance: variance(n.Variance(), ctx),
+		Kind:     kind,
+	}
+}
+
+// `retTyp` is the type annotation of the return type
+func flowFnTyp(rng span.Range, typParams parser.Node, params []parser.Node, retTyp parser.Node, ctx *ConvertCtx) Node {
+	ps := make([]Node, 0, len(params))
+	var rest Node
+	for _, param := range params {
+		if param.Type() == parser.N_PAT_REST || param.Type() == parser.N_TS_REST {
+			rest = flowFnParam(param, ctx)
+			continue
+		}
+		ps = append(ps, flowFnParam(param, ctx))
+	}
+	return &FunctionTypeAnnotation{
+		Type:           "FunctionTypeAnnotation",
+		Start:          int(rng.Lo),
+		End:            int(rng.Hi),
+		Loc:            locOfRng(rng, ctx.Parser.Source(), ctx),
+		Params:         ps,
+		Rest:           rest,
+		ReturnType:     flowTyp(retTyp, ctx),
+		TypeParameters: ConvertTsTyp(typParams, ctx),
+	}
+}
+
+var flowKeywords = map[string]string{
+	"any":     "AnyTypeAnnotation",
+	"bigint":  "BigIntTypeAnnotation",
+	"bool":    "BooleanTypeAnnotation",
+	"boolean": "BooleanTypeAnnotation",
+	"empty":   "EmptyTypeAnnotation",
+	"mixed":   "MixedTypeAnnotation",
+	"number":  "NumberTypeAnnotation",
+	"string":  "StringTypeAnnotation",
+	"symbol":  "SymbolTypeAnnotation",
+}
+
+// the param of function types maybe named like `a: T` or unnamed like `T`
+func flowFnParam(param parser.Node, ctx *ConvertCtx) Node {
+	switch param.Type() {
+	case parser.N_PAT_REST:
+		param = param.(*parser.RestPat).Arg()
+	case parser.N_TS_REST:
+		param = param.(*parser.TsRest).Arg()
+	}
+
+	if param.Type() == parser.N_NAME {
+		n := param.(*parser.Ident)
+		ti := n.TypInfo()
+		if ti != nil && ti.TypAnnot() != nil {
+			rng, loc := locWithTypeInfo(n, false, ctx.Parser.Source(), ctx)
+			return &FunctionTypeParam{
+				Type:           "FunctionTypeParam",
+				Start:          int(rng.Lo),
+				End:            int(rng.Hi),
+				Loc:            loc,
+				Name:           flowIdent(n, ctx),
+				Optional:       ti.Optional(),
+				TypeAnnotation: flowTyp(ti.TypAnnot(), ctx),
+			}
+		}
+
+		// the unnamed param of the declared functions like `declare function f(string): void`
+		// is parsed as the binding name
+		var typ Node
+		if kw, ok := flowKeywords[ctx.Parser.NodeText(n)]; ok {
+			typ = flowKeyword(kw, n, ctx)
+		} else {
+			typ = &GenericTypeAnnotation{
+				Type:  "GenericTypeAnnotation",
+				Start: int(n.Range().Lo),
+				End:   int(n.Range().Hi),
+				Loc:   locOfNode(n, ctx.Parser.Source(), ctx),
+				Id:    flowIdent(n, ctx),
+			}
+		}
+		return &FunctionTypeParam{
+			Type:           "FunctionTypeParam",
+			Start:          int(n.Range().Lo),
+			End:            int(n.Range().Hi),
+			Loc:            locOfNode(n, ctx.Parser.Source(), ctx),
+			TypeAnnotation: typ,
+		}
+	}
+
+	return &FunctionTypeParam{
+		Type:           "FunctionTypeParam",
+		Start:          int(param.Range().Lo),
+		End:            int(param.Range().Hi),
+		Loc:            locOfNode(param, ctx.Parser.Source(), ctx),
+		TypeAnnotation: flowTyp(param, ctx),
+	}
+}
+
+// `prefix` is `Declare` for the ambient declarations
+func flowTypDec(dec *parser.TsTypDec, node parser.Node, prefix string, ctx *ConvertCtx) Node {
+	if dec.Opaque() {
+		return &OpaqueType{
+			Type:           prefix + "OpaqueType",
+			Start:          int(node.Range().Lo),
+			End:            int(node.Range().Hi),
+			Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+			Id:             flowIdent(dec.Id(), ctx),
+			TypeParameters: ConvertTsTyp(dec.TypParams(), ctx),
+			Supertype:      flowTyp(dec.Super(), ctx),
+			Impltype:       flowTyp(dec.TypInfo().TypAnnot(), ctx),
+		}
+	}
+	return &TypeAlias{
+		Type:           prefix + "TypeAlias",
+		Start:          int(node.Range().Lo),
+		End:            int(node.Range().Hi),
+		Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+		Id:             flowIdent(dec.Id(), ctx),
+		TypeParameters: ConvertTsTyp(dec.TypParams(), ctx),
+		Right:          flowTyp(dec.TypInfo().TypAnnot(), ctx),
+	}
+}
+
+func flowItf(itf *parser.TsInterface, node parser.Node, typ string, ctx *ConvertCtx) Node {
+	var body Node
+	if itf.Body() != nil {
+		b := itf.Body().(*parser.TsInterfaceBody)
+		body = flowObj(b, b.Body(), false, false, ctx)
+	}
+	return &InterfaceDeclaration{
+		Type:           typ,
+		Start:          int(node.Range().Lo),
+		End:            int(node.Range().Hi),
+		Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+		Id:             flowIdent(itf.Id(), ctx),
+		TypeParameters: ConvertTsTyp(itf.TypParams(), ctx),
+		Extends:        flowExtends("InterfaceExtends", itf.Supers(), ctx),
+		Body:           body,
+	}
+}
+
+// converts the `extends` of interfaces and `implements` of classes
+func flowExtends(typ string, nodes []parser.Node, ctx *ConvertCtx) []Node {
+	ret := make([]Node, len(nodes))
+	for i, node := range nodes {
+		id := node
+		var args parser.Node
+		if node.Type() == parser.N_TS_REF {
+			n := node.(*parser.TsRef)
+			id = n.Name()
+			args = n.ParamsInst()
+		}
+		ret[i] = &InterfaceExtends{
+			Type:           typ,
+			Start:          int(node.Range().Lo),
+			End:            int(node.Range().Hi),
+			Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+			Id:             flowTypId(id, ctx),
+			TypeParameters: ConvertTsTyp(args, ctx),
+		}
+	}
+	return ret
+}
+
+// the `implements` of classes are `ClassImplements` in flow
+func clsImplements(nodes []parser.Node, ctx *ConvertCtx) []Node {
+	if ctx.flow() {
+		return flowExtends("ClassImplements", nodes, ctx)
+	}
+	return elems(nodes, ctx)
+}
+
+// the signature of the declared function is represented as the type annotation of its id:
+//
+// ```js
+// declare function f(a: number): string
+// ```
+func flowDecFn(node *parser.TsDec, fn *parser.FnDec, ctx *ConvertCtx) Node {
+	id := fn.Id()
+	ti := fn.TypInfo()
+
+	rng := span.Range{Lo: id.Range().Hi, Hi: id.Range().Hi}
+	if ti.TypParams() != nil {
+		rng.Lo = ti.TypParams().Range().Lo
+	}
+	if ti.TypAnnot() != nil {
+		rng.Hi = ti.TypAnnot().Range().Hi
+	}
+	fnTyp := flowFnTyp(rng, ti.TypParams(), fn.Params(), ti.TypAnnot(), ctx)
+
+	idRng := span.Range{Lo: id.Range().Lo, Hi: rng.Hi}
+	return &DeclareFunction{
+		Type:  "DeclareFunction",
+		Start: int(node.Range().Lo),
+		End:   int(node.Range().Hi),
+		Loc:   locOfNode(node, ctx.Parser.Source(), ctx),
+		Id: &TSIdentifier{
+			Type:  "Identifier",
+			Start: int(idRng.Lo),
+			End:   int(idRng.Hi),
+			Loc:   locOfRng(idRng, ctx.Parser.Source(), ctx),
+			Name:  ctx.Parser.NodeText(id),
+			TypeAnnotation: &TypeAnnotation{
+				Type:           "TypeAnnotation",
+				Start:          int(rng.Lo),
+				End:            int(rng.Hi),
+				Loc:            locOfRng(rng, ctx.Parser.Source(), ctx),
+				TypeAnnotation: fnTyp,
+			},
+		},
+	}
+}
+
+// the body of the declared class is represented as an object type
+func flowDecClass(node *parser.TsDec, cls *parser.ClassDec, ctx *ConvertCtx) Node {
+	var exts []Node
+	if cls.Super() != nil {
+		super := cls.Super()
+		rng := super.Range()
+		if cls.SuperTypArgs() != nil {
+			rng.Hi = cls.SuperTypArgs().Range().Hi
+		}
+		exts = []Node{&InterfaceExtends{
+			Type:           "InterfaceExtends",
+			Start:          int(rng.Lo),
+			End:            int(rng.Hi),
+			Loc:            locOfRng(rng, ctx.Parser.Source(), ctx),
+			Id:             flowIdent(super, ctx),
+			TypeParameters: ConvertTsTyp(cls.SuperTypArgs(), ctx),
+		}}
+	} else {
+		exts = []Node{}
+	}
+
+	body := cls.Body().(*parser.ClassBody)
+	props := make([]Node, 0, len(body.Elems()))
+	for _, elem := range body.Elems() {
+		switch elem.Type() {
+		case parser.N_FIELD:
+			n := elem.(*parser.Field)
+			ti := n.TypInfo()
+			props = append(props, &ObjectTypeProperty{
+				Type:     "ObjectTypeProperty",
+				Start:    int(n.Range().Lo),
+				End:      int(n.Range().Hi),
+				Loc:      locOfNode(n, ctx.Parser.Source(), ctx),
+				Key:      flowKey(n.Key(), ctx),
+				Value:    flowTyp(ti.TypAnnot(), ctx),
+				Optional: ti.Optional(),
+				Static:   n.Static(),
+				Variance: variance(ti.Variance(), ctx),
+				Kind:     "init",
+			})
+		case parser.N_METHOD:
+			n := elem.(*parser.Method)
+			fn := n.Val().(*parser.FnDec)
+			ti := fn.TypInfo()
+			kind := n.Kind()
+			if kind == "method" || kind == "constructor" {
+				kind = "init"
+			}
+			props = append(props, &ObjectTypeProperty{
+				Type:   "ObjectTypeProperty",
+				Start:  int(n.Range().Lo),
+				End:    int(n.Range().Hi),
+				Loc:    locOfNode(n, ctx.Parser.Source(), ctx),
+				Key:    flowKey(n.Key(), ctx),
+				Value:  flowFnTyp(fn.Range(), ti.TypParams(), fn.Params(), ti.TypAnnot(), ctx),
+				Method: kind == "init",
+				Static: n.Static(),
+				Kind:   kind,
+			})
+		default:
+			props = append(props, Convert(elem, ctx))
+		}
+	}
+
+	return &DeclareClass{
+		Type:           "DeclareClass",
+		Start:          int(node.Range().Lo),
+		End:            int(node.Range().Hi),
+		Loc:            locOfNode(node, ctx.Parser.Source(), ctx),
+		Id:             flowIdent(cls.Id(), ctx),
+		TypeParameters: ConvertTsTyp(cls.TypParams(), ctx),
+		Extends:        exts,
+		Implements:     flowExtends("ClassImplements", cls.Implements(), ctx),
+		Mixins:         []Node{},
+		Body: &ObjectTypeAnnotation{
+			Type:           "ObjectTypeAnnotation",
+			Start:          int(body.Range().Lo),
+			End:            int(body.Range().Hi),
+			Loc:            locOfNode(body, ctx.Parser.Source(), ctx),
+			Properties:     props,
+			Indexers:       []Node{},
+			CallProperties: []Node{},
+			InternalSlots:  []Node{},
+		},
+	}
+}
+
+func flowDecExport(node *parser.TsDec, exp *parser.ExportDec, ctx *ConvertCtx) Node {
+	if exp.All() {
+		var spec parser.Node
+		if len(exp.Specs()) == 1 {
+			spec = exp.Specs()[0].(*parser.ExportSpec).Local()
+		}
+		return &DeclareExportAllDeclaration{
+			Type:     "DeclareExportAllDeclaration",
+			Start:    int(node.Range().Lo),
+			End:      int(node.Range().Hi),
+			Loc:      locOfNode(node, ctx.Parser.Source(), ctx),
+			Source:   Convert(exp.Src(), ctx),
+			Exported: Convert(spec, ctx),
+		}
+	}
+	return &DeclareExportDeclaration{
+		Type:        "DeclareExportDeclaration",
+		Start:       int(node.Range().Lo),
+		End:         int(node.Range().Hi),
+		Loc:         locOfNode(node, ctx.Parser.Source(), ctx),
+		Default:     exp.Default(),
+		Declaration: flowTyp(exp.Dec(), ctx),
+		Specifiers:  exportSpecs(exp.Specs(), ctx),
+		Source:      Convert(exp.Src(), ctx),
+	}
+}
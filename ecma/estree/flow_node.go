@@ -0,0 +1,362 @@
+package estree
+
+// the nodes of flow follow the estree output of the flow-parser, which is also the basis of
+// the babel's flow plugin
+
+// `TypeAnnotation` and `DeclareModuleExports`
+type TypeAnnotation struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
+}
+
+// the primitive types like `NumberTypeAnnotation`, `MixedTypeAnnotation` and `ThisTypeAnnotation`
+type FlowKeywordTypeAnnotation struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
+}
+
+// the literal types like `StringLiteralTypeAnnotation` and `NumberLiteralTypeAnnotation`
+type FlowLiteralTypeAnnotation struct {
+	Type  string      `json:"type"`
+	Start int         `json:"start"`
+	End   int         `json:"end"`
+	Loc   *SrcLoc     `json:"loc"`
+	Value interface{} `json:"value"`
+	Raw   string      `json:"raw"`
+	*NodeComments
+}
+
+type GenericTypeAnnotation struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
+}
+
+type QualifiedTypeIdentifier struct {
+	Type          string  `json:"type"`
+	Start         int     `json:"start"`
+	End           int     `json:"end"`
+	Loc           *SrcLoc `json:"loc"`
+	Qualification Node    `json:"qualification"`
+	Id            Node    `json:"id"`
+	*NodeComments
+}
+
+type NullableTypeAnnotation struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
+}
+
+type ArrayTypeAnnotation struct {
+	Type        string  `json:"type"`
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	Loc         *SrcLoc `json:"loc"`
+	ElementType Node    `json:"elementType"`
+	*NodeComments
+}
+
+// `UnionTypeAnnotation`, `IntersectionTypeAnnotation` and `TupleTypeAnnotation`
+type FlowTypesAnnotation struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Types []Node  `json:"types"`
+	*NodeComments
+}
+
+type TypeofTypeAnnotation struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Argument Node    `json:"argument"`
+	*NodeComments
+}
+
+type IndexedAccessType struct {
+	Type       string  `json:"type"`
+	Start      int     `json:"start"`
+	End        int     `json:"end"`
+	Loc        *SrcLoc `json:"loc"`
+	ObjectType Node    `json:"objectType"`
+	IndexType  Node    `json:"indexType"`
+	*NodeComments
+}
+
+type FunctionTypeAnnotation struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Params         []Node  `json:"params"`
+	Rest           Node    `json:"rest"`
+	This           Node    `json:"this"`
+	ReturnType     Node    `json:"returnType"`
+	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
+}
+
+type FunctionTypeParam struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Name           Node    `json:"name"`
+	Optional       bool    `json:"optional"`
+	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
+}
+
+type ObjectTypeAnnotation struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Properties     []Node  `json:"properties"` // [ ObjectTypeProperty | ObjectTypeSpreadProperty ]
+	Indexers       []Node  `json:"indexers"`
+	CallProperties []Node  `json:"callProperties"`
+	InternalSlots  []Node  `json:"internalSlots"`
+	Exact          bool    `json:"exact"`
+	Inexact        bool    `json:"inexact"`
+	*NodeComments
+}
+
+type ObjectTypeProperty struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Key      Node    `json:"key"`
+	Value    Node    `json:"value"`
+	Method   bool    `json:"method"`
+	Optional bool    `json:"optional"`
+	Static   bool    `json:"static"`
+	Proto    bool    `json:"proto"`
+	Variance Node    `json:"variance"`
+	Kind     string  `json:"kind"`
+	*NodeComments
+}
+
+type ObjectTypeSpreadProperty struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Argument Node    `json:"argument"`
+	*NodeComments
+}
+
+type ObjectTypeIndexer struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Id       Node    `json:"id"`
+	Key      Node    `json:"key"`
+	Value    Node    `json:"value"`
+	Static   bool    `json:"static"`
+	Variance Node    `json:"variance"`
+	*NodeComments
+}
+
+type ObjectTypeCallProperty struct {
+	Type   string  `json:"type"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Loc    *SrcLoc `json:"loc"`
+	Value  Node    `json:"value"`
+	Static bool    `json:"static"`
+	*NodeComments
+}
+
+type Variance struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Kind  string  `json:"kind"` // plus | minus
+	*NodeComments
+}
+
+type TypeParameterDeclaration struct {
+	Type   string  `json:"type"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Loc    *SrcLoc `json:"loc"`
+	Params []Node  `json:"params"`
+	*NodeComments
+}
+
+type TypeParameter struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Name     string  `json:"name"`
+	Bound    Node    `json:"bound"`
+	Variance Node    `json:"variance"`
+	Default  Node    `json:"default"`
+	*NodeComments
+}
+
+type TypeParameterInstantiation struct {
+	Type   string  `json:"type"`
+	Start  int     `json:"start"`
+	End    int     `json:"end"`
+	Loc    *SrcLoc `json:"loc"`
+	Params []Node  `json:"params"`
+	*NodeComments
+}
+
+// `TypeAlias` and `DeclareTypeAlias`
+type TypeAlias struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	Right          Node    `json:"right"`
+	*NodeComments
+}
+
+// `OpaqueType` and `DeclareOpaqueType`
+type OpaqueType struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	Supertype      Node    `json:"supertype"`
+	Impltype       Node    `json:"impltype"`
+	*NodeComments
+}
+
+// `InterfaceDeclaration` and `DeclareInterface`
+type InterfaceDeclaration struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	Extends        []Node  `json:"extends"`
+	Body           Node    `json:"body"`
+	*NodeComments
+}
+
+// the inline interface type `interface { m(): void }`
+type InterfaceTypeAnnotation struct {
+	Type    string  `json:"type"`
+	Start   int     `json:"start"`
+	End     int     `json:"end"`
+	Loc     *SrcLoc `json:"loc"`
+	Extends []Node  `json:"extends"`
+	Body    Node    `json:"body"`
+	*NodeComments
+}
+
+// `InterfaceExtends` and `ClassImplements`
+type InterfaceExtends struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
+}
+
+type DeclareVariable struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Id    Node    `json:"id"`
+	Kind  string  `json:"kind"`
+	*NodeComments
+}
+
+type DeclareFunction struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Id    Node    `json:"id"`
+	*NodeComments
+}
+
+type DeclareClass struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Id             Node    `json:"id"`
+	TypeParameters Node    `json:"typeParameters"`
+	Extends        []Node  `json:"extends"`
+	Implements     []Node  `json:"implements"`
+	Mixins         []Node  `json:"mixins"`
+	Body           Node    `json:"body"`
+	*NodeComments
+}
+
+type DeclareModule struct {
+	Type  string  `json:"type"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Id    Node    `json:"id"`
+	Body  Node    `json:"body"`
+	Kind  string  `json:"kind"` // CommonJS | ES
+	*NodeComments
+}
+
+type DeclareExportDeclaration struct {
+	Type        string  `json:"type"`
+	Start       int     `json:"start"`
+	End         int     `json:"end"`
+	Loc         *SrcLoc `json:"loc"`
+	Default     bool    `json:"default"`
+	Declaration Node    `json:"declaration"`
+	Specifiers  []Node  `json:"specifiers"`
+	Source      Node    `json:"source"`
+	*NodeComments
+}
+
+type DeclareExportAllDeclaration struct {
+	Type     string  `json:"type"`
+	Start    int     `json:"start"`
+	End      int     `json:"end"`
+	Loc      *SrcLoc `json:"loc"`
+	Source   Node    `json:"source"`
+	Exported Node    `json:"exported"`
+	*NodeComments
+}
+
+type TypeCastExpression struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Expression     Node    `json:"expression"`
+	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
+}
@@ -11,6 +11,7 @@ type JSXElement struct {
 	OpeningElement Node    `json:"openingElement"`
 	Children       []Node  `json:"children"`
 	ClosingElement Node    `json:"closingElement"`
+	*NodeComments
 }
 
 type JSXOpeningElement struct {
@@ -21,6 +22,7 @@ type JSXOpeningElement struct {
 	Name        Node    `json:"name"`
 	Attributes  []Node  `json:"attributes"`
 	SelfClosing bool    `json:"selfClosing"`
+	*NodeComments
 }
 
 type JSXIdentifier struct {
@@ -29,6 +31,7 @@ type JSXIdentifier struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Name  string  `json:"name"`
+	*NodeComments
 }
 
 type JSXNamespacedName struct {
@@ -38,6 +41,7 @@ type JSXNamespacedName struct {
 	Loc       *SrcLoc `json:"loc"`
 	Namespace string  `json:"namespace"`
 	Name      string  `json:"name"`
+	*NodeComments
 }
 
 type JSXMemberExpression struct {
@@ -47,6 +51,7 @@ type JSXMemberExpression struct {
 	Loc      *SrcLoc `json:"loc"`
 	Object   Node    `json:"object"`
 	Property Node    `json:"property"`
+	*NodeComments
 }
 
 type JSXClosingElement struct {
@@ -55,6 +60,7 @@ type JSXClosingElement struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Name  Node    `json:"name"`
+	*NodeComments
 }
 
 type JSXFragment struct {
@@ -65,6 +71,7 @@ type JSXFragment struct {
 	OpeningFragment Node    `json:"openingFragment"`
 	Children        []Node  `json:"children"`
 	ClosingFragment Node    `json:"closingFragment"`
+	*NodeComments
 }
 
 type JSXOpeningFragment struct {
@@ -72,6 +79,7 @@ type JSXOpeningFragment struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type JSXClosingFragment struct {
@@ -79,6 +87,7 @@ type JSXClosingFragment struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type JSXText struct {
@@ -88,6 +97,7 @@ type JSXText struct {
 	Loc   *SrcLoc `json:"loc"`
 	Value string  `json:"value"`
 	Raw   string  `json:"raw"`
+	*NodeComments
 }
 
 type JSXExpressionContainer struct {
@@ -96,6 +106,7 @@ type JSXExpressionContainer struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Expression Node    `json:"expression"`
+	*NodeComments
 }
 
 type JSXSpreadAttribute struct {
@@ -104,6 +115,7 @@ type JSXSpreadAttribute struct {
 	End      int        `json:"end"`
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 type JSXSpreadChild struct {
@@ -112,6 +124,7 @@ type JSXSpreadChild struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Expression Node    `json:"expression"`
+	*NodeComments
 }
 
 type JSXAttribute struct {
@@ -121,6 +134,7 @@ type JSXAttribute struct {
 	Loc   *SrcLoc `json:"loc"`
 	Name  Node    `json:"name"`
 	Value Node    `json:"value"`
+	*NodeComments
 }
 
 type JSXEmptyExpression struct {
@@ -128,4 +142,5 @@ type JSXEmptyExpression struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
@@ -16,12 +16,15 @@ type SrcLoc struct {
 
 // https://github.com/estree/estree/blob/master/es5.md#programs
 type Program struct {
-	Type       string  `json:"type"`
-	Start      int     `json:"start"`
-	End        int     `json:"end"`
-	Loc        *SrcLoc `json:"loc"`
-	SourceType string  `json:"sourceType"` // "script" | "module"
-	Body       []Node  `json:"body"`       // [ Directive | Statement ]
+	Type       string     `json:"type"`
+	Start      int        `json:"start"`
+	End        int        `json:"end"`
+	Loc        *SrcLoc    `json:"loc"`
+	SourceType string     `json:"sourceType"` // "script" | "module"
+	Body       []Node     `json:"body"`       // [ Directive | Statement ]
+	Comments   []*Comment `json:"comments,omitempty"`
+	Tokens     []*Token   `json:"tokens,omitempty"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#identifier
@@ -31,6 +34,7 @@ type Identifier struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Name  string  `json:"name"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#literal
@@ -41,6 +45,7 @@ type Literal struct {
 	Loc   *SrcLoc     `json:"loc"`
 	Value interface{} `json:"value"` // string | boolean | null | number | RegExp | bigint(es2020)
 	Raw   string      `json:"raw"`
+	*NodeComments
 }
 
 type Regexp struct {
@@ -50,12 +55,15 @@ type Regexp struct {
 
 // https://github.com/estree/estree/blob/master/es5.md#regexpliteral
 type RegExpLiteral struct {
-	Type   string      `json:"type"`
-	Start  int         `json:"start"`
-	End    int         `json:"end"`
-	Loc    *SrcLoc     `json:"loc"`
-	Value  interface{} `json:"value"`
-	Regexp *Regexp     `json:"regexp"`
+	Type  string      `json:"type"`
+	Start int         `json:"start"`
+	End   int         `json:"end"`
+	Loc   *SrcLoc     `json:"loc"`
+	Value interface{} `json:"value"`
+	Regex *Regexp     `json:"regex"`
+	// the same as `Regex`, it's kept for the compatibility with the prior versions of mole
+	Regexp *Regexp `json:"regexp"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2020.md#bigintliteral
@@ -67,6 +75,7 @@ type BigIntLiteral struct {
 	Value  interface{} `json:"value"` // string | boolean | null | number | RegExp | bigint(es2020)
 	Raw    string      `json:"raw"`
 	Bigint string      `json:"bigint"`
+	*NodeComments
 }
 
 type Expression interface{}
@@ -80,6 +89,7 @@ type ExpressionStatement struct {
 	End        int        `json:"end"`
 	Loc        *SrcLoc    `json:"loc"`
 	Expression Expression `json:"expression"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#directive
@@ -90,6 +100,7 @@ type Directive struct {
 	Loc        *SrcLoc    `json:"loc"`
 	Expression Expression `json:"expression"`
 	Directive  string     `json:"directive"`
+	*NodeComments
 }
 
 type EmptyStatement struct {
@@ -97,6 +108,7 @@ type EmptyStatement struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type DebuggerStatement struct {
@@ -104,6 +116,7 @@ type DebuggerStatement struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#blockstatement
@@ -113,6 +126,7 @@ type BlockStatement struct {
 	End   int         `json:"end"`
 	Loc   *SrcLoc     `json:"loc"`
 	Body  []Statement `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#withstatement
@@ -123,6 +137,7 @@ type WithStatement struct {
 	Loc    *SrcLoc    `json:"loc"`
 	Object Expression `json:"object"`
 	Body   Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#returnstatement
@@ -132,6 +147,7 @@ type ReturnStatement struct {
 	End      int        `json:"end"`
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#labeledstatement
@@ -142,6 +158,7 @@ type LabeledStatement struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Label Expression `json:"label"`
 	Body  Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#breakstatement
@@ -151,6 +168,7 @@ type BreakStatement struct {
 	End   int        `json:"end"`
 	Loc   *SrcLoc    `json:"loc"`
 	Label Expression `json:"label"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#continuestatement
@@ -160,6 +178,7 @@ type ContinueStatement struct {
 	End   int        `json:"end"`
 	Loc   *SrcLoc    `json:"loc"`
 	Label Expression `json:"label"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#ifstatement
@@ -171,6 +190,7 @@ type IfStatement struct {
 	Test       Expression `json:"test"`
 	Consequent Statement  `json:"consequent"`
 	Alternate  Statement  `json:"alternate"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#switchstatement
@@ -181,6 +201,7 @@ type SwitchStatement struct {
 	Loc          *SrcLoc       `json:"loc"`
 	Discriminant Expression    `json:"discriminant"`
 	Cases        []*SwitchCase `json:"cases"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#switchcase
@@ -191,6 +212,7 @@ type SwitchCase struct {
 	Loc        *SrcLoc     `json:"loc"`
 	Test       Expression  `json:"test"`
 	Consequent []Statement `json:"consequent"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#throwstatement
@@ -200,6 +222,7 @@ type ThrowStatement struct {
 	End      int        `json:"end"`
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#trystatement
@@ -211,6 +234,7 @@ type TryStatement struct {
 	Block     Statement  `json:"block"`
 	Handler   Expression `json:"handler"`
 	Finalizer Statement  `json:"finalizer"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#catchclause
@@ -221,6 +245,7 @@ type CatchClause struct {
 	Loc   *SrcLoc   `json:"loc"`
 	Param Pattern   `json:"param"` // `Pattern | null` from es2019
 	Body  Statement `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#whilestatement
@@ -231,6 +256,7 @@ type WhileStatement struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Test  Expression `json:"test"`
 	Body  Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#dowhilestatement
@@ -241,6 +267,7 @@ type DoWhileStatement struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Test  Expression `json:"test"`
 	Body  Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#forstatement
@@ -253,6 +280,7 @@ type ForStatement struct {
 	Test   Expression `json:"test"`
 	Update Expression `json:"update"`
 	Body   Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#forinstatement
@@ -264,6 +292,7 @@ type ForInStatement struct {
 	Left  Node       `json:"left"`
 	Right Expression `json:"right"`
 	Body  Statement  `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#forofstatement
@@ -276,6 +305,7 @@ type ForOfStatement struct {
 	Right Expression `json:"right"`
 	Body  Statement  `json:"body"`
 	Await bool       `json:"await"`
+	*NodeComments
 }
 
 type Declaration interface{}
@@ -291,6 +321,7 @@ type FunctionDeclaration struct {
 	Body      Node    `json:"body"`
 	Generator bool    `json:"generator"`
 	Async     bool    `json:"async"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#variabledeclaration
@@ -301,6 +332,7 @@ type VariableDeclaration struct {
 	Loc          *SrcLoc               `json:"loc"`
 	Kind         string                `json:"kind"`
 	Declarations []*VariableDeclarator `json:"declarations"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#variabledeclarator
@@ -311,6 +343,10 @@ type VariableDeclarator struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Id    Pattern    `json:"id"`
 	Init  Expression `json:"init"`
+
+	// the definite assignment assertion of typescript `let x!: number`
+	Definite bool `json:"definite,omitempty"`
+	*NodeComments
 }
 
 type ThisExpression struct {
@@ -318,6 +354,7 @@ type ThisExpression struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#arrayexpression
@@ -327,6 +364,7 @@ type ArrayExpression struct {
 	End      int          `json:"end"`
 	Loc      *SrcLoc      `json:"loc"`
 	Elements []Expression `json:"elements"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#objectexpression
@@ -336,6 +374,7 @@ type ObjectExpression struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Properties []Node  `json:"properties"` // Property | SpreadElement
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#functionexpression
@@ -350,6 +389,7 @@ type FunctionExpression struct {
 	Generator  bool    `json:"generator"`
 	Async      bool    `json:"async"`
 	Expression bool    `json:"expression"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#unaryexpression
@@ -361,6 +401,7 @@ type UnaryExpression struct {
 	Operator string     `json:"operator"` //  "-" | "+" | "!" | "~" | "typeof" | "void" | "delete"
 	Prefix   bool       `json:"prefix"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#updateexpression
@@ -372,6 +413,7 @@ type UpdateExpression struct {
 	Operator string     `json:"operator"` // "++" | "--"
 	Argument Expression `json:"argument"`
 	Prefix   bool       `json:"prefix"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#binaryexpression
@@ -383,6 +425,7 @@ type BinaryExpression struct {
 	Operator string     `json:"operator"`
 	Left     Expression `json:"left"`
 	Right    Expression `json:"right"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#assignmentexpression
@@ -394,6 +437,7 @@ type AssignmentExpression struct {
 	Operator string     `json:"operator"`
 	Left     Node       `json:"left"`
 	Right    Expression `json:"right"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#logicalexpression
@@ -405,6 +449,7 @@ type LogicalExpression struct {
 	Operator string     `json:"operator"`
 	Left     Expression `json:"left"`
 	Right    Expression `json:"right"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#memberexpression
@@ -417,6 +462,7 @@ type MemberExpression struct {
 	Property Expression `json:"property"`
 	Computed bool       `json:"computed"`
 	Optional bool       `json:"optional"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#conditionalexpression
@@ -428,6 +474,7 @@ type ConditionalExpression struct {
 	Test       Expression `json:"test"`
 	Consequent Expression `json:"consequent"`
 	Alternate  Expression `json:"alternate"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#callexpression
@@ -439,6 +486,7 @@ type CallExpression struct {
 	Callee    Expression   `json:"callee"`
 	Arguments []Expression `json:"arguments"`
 	Optional  bool         `json:"optional"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#newexpression
@@ -449,6 +497,7 @@ type NewExpression struct {
 	Loc       *SrcLoc      `json:"loc"`
 	Callee    Expression   `json:"callee"`
 	Arguments []Expression `json:"arguments"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es5.md#sequenceexpression
@@ -458,6 +507,7 @@ type SequenceExpression struct {
 	End         int          `json:"end"`
 	Loc         *SrcLoc      `json:"loc"`
 	Expressions []Expression `json:"expressions"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#expressions
@@ -466,6 +516,7 @@ type Super struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#expressions
@@ -475,6 +526,7 @@ type SpreadElement struct {
 	End      int        `json:"end"`
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#arrowfunctionexpression
@@ -489,6 +541,7 @@ type ArrowFunctionExpression struct {
 	Generator  bool        `json:"generator"`
 	Async      bool        `json:"async"`
 	Expression bool        `json:"expression"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#yieldexpression
@@ -499,6 +552,7 @@ type YieldExpression struct {
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
 	Delegate bool       `json:"delegate"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2017.md#awaitexpression
@@ -508,6 +562,7 @@ type AwaitExpression struct {
 	End      int        `json:"end"`
 	Loc      *SrcLoc    `json:"loc"`
 	Argument Expression `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#templateliteral
@@ -518,6 +573,7 @@ type TemplateLiteral struct {
 	Loc         *SrcLoc      `json:"loc"`
 	Quasis      []Expression `json:"quasis"`
 	Expressions []Expression `json:"expressions"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#taggedtemplateexpression
@@ -528,6 +584,7 @@ type TaggedTemplateExpression struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Tag   Expression `json:"tag"`
 	Quasi Expression `json:"quasi"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#templateelement
@@ -538,6 +595,7 @@ type TemplateElement struct {
 	Loc   *SrcLoc               `json:"loc"`
 	Tail  bool                  `json:"tail"`
 	Value *TemplateElementValue `json:"value"`
+	*NodeComments
 }
 
 type TemplateElementValue struct {
@@ -552,15 +610,18 @@ type ChainExpression struct {
 	End        int        `json:"end"`
 	Loc        *SrcLoc    `json:"loc"`
 	Expression Expression `json:"expression"` // CallExpression | MemberExpression
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2020.md#importexpression
 type ImportExpression struct {
-	Type   string     `json:"type"`
-	Start  int        `json:"start"`
-	End    int        `json:"end"`
-	Loc    *SrcLoc    `json:"loc"`
-	Source Expression `json:"source"`
+	Type    string     `json:"type"`
+	Start   int        `json:"start"`
+	End     int        `json:"end"`
+	Loc     *SrcLoc    `json:"loc"`
+	Source  Expression `json:"source"`
+	Options Expression `json:"options"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#patterns
@@ -578,6 +639,7 @@ type Property struct {
 	Method    bool       `json:"method"`
 	Shorthand bool       `json:"shorthand"`
 	Computed  bool       `json:"computed"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#objectpattern
@@ -592,6 +654,7 @@ type AssignmentProperty struct {
 	Computed  bool       `json:"computed"`
 	Value     Pattern    `json:"value"`
 	Kind      string     `json:"kind"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#objectpattern
@@ -601,6 +664,7 @@ type ObjectPattern struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Properties []Node  `json:"properties"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#arraypattern
@@ -610,6 +674,7 @@ type ArrayPattern struct {
 	End      int     `json:"end"`
 	Loc      *SrcLoc `json:"loc"`
 	Elements []Node  `json:"elements"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#restelement
@@ -619,6 +684,7 @@ type RestElement struct {
 	End      int     `json:"end"`
 	Loc      *SrcLoc `json:"loc"`
 	Argument Pattern `json:"argument"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#assignmentpattern
@@ -629,6 +695,7 @@ type AssignmentPattern struct {
 	Loc   *SrcLoc `json:"loc"`
 	Left  Node    `json:"left"`
 	Right Node    `json:"right"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#classbody
@@ -638,6 +705,7 @@ type ClassBody struct {
 	End   int          `json:"end"`
 	Loc   *SrcLoc      `json:"loc"`
 	Body  []Expression `json:"body"` // MethodDefinition | PropertyDefinition | StaticBlock
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#methoddefinition
@@ -652,6 +720,7 @@ type MethodDefinition struct {
 	Computed   bool       `json:"computed"`
 	Static     bool       `json:"static"`
 	Decorators []Node     `json:"decorators"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2022.md#propertydefinition
@@ -665,6 +734,7 @@ type PropertyDefinition struct {
 	Computed   bool       `json:"computed"`
 	Static     bool       `json:"static"`
 	Decorators []Node     `json:"decorators"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#classdeclaration
@@ -679,6 +749,7 @@ type ClassDeclaration struct {
 	Abstract   bool       `json:"abstract"`
 	Declare    bool       `json:"declare"`
 	Decorators []Node     `json:"decorators"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#classexpression
@@ -691,6 +762,8 @@ type ClassExpression struct {
 	SuperClass Expression `json:"superClass"`
 	Body       Expression `json:"body"`
 	Abstract   bool       `json:"abstract"`
+	Decorators []Node     `json:"decorators"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2022.md#privateidentifier
@@ -700,6 +773,7 @@ type PrivateIdentifier struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Name  string  `json:"name"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2022.md#staticblock
@@ -709,6 +783,7 @@ type StaticBlock struct {
 	End   int         `json:"end"`
 	Loc   *SrcLoc     `json:"loc"`
 	Body  []Statement `json:"body"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#metaproperty
@@ -719,6 +794,7 @@ type MetaProperty struct {
 	Loc      *SrcLoc    `json:"loc"`
 	Meta     Expression `json:"meta"`
 	Property Expression `json:"property"`
+	*NodeComments
 }
 
 type ModuleDeclaration interface{}
@@ -731,7 +807,27 @@ type ImportDeclaration struct {
 	Loc        *SrcLoc    `json:"loc"`
 	Specifiers []Node     `json:"specifiers"` // [ ImportSpecifier | ImportDefaultSpecifier | ImportNamespaceSpecifier ]
 	Source     Expression `json:"source"`
+	Attributes []Node     `json:"attributes"` // [ ImportAttribute ]
 	ImportKind string     `json:"importKind"`
+	Extra      *ModExtra  `json:"extra,omitempty"`
+	*NodeComments
+}
+
+// https://github.com/estree/estree/blob/master/es2025.md#importattribute
+type ImportAttribute struct {
+	Type  string     `json:"type"`
+	Start int        `json:"start"`
+	End   int        `json:"end"`
+	Loc   *SrcLoc    `json:"loc"`
+	Key   Expression `json:"key"` // Identifier | Literal
+	Value Expression `json:"value"`
+	*NodeComments
+}
+
+// the `extra` of the module declarations, `DeprecatedAssertSyntax` is `true` if the attributes
+// are leading by the legacy keyword `assert`
+type ModExtra struct {
+	DeprecatedAssertSyntax bool `json:"deprecatedAssertSyntax"`
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#importspecifier
@@ -743,6 +839,7 @@ type ImportSpecifier struct {
 	Local      Node    `json:"local"`
 	Imported   Node    `json:"imported"`
 	ImportKind string  `json:"importKind"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#importdefaultspecifier
@@ -752,6 +849,7 @@ type ImportDefaultSpecifier struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Local Node    `json:"local"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#importnamespacespecifier
@@ -761,6 +859,7 @@ type ImportNamespaceSpecifier struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Local Node    `json:"local"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#exportnameddeclaration
@@ -772,7 +871,10 @@ type ExportNamedDeclaration struct {
 	Declaration Declaration `json:"declaration"` // Declaration | null
 	Specifiers  []Node      `json:"specifiers"`
 	Source      Expression  `json:"source"` // Literal | null
+	Attributes  []Node      `json:"attributes"`
 	ExportKind  string      `json:"exportKind"`
+	Extra       *ModExtra   `json:"extra,omitempty"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#exportspecifier
@@ -784,6 +886,7 @@ type ExportSpecifier struct {
 	Local      Expression `json:"local"`
 	Exported   Expression `json:"exported"`
 	ExportKind string     `json:"exportKind"`
+	*NodeComments
 }
 
 type ExportDefaultDeclaration struct {
@@ -794,16 +897,21 @@ type ExportDefaultDeclaration struct {
 
 	// AnonymousDefaultExportedFunctionDeclaration | FunctionDeclaration | AnonymousDefaultExportedClassDeclaration | ClassDeclaration | Expression
 	Declaration Node `json:"declaration"`
+	*NodeComments
 }
 
 // https://github.com/estree/estree/blob/master/es2015.md#exportalldeclaration
 type ExportAllDeclaration struct {
-	Type     string     `json:"type"`
-	Start    int        `json:"start"`
-	End      int        `json:"end"`
-	Loc      *SrcLoc    `json:"loc"`
-	Exported Expression `json:"exported"`
-	Source   Expression `json:"source"`
+	Type       string     `json:"type"`
+	Start      int        `json:"start"`
+	End        int        `json:"end"`
+	Loc        *SrcLoc    `json:"loc"`
+	Exported   Expression `json:"exported"`
+	Source     Expression `json:"source"`
+	Attributes []Node     `json:"attributes"`
+	ExportKind string     `json:"exportKind"`
+	Extra      *ModExtra  `json:"extra,omitempty"`
+	*NodeComments
 }
 
 type Decorator struct {
@@ -812,4 +920,5 @@ type Decorator struct {
 	End        int        `json:"end"`
 	Loc        *SrcLoc    `json:"loc"`
 	Expression Expression `json:"expression"`
+	*NodeComments
 }
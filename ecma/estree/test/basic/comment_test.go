@@ -0,0 +1,149 @@
+package estree_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/estree"
+	. "github.com/hsiaosiyuan0/mole/ecma/estree/test"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func compileWithCmts(t *testing.T, code string) string {
+	p := NewParser(code, nil)
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ctx := estree.NewConvertCtx(p)
+	ctx.Comments = true
+	b, err := json.Marshal(estree.ConvertProg(ast.(*parser.Prog), ctx))
+	AssertEqual(t, nil, err, "should be ok")
+	return string(b)
+}
+
+func TestComments(t *testing.T) {
+	ast := compileWithCmts(t, "/* a */\nlet x = { // b\n  y: 1 /* c */ }\nf(/* d */)")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "body": [
+    {
+      "type": "VariableDeclaration",
+      "declarations": [
+        {
+          "type": "VariableDeclarator",
+          "init": {
+            "type": "ObjectExpression",
+            "properties": [
+              {
+                "type": "Property",
+                "leadingComments": [
+                  {
+                    "type": "Line",
+                    "start": 18,
+                    "end": 22,
+                    "value": " b"
+                  }
+                ],
+                "trailingComments": [
+                  {
+                    "type": "Block",
+                    "start": 30,
+                    "end": 37,
+                    "value": " c "
+                  }
+                ]
+              }
+            ]
+          }
+        }
+      ],
+      "leadingComments": [
+        {
+          "type": "Block",
+          "start": 0,
+          "end": 7,
+          "loc": {
+            "start": {
+              "line": 1,
+              "column": 0
+            },
+            "end": {
+              "line": 1,
+              "column": 7
+            }
+          },
+          "value": " a "
+        }
+      ]
+    },
+    {
+      "type": "ExpressionStatement",
+      "expression": {
+        "type": "CallExpression",
+        "innerComments": [
+          {
+            "type": "Block",
+            "start": 42,
+            "end": 49,
+            "value": " d "
+          }
+        ]
+      }
+    }
+  ],
+  "comments": [
+    {
+      "type": "Block",
+      "value": " a "
+    },
+    {
+      "type": "Line",
+      "value": " b"
+    },
+    {
+      "type": "Block",
+      "value": " c "
+    },
+    {
+      "type": "Block",
+      "value": " d "
+    }
+  ]
+}
+`, ast)
+}
+
+func TestCommentsDisabled(t *testing.T) {
+	ast, err := Compile("/* a */ f()")
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, false, strings.Contains(ast, "omments"), "should be ok")
+}
+
+func TestHashbangComment(t *testing.T) {
+	ast := compileWithCmts(t, "#!/usr/bin/env node\nlet a = 1")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "start": 0,
+  "body": [
+    {
+      "type": "VariableDeclaration",
+      "start": 20,
+      "leadingComments": [
+        {
+          "type": "Line",
+          "start": 0,
+          "end": 19,
+          "value": "/usr/bin/env node"
+        }
+      ]
+    }
+  ]
+}
+`, ast)
+}
@@ -28,7 +28,9 @@ func TestDynamicImportFail4(t *testing.T) {
 }
 
 func TestDynamicImportFail5(t *testing.T) {
-	TestFail(t, "import(a, b)", "Unexpected token `,` at (1:8)", nil)
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.Off(parser.FEAT_IMPORT_ATTRS)
+	TestFail(t, "import(a, b)", "Unexpected token `,` at (1:8)", opts)
 }
 
 func TestDynamicImportFail6(t *testing.T) {
@@ -36,7 +38,9 @@ func TestDynamicImportFail6(t *testing.T) {
 }
 
 func TestDynamicImportFail7(t *testing.T) {
-	TestFail(t, "import(source,)", "Unexpected token `,` at (1:13)", nil)
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.Off(parser.FEAT_IMPORT_ATTRS)
+	TestFail(t, "import(source,)", "Unexpected token `,` at (1:13)", opts)
 }
 
 func TestDynamicImportFail8(t *testing.T) {
@@ -46,3 +50,7 @@ func TestDynamicImportFail8(t *testing.T) {
 func TestDynamicImportFail9(t *testing.T) {
 	TestFail(t, "(import)(s)", "Unexpected token `)` at (1:7)", nil)
 }
+
+func TestDynamicImportFail10(t *testing.T) {
+	TestFail(t, "import(a, b, c)", "`import()` requires exactly one or two arguments at (1:13)", nil)
+}
@@ -0,0 +1,214 @@
+package estree_test
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/ecma/estree/test"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+// Ergonomic Brand Checks
+func TestES2022PrivateIn1(t *testing.T) {
+	ast, err := Compile("class A { #x; m(o) { return #x in o } }")
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "start": 0,
+  "end": 39,
+  "body": [
+    {
+      "type": "ClassDeclaration",
+      "body": {
+        "type": "ClassBody",
+        "body": [
+          {
+            "type": "PropertyDefinition",
+            "key": {
+              "type": "PrivateIdentifier",
+              "start": 10,
+              "end": 12,
+              "name": "x"
+            }
+          },
+          {
+            "type": "MethodDefinition",
+            "value": {
+              "type": "FunctionExpression",
+              "body": {
+                "type": "BlockStatement",
+                "body": [
+                  {
+                    "type": "ReturnStatement",
+                    "start": 21,
+                    "end": 35,
+                    "argument": {
+                      "type": "BinaryExpression",
+                      "start": 28,
+                      "end": 35,
+                      "operator": "in",
+                      "left": {
+                        "type": "PrivateIdentifier",
+                        "start": 28,
+                        "end": 30,
+                        "name": "x"
+                      },
+                      "right": {
+                        "type": "Identifier",
+                        "start": 34,
+                        "end": 35,
+                        "name": "o"
+                      }
+                    }
+                  }
+                ]
+              }
+            }
+          }
+        ]
+      }
+    }
+  ]
+}
+`, ast)
+}
+
+func TestES2022PrivateInFail1(t *testing.T) {
+	TestFail(t, "class A { #x; m(o) { return 1 + #x in o } }", "Unexpected private field at (1:32)", nil)
+}
+
+func TestES2022PrivateInFail2(t *testing.T) {
+	TestFail(t, "class A { #x; m() { #x } }", "Unexpected token `private identifier` at (1:20)", nil)
+}
+
+func TestES2022PrivateInFail3(t *testing.T) {
+	TestFail(t, "class A { m(o) { return #x in o } }",
+		"Private field `#x` must be declared in an enclosing class at (1:24)", nil)
+}
+
+// Arbitrary Module Namespace Names
+func TestES2022ModuleStrName1(t *testing.T) {
+	ast, err := Compile(`import { "a b" as c } from "m"`)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "start": 0,
+  "end": 30,
+  "body": [
+    {
+      "type": "ImportDeclaration",
+      "start": 0,
+      "end": 30,
+      "specifiers": [
+        {
+          "type": "ImportSpecifier",
+          "start": 9,
+          "end": 19,
+          "imported": {
+            "type": "Literal",
+            "start": 9,
+            "end": 14,
+            "value": "a b",
+            "raw": "\"a b\""
+          },
+          "local": {
+            "type": "Identifier",
+            "start": 18,
+            "end": 19,
+            "name": "c"
+          }
+        }
+      ],
+      "source": {
+        "type": "Literal",
+        "start": 27,
+        "end": 30,
+        "value": "m"
+      }
+    }
+  ]
+}
+`, ast)
+}
+
+func TestES2022ModuleStrName2(t *testing.T) {
+	ast, err := Compile(`let a; export { a as "b c" }; export { "d" as "e" } from "m"; export * as "f" from "m"`)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "body": [
+    {
+      "type": "VariableDeclaration"
+    },
+    {
+      "type": "ExportNamedDeclaration",
+      "specifiers": [
+        {
+          "type": "ExportSpecifier",
+          "local": {
+            "type": "Identifier",
+            "name": "a"
+          },
+          "exported": {
+            "type": "Literal",
+            "start": 21,
+            "end": 26,
+            "value": "b c"
+          }
+        }
+      ]
+    },
+    {
+      "type": "ExportNamedDeclaration",
+      "specifiers": [
+        {
+          "type": "ExportSpecifier",
+          "local": {
+            "type": "Literal",
+            "value": "d"
+          },
+          "exported": {
+            "type": "Literal",
+            "value": "e"
+          }
+        }
+      ]
+    },
+    {
+      "type": "ExportAllDeclaration",
+      "exported": {
+        "type": "Literal",
+        "value": "f"
+      }
+    }
+  ]
+}
+`, ast)
+}
+
+func TestES2022ModuleStrNameFail1(t *testing.T) {
+	TestFail(t, `export { "a" }`, "A string literal cannot be used as an exported binding without `from` at (1:9)", nil)
+}
+
+func TestES2022ModuleStrNameFail2(t *testing.T) {
+	TestFail(t, `import { "a" } from "m"`, "Unexpected token `}` at (1:13)", nil)
+}
+
+func TestES2022ModuleStrNameFail3(t *testing.T) {
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.Off(parser.FEAT_MODULE_STR_NAME)
+	TestFail(t, `export { "a" } from "m"`, "Unexpected token `string` at (1:9)", opts)
+}
+
+// Class Static Block
+func TestES2022StaticBlockFail1(t *testing.T) {
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.Off(parser.FEAT_CLASS_STATIC_BLOCK)
+	TestFail(t, "class A { static { } }", "Unexpected token `{` at (1:17)", opts)
+}
@@ -0,0 +1,70 @@
+package estree_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/estree"
+	. "github.com/hsiaosiyuan0/mole/ecma/estree/test"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+// JSON modules
+func TestJson1(t *testing.T) {
+	p := NewParser(`{ "a": [1, -2] }`, nil)
+	node, err := p.Json()
+	AssertEqual(t, nil, err, "should be ok")
+
+	b, err := json.Marshal(estree.Convert(node, estree.NewConvertCtx(p)))
+	AssertEqual(t, nil, err, "should be ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "ObjectExpression",
+  "start": 0,
+  "end": 16,
+  "properties": [
+    {
+      "type": "Property",
+      "start": 2,
+      "end": 14,
+      "key": {
+        "type": "Literal",
+        "start": 2,
+        "end": 5,
+        "value": "a"
+      },
+      "value": {
+        "type": "ArrayExpression",
+        "start": 7,
+        "end": 14,
+        "elements": [
+          {
+            "type": "Literal",
+            "start": 8,
+            "end": 9,
+            "value": 1
+          },
+          {
+            "type": "UnaryExpression",
+            "start": 11,
+            "end": 13,
+            "operator": "-",
+            "argument": {
+              "type": "Literal",
+              "start": 12,
+              "end": 13,
+              "value": 2
+            }
+          }
+        ]
+      },
+      "kind": "init",
+      "computed": false,
+      "shorthand": false,
+      "method": false
+    }
+  ]
+}
+`, string(b))
+}
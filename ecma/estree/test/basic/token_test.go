@@ -0,0 +1,97 @@
+package estree_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/estree"
+	. "github.com/hsiaosiyuan0/mole/ecma/estree/test"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func compileWithToks(t *testing.T, code string) string {
+	opts := parser.NewParserOpts()
+	opts.Tokens = true
+	p := NewParser(code, opts)
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ctx := estree.NewConvertCtx(p)
+	b, err := json.Marshal(estree.ConvertProg(ast.(*parser.Prog), ctx))
+	AssertEqual(t, nil, err, "should be ok")
+	return string(b)
+}
+
+func TestTokens(t *testing.T) {
+	ast := compileWithToks(t, "let a = /b/g.test(`c${d}`) / 2 // e")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "tokens": [
+    { "type": "Keyword", "value": "let", "start": 0, "end": 3 },
+    { "type": "Identifier", "value": "a", "start": 4, "end": 5 },
+    { "type": "Punctuator", "value": "=", "start": 6, "end": 7 },
+    {
+      "type": "RegularExpression",
+      "value": "/b/g",
+      "start": 8,
+      "end": 12,
+      "regex": { "pattern": "b", "flags": "g" }
+    },
+    { "type": "Punctuator", "value": "." },
+    { "type": "Identifier", "value": "test" },
+    { "type": "Punctuator", "value": "(" },
+    { "type": "Template", "value": "`+"`c${"+`" },
+    { "type": "Identifier", "value": "d" },
+    { "type": "Template", "value": "}`+"`"+`" },
+    { "type": "Punctuator", "value": ")" },
+    { "type": "Punctuator", "value": "/" },
+    {
+      "type": "Numeric",
+      "value": "2",
+      "loc": {
+        "start": { "line": 1, "column": 29 },
+        "end": { "line": 1, "column": 30 }
+      }
+    }
+  ]
+}
+`, ast)
+}
+
+func TestTokensJSX(t *testing.T) {
+	ast := compileWithToks(t, "<a b={c}>d</a>")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "tokens": [
+    { "type": "Punctuator", "value": "<" },
+    { "type": "JSXIdentifier", "value": "a" },
+    { "type": "JSXIdentifier", "value": "b" },
+    { "type": "Punctuator", "value": "=" },
+    { "type": "Punctuator", "value": "{" },
+    { "type": "Identifier", "value": "c" },
+    { "type": "Punctuator", "value": "}" },
+    { "type": "Punctuator", "value": ">" },
+    { "type": "JSXText", "value": "d" },
+    { "type": "Punctuator", "value": "<" },
+    { "type": "Punctuator", "value": "/" },
+    { "type": "JSXIdentifier", "value": "a" },
+    { "type": "Punctuator", "value": ">" }
+  ]
+}
+`, ast)
+}
+
+func TestTokensDisabled(t *testing.T) {
+	ast, err := Compile("let a = 1")
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	obj := map[string]interface{}{}
+	json.Unmarshal([]byte(ast), &obj)
+	_, ok := obj["tokens"]
+	AssertEqual(t, false, ok, "should be ok")
+}
@@ -0,0 +1,109 @@
+package estree_test
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/ecma/estree/test"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+// Explicit Resource Management
+func TestUsing1(t *testing.T) {
+	ast, err := Compile("{ using a = b(), c = d }")
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "start": 0,
+  "end": 24,
+  "body": [
+    {
+      "type": "BlockStatement",
+      "body": [
+        {
+          "type": "VariableDeclaration",
+          "start": 2,
+          "end": 22,
+          "kind": "using",
+          "declarations": [
+            {
+              "type": "VariableDeclarator",
+              "start": 8,
+              "end": 15,
+              "id": {
+                "type": "Identifier",
+                "name": "a"
+              },
+              "init": {
+                "type": "CallExpression",
+                "callee": {
+                  "type": "Identifier",
+                  "name": "b"
+                }
+              }
+            },
+            {
+              "type": "VariableDeclarator",
+              "start": 17,
+              "end": 22,
+              "id": {
+                "type": "Identifier",
+                "name": "c"
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+  `, ast)
+}
+
+func TestUsing2(t *testing.T) {
+	ast, err := Compile("async function f() { for (await using x of y); }")
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	AssertEqualJson(t, `
+{
+  "type": "Program",
+  "body": [
+    {
+      "type": "FunctionDeclaration",
+      "body": {
+        "type": "BlockStatement",
+        "body": [
+          {
+            "type": "ForOfStatement",
+            "start": 21,
+            "end": 46,
+            "await": false,
+            "left": {
+              "type": "VariableDeclaration",
+              "start": 26,
+              "end": 39,
+              "kind": "await using",
+              "declarations": [
+                {
+                  "type": "VariableDeclarator",
+                  "id": {
+                    "type": "Identifier",
+                    "name": "x"
+                  },
+                  "init": null
+                }
+              ]
+            },
+            "right": {
+              "type": "Identifier",
+              "name": "y"
+            }
+          }
+        ]
+      }
+    }
+  ]
+}
+  `, ast)
+}
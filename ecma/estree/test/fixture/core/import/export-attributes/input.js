@@ -0,0 +1,2 @@
+export { default as x } from "./foo.json" with { type: "json" };
+export * as ns from "./bar.json" with { type: "json" };
@@ -0,0 +1 @@
+import json from "./foo.json" assert { type: "json" };
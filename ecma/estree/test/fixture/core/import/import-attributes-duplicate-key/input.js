@@ -0,0 +1 @@
+import json from "./foo.json" with { type: "json", "type": "json" };
@@ -0,0 +1,2 @@
+import json from "./foo.json" with { type: "json" };
+import "./bar.css" with { "type": "css", lazy: "true" };
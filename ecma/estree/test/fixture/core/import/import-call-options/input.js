@@ -0,0 +1,2 @@
+import("./foo.json", { with: { type: "json" } });
+import("./bar.json",);
@@ -0,0 +1 @@
+const re = /(?<a>x)(?<a>y)/;
@@ -0,0 +1 @@
+const re = /[\p{L}--[a-z]]/v;
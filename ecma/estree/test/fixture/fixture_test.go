@@ -24,6 +24,12 @@ func TestFixture_ts(t *testing.T) {
 	RunFixtures(t, "typescript", opts)
 }
 
+func TestFixture_flow(t *testing.T) {
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.On(parser.FEAT_FLOW).Off(parser.FEAT_JSX)
+	RunFixtures(t, "flow", opts)
+}
+
 func TestFixture_tsManually(t *testing.T) {
 	opts := parser.NewParserOpts()
 	opts.Feature = opts.Feature.On(parser.FEAT_TS)
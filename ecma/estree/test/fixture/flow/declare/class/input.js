@@ -0,0 +1 @@
+declare class A<T> extends B<T> implements C { m(): void; static x: number }
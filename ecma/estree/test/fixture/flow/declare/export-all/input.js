@@ -0,0 +1 @@
+declare export * from 'a';
@@ -0,0 +1 @@
+declare export default string;
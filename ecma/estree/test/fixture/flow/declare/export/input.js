@@ -0,0 +1 @@
+declare export function f(string): void;
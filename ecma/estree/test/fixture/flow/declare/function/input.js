@@ -0,0 +1 @@
+declare function f(a: string): void;
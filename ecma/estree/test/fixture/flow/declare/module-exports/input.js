@@ -0,0 +1 @@
+declare module "m" { declare module.exports: { a: number }; }
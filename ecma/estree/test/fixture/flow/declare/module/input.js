@@ -0,0 +1 @@
+declare module "m" { declare var a: number; }
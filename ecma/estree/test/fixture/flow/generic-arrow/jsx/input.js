@@ -0,0 +1 @@
+const f = <T>(x: T): T => x;
@@ -0,0 +1,2 @@
+import typeof A from "a";
+import { typeof B, type C, D } from "b";
@@ -0,0 +1 @@
+interface I<T> extends J<T> { m(): T; +p: string }
@@ -0,0 +1 @@
+type A = interface extends B { m(): void };
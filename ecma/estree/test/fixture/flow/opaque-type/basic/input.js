@@ -0,0 +1 @@
+opaque type A: S = T;
@@ -0,0 +1 @@
+declare opaque type C: string;
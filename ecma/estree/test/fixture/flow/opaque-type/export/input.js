@@ -0,0 +1 @@
+export opaque type B = number;
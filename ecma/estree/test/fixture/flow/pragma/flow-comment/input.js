@@ -0,0 +1,2 @@
+// @flow
+var a: number = 1;
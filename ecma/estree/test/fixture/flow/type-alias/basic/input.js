@@ -0,0 +1 @@
+type A<T> = ?Array<T> | string;
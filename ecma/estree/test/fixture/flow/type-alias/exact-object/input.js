@@ -0,0 +1 @@
+type A = {| +a: number, -b?: string, [k: string]: mixed |};
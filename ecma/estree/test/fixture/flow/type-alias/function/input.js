@@ -0,0 +1 @@
+type F = <T: Object>(string, b?: T, ...rest: Array<T>) => void;
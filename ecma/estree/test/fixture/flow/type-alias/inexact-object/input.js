@@ -0,0 +1 @@
+type A = { a: T, ... };
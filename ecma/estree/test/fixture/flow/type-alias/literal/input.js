@@ -0,0 +1 @@
+type L = 'a' | 1 | true | typeof x;
@@ -0,0 +1 @@
+type B = { ...A, (x: number): string };
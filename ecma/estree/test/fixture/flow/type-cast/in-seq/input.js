@@ -0,0 +1 @@
+const a = (b: any, c);
@@ -0,0 +1 @@
+abstract class A {}
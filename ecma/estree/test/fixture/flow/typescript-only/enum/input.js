@@ -0,0 +1 @@
+enum E {}
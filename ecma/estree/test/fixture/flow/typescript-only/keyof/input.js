@@ -0,0 +1 @@
+type A = keyof T
@@ -0,0 +1 @@
+namespace N {}
@@ -0,0 +1 @@
+class A<+T, -U> { +x: T; static -y: U = 1 }
@@ -0,0 +1 @@
+const t = [1, "a"] as const satisfies readonly unknown[];
@@ -0,0 +1,3 @@
+const a = { x: 1 } satisfies Rec;
+b satisfies unknown as T;
+(c satisfies any) = 1;
@@ -0,0 +1,3 @@
+class C {
+  accessor m() {}
+}
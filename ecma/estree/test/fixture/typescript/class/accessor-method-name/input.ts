@@ -0,0 +1,5 @@
+class A {
+  accessor() {}
+  static accessor() {}
+  static readonly() {}
+}
@@ -0,0 +1,3 @@
+class C {
+  readonly accessor x = 1;
+}
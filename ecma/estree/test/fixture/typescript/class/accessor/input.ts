@@ -0,0 +1,6 @@
+class C {
+  accessor x = 1;
+  static accessor #y: string;
+  private accessor z?: number;
+  accessor;
+}
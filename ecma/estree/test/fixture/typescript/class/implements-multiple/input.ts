@@ -0,0 +1 @@
+class C implements X, Y<T> {}
\ No newline at end of file
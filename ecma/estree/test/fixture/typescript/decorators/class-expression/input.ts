@@ -0,0 +1 @@
+const A = @foo class {}
@@ -0,0 +1,8 @@
+class C {
+  @foo [k]() {
+    return 1;
+  }
+  @bar.baz() get v() {
+    return 2;
+  }
+}
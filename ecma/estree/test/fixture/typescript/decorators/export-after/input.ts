@@ -0,0 +1,2 @@
+export @foo class A {}
+export default @bar class {}
@@ -0,0 +1 @@
+@foo export @bar class A {}
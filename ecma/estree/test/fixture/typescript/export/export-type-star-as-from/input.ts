@@ -0,0 +1 @@
+export type * as ns from "./mod";
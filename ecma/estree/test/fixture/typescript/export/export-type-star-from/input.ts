@@ -0,0 +1 @@
+export type * from "./mod";
@@ -0,0 +1,5 @@
+namespace A {
+  export namespace B {
+    export const c = 1;
+  }
+}
\ No newline at end of file
@@ -0,0 +1 @@
+type A<T = string> = T;
\ No newline at end of file
@@ -0,0 +1 @@
+const f = <const T,>(a: T) => a;
@@ -0,0 +1,2 @@
+function f<const T extends readonly unknown[]>(a: T): T { return a; }
+class C<const T> { m<const U, V>(u: U) {} }
@@ -0,0 +1 @@
+const f = <in T,>(x: T) => x;
@@ -0,0 +1 @@
+function f<in T>() {}
@@ -0,0 +1 @@
+class C { m<out T>() {} }
@@ -0,0 +1,3 @@
+interface I<in T, out U, in out V> {}
+type F<in out T> = (x: T) => T;
+class C<in T, out> {}
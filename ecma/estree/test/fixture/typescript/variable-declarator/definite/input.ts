@@ -0,0 +1 @@
+let x!: number;
\ No newline at end of file
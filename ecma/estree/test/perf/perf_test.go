@@ -29,45 +29,39 @@ func compileToESTree(code string, toEstree bool) error {
 	return nil
 }
 
-func BenchmarkParsingToESTree(t *testing.B) {
-	libs := []*struct {
-		name string
-		code string
-	}{
-		{"angular.js", ""},
-		{"backbone.js", ""},
-		{"ember.js", ""},
-		{"jquery.js", ""},
-		{"react-dom.js", ""},
-		{"react.js", ""},
-	}
-
-	_, fileName, _, _ := runtime.Caller(0)
-	for _, lib := range libs {
-		b, err := ioutil.ReadFile(filepath.Join(path.Dir(fileName), "asset", lib.name))
-		if err != nil {
-			t.Fatal(err)
-		}
-		lib.code = string(b)
+func BenchmarkParsingToESTree(b *testing.B) {
+	for _, lib := range loadLibs(b) {
+		code := lib.code
+		b.Run(lib.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := compileToESTree(code, true); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
 	}
+}
 
-	for _, lib := range libs {
-		t.Run(lib.name, func(b *testing.B) {
+func BenchmarkParsing(b *testing.B) {
+	for _, lib := range loadLibs(b) {
+		code := lib.code
+		b.Run(lib.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				err := compileToESTree(lib.code, true)
-				if err != nil {
-					t.Fatal(err)
+				if err := compileToESTree(code, false); err != nil {
+					b.Fatal(err)
 				}
 			}
 		})
 	}
 }
 
-func BenchmarkParsing(t *testing.B) {
-	libs := []*struct {
-		name string
-		code string
-	}{
+type lib struct {
+	name string
+	code string
+}
+
+func loadLibs(b *testing.B) []*lib {
+	libs := []*lib{
 		{"angular.js", ""},
 		{"backbone.js", ""},
 		{"ember.js", ""},
@@ -78,21 +72,78 @@ func BenchmarkParsing(t *testing.B) {
 
 	_, fileName, _, _ := runtime.Caller(0)
 	for _, lib := range libs {
-		b, err := ioutil.ReadFile(filepath.Join(path.Dir(fileName), "asset", lib.name))
+		code, err := ioutil.ReadFile(filepath.Join(path.Dir(fileName), "asset", lib.name))
 		if err != nil {
-			t.Fatal(err)
+			b.Fatal(err)
 		}
-		lib.code = string(b)
+		lib.code = string(code)
 	}
+	return libs
+}
 
-	for _, lib := range libs {
-		t.Run(lib.name, func(b *testing.B) {
-			for i := 0; i < b.N; i++ {
-				err := compileToESTree(lib.code, false)
-				if err != nil {
-					b.Fatal(err)
-				}
-			}
+// runs `parse` for `b.N` times and reports the GC cycles and the GC pauses besides the
+// allocations, for comparing the ways of parsing in the services which parse massively
+func benchGC(b *testing.B, parse func() error) {
+	b.ReportAllocs()
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	runtime.ReadMemStats(&after)
+	b.ReportMetric(float64(after.NumGC-before.NumGC)/float64(b.N), "gcs/op")
+	b.ReportMetric(float64(after.PauseTotalNs-before.PauseTotalNs)/float64(b.N), "gc-pause-ns/op")
+}
+
+// parses with a new parser every time as the baseline of the pooled ones
+func BenchmarkParsingNew(b *testing.B) {
+	for _, lib := range loadLibs(b) {
+		code := lib.code
+		b.Run(lib.name, func(b *testing.B) {
+			benchGC(b, func() error {
+				p := parser.NewParser(span.NewSource("", code), parser.NewParserOpts())
+				_, err := p.Prog()
+				return err
+			})
+		})
+	}
+}
+
+// parses with the parser reused by `Parser.Reset`
+func BenchmarkParsingReset(b *testing.B) {
+	for _, lib := range loadLibs(b) {
+		code := lib.code
+		b.Run(lib.name, func(b *testing.B) {
+			p := &parser.Parser{}
+			benchGC(b, func() error {
+				p.Reset(span.NewSource("", code), parser.NewParserOpts())
+				_, err := p.Prog()
+				return err
+			})
+		})
+	}
+}
+
+// parses with the parser reused by `Parser.Reset` and the nodes allocated from the arena which
+// is released after every parsing
+func BenchmarkParsingArena(b *testing.B) {
+	for _, lib := range loadLibs(b) {
+		code := lib.code
+		b.Run(lib.name, func(b *testing.B) {
+			p := &parser.Parser{}
+			opts := parser.NewParserOpts()
+			opts.Arena = parser.NewArena()
+			benchGC(b, func() error {
+				opts.Arena.Release()
+				p.Reset(span.NewSource("", code), opts)
+				_, err := p.Prog()
+				return err
+			})
 		})
 	}
 }
@@ -0,0 +1,85 @@
+package estree
+
+import (
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+)
+
+// the token in the `tokens` of the program, it follows the format of espree
+type Token struct {
+	Type  string  `json:"type"`
+	Value string  `json:"value"`
+	Start int     `json:"start"`
+	End   int     `json:"end"`
+	Loc   *SrcLoc `json:"loc"`
+	Regex *Regexp `json:"regex,omitempty"`
+}
+
+// the names which are the keywords in espree besides the reserved words
+var espreeKeywords = map[string]bool{
+	"in":     true,
+	"const":  true,
+	"let":    true,
+	"static": true,
+	"yield":  true,
+}
+
+func tokens(toks []parser.Token, ctx *ConvertCtx) []*Token {
+	ret := make([]*Token, 0, len(toks))
+	for i := range toks {
+		tok := &toks[i]
+		if tok.Val() == parser.T_COMMENT || tok.Val() == parser.T_WHITESPACE {
+			continue
+		}
+		ret = append(ret, token(tok, ctx))
+	}
+	return ret
+}
+
+func token(tok *parser.Token, ctx *ConvertCtx) *Token {
+	rng := tok.Range()
+	text := ctx.Parser.RngText(rng)
+	t := &Token{
+		Value: text,
+		Start: int(rng.Lo),
+		End:   int(rng.Hi),
+		Loc:   locOfRng(rng, ctx.Parser.Source(), ctx),
+	}
+
+	switch tok.Val() {
+	case parser.T_NULL:
+		t.Type = "Null"
+	case parser.T_TRUE, parser.T_FALSE:
+		t.Type = "Boolean"
+	case parser.T_NUM:
+		t.Type = "Numeric"
+	case parser.T_STRING:
+		t.Type = "String"
+	case parser.T_TPL_HEAD, parser.T_TPL_SPAN, parser.T_TPL_TAIL:
+		t.Type = "Template"
+	case parser.T_NAME_PVT:
+		t.Type = "PrivateIdentifier"
+		t.Value = text[1:]
+	case parser.T_JSX_TXT:
+		t.Type = "JSXText"
+	case parser.T_REGEXP:
+		t.Type = "RegularExpression"
+		re := tok.Regexp()
+		t.Regex = &Regexp{
+			Pattern: ctx.Parser.RngText(re.Pattern()),
+			Flags:   ctx.Parser.RngText(re.Flags()),
+		}
+	default:
+		if tok.IsJsxName() {
+			t.Type = "JSXIdentifier"
+		} else if tok.Val() == parser.T_NAME || tok.IsKw() {
+			if parser.IsKeyword(text) || espreeKeywords[text] {
+				t.Type = "Keyword"
+			} else {
+				t.Type = "Identifier"
+			}
+		} else {
+			t.Type = "Punctuator"
+		}
+	}
+	return t
+}
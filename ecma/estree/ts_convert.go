@@ -9,6 +9,12 @@ func ConvertTsTyp(node parser.Node, ctx *ConvertCtx) Node {
 		return nil
 	}
 
+	if ctx.flow() {
+		if n := flowConvert(node, ctx); n != nil {
+			return n
+		}
+	}
+
 	switch node.Type() {
 	case parser.N_NAME:
 		return ident(node, ctx)
@@ -157,6 +163,9 @@ func ConvertTsTyp(node parser.Node, ctx *ConvertCtx) Node {
 			Name:       Convert(n.Name(), ctx),
 			Constraint: Convert(n.Cons(), ctx),
 			Default:    Convert(n.Default(), ctx),
+			In:         n.In(),
+			Out:        n.Out(),
+			Const:      n.Const(),
 		}
 	case parser.N_TS_ARR:
 		n := node.(*parser.TsArr)
@@ -736,6 +745,9 @@ func typParams(ti *parser.TypInfo, ctx *ConvertCtx) Node {
 	if psLen == 0 {
 		return nil
 	}
+	if ctx.flow() {
+		return ConvertTsTyp(psDec, ctx)
+	}
 
 	ret := make([]Node, len(ps))
 	for i, p := range ps {
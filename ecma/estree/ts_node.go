@@ -6,6 +6,7 @@ type TSTypeAnnotation struct {
 	End            int     `json:"end"`
 	Loc            *SrcLoc `json:"loc"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSIdentifier struct {
@@ -17,6 +18,7 @@ type TSIdentifier struct {
 	Optional       bool    `json:"optional"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
 	Decorators     []Node  `json:"decorators"`
+	*NodeComments
 }
 
 type TSNumberKeyword struct {
@@ -24,6 +26,7 @@ type TSNumberKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSObjectKeyword struct {
@@ -31,6 +34,7 @@ type TSObjectKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSStringKeyword struct {
@@ -38,6 +42,7 @@ type TSStringKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSVoidKeyword struct {
@@ -45,6 +50,7 @@ type TSVoidKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSAnyKeyword struct {
@@ -52,6 +58,7 @@ type TSAnyKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSBooleanKeyword struct {
@@ -59,6 +66,7 @@ type TSBooleanKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSThisType struct {
@@ -66,6 +74,7 @@ type TSThisType struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSIntrinsicKeyword struct {
@@ -73,6 +82,7 @@ type TSIntrinsicKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSNeverKeyword struct {
@@ -80,6 +90,7 @@ type TSNeverKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSSymbolKeyword struct {
@@ -87,6 +98,7 @@ type TSSymbolKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSUndefinedKeyword struct {
@@ -94,6 +106,7 @@ type TSUndefinedKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSBigIntKeyword struct {
@@ -101,6 +114,7 @@ type TSBigIntKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSNullKeyword struct {
@@ -108,6 +122,7 @@ type TSNullKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSUnknownKeyword struct {
@@ -115,6 +130,7 @@ type TSUnknownKeyword struct {
 	Start int     `json:"start"`
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
+	*NodeComments
 }
 
 type TSFunctionExpression struct {
@@ -130,6 +146,7 @@ type TSFunctionExpression struct {
 	Expression     bool    `json:"expression"`
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
+	*NodeComments
 }
 
 type TSFunctionDeclaration struct {
@@ -144,6 +161,7 @@ type TSFunctionDeclaration struct {
 	Async          bool    `json:"async"`
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
+	*NodeComments
 }
 
 type TSCallSignatureDeclaration struct {
@@ -157,6 +175,7 @@ type TSCallSignatureDeclaration struct {
 	Async          bool    `json:"async"`
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
+	*NodeComments
 }
 
 type TSConstructSignatureDeclaration struct {
@@ -171,6 +190,7 @@ type TSConstructSignatureDeclaration struct {
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
 	Abstract       bool    `json:"abstract"`
+	*NodeComments
 }
 
 type TSConstructorType struct {
@@ -185,6 +205,7 @@ type TSConstructorType struct {
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
 	Abstract       bool    `json:"abstract"`
+	*NodeComments
 }
 
 type TSFunctionType struct {
@@ -198,6 +219,7 @@ type TSFunctionType struct {
 	Async          bool    `json:"async"`
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
+	*NodeComments
 }
 
 type TSArrowFunctionExpression struct {
@@ -213,6 +235,7 @@ type TSArrowFunctionExpression struct {
 	Expression     bool        `json:"expression"`
 	TypeParameters Node        `json:"typeParameters"`
 	ReturnType     Node        `json:"returnType"`
+	*NodeComments
 }
 
 type TSTypeReference struct {
@@ -222,6 +245,7 @@ type TSTypeReference struct {
 	Loc            *SrcLoc `json:"loc"`
 	TypeName       Node    `json:"typeName"`
 	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSTypeParameterDeclaration struct {
@@ -230,6 +254,7 @@ type TSTypeParameterDeclaration struct {
 	End    int     `json:"end"`
 	Loc    *SrcLoc `json:"loc"`
 	Params []Node  `json:"params"`
+	*NodeComments
 }
 
 type TSTypeParameterInstantiation struct {
@@ -238,6 +263,7 @@ type TSTypeParameterInstantiation struct {
 	End    int     `json:"end"`
 	Loc    *SrcLoc `json:"loc"`
 	Params []Node  `json:"params"`
+	*NodeComments
 }
 
 type TSTypeParameter struct {
@@ -248,6 +274,10 @@ type TSTypeParameter struct {
 	Name       Node    `json:"name"`
 	Constraint Node    `json:"constraint"`
 	Default    Node    `json:"default"`
+	In         bool    `json:"in,omitempty"`
+	Out        bool    `json:"out,omitempty"`
+	Const      bool    `json:"const,omitempty"`
+	*NodeComments
 }
 
 type TSCallExpression struct {
@@ -259,6 +289,7 @@ type TSCallExpression struct {
 	Arguments      []Expression `json:"arguments"`
 	Optional       bool         `json:"optional"`
 	TypeParameters Node         `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSNewExpression struct {
@@ -269,6 +300,7 @@ type TSNewExpression struct {
 	Callee         Expression   `json:"callee"`
 	Arguments      []Expression `json:"arguments"`
 	TypeParameters Node         `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSRestElement struct {
@@ -279,6 +311,7 @@ type TSRestElement struct {
 	Argument       Pattern `json:"argument"`
 	Optional       bool    `json:"optional"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSArrayType struct {
@@ -287,6 +320,7 @@ type TSArrayType struct {
 	End         int     `json:"end"`
 	Loc         *SrcLoc `json:"loc"`
 	ElementType Node    `json:"elementType"`
+	*NodeComments
 }
 
 type TSTypeLiteral struct {
@@ -295,6 +329,7 @@ type TSTypeLiteral struct {
 	End     int     `json:"end"`
 	Loc     *SrcLoc `json:"loc"`
 	Members Node    `json:"members"`
+	*NodeComments
 }
 
 // used as the member of `TSTypeLiteral`
@@ -309,6 +344,7 @@ type TSPropertySignature struct {
 	TypeAnnotation Node    `json:"typeAnnotation"`
 	Kind           string  `json:"kind"`
 	Readonly       bool    `json:"readonly"`
+	*NodeComments
 }
 
 type TSMethodSignature struct {
@@ -321,6 +357,7 @@ type TSMethodSignature struct {
 	Computed bool       `json:"computed"`
 	Optional bool       `json:"optional"`
 	Kind     string     `json:"kind"`
+	*NodeComments
 }
 
 type TSObjectPattern struct {
@@ -331,6 +368,7 @@ type TSObjectPattern struct {
 	Properties     []Node  `json:"properties"`
 	Optional       bool    `json:"optional"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSTypePredicate struct {
@@ -341,6 +379,7 @@ type TSTypePredicate struct {
 	ParameterName  Node    `json:"parameterName"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
 	Asserts        bool    `json:"asserts"`
+	*NodeComments
 }
 
 type TSDeclareFunction struct {
@@ -355,6 +394,7 @@ type TSDeclareFunction struct {
 	Async          bool    `json:"async"`
 	TypeParameters Node    `json:"typeParameters"`
 	ReturnType     Node    `json:"returnType"`
+	*NodeComments
 }
 
 type TSMethodDefinition struct {
@@ -374,6 +414,7 @@ type TSMethodDefinition struct {
 	Readonly      bool       `json:"readonly"`
 	Accessibility string     `json:"accessibility"`
 	Decorators    []Node     `json:"decorators"`
+	*NodeComments
 }
 
 // represets the properties defined via constructor params
@@ -387,6 +428,7 @@ type TSParameterProperty struct {
 	Accessibility string  `json:"accessibility"`
 	Override      bool    `json:"override"`
 	Decorators    []Node  `json:"decorators"`
+	*NodeComments
 }
 
 type TSPropertyDefinition struct {
@@ -407,6 +449,8 @@ type TSPropertyDefinition struct {
 	Accessibility  string     `json:"accessibility"`
 	TypeAnnotation Node       `json:"typeAnnotation"`
 	Decorators     []Node     `json:"decorators"`
+	Variance       Node       `json:"variance,omitempty"` // flow
+	*NodeComments
 }
 
 type TSIndexSignature struct {
@@ -423,6 +467,7 @@ type TSIndexSignature struct {
 	Parameters     []Node  `json:"parameters"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
 	Decorators     []Node  `json:"decorators"`
+	*NodeComments
 }
 
 type TSAsExpression struct {
@@ -432,6 +477,17 @@ type TSAsExpression struct {
 	Loc            *SrcLoc `json:"loc"`
 	Expression     Node    `json:"expression"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
+}
+
+type TSSatisfiesExpression struct {
+	Type           string  `json:"type"`
+	Start          int     `json:"start"`
+	End            int     `json:"end"`
+	Loc            *SrcLoc `json:"loc"`
+	Expression     Node    `json:"expression"`
+	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSTypeAssertion struct {
@@ -441,6 +497,7 @@ type TSTypeAssertion struct {
 	Loc            *SrcLoc `json:"loc"`
 	Expression     Node    `json:"expression"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSNonNullExpression struct {
@@ -449,6 +506,7 @@ type TSNonNullExpression struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Expression Node    `json:"expression"`
+	*NodeComments
 }
 
 type TSUnionType struct {
@@ -457,6 +515,7 @@ type TSUnionType struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Types []Node  `json:"types"`
+	*NodeComments
 }
 
 type TSIntersectionType struct {
@@ -465,6 +524,7 @@ type TSIntersectionType struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Types []Node  `json:"types"`
+	*NodeComments
 }
 
 type TSClassDeclaration struct {
@@ -481,6 +541,7 @@ type TSClassDeclaration struct {
 	Declare             bool       `json:"declare"`
 	Decorators          []Node     `json:"decorators"`
 	Abstract            bool       `json:"abstract"`
+	*NodeComments
 }
 
 type TSClassExpression struct {
@@ -496,6 +557,7 @@ type TSClassExpression struct {
 	Body                Expression `json:"body"`
 	Decorators          []Node     `json:"decorators"`
 	Abstract            bool       `json:"abstract"`
+	*NodeComments
 }
 
 type TSQualifiedName struct {
@@ -505,6 +567,7 @@ type TSQualifiedName struct {
 	Loc   *SrcLoc    `json:"loc"`
 	Left  Expression `json:"left"`
 	Right Expression `json:"right"`
+	*NodeComments
 }
 
 type TSVariableDeclaration struct {
@@ -515,6 +578,7 @@ type TSVariableDeclaration struct {
 	Kind         string                `json:"kind"`
 	Declarations []*VariableDeclarator `json:"declarations"`
 	Declare      bool                  `json:"declare"`
+	*NodeComments
 }
 
 type TSInterfaceDeclaration struct {
@@ -527,6 +591,7 @@ type TSInterfaceDeclaration struct {
 	Extends        []Node     `json:"extends"`
 	Body           Expression `json:"body"`
 	Declare        bool       `json:"declare"`
+	*NodeComments
 }
 
 type TSInterfaceBody struct {
@@ -535,6 +600,7 @@ type TSInterfaceBody struct {
 	End   int     `json:"end"`
 	Loc   *SrcLoc `json:"loc"`
 	Body  []Node  `json:"body"`
+	*NodeComments
 }
 
 type TSExpressionWithTypeArguments struct {
@@ -544,6 +610,7 @@ type TSExpressionWithTypeArguments struct {
 	Loc            *SrcLoc `json:"loc"`
 	Expression     Node    `json:"expression"`
 	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSArrayPattern struct {
@@ -554,6 +621,7 @@ type TSArrayPattern struct {
 	Elements       []Node  `json:"elements"`
 	Optional       bool    `json:"optional"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSEnumDeclaration struct {
@@ -565,6 +633,7 @@ type TSEnumDeclaration struct {
 	Members []Node     `json:"members"`
 	Const   bool       `json:"const"`
 	Declare bool       `json:"declare"`
+	*NodeComments
 }
 
 type TSEnumMember struct {
@@ -574,6 +643,7 @@ type TSEnumMember struct {
 	Loc         *SrcLoc    `json:"loc"`
 	Id          Expression `json:"id"`
 	Initializer Node       `json:"initializer"`
+	*NodeComments
 }
 
 type TSTypeAliasDeclaration struct {
@@ -585,6 +655,7 @@ type TSTypeAliasDeclaration struct {
 	TypeParameters Node       `json:"typeParameters"`
 	TypeAnnotation Node       `json:"typeAnnotation"`
 	Declare        bool       `json:"declare"`
+	*NodeComments
 }
 
 type TSModuleDeclaration struct {
@@ -596,6 +667,7 @@ type TSModuleDeclaration struct {
 	Body    Node       `json:"body"`
 	Declare bool       `json:"declare"`
 	Global  bool       `json:"global"`
+	*NodeComments
 }
 
 type TSNamespaceExportDeclaration struct {
@@ -604,6 +676,7 @@ type TSNamespaceExportDeclaration struct {
 	End   int        `json:"end"`
 	Loc   *SrcLoc    `json:"loc"`
 	Id    Expression `json:"id"`
+	*NodeComments
 }
 
 type TSExportAssignment struct {
@@ -612,6 +685,7 @@ type TSExportAssignment struct {
 	End        int        `json:"end"`
 	Loc        *SrcLoc    `json:"loc"`
 	Expression Expression `json:"expression"`
+	*NodeComments
 }
 
 type TSLiteralType struct {
@@ -620,6 +694,7 @@ type TSLiteralType struct {
 	End     int     `json:"end"`
 	Loc     *SrcLoc `json:"loc"`
 	Literal Node    `json:"literal"`
+	*NodeComments
 }
 
 type TSImportEqualsDeclaration struct {
@@ -630,6 +705,7 @@ type TSImportEqualsDeclaration struct {
 	Id              Node    `json:"id"`
 	ModuleReference Node    `json:"moduleReference"`
 	IsExport        bool    `json:"isExport"`
+	*NodeComments
 }
 
 type TSExternalModuleReference struct {
@@ -638,6 +714,7 @@ type TSExternalModuleReference struct {
 	End        int     `json:"end"`
 	Loc        *SrcLoc `json:"loc"`
 	Expression Node    `json:"expression"`
+	*NodeComments
 }
 
 type TSTaggedTemplateExpression struct {
@@ -648,6 +725,7 @@ type TSTaggedTemplateExpression struct {
 	Tag            Expression `json:"tag"`
 	Quasi          Expression `json:"quasi"`
 	TypeParameters Node       `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSXOpeningElement struct {
@@ -659,6 +737,7 @@ type TSXOpeningElement struct {
 	Attributes     []Node  `json:"attributes"`
 	SelfClosing    bool    `json:"selfClosing"`
 	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSImportType struct {
@@ -669,6 +748,7 @@ type TSImportType struct {
 	Argument       Node    `json:"argument"`
 	Qualifier      Node    `json:"qualifier"`
 	TypeParameters Node    `json:"typeParameters"`
+	*NodeComments
 }
 
 type TSTypeQuery struct {
@@ -677,6 +757,7 @@ type TSTypeQuery struct {
 	End      int     `json:"end"`
 	Loc      *SrcLoc `json:"loc"`
 	ExprName Node    `json:"exprName"`
+	*NodeComments
 }
 
 type TSConditionalType struct {
@@ -688,6 +769,7 @@ type TSConditionalType struct {
 	ExtendsType Node    `json:"extendsType"`
 	TrueType    Node    `json:"trueType"`
 	FalseType   Node    `json:"falseType"`
+	*NodeComments
 }
 
 type TSInferType struct {
@@ -696,6 +778,7 @@ type TSInferType struct {
 	End           int     `json:"end"`
 	Loc           *SrcLoc `json:"loc"`
 	TypeParameter Node    `json:"typeParameter"`
+	*NodeComments
 }
 
 type TSParenthesizedType struct {
@@ -704,6 +787,7 @@ type TSParenthesizedType struct {
 	End            int     `json:"end"`
 	Loc            *SrcLoc `json:"loc"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSIndexedAccessType struct {
@@ -713,6 +797,7 @@ type TSIndexedAccessType struct {
 	Loc        *SrcLoc `json:"loc"`
 	ObjectType Node    `json:"objectType"`
 	IndexType  Node    `json:"indexType"`
+	*NodeComments
 }
 
 type TSMappedType struct {
@@ -725,6 +810,7 @@ type TSMappedType struct {
 	TypeParameter  Node        `json:"typeParameter"`
 	NameType       Node        `json:"nameType"`
 	TypeAnnotation Node        `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSTypeOperator struct {
@@ -734,6 +820,7 @@ type TSTypeOperator struct {
 	Loc            *SrcLoc     `json:"loc"`
 	Operator       interface{} `json:"operator"`
 	TypeAnnotation Node        `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSTupleType struct {
@@ -742,6 +829,7 @@ type TSTupleType struct {
 	End          int     `json:"end"`
 	Loc          *SrcLoc `json:"loc"`
 	ElementTypes []Node  `json:"elementTypes"`
+	*NodeComments
 }
 
 type TSRestType struct {
@@ -750,6 +838,7 @@ type TSRestType struct {
 	End            int     `json:"end"`
 	Loc            *SrcLoc `json:"loc"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
 
 type TSNamedTupleMember struct {
@@ -760,6 +849,7 @@ type TSNamedTupleMember struct {
 	Optional    bool    `json:"optional"`
 	Label       Node    `json:"label"`
 	ElementType Node    `json:"elementType"`
+	*NodeComments
 }
 
 type TSOptionalType struct {
@@ -768,4 +858,5 @@ type TSOptionalType struct {
 	End            int     `json:"end"`
 	Loc            *SrcLoc `json:"loc"`
 	TypeAnnotation Node    `json:"typeAnnotation"`
+	*NodeComments
 }
@@ -0,0 +1,346 @@
+package format
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// the intermediate representation of the formatted code which is independent of the line
+// width, the layout is decided by the printer which breaks the groups do not fit in the line,
+// it's the algorithm described in "A prettier printer" by Philip Wadler
+type Doc interface {
+	doc()
+}
+
+type docText string
+
+// the docs are printed one by one
+type docConcat []Doc
+
+// the lines in the group are broken if the group does not fit in the rest of the line
+type docGroup struct {
+	contents Doc
+	brk      bool // the group is broken unconditionally, for it contains the hard lines
+}
+
+// the lines broken in the contents are indented by one more level
+type docIndent struct {
+	contents Doc
+}
+
+type docLine struct {
+	soft bool // prints nothing instead of a space if the line is not broken
+	hard bool // the line is always broken
+}
+
+// prints `brk` if the enclosing group is broken otherwise `flat`
+type docIfBreak struct {
+	brk  Doc
+	flat Doc
+}
+
+// the contents are deferred to the end of the line, it's used for the line comments
+type docLineSuffix struct {
+	contents Doc
+}
+
+// the contents and the separators are placed alternately, a separator is broken only if
+// the content after it does not fit in the rest of the line, it's used to reflow the texts
+type docFill []Doc
+
+// forces the enclosing groups to be broken
+type docBreakParent struct{}
+
+func (d docText) doc()        {}
+func (d docConcat) doc()      {}
+func (d *docGroup) doc()      {}
+func (d *docIndent) doc()     {}
+func (d *docLine) doc()       {}
+func (d *docIfBreak) doc()    {}
+func (d *docLineSuffix) doc() {}
+func (d docFill) doc()        {}
+func (d docBreakParent) doc() {}
+
+var (
+	// a space if the enclosing group is not broken otherwise a line break
+	Line Doc = &docLine{}
+	// nothing if the enclosing group is not broken otherwise a line break
+	SoftLine Doc = &docLine{soft: true}
+	// a line break regardless of the enclosing group
+	HardLine Doc = docConcat{&docLine{hard: true}, BreakParent}
+	// breaks the enclosing groups
+	BreakParent Doc = docBreakParent{}
+)
+
+func Text(s string) Doc {
+	return docText(s)
+}
+
+func Concat(docs ...Doc) Doc {
+	return docConcat(docs)
+}
+
+func Group(docs ...Doc) Doc {
+	return &docGroup{contents: docConcat(docs)}
+}
+
+func Indent(docs ...Doc) Doc {
+	return &docIndent{docConcat(docs)}
+}
+
+func IfBreak(brk, flat Doc) Doc {
+	return &docIfBreak{brk, flat}
+}
+
+func Fill(parts ...Doc) Doc {
+	return docFill(parts)
+}
+
+func LineSuffix(docs ...Doc) Doc {
+	return &docLineSuffix{docConcat(docs)}
+}
+
+// joins the docs with the separator
+func Join(sep Doc, docs []Doc) Doc {
+	ret := make(docConcat, 0, len(docs)*2)
+	for i, d := range docs {
+		if i > 0 {
+			ret = append(ret, sep)
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// marks the groups contain the hard lines as broken, returns whether the doc contains them
+func propagateBreaks(d Doc) bool {
+	switch n := d.(type) {
+	case docFill:
+		return propagateBreaks(docConcat(n))
+	case docConcat:
+		brk := false
+		for _, c := range n {
+			if propagateBreaks(c) {
+				brk = true
+			}
+		}
+		return brk
+	case *docGroup:
+		if propagateBreaks(n.contents) {
+			n.brk = true
+		}
+		return n.brk
+	case *docIndent:
+		return propagateBreaks(n.contents)
+	case *docIfBreak:
+		b := n.brk != nil && propagateBreaks(n.brk)
+		f := n.flat != nil && propagateBreaks(n.flat)
+		return b || f
+	case *docLineSuffix:
+		return propagateBreaks(n.contents)
+	case docBreakParent:
+		return true
+	}
+	return false
+}
+
+type printMode uint8
+
+const (
+	modeBreak printMode = iota
+	modeFlat
+)
+
+type printCmd struct {
+	ind  string
+	mode printMode
+	doc  Doc
+}
+
+type DocPrinterOpts struct {
+	Width  int    // the max width of the lines
+	Indent string // the string used to indent one level
+	Base   string // the indentation of the lines except the first one
+}
+
+// prints the doc to string, the trailing whitespaces of the lines are trimmed
+func PrintDoc(d Doc, opts *DocPrinterOpts) string {
+	propagateBreaks(d)
+
+	out := make([]byte, 0, 1024)
+	pos := 0
+	cmds := []*printCmd{{opts.Base, modeBreak, d}}
+	suffix := make([]*printCmd, 0)
+
+	for len(cmds) > 0 {
+		c := cmds[len(cmds)-1]
+		cmds = cmds[:len(cmds)-1]
+
+		switch n := c.doc.(type) {
+		case docText:
+			out = append(out, n...)
+			pos = advance(pos, string(n))
+		case docConcat:
+			for i := len(n) - 1; i >= 0; i-- {
+				cmds = append(cmds, &printCmd{c.ind, c.mode, n[i]})
+			}
+		case *docIndent:
+			cmds = append(cmds, &printCmd{c.ind + opts.Indent, c.mode, n.contents})
+		case docFill:
+			cmds = printFill(n, c, cmds, opts.Width-pos)
+		case *docGroup:
+			flat := &printCmd{c.ind, modeFlat, n.contents}
+			if c.mode == modeFlat || !n.brk && fits(flat, cmds, opts.Width-pos) {
+				cmds = append(cmds, flat)
+			} else {
+				cmds = append(cmds, &printCmd{c.ind, modeBreak, n.contents})
+			}
+		case *docIfBreak:
+			sub := n.flat
+			if c.mode == modeBreak {
+				sub = n.brk
+			}
+			if sub != nil {
+				cmds = append(cmds, &printCmd{c.ind, c.mode, sub})
+			}
+		case *docLineSuffix:
+			suffix = append(suffix, &printCmd{c.ind, c.mode, n.contents})
+		case *docLine:
+			if c.mode == modeFlat && !n.hard {
+				if !n.soft {
+					out = append(out, ' ')
+					pos += 1
+				}
+				continue
+			}
+			if len(suffix) > 0 {
+				cmds = append(cmds, c)
+				for i := len(suffix) - 1; i >= 0; i-- {
+					cmds = append(cmds, suffix[i])
+				}
+				suffix = suffix[:0]
+				continue
+			}
+			out = append(trimTrailingSpaces(out), '\n')
+			out = append(out, c.ind...)
+			pos = len(c.ind)
+		}
+
+		if len(cmds) == 0 && len(suffix) > 0 {
+			for i := len(suffix) - 1; i >= 0; i-- {
+				cmds = append(cmds, suffix[i])
+			}
+			suffix = suffix[:0]
+		}
+	}
+	return string(trimTrailingSpaces(out))
+}
+
+func printFill(parts docFill, c *printCmd, cmds []*printCmd, width int) []*printCmd {
+	if len(parts) == 0 {
+		return cmds
+	}
+	flat := func(d Doc) *printCmd { return &printCmd{c.ind, modeFlat, d} }
+	brk := func(d Doc) *printCmd { return &printCmd{c.ind, modeBreak, d} }
+
+	content := parts[0]
+	contentFits := fits(flat(content), nil, width)
+	if len(parts) == 1 {
+		if contentFits {
+			return append(cmds, flat(content))
+		}
+		return append(cmds, brk(content))
+	}
+
+	sep := parts[1]
+	if len(parts) == 2 {
+		if contentFits {
+			return append(cmds, flat(sep), flat(content))
+		}
+		return append(cmds, brk(sep), brk(content))
+	}
+
+	cmds = append(cmds, &printCmd{c.ind, c.mode, parts[2:]})
+	if fits(flat(docConcat{content, sep, parts[2]}), nil, width) {
+		return append(cmds, flat(sep), flat(content))
+	}
+	if contentFits {
+		return append(cmds, brk(sep), flat(content))
+	}
+	return append(cmds, brk(sep), brk(content))
+}
+
+func advance(pos int, s string) int {
+	if i := strings.LastIndexByte(s, '\n'); i != -1 {
+		return utf8.RuneCountInString(s[i+1:])
+	}
+	return pos + utf8.RuneCountInString(s)
+}
+
+func trimTrailingSpaces(out []byte) []byte {
+	i := len(out)
+	for i > 0 && (out[i-1] == ' ' || out[i-1] == '\t') {
+		i--
+	}
+	return out[:i]
+}
+
+// whether the contents of `next` fit in the `width` in the flat mode, the commands in `rest`
+// are also measured until the first line break since they are printed in the same line
+func fits(next *printCmd, rest []*printCmd, width int) bool {
+	stk := []*printCmd{next}
+	ri := len(rest)
+	for width >= 0 {
+		if len(stk) == 0 {
+			if ri == 0 {
+				return true
+			}
+			ri--
+			stk = append(stk, rest[ri])
+			continue
+		}
+		c := stk[len(stk)-1]
+		stk = stk[:len(stk)-1]
+
+		switch n := c.doc.(type) {
+		case docText:
+			s := string(n)
+			if i := strings.IndexByte(s, '\n'); i != -1 {
+				return width-utf8.RuneCountInString(s[:i]) >= 0
+			}
+			width -= utf8.RuneCountInString(s)
+		case docConcat:
+			for i := len(n) - 1; i >= 0; i-- {
+				stk = append(stk, &printCmd{c.ind, c.mode, n[i]})
+			}
+		case docFill:
+			for i := len(n) - 1; i >= 0; i-- {
+				stk = append(stk, &printCmd{c.ind, c.mode, n[i]})
+			}
+		case *docIndent:
+			stk = append(stk, &printCmd{c.ind, c.mode, n.contents})
+		case *docGroup:
+			mode := c.mode
+			if n.brk {
+				mode = modeBreak
+			}
+			stk = append(stk, &printCmd{c.ind, mode, n.contents})
+		case *docIfBreak:
+			sub := n.flat
+			if c.mode == modeBreak {
+				sub = n.brk
+			}
+			if sub != nil {
+				stk = append(stk, &printCmd{c.ind, c.mode, sub})
+			}
+		case *docLine:
+			if c.mode == modeBreak || n.hard {
+				return true
+			}
+			if !n.soft {
+				width -= 1
+			}
+		}
+	}
+	return false
+}
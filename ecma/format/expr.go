@@ -0,0 +1,547 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+func (f *formatter) expr(node parser.Node) Doc {
+	switch n := node.(type) {
+	case *parser.NullLit:
+		return Text("null")
+	case *parser.BoolLit:
+		if n.Val() {
+			return Text("true")
+		}
+		return Text("false")
+	case *parser.NumLit, *parser.RegLit, *parser.MetaProp, *parser.Decorator:
+		return f.raw(node.Range())
+	case *parser.StrLit:
+		return Text(quote(f.text(n.Range())))
+	case *parser.Ident:
+		return Concat(Text(f.text(n.Range())), f.typSuffix(n.TypInfo()))
+	case *parser.ThisExpr:
+		return Text("this")
+	case *parser.Super:
+		return Text("super")
+	case *parser.ArrLit:
+		return f.array(n.Elems(), n.Range().Hi, nil)
+	case *parser.ArrPat:
+		return f.array(n.Elems(), n.Range().Hi, n.TypInfo())
+	case *parser.ObjLit:
+		return f.object(n.Props(), n.Range(), nil)
+	case *parser.ObjPat:
+		return f.object(n.Props(), n.Range(), n.TypInfo())
+	case *parser.Prop:
+		return f.prop(n)
+	case *parser.Spread:
+		return Concat(Text("..."), f.node(n.Arg()))
+	case *parser.RestPat:
+		ques := ""
+		if n.TypInfo() != nil && n.Optional() {
+			ques = "?"
+		}
+		return Concat(Text("..."), f.node(n.Arg()), Text(ques), f.typAnnot(n.TypInfo()))
+	case *parser.AssignPat:
+		return Concat(f.lhs(n.Lhs()), Text(" = "), f.node(n.Rhs()))
+	case *parser.VarDec:
+		return f.varDec(n)
+	case *parser.FnDec:
+		return f.fn(n)
+	case *parser.ArrowFn:
+		return f.arrow(n)
+	case *parser.ClassDec:
+		return f.class(n)
+	case *parser.ParenExpr:
+		return Concat(Text("("), f.node(n.Expr()), f.rest(n.Range().Hi-1), Text(")"))
+	case *parser.SeqExpr:
+		return Join(Text(", "), f.commaList(n.Elems(), n.Range().Hi))
+	case *parser.ChainExpr:
+		return f.node(n.Expr())
+	case *parser.MemberExpr, *parser.CallExpr:
+		return f.chain(n)
+	case *parser.NewExpr:
+		callee := f.node(n.Callee())
+		return Concat(Text("new "), callee, f.typArgs(n.TypInfo()), f.args(n.Args(), n.Range().Hi))
+	case *parser.ImportCall:
+		if opts := n.Opts(); opts != nil {
+			return Concat(Text("import("), f.node(n.Src()), Text(", "), f.node(opts), f.rest(n.Range().Hi), Text(")"))
+		}
+		return Concat(Text("import("), f.node(n.Src()), f.rest(n.Range().Hi), Text(")"))
+	case *parser.TplExpr:
+		if n.Tag() == nil {
+			return f.raw(n.Range())
+		}
+		return Concat(f.node(n.Tag()), f.typArgsOf(n.Tag()), f.raw(n.Range()))
+	case *parser.BinExpr:
+		if isTsBin(n) {
+			return Concat(f.node(n.Lhs()), Text(" "+n.OpText()+" "), f.raw(n.Rhs().Range()))
+		}
+		return f.binary(n, true)
+	case *parser.UnaryExpr:
+		return f.unary(n)
+	case *parser.UpdateExpr:
+		if n.Prefix() {
+			return Concat(Text(n.OpText()), f.node(n.Arg()))
+		}
+		return Concat(f.node(n.Arg()), Text(n.OpText()))
+	case *parser.CondExpr:
+		return Group(f.node(n.Test()), Indent(Line, Text("? "), f.node(n.Cons()), Line, Text(": "), f.node(n.Alt())))
+	case *parser.AssignExpr:
+		return Concat(f.lhs(n.Lhs()), Text(" "+n.OpName()), f.assignRhs(n.Rhs()))
+	case *parser.YieldExpr:
+		kw := "yield"
+		if n.Delegate() {
+			kw = "yield*"
+		}
+		if n.Arg() == nil {
+			return Text(kw)
+		}
+		if f.breakingCmtBefore(outerRange(n.Arg()).Lo) {
+			return Concat(Text(kw+" ("), Indent(HardLine, f.node(n.Arg())), HardLine, Text(")"))
+		}
+		return Concat(Text(kw+" "), f.node(n.Arg()))
+	case *parser.JsxElem:
+		return f.jsxElem(n)
+	case *parser.JsxAttr, *parser.JsxSpreadAttr, *parser.JsxExprSpan, *parser.JsxSpreadChild:
+		return f.jsxAttr(n)
+	}
+	return f.raw(node.Range())
+}
+
+// the parentheses of the type assertions as the assignment targets are not kept in the AST
+func (f *formatter) lhs(node parser.Node) Doc {
+	if outerRange(node) == node.Range() {
+		if n, ok := node.(*parser.BinExpr); ok && isTsBin(n) || node.Type() == parser.N_TS_TYP_ASSERT {
+			return Concat(Text("("), f.node(node), Text(")"))
+		}
+	}
+	return f.node(node)
+}
+
+// `as` and `satisfies` whose right-hand side is a type
+func isTsBin(n *parser.BinExpr) bool {
+	typ := n.Rhs().Type()
+	return typ > parser.N_TS_BEGIN && typ < parser.N_TS_END
+}
+
+// the string literal is quoted by double quotes unless it contains more double quotes than
+// single quotes
+func quote(raw string) string {
+	if len(raw) < 2 || raw[0] != '\'' {
+		return raw
+	}
+	s := raw[1 : len(raw)-1]
+	if strings.Count(s, "\"") > strings.Count(s, "\\'") {
+		return raw
+	}
+	s = strings.ReplaceAll(s, "\\'", "'")
+	return "\"" + strings.ReplaceAll(s, "\"", "\\\"") + "\""
+}
+
+// the optional mark, the definite mark and the type annotation
+func (f *formatter) typSuffix(ti *parser.TypInfo) Doc {
+	if ti == nil {
+		return Text("")
+	}
+	docs := docConcat{}
+	if ti.Optional() {
+		docs = append(docs, Text("?"))
+	}
+	if ti.Definite() {
+		docs = append(docs, Text("!"))
+	}
+	return append(docs, f.typAnnot(ti))
+}
+
+func (f *formatter) typAnnot(ti *parser.TypInfo) Doc {
+	if ti == nil || ti.TypAnnot() == nil {
+		return Text("")
+	}
+	rng := ti.TypAnnot().Range()
+	lo := rng.Lo
+	if f.code[lo] == ':' {
+		lo = f.skipSpaces(lo + 1)
+	}
+	rng.Lo = lo
+	return Concat(Text(": "), f.raw(rng))
+}
+
+func (f *formatter) typParams(ti *parser.TypInfo) Doc {
+	if ti == nil || ti.TypParams() == nil {
+		return Text("")
+	}
+	return f.raw(ti.TypParams().Range())
+}
+
+func (f *formatter) typArgs(ti *parser.TypInfo) Doc {
+	if ti == nil || ti.TypArgs() == nil {
+		return Text("")
+	}
+	return f.raw(ti.TypArgs().Range())
+}
+
+func (f *formatter) typArgsOf(node parser.Node) Doc {
+	if wt, ok := node.(parser.NodeWithTypInfo); ok {
+		return f.typArgs(wt.TypInfo())
+	}
+	return Text("")
+}
+
+func (f *formatter) array(elems []parser.Node, hi uint32, ti *parser.TypInfo) Doc {
+	var doc Doc
+	if len(elems) > 0 && elems[len(elems)-1] == nil {
+		// the trailing hole needs an explicit comma
+		docs := f.commaList(elems, hi)
+		doc = Group(Text("["), Indent(SoftLine, Join(Concat(Text(","), Line), docs)), Text(","), SoftLine, Text("]"))
+	} else {
+		doc = f.group("[", "]", elems, hi-1, SoftLine, !endsWithRest(elems))
+	}
+	if ti != nil {
+		return Concat(doc, f.typSuffix(ti))
+	}
+	return doc
+}
+
+func endsWithRest(nodes []parser.Node) bool {
+	if len(nodes) == 0 || nodes[len(nodes)-1] == nil {
+		return false
+	}
+	return nodes[len(nodes)-1].Type() == parser.N_PAT_REST
+}
+
+// the object is broken if there is a line break after its opening brace in the source
+func (f *formatter) object(props []parser.Node, rng span.Range, ti *parser.TypInfo) Doc {
+	doc := f.group("{", "}", props, rng.Hi-1, Line, !endsWithRest(props))
+	if g, ok := doc.(*docGroup); ok && f.hasNewline(rng.Lo, props[0].Range().Lo) {
+		g.brk = true
+	}
+	if ti != nil {
+		return Concat(doc, f.typSuffix(ti))
+	}
+	return doc
+}
+
+func (f *formatter) prop(n *parser.Prop) Doc {
+	val := n.Val()
+	if n.Shorthand() {
+		return f.node(val)
+	}
+	if n.Method() || n.PropKind() == parser.PK_GETTER || n.PropKind() == parser.PK_SETTER {
+		return Concat(f.memberKey(n, n.Key(), n.Computed()), f.fnTail(val.(*parser.FnDec)))
+	}
+	key := f.key(n.Key(), n.Computed())
+	if n.Computed() {
+		key = Concat(f.leading(n.Range().Lo), key)
+	}
+	return Concat(key, Text(":"), f.assignRhs(val))
+}
+
+func (f *formatter) fn(n *parser.FnDec) Doc {
+	kw := "function"
+	if n.Async() {
+		kw = "async function"
+	}
+	if n.Generator() {
+		kw += "*"
+	}
+	docs := docConcat{Text(kw)}
+	if n.Id() != nil {
+		docs = append(docs, Text(" "), f.node(n.Id()))
+	} else {
+		docs = append(docs, Text(" "))
+	}
+	return append(docs, f.fnTail(n))
+}
+
+// the type parameters, the parameters, the return type and the body of the function
+func (f *formatter) fnTail(n *parser.FnDec) Doc {
+	docs := docConcat{f.typParams(n.TypInfo())}
+	hi := n.Range().Hi
+	if n.Body() != nil {
+		hi = n.Body().Range().Lo
+	}
+	if ti := n.TypInfo(); ti != nil && ti.TypAnnot() != nil {
+		hi = ti.TypAnnot().Range().Lo
+	}
+	docs = append(docs, f.params(n.Params(), hi), f.typAnnot(n.TypInfo()))
+	if n.Body() == nil || n.IsSig() {
+		if n.Type() == parser.N_EXPR_FN && n.Body() == nil {
+			return append(docs, Text(";"))
+		}
+		return docs
+	}
+	return append(docs, Text(" "), f.node(n.Body()))
+}
+
+// the modifiers of the parameter properties which are not kept in the AST
+var paramModifiers = map[string]bool{
+	"public":    true,
+	"private":   true,
+	"protected": true,
+	"readonly":  true,
+	"override":  true,
+}
+
+func (f *formatter) paramModifiers(lo uint32) string {
+	words := make([]string, 0)
+	for {
+		i := int(lo)
+		for i > 0 && strings.IndexByte(" \t\r\n", f.code[i-1]) != -1 {
+			i--
+		}
+		j := i
+		for j > 0 && (f.code[j-1] >= 'a' && f.code[j-1] <= 'z') {
+			j--
+		}
+		if !paramModifiers[f.code[j:i]] {
+			break
+		}
+		words = append([]string{f.code[j:i]}, words...)
+		lo = uint32(j)
+	}
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.Join(words, " ") + " "
+}
+
+func (f *formatter) params(params []parser.Node, hi uint32) Doc {
+	if len(params) == 1 && params[0].Type() == parser.N_PAT_OBJ {
+		return Concat(Text("("), f.param(params[0]), f.rest(hi), Text(")"))
+	}
+	if len(params) == 0 {
+		return f.group("(", ")", nil, hi, SoftLine, false)
+	}
+
+	docs := make([]Doc, len(params))
+	for i, param := range params {
+		limit := hi
+		if i < len(params)-1 {
+			limit = outerRange(params[i+1]).Lo
+		}
+		docs[i] = Concat(f.param(param), f.trailing(outerRange(param).Hi, limit))
+	}
+	docs[len(docs)-1] = Concat(docs[len(docs)-1], f.rest(hi))
+	comma := Doc(Text(""))
+	if !endsWithRest(params) {
+		comma = IfBreak(Text(","), nil)
+	}
+	return Group(Text("("), Indent(SoftLine, Join(Concat(Text(","), Line), docs)), comma, SoftLine, Text(")"))
+}
+
+func (f *formatter) param(param parser.Node) Doc {
+	decs, lo := f.decorators(param)
+	if mod := f.paramModifiers(param.Range().Lo); mod != "" {
+		return Concat(decs, f.leading(lo), Text(mod), f.node(param))
+	}
+	return Concat(decs, f.node(param))
+}
+
+func (f *formatter) arrow(n *parser.ArrowFn) Doc {
+	docs := docConcat{}
+	if n.Async() {
+		docs = append(docs, Text("async "))
+	}
+	hi := n.Body().Range().Lo
+	if ti := n.TypInfo(); ti != nil && ti.TypAnnot() != nil {
+		hi = ti.TypAnnot().Range().Lo
+	}
+	docs = append(docs, f.typParams(n.TypInfo()), f.params(n.Params(), hi), f.typAnnot(n.TypInfo()), Text(" =>"))
+
+	body := n.Body()
+	switch body.Type() {
+	case parser.N_STMT_BLOCK, parser.N_LIT_OBJ, parser.N_LIT_ARR, parser.N_EXPR_CALL, parser.N_EXPR_TPL, parser.N_EXPR_ARROW:
+		return append(docs, Text(" "), f.node(body))
+	case parser.N_JSX_ELEM:
+		return append(docs, Text(" "), f.wrapped(body))
+	}
+	if f.breakingCmtBefore(outerRange(body).Lo) {
+		return append(docs, Indent(HardLine, f.node(body)))
+	}
+	return append(docs, Group(Indent(Line, f.node(body))))
+}
+
+// whether the argument is the function or the object literal which can be hugged by the
+// parentheses of the call
+func huggable(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.ArrowFn, *parser.FnDec:
+		return true
+	case *parser.ObjLit:
+		return len(n.Props()) > 0
+	case *parser.ArrLit:
+		return len(n.Elems()) > 0
+	}
+	return false
+}
+
+func isFn(node parser.Node) bool {
+	typ := node.Type()
+	return typ == parser.N_EXPR_ARROW || typ == parser.N_EXPR_FN
+}
+
+func (f *formatter) args(args []parser.Node, hi uint32) Doc {
+	if len(args) == 0 {
+		return f.group("(", ")", nil, hi, SoftLine, false)
+	}
+	last := args[len(args)-1]
+	hug := huggable(last)
+	for _, arg := range args[:len(args)-1] {
+		if huggable(arg) && (isFn(arg) || !isFn(last)) {
+			hug = false
+		}
+	}
+	if hug && !f.breakingCmtBefore(hi) {
+		docs := f.commaList(args, hi-1)
+		return Concat(Text("("), Join(Text(", "), docs), Text(")"))
+	}
+	return f.group("(", ")", args, hi-1, SoftLine, !endsWithSpread(args))
+}
+
+func endsWithSpread(nodes []parser.Node) bool {
+	return len(nodes) > 0 && nodes[len(nodes)-1].Type() == parser.N_SPREAD
+}
+
+// a link of the member chain, it's either a property access or a call
+type chainLink struct {
+	node parser.Node
+	call bool
+}
+
+// the member accesses and the calls are flattened, the chain is broken before the property
+// accesses after the calls if there are more than 2 calls and it does not fit in the line
+func (f *formatter) chain(node parser.Node) Doc {
+	links := make([]chainLink, 0)
+	head := node
+	for {
+		if head != node && outerRange(head) != head.Range() {
+			break
+		}
+		if m, ok := head.(*parser.MemberExpr); ok {
+			links = append(links, chainLink{m, false})
+			head = m.Obj()
+		} else if c, ok := head.(*parser.CallExpr); ok {
+			links = append(links, chainLink{c, true})
+			head = c.Callee()
+		} else {
+			break
+		}
+	}
+
+	calls := 0
+	for _, link := range links {
+		if link.call {
+			calls++
+		}
+	}
+
+	docs := docConcat{f.node(head)}
+	groups := make([]Doc, 0)
+	brk := calls > 2 && !isShortHead(head)
+	for i := len(links) - 1; i >= 0; i-- {
+		link := links[i]
+		var doc Doc
+		if link.call {
+			c := link.node.(*parser.CallExpr)
+			opt := ""
+			if c.Optional() {
+				opt = "?."
+			}
+			doc = Concat(Text(opt), f.typArgs(c.TypInfo()), f.args(c.Args(), c.Range().Hi))
+		} else {
+			m := link.node.(*parser.MemberExpr)
+			if m.Compute() {
+				opt := ""
+				if m.Optional() {
+					opt = "?."
+				}
+				doc = Concat(Text(opt+"["), f.node(m.Prop()), f.rest(m.Range().Hi), Text("]"))
+			} else {
+				dot := "."
+				if m.Optional() {
+					dot = "?."
+				}
+				// the comments before the dot are placed in their own lines
+				objHi := outerRange(m.Obj()).Hi
+				dotLo := objHi + uint32(strings.LastIndexByte(f.code[objHi:m.Prop().Range().Lo], '.'))
+				cmts, _ := f.dangling(dotLo)
+				doc = Concat(Text(dot), f.node(m.Prop()))
+				if brk && i < len(links)-1 && links[i+1].call {
+					groups = append(groups, docs)
+					docs = docConcat{}
+					if len(cmts) > 0 {
+						doc = Concat(HardLine, Join(HardLine, cmts), HardLine, doc)
+					} else {
+						doc = Concat(SoftLine, doc)
+					}
+				} else if len(cmts) > 0 {
+					doc = Indent(HardLine, Join(HardLine, cmts), HardLine, doc)
+				}
+			}
+		}
+		docs = append(docs, doc)
+	}
+	if len(groups) == 0 {
+		return docs
+	}
+	groups = append(groups, docs)
+	return Group(groups[0], Indent(groups[1:]...))
+}
+
+// the short identifiers such as `$` and `_` are kept in the same line with the first call
+func isShortHead(node parser.Node) bool {
+	if id, ok := node.(*parser.Ident); ok {
+		return len(id.Val()) <= 2
+	}
+	return node.Type() == parser.N_EXPR_THIS
+}
+
+// the binary expressions on the left-hand side are flattened, the operators are placed at the
+// end of the lines if the expression is broken, the rest lines are indented if `ind` is true
+func (f *formatter) binary(n *parser.BinExpr, ind bool) Doc {
+	ops := make([]*parser.BinExpr, 0)
+	var head parser.Node = n
+	for {
+		b, ok := head.(*parser.BinExpr)
+		if !ok || isTsBin(b) || head != n && !b.OuterParen().Empty() {
+			break
+		}
+		ops = append(ops, b)
+		head = b.Lhs()
+	}
+
+	tail := docConcat{}
+	first := f.node(head)
+	for i := len(ops) - 1; i >= 0; i-- {
+		b := ops[i]
+		tail = append(tail, Text(" "+b.OpText()), Line, f.node(b.Rhs()))
+	}
+	if ind {
+		return Group(first, Indent(tail))
+	}
+	return Group(first, tail)
+}
+
+func (f *formatter) unary(n *parser.UnaryExpr) Doc {
+	op := n.OpText()
+	if op[0] >= 'a' && op[0] <= 'z' {
+		return Concat(Text(op+" "), f.node(n.Arg()))
+	}
+	arg := n.Arg()
+	if outerRange(arg) == arg.Range() {
+		switch a := arg.(type) {
+		case *parser.UnaryExpr:
+			if a.OpText()[0] == op[0] {
+				return Concat(Text(op+" "), f.node(arg))
+			}
+		case *parser.UpdateExpr:
+			if a.Prefix() && a.OpText()[0] == op[0] {
+				return Concat(Text(op+" "), f.node(arg))
+			}
+		}
+	}
+	return Concat(Text(op), f.node(arg))
+}
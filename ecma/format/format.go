@@ -0,0 +1,287 @@
+package format
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/estree"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+type Opts struct {
+	LineWidth   int
+	IndentWidth int
+	UseTabs     bool
+
+	// the options used to parse the source, they are adjusted by the extension of the file,
+	// see `parser.ParserOpts.OfFile`
+	ParserOpts *parser.ParserOpts
+}
+
+func NewOpts() *Opts {
+	return &Opts{
+		LineWidth:   80,
+		IndentWidth: 2,
+		ParserOpts:  parser.NewParserOpts(),
+	}
+}
+
+func (o *Opts) indent() string {
+	if o.UseTabs {
+		return "\t"
+	}
+	return strings.Repeat(" ", o.IndentWidth)
+}
+
+func parse(file, code string, opts *Opts) (*parser.Parser, *parser.Prog, error) {
+	p := parser.NewParser(span.NewSource(file, code), opts.ParserOpts.OfFile(file))
+	ast, err := p.Prog()
+	if err != nil {
+		return nil, nil, err
+	}
+	return p, ast.(*parser.Prog), nil
+}
+
+// formats the code in the opinionated style, the layout of the code is decided by the line
+// width and the blank lines between the statements are preserved, the comments are attached
+// to their nearest nodes so they are all kept, the constructs of typescript other than the
+// type annotations of the javascript constructs are kept as they are
+//
+// the typescript declarations like the interfaces, the type aliases, the enums, the namespaces
+// and the ambient modules are not formatted, they are passed through, their source text is
+// copied as it is, including the whitespaces and the comments inside them, only the statements
+// around them are formatted
+//
+// the formatted code is parsed again to make sure it's equivalent to the source, an error is
+// returned if it's not, which means there is a bug of the formatter
+func Format(file, code string, opts *Opts) (string, error) {
+	if opts == nil {
+		opts = NewOpts()
+	}
+	p, ast, err := parse(file, code, opts)
+	if err != nil {
+		return "", err
+	}
+
+	f := newFormatter(p, code, opts)
+	out := PrintDoc(f.prog(ast), &DocPrinterOpts{opts.LineWidth, opts.indent(), ""})
+	if out != "" {
+		out += "\n"
+	}
+	if err := verify(file, code, out, opts, p, ast); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// formats the statements which intersect the range `[lo, hi)`, the statements are searched in
+// the innermost statement list which contains the range, the source outside the statements is
+// kept as it is, it's used by the editors to format the selection
+func FormatRange(file, code string, lo, hi uint32, opts *Opts) (string, error) {
+	if opts == nil {
+		opts = NewOpts()
+	}
+	p, ast, err := parse(file, code, opts)
+	if err != nil {
+		return "", err
+	}
+
+	stmts := stmtsInRange(ast.Body(), lo, hi)
+	if len(stmts) == 0 {
+		return code, nil
+	}
+	f := newFormatter(p, code, opts)
+	first, last := stmts[0].Range(), stmts[len(stmts)-1].Range()
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Lo < first.Lo {
+		f.ci++
+	}
+
+	doc := f.stmts(stmts, last.Hi)
+	end := last.Hi
+	if f.ci > 0 && f.cmts[f.ci-1].Hi > end {
+		end = f.cmts[f.ci-1].Hi
+	}
+	base := util.IndentOf(code, first.Lo)
+	out := code[:first.Lo] + PrintDoc(doc, &DocPrinterOpts{opts.LineWidth, opts.indent(), base}) + code[end:]
+	if err := verify(file, code, out, opts, p, ast); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// the statements intersect the range in the innermost statement list which contains the range
+func stmtsInRange(list []parser.Node, lo, hi uint32) []parser.Node {
+	ret := make([]parser.Node, 0)
+	for _, stmt := range list {
+		rng := stmt.Range()
+		if rng.Lo < hi && lo < rng.Hi || rng.Lo == lo && lo == hi {
+			ret = append(ret, stmt)
+		}
+	}
+	if len(ret) != 1 {
+		return ret
+	}
+
+	for _, inner := range innerStmtLists(ret[0]) {
+		if len(inner) == 0 {
+			continue
+		}
+		if inner[0].Range().Lo <= lo && hi <= inner[len(inner)-1].Range().Hi {
+			if sub := stmtsInRange(inner, lo, hi); len(sub) > 0 {
+				return sub
+			}
+		}
+	}
+	return ret
+}
+
+func innerStmtLists(stmt parser.Node) [][]parser.Node {
+	ret := make([][]parser.Node, 0)
+	add := func(nodes ...parser.Node) {
+		for _, n := range nodes {
+			if n == nil {
+				continue
+			}
+			if b, ok := n.(*parser.BlockStmt); ok {
+				ret = append(ret, b.Body())
+			} else if n.Type() > parser.N_STMT_BEGIN && n.Type() < parser.N_STMT_END {
+				ret = append(ret, []parser.Node{n})
+			}
+		}
+	}
+	switch n := stmt.(type) {
+	case *parser.BlockStmt:
+		add(n)
+	case *parser.FnDec:
+		add(n.Body())
+	case *parser.IfStmt:
+		add(n.Cons(), n.Alt())
+	case *parser.ForStmt:
+		add(n.Body())
+	case *parser.ForInOfStmt:
+		add(n.Body())
+	case *parser.WhileStmt:
+		add(n.Body())
+	case *parser.DoWhileStmt:
+		add(n.Body())
+	case *parser.LabelStmt:
+		add(n.Body())
+	case *parser.WithStmt:
+		add(n.Body())
+	case *parser.TryStmt:
+		add(n.Try(), n.Fin())
+		if n.Catch() != nil {
+			add(n.Catch().(*parser.Catch).Body())
+		}
+	case *parser.SwitchStmt:
+		for _, c := range n.Cases() {
+			ret = append(ret, c.(*parser.SwitchCase).Cons())
+		}
+	case *parser.ExportDec:
+		if n.Dec() != nil {
+			return innerStmtLists(n.Dec())
+		}
+	}
+	return ret
+}
+
+// makes sure the formatted code is equivalent to the source by comparing their ESTree outputs
+// without the positions and the raw texts, and all the comments are kept
+func verify(file, code, out string, opts *Opts, p *parser.Parser, ast *parser.Prog) error {
+	p2, ast2, err := parse(file, out, opts)
+	if err != nil {
+		return fmt.Errorf("the formatted code is invalid: %v", err)
+	}
+	a, err := normalizedTree(p, ast)
+	if err != nil {
+		return err
+	}
+	b, err := normalizedTree(p2, ast2)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(a, b) {
+		return errors.New("the formatted code is not equivalent to the source")
+	}
+	if cmtsDigest(code, p.Comments()) != cmtsDigest(out, p2.Comments()) {
+		return errors.New("the comments are not kept in the formatted code")
+	}
+	return nil
+}
+
+func cmtsDigest(code string, cmts []span.Range) string {
+	var b strings.Builder
+	for _, c := range cmts {
+		b.WriteString(strings.Join(strings.Fields(code[c.Lo:c.Hi]), ""))
+	}
+	return b.String()
+}
+
+func normalizedTree(p *parser.Parser, ast *parser.Prog) (ret interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("failed to convert the AST: %v", r)
+		}
+	}()
+	ctx := estree.NewConvertCtx(p)
+	ctx.LineCol = false
+	b, err := json.Marshal(estree.ConvertProg(ast, ctx))
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &ret); err != nil {
+		return nil, err
+	}
+	return normalizeTree(ret), nil
+}
+
+func normalizeTree(node interface{}) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for _, key := range []string{"start", "end", "loc", "range", "raw"} {
+			delete(n, key)
+		}
+		if n["type"] == "JSXText" {
+			n["value"] = cleanJsxText(n["value"].(string))
+		}
+		for k, v := range n {
+			n[k] = normalizeTree(v)
+		}
+	case []interface{}:
+		ret := make([]interface{}, 0, len(n))
+		for _, v := range n {
+			if m, ok := v.(map[string]interface{}); ok && m["type"] == "JSXText" && cleanJsxText(m["value"].(string)) == "" {
+				continue
+			}
+			ret = append(ret, normalizeTree(v))
+		}
+		return ret
+	}
+	return node
+}
+
+// the text of the jsx text after the whitespaces are handled in the way of the jsx
+// transform, the lines are trimmed, the empty lines are removed and the rest lines are
+// joined with a space
+func cleanJsxText(s string) string {
+	lines := strings.Split(s, "\n")
+	parts := make([]string, 0, len(lines))
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if i > 0 {
+			line = strings.TrimLeft(line, " \t")
+		}
+		if i < len(lines)-1 {
+			line = strings.TrimRight(line, " \t")
+		}
+		if line != "" {
+			parts = append(parts, line)
+		}
+	}
+	return strings.Join(parts, " ")
+}
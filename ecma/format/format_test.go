@@ -0,0 +1,178 @@
+package format
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func assertFormat(t *testing.T, file, code, expected string, opts *Opts) {
+	out, err := Format(file, code, opts)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, expected, out, "should be ok")
+
+	again, err := Format(file, out, opts)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, out, again, "should be stable")
+}
+
+func TestFormatStmts(t *testing.T) {
+	assertFormat(t, "a.js", "let a=1,b={x:1,y:[1,2,3]}\nif(a){b()}else if(c)d()\nfor(let i=0;i<10;i++){}", `let a = 1,
+  b = { x: 1, y: [1, 2, 3] };
+if (a) {
+  b();
+} else if (c) d();
+for (let i = 0; i < 10; i++) {}
+`, nil)
+
+	assertFormat(t, "a.js", "switch(x){case 1:case 2:y();break;default:}\ntry{a()}catch{b()}finally{c()}", `switch (x) {
+  case 1:
+  case 2:
+    y();
+    break;
+  default:
+}
+try {
+  a();
+} catch {
+  b();
+} finally {
+  c();
+}
+`, nil)
+}
+
+func TestFormatImportAttrs(t *testing.T) {
+	assertFormat(t, "a.js", `import a from"./a.json"with{type:"json"}
+import"./b.css"assert{"type":'css'}
+export*from"./c.json"with{type:"json"}
+export{d}from"./d.json"with{type:"json"}
+import("./e.json",{with:{type:"json"}})`, `import a from "./a.json" with { type: "json" };
+import "./b.css" assert { "type": "css" };
+export * from "./c.json" with { type: "json" };
+export { d } from "./d.json" with { type: "json" };
+import("./e.json", { with: { type: "json" } });
+`, nil)
+}
+
+func TestFormatBlankLines(t *testing.T) {
+	assertFormat(t, "a.js", "a()\n\n\n\nb()\nc()\n", "a();\n\nb();\nc();\n", nil)
+}
+
+func TestFormatComments(t *testing.T) {
+	assertFormat(t, "a.js", `// c1
+function f(a,b){return a+b} // t
+
+/**
+   * doc
+   */
+const x = [ /* empty */ ]
+`, `// c1
+function f(a, b) {
+  return a + b;
+} // t
+
+/**
+ * doc
+ */
+const x = [/* empty */];
+`, nil)
+
+	// the comments start in their own lines are kept in their own lines
+	assertFormat(t, "a.js", "const o = {\n  a: 1, // t\n  // c\n};\nf(a,\n /* d */\n)\nx=[1,2\n/* e */]", `const o = {
+  a: 1, // t
+  // c
+};
+f(
+  a,
+  /* d */
+);
+x = [
+  1,
+  2,
+  /* e */
+];
+`, nil)
+
+	assertFormat(t, "a.js", "function f(){return ( // c\n a )}", `function f() {
+  return (
+    // c
+    a
+  );
+}
+`, nil)
+}
+
+func TestFormatLineWidth(t *testing.T) {
+	assertFormat(t, "a.js", "const veryLongVariableNameForTesting = someFunction(argumentNumberOne, argumentNumberTwo, argumentNumberThree)", `const veryLongVariableNameForTesting = someFunction(
+  argumentNumberOne,
+  argumentNumberTwo,
+  argumentNumberThree,
+);
+`, nil)
+
+	opts := NewOpts()
+	opts.LineWidth = 20
+	opts.UseTabs = true
+	assertFormat(t, "a.js", "foo(aaaaaaaa, bbbbbbbb, cccccccc)", "foo(\n\taaaaaaaa,\n\tbbbbbbbb,\n\tcccccccc,\n);\n", opts)
+}
+
+func TestFormatTs(t *testing.T) {
+	assertFormat(t, "a.ts", "const x:number=foo<string>( a ) as any\ninterface A { b: string }", `const x: number = foo<string>(a) as any;
+interface A { b: string }
+`, nil)
+}
+
+func TestFormatTsDecs(t *testing.T) {
+	// the typescript declarations are kept as they are, only their indentation follows the
+	// enclosing statements
+	assertFormat(t, "a.ts", "interface  A{b:string;c ?: number}\ntype  B={x:1}|2\nenum C{a=1,b}\nexport  interface D{}\nif(a){type E=[1]}", `interface  A{b:string;c ?: number}
+type  B={x:1}|2
+enum C{a=1,b}
+export interface D{}
+if (a) {
+  type E=[1]
+}
+`, nil)
+
+	assertFormat(t, "a.ts", "namespace  N{export const a=1}\ndeclare module 'm'{ export type T=1 }\nlet x={a:1}", `namespace  N{export const a=1}
+declare module 'm'{ export type T=1 }
+let x = { a: 1 };
+`, nil)
+}
+
+func TestFormatForInit(t *testing.T) {
+	assertFormat(t, "a.js", `for(let i=("a" in b);;){}
+for(i=("a" in b);;){}
+for(let f=()=>("a" in b);;){}
+for(let i=(a+("a" in b));;){}
+let x=("a" in b)`, `for (let i = ("a" in b);;) {}
+for (i = ("a" in b);;) {}
+for (let f = () => ("a" in b);;) {}
+for (let i = (a + ("a" in b));;) {}
+let x = "a" in b;
+`, nil)
+}
+
+func TestFormatJsx(t *testing.T) {
+	assertFormat(t, "a.jsx", `const e=<div a="1" {...p}>hi {x}</div>`, `const e = <div a="1" {...p}>hi {x}</div>;
+`, nil)
+}
+
+func TestFormatRange(t *testing.T) {
+	code := "if(a){\n  b( 1 )\n  c( 2 )\n}\n"
+	out, err := FormatRange("a.js", code, 15, 20, nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, "if(a){\n  b( 1 )\n  c(2);\n}\n", out, "should be ok")
+}
+
+func TestFormatSyntaxErr(t *testing.T) {
+	_, err := Format("a.js", "let = 1", nil)
+	AssertEqual(t, true, err != nil, "should be failed")
+}
+
+func TestPrintDoc(t *testing.T) {
+	doc := Group(Text("["), Indent(SoftLine, Join(Concat(Text(","), Line), []Doc{Text("a"), Text("b")})), SoftLine, Text("]"))
+	AssertEqualString(t, "[a, b]", PrintDoc(doc, &DocPrinterOpts{80, "  ", ""}), "should be ok")
+	AssertEqualString(t, "[\n  a,\n  b\n]", PrintDoc(doc, &DocPrinterOpts{3, "  ", ""}), "should be ok")
+}
@@ -0,0 +1,159 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+)
+
+func (f *formatter) jsxElem(n *parser.JsxElem) Doc {
+	open := f.jsxOpen(n.Open().(*parser.JsxOpen))
+	if n.Close() == nil {
+		return open
+	}
+	children := f.jsxChildren(n.Children())
+	close := f.jsxClose(n.Close().(*parser.JsxClose))
+	if children == nil {
+		return Concat(open, close)
+	}
+	return Group(open, children, close)
+}
+
+func (f *formatter) jsxOpen(n *parser.JsxOpen) Doc {
+	if n.Name() == nil {
+		return Text("<>")
+	}
+	name := Concat(Text("<"), f.raw(n.Name().Range()), f.typArgsOf(n.Name()))
+	attrs := n.Attrs()
+	if len(attrs) == 0 {
+		if n.Closed() {
+			return Concat(name, f.rest(n.Range().Hi), Text(" />"))
+		}
+		return Concat(name, f.rest(n.Range().Hi), Text(">"))
+	}
+
+	docs := make([]Doc, len(attrs))
+	for i, attr := range attrs {
+		limit := n.Range().Hi
+		if i < len(attrs)-1 {
+			limit = attrs[i+1].Range().Lo
+		}
+		docs[i] = Concat(f.node(attr), f.trailing(attr.Range().Hi, limit))
+	}
+	docs[len(docs)-1] = Concat(docs[len(docs)-1], f.rest(n.Range().Hi))
+	if n.Closed() {
+		return Group(name, Indent(Line, Join(Line, docs)), Line, Text("/>"))
+	}
+	return Group(name, Indent(Line, Join(Line, docs)), SoftLine, Text(">"))
+}
+
+func (f *formatter) jsxClose(n *parser.JsxClose) Doc {
+	if n.Name() == nil {
+		return Text("</>")
+	}
+	return Concat(Text("</"), f.raw(n.Name().Range()), Text(">"))
+}
+
+func (f *formatter) jsxAttr(node parser.Node) Doc {
+	switch n := node.(type) {
+	case *parser.JsxAttr:
+		name := f.raw(n.Name().Range())
+		if n.Val() == nil {
+			return name
+		}
+		val := n.Val()
+		if val.Type() == parser.N_LIT_STR {
+			return Concat(name, Text("="), f.raw(val.Range()))
+		}
+		return Concat(name, Text("="), f.node(val))
+	case *parser.JsxSpreadAttr:
+		return Concat(Text("{"), f.node(n.Arg()), f.rest(n.Range().Hi), Text("}"))
+	case *parser.JsxExprSpan:
+		if n.Expr().Type() == parser.N_JSX_EMPTY {
+			return f.raw(n.Range())
+		}
+		return Concat(Text("{"), f.node(n.Expr()), f.rest(n.Range().Hi), Text("}"))
+	case *parser.JsxSpreadChild:
+		return Concat(Text("{..."), f.node(n.Expr()), f.rest(n.Range().Hi), Text("}"))
+	}
+	return f.raw(node.Range())
+}
+
+func isJsxSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r'
+}
+
+// the separator of the children which is represented by the whitespaces between them, the
+// whitespaces contain a line break are insignificant, the other ones are kept as a space
+func jsxSep(ws string, words bool) Doc {
+	if ws == "" || strings.ContainsAny(ws, "\n\r") {
+		if words {
+			return Line
+		}
+		return SoftLine
+	}
+	if words {
+		return Line
+	}
+	return Text(" ")
+}
+
+// the children are filled in the lines, the words of the texts are reflowed, nil is returned
+// if there are only the insignificant whitespaces
+func (f *formatter) jsxChildren(children []parser.Node) Doc {
+	parts := make([]Doc, 0)
+	pendingWs := "" // the whitespaces after the last content
+	prevWord := false
+	started := false
+	var lead Doc = SoftLine
+
+	push := func(doc Doc, word bool) {
+		if !started {
+			if pendingWs != "" && !strings.ContainsAny(pendingWs, "\n\r") {
+				lead = Text(" ")
+			}
+			started = true
+		} else {
+			parts = append(parts, jsxSep(pendingWs, word && prevWord))
+		}
+		parts = append(parts, doc)
+		pendingWs = ""
+		prevWord = word
+	}
+
+	for _, child := range children {
+		txt, ok := child.(*parser.JsxText)
+		if !ok {
+			push(f.node(child), false)
+			continue
+		}
+
+		s := f.text(txt.Range())
+		i := 0
+		for i < len(s) {
+			j := i
+			for j < len(s) && isJsxSpace(rune(s[j])) {
+				j++
+			}
+			pendingWs += s[i:j]
+			if j == len(s) {
+				break
+			}
+			k := j
+			for k < len(s) && !isJsxSpace(rune(s[k])) {
+				k++
+			}
+			push(Text(s[j:k]), true)
+			i = k
+		}
+	}
+	if !started {
+		return nil
+	}
+
+	var tail Doc = SoftLine
+	if pendingWs != "" && !strings.ContainsAny(pendingWs, "\n\r") {
+		tail = Text(" ")
+	}
+	return Concat(Indent(lead, Fill(parts...)), tail)
+}
@@ -0,0 +1,312 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// converts the AST to the doc, the comments are printed in the order of their positions, the
+// ones before a node are printed as its leading comments and the ones after a node in the same
+// line are printed as its trailing comments
+type formatter struct {
+	opts *Opts
+	p    *parser.Parser
+	code string
+
+	cmts []span.Range
+	ci   int // the index of the first comment which is not printed
+
+	// whether the head of the `for` statement is being printed, the parentheses around the
+	// binary expressions are kept in it since the `in` operator in them would be taken as
+	// the one of the `for-in` statement
+	noIn bool
+}
+
+func newFormatter(p *parser.Parser, code string, opts *Opts) *formatter {
+	return &formatter{opts: opts, p: p, code: code, cmts: p.Comments()}
+}
+
+func (f *formatter) text(rng span.Range) string {
+	return f.code[rng.Lo:rng.Hi]
+}
+
+func (f *formatter) isLineCmt(c span.Range) bool {
+	return strings.HasPrefix(f.code[c.Lo:c.Hi], "//")
+}
+
+func (f *formatter) hasNewline(lo, hi uint32) bool {
+	return lo < hi && strings.ContainsAny(f.code[lo:hi], "\n\r")
+}
+
+// whether the line after the one of `ofst` is empty
+func (f *formatter) blankLineAfter(ofst uint32) bool {
+	i := strings.IndexByte(f.code[ofst:], '\n')
+	if i == -1 {
+		return false
+	}
+	for j := int(ofst) + i + 1; j < len(f.code); j++ {
+		switch f.code[j] {
+		case ' ', '\t', '\r':
+			continue
+		case '\n':
+			return true
+		}
+		return false
+	}
+	return false
+}
+
+// the first offset after `ofst` which is not a whitespace
+func (f *formatter) skipSpaces(ofst uint32) uint32 {
+	for int(ofst) < len(f.code) && strings.IndexByte(" \t\r\n", f.code[ofst]) != -1 {
+		ofst++
+	}
+	return ofst
+}
+
+// the doc of the comment, the lines of the block comment are re-indented if they are aligned
+// by `*` like the jsdoc comments
+func (f *formatter) cmtDoc(c span.Range) Doc {
+	lines := strings.Split(f.text(c), "\n")
+	if len(lines) == 1 {
+		return Text(strings.TrimRight(lines[0], "\r"))
+	}
+	for _, line := range lines[1:] {
+		if !strings.HasPrefix(strings.TrimSpace(line), "*") {
+			return Text(f.text(c))
+		}
+	}
+	docs := docConcat{Text(strings.TrimRight(lines[0], " \t\r"))}
+	for _, line := range lines[1:] {
+		docs = append(docs, HardLine, Text(" "+strings.TrimSpace(line)))
+	}
+	return docs
+}
+
+// the comments end before `lo` which are not printed yet
+func (f *formatter) leading(lo uint32) Doc {
+	docs := docConcat{}
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Hi <= lo {
+		c := f.cmts[f.ci]
+		f.ci++
+		docs = append(docs, f.cmtDoc(c))
+		if f.isLineCmt(c) || f.hasNewline(c.Hi, f.skipSpaces(c.Hi)) {
+			docs = append(docs, HardLine)
+			if f.blankLineAfter(c.Hi) {
+				docs = append(docs, HardLine)
+			}
+		} else {
+			docs = append(docs, Text(" "))
+		}
+	}
+	return docs
+}
+
+// whether there is a line comment or a comment followed by a line break before `lo`, it's
+// used to keep the arguments of the restricted productions such as `return` in the same
+// line with their keywords
+func (f *formatter) breakingCmtBefore(lo uint32) bool {
+	for i := f.ci; i < len(f.cmts) && f.cmts[i].Hi <= lo; i++ {
+		c := f.cmts[i]
+		if f.isLineCmt(c) || f.hasNewline(c.Hi, f.skipSpaces(c.Hi)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formatter) trailingCmt(c span.Range) Doc {
+	if f.isLineCmt(c) {
+		return Concat(LineSuffix(Text(" "), f.cmtDoc(c)), BreakParent)
+	}
+	return Concat(Text(" "), f.cmtDoc(c))
+}
+
+// the comments start before `limit` and in the same line with `hi`
+func (f *formatter) trailing(hi, limit uint32) Doc {
+	docs := docConcat{}
+	for f.ci < len(f.cmts) {
+		c := f.cmts[f.ci]
+		if c.Lo >= limit || f.hasNewline(hi, c.Lo) {
+			break
+		}
+		f.ci++
+		docs = append(docs, f.trailingCmt(c))
+		hi = c.Hi
+	}
+	return docs
+}
+
+// the comments end before `hi` which are not printed yet, as the trailing comments
+func (f *formatter) rest(hi uint32) Doc {
+	docs := docConcat{}
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Hi <= hi {
+		docs = append(docs, f.trailingCmt(f.cmts[f.ci]))
+		f.ci++
+	}
+	return docs
+}
+
+// the comments end before `hi` which are not printed yet, as the contents of an empty
+// container such as `{}`, `brk` is true if any of them requires the container to be broken
+func (f *formatter) dangling(hi uint32) (docs []Doc, brk bool) {
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Hi <= hi {
+		c := f.cmts[f.ci]
+		f.ci++
+		docs = append(docs, f.cmtDoc(c))
+		if f.isLineCmt(c) || f.hasNewline(c.Lo, c.Hi) {
+			brk = true
+		}
+	}
+	return
+}
+
+// the source text of the range is printed as it is, the comments in the range are skipped
+func (f *formatter) raw(rng span.Range) Doc {
+	lead := f.leading(rng.Lo)
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Lo < rng.Hi {
+		f.ci++
+	}
+	return Concat(lead, Text(strings.TrimRight(f.text(rng), " \t\r\n")))
+}
+
+// the range of the node including its parentheses
+func outerRange(node parser.Node) span.Range {
+	if n, ok := node.(parser.InParenNode); ok {
+		if rng := n.OuterParen(); !rng.Empty() {
+			return rng
+		}
+	}
+	return node.Range()
+}
+
+// the doc of the node with its comments and parentheses
+func (f *formatter) node(node parser.Node) Doc {
+	rng := outerRange(node)
+	lead := f.leading(rng.Lo)
+	doc := f.print(node)
+	if rng != node.Range() {
+		doc = Concat(Text("("), doc, f.rest(node.Range().Hi), Text(")"))
+	}
+	return Concat(lead, doc, f.rest(rng.Hi))
+}
+
+// prints the list of the nodes which are separated by commas, the comments after a node in
+// the same line are kept in the same line with it, `hi` is the end of the list
+func (f *formatter) commaList(nodes []parser.Node, hi uint32) []Doc {
+	docs := f.commaItems(nodes, hi)
+	if n := len(docs); n > 0 {
+		docs[n-1] = Concat(docs[n-1], f.tail(hi))
+	}
+	return docs
+}
+
+// the docs of the nodes separated by commas without the comments after the last one except
+// the ones in the same line with it
+func (f *formatter) commaItems(nodes []parser.Node, hi uint32) []Doc {
+	docs := make([]Doc, len(nodes))
+	for i, n := range nodes {
+		if n == nil {
+			docs[i] = Text("")
+			continue
+		}
+		limit := hi
+		if i < len(nodes)-1 && nodes[i+1] != nil {
+			limit = outerRange(nodes[i+1]).Lo
+		}
+		docs[i] = Concat(f.node(n), f.trailing(outerRange(n).Hi, limit))
+	}
+	return docs
+}
+
+// the comments end before `hi` which are not printed yet, the ones start in their own lines
+// are kept in their own lines and the others are printed as the trailing comments
+func (f *formatter) tail(hi uint32) Doc {
+	docs := docConcat{}
+	prev := uint32(0)
+	if f.ci > 0 {
+		prev = f.cmts[f.ci-1].Hi
+	}
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Hi <= hi {
+		c := f.cmts[f.ci]
+		f.ci++
+		if f.hasNewline(prev, c.Lo) {
+			docs = append(docs, HardLine, f.cmtDoc(c))
+		} else {
+			docs = append(docs, f.trailingCmt(c))
+		}
+		prev = c.Hi
+	}
+	return docs
+}
+
+// the docs of the nodes separated by commas in a group, they are placed one per line with a
+// trailing comma if the group is broken, the comments in their own lines after the last node
+// are placed after the trailing comma
+func (f *formatter) group(open, close string, nodes []parser.Node, hi uint32, line Doc, trailingComma bool) Doc {
+	if len(nodes) == 0 {
+		cmts, brk := f.dangling(hi)
+		if len(cmts) == 0 {
+			return Text(open + close)
+		}
+		if brk {
+			return Concat(Text(open), Indent(HardLine, Join(HardLine, cmts)), HardLine, Text(close))
+		}
+		return Concat(Text(open), Join(Text(" "), cmts), Text(close))
+	}
+	comma := Doc(Text(""))
+	if trailingComma {
+		comma = IfBreak(Text(","), nil)
+	}
+	docs := f.commaItems(nodes, hi)
+	return Group(Text(open), Indent(line, Join(Concat(Text(","), Line), docs), comma, f.tail(hi)), line, Text(close))
+}
+
+// prints the statements one per line, the blank lines between them are preserved
+func (f *formatter) stmts(stmts []parser.Node, hi uint32) Doc {
+	docs := docConcat{}
+	prev := uint32(0)
+	for i, stmt := range stmts {
+		rng := stmt.Range()
+		if i > 0 {
+			docs = append(docs, HardLine)
+			if f.blankLineAfter(prev) && f.blankBetween(prev, rng.Lo) {
+				docs = append(docs, HardLine)
+			}
+		}
+		limit := hi
+		if i < len(stmts)-1 {
+			limit = stmts[i+1].Range().Lo
+		}
+		docs = append(docs, f.node(stmt), f.trailing(rng.Hi, limit))
+		prev = rng.Hi
+		if f.ci > 0 && f.cmts[f.ci-1].Hi > prev && f.cmts[f.ci-1].Hi <= limit {
+			prev = f.cmts[f.ci-1].Hi
+		}
+	}
+
+	// the comments after the last statement
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Hi <= hi {
+		c := f.cmts[f.ci]
+		f.ci++
+		if len(docs) > 0 {
+			docs = append(docs, HardLine)
+			if f.blankLineAfter(prev) && f.blankBetween(prev, c.Lo) {
+				docs = append(docs, HardLine)
+			}
+		}
+		docs = append(docs, f.cmtDoc(c))
+		prev = c.Hi
+	}
+	return docs
+}
+
+// whether there is an empty line between `lo` and the first comment or token after it
+func (f *formatter) blankBetween(lo, hi uint32) bool {
+	if f.ci < len(f.cmts) && f.cmts[f.ci].Lo < hi && f.cmts[f.ci].Lo >= lo {
+		hi = f.cmts[f.ci].Lo
+	}
+	return strings.Count(f.code[lo:hi], "\n") >= 2
+}
@@ -0,0 +1,557 @@
+package format
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+func (f *formatter) prog(ast *parser.Prog) Doc {
+	return f.stmts(ast.Body(), uint32(len(f.code)))
+}
+
+// the doc of the node without its comments and parentheses
+func (f *formatter) print(node parser.Node) Doc {
+	typ := node.Type()
+	if typ > parser.N_TS_BEGIN && typ < parser.N_TS_END {
+		return f.raw(node.Range())
+	}
+
+	switch n := node.(type) {
+	case *parser.ExprStmt:
+		if n.Dir() {
+			return Concat(f.raw(n.Expr().Range()), Text(";"))
+		}
+		return Concat(f.node(n.Expr()), Text(";"))
+	case *parser.EmptyStmt:
+		return Text(";")
+	case *parser.VarDecStmt:
+		return Concat(f.varDecs(n), Text(";"))
+	case *parser.FnDec:
+		doc := f.fn(n)
+		if n.IsSig() && typ == parser.N_STMT_FN {
+			return Concat(doc, Text(";"))
+		}
+		return doc
+	case *parser.BlockStmt:
+		return f.block(n.Body(), n.Range().Hi)
+	case *parser.IfStmt:
+		return f.ifStmt(n)
+	case *parser.ForStmt:
+		return f.forStmt(n)
+	case *parser.ForInOfStmt:
+		kw := "for"
+		if n.Await() {
+			kw = "for await"
+		}
+		op := " of "
+		if n.In() {
+			op = " in "
+		}
+		return Concat(Text(kw+" ("), f.forInit(n.Left()), Text(op), f.node(n.Right()), Text(")"), f.clause(n.Body()))
+	case *parser.WhileStmt:
+		return Concat(Text("while ("), f.node(n.Test()), Text(")"), f.clause(n.Body()))
+	case *parser.DoWhileStmt:
+		doc := Concat(Text("do"), f.clause(n.Body()))
+		if n.Body().Type() == parser.N_STMT_BLOCK {
+			return Concat(doc, Text(" while ("), f.node(n.Test()), Text(");"))
+		}
+		return Concat(doc, HardLine, Text("while ("), f.node(n.Test()), Text(");"))
+	case *parser.SwitchStmt:
+		return f.switchStmt(n)
+	case *parser.SwitchCase:
+		return f.switchCase(n)
+	case *parser.BrkStmt:
+		return f.jump("break", n.Label())
+	case *parser.ContStmt:
+		return f.jump("continue", n.Label())
+	case *parser.LabelStmt:
+		if n.Body().Type() == parser.N_STMT_EMPTY {
+			return Concat(f.node(n.Label()), Text(":;"))
+		}
+		return Concat(f.node(n.Label()), Text(": "), f.node(n.Body()))
+	case *parser.RetStmt:
+		return f.restricted("return", n.Arg())
+	case *parser.ThrowStmt:
+		return f.restricted("throw", n.Arg())
+	case *parser.TryStmt:
+		return f.tryStmt(n)
+	case *parser.Catch:
+		doc := Text("catch ")
+		if n.Param() != nil {
+			doc = Concat(Text("catch ("), f.node(n.Param()), Text(") "))
+		}
+		return Concat(doc, f.node(n.Body()))
+	case *parser.DebugStmt:
+		return Text("debugger;")
+	case *parser.WithStmt:
+		return Concat(Text("with ("), f.node(n.Expr()), Text(")"), f.clause(n.Body()))
+	case *parser.ClassDec:
+		return f.class(n)
+	case *parser.ClassBody:
+		return f.block(n.Elems(), n.Range().Hi)
+	case *parser.Method:
+		return f.method(n)
+	case *parser.Field:
+		return f.field(n)
+	case *parser.StaticBlock:
+		return Concat(Text("static "), f.block(n.Body(), n.Range().Hi))
+	case *parser.ImportDec:
+		return f.importDec(n)
+	case *parser.ImportSpec:
+		return f.importSpec(n)
+	case *parser.ImportAttr:
+		return Concat(f.node(n.Key()), Text(": "), f.node(n.Val()))
+	case *parser.ExportDec:
+		return f.exportDec(n)
+	case *parser.ExportSpec:
+		prefix := Text("")
+		if n.Range().Lo < n.Local().Range().Lo {
+			prefix = Text("type ")
+		}
+		if n.Id() == nil || n.Id().Range() == n.Local().Range() {
+			return Concat(prefix, f.node(n.Local()))
+		}
+		return Concat(prefix, f.node(n.Local()), Text(" as "), f.node(n.Id()))
+	}
+	return f.expr(node)
+}
+
+// the statements in braces, `hi` is the end of the closing brace
+func (f *formatter) block(body []parser.Node, hi uint32) Doc {
+	if len(body) == 0 {
+		cmts, _ := f.dangling(hi - 1)
+		if len(cmts) == 0 {
+			return Text("{}")
+		}
+		return Concat(Text("{"), Indent(HardLine, Join(HardLine, cmts)), HardLine, Text("}"))
+	}
+	return Concat(Text("{"), Indent(HardLine, f.stmts(body, hi-1)), HardLine, Text("}"))
+}
+
+// the body of the compound statements, the non-block one is placed in the next line if it
+// does not fit in the line
+func (f *formatter) clause(body parser.Node) Doc {
+	switch body.Type() {
+	case parser.N_STMT_BLOCK:
+		return Concat(Text(" "), f.node(body))
+	case parser.N_STMT_EMPTY:
+		return f.node(body)
+	}
+	return Group(Indent(Line, f.node(body)))
+}
+
+func (f *formatter) ifStmt(n *parser.IfStmt) Doc {
+	doc := Concat(Text("if ("), f.node(n.Test()), Text(")"), f.clause(n.Cons()))
+	if n.Alt() == nil {
+		return doc
+	}
+
+	sep := Text(" ")
+	if n.Cons().Type() != parser.N_STMT_BLOCK {
+		sep = HardLine
+	}
+	alt := n.Alt()
+	if alt.Type() == parser.N_STMT_IF {
+		return Concat(doc, sep, Text("else "), f.node(alt))
+	}
+	return Concat(doc, sep, Text("else"), f.clause(alt))
+}
+
+// the variable declarations or the expressions in the head of the `for` statements
+func (f *formatter) forInit(node parser.Node) Doc {
+	noIn := f.noIn
+	f.noIn = true
+	defer func() { f.noIn = noIn }()
+	if n, ok := node.(*parser.VarDecStmt); ok {
+		return Concat(f.leading(n.Range().Lo), f.varDecs(n), f.rest(n.Range().Hi))
+	}
+	return f.node(node)
+}
+
+func (f *formatter) forStmt(n *parser.ForStmt) Doc {
+	if n.Init() == nil && n.Test() == nil && n.Update() == nil {
+		return Concat(Text("for (;;)"), f.clause(n.Body()))
+	}
+	docs := docConcat{Text("for (")}
+	if n.Init() != nil {
+		docs = append(docs, f.forInit(n.Init()))
+	}
+	docs = append(docs, Text(";"))
+	if n.Test() != nil {
+		docs = append(docs, Text(" "), f.node(n.Test()))
+	}
+	docs = append(docs, Text(";"))
+	if n.Update() != nil {
+		docs = append(docs, Text(" "), f.node(n.Update()))
+	}
+	docs = append(docs, Text(")"), f.clause(n.Body()))
+	return docs
+}
+
+func (f *formatter) switchStmt(n *parser.SwitchStmt) Doc {
+	head := Concat(Text("switch ("), f.node(n.Test()), Text(") "))
+	cases := n.Cases()
+	if len(cases) == 0 {
+		return Concat(head, f.block(nil, n.Range().Hi))
+	}
+	return Concat(head, Text("{"), Indent(HardLine, f.stmts(cases, n.Range().Hi-1)), HardLine, Text("}"))
+}
+
+func (f *formatter) switchCase(n *parser.SwitchCase) Doc {
+	head := Text("default:")
+	if n.Test() != nil {
+		head = Concat(Text("case "), f.node(n.Test()), Text(":"))
+	}
+	cons := n.Cons()
+	if len(cons) == 1 && cons[0].Type() == parser.N_STMT_BLOCK {
+		return Concat(head, Text(" "), f.node(cons[0]))
+	}
+	body := f.stmts(cons, n.Range().Hi)
+	if len(body.(docConcat)) == 0 {
+		return head
+	}
+	return Concat(head, Indent(HardLine, body))
+}
+
+// `break` and `continue`, the label is kept in the same line with the keyword
+func (f *formatter) jump(kw string, label parser.Node) Doc {
+	if label == nil {
+		return Text(kw + ";")
+	}
+	lead := f.rest(label.Range().Lo)
+	return Concat(Text(kw), lead, Text(" "+f.text(label.Range())+";"))
+}
+
+// `return` and `throw` whose argument should start in the same line with the keyword, the
+// argument is wrapped in parentheses if there is a comment forcing a line break before it
+func (f *formatter) restricted(kw string, arg parser.Node) Doc {
+	if arg == nil {
+		return Text(kw + ";")
+	}
+	// the redundant parentheses are removed since the argument is wrapped in the new ones
+	inner := arg
+	for {
+		if p, ok := inner.(*parser.ParenExpr); ok {
+			inner = p.Expr()
+			continue
+		}
+		break
+	}
+	if f.breakingCmtBefore(inner.Range().Lo) {
+		doc := Concat(f.leading(inner.Range().Lo), f.print(inner), f.rest(outerRange(arg).Hi))
+		return Concat(Text(kw+" ("), Indent(HardLine, doc), HardLine, Text(");"))
+	}
+	return Concat(Text(kw+" "), f.wrapped(arg), Text(";"))
+}
+
+// the parentheses around the binary expressions and the jsx elements are redundant as the
+// right-hand sides of the assignments and the arguments of `return` and `throw`, the inner
+// node is returned with the range including the parentheses, nil is returned for the others
+func (f *formatter) unparen(node parser.Node) (parser.Node, span.Range) {
+	rng := outerRange(node)
+	if p, ok := node.(*parser.ParenExpr); ok {
+		if f.noIn && p.Expr().Type() == parser.N_EXPR_BIN {
+			return nil, rng
+		}
+		node = p.Expr()
+	}
+	switch n := node.(type) {
+	case *parser.JsxElem:
+		return n, rng
+	case *parser.BinExpr:
+		if !isTsBin(n) {
+			return n, rng
+		}
+	}
+	return nil, rng
+}
+
+// the doc of the node returned by `unparen` with its comments
+func (f *formatter) unparened(inner parser.Node, rng span.Range, wrap func(Doc) Doc) Doc {
+	lead := Concat(f.leading(rng.Lo), f.leading(inner.Range().Lo))
+	var doc Doc
+	if n, ok := inner.(*parser.BinExpr); ok {
+		doc = f.binary(n, false)
+	} else {
+		doc = f.print(inner)
+	}
+	return Concat(wrap(Concat(lead, doc, f.rest(inner.Range().Hi))), f.rest(rng.Hi))
+}
+
+// the node is wrapped in the parentheses if it does not fit in the line, it's used for the
+// multi-line expressions after the keywords and the operators
+func (f *formatter) wrapped(node parser.Node) Doc {
+	inner, rng := f.unparen(node)
+	if inner == nil {
+		return f.node(node)
+	}
+	return f.unparened(inner, rng, func(doc Doc) Doc {
+		return Group(IfBreak(Text("("), nil), Indent(SoftLine, doc), SoftLine, IfBreak(Text(")"), nil))
+	})
+}
+
+func (f *formatter) tryStmt(n *parser.TryStmt) Doc {
+	docs := docConcat{Text("try "), f.node(n.Try())}
+	if n.Catch() != nil {
+		docs = append(docs, Text(" "), f.node(n.Catch()))
+	}
+	if n.Fin() != nil {
+		docs = append(docs, Text(" finally "), f.node(n.Fin()))
+	}
+	return docs
+}
+
+func (f *formatter) varDecs(n *parser.VarDecStmt) Doc {
+	decs := n.DecList()
+	docs := f.commaList(decs, n.Range().Hi)
+	kw := Text(n.Kind() + " ")
+	if len(docs) == 1 {
+		return Concat(kw, docs[0])
+	}
+
+	hasInit := false
+	for _, dec := range decs {
+		if dec.(*parser.VarDec).Init() != nil {
+			hasInit = true
+		}
+	}
+	if hasInit {
+		return Concat(kw, Indent(Join(Concat(Text(","), HardLine), docs)))
+	}
+	return Group(kw, Indent(Join(Concat(Text(","), Line), docs)))
+}
+
+func (f *formatter) varDec(n *parser.VarDec) Doc {
+	id := f.node(n.Id())
+	if n.Init() == nil {
+		return id
+	}
+	return Concat(id, Text(" ="), f.assignRhs(n.Init()))
+}
+
+// the right-hand side of the assignment starts with a space or a line break
+func (f *formatter) assignRhs(rhs parser.Node) Doc {
+	if inner, rng := f.unparen(rhs); inner != nil && inner.Type() == parser.N_EXPR_BIN {
+		return f.unparened(inner, rng, func(doc Doc) Doc {
+			return Group(Indent(Line, doc))
+		})
+	}
+	return Concat(Text(" "), f.wrapped(rhs))
+}
+
+// the `[lo, hi)` of the source which consists of the keywords, the whitespaces between them
+// are collapsed if there is no comment or literal in it, the comments in it are skipped since
+// they are kept in the text
+func (f *formatter) head(lo, hi uint32) string {
+	if lo >= hi {
+		return ""
+	}
+	for f.ci < len(f.cmts) && f.cmts[f.ci].Lo < hi {
+		f.ci++
+	}
+	s := strings.TrimSpace(f.code[lo:hi])
+	if strings.ContainsAny(s, "'\"`/") {
+		return s
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// the decorators are placed in their own lines if they are in the source
+func (f *formatter) decorators(node parser.Node) (Doc, uint32) {
+	decs := parser.DecoratorsOf(node)
+	lo := node.Range().Lo
+	if len(decs) == 0 {
+		return Text(""), lo
+	}
+	docs := docConcat{}
+	for _, dec := range decs {
+		docs = append(docs, f.raw(dec.Range()))
+		hi := dec.Range().Hi
+		lo = f.skipSpaces(hi)
+		if f.hasNewline(hi, lo) {
+			docs = append(docs, HardLine)
+		} else {
+			docs = append(docs, Text(" "))
+		}
+	}
+	return docs, lo
+}
+
+func (f *formatter) class(n *parser.ClassDec) Doc {
+	decs, lo := f.decorators(n)
+	body := n.Body()
+	head := f.head(f.skipCmts(lo), body.Range().Lo)
+	return Concat(decs, f.leading(lo), Text(head+" "), f.node(body))
+}
+
+// skips the comments which are printed as the leading ones
+func (f *formatter) skipCmts(lo uint32) uint32 {
+	for i := f.ci; i < len(f.cmts) && f.cmts[i].Lo == lo; i++ {
+		lo = f.skipSpaces(f.cmts[i].Hi)
+	}
+	return lo
+}
+
+// the modifiers of the class member and its key
+func (f *formatter) memberKey(node parser.Node, key parser.Node, computed bool) Doc {
+	decs, lo := f.decorators(node)
+	lo = f.skipCmts(lo)
+	lead := f.leading(lo)
+	hi := key.Range().Lo
+	if computed {
+		hi = uint32(strings.LastIndexByte(f.code[:hi], '['))
+	}
+	head := f.head(lo, hi)
+	if head != "" && !strings.HasSuffix(head, "*") {
+		head += " "
+	}
+	return Concat(decs, lead, Text(head), f.key(key, computed))
+}
+
+func (f *formatter) key(key parser.Node, computed bool) Doc {
+	if computed {
+		return Concat(Text("["), f.node(key), Text("]"))
+	}
+	return f.node(key)
+}
+
+// the optional and the definite marks of the member which are not printed with its key
+func (f *formatter) memberMarks(key parser.Node, ti *parser.TypInfo) Doc {
+	if ti == nil {
+		return Text("")
+	}
+	var kti *parser.TypInfo
+	if id, ok := key.(*parser.Ident); ok {
+		kti = id.TypInfo()
+	}
+	marks := ""
+	if ti.Optional() && (kti == nil || !kti.Optional()) {
+		marks += "?"
+	}
+	if ti.Definite() && (kti == nil || !kti.Definite()) {
+		marks += "!"
+	}
+	return Text(marks)
+}
+
+func (f *formatter) method(n *parser.Method) Doc {
+	fn := n.Val().(*parser.FnDec)
+	return Concat(f.memberKey(n, n.Key(), n.Computed()), f.memberMarks(n.Key(), fn.TypInfo()), f.fnTail(fn))
+}
+
+func (f *formatter) field(n *parser.Field) Doc {
+	if n.IsTsSig() {
+		return f.raw(n.Range())
+	}
+	docs := docConcat{f.memberKey(n, n.Key(), n.Computed()), f.memberMarks(n.Key(), n.TypInfo()), f.typAnnot(n.TypInfo())}
+	if n.Val() != nil {
+		docs = append(docs, Text(" ="), f.assignRhs(n.Val()))
+	}
+	return append(docs, Text(";"))
+}
+
+func (f *formatter) importDec(n *parser.ImportDec) Doc {
+	kw := "import "
+	if n.TsTyp() {
+		kw = "import type "
+	}
+	specs := n.Specs()
+	src := n.Src()
+	if len(specs) == 0 {
+		if strings.Contains(f.code[n.Range().Lo:src.Range().Lo], "{") {
+			return Concat(Text(kw+"{} from "), f.node(src), f.importAttrs(n.AttrKw(), n.Attrs(), n.Range().Hi), Text(";"))
+		}
+		return Concat(Text(kw), f.node(src), f.importAttrs(n.AttrKw(), n.Attrs(), n.Range().Hi), Text(";"))
+	}
+
+	docs := docConcat{Text(kw)}
+	named := make([]parser.Node, 0, len(specs))
+	for _, spec := range specs {
+		s := spec.(*parser.ImportSpec)
+		if s.Default() || s.NameSpace() {
+			if len(docs) > 1 {
+				docs = append(docs, Text(", "))
+			}
+			docs = append(docs, f.node(s))
+			continue
+		}
+		named = append(named, s)
+	}
+	if len(named) > 0 {
+		if len(docs) > 1 {
+			docs = append(docs, Text(", "))
+		}
+		docs = append(docs, f.group("{", "}", named, src.Range().Lo, Line, true))
+	}
+	return append(docs, Text(" from "), f.node(src), f.importAttrs(n.AttrKw(), n.Attrs(), n.Range().Hi), Text(";"))
+}
+
+// the import attributes after the module specifier like ` with { type: "json" }`, the keyword
+// is kept as it is since the legacy `assert` is still used by some codebases
+func (f *formatter) importAttrs(kw span.Range, attrs []parser.Node, hi uint32) Doc {
+	if kw.Empty() {
+		return Text("")
+	}
+	return Concat(Text(" "+f.code[kw.Lo:kw.Hi]+" "), f.group("{", "}", attrs, hi, Line, true))
+}
+
+func (f *formatter) importSpec(n *parser.ImportSpec) Doc {
+	prefix := ""
+	if name := n.Id(); name != nil && n.Range().Lo < name.Range().Lo && !n.Default() && !n.NameSpace() {
+		prefix = "type "
+	}
+	if n.NameSpace() {
+		return Concat(Text("* as "), f.node(n.Local()))
+	}
+	if n.Default() || n.Id() == nil || n.Id().Range() == n.Local().Range() {
+		return Concat(Text(prefix), f.node(n.Local()))
+	}
+	return Concat(Text(prefix), f.node(n.Id()), Text(" as "), f.node(n.Local()))
+}
+
+func (f *formatter) exportDec(n *parser.ExportDec) Doc {
+	if dec := n.Dec(); dec != nil {
+		if dec.Range().Lo == n.Range().Lo {
+			return f.node(dec)
+		}
+		kw := "export "
+		if n.Default() {
+			kw = "export default "
+		}
+		switch dec.Type() {
+		case parser.N_STMT_FN, parser.N_STMT_CLASS, parser.N_EXPR_FN, parser.N_EXPR_CLASS:
+			if outerRange(dec) == dec.Range() {
+				return Concat(Text(kw), f.node(dec))
+			}
+		}
+		if dec.Type() > parser.N_STMT_BEGIN && dec.Type() < parser.N_STMT_END || dec.Type() > parser.N_TS_BEGIN && dec.Type() < parser.N_TS_END {
+			return Concat(Text(kw), f.node(dec))
+		}
+		return Concat(Text(kw), f.wrapped(dec), Text(";"))
+	}
+
+	kw := "export "
+	if n.TsTyp() {
+		kw = "export type "
+	}
+	src := n.Src()
+	if n.All() {
+		docs := docConcat{Text(kw + "*")}
+		for _, spec := range n.Specs() {
+			docs = append(docs, Text(" as "), f.node(spec.(*parser.ExportSpec).Local()))
+		}
+		return append(docs, Text(" from "), f.node(src), f.importAttrs(n.AttrKw(), n.Attrs(), n.Range().Hi), Text(";"))
+	}
+
+	hi := n.Range().Hi
+	if src != nil {
+		hi = src.Range().Lo
+	}
+	docs := docConcat{Text(kw), f.group("{", "}", n.Specs(), hi, Line, true)}
+	if src != nil {
+		docs = append(docs, Text(" from "), f.node(src), f.importAttrs(n.AttrKw(), n.Attrs(), n.Range().Hi))
+	}
+	return append(docs, Text(";"))
+}
@@ -0,0 +1,220 @@
+// the parser of the jsdoc comments, the comments are parsed into the description and the tags,
+// the type expressions in the tags are parsed by the typescript type parser, for example:
+//
+//	/**
+//	 * Adds two numbers.
+//	 * @param {number} a - the first number
+//	 * @param {number} [b=1] - the second number
+//	 * @returns {number} the sum
+//	 */
+//	function add(a, b) {}
+//
+// `Of` returns the jsdoc of the declaration and `Parse` parses a comment captured by the lexer
+package jsdoc
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type JSDoc struct {
+	Rng  span.Range // the range of the comment including `/**` and `*/`
+	Desc string     // the description before the tags
+	Tags []Tag
+}
+
+// the tags of the specified kind in the order of their positions
+func (d *JSDoc) TagsOf(kind TagKind) []Tag {
+	ret := make([]Tag, 0)
+	for _, tag := range d.Tags {
+		if tag.Kind() == kind {
+			ret = append(ret, tag)
+		}
+	}
+	return ret
+}
+
+func (d *JSDoc) Params() []*ParamTag {
+	ret := make([]*ParamTag, 0)
+	for _, tag := range d.TagsOf(TAG_PARAM) {
+		ret = append(ret, tag.(*ParamTag))
+	}
+	return ret
+}
+
+func (d *JSDoc) Param(name string) *ParamTag {
+	for _, tag := range d.Params() {
+		if tag.Name == name {
+			return tag
+		}
+	}
+	return nil
+}
+
+func (d *JSDoc) Returns() *TypTag {
+	if tags := d.TagsOf(TAG_RETURNS); len(tags) > 0 {
+		return tags[0].(*TypTag)
+	}
+	return nil
+}
+
+func (d *JSDoc) Type() *TypTag {
+	if tags := d.TagsOf(TAG_TYPE); len(tags) > 0 {
+		return tags[0].(*TypTag)
+	}
+	return nil
+}
+
+// the reason of the deprecation is returned if the `@deprecated` tag is present
+func (d *JSDoc) Deprecated() (string, bool) {
+	if tags := d.TagsOf(TAG_DEPRECATED); len(tags) > 0 {
+		return tags[0].(*TextTag).Text, true
+	}
+	return "", false
+}
+
+// the modifiers can appear between the jsdoc comment and the declaration
+var modifiers = map[string]bool{
+	"export": true, "default": true, "declare": true, "const": true, "let": true, "var": true,
+	"async": true, "static": true, "public": true, "private": true, "protected": true,
+	"readonly": true, "abstract": true, "override": true, "get": true, "set": true,
+	"accessor": true, "*": true,
+}
+
+// the jsdoc of the function, class, variable declaration or class member, it's the nearest
+// comment before the declaration if the comment is in the form of `/** ... */` and there are
+// only the modifiers such as `export` between them, nil is returned if there is no such comment
+func Of(p *parser.Parser, node parser.Node) *JSDoc {
+	lo := node.Range().Lo
+	for _, d := range parser.DecoratorsOf(node) {
+		if d.Range().Lo < lo {
+			lo = d.Range().Lo
+		}
+	}
+
+	cmts := p.Comments()
+	i := sort.Search(len(cmts), func(i int) bool {
+		return cmts[i].Hi > lo
+	}) - 1
+	if i < 0 {
+		return nil
+	}
+
+	src := p.Source()
+	code := src.Text(0, uint32(src.Len()))
+	for _, word := range strings.Fields(code[cmts[i].Hi:lo]) {
+		if !modifiers[word] {
+			return nil
+		}
+	}
+	return Parse(code, cmts[i])
+}
+
+// parses the comment at `rng` of the code, nil is returned if it's not a jsdoc comment
+func Parse(code string, rng span.Range) *JSDoc {
+	text := code[rng.Lo:rng.Hi]
+	if !strings.HasPrefix(text, "/**") || !strings.HasSuffix(text, "*/") || len(text) < 5 || text[3] == '/' {
+		return nil
+	}
+
+	doc := &JSDoc{Rng: rng, Tags: make([]Tag, 0)}
+	var desc *block
+	var cur *block
+	for _, ln := range splitLines(code, rng) {
+		if strings.HasPrefix(ln.s, "@") {
+			if cur != nil {
+				doc.Tags = append(doc.Tags, parseTag(cur))
+			}
+			cur = &block{}
+		} else if cur == nil && desc == nil {
+			desc = &block{}
+		}
+		if cur != nil {
+			cur.add(ln)
+		} else {
+			desc.add(ln)
+		}
+	}
+	if cur != nil {
+		doc.Tags = append(doc.Tags, parseTag(cur))
+	}
+	if desc != nil {
+		doc.Desc = strings.TrimSpace(desc.s)
+	}
+	return doc
+}
+
+// parses the text of a jsdoc comment including `/**` and `*/`, the ranges in the result are
+// relative to the text
+func ParseText(text string) *JSDoc {
+	return Parse(text, span.Range{Lo: 0, Hi: uint32(len(text))})
+}
+
+type line struct {
+	s  string
+	lo uint32 // the offset of `s` in the source
+}
+
+// the lines of the comment without the leading `*` and the whitespaces around them
+func splitLines(code string, rng span.Range) []line {
+	ret := make([]line, 0)
+	lo := rng.Lo + 3
+	body := code[lo : rng.Hi-2]
+	for i, s := range strings.Split(body, "\n") {
+		ofst := lo
+		lo += uint32(len(s)) + 1
+
+		trimmed := strings.TrimLeft(s, " \t")
+		if i > 0 && strings.HasPrefix(trimmed, "*") {
+			trimmed = trimmed[1:]
+			if strings.HasPrefix(trimmed, " ") {
+				trimmed = trimmed[1:]
+			}
+		} else {
+			trimmed = strings.TrimLeft(trimmed, " \t")
+		}
+		ofst += uint32(len(s) - len(trimmed))
+		ret = append(ret, line{strings.TrimRight(trimmed, " \t\r"), ofst})
+	}
+
+	// the empty lines at the beginning and the end are insignificant
+	for len(ret) > 0 && ret[0].s == "" {
+		ret = ret[1:]
+	}
+	for len(ret) > 0 && ret[len(ret)-1].s == "" {
+		ret = ret[:len(ret)-1]
+	}
+	return ret
+}
+
+// the text of the lines joined by line breaks, the offset in the source of every byte of the
+// text is kept to locate the parts of the text
+type block struct {
+	s    string
+	ofst []uint32
+}
+
+func (b *block) add(ln line) {
+	if len(b.ofst) > 0 {
+		b.ofst = append(b.ofst, b.ofst[len(b.ofst)-1]+1)
+		b.s += "\n"
+	}
+	for i := 0; i < len(ln.s); i++ {
+		b.ofst = append(b.ofst, ln.lo+uint32(i))
+	}
+	b.s += ln.s
+}
+
+// the offset in the source of the position `i` of the text
+func (b *block) pos(i int) uint32 {
+	if i < len(b.ofst) {
+		return b.ofst[i]
+	}
+	if len(b.ofst) == 0 {
+		return 0
+	}
+	return b.ofst[len(b.ofst)-1] + 1
+}
@@ -0,0 +1,190 @@
+package jsdoc
+
+import (
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func compile(t *testing.T, code string, ts bool) (parser.Node, *parser.Parser) {
+	opts := parser.NewParserOpts()
+	if ts {
+		opts.Feature = opts.Feature.On(parser.FEAT_TS)
+	}
+	p := parser.NewParser(span.NewSource("", code), opts)
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	return ast, p
+}
+
+func stmt(ast parser.Node, i int) parser.Node {
+	return ast.(*parser.Prog).Body()[i]
+}
+
+func TestDesc(t *testing.T) {
+	doc := ParseText(`/**
+ * The first line.
+ *
+ * The second line.
+ */`)
+	AssertEqual(t, "The first line.\n\nThe second line.", doc.Desc, "should be ok")
+	AssertEqual(t, 0, len(doc.Tags), "should be ok")
+
+	doc = ParseText("/** single line */")
+	AssertEqual(t, "single line", doc.Desc, "should be ok")
+
+	AssertEqual(t, true, ParseText("/* not jsdoc */") == nil, "should be nil")
+	AssertEqual(t, true, ParseText("/**/") == nil, "should be nil")
+}
+
+func TestParam(t *testing.T) {
+	doc := ParseText(`/**
+ * Adds two numbers.
+ * @param {number} a - the first number
+ * @param {number} [b=1] the second
+ *   number
+ * @arg {string=} c
+ * @returns {number} the sum
+ */`)
+	AssertEqual(t, "Adds two numbers.", doc.Desc, "should be ok")
+
+	params := doc.Params()
+	AssertEqual(t, 3, len(params), "should be ok")
+
+	a := params[0]
+	AssertEqual(t, "a", a.Name, "should be ok")
+	AssertEqual(t, "number", a.Typ.Raw, "should be ok")
+	AssertEqual(t, parser.N_TS_NUM, a.Typ.Node.Type(), "should be ok")
+	AssertEqual(t, "the first number", a.Desc, "should be ok")
+	AssertEqual(t, false, a.Optional, "should be ok")
+
+	b := doc.Param("b")
+	AssertEqual(t, true, b.Optional, "should be ok")
+	AssertEqual(t, "1", b.Default, "should be ok")
+	AssertEqual(t, "the second\n  number", b.Desc, "should be ok")
+
+	c := params[2]
+	AssertEqual(t, "arg", c.TagName(), "should be ok")
+	AssertEqual(t, true, c.Optional, "should be ok")
+	AssertEqual(t, parser.N_TS_STR, c.Typ.Node.Type(), "should be ok")
+
+	ret := doc.Returns()
+	AssertEqual(t, "number", ret.Typ.Raw, "should be ok")
+	AssertEqual(t, "the sum", ret.Desc, "should be ok")
+}
+
+func TestTypeRange(t *testing.T) {
+	code := "/** @type {Array<string>} */ let a"
+	ast, p := compile(t, code, false)
+	doc := Of(p, stmt(ast, 0))
+	typ := doc.Type().Typ
+	AssertEqual(t, "Array<string>", code[typ.Rng.Lo:typ.Rng.Hi], "should be ok")
+	AssertEqual(t, parser.N_TS_REF, typ.Node.Type(), "should be ok")
+	AssertEqual(t, "@type {Array<string>}", code[doc.Tags[0].Range().Lo:doc.Tags[0].Range().Hi], "should be ok")
+}
+
+func TestClosureType(t *testing.T) {
+	doc := ParseText(`/**
+ * @param {?number} a
+ * @param {!Object} b
+ * @param {...string} c
+ * @param {*} d
+ * @param {Array.<string>} e
+ * @param {function(string): number} f
+ */`)
+	params := doc.Params()
+	AssertEqual(t, true, params[0].Typ.Nullable, "should be ok")
+	AssertEqual(t, parser.N_TS_NUM, params[0].Typ.Node.Type(), "should be ok")
+	AssertEqual(t, true, params[1].Typ.NonNull, "should be ok")
+	AssertEqual(t, true, params[2].Typ.Rest, "should be ok")
+	AssertEqual(t, parser.N_TS_STR, params[2].Typ.Node.Type(), "should be ok")
+	AssertEqual(t, true, params[3].Typ.Any, "should be ok")
+	AssertEqual(t, true, params[3].Typ.Node == nil, "should be ok")
+	AssertEqual(t, parser.N_TS_REF, params[4].Typ.Node.Type(), "should be ok")
+	AssertEqual(t, true, params[5].Typ.Err != nil, "should be failed")
+}
+
+func TestTypedefTemplate(t *testing.T) {
+	doc := ParseText(`/**
+ * @template {string} K, V the keys
+ * @typedef {{ a: K, b: V }} Pair a pair
+ * @property {K} a
+ * @callback Fn
+ * @deprecated use others
+ * @see https://jsdoc.app
+ * @foo bar
+ */`)
+	tpl := doc.Tags[0].(*TemplateTag)
+	AssertEqual(t, TAG_TEMPLATE, tpl.Kind(), "should be ok")
+	AssertEqual(t, "K,V", tpl.Names[0]+","+tpl.Names[1], "should be ok")
+	AssertEqual(t, parser.N_TS_STR, tpl.Constraint.Node.Type(), "should be ok")
+	AssertEqual(t, "the keys", tpl.Desc, "should be ok")
+
+	def := doc.Tags[1].(*TypedefTag)
+	AssertEqual(t, "Pair", def.Name, "should be ok")
+	AssertEqual(t, parser.N_TS_LIT_OBJ, def.Typ.Node.Type(), "should be ok")
+	AssertEqual(t, "a pair", def.Desc, "should be ok")
+
+	AssertEqual(t, TAG_PROPERTY, doc.Tags[2].Kind(), "should be ok")
+	AssertEqual(t, "Fn", doc.Tags[3].(*TypedefTag).Name, "should be ok")
+
+	reason, ok := doc.Deprecated()
+	AssertEqual(t, true, ok, "should be ok")
+	AssertEqual(t, "use others", reason, "should be ok")
+
+	AssertEqual(t, "https://jsdoc.app", doc.Tags[5].(*TextTag).Text, "should be ok")
+	AssertEqual(t, TAG_UNKNOWN, doc.Tags[6].Kind(), "should be ok")
+	AssertEqual(t, "foo", doc.Tags[6].TagName(), "should be ok")
+}
+
+func TestExample(t *testing.T) {
+	doc := ParseText(`/**
+ * @example
+ * if (a) {
+ *   b()
+ * }
+ */`)
+	AssertEqual(t, "if (a) {\n  b()\n}", doc.Tags[0].(*TextTag).Text, "should be ok")
+}
+
+func TestOf(t *testing.T) {
+	ast, p := compile(t, `/** f */
+export async function f() {}
+
+/** c */
+export default class C {
+  /** m */
+  static async m() {}
+
+  /** p */
+  private p = 1
+}
+
+/** v */
+const v = 1
+
+// not jsdoc
+let x
+
+/** detached */
+;
+let y
+`, true)
+
+	AssertEqual(t, "f", Of(p, stmt(ast, 0)).Desc, "should be ok")
+
+	cls := stmt(ast, 1)
+	AssertEqual(t, "c", Of(p, cls).Desc, "should be ok")
+	body := cls.(*parser.ExportDec).Dec().(*parser.ClassDec).Body().(*parser.ClassBody).Elems()
+	AssertEqual(t, "m", Of(p, body[0]).Desc, "should be ok")
+	AssertEqual(t, "p", Of(p, body[1]).Desc, "should be ok")
+
+	vs := stmt(ast, 2)
+	AssertEqual(t, "v", Of(p, vs).Desc, "should be ok")
+	AssertEqual(t, "v", Of(p, vs.(*parser.VarDecStmt).DecList()[0]).Desc, "should be ok")
+
+	AssertEqual(t, true, Of(p, stmt(ast, 3)) == nil, "should be nil")
+	AssertEqual(t, true, Of(p, stmt(ast, 4)) == nil, "should be nil")
+}
@@ -0,0 +1,348 @@
+package jsdoc
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type TagKind uint8
+
+const (
+	TAG_UNKNOWN    TagKind = iota
+	TAG_PARAM              // @param, @arg, @argument
+	TAG_PROPERTY           // @property, @prop
+	TAG_RETURNS            // @returns, @return
+	TAG_TYPE               // @type
+	TAG_TYPEDEF            // @typedef
+	TAG_CALLBACK           // @callback
+	TAG_TEMPLATE           // @template
+	TAG_DEPRECATED         // @deprecated
+	TAG_THROWS             // @throws, @exception
+	TAG_EXAMPLE            // @example
+	TAG_THIS               // @this
+	TAG_ENUM               // @enum
+	TAG_EXTENDS            // @extends, @augments
+	TAG_IMPLEMENTS         // @implements
+	TAG_SATISFIES          // @satisfies
+	TAG_SEE                // @see
+	TAG_SINCE              // @since
+)
+
+var tagKinds = map[string]TagKind{
+	"param":      TAG_PARAM,
+	"arg":        TAG_PARAM,
+	"argument":   TAG_PARAM,
+	"property":   TAG_PROPERTY,
+	"prop":       TAG_PROPERTY,
+	"returns":    TAG_RETURNS,
+	"return":     TAG_RETURNS,
+	"type":       TAG_TYPE,
+	"typedef":    TAG_TYPEDEF,
+	"callback":   TAG_CALLBACK,
+	"template":   TAG_TEMPLATE,
+	"deprecated": TAG_DEPRECATED,
+	"throws":     TAG_THROWS,
+	"exception":  TAG_THROWS,
+	"example":    TAG_EXAMPLE,
+	"this":       TAG_THIS,
+	"enum":       TAG_ENUM,
+	"extends":    TAG_EXTENDS,
+	"augments":   TAG_EXTENDS,
+	"implements": TAG_IMPLEMENTS,
+	"satisfies":  TAG_SATISFIES,
+	"see":        TAG_SEE,
+	"since":      TAG_SINCE,
+}
+
+type Tag interface {
+	Kind() TagKind
+	TagName() string // the name of the tag without `@`, such as `arg` for the `TAG_PARAM`
+	Range() span.Range
+}
+
+type tagBase struct {
+	kind TagKind
+	name string
+	rng  span.Range
+}
+
+func (t *tagBase) Kind() TagKind {
+	return t.kind
+}
+
+func (t *tagBase) TagName() string {
+	return t.name
+}
+
+func (t *tagBase) Range() span.Range {
+	return t.rng
+}
+
+// `@param {Type} name desc`, `@param {Type} [name=default] desc` and the same forms of
+// `@property`
+type ParamTag struct {
+	tagBase
+	Typ      *Type // nil if the type is absent
+	Name     string
+	Optional bool   // `[name]` or the type is in the form of `{Type=}`
+	Default  string // the default value in `[name=default]`
+	Desc     string
+}
+
+// the tags in the form of `@tag {Type} desc`, such as `@returns`, `@type` and `@throws`
+type TypTag struct {
+	tagBase
+	Typ  *Type
+	Desc string
+}
+
+// `@typedef {Type} Name desc` and `@callback Name desc`, `Typ` is nil for `@callback`
+type TypedefTag struct {
+	tagBase
+	Typ  *Type
+	Name string
+	Desc string
+}
+
+// `@template {Constraint} T, U desc`
+type TemplateTag struct {
+	tagBase
+	Constraint *Type
+	Names      []string
+	Desc       string
+}
+
+// the tags carry only the text such as `@deprecated`, `@example` and the unknown tags, the
+// line breaks and the indentations are kept in the text of `@example`
+type TextTag struct {
+	tagBase
+	Text string
+}
+
+// the type expression in the braces of the tag, it's parsed by the typescript type parser after
+// the closure-style modifiers are stripped, such as `?number`, `!Object`, `...string` and
+// `string=`, the `Node` is nil if the type is `*` or it cannot be parsed, in the latter case
+// the error is kept in `Err`, the ranges of the nodes in `Node` are relative to `Rng.Lo`
+type Type struct {
+	Raw      string
+	Rng      span.Range
+	Node     parser.Node
+	Err      error
+	Nullable bool // `?Type`
+	NonNull  bool // `!Type`
+	Optional bool // `Type=`
+	Rest     bool // `...Type`
+	Any      bool // `*` or `?`
+}
+
+func parseType(raw string, lo uint32) *Type {
+	typ := &Type{Raw: raw, Rng: span.Range{Lo: lo, Hi: lo + uint32(len(raw))}}
+
+	// the modifiers are replaced by the spaces with the same length, so the ranges of the
+	// nodes are kept in the raw text
+	b := []byte(raw)
+	s := strings.TrimSpace(raw)
+	if s == "*" || s == "?" {
+		typ.Any = true
+		return typ
+	}
+	i := strings.Index(raw, s)
+	j := i + len(s)
+	blank := func(lo, hi int) {
+		for k := lo; k < hi; k++ {
+			b[k] = ' '
+		}
+	}
+	if strings.HasPrefix(s, "...") {
+		typ.Rest = true
+		blank(i, i+3)
+		i += 3
+	}
+	if i < j && b[i] == '?' {
+		typ.Nullable = true
+		blank(i, i+1)
+	} else if i < j && b[i] == '!' {
+		typ.NonNull = true
+		blank(i, i+1)
+	}
+	if j > i && b[j-1] == '=' {
+		typ.Optional = true
+		blank(j-1, j)
+	}
+	code := strings.ReplaceAll(string(b), ".<", " <")
+
+	opts := parser.NewParserOpts()
+	opts.Feature = opts.Feature.On(parser.FEAT_TS)
+	p := parser.NewParser(span.NewSource("", code), opts)
+	typ.Node, typ.Err = p.TsTyp()
+	return typ
+}
+
+// the cursor over the text of a tag block
+type scanner struct {
+	b *block
+	i int
+}
+
+func (s *scanner) eof() bool {
+	return s.i >= len(s.b.s)
+}
+
+func (s *scanner) skipSpace() {
+	for !s.eof() && isSpace(s.b.s[s.i]) {
+		s.i++
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// the text until the next whitespace
+func (s *scanner) word() string {
+	s.skipSpace()
+	i := s.i
+	for !s.eof() && !isSpace(s.b.s[s.i]) {
+		s.i++
+	}
+	return s.b.s[i:s.i]
+}
+
+// the text in the balanced pair of the `open` and `close`, the cursor is expected to be at the
+// `open`, the text till the end is returned if the pair is not closed
+func (s *scanner) enclosed(open, close byte) (string, int) {
+	s.i++
+	lo := s.i
+	depth := 1
+	for ; !s.eof(); s.i++ {
+		c := s.b.s[s.i]
+		if c == open {
+			depth++
+		} else if c == close {
+			depth--
+			if depth == 0 {
+				text := s.b.s[lo:s.i]
+				s.i++
+				return text, lo
+			}
+		}
+	}
+	return s.b.s[lo:], lo
+}
+
+// the optional type in the braces
+func (s *scanner) typ() *Type {
+	s.skipSpace()
+	if s.eof() || s.b.s[s.i] != '{' {
+		return nil
+	}
+	raw, lo := s.enclosed('{', '}')
+	return parseType(raw, s.b.pos(lo))
+}
+
+func (s *scanner) rest() string {
+	text := strings.TrimSpace(s.b.s[s.i:])
+	s.i = len(s.b.s)
+	return text
+}
+
+// the description after the name of the param, the hyphen before it is omitted
+func (s *scanner) desc() string {
+	text := s.rest()
+	if strings.HasPrefix(text, "- ") || text == "-" {
+		text = strings.TrimSpace(text[1:])
+	}
+	return text
+}
+
+func parseTag(b *block) Tag {
+	s := &scanner{b, 1}
+	for !s.eof() && !isSpace(b.s[s.i]) && b.s[s.i] != '{' {
+		s.i++
+	}
+	name := b.s[1:s.i]
+	kind := tagKinds[name]
+	base := tagBase{kind, name, span.Range{Lo: b.pos(0), Hi: b.pos(len(b.s))}}
+
+	switch kind {
+	case TAG_PARAM, TAG_PROPERTY:
+		tag := &ParamTag{tagBase: base, Typ: s.typ()}
+		if tag.Typ != nil && tag.Typ.Optional {
+			tag.Optional = true
+		}
+		s.skipSpace()
+		if !s.eof() && b.s[s.i] == '[' {
+			text, _ := s.enclosed('[', ']')
+			tag.Optional = true
+			if i := strings.IndexByte(text, '='); i >= 0 {
+				tag.Name = strings.TrimSpace(text[:i])
+				tag.Default = strings.TrimSpace(text[i+1:])
+			} else {
+				tag.Name = strings.TrimSpace(text)
+			}
+		} else {
+			tag.Name = s.word()
+		}
+		tag.Desc = s.desc()
+		return tag
+	case TAG_RETURNS, TAG_TYPE, TAG_THROWS, TAG_THIS, TAG_ENUM, TAG_SATISFIES:
+		tag := &TypTag{tagBase: base, Typ: s.typ()}
+		tag.Desc = s.desc()
+		return tag
+	case TAG_EXTENDS, TAG_IMPLEMENTS:
+		// the braces are optional for these tags, e.g. `@augments Base<T>`
+		tag := &TypTag{tagBase: base, Typ: s.typ()}
+		if tag.Typ == nil {
+			s.skipSpace()
+			lo := s.i
+			if raw := s.word(); raw != "" {
+				tag.Typ = parseType(raw, b.pos(lo))
+			}
+		}
+		tag.Desc = s.desc()
+		return tag
+	case TAG_TYPEDEF, TAG_CALLBACK:
+		tag := &TypedefTag{tagBase: base}
+		if kind == TAG_TYPEDEF {
+			tag.Typ = s.typ()
+		}
+		tag.Name = s.word()
+		tag.Desc = s.desc()
+		return tag
+	case TAG_TEMPLATE:
+		tag := &TemplateTag{tagBase: base, Constraint: s.typ(), Names: make([]string, 0)}
+		for {
+			s.skipSpace()
+			lo := s.i
+			for !s.eof() && (isIdentPart(b.s[s.i])) {
+				s.i++
+			}
+			if lo == s.i {
+				break
+			}
+			tag.Names = append(tag.Names, b.s[lo:s.i])
+			for !s.eof() && (b.s[s.i] == ' ' || b.s[s.i] == '\t') {
+				s.i++
+			}
+			if s.eof() || b.s[s.i] != ',' {
+				break
+			}
+			s.i++
+		}
+		tag.Desc = s.desc()
+		return tag
+	case TAG_EXAMPLE:
+		text := b.s[s.i:]
+		if strings.HasPrefix(text, " ") {
+			text = text[1:]
+		}
+		return &TextTag{tagBase: base, Text: strings.TrimLeft(strings.TrimRight(text, " \t\n"), "\n")}
+	}
+	return &TextTag{tagBase: base, Text: s.rest()}
+}
+
+func isIdentPart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
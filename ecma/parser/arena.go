@@ -0,0 +1,259 @@
+package parser
+
+// the count of the values in a chunk of the slab
+const slabChunkLen = 256
+
+// the count of the nodes in a chunk of the node lists
+const listChunkLen = 4096
+
+// allocates the values of type `T` in chunks, the values are released in bulk and their room
+// is reused by the later allocations
+type slab[T any] struct {
+	chunks [][]T
+	i      int // the index of the chunk to allocate from
+	j      int // the index of the next value in that chunk
+}
+
+func (s *slab[T]) next() *T {
+	if s.i == len(s.chunks) {
+		s.chunks = append(s.chunks, make([]T, slabChunkLen))
+	}
+	ret := &s.chunks[s.i][s.j]
+	if s.j++; s.j == slabChunkLen {
+		s.i, s.j = s.i+1, 0
+	}
+	return ret
+}
+
+func (s *slab[T]) alloc(v T) *T {
+	ret := s.next()
+	*ret = v
+	return ret
+}
+
+// calls `fn` with the allocated values and rewinds the slab
+func (s *slab[T]) release(fn func(*T)) {
+	for i := 0; i <= s.i && i < len(s.chunks); i++ {
+		n := slabChunkLen
+		if i == s.i {
+			n = s.j
+		}
+		for j := 0; j < n; j++ {
+			fn(&s.chunks[i][j])
+		}
+	}
+	s.i, s.j = 0, 0
+}
+
+// the backing arrays of the node lists, a list is a slice of the chunk whose capacity is
+// limited by the full slice expression, so appending to it beyond the capacity moves it to
+// the heap instead of overwriting the neighbours
+type nodeLists struct {
+	chunks [][]Node
+	i      int
+	j      int
+}
+
+func (l *nodeLists) alloc(n int) []Node {
+	if l.i < len(l.chunks) && l.j+n > listChunkLen {
+		l.i, l.j = l.i+1, 0
+	}
+	if l.i == len(l.chunks) {
+		l.chunks = append(l.chunks, make([]Node, listChunkLen))
+	}
+	lo := l.j
+	l.j += n
+	return l.chunks[l.i][lo:lo:l.j]
+}
+
+func (l *nodeLists) release() {
+	for i := 0; i <= l.i && i < len(l.chunks); i++ {
+		chunk := l.chunks[i]
+		if i == l.i {
+			chunk = chunk[:l.j]
+		}
+		for j := range chunk {
+			chunk[j] = nil
+		}
+	}
+	l.i, l.j = 0, 0
+}
+
+// Arena allocates the frequent nodes, the node lists, the scopes and the refs in slabs instead
+// of one by one, it's turned on by `ParserOpts.Arena` for the services which parse the sources
+// massively, the garbage collector has much fewer objects to trace and the allocations are
+// reused across the parsings
+//
+// the objects are released in bulk by `Release`, the ASTs and the symtabs of the parsings which
+// use the arena should not be used after it's called, an arena is not safe for concurrent use
+// so it should be used by one parser at a time
+type Arena struct {
+	idents      slab[Ident]
+	members     slab[MemberExpr]
+	calls       slab[CallExpr]
+	bins        slab[BinExpr]
+	assigns     slab[AssignExpr]
+	strs        slab[StrLit]
+	nums        slab[NumLit]
+	exprStmts   slab[ExprStmt]
+	blocks      slab[BlockStmt]
+	varDecStmts slab[VarDecStmt]
+	varDecs     slab[VarDec]
+	typInfos    slab[TypInfo]
+	refs        slab[Ref]
+	scopes      slab[Scope]
+	lists       nodeLists
+}
+
+func NewArena() *Arena {
+	return &Arena{}
+}
+
+func zero[T any](v *T) {
+	var z T
+	*v = z
+}
+
+// releases all the objects allocated from the arena, their room is reused by the later
+// parsings, the maps and the slices of the scopes are kept for the reuse too
+func (a *Arena) Release() {
+	a.idents.release(zero[Ident])
+	a.members.release(zero[MemberExpr])
+	a.calls.release(zero[CallExpr])
+	a.bins.release(zero[BinExpr])
+	a.assigns.release(zero[AssignExpr])
+	a.strs.release(zero[StrLit])
+	a.nums.release(zero[NumLit])
+	a.exprStmts.release(zero[ExprStmt])
+	a.blocks.release(zero[BlockStmt])
+	a.varDecStmts.release(zero[VarDecStmt])
+	a.varDecs.release(zero[VarDec])
+	a.typInfos.release(zero[TypInfo])
+	a.refs.release(zero[Ref])
+	a.scopes.release((*Scope).reset)
+	a.lists.release()
+}
+
+// the copy of `v` on the heap, unlike `&v` it doesn't make `v` escape, otherwise the params of
+// the allocators below are moved to the heap even if they are allocated from the arena
+func onHeap[T any](v T) *T {
+	ret := new(T)
+	*ret = v
+	return ret
+}
+
+func (p *Parser) newIdent(n Ident) *Ident {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.idents.alloc(n)
+}
+
+func (p *Parser) newMemberExpr(n MemberExpr) *MemberExpr {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.members.alloc(n)
+}
+
+func (p *Parser) newCallExpr(n CallExpr) *CallExpr {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.calls.alloc(n)
+}
+
+func (p *Parser) newBinExpr(n BinExpr) *BinExpr {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.bins.alloc(n)
+}
+
+func (p *Parser) newAssignExpr(n AssignExpr) *AssignExpr {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.assigns.alloc(n)
+}
+
+func (p *Parser) newStrLit(n StrLit) *StrLit {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.strs.alloc(n)
+}
+
+func (p *Parser) newNumLit(n NumLit) *NumLit {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.nums.alloc(n)
+}
+
+func (p *Parser) newExprStmt(n ExprStmt) *ExprStmt {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.exprStmts.alloc(n)
+}
+
+func (p *Parser) newBlockStmt(n BlockStmt) *BlockStmt {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.blocks.alloc(n)
+}
+
+func (p *Parser) newVarDecStmt(n VarDecStmt) *VarDecStmt {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.varDecStmts.alloc(n)
+}
+
+func (p *Parser) newVarDec(n VarDec) *VarDec {
+	if p.arena == nil {
+		return onHeap(n)
+	}
+	return p.arena.varDecs.alloc(n)
+}
+
+func (p *Parser) newRef() *Ref {
+	if p.arena == nil {
+		return NewRef()
+	}
+	return p.arena.refs.next()
+}
+
+// the empty node list with the capacity `n`
+func (p *Parser) nodeList(n int) []Node {
+	if p.arena == nil {
+		return make([]Node, 0, n)
+	}
+	return p.arena.lists.alloc(n)
+}
+
+// the emptied `m` for the reuse, a new map is made if it's `nil`
+func emptyMap[K comparable, V any](m map[K]V) map[K]V {
+	if m == nil {
+		return map[K]V{}
+	}
+	for k := range m {
+		delete(m, k)
+	}
+	return m
+}
+
+// the emptied `s` for the reuse, its elements are zeroed to drop their references
+func emptySlice[T any](s []T) []T {
+	if s == nil {
+		return make([]T, 0)
+	}
+	s = s[:cap(s)]
+	var z T
+	for i := range s {
+		s[i] = z
+	}
+	return s[:0]
+}
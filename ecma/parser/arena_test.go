@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func TestArenaAndReset(t *testing.T) {
+	files, err := filepath.Glob("../estree/test/fixture/*/*/input.*")
+	AssertEqual(t, nil, err, "should be ok")
+	more, err := filepath.Glob("../estree/test/fixture/*/*/*/input.*")
+	AssertEqual(t, nil, err, "should be ok")
+	files = append(files, more...)
+
+	arena := NewArena()
+	pooled := &Parser{}
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		AssertEqual(t, nil, err, "should be ok")
+		code := string(b)
+
+		opts := NewParserOpts()
+		opts.Tokens = true
+		if strings.HasSuffix(file, ".ts") || strings.HasSuffix(file, ".tsx") {
+			opts.Feature = opts.Feature.On(FEAT_TS)
+		}
+		if strings.Contains(file, "/flow/") {
+			opts.Feature = opts.Feature.On(FEAT_FLOW)
+		}
+		fresh := NewParser(span.NewSource(file, code), opts)
+		exp, expErr := fresh.Prog()
+
+		arena.Release()
+		opts = opts.Clone()
+		opts.Arena = arena
+		pooled.Reset(span.NewSource(file, code), opts)
+		ast, err := pooled.Prog()
+
+		if expErr != nil || err != nil {
+			if expErr == nil || err == nil || expErr.Error() != err.Error() {
+				t.Fatalf("%s: errors mismatch, expected %v, actual %v", file, expErr, err)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(exp, ast) {
+			t.Fatalf("%s: ast mismatch", file)
+		}
+		if !reflect.DeepEqual(fresh.Tokens(), pooled.Tokens()) {
+			t.Fatalf("%s: tokens mismatch", file)
+		}
+		if !reflect.DeepEqual(fresh.Comments(), pooled.Comments()) {
+			t.Fatalf("%s: comments mismatch", file)
+		}
+		fresh.symtab.rebuildScopes()
+		pooled.symtab.rebuildScopes()
+		symtab := *pooled.symtab
+		symtab.arena = nil
+		if !reflect.DeepEqual(fresh.symtab, &symtab) {
+			t.Fatalf("%s: symtab mismatch", file)
+		}
+	}
+}
@@ -383,6 +383,15 @@ type NodeWithTypInfo interface {
 	SetTypInfo(*TypInfo)
 }
 
+// the type info of the node, some nodes such as `RestPat` have the type info but they're not
+// `NodeWithTypInfo`, nil is returned if the node has no type info
+func TypInfoOf(node Node) *TypInfo {
+	if wt, ok := node.(interface{ TypInfo() *TypInfo }); ok {
+		return wt.TypInfo()
+	}
+	return nil
+}
+
 type Ident struct {
 	typ            NodeType
 	rng            span.Range
@@ -952,12 +961,13 @@ func (n *Super) SetTypInfo(ti *TypInfo) {
 	n.ti = ti
 }
 
-// #[visitor(Src)]
+// #[visitor(Src,Opts)]
 type ImportCall struct {
-	typ NodeType
-	rng span.Range
-	src Node
-	opa span.Range
+	typ  NodeType
+	rng  span.Range
+	src  Node
+	opts Node
+	opa  span.Range
 }
 
 func (n *ImportCall) Type() NodeType {
@@ -972,6 +982,12 @@ func (n *ImportCall) Src() Node {
 	return n.src
 }
 
+// the second argument of the call like `{ with: { type: "json" } }` in `import("./a.json", { with: { type: "json" } })`,
+// it's `nil` if the argument is absent
+func (n *ImportCall) Opts() Node {
+	return n.opts
+}
+
 func (n *ImportCall) OuterParen() span.Range {
 	return n.opa
 }
@@ -1413,6 +1429,11 @@ func (n *VarDecStmt) Kind() string {
 	return TokenKinds[n.kind].Name
 }
 
+// whether it's the `using` or `await using` declaration
+func (n *VarDecStmt) IsUsing() bool {
+	return n.kind == T_USING || n.kind == T_AWAIT_USING
+}
+
 func (n *VarDecStmt) DecList() []Node {
 	return n.decList
 }
@@ -2090,13 +2111,16 @@ func (n *MetaProp) Prop() Node {
 	return n.prop
 }
 
-// #[visitor(Specs,Src)]
+// #[visitor(Specs,Src,Attrs)]
 type ImportDec struct {
-	typ   NodeType
-	rng   span.Range
-	specs []Node
-	src   Node
-	tsTyp bool
+	typ    NodeType
+	rng    span.Range
+	specs  []Node
+	src    Node
+	attrs  []Node
+	attrKw span.Range
+	tsTyp  bool
+	typOf  bool // the flow `import typeof A from "a"`
 }
 
 func (n *ImportDec) Type() NodeType {
@@ -2112,6 +2136,9 @@ func (n *ImportDec) TsTyp() bool {
 }
 
 func (n *ImportDec) Kind() string {
+	if n.typOf {
+		return "typeof"
+	}
 	if n.tsTyp {
 		return "type"
 	}
@@ -2126,6 +2153,17 @@ func (n *ImportDec) Src() Node {
 	return n.src
 }
 
+// the import attributes like `type: "json"` in `import a from "./a.json" with { type: "json" }`
+func (n *ImportDec) Attrs() []Node {
+	return n.attrs
+}
+
+// the range of the keyword `with` or `assert` which leads the attributes, it's empty if
+// the attributes are absent
+func (n *ImportDec) AttrKw() span.Range {
+	return n.attrKw
+}
+
 // #[visitor(Local,Id)]
 type ImportSpec struct {
 	typ   NodeType
@@ -2135,6 +2173,7 @@ type ImportSpec struct {
 	local Node
 	id    Node
 	tsTyp bool // if represents the ts type
+	typOf bool // the flow `import { typeof A } from "a"`
 }
 
 func (n *ImportSpec) Type() NodeType {
@@ -2150,6 +2189,9 @@ func (n *ImportSpec) TsTyp() bool {
 }
 
 func (n *ImportSpec) Kind() string {
+	if n.typOf {
+		return "typeof"
+	}
 	if n.tsTyp {
 		return "type"
 	}
@@ -2172,16 +2214,18 @@ func (n *ImportSpec) Id() Node {
 	return n.id
 }
 
-// #[visitor(Dec,Specs,Src)]
+// #[visitor(Dec,Specs,Src,Attrs)]
 type ExportDec struct {
-	typ   NodeType
-	rng   span.Range
-	all   bool
-	def   span.Range
-	dec   Node
-	specs []Node
-	src   Node
-	tsTyp bool
+	typ    NodeType
+	rng    span.Range
+	all    bool
+	def    span.Range
+	dec    Node
+	specs  []Node
+	src    Node
+	attrs  []Node
+	attrKw span.Range
+	tsTyp  bool
 }
 
 func (n *ExportDec) Type() NodeType {
@@ -2223,6 +2267,17 @@ func (n *ExportDec) Src() Node {
 	return n.src
 }
 
+// the attributes of the re-exported module like `type: "json"` in `export { a } from "./a.json" with { type: "json" }`
+func (n *ExportDec) Attrs() []Node {
+	return n.attrs
+}
+
+// the range of the keyword `with` or `assert` which leads the attributes, it's empty if
+// the attributes are absent
+func (n *ExportDec) AttrKw() span.Range {
+	return n.attrKw
+}
+
 // #[visitor(Local,Id)]
 type ExportSpec struct {
 	typ   NodeType
@@ -2264,6 +2319,38 @@ func (n *ExportSpec) Id() Node {
 	return n.id
 }
 
+// #[visitor(Key,Val)]
+type ImportAttr struct {
+	typ NodeType
+	rng span.Range
+	key Node // Ident | StrLit
+	val Node // StrLit
+}
+
+func (n *ImportAttr) Type() NodeType {
+	return n.typ
+}
+
+func (n *ImportAttr) Range() span.Range {
+	return n.rng
+}
+
+func (n *ImportAttr) Key() Node {
+	return n.key
+}
+
+func (n *ImportAttr) Val() Node {
+	return n.val
+}
+
+// the name of the key, for both `type: "json"` and `"type": "json"` it's `type`
+func (n *ImportAttr) KeyName() string {
+	if n.key.Type() == N_LIT_STR {
+		return n.key.(*StrLit).val
+	}
+	return n.key.(*Ident).val
+}
+
 // #[visitor(Expr)]
 type ChainExpr struct {
 	typ  NodeType
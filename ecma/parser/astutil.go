@@ -124,7 +124,7 @@ func TokText(t *Token, s *span.Source) string {
 		return ""
 	}
 
-	if !t.txt.Empty() {
+	if !t.txt.Empty() && t.txt.Valid() {
 		t.text = s.RngText(t.txt)
 	} else if t.rng.Valid() {
 		t.text = s.RngText(t.rng)
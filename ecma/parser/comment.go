@@ -0,0 +1,506 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+type childNodes []Node
+
+func (c *childNodes) add(nodes ...Node) {
+	for _, n := range nodes {
+		if !util.IsNilPtr(n) {
+			*c = append(*c, n)
+		}
+	}
+}
+
+func (c *childNodes) addTypInfo(ti *TypInfo) {
+	if ti == nil {
+		return
+	}
+	c.add(ti.decorators...)
+	c.add(ti.typParams, ti.typArgs)
+	if ti.typAnnot != nil {
+		c.add(ti.typAnnot)
+	}
+	if ti.clsTyp != nil {
+		c.add(ti.clsTyp.superTypArgs)
+		c.add(ti.clsTyp.implements...)
+	}
+}
+
+// the direct children of the node in the order of their positions, the type annotations and
+// the decorators are included, the nodes which are referenced by the node but not owned by it,
+// such as the target of `break` and the return statements of a function, are excluded
+func ChildNodes(node Node) []Node {
+	if util.IsNilPtr(node) {
+		return nil
+	}
+	c := childNodes{}
+	switch n := node.(type) {
+	case *Prog:
+		c.add(n.stmts...)
+	case *ExprStmt:
+		c.add(n.expr)
+	case *NullLit:
+		c.addTypInfo(n.ti)
+	case *BoolLit:
+		c.addTypInfo(n.ti)
+	case *StrLit:
+		c.addTypInfo(n.ti)
+	case *RegLit:
+		c.addTypInfo(n.ti)
+	case *ArrLit:
+		c.add(n.elems...)
+		c.addTypInfo(n.ti)
+	case *Spread:
+		c.add(n.arg)
+		c.addTypInfo(n.ti)
+	case *ObjLit:
+		c.add(n.props...)
+		c.addTypInfo(n.ti)
+	case *Ident:
+		c.addTypInfo(n.ti)
+	case *NewExpr:
+		c.add(n.callee)
+		c.add(n.args...)
+		c.addTypInfo(n.ti)
+	case *MemberExpr:
+		c.add(n.obj, n.prop)
+	case *CallExpr:
+		c.add(n.callee)
+		c.add(n.args...)
+		c.addTypInfo(n.ti)
+	case *BinExpr:
+		c.add(n.lhs, n.rhs)
+	case *UnaryExpr:
+		c.add(n.arg)
+	case *UpdateExpr:
+		c.add(n.arg)
+	case *CondExpr:
+		c.add(n.test, n.cons, n.alt)
+	case *AssignExpr:
+		c.add(n.lhs, n.rhs)
+		c.addTypInfo(n.ti)
+	case *ThisExpr:
+		c.addTypInfo(n.ti)
+	case *SeqExpr:
+		c.add(n.elems...)
+	case *ParenExpr:
+		c.add(n.expr)
+	case *TplExpr:
+		c.add(n.tag)
+		c.add(n.elems...)
+	case *Super:
+		c.addTypInfo(n.ti)
+	case *ImportCall:
+		c.add(n.src, n.opts)
+	case *YieldExpr:
+		c.add(n.arg)
+	case *ArrPat:
+		c.add(n.elems...)
+		c.addTypInfo(n.ti)
+	case *AssignPat:
+		c.add(n.lhs, n.rhs)
+		c.addTypInfo(n.ti)
+	case *RestPat:
+		c.add(n.arg)
+		c.addTypInfo(n.ti)
+	case *ObjPat:
+		c.add(n.props...)
+		c.addTypInfo(n.ti)
+	case *Prop:
+		c.add(n.key)
+		if n.value != n.key {
+			c.add(n.value)
+		}
+	case *FnDec:
+		c.add(n.id)
+		c.add(n.params...)
+		c.add(n.body)
+		c.addTypInfo(n.ti)
+	case *ArrowFn:
+		c.add(n.params...)
+		c.add(n.body)
+		c.addTypInfo(n.ti)
+	case *VarDecStmt:
+		c.add(n.decList...)
+	case *VarDec:
+		c.add(n.id, n.init)
+	case *BlockStmt:
+		c.add(n.body...)
+	case *DoWhileStmt:
+		c.add(n.body, n.test)
+	case *WhileStmt:
+		c.add(n.test, n.body)
+	case *ForStmt:
+		c.add(n.init, n.test, n.update, n.body)
+	case *ForInOfStmt:
+		c.add(n.left, n.right, n.body)
+	case *IfStmt:
+		c.add(n.test, n.cons, n.alt)
+	case *SwitchStmt:
+		c.add(n.test)
+		c.add(n.cases...)
+	case *SwitchCase:
+		c.add(n.test)
+		c.add(n.cons...)
+	case *BrkStmt:
+		c.add(n.label)
+	case *ContStmt:
+		c.add(n.label)
+	case *LabelStmt:
+		c.add(n.label, n.body)
+	case *RetStmt:
+		c.add(n.arg)
+	case *ThrowStmt:
+		c.add(n.arg)
+	case *Catch:
+		c.add(n.param, n.body)
+	case *TryStmt:
+		c.add(n.try, n.catch, n.fin)
+	case *WithStmt:
+		c.add(n.expr, n.body)
+	case *ClassDec:
+		c.add(n.id, n.super, n.body)
+		c.addTypInfo(n.ti)
+	case *ClassBody:
+		c.add(n.elems...)
+	case *Method:
+		c.add(n.key, n.val)
+		c.addTypInfo(n.ti)
+	case *Field:
+		c.add(n.key, n.val)
+		c.addTypInfo(n.ti)
+	case *StaticBlock:
+		c.add(n.body...)
+		c.addTypInfo(n.ti)
+	case *MetaProp:
+		c.add(n.meta, n.prop)
+	case *ImportDec:
+		c.add(n.specs...)
+		c.add(n.src)
+		c.add(n.attrs...)
+	case *ImportSpec:
+		c.add(n.id)
+		if n.local != n.id {
+			c.add(n.local)
+		}
+	case *ExportDec:
+		c.add(n.dec)
+		c.add(n.specs...)
+		c.add(n.src)
+		c.add(n.attrs...)
+	case *ImportAttr:
+		c.add(n.key, n.val)
+	case *ExportSpec:
+		c.add(n.local)
+		if n.id != n.local {
+			c.add(n.id)
+		}
+	case *ChainExpr:
+		c.add(n.expr)
+	case *Decorator:
+		c.add(n.expr)
+	case *TsTypAnnot:
+		c.add(n.tsTyp)
+	case *TsLit:
+		c.add(n.lit)
+	case *TsNsName:
+		c.add(n.lhs, n.rhs)
+	case *TsRef:
+		c.add(n.name, n.args)
+	case *TsTypQuery:
+		c.add(n.arg)
+	case *TsParen:
+		c.add(n.arg)
+	case *TsArr:
+		c.add(n.arg)
+	case *TsIdxAccess:
+		c.add(n.obj, n.idx)
+	case *TsTuple:
+		c.add(n.args...)
+	case *TsRest:
+		c.add(n.arg)
+	case *TsTupleNamedMember:
+		c.add(n.label, n.val)
+	case *TsObj:
+		c.add(n.props...)
+	case *TsProp:
+		c.add(n.key, n.val)
+	case *TsCallSig:
+		c.add(n.typParams)
+		c.add(n.params...)
+		c.add(n.retTyp)
+	case *TsNewSig:
+		c.add(n.typParams)
+		c.add(n.params...)
+		c.add(n.retTyp)
+	case *TsIdxSig:
+		c.add(n.key, n.val)
+	case *TsRoughParam:
+		c.add(n.name)
+		c.addTypInfo(n.ti)
+	case *TsParamsInst:
+		c.add(n.params...)
+	case *TsParamsDec:
+		c.add(n.params...)
+	case *TsParam:
+		c.add(n.name, n.cons, n.val)
+	case *TsFnTyp:
+		c.add(n.typParams)
+		c.add(n.params...)
+		c.add(n.retTyp)
+	case *TsUnionTyp:
+		c.add(n.elems...)
+	case *TsIntersectTyp:
+		c.add(n.elems...)
+	case *TsTypAssert:
+		c.add(n.des, n.arg)
+	case *TsTypDec:
+		c.add(n.name)
+		c.addTypInfo(n.ti)
+	case *TsInterface:
+		c.add(n.name, n.params)
+		c.add(n.supers...)
+		c.add(n.body)
+	case *TsInterfaceBody:
+		c.add(n.body...)
+	case *TsEnum:
+		c.add(n.name)
+		c.add(n.items...)
+	case *TsEnumMember:
+		c.add(n.key, n.val)
+	case *TsImportAlias:
+		c.add(n.name, n.val)
+	case *TsNS:
+		c.add(n.name, n.body)
+	case *TsImportRequire:
+		c.add(n.name, n.expr)
+	case *TsExportAssign:
+		c.add(n.expr)
+	case *TsDec:
+		c.add(n.name, n.inner)
+	case *TsTypPredicate:
+		c.add(n.name, n.des)
+	case *TsNoNull:
+		c.add(n.arg)
+	case *TsImportType:
+		c.add(n.arg, n.qualifier, n.typArgs)
+	case *TsCondType:
+		c.add(n.check, n.ext, n.trueTyp, n.falseTyp)
+	case *TsTypInfer:
+		c.add(n.arg)
+	case *TsMapped:
+		c.add(n.key, n.name, n.val)
+	case *TsTypOp:
+		c.add(n.arg)
+	case *TsOpt:
+		c.add(n.arg)
+	case *JsxIdent:
+		c.addTypInfo(n.ti)
+	case *JsxNsName:
+		c.add(n.ns, n.name)
+	case *JsxMember:
+		c.add(n.obj, n.prop)
+		c.addTypInfo(n.ti)
+	case *JsxOpen:
+		c.add(n.name)
+		c.add(n.attrs...)
+	case *JsxClose:
+		c.add(n.name)
+	case *JsxAttr:
+		c.add(n.name, n.val)
+	case *JsxSpreadAttr:
+		c.add(n.arg)
+	case *JsxSpreadChild:
+		c.add(n.expr)
+	case *JsxElem:
+		c.add(n.open)
+		c.add(n.children...)
+		c.add(n.close)
+	case *JsxExprSpan:
+		c.add(n.expr)
+	}
+
+	// the nodes in the type info are not in the order of their positions
+	ret := []Node(c)
+	if !sort.SliceIsSorted(ret, func(i, j int) bool { return ret[i].Range().Lo < ret[j].Range().Lo }) {
+		sort.SliceStable(ret, func(i, j int) bool {
+			return ret[i].Range().Lo < ret[j].Range().Lo
+		})
+	}
+	return ret
+}
+
+// the comments attached to the nodes, a comment is attached to exactly one node as one of:
+//
+//   - the leading comment of the node follows it
+//   - the trailing comment of the node precedes it in the same line
+//   - the inner comment of the node encloses it if there is no child adjacent to it, such as
+//     the comments in `{}` or `f(/* empty */)`
+type NodeComments struct {
+	code     string
+	leading  map[Node][]span.Range
+	trailing map[Node][]span.Range
+	inner    map[Node][]span.Range
+}
+
+func (c *NodeComments) Leading(node Node) []span.Range {
+	return c.leading[node]
+}
+
+func (c *NodeComments) Trailing(node Node) []span.Range {
+	return c.trailing[node]
+}
+
+func (c *NodeComments) Inner(node Node) []span.Range {
+	return c.inner[node]
+}
+
+// attaches the comments to the nodes of the tree, `children` returns the direct children of a
+// node in the order of their positions, it's `ChildNodes` for the AST of this package and can be
+// other functions for the trees derived from the AST such as ESTree
+func AttachComments(root Node, cmts []span.Range, code string, children func(Node) []Node) *NodeComments {
+	c := &NodeComments{
+		code:     code,
+		leading:  map[Node][]span.Range{},
+		trailing: map[Node][]span.Range{},
+		inner:    map[Node][]span.Range{},
+	}
+	if len(cmts) > 0 && !util.IsNilPtr(root) {
+		c.attach(root, cmts, children)
+	}
+	return c
+}
+
+// the range covers the node and its children, the type annotations of some nodes are out of
+// their ranges, the descendants of the children are not considered since they are in the
+// ranges of their parents in practice
+func extentOf(node Node, children func(Node) []Node) span.Range {
+	rng := node.Range()
+	for _, kid := range children(node) {
+		r := kid.Range()
+		if r.Empty() {
+			continue
+		}
+		if rng.Empty() || r.Lo < rng.Lo {
+			rng.Lo = r.Lo
+		}
+		if r.Hi > rng.Hi {
+			rng.Hi = r.Hi
+		}
+	}
+	return rng
+}
+
+// skips the whitespaces and the comments from `ofst`, returns the offset of the first token
+// after `ofst` and whether there is a line break before it, the comments in the gaps between
+// the nodes can be recognized by their leading chars since there are no literals in the gaps
+func (c *NodeComments) skip(ofst, hi uint32) (uint32, bool) {
+	nl := false
+	for ofst < hi {
+		switch ch := c.code[ofst]; {
+		case ch == '\n' || ch == '\r':
+			nl = true
+			ofst++
+		case ch == ' ' || ch == '\t' || ch == '\v' || ch == '\f':
+			ofst++
+		case strings.HasPrefix(c.code[ofst:hi], "//"):
+			i := strings.IndexAny(c.code[ofst:hi], "\n\r")
+			if i == -1 {
+				return hi, nl
+			}
+			ofst += uint32(i)
+		case strings.HasPrefix(c.code[ofst:hi], "/*"):
+			i := strings.Index(c.code[ofst+2:hi], "*/")
+			if i == -1 {
+				return hi, nl
+			}
+			ofst += uint32(i) + 4
+		default:
+			return ofst, nl
+		}
+	}
+	return ofst, nl
+}
+
+// whether there are only the whitespaces and the comments in `[lo, hi)`, the line breaks are
+// not permitted unless `nl` is true
+func (c *NodeComments) adjacent(lo, hi uint32, nl bool) bool {
+	end, metNl := c.skip(lo, hi)
+	return end >= hi && (nl || !metNl)
+}
+
+type kidsByExtent struct {
+	kids []Node
+	rngs []span.Range
+}
+
+func (k *kidsByExtent) Len() int           { return len(k.kids) }
+func (k *kidsByExtent) Less(i, j int) bool { return k.rngs[i].Lo < k.rngs[j].Lo }
+func (k *kidsByExtent) Swap(i, j int) {
+	k.kids[i], k.kids[j] = k.kids[j], k.kids[i]
+	k.rngs[i], k.rngs[j] = k.rngs[j], k.rngs[i]
+}
+
+func (c *NodeComments) attach(node Node, cmts []span.Range, children func(Node) []Node) {
+	kids := make([]Node, 0)
+	rngs := make([]span.Range, 0)
+	for _, kid := range children(node) {
+		if rng := extentOf(kid, children); !rng.Empty() {
+			kids = append(kids, kid)
+			rngs = append(rngs, rng)
+		}
+	}
+	sort.Sort(&kidsByExtent{kids, rngs})
+
+	// the comments enclosed by the children are attached recursively
+	inKids := make(map[int][]span.Range)
+	for _, cmt := range cmts {
+		// the index of the last child starts before the comment
+		i := sort.Search(len(rngs), func(i int) bool {
+			return rngs[i].Lo > cmt.Lo
+		}) - 1
+
+		if i >= 0 && rngs[i].Lo <= cmt.Lo && cmt.Hi <= rngs[i].Hi {
+			inKids[i] = append(inKids[i], cmt)
+			continue
+		}
+
+		var prev, next Node
+		if i >= 0 {
+			prev = kids[i]
+		}
+		if i+1 < len(kids) {
+			next = kids[i+1]
+		}
+		// the comment at the end of the line is the trailing comment of the previous node in the
+		// same line, otherwise it's attached to the adjacent node which is not separated from it
+		// by the punctuators, it's the inner comment of the enclosing node if there is no such node
+		end, eol := c.skip(cmt.Hi, uint32(len(c.code)))
+		eol = eol || end == uint32(len(c.code))
+		switch {
+		case prev != nil && eol && !strings.ContainsAny(c.code[rngs[i].Hi:cmt.Lo], "\n\r\u2028\u2029"):
+			c.trailing[prev] = append(c.trailing[prev], cmt)
+		case prev != nil && c.adjacent(rngs[i].Hi, cmt.Lo, false) && (next == nil || !c.adjacent(cmt.Hi, rngs[i+1].Lo, true)):
+			c.trailing[prev] = append(c.trailing[prev], cmt)
+		case next != nil && c.adjacent(cmt.Hi, rngs[i+1].Lo, true):
+			c.leading[next] = append(c.leading[next], cmt)
+		case prev != nil && c.adjacent(rngs[i].Hi, cmt.Lo, true):
+			c.trailing[prev] = append(c.trailing[prev], cmt)
+		default:
+			c.inner[node] = append(c.inner[node], cmt)
+		}
+	}
+
+	for i, kid := range kids {
+		if cs, ok := inKids[i]; ok {
+			c.attach(kid, cs, children)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+// the texts of the comments attached to the nodes in the form of `kind:node:comment`
+func attachedCmts(p *Parser, ast Node) []string {
+	ret := make([]string, 0)
+	cs := p.NodeComments()
+	var visit func(node Node)
+	visit = func(node Node) {
+		for _, c := range cs.Leading(node) {
+			ret = append(ret, "leading:"+p.RngText(node.Range())+":"+p.RngText(c))
+		}
+		for _, c := range cs.Inner(node) {
+			ret = append(ret, "inner:"+p.RngText(node.Range())+":"+p.RngText(c))
+		}
+		for _, kid := range ChildNodes(node) {
+			visit(kid)
+		}
+		for _, c := range cs.Trailing(node) {
+			ret = append(ret, "trailing:"+p.RngText(node.Range())+":"+p.RngText(c))
+		}
+	}
+	visit(ast)
+	return ret
+}
+
+func assertCmts(t *testing.T, code string, opts *ParserOpts, expected ...string) {
+	ast, p, err := compile(code, opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	cmts := attachedCmts(p, ast)
+	AssertEqual(t, len(p.Comments()), len(cmts), "all the comments should be attached")
+	AssertEqual(t, strings.Join(expected, "\n"), strings.Join(cmts, "\n"), "should be ok")
+}
+
+func TestAttachCmtsExpr(t *testing.T) {
+	assertCmts(t, "let a = /* lead */ 1 + 2 // trail", nil,
+		"leading:1 + 2:/* lead */",
+		"trailing:let a = /* lead */ 1 + 2:// trail",
+	)
+
+	assertCmts(t, "f(a /* 1 */, /* 2 */ b)", nil,
+		"trailing:a:/* 1 */",
+		"leading:b:/* 2 */",
+	)
+}
+
+func TestAttachCmtsObj(t *testing.T) {
+	assertCmts(t, `x = {
+  // c1
+  a: 1, // c2
+  /* c3 */ b
+}`, nil,
+		"leading:a: 1:// c1",
+		"trailing:a: 1:// c2",
+		"leading:b:/* c3 */",
+	)
+}
+
+func TestAttachCmtsInner(t *testing.T) {
+	assertCmts(t, `x = {/* e */}
+function f() {
+  // nothing
+}
+// eof`, nil,
+		"inner:{/* e */}:/* e */",
+		"inner:{\n  // nothing\n}:// nothing",
+		"trailing:function f() {\n  // nothing\n}:// eof",
+	)
+
+	assertCmts(t, "/* only */", nil, "inner:/* only */:/* only */")
+
+	assertCmts(t, "f(/* 1 */)\nfunction g(/* 2 */) {}", nil,
+		"inner:f(/* 1 */):/* 1 */",
+		"inner:function g(/* 2 */) {}:/* 2 */",
+	)
+}
+
+func TestAttachCmtsParams(t *testing.T) {
+	assertCmts(t, "function f(/* 1 */ a, b /* 2 */) {}", nil,
+		"leading:a:/* 1 */",
+		"trailing:b:/* 2 */",
+	)
+}
+
+func TestAttachCmtsJsx(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_JSX)
+	assertCmts(t, `<div /* 1 */ a="1" b={/* 2 */ c} />`, opts,
+		"leading:a=\"1\":/* 1 */",
+		"leading:c:/* 2 */",
+	)
+}
+
+func TestAttachCmtsTs(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+	assertCmts(t, `type T = {
+  /* 1 */ a: string
+  b: /* 2 */ number
+}
+let x: /* 3 */ T`, opts,
+		"leading:a: string:/* 1 */",
+		"leading:number:/* 2 */",
+		"leading:T:/* 3 */",
+	)
+}
@@ -1,5 +1,7 @@
 package parser
 
+import "strconv"
+
 type ESVersion int
 
 const (
@@ -12,6 +14,70 @@ const (
 	ES11                         // https://262.ecma-international.org/11.0/
 	ES12                         // https://262.ecma-international.org/12.0/
 	ES13                         // https://262.ecma-international.org/13.0/
+	ES14                         // https://262.ecma-international.org/14.0/
+	ES15                         // https://262.ecma-international.org/15.0/
+	ES16                         // https://262.ecma-international.org/16.0/
 )
 
-// TODO: es version to features group
+// the features reached stage 4 but not yet included in a published edition, it's greater than
+// all the published versions
+const ESNEXT ESVersion = 9999
+
+// the features introduced by each version, the features of a version are the ones introduced
+// by itself and all the versions before it
+var versionFeatures = []struct {
+	ver  ESVersion
+	feat Feature
+}{
+	{ES6, FEAT_LET_CONST | FEAT_SPREAD | FEAT_BINDING_PATTERN | FEAT_BINDING_REST_ELEM | FEAT_MODULE |
+		FEAT_IMPORT_DEC | FEAT_EXPORT_DEC | FEAT_META_PROPERTY | FEAT_REGEXP_UNICODE | FEAT_REGEXP_STICKY |
+		FEAT_ARROW_FN | FEAT_CLASS | FEAT_GENERATOR | FEAT_TPL_LIT | FEAT_FOR_OF | FEAT_DEFAULT_PARAM | FEAT_COMPUTED_KEY},
+	{ES7, FEAT_POW | FEAT_BINDING_REST_ELEM_NESTED},
+	{ES8, FEAT_ASYNC_AWAIT},
+	{ES9, FEAT_BAD_ESCAPE_IN_TAGGED_TPL | FEAT_ASYNC_GENERATOR | FEAT_ASYNC_ITERATION | FEAT_REGEXP_DOT_ALL |
+		FEAT_OBJ_REST_SPREAD | FEAT_REGEXP_NAMED_GROUP | FEAT_REGEXP_LOOKBEHIND},
+	{ES10, FEAT_OPT_CATCH_PARAM | FEAT_JSON_SUPER_SET},
+	{ES11, FEAT_OPT_EXPR | FEAT_NULLISH | FEAT_BIGINT | FEAT_DYNAMIC_IMPORT | FEAT_EXPORT_ALL_AS_NS | FEAT_IMPORT_META},
+	{ES12, FEAT_NUM_SEP | FEAT_LOGIC_ASSIGN},
+	{ES13, FEAT_CLASS_PRV | FEAT_CLASS_PUB_FIELD | FEAT_CLASS_PRIV_FIELD | FEAT_CLASS_PRIV_IN | FEAT_CLASS_STATIC_BLOCK |
+		FEAT_MODULE_STR_NAME | FEAT_GLOBAL_ASYNC | FEAT_REGEXP_HAS_INDICES},
+	{ES14, FEAT_HASHBANG},
+	{ES15, FEAT_REGEXP_UNICODE_SETS},
+	{ES16, FEAT_IMPORT_ATTRS},
+	{ESNEXT, FEAT_USING},
+}
+
+// the features belong to the versions, the others like `FEAT_JSX` and `FEAT_TS` are not
+// affected by the version
+var versionedFeatures = func() Feature {
+	var f Feature
+	for _, vf := range versionFeatures {
+		f |= vf.feat
+	}
+	return f
+}()
+
+// the features supported by the version, the versioned features of `ES5` are none since
+// it's the baseline of mole
+func (v ESVersion) Features() Feature {
+	var f Feature
+	for _, vf := range versionFeatures {
+		if vf.ver > v {
+			break
+		}
+		f |= vf.feat
+	}
+	return f
+}
+
+// the name of the version in the form of the publication year like `ES2020`, except `ES5` and
+// `ESNext`
+func (v ESVersion) String() string {
+	if v == ES5 {
+		return "ES5"
+	}
+	if v == ESNEXT {
+		return "ESNext"
+	}
+	return "ES" + strconv.Itoa(int(v))
+}
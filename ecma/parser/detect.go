@@ -0,0 +1,481 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// the source type and the dialect of the code decided by `Detect`
+type Detection struct {
+	Module bool
+	TS     bool
+	Dts    bool
+	JSX    bool
+	Flow   bool
+
+	// the features in `FEAT_MODULE`, `FEAT_JSX`, `FEAT_TS` and `FEAT_FLOW` which are guessed by
+	// sniffing the tokens rather than decided by the file extension, the pragmas or the
+	// `package.json`, they are the candidates to be revised if the code fails to be parsed
+	guessed Feature
+}
+
+// reports whether the feature is guessed by sniffing the tokens of the code
+func (d *Detection) Guessed(feat Feature) bool {
+	return d.guessed&feat != 0
+}
+
+// turns the features on or off by the detection, the other features in `f` are kept as they
+// are, the scripts are not strict unless they have the `"use strict"` directive
+func (d *Detection) Apply(f Feature) Feature {
+	return f.Turn(FEAT_MODULE, d.Module).Turn(FEAT_STRICT, d.Module).
+		Turn(FEAT_TS, d.TS).Turn(FEAT_DTS, d.Dts).Turn(FEAT_FLOW, d.Flow).
+		Turn(FEAT_JSX, d.JSX)
+}
+
+// the features to reparse the code with if it fails to be parsed with `Apply(f)`, each of
+// them revises one of the guessed features, the ambiguity like `<T>x` between the JSX element
+// and the typescript type assertion is resolved by the first one
+func (d *Detection) alternatives(f Feature) []Feature {
+	base := d.Apply(f)
+	alts := make([]Feature, 0, 3)
+	if d.guessed&FEAT_JSX != 0 {
+		alts = append(alts, base^FEAT_JSX)
+	}
+	if d.guessed&(FEAT_TS|FEAT_FLOW) != 0 {
+		switch {
+		case d.Flow:
+			alts = append(alts, base.Off(FEAT_FLOW).On(FEAT_TS))
+		case d.TS:
+			alts = append(alts, base.Off(FEAT_TS).On(FEAT_FLOW))
+		default:
+			// the untyped code like `let x = <T>(y) => y` is the generic arrow of flow
+			alts = append(alts, base.On(FEAT_FLOW))
+		}
+	}
+	if d.guessed&FEAT_MODULE != 0 {
+		alts = append(alts, base.Turn(FEAT_MODULE, !d.Module).Turn(FEAT_STRICT, !d.Module))
+	}
+	return alts
+}
+
+// the detection revised by the features which the code is parsed successfully with
+func (d *Detection) revise(f Feature) *Detection {
+	return &Detection{
+		Module:  f&FEAT_MODULE != 0,
+		TS:      f&FEAT_TS != 0 && f&FEAT_FLOW == 0,
+		Dts:     f&FEAT_DTS != 0,
+		JSX:     f&FEAT_JSX != 0,
+		Flow:    f&FEAT_FLOW != 0,
+		guessed: d.guessed,
+	}
+}
+
+// detects the source type and the dialect of the code, the file extension is respected firstly,
+// then the pragmas like `@flow` and `@jsx`, the hashbang which runs the code by the typescript
+// runners like `ts-node`, the `type` field of the nearest `package.json` of the file and the
+// tokens of the code are sniffed at last:
+//
+// - the `import` and `export` declarations, `import.meta` and the top-level `await` make the
+// code be a module
+// - `</` and `/>` make the code contain JSX
+// - the type annotations like `let a: T`, `function f(): T` and the declarations like
+// `interface I {}` make the code be typescript or flow, the flow specific syntax like `{| |}`
+// and `opaque type` make it be flow, it's also flow if the file is `.js` since typescript is
+// not allowed in it
+// - the code without the type annotations is the plain javascript, it's reparsed as flow in the
+// auto mode if it fails to be parsed, for the generic arrows like `<T>(x) => x`
+//
+// `filename` can be empty if the code is not from a file, it's used to find the extension
+// and the `package.json`
+func Detect(code, filename string) *Detection {
+	d := &Detection{}
+	sn := sniff(code)
+
+	name := filepath.Base(filename)
+	ext := filepath.Ext(name)
+	switch ext {
+	case ".ts", ".mts", ".cts":
+		d.TS = true
+		d.Dts = isDtsFile(name)
+	case ".tsx":
+		d.TS, d.JSX = true, true
+	case ".flow":
+		d.Flow, d.JSX = true, true
+		d.guessed |= FEAT_JSX
+	default:
+		d.detectDialect(code, ext, sn)
+	}
+
+	switch {
+	case ext == ".mjs" || ext == ".mts":
+		d.Module = true
+	case ext == ".cjs" || ext == ".cts":
+		d.Module = false
+	case sn.module:
+		d.Module = true
+	default:
+		switch pkgType(filename) {
+		case "module":
+			d.Module = true
+		case "commonjs":
+			d.Module = false
+		default:
+			d.Module = sn.await
+			d.guessed |= FEAT_MODULE
+		}
+	}
+	return d
+}
+
+func (d *Detection) detectDialect(code, ext string, sn *sniffed) {
+	js := ext == ".js" || ext == ".jsx" || ext == ".mjs" || ext == ".cjs"
+	switch {
+	case HasFlowPragma(code):
+		d.Flow = true
+	case !js && hasTsHashbang(code):
+		d.TS = true
+	case sn.flow:
+		d.Flow = true
+		d.guessed |= FEAT_FLOW
+	case sn.ts:
+		d.TS = true
+		d.guessed |= FEAT_TS
+	case sn.typed && js:
+		d.Flow = true
+		d.guessed |= FEAT_FLOW
+	case sn.typed:
+		d.TS = true
+		d.guessed |= FEAT_TS
+	default:
+		// no type annotation is sniffed, it's still flow if the code fails to be parsed as the
+		// plain javascript
+		d.guessed |= FEAT_FLOW
+	}
+
+	// JSX is harmless to the plain javascript since `<` can not be at the beginning of the
+	// expressions, however it's ambiguous with the type arguments and assertions like `<T>x`
+	switch {
+	case ext == ".jsx" || hasJsxPragma(code):
+		d.JSX = true
+	case d.TS || d.Flow:
+		d.JSX = sn.jsx || d.Flow
+		d.guessed |= FEAT_JSX
+	default:
+		d.JSX = true
+	}
+}
+
+func isDtsFile(name string) bool {
+	return strings.HasSuffix(name, ".d.ts") || strings.HasSuffix(name, ".d.mts") ||
+		strings.HasSuffix(name, ".d.cts")
+}
+
+// the `type` field of the nearest `package.json` of the file, `""` is returned if it's not found
+func pkgType(filename string) string {
+	if filename == "" {
+		return ""
+	}
+	dir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return ""
+	}
+	for {
+		raw, err := os.ReadFile(filepath.Join(dir, "package.json"))
+		if err == nil {
+			pkg := struct {
+				Type string `json:"type"`
+			}{}
+			if err := json.Unmarshal(raw, &pkg); err != nil {
+				return ""
+			}
+			return pkg.Type
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+var tsRunners = map[string]bool{"ts-node": true, "ts-node-esm": true, "ts-node-script": true, "tsx": true}
+
+// `#!/usr/bin/env ts-node` or `#!/usr/bin/env -S npx tsx`
+func hasTsHashbang(code string) bool {
+	if !strings.HasPrefix(code, "#!") {
+		return false
+	}
+	line := code[2:]
+	if i := strings.IndexByte(line, '\n'); i != -1 {
+		line = line[:i]
+	}
+	for _, field := range strings.Fields(line) {
+		if tsRunners[filepath.Base(field)] {
+			return true
+		}
+	}
+	return false
+}
+
+func hasJsxPragma(code string) bool {
+	return hasLeadingPragma(code, "@jsx") || hasLeadingPragma(code, "@jsxImportSource") ||
+		hasLeadingPragma(code, "@jsxRuntime") || hasLeadingPragma(code, "@jsxFrag")
+}
+
+// reports whether the pragma appears in the comments before the first token of the code, the
+// pragma should not be followed by the identifier parts, so `@flowtype` is not `@flow`
+func hasLeadingPragma(code, pragma string) bool {
+	i := 0
+	n := len(code)
+
+	// the hashbang like `#!/usr/bin/env node`
+	if strings.HasPrefix(code, "#!") {
+		for i < n && code[i] != '\n' {
+			i++
+		}
+	}
+
+	for i < n {
+		c := code[i]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == '\v' {
+			i++
+			continue
+		}
+		if c != '/' || i+1 >= n {
+			return false
+		}
+
+		var cmt string
+		if code[i+1] == '/' {
+			end := strings.IndexByte(code[i:], '\n')
+			if end == -1 {
+				end = n - i
+			}
+			cmt = code[i+2 : i+end]
+			i += end
+		} else if code[i+1] == '*' {
+			end := strings.Index(code[i+2:], "*/")
+			if end == -1 {
+				return false
+			}
+			cmt = code[i+2 : i+2+end]
+			i += end + 4
+		} else {
+			return false
+		}
+
+		if hasPragma(cmt, pragma) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasPragma(cmt, pragma string) bool {
+	for {
+		idx := strings.Index(cmt, pragma)
+		if idx == -1 {
+			return false
+		}
+		cmt = cmt[idx+len(pragma):]
+		if len(cmt) == 0 || !isPragmaPart(cmt[0]) {
+			return true
+		}
+	}
+}
+
+func isPragmaPart(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// the signals found by sniffing the tokens of the code
+type sniffed struct {
+	module bool // the `import` and `export` declarations or `import.meta`
+	await  bool // the top-level `await`
+	jsx    bool // `</` or `/>`
+	typed  bool // the type annotations shared by typescript and flow
+	ts     bool // the typescript specific syntax like `enum E {}` and `x as const`
+	flow   bool // the flow specific syntax like `{| |}` and `opaque type`
+}
+
+func (s *sniffed) done() bool {
+	return s.module && s.jsx && (s.ts || s.flow)
+}
+
+// sniffs the tokens of the code without parsing it, the lexer is restarted after the beginning
+// of the illegal token if it meets one, which is usually caused by the JSX texts like
+// `<p>don't</p>` since the lexer does not know whether it's in JSX without the parser
+func sniff(code string) *sniffed {
+	sn := &sniffed{}
+	for len(code) > 0 && !sn.done() {
+		s := span.NewSource("", code)
+		next := sniffSrc(s, sn)
+		if next == -1 {
+			break
+		}
+		code = code[next:]
+	}
+	return sn
+}
+
+// returns the offset to restart the lexer from, or `-1` if the source is sniffed to the end
+func sniffSrc(s *span.Source, sn *sniffed) int {
+	code := s.Text(0, uint32(s.Len()))
+	l := NewLexer(s)
+	l.feat = defaultFeatures
+
+	// the `(`s are marked whether they are the beginning of the formal params of the functions
+	fnParams := make([]bool, 0, 8)
+	closeFnParams := false
+	depth := 0
+
+	var prev, prev2 Token
+	for !sn.done() {
+		tok := *l.Next()
+		tv := tok.value
+		if tv == T_EOF {
+			return -1
+		}
+		if !tok.IsLegal() {
+			return int(tok.rng.Lo) + 1
+		}
+
+		afterDot := prev.value == T_DOT || prev.value == T_OPT_CHAIN
+		stmtBegin := tok.afterLineTerm || prev.value == T_ILLEGAL || prev.value == T_SEMI ||
+			prev.value == T_BRACE_L || prev.value == T_BRACE_R || prev.value == T_EXPORT
+		switch tv {
+		case T_IMPORT:
+			if afterDot {
+				break
+			}
+			ahead := l.Peek()
+			if ahead.value == T_PAREN_L {
+				break
+			}
+			sn.module = true
+			if IsName(ahead, "typeof", false) {
+				sn.flow = true
+			} else if IsName(ahead, "type", false) {
+				ahead2 := l.Peek2nd()
+				if ahead2.value == T_BRACE_L || ahead2.value == T_MUL ||
+					ahead2.value == T_NAME && !IsName(ahead2, "from", false) {
+					sn.typed = true
+				}
+			}
+		case T_EXPORT:
+			if !afterDot {
+				sn.module = true
+			}
+		case T_AWAIT:
+			if depth == 0 && !afterDot {
+				sn.await = true
+			}
+		case T_BRACE_L:
+			depth++
+		case T_BRACE_R:
+			depth--
+		case T_PAREN_L:
+			fnParams = append(fnParams, prev.value == T_FUNC ||
+				prev.value == T_NAME && prev2.value == T_FUNC)
+		case T_PAREN_R:
+			if len(fnParams) > 0 {
+				closeFnParams = fnParams[len(fnParams)-1]
+				fnParams = fnParams[:len(fnParams)-1]
+			}
+		case T_COLON:
+			// `let a: T`, `(a: T)`, `(a?: T)` and `function f(): T`
+			if prev.value == T_NAME && (prev2.text == "var" || prev2.text == "let" ||
+				prev2.text == "const" || prev2.value == T_PAREN_L) ||
+				prev.value == T_HOOK && prev2.value == T_NAME ||
+				prev.value == T_PAREN_R && closeFnParams {
+				sn.typed = true
+			}
+		case T_LT:
+			// `</div>` and the fragment `<>`
+			if c := charAt(code, tok.rng.Hi); c == '/' || c == '>' && TokenKinds[prev.value].BeforeExpr {
+				sn.jsx = true
+			}
+		case T_DIV:
+			// `<br />`
+			if charAt(code, tok.rng.Hi) == '>' {
+				sn.jsx = true
+			}
+		case T_BIT_OR:
+			// `{| a: T |}`
+			if prev.value == T_BRACE_L && prev.rng.Hi == tok.rng.Lo {
+				sn.flow = true
+			}
+		case T_ENUM:
+			if l.Peek().value == T_NAME {
+				sn.ts = true
+			}
+		case T_NAME:
+			if stmtBegin {
+				sniffDec(l, &tok, sn)
+			} else if IsName(&tok, "as", false) && l.Peek().text == "const" {
+				sn.ts = true
+			}
+		}
+		if tv != T_PAREN_R {
+			closeFnParams = false
+		}
+		prev2, prev = prev, tok
+	}
+	return -1
+}
+
+// sniffs the declarations which begin with the contextual keywords like `interface I {}`
+func sniffDec(l *Lexer, tok *Token, sn *sniffed) {
+	ahead := l.Peek()
+	if ahead.afterLineTerm {
+		return
+	}
+	switch tok.text {
+	case "interface":
+		if ahead.value == T_NAME {
+			ahead2 := l.Peek2nd()
+			if ahead2.value == T_BRACE_L || ahead2.value == T_LT || IsName(ahead2, "extends", false) {
+				sn.typed = true
+			}
+		}
+	case "type":
+		if ahead.value == T_NAME {
+			ahead2 := l.Peek2nd()
+			if ahead2.value == T_ASSIGN || ahead2.value == T_LT {
+				sn.typed = true
+			}
+		}
+	case "opaque":
+		if IsName(ahead, "type", false) {
+			sn.flow = true
+		}
+	case "namespace", "abstract":
+		if ahead.value == T_NAME || ahead.value == T_CLASS {
+			sn.ts = true
+		}
+	case "declare":
+		switch ahead.text {
+		case "var", "let", "const", "function", "class", "module", "type", "interface":
+			sn.typed = true
+		case "enum", "namespace", "global", "abstract":
+			sn.ts = true
+		case "export", "opaque":
+			sn.flow = true
+		}
+	case "private", "protected", "public", "readonly":
+		if ahead.value == T_NAME || ahead.value == T_NAME_PVT {
+			sn.ts = true
+		}
+	}
+}
+
+func charAt(code string, i uint32) byte {
+	if int(i) < len(code) {
+		return code[i]
+	}
+	return 0
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func TestDetectExt(t *testing.T) {
+	d := Detect("let a = 1", "a.tsx")
+	AssertEqual(t, true, d.TS, "should be ok")
+	AssertEqual(t, true, d.JSX, "should be ok")
+	AssertEqual(t, false, d.Guessed(FEAT_JSX), "should be ok")
+
+	d = Detect("let a = 1", "a.mts")
+	AssertEqual(t, true, d.TS, "should be ok")
+	AssertEqual(t, false, d.JSX, "should be ok")
+	AssertEqual(t, true, d.Module, "should be ok")
+	AssertEqual(t, false, d.Guessed(FEAT_MODULE), "should be ok")
+
+	d = Detect("export {}", "a.cjs")
+	AssertEqual(t, false, d.Module, "should be ok")
+
+	d = Detect("declare const a: number", "lib/a.d.ts")
+	AssertEqual(t, true, d.Dts, "should be ok")
+}
+
+func TestDetectSniff(t *testing.T) {
+	d := Detect("const a = import.meta.url", "")
+	AssertEqual(t, true, d.Module, "should be ok")
+
+	d = Detect("const a = await import('a')", "")
+	AssertEqual(t, true, d.Module, "should be ok")
+	AssertEqual(t, true, d.Guessed(FEAT_MODULE), "should be ok")
+
+	d = Detect("function f() { return import('a') }", "")
+	AssertEqual(t, false, d.Module, "should be ok")
+
+	d = Detect("const e = <p>don't</p>; export default e", "")
+	AssertEqual(t, true, d.Module, "should be ok")
+	AssertEqual(t, true, d.JSX, "should be ok")
+
+	d = Detect("function f(a): T {}; let e = <A></A>", "")
+	AssertEqual(t, true, d.TS, "should be ok")
+	AssertEqual(t, true, d.JSX, "should be ok")
+	AssertEqual(t, true, d.Guessed(FEAT_TS), "should be ok")
+
+	d = Detect("let a: number = <number>b", "a.js")
+	AssertEqual(t, true, d.Flow, "should be ok")
+	AssertEqual(t, false, d.TS, "should be ok")
+
+	d = Detect("const a = x as const", "a.js")
+	AssertEqual(t, true, d.TS, "should be ok")
+
+	d = Detect("type A = {| a: T |}", "")
+	AssertEqual(t, true, d.Flow, "should be ok")
+
+	d = Detect("var a = b ? (c) : d; label: for (;;) {}", "")
+	AssertEqual(t, false, d.TS, "should be ok")
+	AssertEqual(t, false, d.Flow, "should be ok")
+	AssertEqual(t, true, d.JSX, "should be ok")
+}
+
+func TestDetectPragma(t *testing.T) {
+	d := Detect("// @flow\nvar a", "a.js")
+	AssertEqual(t, true, d.Flow, "should be ok")
+	AssertEqual(t, false, d.Guessed(FEAT_FLOW), "should be ok")
+
+	d = Detect("/** @jsx h */\nlet a: T = b", "")
+	AssertEqual(t, true, d.TS, "should be ok")
+	AssertEqual(t, true, d.JSX, "should be ok")
+	AssertEqual(t, false, d.Guessed(FEAT_JSX), "should be ok")
+
+	d = Detect("#!/usr/bin/env -S npx tsx\nmain()", "bin/cli")
+	AssertEqual(t, true, d.TS, "should be ok")
+}
+
+func TestDetectPkgType(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "package.json"), []byte(`{"type": "module"}`), 0644)
+	AssertEqual(t, nil, err, "should be ok")
+
+	d := Detect("var a = 1", filepath.Join(dir, "src", "a.js"))
+	AssertEqual(t, true, d.Module, "should be ok")
+	AssertEqual(t, false, d.Guessed(FEAT_MODULE), "should be ok")
+
+	d = Detect("var a = 1", filepath.Join(dir, "a.cjs"))
+	AssertEqual(t, false, d.Module, "should be ok")
+}
+
+func TestAutoReparse(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Auto = true
+
+	// `<T>` is resolved as the type parameters of the generic arrow of flow, the JSX is kept
+	code := "type A = number; const f = <T>(x: T): T => x"
+	p := NewParser(span.NewSource("a.js", code), opts)
+	_, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, true, p.Detection().Flow, "should be ok")
+	AssertEqual(t, true, p.Detection().JSX, "should be ok")
+
+	// the top-level `await` in block is not sniffed
+	p = NewParser(span.NewSource("", "if (a) { await b }"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, true, p.Detection().Module, "should be ok")
+
+	p = NewParser(span.NewSource("", "with (a) {}"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, false, p.Feature()&FEAT_STRICT != 0, "should be ok")
+
+	// the untyped code is reparsed as flow
+	p = NewParser(span.NewSource("a.js", "let x = <T>(y) => y"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, true, p.Detection().Flow, "should be ok")
+	AssertEqual(t, true, p.Detection().JSX, "should be ok")
+
+	p = NewParser(span.NewSource("a.js", "let x = <div>{y}</div>"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, false, p.Detection().Flow, "should be ok")
+
+	p = NewParser(span.NewSource("a.ts", "let a = <div></div>"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, "Unexpected token at a.ts(1:14)", err.Error(), "should be ok")
+}
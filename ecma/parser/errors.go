@@ -33,6 +33,9 @@ type ParserError struct {
 	msg  string
 	file string
 	ofst uint32
+
+	// the error which causes the parsing to be aborted, like the one of the canceled context
+	cause error
 }
 
 func newParserError(p *Parser, msg, file string, ofst uint32) *ParserError {
@@ -49,6 +52,10 @@ func (e *ParserError) Error() string {
 	return fmt.Sprintf("%s at %s(%d:%d)", e.msg, e.file, loc.Line, loc.Col)
 }
 
+func (e *ParserError) Unwrap() error {
+	return e.cause
+}
+
 func (e *ParserError) MarshalJSON() ([]byte, error) {
 	loc := e.p.lexer.src.OfstLineCol(e.ofst)
 	return json.Marshal(&struct {
@@ -72,6 +79,7 @@ const (
 	ERR_UNTERMINATED_REGEXP                        = "Unterminated regular expression"
 	ERR_UNTERMINATED_STR                           = "Unterminated string constant"
 	ERR_INVALID_REGEXP_FLAG                        = "Invalid regular expression flag"
+	ERR_TPL_INVALID_REGEXP                         = "Invalid regular expression: /%s/%s: %s"
 	ERR_IDENT_AFTER_NUMBER                         = "Identifier directly after number"
 	ERR_INVALID_NUMBER                             = "Invalid number"
 	ERR_TPL_EXPECT_NUM_RADIX                       = "Expected number in radix %s"
@@ -108,6 +116,11 @@ const (
 	ERR_REDEF_PROP                                 = "Redefinition of property"
 	ERR_ILLEGAL_NEWLINE_AFTER_THROW                = "Illegal newline after throw"
 	ERR_CONST_DEC_INIT_REQUIRED                    = "Const declarations require an initialization value"
+	ERR_USING_DEC_INIT_REQUIRED                    = "Using declarations require an initialization value"
+	ERR_USING_DEC_BINDING_PATTERN                  = "Using declarations cannot have destructuring patterns"
+	ERR_USING_DEC_AT_SCRIPT_TOP_LEVEL              = "Using declarations are not allowed at the top level of scripts"
+	ERR_USING_DEC_IN_SWITCH_CASE                   = "Using declarations are not allowed directly in the case clauses"
+	ERR_USING_DEC_IN_FOR_IN                        = "Using declarations are not allowed in the head of for-in loops"
 	ERR_TPL_FORBIDDEN_LEXICAL_NAME                 = "%s is disallowed as a lexically bound name"
 	ERR_GETTER_SHOULD_NO_PARAM                     = "Getter must not have any formal parameters"
 	ERR_SETTER_SHOULD_ONE_PARAM                    = "Setter must have exactly one formal parameter"
@@ -147,6 +160,7 @@ const (
 	ERR_UNEXPECTED_PVT_FIELD                       = "Unexpected private field"
 	ERR_DELETE_PVT_FIELD                           = "Private fields can not be deleted"
 	ERR_TPL_ALONE_PVT_FIELD                        = "Private field `%s` must be declared in an enclosing class"
+	ERR_STR_EXPORT_WITHOUT_FROM                    = "A string literal cannot be used as an exported binding without `from`"
 	ERR_OPT_EXPR_IN_NEW                            = "Invalid optional chain from new expression"
 	ERR_OPT_EXPR_IN_TAG                            = "Invalid tagged template on optional chain"
 	ERR_NULLISH_MIXED_WITH_LOGIC                   = "Cannot use unparenthesized `??` within logic expressions"
@@ -157,7 +171,18 @@ const (
 	ERR_ILLEGAL_IMPORT_PROP                        = "The only valid meta property for import is `import.meta`"
 	ERR_META_PROP_CONTAINS_ESCAPE                  = "Meta property can not contain escaped characters"
 	ERR_DYNAMIC_IMPORT_CANNOT_NEW                  = "Cannot use new with `import()`"
+	ERR_DYNAMIC_IMPORT_ARGS                        = "`import()` requires exactly one or two arguments"
+	ERR_TPL_IMPORT_ATTR_DUP_KEY                    = "Duplicate key `%s` is not allowed in import attributes"
+	ERR_IMPORT_ATTR_VALUE_MUST_STR                 = "Only string literals are allowed as import attribute values"
+	ERR_JSON_COMMENT                               = "Comments are not allowed in JSON"
+	ERR_JSON_PROP_KEY                              = "Property keys must be double-quoted strings in JSON"
+	ERR_JSON_STR_QUOTE                             = "Strings must be double-quoted in JSON"
+	ERR_JSON_STR_CTRL_CHAR                         = "Control characters must be escaped in JSON strings"
+	ERR_JSON_STR_ESCAPE                            = "Invalid escape sequence in JSON string"
+	ERR_JSON_NUM                                   = "Invalid number in JSON"
 	ERR_DECORATOR_INVALID_POSITION                 = "Leading decorators must be attached to a class declaration"
+	ERR_DECORATORS_BEFORE_AND_AFTER_EXPORT         = "Decorators may not appear after `export` if they also appear before `export`"
+	ERR_TPL_REQUIRES_VERSION                       = "%s requires %s"
 
 	// JSX related errors
 	ERR_UNTERMINATED_JSX_CONTENTS           = "Unterminated JSX contents"
@@ -204,6 +229,8 @@ const (
 	ERR_IMPORT_TYPE_IN_IMPORT_ALIAS            = "An import alias can not use `import type`"
 	ERR_ABSTRACT_AT_INVALID_POSITION           = "`abstract` modifier can only appear on a class, method, or property declaration"
 	ERR_ACCESSOR_WITH_TYPE_PARAMS              = "An accessor cannot have type parameters"
+	ERR_ACCESSOR_MOD_ON_NON_FIELD              = "`accessor` modifier can only appear on a property declaration"
+	ERR_TPL_ACCESSOR_MIXED_WITH                = "`accessor` modifier cannot be used with `%s` modifier"
 	ERR_GETTER_WITH_PARAMS                     = "A `get` accessor cannot have parameters"
 	ERR_SETTER_WITH_PARAM_OPTIONAL             = "A `set` accessor cannot have an optional parameter"
 	ERR_SETTER_MISSING_PARAM                   = "A `set` accessor must have exactly one parameter"
@@ -222,4 +249,18 @@ const (
 	ERR_TUPLE_NAMED_SHOULD_ALL_NAMED           = "Tuple members must all have names or all not have names"
 	ERR_TUPLE_LABEL_SHOULD_BE_SIMPLE           = "Tuple members must be labeled with a simple identifier"
 	ERR_TUPLE_OPT_SHOULD_AFTER_REQUIRED        = "A required element cannot follow an optional element"
+	ERR_TPL_VARIANCE_ON_NON_TYPE_PARAM         = "`%s` modifier can only appear on a type parameter of a class, interface or type alias"
+
+	// Flow related errors
+	ERR_FLOW_TYPE_CAST_IN_SEQ    = "The type cast expression is expected to be wrapped with parenthesis"
+	ERR_FLOW_INEXACT_NOT_LAST    = "Explicit inexact syntax must appear at the end of an inexact object"
+	ERR_FLOW_INEXACT_IN_EXACT    = "Explicit inexact syntax cannot appear inside an explicit exact object type"
+	ERR_FLOW_UNEXPECTED_VARIANCE = "Unexpected variance sigil"
+	ERR_FLOW_OPAQUE_MISSING_IMPL = "Opaque type must have an underlying type unless it's declared"
+
+	// the limits of `ParserOpts`
+	ERR_TPL_SRC_TOO_LARGE    = "Source exceeds the maximum size of %d bytes"
+	ERR_TPL_NESTING_TOO_DEEP = "Nesting exceeds the maximum depth of %d"
+	ERR_TPL_TOO_MANY_TOKENS  = "Source exceeds the maximum count of %d tokens"
+	ERR_TPL_PARSING_ABORTED  = "Parsing is aborted: %s"
 )
@@ -14,6 +14,13 @@ const (
 	FEAT_IMPORT_DEC        // from es6
 	FEAT_EXPORT_DEC        // from es6
 	FEAT_META_PROPERTY     // from es6
+	FEAT_ARROW_FN          // from es6
+	FEAT_CLASS             // from es6
+	FEAT_GENERATOR         // from es6
+	FEAT_TPL_LIT           // from es6
+	FEAT_FOR_OF            // from es6
+	FEAT_DEFAULT_PARAM     // from es6
+	FEAT_COMPUTED_KEY      // from es6
 
 	FEAT_POW                      // from es7
 	FEAT_BINDING_REST_ELEM_NESTED // from es7
@@ -23,22 +30,31 @@ const (
 	FEAT_BAD_ESCAPE_IN_TAGGED_TPL // from es9
 	FEAT_ASYNC_GENERATOR          // from es9
 	FEAT_ASYNC_ITERATION          // from es9
+	FEAT_OBJ_REST_SPREAD          // from es9
+	FEAT_REGEXP_NAMED_GROUP       // from es9
+	FEAT_REGEXP_LOOKBEHIND        // from es9
 
 	FEAT_OPT_CATCH_PARAM // from es10
 	FEAT_JSON_SUPER_SET  // from es10
 
-	FEAT_CLASS_PRV        // from es11
+	FEAT_CLASS_PRV        // from es13
 	FEAT_OPT_EXPR         // from es11
 	FEAT_NULLISH          // from es11
 	FEAT_BIGINT           // from es11
 	FEAT_DYNAMIC_IMPORT   // from es11
 	FEAT_EXPORT_ALL_AS_NS // from es11
+	FEAT_IMPORT_META      // from es11
 
 	FEAT_NUM_SEP      // from es12
 	FEAT_LOGIC_ASSIGN // from es12
 
-	FEAT_CLASS_PUB_FIELD  // from es13
-	FEAT_CLASS_PRIV_FIELD // from es13
+	FEAT_CLASS_PUB_FIELD    // from es13
+	FEAT_CLASS_PRIV_FIELD   // from es13
+	FEAT_CLASS_PRIV_IN      // from es13
+	FEAT_CLASS_STATIC_BLOCK // from es13
+	FEAT_MODULE_STR_NAME    // from es13
+
+	FEAT_HASHBANG // from es14
 
 	FEAT_JSX
 	FEAT_JSX_NS
@@ -47,15 +63,32 @@ const (
 	// even though it's implemented in some other parsers, so flag `FEAT_CHK_REGEXP_FLAGS`
 	// is opt-in in mole
 	FEAT_CHK_REGEXP_FLAGS
-	FEAT_REGEXP_UNICODE     // from es6
-	FEAT_REGEXP_STICKY      // from es6
-	FEAT_REGEXP_DOT_ALL     // from es8
-	FEAT_REGEXP_HAS_INDICES // from es10
+	FEAT_REGEXP_UNICODE      // from es6
+	FEAT_REGEXP_STICKY       // from es6
+	FEAT_REGEXP_DOT_ALL      // from es9
+	FEAT_REGEXP_HAS_INDICES  // from es13
+	FEAT_REGEXP_UNICODE_SETS // from es15
+
+	// the patterns of the regexps are parsed and the early errors of them are reported, like
+	// the duplicated group names in `/(?<a>x)(?<a>y)/` and the out-of-order range in `/[z-a]/`
+	FEAT_CHK_REGEXP
 
 	FEAT_TS
 	FEAT_DTS
 
 	FEAT_DECORATOR
+
+	// the explicit resource management like `using x = res()` and `await using x = res()`
+	FEAT_USING
+
+	// the import attributes like `import a from "./a.json" with { type: "json" }` and its legacy
+	// form `assert { type: "json" }`, also the second argument of `import()`
+	FEAT_IMPORT_ATTRS
+
+	// the flow type annotations, they are parsed by the typescript facilities so `FEAT_TS` is
+	// also turned on by it, the flow specific syntax like the exact object `{| a: T |}`, the
+	// variance sigils and the type casts `(x: T)` are only recognized in this mode
+	FEAT_FLOW
 )
 
 func (f Feature) On(flag Feature) Feature {
@@ -72,3 +105,75 @@ func (f Feature) Turn(flag Feature, on bool) Feature {
 	}
 	return f.Off(flag)
 }
+
+// the version introduces the features, `0` is returned if any of them is not versioned or
+// they are introduced by different versions
+func (f Feature) Version() ESVersion {
+	if f == FEAT_NONE {
+		return 0
+	}
+	for _, vf := range versionFeatures {
+		if vf.feat&f == f {
+			return vf.ver
+		}
+	}
+	return 0
+}
+
+// the names of the versioned features used in the diagnostics
+var featureSyntax = map[Feature]string{
+	FEAT_LET_CONST:                       "Lexical declaration",
+	FEAT_SPREAD:                          "Spread syntax",
+	FEAT_BINDING_PATTERN:                 "Destructuring pattern",
+	FEAT_BINDING_REST_ELEM:               "Rest element",
+	FEAT_SPREAD | FEAT_BINDING_REST_ELEM: "Spread and rest syntax",
+	FEAT_MODULE:                          "Module",
+	FEAT_IMPORT_DEC:                      "Import declaration",
+	FEAT_EXPORT_DEC:                      "Export declaration",
+	FEAT_META_PROPERTY:                   "`new.target`",
+	FEAT_ARROW_FN:                        "Arrow function",
+	FEAT_CLASS:                           "Class",
+	FEAT_GENERATOR:                       "Generator",
+	FEAT_TPL_LIT:                         "Template literal",
+	FEAT_FOR_OF:                          "`for-of` statement",
+	FEAT_DEFAULT_PARAM:                   "Default parameter",
+	FEAT_COMPUTED_KEY:                    "Computed property name",
+	FEAT_POW:                             "Exponentiation operator",
+	FEAT_BINDING_REST_ELEM_NESTED:        "Nested rest element",
+	FEAT_ASYNC_AWAIT:                     "Async function",
+	FEAT_BAD_ESCAPE_IN_TAGGED_TPL:        "Invalid escape in tagged template",
+	FEAT_ASYNC_GENERATOR:                 "Async generator",
+	FEAT_ASYNC_ITERATION:                 "Async iteration",
+	FEAT_OBJ_REST_SPREAD:                 "Object rest and spread",
+	FEAT_REGEXP_NAMED_GROUP:              "RegExp named group",
+	FEAT_REGEXP_LOOKBEHIND:               "RegExp lookbehind assertion",
+	FEAT_OPT_CATCH_PARAM:                 "Optional catch binding",
+	FEAT_JSON_SUPER_SET:                  "Line separator in string",
+	FEAT_CLASS_PRV:                       "Private name",
+	FEAT_OPT_EXPR:                        "Optional chaining",
+	FEAT_NULLISH:                         "Nullish coalescing",
+	FEAT_BIGINT:                          "BigInt literal",
+	FEAT_DYNAMIC_IMPORT:                  "Dynamic import",
+	FEAT_EXPORT_ALL_AS_NS:                "`export * as ns`",
+	FEAT_IMPORT_META:                     "`import.meta`",
+	FEAT_NUM_SEP:                         "Numeric separator",
+	FEAT_LOGIC_ASSIGN:                    "Logical assignment",
+	FEAT_CLASS_PUB_FIELD:                 "Class field",
+	FEAT_CLASS_PRIV_FIELD:                "Private class field",
+	FEAT_CLASS_PRIV_IN:                   "Private name in `in` expression",
+	FEAT_CLASS_STATIC_BLOCK:              "Class static block",
+	FEAT_MODULE_STR_NAME:                 "String module export name",
+	FEAT_HASHBANG:                        "Hashbang comment",
+	FEAT_GLOBAL_ASYNC:                    "Top-level await",
+	FEAT_REGEXP_UNICODE:                  "RegExp flag `u`",
+	FEAT_REGEXP_STICKY:                   "RegExp flag `y`",
+	FEAT_REGEXP_DOT_ALL:                  "RegExp flag `s`",
+	FEAT_REGEXP_HAS_INDICES:              "RegExp flag `d`",
+	FEAT_REGEXP_UNICODE_SETS:             "RegExp flag `v`",
+	FEAT_USING:                           "Using declaration",
+	FEAT_IMPORT_ATTRS:                    "Import attributes",
+}
+
+func (f Feature) Syntax() string {
+	return featureSyntax[f]
+}
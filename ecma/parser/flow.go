@@ -0,0 +1,490 @@
+package parser
+
+import (
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// reports whether the `@flow` pragma appears in the comments before the first token of the
+// code, the `@noflow` is not considered as the pragma, it's used to decide whether to turn on
+// `FEAT_FLOW` for the `.js` files in the codebase partially typed by flow:
+//
+// ```js
+// // @flow
+// import type { A } from "./a"
+// ```
+func HasFlowPragma(code string) bool {
+	return hasLeadingPragma(code, "@flow")
+}
+
+// `opaque type A = T`
+func (p *Parser) aheadIsFlowOpaque(tok *Token) bool {
+	if !p.flow || !IsName(tok, "opaque", false) {
+		return false
+	}
+	ahead := p.lexer.Peek2nd()
+	return !ahead.afterLineTerm && IsName(ahead, "type", false)
+}
+
+// the opaque type alias `opaque type A: Super = T`, the underlying type can be omitted
+// only if it's declared as `declare opaque type A: Super`
+func (p *Parser) flowOpaqueTyp(declare bool) (Node, error) {
+	rng := p.lexer.Next().rng // `opaque`
+	p.lexer.Next()            // `type`
+
+	name, err := p.ident(nil, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ref := p.newRef()
+	ref.Id = name
+	ref.BindKind = BK_LET
+	ref.Typ = RDT_TYPE
+	if err := p.addLocalBinding(nil, ref, true, ref.Id.val); err != nil {
+		return nil, err
+	}
+
+	params, err := p.tsTryTypParams(true)
+	if err != nil {
+		return nil, err
+	}
+
+	var super Node
+	if p.lexer.Peek().value == T_COLON {
+		p.lexer.Next()
+		super, err = p.tsTyp(false, false, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var typAnnot Node
+	ahead := p.lexer.Peek()
+	if ahead.value == T_ASSIGN {
+		p.lexer.Next()
+		typAnnot, err = p.tsTyp(false, false, true)
+		if err != nil {
+			return nil, err
+		}
+	} else if !declare {
+		return nil, p.errorAtLoc(ahead.rng, ERR_FLOW_OPAQUE_MISSING_IMPL)
+	}
+
+	if err := p.advanceIfSemi(true); err != nil {
+		return nil, err
+	}
+
+	ti := p.newTypInfo(N_TS_TYP_DEC)
+	ti.SetTypParams(params)
+	ti.SetTypAnnot(typAnnot)
+	return &TsTypDec{N_TS_TYP_DEC, p.finRng(rng), name, ti, true, super}, nil
+}
+
+// the object type of flow, it differs from the one of typescript in:
+//
+// - the exact object `{| a: T |}`
+// - the explicit inexact object `{ a: T, ... }` and the spread of the other object types `{ ...A, b: T }`
+// - the variance sigils before the keys like `{ +a: T, -b: T }`
+// - the indexers can have no name like `{ [string]: number }`
+// - there is no mapped type
+func (p *Parser) flowObj(rng span.Range) (Node, error) {
+	exact := false
+	ahead := p.lexer.Peek()
+	if ahead.value == T_BIT_OR {
+		p.lexer.Next()
+		exact = true
+	} else if ahead.value == T_OR {
+		// `{||}`
+		p.lexer.Next()
+		if _, err := p.nextMustTok(T_BRACE_R); err != nil {
+			return nil, err
+		}
+		return &TsObj{N_TS_LIT_OBJ, p.finRng(rng), []Node{}, span.Range{}, true, false}, nil
+	}
+
+	props := make([]Node, 0, 1)
+	var inexact span.Range
+	for {
+		ahead := p.lexer.Peek()
+		av := ahead.value
+		if exact && av == T_BIT_OR && p.lexer.Peek2nd().value == T_BRACE_R {
+			p.lexer.Next()
+			p.lexer.Next()
+			break
+		} else if !exact && av == T_BRACE_R {
+			p.lexer.Next()
+			break
+		} else if av == T_EOF {
+			return nil, p.errorTok(ahead)
+		}
+
+		if !inexact.Empty() {
+			return nil, p.errorAtLoc(inexact, ERR_FLOW_INEXACT_NOT_LAST)
+		}
+
+		if av == T_DOT_TRI {
+			loc := p.lexer.Next().rng
+			ahead := p.lexer.Peek()
+			av := ahead.value
+			if av == T_BRACE_R || av == T_COMMA || av == T_SEMI || av == T_BIT_OR {
+				if exact {
+					return nil, p.errorAtLoc(loc, ERR_FLOW_INEXACT_IN_EXACT)
+				}
+				inexact = loc
+			} else {
+				arg, err := p.tsTyp(false, false, true)
+				if err != nil {
+					return nil, err
+				}
+				props = append(props, &TsRest{N_TS_REST, p.finRng(loc), arg})
+			}
+		} else {
+			var variance span.Range
+			if av == T_ADD || av == T_SUB {
+				variance = p.lexer.Next().rng
+			}
+
+			prop, err := p.tsProp(false, false, rng)
+			if err != nil {
+				return nil, err
+			}
+
+			if !variance.Empty() {
+				switch n := prop.(type) {
+				case *TsProp:
+					if n.IsMethod() {
+						return nil, p.errorAtLoc(variance, ERR_FLOW_UNEXPECTED_VARIANCE)
+					}
+					n.variance = variance
+					n.rng.Lo = variance.Lo
+				case *TsIdxSig:
+					n.variance = variance
+					n.rng.Lo = variance.Lo
+				default:
+					return nil, p.errorAtLoc(variance, ERR_FLOW_UNEXPECTED_VARIANCE)
+				}
+			}
+			props = append(props, prop)
+		}
+
+		ahead = p.lexer.Peek()
+		av = ahead.value
+		if av == T_COMMA || av == T_SEMI {
+			p.lexer.Next()
+		}
+	}
+	return &TsObj{N_TS_LIT_OBJ, p.finRng(rng), props, span.Range{}, exact, !inexact.Empty()}, nil
+}
+
+// the indexer of flow like `[k: string]: number`, the name of the key is optional
+func (p *Parser) flowIdxSig(rng span.Range) (Node, error) {
+	p.lexer.Next() // `[`
+
+	var key Node
+	var err error
+	ahead := p.lexer.Peek()
+	if (ahead.value == T_NAME || ahead.IsKw()) && p.lexer.Peek2nd().value == T_COLON {
+		id, err := p.identWithKw(nil, false)
+		if err != nil {
+			return nil, err
+		}
+		typAnnot, err := p.tsTypAnnot()
+		if err != nil {
+			return nil, err
+		}
+		id.(*Ident).ti.SetTypAnnot(typAnnot)
+		key = id
+	} else {
+		key, err = p.tsTyp(false, false, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.nextMustTok(T_BRACKET_R); err != nil {
+		return nil, err
+	}
+
+	val, err := p.tsTypAnnot()
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, p.errorTok(p.lexer.Peek())
+	}
+	p.advanceIfSemi(false)
+	return &TsIdxSig{N_TS_IDX_SIG, p.finRng(rng), key, val, span.Range{}, span.Range{}}, nil
+}
+
+// the parenthesized type or the function type like `<T>(a: T, string) => void`, unlike
+// typescript the params of the function types of flow can have no name
+func (p *Parser) flowParen() (Node, error) {
+	rng := p.lexer.Peek().rng
+	typParams, err := p.tsTryTypParams(false)
+	if err != nil {
+		return nil, err
+	}
+
+	parenL, err := p.nextMustTok(T_PAREN_L)
+	if err != nil {
+		return nil, err
+	}
+	parenRng := parenL.rng
+
+	params, paren, err := p.flowFnParams()
+	if err != nil {
+		return nil, err
+	}
+
+	if paren && typParams == nil && p.lexer.Peek().value != T_ARROW {
+		return &TsParen{N_TS_PAREN, p.finRng(parenRng), params[0], span.Range{}}, nil
+	}
+
+	arrow, err := p.nextMustTok(T_ARROW)
+	if err != nil {
+		return nil, err
+	}
+	arrowRng := arrow.rng
+
+	retTyp, err := p.tsTyp(false, false, false)
+	if err != nil {
+		return nil, err
+	}
+	ti := &TsTypAnnot{N_TS_TYP_ANNOT, p.finRng(arrowRng), retTyp}
+	return &TsFnTyp{N_TS_FN_TYP, p.finRng(rng), typParams, params, ti, span.Range{}}, nil
+}
+
+// parses the params after the `(` of the function types, the named ones are represented by
+// the `Ident` with type annotation and the unnamed ones are the types themselves, the rest
+// param is `RestPat` if it's named otherwise it's `TsRest`
+//
+// `paren` indicates the params can be a parenthesized type like `(A | B)`
+func (p *Parser) flowFnParams() (params []Node, paren bool, err error) {
+	params = make([]Node, 0, 2)
+	paren = true
+	for {
+		ahead := p.lexer.Peek()
+		if ahead.value == T_PAREN_R {
+			p.lexer.Next()
+			break
+		}
+
+		var param Node
+		if param, err = p.flowFnParam(); err != nil {
+			return
+		}
+		params = append(params, param)
+
+		ahead = p.lexer.Peek()
+		if ahead.value == T_COMMA {
+			tok := p.lexer.Next()
+			if param.Type() == N_PAT_REST || param.Type() == N_TS_REST {
+				err = p.errorAt(tok.value, tok.rng, ERR_REST_ELEM_MUST_LAST)
+				return
+			}
+			paren = false
+		} else if ahead.value != T_PAREN_R {
+			err = p.errorTok(ahead)
+			return
+		}
+
+		if param.Type() == N_NAME || param.Type() == N_PAT_REST || param.Type() == N_TS_REST {
+			paren = false
+		}
+	}
+	paren = paren && len(params) == 1
+	return
+}
+
+func (p *Parser) flowFnParam() (Node, error) {
+	ahead := p.lexer.Peek()
+	rng := ahead.rng
+	rest := ahead.value == T_DOT_TRI
+	if rest {
+		p.lexer.Next()
+		ahead = p.lexer.Peek()
+	}
+
+	var param Node
+	var err error
+	av := p.lexer.Peek2nd().value
+	if (ahead.value == T_NAME || ahead.value == T_THIS || ahead.IsKw()) && (av == T_COLON || av == T_HOOK) {
+		id, err := p.identWithKw(nil, false)
+		if err != nil {
+			return nil, err
+		}
+		ques, _ := p.tsAdvanceHook(false)
+		if _, err := p.nextMustTok(T_COLON); err != nil {
+			return nil, err
+		}
+		typ, err := p.tsTyp(false, false, true)
+		if err != nil {
+			return nil, err
+		}
+		ti := id.(*Ident).ti
+		ti.SetQues(ques)
+		ti.SetTypAnnot(&TsTypAnnot{N_TS_TYP_ANNOT, typ.Range(), typ})
+		param = id
+	} else {
+		param, err = p.tsTyp(false, false, true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !rest {
+		return param, nil
+	}
+	if param.Type() == N_NAME {
+		return &RestPat{N_PAT_REST, p.finRng(rng), param, span.Range{}, p.newTypInfo(N_PAT_REST)}, nil
+	}
+	return &TsRest{N_TS_REST, p.finRng(rng), param}, nil
+}
+
+// `import { typeof A } from "a"`, the `typeof` is the name of the imported binding if it's
+// followed by `as`, `,` or `}`
+func (p *Parser) aheadIsFlowTypOfSpec() bool {
+	ahead := p.lexer.Peek2nd()
+	av := ahead.value
+	if av == T_COMMA || av == T_BRACE_R {
+		return false
+	}
+	if IsName(ahead, "as", false) {
+		return false
+	}
+	return true
+}
+
+// wraps the expression by the flow type cast like `(a.b: T)` with the type annotation
+// after the `:`
+func (p *Parser) flowTypCast(expr Node) (Node, error) {
+	typAnnot, err := p.tsTypAnnot()
+	if err != nil {
+		return nil, err
+	}
+	return &TsTypAssert{N_TS_TYP_ASSERT, p.finRng(expr.Range()), typAnnot, expr, span.Range{}}, nil
+}
+
+// the type annotations of the expressions like `(a: T)` are attached to them by `assignExpr`,
+// they are moved to the type casts which wrap the expressions since the parenthesized expression
+// is not followed by `=>`, the type casts cannot be the items of the sequence expression
+func (p *Parser) flowTypCasts(args []Node) error {
+	for i, arg := range args {
+		if arg.Type() != N_TS_TYP_ASSERT {
+			wt, ok := arg.(NodeWithTypInfo)
+			if !ok || wt.TypInfo() == nil || wt.TypInfo().TypAnnot() == nil {
+				continue
+			}
+			ti := wt.TypInfo()
+			if !ti.Ques().Empty() {
+				return p.errorAtLoc(ti.Ques(), ERR_UNEXPECTED_TOKEN)
+			}
+			typAnnot := ti.TypAnnot()
+			ti.SetTypAnnot(nil)
+			arg = &TsTypAssert{N_TS_TYP_ASSERT, span.Range{Lo: arg.Range().Lo, Hi: typAnnot.Range().Hi}, typAnnot, arg, span.Range{}}
+			args[i] = arg
+		} else if !arg.(*TsTypAssert).Cast() {
+			continue
+		}
+		if len(args) > 1 {
+			return p.errorAtLoc(arg.Range(), ERR_FLOW_TYPE_CAST_IN_SEQ)
+		}
+	}
+	return nil
+}
+
+// `declare export function f(): void`, the `declare` is consumed by the caller
+func (p *Parser) flowDecExport() (Node, error) {
+	rng := p.lexer.Next().rng // `export`
+
+	var err error
+	node := &ExportDec{N_STMT_EXPORT, span.Range{}, false, span.Range{}, nil, nil, nil, nil, span.Range{}, false}
+	tok := p.lexer.Peek()
+	tv := tok.value
+	if tv == T_MUL || tv == T_BRACE_L {
+		specs, all, src, err := p.exportFrom(false)
+		if err != nil {
+			return nil, err
+		}
+		node.specs = specs
+		node.src = src
+		node.all = all
+		if err := p.advanceIfSemi(true); err != nil {
+			return nil, err
+		}
+	} else if tv == T_DEFAULT {
+		node.def = p.lexer.Next().rng
+		tok = p.lexer.Peek()
+		if tok.value == T_FUNC || tok.value == T_CLASS {
+			node.dec, err = p.tsDecAt(tok.rng)
+		} else {
+			// `declare export default string;`
+			node.dec, err = p.tsTyp(false, false, true)
+			if err == nil {
+				err = p.advanceIfSemi(true)
+			}
+		}
+	} else {
+		node.dec, err = p.tsDecAt(tok.rng)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	node.rng = p.finRng(rng)
+	return node, nil
+}
+
+// the inline interface type `interface extends A { m(): void }`, it's represented by the
+// interface without name and type parameters
+func (p *Parser) flowItfTyp() (Node, error) {
+	rng := p.lexer.Next().rng // `interface`
+
+	supers, err := p.tsItfExtClause()
+	if err != nil {
+		return nil, err
+	}
+
+	scope := p.symtab.EnterScope(false, false, true)
+	scope.AddKind(SPK_TS_INTERFACE)
+	body, err := p.tsObj(false)
+	if err != nil {
+		return nil, err
+	}
+	p.symtab.LeaveScope()
+
+	itfBody := &TsInterfaceBody{
+		typ:  N_TS_INTERFACE_BODY,
+		rng:  body.(*TsObj).rng,
+		body: body.(*TsObj).props,
+	}
+	return &TsInterface{N_TS_INTERFACE, p.finRng(rng), nil, nil, supers, itfBody}, nil
+}
+
+// `declare module.exports: T`, the `declare` is consumed by the caller
+func (p *Parser) aheadIsFlowModExps(tok *Token) bool {
+	return p.flow && IsName(tok, "module", false) && p.lexer.Peek2nd().value == T_DOT
+}
+
+// the type of the CommonJS exports of the ambient module, it's recorded as the type annotation
+// in the inner of the `TsDec`
+func (p *Parser) flowModExps() (Node, error) {
+	p.lexer.Next() // `module`
+	p.lexer.Next() // `.`
+	if _, err := p.nextMustName("exports", false); err != nil {
+		return nil, err
+	}
+
+	if ahead := p.lexer.Peek(); ahead.value != T_COLON {
+		return nil, p.errorTok(ahead)
+	}
+	typAnnot, err := p.tsTypAnnot()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := p.advanceIfSemi(true); err != nil {
+		return nil, err
+	}
+	return typAnnot, nil
+}
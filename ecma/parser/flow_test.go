@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func flowOpts() *ParserOpts {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_FLOW).Off(FEAT_JSX)
+	return opts
+}
+
+func TestFlowPragma(t *testing.T) {
+	AssertEqual(t, true, HasFlowPragma("// @flow\nvar a"), "should be ok")
+	AssertEqual(t, true, HasFlowPragma("#!/usr/bin/env node\n/**\n * @flow strict\n */\nvar a"), "should be ok")
+	AssertEqual(t, true, HasFlowPragma("// license\n\n// @flow"), "should be ok")
+	AssertEqual(t, false, HasFlowPragma("// @noflow\nvar a"), "should be ok")
+	AssertEqual(t, false, HasFlowPragma("// @flowtype\nvar a"), "should be ok")
+	AssertEqual(t, false, HasFlowPragma("var a // @flow"), "should be ok")
+	AssertEqual(t, false, HasFlowPragma(""), "should be ok")
+}
+
+func TestFlowObj(t *testing.T) {
+	ast, p, err := compile("type A = {| +a: number, -b?: string, [k: string]: ?T |}", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	dec := ast.(*Prog).Body()[0].(*TsTypDec)
+	obj := dec.ti.TypAnnot().TsTyp().(*TsObj)
+	AssertEqual(t, true, obj.Exact(), "should be ok")
+	AssertEqual(t, false, obj.Inexact(), "should be ok")
+	AssertEqual(t, 3, len(obj.Props()), "should be ok")
+
+	a := obj.Props()[0].(*TsProp)
+	AssertEqual(t, "+", p.RngText(a.Variance()), "should be ok")
+	AssertEqual(t, "+a: number", p.RngText(a.Range()), "should be ok")
+
+	b := obj.Props()[1].(*TsProp)
+	AssertEqual(t, "-", p.RngText(b.Variance()), "should be ok")
+	AssertEqual(t, true, b.Optional(), "should be ok")
+
+	idx := obj.Props()[2].(*TsIdxSig)
+	AssertEqual(t, "k", p.RngText(idx.KeyName().Range()), "should be ok")
+	AssertEqual(t, N_TS_TYP_OP, idx.Val().(*TsTypAnnot).TsTyp().Type(), "should be ok")
+
+	ast, _, err = compile("type A = { a: T, ... }; type B = { ...A, [string]: number }", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+	obj = ast.(*Prog).Body()[0].(*TsTypDec).ti.TypAnnot().TsTyp().(*TsObj)
+	AssertEqual(t, true, obj.Inexact(), "should be ok")
+	obj = ast.(*Prog).Body()[1].(*TsTypDec).ti.TypAnnot().TsTyp().(*TsObj)
+	AssertEqual(t, N_TS_REST, obj.Props()[0].Type(), "should be ok")
+	AssertEqual(t, nil, obj.Props()[1].(*TsIdxSig).KeyName(), "should be ok")
+
+	testPass(t, "var a: {||} = {}", flowOpts())
+	testFail(t, "type A = {| a: T, ... |}", "Explicit inexact syntax cannot appear inside an explicit exact object type at (1:18)", flowOpts())
+	testFail(t, "type A = { ..., a: T }", "Explicit inexact syntax must appear at the end of an inexact object at (1:11)", flowOpts())
+	testFail(t, "type A = { +m(): void }", "Unexpected variance sigil at (1:11)", flowOpts())
+}
+
+func TestFlowFnTyp(t *testing.T) {
+	ast, _, err := compile("type F = <T: Object>(string, b?: T, ...rest: Array<T>) => void", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	fn := ast.(*Prog).Body()[0].(*TsTypDec).ti.TypAnnot().TsTyp().(*TsFnTyp)
+	AssertEqual(t, 3, len(fn.Params()), "should be ok")
+	AssertEqual(t, N_TS_STR, fn.Params()[0].Type(), "should be ok")
+	AssertEqual(t, N_NAME, fn.Params()[1].Type(), "should be ok")
+	AssertEqual(t, true, fn.Params()[1].(*Ident).ti.Optional(), "should be ok")
+	AssertEqual(t, N_PAT_REST, fn.Params()[2].Type(), "should be ok")
+
+	param := fn.TypParams().(*TsParamsDec).Params()[0].(*TsParam)
+	AssertEqual(t, N_TS_TYP_ANNOT, param.Cons().Type(), "should be ok")
+
+	ast, _, err = compile("type U = (A | B)[]; let f: ?() => void", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+	arr := ast.(*Prog).Body()[0].(*TsTypDec).ti.TypAnnot().TsTyp().(*TsArr)
+	AssertEqual(t, N_TS_PAREN, arr.Arg().Type(), "should be ok")
+}
+
+func TestFlowVariance(t *testing.T) {
+	ast, p, err := compile("class A<+T, -U> { +x: T; static -y: U = 1 }", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	cls := ast.(*Prog).Body()[0].(*ClassDec)
+	params := cls.TypParams().(*TsParamsDec).Params()
+	AssertEqual(t, "+", p.RngText(params[0].(*TsParam).Variance()), "should be ok")
+	AssertEqual(t, "-", p.RngText(params[1].(*TsParam).Variance()), "should be ok")
+
+	elems := cls.Body().(*ClassBody).Elems()
+	AssertEqual(t, "+", p.RngText(elems[0].(*Field).ti.Variance()), "should be ok")
+	AssertEqual(t, "-", p.RngText(elems[1].(*Field).ti.Variance()), "should be ok")
+
+	testFail(t, "class A { +m() {} }", "Unexpected variance sigil at (1:10)", flowOpts())
+}
+
+func TestFlowTypCast(t *testing.T) {
+	ast, _, err := compile("const a = (b: any); f((c: T))", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	init := ast.(*Prog).Body()[0].(*VarDecStmt).DecList()[0].(*VarDec).Init()
+	cast := init.(*ParenExpr).Expr().(*TsTypAssert)
+	AssertEqual(t, true, cast.Cast(), "should be ok")
+	AssertEqual(t, N_NAME, cast.Expr().Type(), "should be ok")
+
+	testPass(t, "const f = (a: T): U => a", flowOpts())
+	testFail(t, "const a = (b: any, c)", "The type cast expression is expected to be wrapped with parenthesis at (1:11)", flowOpts())
+}
+
+func TestFlowOpaque(t *testing.T) {
+	ast, _, err := compile("opaque type A: S = T; export opaque type B = number; declare opaque type C", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	dec := ast.(*Prog).Body()[0].(*TsTypDec)
+	AssertEqual(t, true, dec.Opaque(), "should be ok")
+	AssertEqual(t, N_TS_REF, dec.Super().Type(), "should be ok")
+
+	exp := ast.(*Prog).Body()[1].(*ExportDec)
+	AssertEqual(t, true, exp.Dec().(*TsTypDec).Opaque(), "should be ok")
+
+	amb := ast.(*Prog).Body()[2].(*TsDec)
+	AssertEqual(t, N_TS_DEC_TYP_DEC, amb.Type(), "should be ok")
+	AssertEqual(t, true, amb.Inner().(*TsTypDec).Opaque(), "should be ok")
+
+	testPass(t, "var opaque = 1; opaque\ntype A = B", flowOpts())
+	testFail(t, "opaque type A", "Opaque type must have an underlying type unless it's declared at (1:13)", flowOpts())
+}
+
+func TestFlowDecExport(t *testing.T) {
+	ast, _, err := compile("declare export function f(string): void; declare export default string; declare export * from 'a'", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	body := ast.(*Prog).Body()
+	dec := body[0].(*TsDec)
+	AssertEqual(t, N_TS_DEC_EXPORT, dec.Type(), "should be ok")
+	AssertEqual(t, N_TS_DEC_FN, dec.Inner().(*ExportDec).Dec().Type(), "should be ok")
+
+	exp := body[1].(*TsDec).Inner().(*ExportDec)
+	AssertEqual(t, true, exp.Default(), "should be ok")
+	AssertEqual(t, N_TS_STR, exp.Dec().Type(), "should be ok")
+
+	AssertEqual(t, true, body[2].(*TsDec).Inner().(*ExportDec).All(), "should be ok")
+}
+
+func TestFlowImportTypeof(t *testing.T) {
+	ast, _, err := compile(`import typeof A from "a"; import { typeof B, type C, D } from "b"`, flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	body := ast.(*Prog).Body()
+	AssertEqual(t, "typeof", body[0].(*ImportDec).Kind(), "should be ok")
+
+	specs := body[1].(*ImportDec).Specs()
+	AssertEqual(t, "typeof", specs[0].(*ImportSpec).Kind(), "should be ok")
+	AssertEqual(t, "type", specs[1].(*ImportSpec).Kind(), "should be ok")
+	AssertEqual(t, "value", specs[2].(*ImportSpec).Kind(), "should be ok")
+}
+
+func TestFlowGenericArrow(t *testing.T) {
+	ast, _, err := compile("const f = <T>(x: T): T => x", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	fn := ast.(*Prog).Body()[0].(*VarDecStmt).DecList()[0].(*VarDec).Init().(*ArrowFn)
+	AssertEqual(t, 1, len(fn.TypInfo().TypParams().(*TsParamsDec).Params()), "should be ok")
+
+	opts := flowOpts()
+	opts.Feature = opts.Feature.On(FEAT_JSX)
+	testPass(t, "const f = <T>(x: T): T => x; f(<T>(x) => x); <div>a</div>", opts)
+}
+
+func TestFlowItfTyp(t *testing.T) {
+	ast, _, err := compile("type A = interface extends B { m(): void }; var a: interface {}[]", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	itf := ast.(*Prog).Body()[0].(*TsTypDec).ti.TypAnnot().TsTyp().(*TsInterface)
+	AssertEqual(t, nil, itf.Id(), "should be ok")
+	AssertEqual(t, 1, len(itf.Supers()), "should be ok")
+	AssertEqual(t, 1, len(itf.Body().(*TsInterfaceBody).Body()), "should be ok")
+}
+
+func TestFlowModExps(t *testing.T) {
+	ast, _, err := compile("declare module 'm' { declare module.exports: { a: number }; }", flowOpts())
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	blk := ast.(*Prog).Body()[0].(*TsDec).Inner().(*BlockStmt)
+	dec := blk.Body()[0].(*TsDec)
+	AssertEqual(t, N_TS_DEC_MOD_EXPS, dec.Type(), "should be ok")
+	AssertEqual(t, N_TS_TYP_ANNOT, dec.Inner().Type(), "should be ok")
+
+	testPass(t, "module.exports = 1", flowOpts())
+	testFail(t, "declare module.foo: T", "Unexpected token `identifier` at (1:15)", flowOpts())
+}
+
+func TestFlowNoTsOnly(t *testing.T) {
+	testFail(t, "a = b!.c", "Unexpected token at (1:5)", flowOpts())
+	testFail(t, "namespace N {}", "Unexpected token at (1:10)", flowOpts())
+	testFail(t, "module M {}", "Unexpected token at (1:7)", flowOpts())
+	testFail(t, "abstract class A {}", "Unexpected token at (1:9)", flowOpts())
+	testFail(t, "class A { private x }", "Unexpected token `identifier` at (1:18)", flowOpts())
+	testFail(t, "class A { constructor(private x) {} }", "Unexpected token `identifier` at (1:30)", flowOpts())
+	testFail(t, "type A = keyof T", "Unexpected token at (1:15)", flowOpts())
+	testFail(t, "enum E {}", "Unexpected token `enum` at (1:0)", flowOpts())
+
+	testPass(t, "var namespace, abstract, keyof; class A { private; abstract }", flowOpts())
+}
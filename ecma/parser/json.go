@@ -0,0 +1,258 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// parses the source as a JSON document, it's used to parse the `.json` modules like the one
+// imported by `import data from "./data.json" with { type: "json" }`
+//
+// the lexer of the ECMAScript is reused so the result is the `ObjLit`-style tree: the objects
+// are `ObjLit` whose properties are `Prop` with `StrLit` keys, the arrays are `ArrLit` and the
+// negative numbers are `UnaryExpr` with operator `-`, the ranges of them are precise as the ones
+// in the normal ECMAScript programs
+//
+// the syntax beyond JSON is reported as error such as the comments, the trailing commas, the
+// single-quoted strings and the numbers in hexadecimal
+func (p *Parser) Json() (Node, error) {
+	val, err := p.jsonVal()
+	if err != nil {
+		return nil, err
+	}
+
+	tok := p.lexer.Next()
+	if tok.value != T_EOF {
+		return nil, p.jsonErrTok(tok)
+	}
+	if cmts := p.lexer.Comments(); len(cmts) > 0 {
+		return nil, p.errorAtLoc(cmts[0], ERR_JSON_COMMENT)
+	}
+
+	p.prog = val
+	return val, nil
+}
+
+func (p *Parser) jsonVal() (Node, error) {
+	tok := p.lexer.Peek()
+	switch tok.value {
+	case T_BRACE_L:
+		return p.jsonObj()
+	case T_BRACKET_L:
+		return p.jsonArr()
+	case T_STRING:
+		return p.jsonStr()
+	case T_NUM:
+		return p.jsonNum()
+	case T_SUB:
+		rng := p.lexer.Next().rng
+		num := p.lexer.Peek()
+		// the minus sign is a part of the number in JSON so they cannot be separated
+		if num.value != T_NUM || num.rng.Lo != rng.Hi {
+			return nil, p.jsonErrTok(num)
+		}
+		arg, err := p.jsonNum()
+		if err != nil {
+			return nil, err
+		}
+		return &UnaryExpr{N_EXPR_UNARY, p.finRng(rng), T_SUB, arg, span.Range{}}, nil
+	case T_NULL:
+		if tok.ContainsEscape() {
+			return nil, p.errorAt(tok.value, tok.rng, ERR_ESCAPE_IN_KEYWORD)
+		}
+		p.lexer.Next()
+		return &NullLit{N_LIT_NULL, p.finRng(tok.rng), span.Range{}, nil}, nil
+	case T_TRUE, T_FALSE:
+		if tok.ContainsEscape() {
+			return nil, p.errorAt(tok.value, tok.rng, ERR_ESCAPE_IN_KEYWORD)
+		}
+		p.lexer.Next()
+		return &BoolLit{N_LIT_BOOL, p.finRng(tok.rng), tok.value == T_TRUE, span.Range{}, nil}, nil
+	}
+	return nil, p.jsonErrTok(p.lexer.Next())
+}
+
+func (p *Parser) jsonObj() (Node, error) {
+	rng := p.lexer.Next().rng
+	props := make([]Node, 0)
+	var comma span.Range
+	for {
+		tok := p.lexer.Peek()
+		if tok.value == T_BRACE_R {
+			if len(props) > 0 {
+				return nil, p.errorAtLoc(comma, ERR_TRAILING_COMMA)
+			}
+			break
+		}
+		if tok.value != T_STRING {
+			if tok.value == T_EOF {
+				return nil, p.errorTok(tok)
+			}
+			return nil, p.errorAtLoc(tok.rng, ERR_JSON_PROP_KEY)
+		}
+
+		prop, err := p.jsonProp()
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, prop)
+
+		ahead := p.lexer.Peek()
+		if ahead.value == T_COMMA {
+			comma = p.lexer.Next().rng
+		} else if ahead.value == T_BRACE_R {
+			break
+		} else {
+			return nil, p.jsonErrTok(ahead)
+		}
+	}
+
+	if _, err := p.nextMustTok(T_BRACE_R); err != nil {
+		return nil, err
+	}
+	return &ObjLit{N_LIT_OBJ, p.finRng(rng), props, span.Range{}, p.newTypInfo(N_LIT_OBJ)}, nil
+}
+
+func (p *Parser) jsonProp() (Node, error) {
+	rng := p.rng()
+	key, err := p.jsonStr()
+	if err != nil {
+		return nil, err
+	}
+
+	colon := p.lexer.Next()
+	if colon.value != T_COLON {
+		return nil, p.jsonErrTok(colon)
+	}
+
+	val, err := p.jsonVal()
+	if err != nil {
+		return nil, err
+	}
+	return &Prop{N_PROP, p.finRng(rng), key, colon.rng, val, false, false, false, false, PK_INIT, ACC_MOD_NONE}, nil
+}
+
+func (p *Parser) jsonArr() (Node, error) {
+	rng := p.lexer.Next().rng
+	elems := make([]Node, 0)
+	var comma span.Range
+	for {
+		tok := p.lexer.Peek()
+		if tok.value == T_BRACKET_R {
+			if len(elems) > 0 {
+				return nil, p.errorAtLoc(comma, ERR_TRAILING_COMMA)
+			}
+			break
+		}
+
+		elem, err := p.jsonVal()
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, elem)
+
+		ahead := p.lexer.Peek()
+		if ahead.value == T_COMMA {
+			comma = p.lexer.Next().rng
+		} else if ahead.value == T_BRACKET_R {
+			break
+		} else {
+			return nil, p.jsonErrTok(ahead)
+		}
+	}
+
+	if _, err := p.nextMustTok(T_BRACKET_R); err != nil {
+		return nil, err
+	}
+	return &ArrLit{N_LIT_ARR, p.finRng(rng), elems, span.Range{}, p.newTypInfo(N_LIT_ARR)}, nil
+}
+
+func (p *Parser) jsonStr() (Node, error) {
+	tok := p.lexer.Next()
+	raw := p.RngText(tok.rng)
+	if raw[0] != '"' {
+		return nil, p.errorAtLoc(tok.rng, ERR_JSON_STR_QUOTE)
+	}
+
+	// only the escapes below are permitted in JSON, the control characters must be escaped:
+	// https://www.rfc-editor.org/rfc/rfc8259#section-7
+	for i := 1; i < len(raw)-1; i++ {
+		c := raw[i]
+		if c < 0x20 {
+			return nil, p.errorAtLoc(span.Range{Lo: tok.rng.Lo + uint32(i), Hi: tok.rng.Lo + uint32(i) + 1}, ERR_JSON_STR_CTRL_CHAR)
+		}
+		if c != '\\' {
+			continue
+		}
+		i++
+		switch raw[i] {
+		case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+		case 'u':
+			if i+4 < len(raw) && isHexDigits(raw[i+1:i+5]) {
+				i += 4
+				continue
+			}
+			fallthrough
+		default:
+			return nil, p.errorAtLoc(span.Range{Lo: tok.rng.Lo + uint32(i) - 1, Hi: tok.rng.Lo + uint32(i) + 1}, ERR_JSON_STR_ESCAPE)
+		}
+	}
+	return p.newStrLit(StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), false, span.Range{}, nil}), nil
+}
+
+func isHexDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// the grammar of the JSON number is the subset of the ECMAScript one:
+//
+//	int [frac] [exp]
+//
+// the leading zeros, the numeric separators, the bigint suffix and the numbers in other bases
+// are not permitted
+func (p *Parser) jsonNum() (Node, error) {
+	tok := p.lexer.Next()
+	raw := p.RngText(tok.rng)
+
+	i := 0
+	digits := func() int {
+		n := 0
+		for i < len(raw) && raw[i] >= '0' && raw[i] <= '9' {
+			i++
+			n++
+		}
+		return n
+	}
+
+	n := digits()
+	ok := n == 1 || n > 1 && raw[0] != '0'
+	if ok && i < len(raw) && raw[i] == '.' {
+		i++
+		ok = digits() > 0
+	}
+	if ok && i < len(raw) && (raw[i] == 'e' || raw[i] == 'E') {
+		i++
+		if i < len(raw) && (raw[i] == '+' || raw[i] == '-') {
+			i++
+		}
+		ok = digits() > 0
+	}
+	if !ok || i != len(raw) {
+		return nil, p.errorAtLoc(tok.rng, ERR_JSON_NUM)
+	}
+	return p.newNumLit(NumLit{N_LIT_NUM, p.finRng(tok.rng), span.Range{}}), nil
+}
+
+func (p *Parser) jsonErrTok(tok *Token) error {
+	if tok.value == T_EOF || tok.value == T_ILLEGAL {
+		return p.errorTok(tok)
+	}
+	return p.errorAtLoc(tok.rng, fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, p.RngText(tok.rng)))
+}
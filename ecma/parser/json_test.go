@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func compileJson(code string) (Node, *Parser, error) {
+	p := newParser(code, nil)
+	node, err := p.Json()
+	return node, p, err
+}
+
+func testJsonFail(t *testing.T, code, errMs string) {
+	node, _, err := compileJson(code)
+	if err == nil {
+		t.Fatalf("should not pass code:\n%s\nast:\n%v", code, node)
+	}
+	AssertEqual(t, errMs, err.Error(), "")
+}
+
+func TestJson(t *testing.T) {
+	node, p, err := compileJson(`{
+  "name": "mole",
+  "tags": ["a", -1.5e3, true, null],
+  "nested": { "a": {} }
+}`)
+	AssertEqual(t, nil, err, "should be ok")
+
+	obj := node.(*ObjLit)
+	AssertEqual(t, 3, len(obj.props), "should be ok")
+
+	name := obj.props[0].(*Prop)
+	AssertEqual(t, "name", name.key.(*StrLit).val, "should be ok")
+	AssertEqual(t, `"mole"`, p.RngText(name.value.Range()), "should be ok")
+	AssertEqual(t, `"name": "mole"`, p.RngText(name.Range()), "should be ok")
+
+	tags := obj.props[1].(*Prop).value.(*ArrLit)
+	AssertEqual(t, 4, len(tags.elems), "should be ok")
+	neg := tags.elems[1].(*UnaryExpr)
+	AssertEqual(t, T_SUB, neg.op, "should be ok")
+	AssertEqual(t, "-1.5e3", p.RngText(neg.Range()), "should be ok")
+	AssertEqual(t, N_LIT_BOOL, tags.elems[2].Type(), "should be ok")
+	AssertEqual(t, N_LIT_NULL, tags.elems[3].Type(), "should be ok")
+
+	nested := obj.props[2].(*Prop).value.(*ObjLit)
+	AssertEqual(t, "a", nested.props[0].(*Prop).key.(*StrLit).val, "should be ok")
+
+	node, _, err = compileJson(` "str" `)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, N_LIT_STR, node.Type(), "should be ok")
+}
+
+func TestJsonFail(t *testing.T) {
+	testJsonFail(t, `{ "a": 1, }`, "Unexpected trailing comma at (1:8)")
+	testJsonFail(t, `[1, 2,]`, "Unexpected trailing comma at (1:5)")
+	testJsonFail(t, `{ a: 1 }`, "Property keys must be double-quoted strings in JSON at (1:2)")
+	testJsonFail(t, `['a']`, "Strings must be double-quoted in JSON at (1:1)")
+	testJsonFail(t, `["\x61"]`, "Invalid escape sequence in JSON string at (1:2)")
+	testJsonFail(t, "[\"a\tb\"]", "Control characters must be escaped in JSON strings at (1:3)")
+	testJsonFail(t, `[0x10]`, "Invalid number in JSON at (1:1)")
+	testJsonFail(t, `[01]`, "Invalid number in JSON at (1:1)")
+	testJsonFail(t, `[.5]`, "Invalid number in JSON at (1:1)")
+	testJsonFail(t, `[1_000]`, "Invalid number in JSON at (1:1)")
+	testJsonFail(t, `[- 1]`, "Unexpected token `1` at (1:3)")
+	testJsonFail(t, `[NaN]`, "Unexpected token `NaN` at (1:1)")
+	testJsonFail(t, `{} {}`, "Unexpected token `{` at (1:3)")
+	testJsonFail(t, `{ "a": 1 } // c`, "Comments are not allowed in JSON at (1:11)")
+	testJsonFail(t, `{ "a" 1 }`, "Unexpected token `1` at (1:6)")
+}
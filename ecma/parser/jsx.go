@@ -126,7 +126,7 @@ func (p *Parser) jsxAttr() (Node, error) {
 	} else if av == T_STRING {
 		tok := p.lexer.Next()
 		rng := tok.rng
-		val = &StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil}
+		val = p.newStrLit(StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
 	} else if av == T_LT {
 		val, err = p.jsx(true, false)
 		if err != nil {
@@ -269,6 +269,11 @@ func (p *Parser) jsxWsTxt() Node {
 // `opening` indicates the opening of the tag has presented, so the
 // closing tag is deserved to be appearing
 func (p *Parser) jsx(root bool, opening bool) (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	tok := p.lexer.Next() // `<`
 
 	p.lexer.PushMode(LM_JSX, true)
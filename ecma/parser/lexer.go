@@ -2,7 +2,9 @@ package parser
 
 import (
 	"container/list"
+	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"unicode"
 	"unicode/utf8"
@@ -124,6 +126,14 @@ type LexerState struct {
 	// always save loc of the previous whitespace being skipped
 	// by `skipSpace` in jsx mode
 	prevWs Token
+
+	// the tokens consumed by `Next` if `keepToks` is turned on, they are kept in the state so
+	// the tokens read after the state is pushed are dropped when the lexer is rewound
+	toks []Token
+
+	// the count of the lexed tokens, it's kept in the state so the tokens lexed again after the
+	// lexer is rewound are not recounted
+	ntoks int
 }
 
 func newLexerState() LexerState {
@@ -144,6 +154,11 @@ type Lexer struct {
 	stmtCmts []span.Range
 	// cmtGrp []int
 
+	// all the comments in the source, the lexer may revisit the comments when it's
+	// rewound by `PopState` so `cmtSeen` is used to dedupe them
+	cmts    []span.Range
+	cmtSeen map[uint32]bool
+
 	state LexerState
 	ss    []LexerState // state stack
 
@@ -157,6 +172,31 @@ type Lexer struct {
 	// try to parse as LSH one more time
 	maybeLshPos map[uint32]bool
 	lshPos      map[uint32]bool
+
+	keepToks bool
+
+	// decides whether the slash is the beginning of regexp instead of the previous token if it's
+	// not nil, it's used by `Tokenize` since there is no parser to tell the lexer the context
+	isRegexp func() bool
+
+	// the limit of the token count and the context for the cancellation, see `ParserOpts`,
+	// `ticks` throttles the checking of the context
+	maxToks int
+	ctx     context.Context
+	ticks   int
+
+	// the sticky reason of the aborted parsing, all the tokens lexed after it's set are the
+	// error tokens of it
+	abort *lexAbort
+
+	// the scratch buffer of `readIdPart`
+	idBuf []byte
+}
+
+type lexAbort struct {
+	msg   string
+	ofst  uint32
+	cause error
 }
 
 func NewLexer(src *span.Source) *Lexer {
@@ -164,6 +204,8 @@ func NewLexer(src *span.Source) *Lexer {
 		src:         src,
 		exprCmts:    make([]span.Range, 0, 20),
 		stmtCmts:    make([]span.Range, 0, 20),
+		cmts:        make([]span.Range, 0),
+		cmtSeen:     map[uint32]bool{},
 		state:       newLexerState(),
 		ss:          make([]LexerState, 0),
 		maybeLshPos: map[uint32]bool{},
@@ -172,11 +214,30 @@ func NewLexer(src *span.Source) *Lexer {
 	return lexer
 }
 
+// empties the lexer to lex another source, its slices and maps are kept for the reuse
+func (l *Lexer) reset(src *span.Source) {
+	state := newLexerState()
+	state.toks = emptySlice(l.state.toks)
+	*l = Lexer{
+		src:         src,
+		exprCmts:    emptySlice(l.exprCmts),
+		stmtCmts:    emptySlice(l.stmtCmts),
+		cmts:        emptySlice(l.cmts),
+		cmtSeen:     emptyMap(l.cmtSeen),
+		state:       state,
+		ss:          emptySlice(l.ss),
+		maybeLshPos: emptyMap(l.maybeLshPos),
+		lshPos:      emptyMap(l.lshPos),
+		idBuf:       l.idBuf,
+	}
+}
+
 // read a token, named `next` to indicate it will move the cursor
 func (l *Lexer) Next() *Token {
 	tok := l.readTok()
 	l.state.prtVal = tok.value
 	l.state.prtRng = tok.rng
+	l.keepTok(tok)
 	return tok
 }
 
@@ -189,9 +250,22 @@ func (l *Lexer) NextRevise(v TokenValue) *Token {
 	tok := l.readTok()
 	l.state.prtVal = v
 	l.state.prtRng = tok.rng
+	l.keepTok(tok)
 	return tok
 }
 
+func (l *Lexer) keepTok(tok *Token) {
+	if l.keepToks && tok.value != T_EOF {
+		l.state.toks = append(l.state.toks, *tok)
+	}
+}
+
+// the tokens consumed by the parser in the order of their positions, they are kept only if
+// `ParserOpts.Tokens` is turned on
+func (l *Lexer) Tokens() []Token {
+	return l.state.toks
+}
+
 func (l *Lexer) Peek() *Token {
 	if !l.state.tb.readable() {
 		return l.advance()
@@ -226,6 +300,19 @@ func (l *Lexer) Peek2nd() *Token {
 	return l.finToken(l.newToken(), T_EOF)
 }
 
+// guard the peeked buffer has at least 3 tokens, return
+// the 3rd if the guarding is succeeded otherwise return
+// the `EOF_TOK`
+func (l *Lexer) Peek3rd() *Token {
+	for i := l.state.tb.len; i < 3; i++ {
+		l.advance()
+	}
+	if l.state.tb.len >= 3 {
+		return &l.state.tb.buf[(l.state.tb.r+2)%TOKENS_BUF_LEN]
+	}
+	return l.finToken(l.newToken(), T_EOF)
+}
+
 func (l *Lexer) Rng() span.Range {
 	rng := span.Range{}
 	if l.state.tb.readable() {
@@ -233,7 +320,7 @@ func (l *Lexer) Rng() span.Range {
 		rng.Lo = tok.rng.Lo
 		rng.Hi = rng.Lo
 	} else {
-		rng.Lo = l.src.Pos()
+		rng.Lo = l.src.Ofst()
 		rng.Hi = rng.Lo
 	}
 	return rng
@@ -243,7 +330,7 @@ func (l *Lexer) FinRng(rng span.Range) span.Range {
 	if l.state.prtVal != T_ILLEGAL {
 		rng.Hi = l.state.prtRng.Hi
 	} else {
-		rng.Hi = l.src.Pos()
+		rng.Hi = l.src.Ofst()
 	}
 	return rng
 }
@@ -344,6 +431,8 @@ func (l *Lexer) readTokWithComment() *Token {
 			return l.readStr()
 		} else if l.aheadIsTplStart() {
 			return l.readTplSpan()
+		} else if l.aheadIsHashbang() {
+			return l.readHashbang()
 		} else if l.aheadIsPvt() {
 			return l.readNamePvt()
 		}
@@ -379,6 +468,8 @@ func (l *Lexer) readTokWithComment() *Token {
 			l.src.Read()
 			if l.src.AheadIsCh('/') {
 				return l.readSinglelineComment(tok)
+			} else if l.src.AheadIsCh('*') {
+				return l.readMultilineComment(tok)
 			}
 			return l.finToken(tok, T_DIV)
 		case '>':
@@ -406,6 +497,9 @@ func (l *Lexer) readTokWithComment() *Token {
 }
 
 func (l *Lexer) lexTok() *Token {
+	if l.abort != nil {
+		return l.errTokOfst(nil, l.abort.msg, l.abort.ofst)
+	}
 	prt := T_ILLEGAL
 	prtAtm := false
 	prtExt := false
@@ -415,7 +509,11 @@ func (l *Lexer) lexTok() *Token {
 			if !tok.afterLineTerm && prt == T_COMMENT && prtExt == true {
 				tok.afterLineTerm = true
 			}
-			return tok
+			return l.count(tok)
+		}
+		if !l.cmtSeen[tok.rng.Lo] {
+			l.cmtSeen[tok.rng.Lo] = true
+			l.cmts = append(l.cmts, tok.rng)
 		}
 		if tok.afterLineTerm || (prt == T_COMMENT && prtAtm) || tok.rng.Lo == 0 {
 			l.stmtCmts = append(l.stmtCmts, tok.rng)
@@ -428,6 +526,14 @@ func (l *Lexer) lexTok() *Token {
 	}
 }
 
+// the ranges of all the comments met by the lexer in the order of their positions
+func (l *Lexer) Comments() []span.Range {
+	sort.Slice(l.cmts, func(i, j int) bool {
+		return l.cmts[i].Lo < l.cmts[j].Lo
+	})
+	return l.cmts
+}
+
 func (l *Lexer) takeExprCmts() []span.Range {
 	if len(l.exprCmts) == 0 {
 		return nil
@@ -448,6 +554,48 @@ func (l *Lexer) takeStmtCmts() []span.Range {
 	return cmts
 }
 
+// counts the lexed token and checks the limits, the error token is returned instead if the
+// parsing is aborted
+func (l *Lexer) count(tok *Token) *Token {
+	if tok.value == T_EOF {
+		return tok
+	}
+	l.state.ntoks++
+	if l.maxToks > 0 && l.state.ntoks > l.maxToks {
+		l.abortAt(fmt.Sprintf(ERR_TPL_TOO_MANY_TOKENS, l.maxToks), tok.rng.Lo, nil)
+	} else {
+		l.tick(tok.rng.Lo)
+	}
+	if l.abort != nil {
+		return l.errTokOfst(nil, l.abort.msg, l.abort.ofst)
+	}
+	return tok
+}
+
+// checks the context every 1024 ticks since `ctx.Err` may acquire the lock
+func (l *Lexer) tick(ofst uint32) {
+	l.ticks++
+	if l.ticks&1023 == 0 {
+		l.checkCtx(ofst)
+	}
+}
+
+func (l *Lexer) checkCtx(ofst uint32) {
+	if l.ctx == nil {
+		return
+	}
+	if err := l.ctx.Err(); err != nil {
+		l.abortAt(fmt.Sprintf(ERR_TPL_PARSING_ABORTED, err), ofst, err)
+	}
+}
+
+// aborts the parsing, only the first reason is kept
+func (l *Lexer) abortAt(msg string, ofst uint32, cause error) {
+	if l.abort == nil {
+		l.abort = &lexAbort{msg, ofst, cause}
+	}
+}
+
 func (l *Lexer) advance() *Token {
 	tok := l.lexTok()
 
@@ -622,7 +770,7 @@ func (l *Lexer) readName(jsx bool) *Token {
 	}
 
 	containsEscape := escapeInStart || escapeInPart
-	tok.ext = &TokExtIdent{containsEscape}
+	tok.ext = &TokExtIdent{containsEscape, jsx}
 
 	tok.rng.Hi = l.src.Ofst()
 	if containsEscape {
@@ -659,6 +807,10 @@ func (l *Lexer) aheadIsRegexp() bool {
 		return false
 	}
 
+	if l.isRegexp != nil {
+		return l.isRegexp()
+	}
+
 	if l.state.beginStmt {
 		return true
 	}
@@ -849,7 +1001,7 @@ func (l *Lexer) readSymbol() *Token {
 				val = T_POW
 			}
 			if l.feat&FEAT_POW == 0 {
-				return l.errTokMsg(tok, ERR_UNEXPECTED_TOKEN)
+				return l.errTokMsg(tok, l.featErr(FEAT_POW, ERR_UNEXPECTED_TOKEN))
 			}
 		} else if l.src.AheadIsCh('=') {
 			l.src.Read()
@@ -935,13 +1087,13 @@ func (l *Lexer) readSymbol() *Token {
 	}
 
 	if val == T_DOT_TRI && (l.feat&FEAT_SPREAD == 0 || l.feat&FEAT_BINDING_REST_ELEM == 0) {
-		return l.errTokMsg(tok, ERR_UNEXPECTED_TOKEN)
+		return l.errTokMsg(tok, l.featErr(FEAT_SPREAD|FEAT_BINDING_REST_ELEM, ERR_UNEXPECTED_TOKEN))
 	} else if val == T_OPT_CHAIN && l.feat&FEAT_OPT_EXPR == 0 {
-		return l.errTokMsg(tok, ERR_UNEXPECTED_TOKEN)
+		return l.errTokMsg(tok, l.featErr(FEAT_OPT_EXPR, ERR_UNEXPECTED_TOKEN))
 	} else if val == T_NULLISH && l.feat&FEAT_NULLISH == 0 {
-		return l.errTokMsg(tok, ERR_UNEXPECTED_TOKEN)
+		return l.errTokMsg(tok, l.featErr(FEAT_NULLISH, ERR_UNEXPECTED_TOKEN))
 	} else if (val == T_ASSIGN_NULLISH || val == T_ASSIGN_AND || val == T_ASSIGN_OR) && l.feat&FEAT_LOGIC_ASSIGN == 0 {
-		return l.errTokMsg(tok, ERR_UNEXPECTED_TOKEN)
+		return l.errTokMsg(tok, l.featErr(FEAT_LOGIC_ASSIGN, ERR_UNEXPECTED_TOKEN))
 	}
 
 	return l.finToken(tok, val)
@@ -968,6 +1120,16 @@ func (l *Lexer) readMultilineComment(tok *Token) *Token {
 	return tok
 }
 
+// the hashbang `#!` at the start of the file is consumed as a single-line comment
+func (l *Lexer) readHashbang() *Token {
+	tok := l.newToken()
+	if l.feat&FEAT_HASHBANG == 0 {
+		return l.errTokMsg(tok, l.featErr(FEAT_HASHBANG, ERR_UNEXPECTED_CHAR))
+	}
+	l.src.Read() // consume `#`
+	return l.readSinglelineComment(tok)
+}
+
 func (l *Lexer) readSinglelineComment(tok *Token) *Token {
 	l.src.Read() // consume `/`
 	for {
@@ -1062,10 +1224,19 @@ func (l *Lexer) readRegexp(tok *Token) *Token {
 		i = len(fs)
 	}
 
+	// the flags of the later versions are reported even if `FEAT_CHK_REGEXP_FLAGS` is off
+	for _, f := range fs {
+		if feat, ok := regexpFlagFeats[rune(f)]; ok && l.feat&feat == 0 {
+			if msg := l.featErr(feat, ""); msg != "" {
+				return l.errTokMsg(tok, msg)
+			}
+		}
+	}
+
 	if l.feat&FEAT_CHK_REGEXP_FLAGS != 0 {
 		for _, f := range fs {
 			if !l.isLegalFlag(rune(f)) {
-				return l.errTokMsg(tok, ERR_INVALID_REGEXP_FLAG)
+				return l.errTokMsg(tok, l.featErr(regexpFlagFeats[rune(f)], ERR_INVALID_REGEXP_FLAG))
 			}
 		}
 	}
@@ -1078,20 +1249,22 @@ func (l *Lexer) readRegexp(tok *Token) *Token {
 	return l.finToken(tok, T_REGEXP)
 }
 
+// the features of the regexp flags which are not available in all the versions
+var regexpFlagFeats = map[rune]Feature{
+	'd': FEAT_REGEXP_HAS_INDICES,
+	'v': FEAT_REGEXP_UNICODE_SETS,
+	'u': FEAT_REGEXP_UNICODE,
+	'y': FEAT_REGEXP_STICKY,
+	's': FEAT_REGEXP_DOT_ALL,
+}
+
 func (l *Lexer) isLegalFlag(f rune) bool {
 	switch f {
 	case 'g', 'i', 'm':
 		return true
-	case 'd':
-		return l.feat&FEAT_REGEXP_HAS_INDICES != 0
-	case 'u':
-		return l.feat&FEAT_REGEXP_UNICODE != 0
-	case 'y':
-		return l.feat&FEAT_REGEXP_STICKY != 0
-	case 's':
-		return l.feat&FEAT_REGEXP_DOT_ALL != 0
 	}
-	return false
+	feat, ok := regexpFlagFeats[f]
+	return ok && l.feat&feat != 0
 }
 
 func (l *Lexer) IsLineTerminator(c rune) bool {
@@ -1294,7 +1467,7 @@ func (l *Lexer) readNamePvt() *Token {
 	tok.value = T_NAME_PVT
 	if l.feat&FEAT_CLASS_PRV == 0 {
 		tok.value = T_ILLEGAL
-		return l.errTokOfst(tok, ERR_UNEXPECTED_CHAR, ofst)
+		return l.errTokOfst(tok, l.featErr(FEAT_CLASS_PRV, ERR_UNEXPECTED_CHAR), ofst)
 	}
 	return tok
 }
@@ -1331,10 +1504,11 @@ func (l *Lexer) readNum() *Token {
 func (l *Lexer) readDecimalNum(tok *Token, first rune) *Token {
 	if first != '.' && first != '0' {
 		c := l.src.Peek()
-		if c != 'e' && c != 'E' && c != 'n' && IsIdStart(c) {
+		if c != 'e' && c != 'E' && c != 'n' && c != '_' && IsIdStart(c) {
 			return l.errTokOfst(nil, ERR_IDENT_AFTER_NUMBER, l.src.Ofst())
 		}
-		if err := l.readDecimalDigits(true); err != "" {
+		// the separator is permitted right after the first digit which is already consumed
+		if err := l.readDecimalDigits(true, 1); err != "" {
 			if err == ERR_NUM_SEP_END {
 				tok := l.newToken()
 				return l.errTokOfst(tok, err, l.src.Ofst()-1)
@@ -1350,7 +1524,7 @@ func (l *Lexer) readDecimalNum(tok *Token, first rune) *Token {
 			float = true
 		}
 		// read the fraction part
-		if err := l.readDecimalDigits(true); err != "" {
+		if err := l.readDecimalDigits(true, 0); err != "" {
 			if err == ERR_NUM_SEP_END {
 				tok := l.newToken()
 				return l.errTokMsg(tok, err)
@@ -1369,7 +1543,7 @@ func (l *Lexer) readDecimalNum(tok *Token, first rune) *Token {
 
 	if first != '.' && !float && !exp {
 		if tok := l.bigintSuffix(); tok != nil {
-			return l.errTokMsg(tok, ERR_IDENT_AFTER_NUMBER)
+			return l.errTokMsg(tok, l.featErr(FEAT_BIGINT, ERR_IDENT_AFTER_NUMBER))
 		}
 	}
 	if IsIdStart(l.src.Peek()) {
@@ -1392,18 +1566,17 @@ func (l *Lexer) readExpPart() string {
 	if l.src.AheadIsChOr('+', '-') {
 		l.src.Read()
 	}
-	return l.readDecimalDigits(false)
+	return l.readDecimalDigits(false, 0)
 }
 
-func (l *Lexer) readDecimalDigits(opt bool) string {
-	i := 0
-
+// `i` is the count of the digits have been consumed by the caller
+func (l *Lexer) readDecimalDigits(opt bool, i int) string {
 	var last rune
 	for {
 		c := l.src.Peek()
 		if IsDecimalDigit(c) || c == '_' {
 			if c == '_' && l.feat&FEAT_NUM_SEP == 0 {
-				return ERR_INVALID_NUMBER
+				return l.featErr(FEAT_NUM_SEP, ERR_INVALID_NUMBER)
 			}
 			if i == 0 && c == '_' {
 				return ERR_NUM_SEP_BEGIN
@@ -1440,7 +1613,7 @@ func (l *Lexer) readBinaryNum(tok *Token) *Token {
 		if c == '0' || c == '1' || c == '_' {
 			if c == '_' {
 				if l.feat&FEAT_NUM_SEP == 0 {
-					return l.errTokMsg(nil, ERR_IDENT_AFTER_NUMBER)
+					return l.errTokMsg(nil, l.featErr(FEAT_NUM_SEP, ERR_IDENT_AFTER_NUMBER))
 				}
 				if i == 0 {
 					return l.errTokMsg(nil, ERR_NUM_SEP_BEGIN)
@@ -1469,7 +1642,7 @@ func (l *Lexer) readBinaryNum(tok *Token) *Token {
 	}
 
 	if tok := l.bigintSuffix(); tok != nil {
-		return l.errTokMsg(tok, ERR_IDENT_AFTER_NUMBER)
+		return l.errTokMsg(tok, l.featErr(FEAT_BIGINT, ERR_IDENT_AFTER_NUMBER))
 	}
 	if IsIdStart(l.src.Peek()) {
 		return l.errTokMsg(nil, ERR_IDENT_AFTER_NUMBER)
@@ -1486,7 +1659,7 @@ func (l *Lexer) readOctalNum(tok *Token, i int) *Token {
 		if c >= '0' && c <= '7' || c == '_' {
 			if c == '_' {
 				if l.feat&FEAT_NUM_SEP == 0 {
-					return l.errTokMsg(nil, ERR_IDENT_AFTER_NUMBER)
+					return l.errTokMsg(nil, l.featErr(FEAT_NUM_SEP, ERR_IDENT_AFTER_NUMBER))
 				}
 				if i == 0 {
 					return l.errTokMsg(nil, ERR_NUM_SEP_BEGIN)
@@ -1513,7 +1686,7 @@ func (l *Lexer) readOctalNum(tok *Token, i int) *Token {
 
 	if !legacy {
 		if tok := l.bigintSuffix(); tok != nil {
-			return l.errTokMsg(tok, ERR_IDENT_AFTER_NUMBER)
+			return l.errTokMsg(tok, l.featErr(FEAT_BIGINT, ERR_IDENT_AFTER_NUMBER))
 		}
 	}
 	if IsIdStart(l.src.Peek()) {
@@ -1531,7 +1704,7 @@ func (l *Lexer) readHexNum(tok *Token) *Token {
 		if IsHexDigit(c) || c == '_' {
 			if c == '_' {
 				if l.feat&FEAT_NUM_SEP == 0 {
-					return l.errTokMsg(nil, ERR_IDENT_AFTER_NUMBER)
+					return l.errTokMsg(nil, l.featErr(FEAT_NUM_SEP, ERR_IDENT_AFTER_NUMBER))
 				}
 				if i == 0 {
 					return l.errTokMsg(nil, ERR_NUM_SEP_BEGIN)
@@ -1555,7 +1728,7 @@ func (l *Lexer) readHexNum(tok *Token) *Token {
 	}
 
 	if tok := l.bigintSuffix(); tok != nil {
-		return l.errTokMsg(tok, ERR_IDENT_AFTER_NUMBER)
+		return l.errTokMsg(tok, l.featErr(FEAT_BIGINT, ERR_IDENT_AFTER_NUMBER))
 	}
 	if IsIdStart(l.src.Peek()) {
 		return l.errTokMsg(nil, ERR_IDENT_AFTER_NUMBER)
@@ -1573,8 +1746,9 @@ func (l *Lexer) readIdStart() (rune, bool, *FixedOfstErr) {
 	return l.readUnicodeEscape(c, true)
 }
 
+// the returned bytes are only valid before the next call since they are in the scratch buffer
 func (l *Lexer) readIdPart(jsx bool) (rs []byte, containsEscape bool, err *FixedOfstErr) {
-	rs = make([]byte, 0, 10)
+	rs = l.idBuf[:0]
 	for {
 		c := l.src.Peek()
 		if IsIdStart(c) || IsIdPart(c) || (jsx && c == '-') {
@@ -1594,6 +1768,7 @@ func (l *Lexer) readIdPart(jsx bool) (rs []byte, containsEscape bool, err *Fixed
 			break
 		}
 	}
+	l.idBuf = rs
 	return rs, containsEscape, nil
 }
 
@@ -1727,6 +1902,10 @@ func (l *Lexer) aheadIsIdStart() bool {
 	return IsIdStart(l.src.Peek())
 }
 
+func (l *Lexer) aheadIsHashbang() bool {
+	return l.src.Ofst() == 0 && l.src.AheadIsChs2('#', '!')
+}
+
 func (l *Lexer) aheadIsPvt() bool {
 	return l.src.AheadIsCh('#')
 }
@@ -1759,6 +1938,7 @@ func (l *Lexer) newToken() *Token {
 	tok.text = ""
 	l.src.OpenRange(&tok.rng)
 	tok.txt = span.Range{}
+	tok.ext = nil
 	tok.len = l.src.Pos()
 	return tok
 }
@@ -1771,6 +1951,15 @@ func (l *Lexer) finToken(tok *Token, value TokenValue) *Token {
 	return tok
 }
 
+// the message of the error raised by the syntax of the turned off feature, it's the version
+// requirement if the feature is turned off by the target version, otherwise `msg` is returned
+func (l *Lexer) featErr(feat Feature, msg string) string {
+	if l.ver != 0 && feat.Version() > l.ver {
+		return fmt.Sprintf(ERR_TPL_REQUIRES_VERSION, feat.Syntax(), feat.Version())
+	}
+	return msg
+}
+
 func (l *Lexer) errTokOfst(tok *Token, msg string, ofst uint32) *Token {
 	if tok == nil {
 		tok = l.newToken()
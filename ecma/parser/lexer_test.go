@@ -98,6 +98,19 @@ func TestReadNum(t *testing.T) {
 	AssertEqual(t, "0x0_1", TokText(tok, s), "should be 0x0_1")
 }
 
+func TestReadNumSep(t *testing.T) {
+	s := span.NewSource("", "1_000 12_3.4_5e6_7")
+	l := NewLexer(s)
+	l.feat = FEAT_NUM_SEP
+	tok := l.Next()
+	AssertEqual(t, true, tok.IsLegal(), "should be ok 1_000")
+	AssertEqual(t, "1_000", TokText(tok, s), "should be 1_000")
+
+	tok = l.Next()
+	AssertEqual(t, true, tok.IsLegal(), "should be ok 12_3.4_5e6_7")
+	AssertEqual(t, "12_3.4_5e6_7", TokText(tok, s), "should be 12_3.4_5e6_7")
+}
+
 func TestReadStr(t *testing.T) {
 	s := span.NewSource("", `
   'h'
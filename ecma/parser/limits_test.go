@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func TestLimitSize(t *testing.T) {
+	opts := NewParserOpts()
+	opts.MaxSize = 8
+	_, _, err := compile("let a = 1", opts)
+	AssertEqual(t, "Source exceeds the maximum size of 8 bytes at (1:0)", err.Error(), "should be failed")
+
+	_, _, err = compile("let a=1", opts)
+	AssertEqual(t, nil, err, "should be ok")
+}
+
+func TestLimitDepth(t *testing.T) {
+	opts := NewParserOpts()
+	opts.MaxDepth = 8
+	testFail(t, "a = [[[[[[[[1]]]]]]]]", "Nesting exceeds the maximum depth of 8 at (1:9)", opts)
+	testFail(t, "a = ((((((((1))))))))", "Nesting exceeds the maximum depth of 8 at (1:9)", opts)
+	testFail(t, "{{{{{{{{{}}}}}}}}}", "Nesting exceeds the maximum depth of 8 at (1:7)", opts)
+	testFail(t, "a = !!!!!!!!b", "Nesting exceeds the maximum depth of 8 at (1:9)", opts)
+	testFail(t, "let [[[[[[[[a]]]]]]]] = b", "Nesting exceeds the maximum depth of 8 at (1:11)", opts)
+
+	_, _, err := compile("a = [[[[[1]]]]]", opts)
+	AssertEqual(t, nil, err, "should be ok")
+
+	// the stack is not overflowed by the deep nesting
+	opts.MaxDepth = 1000
+	_, _, err = compile(strings.Repeat("[", 1000000), opts)
+	AssertEqual(t, "Nesting exceeds the maximum depth of 1000 at (1:998)", err.Error(), "should be failed")
+
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+	opts.MaxDepth = 8
+	testFail(t, "let a: A<A<A<A<A<A<A<A<A>>>>>>>>", "Nesting exceeds the maximum depth of 8 at (1:20)", opts)
+	testFail(t, "<a><a><a><a><a><a><a><a></a></a></a></a></a></a></a></a>", "Nesting exceeds the maximum depth of 8 at (1:17)", opts)
+}
+
+func TestLimitTokens(t *testing.T) {
+	opts := NewParserOpts()
+	opts.MaxTokens = 4
+	testFail(t, "a + b /* c */ + c", "Source exceeds the maximum count of 4 tokens at (1:16)", opts)
+
+	_, _, err := compile("a + b // c", opts)
+	AssertEqual(t, nil, err, "should be ok")
+
+	// the tokens lexed again after the lexer is rewound are not recounted
+	opts.MaxTokens = 9
+	_, _, err = compile("(a, b) => a", opts)
+	AssertEqual(t, nil, err, "should be ok")
+}
+
+func TestLimitCanceled(t *testing.T) {
+	opts := NewParserOpts()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts.Ctx = ctx
+	_, _, err := compile("a", opts)
+	AssertEqual(t, "Parsing is aborted: context canceled at (1:0)", err.Error(), "should be failed")
+	AssertEqual(t, true, errors.Is(err, context.Canceled), "should unwrap to the error of the context")
+}
+
+// the context which is done after its `Err` is called for `n` times
+type doneAfter struct {
+	context.Context
+	n int
+}
+
+func (c *doneAfter) Err() error {
+	if c.n == 0 {
+		return context.DeadlineExceeded
+	}
+	c.n--
+	return nil
+}
+
+func TestLimitCanceledPeriodically(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Ctx = &doneAfter{context.Background(), 2}
+	_, _, err := compile(strings.Repeat("a;\n", 2000), opts)
+	AssertEqual(t, "Parsing is aborted: context deadline exceeded at (511:1)", err.Error(), "should be failed")
+	AssertEqual(t, true, errors.Is(err, context.DeadlineExceeded), "should unwrap to the error of the context")
+}
+
+func TestLimitReparse(t *testing.T) {
+	opts := reparseOpts(false)
+	opts.MaxSize = 16
+	p := NewParser(span.NewSource("", "let a = 1\na"), opts)
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	ast, err = p.Reparse(ast, []Edit{{span.Range{Lo: 10, Hi: 11}, "a + 1"}})
+	AssertEqual(t, nil, err, "should be ok")
+
+	_, err = p.Reparse(ast, []Edit{{span.Range{Lo: 10, Hi: 15}, "a + a + a"}})
+	AssertEqual(t, "Source exceeds the maximum size of 16 bytes at (1:0)", err.Error(), "should be failed")
+}
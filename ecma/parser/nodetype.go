@@ -84,6 +84,7 @@ const (
 	N_SUPER        // #[visitor(Super)]
 	N_IMPORT_SPEC  // #[visitor(ImportSpec)]
 	N_EXPORT_SPEC  // #[visitor(ExportSpec)]
+	N_IMPORT_ATTR  // #[visitor(ImportAttr)]
 
 	N_JSX_BEGIN
 	N_JSX_ID           // #[visitor(JsxIdent)]
@@ -163,6 +164,8 @@ const (
 	N_TS_DEC_MODULE    // #[visitor(TsDec)]
 	N_TS_DEC_GLOBAL    // #[visitor(TsDec)]
 	N_TS_DEC_INTERFACE // #[visitor(TsDec)]
+	N_TS_DEC_EXPORT    // #[visitor(TsDec)]
+	N_TS_DEC_MOD_EXPS  // #[visitor(TsDec)]
 	N_TS_DEC_TYP_DEC   // #[visitor(TsDec)]
 
 	N_TS_TYP_PREDICATE // #[visitor(TsTypPredicate)]
@@ -29,6 +29,7 @@ func init() {
 	nodetypeStrings[N_EXPR_UPDATE] = "UpdateExpr"
 	nodetypeStrings[N_EXPR_YIELD] = "YieldExpr"
 	nodetypeStrings[N_FIELD] = "Field"
+	nodetypeStrings[N_IMPORT_ATTR] = "ImportAttr"
 	nodetypeStrings[N_IMPORT_CALL] = "ImportCall"
 	nodetypeStrings[N_IMPORT_SPEC] = "ImportSpec"
 	nodetypeStrings[N_JSX_ATTR] = "JsxAttr"
@@ -92,10 +93,12 @@ func init() {
 	nodetypeStrings[N_TS_COND] = "TsCondType"
 	nodetypeStrings[N_TS_DEC_CLASS] = "TsDec"
 	nodetypeStrings[N_TS_DEC_ENUM] = "TsDec"
+	nodetypeStrings[N_TS_DEC_EXPORT] = "TsDec"
 	nodetypeStrings[N_TS_DEC_FN] = "TsDec"
 	nodetypeStrings[N_TS_DEC_GLOBAL] = "TsDec"
 	nodetypeStrings[N_TS_DEC_INTERFACE] = "TsDec"
 	nodetypeStrings[N_TS_DEC_MODULE] = "TsDec"
+	nodetypeStrings[N_TS_DEC_MOD_EXPS] = "TsDec"
 	nodetypeStrings[N_TS_DEC_NS] = "TsDec"
 	nodetypeStrings[N_TS_DEC_TYP_DEC] = "TsDec"
 	nodetypeStrings[N_TS_DEC_VAR_DEC] = "TsDec"
@@ -1,9 +1,12 @@
 package parser
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/hsiaosiyuan0/mole/ecma/regex"
 	span "github.com/hsiaosiyuan0/mole/span"
 )
 
@@ -11,7 +14,7 @@ import (
 //
 // it supports below syntaxes out-of-box by setting the `ParserOpts.Feature`:
 //
-// - ecmascript up to 2020
+// - ecmascript up to 2024
 // - jsx
 // - typescript
 //
@@ -24,8 +27,9 @@ type Parser struct {
 	checkName       bool
 	danglingPvtRefs []*Ref
 
-	ts  bool
-	dts bool
+	ts   bool
+	dts  bool
+	flow bool
 
 	// the ts func sig cannot stand alone:
 	// `function f(a:number)` is illegal unless it's followed by a
@@ -58,14 +62,59 @@ type Parser struct {
 	// node => comments
 	prevCmts map[Node][]span.Range
 	postCmts map[Node][]span.Range
+	nodeCmts *NodeComments
 
 	errTypArgMissingGT ErrTypArgMissingGT
+
+	// the detection and the options of the auto mode, the code is reparsed with the alternatives
+	// of the guessed features if it fails to be parsed
+	det      *Detection
+	autoOpts *ParserOpts
+
+	// the marks of the top-level statements and the context of the reparse, see `Reparse`
+	marks  []stmtMark
+	reused *reparseCtx
+
+	// the limits of the source size and the nesting depth, see `ParserOpts`, `depth` is the
+	// current depth of the nested syntax
+	maxSize  int
+	maxDepth int
+	depth    int
+
+	// the frequent nodes, the scopes and the refs are allocated from it if it's not `nil`
+	arena *Arena
 }
 
 type ParserOpts struct {
 	Externals []string
-	Version   ESVersion
-	Feature   Feature
+	// the target version, the features not supported by it are turned off unless `FEAT_TS`
+	// is on, the version is not restricted if it's `0`
+	Version ESVersion
+	Feature Feature
+	// detects the source type and the dialect of the code by `Detect` with the path of the source
+	// as the filename, the detected features are applied on `Feature`
+	Auto bool
+	// keeps the tokens consumed by the parser, they are available by `Parser.Tokens` and the
+	// `tokens` of the estree output
+	Tokens bool
+
+	// the limits for parsing the untrusted sources, each of them is not applied if it's `0`, the
+	// parsing fails with the distinct error once any of them is exceeded:
+	// - `MaxSize` is the max bytes of the source
+	// - `MaxDepth` is the max depth of the nested syntax like the arrays, the parenthesized
+	//   expressions and the blocks, it keeps the recursive descent from overflowing the stack
+	// - `MaxTokens` is the max count of the tokens, only the tokens of the reparsed statements
+	//   are counted by `Reparse`
+	MaxSize   int
+	MaxDepth  int
+	MaxTokens int
+	// the parsing is aborted once the context is done, it's checked periodically while the
+	// source is being parsed, the error unwraps to `Ctx.Err()`
+	Ctx context.Context
+
+	// allocates the frequent nodes, the scopes and the refs from the arena instead of the heap,
+	// see `Arena`
+	Arena *Arena
 }
 
 const defaultFeatures Feature = FEAT_MODULE | FEAT_GLOBAL_ASYNC | FEAT_STRICT | FEAT_LET_CONST |
@@ -73,7 +122,11 @@ const defaultFeatures Feature = FEAT_MODULE | FEAT_GLOBAL_ASYNC | FEAT_STRICT |
 	FEAT_SPREAD | FEAT_META_PROPERTY | FEAT_ASYNC_AWAIT | FEAT_ASYNC_ITERATION | FEAT_ASYNC_GENERATOR |
 	FEAT_POW | FEAT_CLASS_PRV | FEAT_CLASS_PUB_FIELD | FEAT_CLASS_PRIV_FIELD | FEAT_OPT_EXPR | FEAT_OPT_CATCH_PARAM |
 	FEAT_NULLISH | FEAT_BAD_ESCAPE_IN_TAGGED_TPL | FEAT_BIGINT | FEAT_NUM_SEP | FEAT_LOGIC_ASSIGN |
-	FEAT_DYNAMIC_IMPORT | FEAT_JSON_SUPER_SET | FEAT_EXPORT_ALL_AS_NS | FEAT_JSX | FEAT_DECORATOR
+	FEAT_DYNAMIC_IMPORT | FEAT_JSON_SUPER_SET | FEAT_EXPORT_ALL_AS_NS | FEAT_CLASS_PRIV_IN | FEAT_CLASS_STATIC_BLOCK |
+	FEAT_MODULE_STR_NAME | FEAT_HASHBANG | FEAT_JSX | FEAT_DECORATOR | FEAT_USING |
+	FEAT_IMPORT_ATTRS | FEAT_CHK_REGEXP | FEAT_ARROW_FN | FEAT_CLASS | FEAT_GENERATOR | FEAT_TPL_LIT |
+	FEAT_FOR_OF | FEAT_DEFAULT_PARAM | FEAT_COMPUTED_KEY | FEAT_OBJ_REST_SPREAD | FEAT_REGEXP_NAMED_GROUP |
+	FEAT_REGEXP_LOOKBEHIND | FEAT_IMPORT_META
 
 func NewParserOpts() *ParserOpts {
 	return &ParserOpts{
@@ -87,13 +140,39 @@ func (o *ParserOpts) Clone() *ParserOpts {
 		Externals: o.Externals,
 		Version:   o.Version,
 		Feature:   o.Feature,
+		Auto:      o.Auto,
+		Tokens:    o.Tokens,
+		MaxSize:   o.MaxSize,
+		MaxDepth:  o.MaxDepth,
+		MaxTokens: o.MaxTokens,
+		Ctx:       o.Ctx,
+		Arena:     o.Arena,
 	}
 }
 
+// the clone of the options adjusted by the extension of the file, `FEAT_TS` is turned on for
+// the typescript files, `FEAT_DTS` is turned on for the declaration files and `FEAT_JSX` is
+// turned off for the typescript files except `.tsx`, the options of the other files such as
+// `.js` and `.jsx` are kept as they are
+func (o *ParserOpts) OfFile(file string) *ParserOpts {
+	opts := o.Clone()
+	switch {
+	case strings.HasSuffix(file, ".d.ts"):
+		opts.Feature = opts.Feature.On(FEAT_TS).On(FEAT_DTS).Off(FEAT_JSX)
+	case strings.HasSuffix(file, ".tsx"):
+		opts.Feature = opts.Feature.On(FEAT_TS)
+	case strings.HasSuffix(file, ".ts"), strings.HasSuffix(file, ".mts"), strings.HasSuffix(file, ".cts"):
+		opts.Feature = opts.Feature.On(FEAT_TS).Off(FEAT_JSX)
+	}
+	return opts
+}
+
 func (o *ParserOpts) MergeJson(obj map[string]interface{}) {
 	if moduleType, ok := obj["sourceType"]; ok {
 		if moduleType == "module" {
 			o.Feature = o.Feature.On(FEAT_MODULE)
+		} else if moduleType == "unambiguous" {
+			o.Auto = true
 		}
 	}
 	if on, ok := obj["typescript"]; ok {
@@ -105,6 +184,9 @@ func (o *ParserOpts) MergeJson(obj map[string]interface{}) {
 	if on, ok := obj["dts"]; ok {
 		o.Feature = o.Feature.Turn(FEAT_DTS, on == true)
 	}
+	if on, ok := obj["flow"]; ok {
+		o.Feature = o.Feature.Turn(FEAT_FLOW, on == true)
+	}
 	if on, ok := obj["strict"]; ok {
 		o.Feature = o.Feature.Turn(FEAT_STRICT, on == true)
 	}
@@ -117,6 +199,45 @@ func NewParser(src *span.Source, opts *ParserOpts) *Parser {
 }
 
 func (p *Parser) Setup(src *span.Source, opts *ParserOpts) {
+	p.setup(src, opts, nil)
+}
+
+// Reset prepares the parser to parse another source like `Setup`, unlike creating a new parser
+// the maps and the slices of the previous parsing are reused, so its results like the AST, the
+// symtab, the comments and the tokens should not be used after the parser is reset, it's for
+// the services which parse the sources massively by pooling the parsers
+func (p *Parser) Reset(src *span.Source, opts *ParserOpts) {
+	if p.lexer == nil {
+		p.Setup(src, opts)
+		return
+	}
+	old := *p
+	*p = Parser{}
+	p.setup(src, opts, &old)
+}
+
+// the containers of `old` are reused if it's not `nil`
+func (p *Parser) setup(src *span.Source, opts *ParserOpts, old *Parser) {
+	p.det, p.autoOpts = nil, nil
+	if opts.Auto {
+		p.det = Detect(src.Text(0, uint32(src.Len())), src.Path)
+		p.autoOpts = opts
+		opts = opts.Clone()
+		opts.Feature = p.det.Apply(opts.Feature)
+		opts.Auto = false
+	}
+
+	// flow reuses the facilities of typescript to parse the type annotations
+	if opts.Feature&FEAT_FLOW != 0 {
+		opts.Feature = opts.Feature.On(FEAT_TS)
+	}
+
+	// the typescript sources are compiled to the target version so the syntax is not
+	// restricted by it
+	if opts.Version != 0 && opts.Feature&FEAT_TS == 0 {
+		opts.Feature &= opts.Version.Features() | ^versionedFeatures
+	}
+
 	if opts.Feature&FEAT_ASYNC_AWAIT == 0 {
 		opts.Feature = opts.Feature.Off(FEAT_GLOBAL_ASYNC)
 	}
@@ -125,26 +246,57 @@ func (p *Parser) Setup(src *span.Source, opts *ParserOpts) {
 	}
 
 	p.feat = opts.Feature
-	p.imp = map[string]*Ident{}
 	p.checkName = true
-	p.danglingPvtRefs = make([]*Ref, 0)
-	p.ltTokens = map[uint32]bool{}
-	p.symtab = NewSymTab(opts.Externals)
-	p.loopStk = []Node{}
-	p.retsStk = [][]Node{}
-	p.tryStk = []Node{}
-	p.prevCmts = map[Node][]span.Range{}
-	p.postCmts = map[Node][]span.Range{}
-
-	p.lexer = NewLexer(src)
+	p.nodeCmts = nil
+	p.marks = nil
+	p.reused = nil
+	p.arena = opts.Arena
+	if old == nil {
+		p.imp = map[string]*Ident{}
+		p.danglingPvtRefs = make([]*Ref, 0)
+		p.ltTokens = map[uint32]bool{}
+		p.symtab = newSymTab(opts.Externals, opts.Arena)
+		p.loopStk = []Node{}
+		p.retsStk = [][]Node{}
+		p.tryStk = []Node{}
+		p.prevCmts = map[Node][]span.Range{}
+		p.postCmts = map[Node][]span.Range{}
+		p.lexer = NewLexer(src)
+	} else {
+		p.imp = emptyMap(old.imp)
+		p.danglingPvtRefs = emptySlice(old.danglingPvtRefs)
+		p.ltTokens = emptyMap(old.ltTokens)
+		p.symtab = old.symtab
+		p.symtab.reset(opts.Externals, opts.Arena)
+		p.loopStk = emptySlice(old.loopStk)
+		p.retsStk = emptySlice(old.retsStk)
+		p.tryStk = emptySlice(old.tryStk)
+		for n := range old.prevCmts {
+			delete(old.prevCmts, n)
+		}
+		for n := range old.postCmts {
+			delete(old.postCmts, n)
+		}
+		p.prevCmts, p.postCmts = old.prevCmts, old.postCmts
+		p.lexer = old.lexer
+		p.lexer.reset(src)
+	}
+
 	p.lexer.ver = opts.Version
 	p.lexer.feat = opts.Feature
+	p.lexer.keepToks = opts.Tokens
+	p.lexer.maxToks = opts.MaxTokens
+	p.lexer.ctx = opts.Ctx
+	p.maxSize = opts.MaxSize
+	p.maxDepth = opts.MaxDepth
+	p.depth = 0
 	if p.feat&FEAT_TS != 0 || p.feat&FEAT_DTS != 0 {
 		p.lexer.AddMode(LM_TS)
 	}
 
 	p.ts = p.feat&FEAT_TS != 0
 	p.dts = p.feat&FEAT_DTS != 0
+	p.flow = p.feat&FEAT_FLOW != 0
 }
 
 func (p *Parser) pushLoopStk(loopNode Node) {
@@ -198,6 +350,34 @@ func (p *Parser) PostCmts(stmt Node) []span.Range {
 	return p.postCmts[stmt]
 }
 
+// the ranges of all the comments in the source, it's only complete after the source is
+// entirely parsed
+func (p *Parser) Comments() []span.Range {
+	return p.lexer.Comments()
+}
+
+// the comments attached to the nodes of the AST, unlike `PrevCmts` and `PostCmts` which only
+// work for the statements, all the comments are attached to the nodes of any kind, the
+// attachment is done at the first call after the source is entirely parsed
+func (p *Parser) NodeComments() *NodeComments {
+	if p.nodeCmts == nil {
+		p.nodeCmts = AttachComments(p.prog, p.Comments(), p.lexer.src.Text(0, uint32(p.lexer.src.Len())), ChildNodes)
+	}
+	return p.nodeCmts
+}
+
+func (p *Parser) LeadingComments(node Node) []span.Range {
+	return p.NodeComments().Leading(node)
+}
+
+func (p *Parser) TrailingComments(node Node) []span.Range {
+	return p.NodeComments().Trailing(node)
+}
+
+func (p *Parser) InnerComments(node Node) []span.Range {
+	return p.NodeComments().Inner(node)
+}
+
 func (p *Parser) Source() *span.Source {
 	return p.lexer.src
 }
@@ -206,24 +386,77 @@ func (p *Parser) Lexer() *Lexer {
 	return p.lexer
 }
 
+// the tokens consumed by the parser if `ParserOpts.Tokens` is turned on, unlike the ones
+// returned by `Tokenize` the ambiguities like the regexps and the JSX are resolved by the parser
+func (p *Parser) Tokens() []Token {
+	return p.lexer.Tokens()
+}
+
+// the features used by the parser, it's the `Feature` of the options passed to `Setup`
+// adjusted by the target version and the dependencies between the features
+func (p *Parser) Feature() Feature {
+	return p.feat
+}
+
 func (p *Parser) Ast() Node {
 	return p.prog
 }
 
+// the detection of the auto mode, it's revised by the features which the code is parsed
+// successfully with, `nil` is returned if the auto mode is not turned on
+func (p *Parser) Detection() *Detection {
+	return p.det
+}
+
 func (p *Parser) Prog() (Node, error) {
+	node, err := p.parseProg()
+	if p.lexer.abort != nil {
+		return nil, p.errorAbort()
+	}
+	if err != nil && p.det != nil {
+		return p.reparse(err)
+	}
+	return node, err
+}
+
+// reparses the code with the alternatives of the guessed features in the auto mode, the parser
+// is restored and the original error is returned if none of them succeeds
+func (p *Parser) reparse(err error) (Node, error) {
+	orig := *p
+	det, opts, src := p.det, p.autoOpts, p.lexer.src
+	code := src.Text(0, uint32(src.Len()))
+	for _, feat := range det.alternatives(opts.Feature) {
+		alt := opts.Clone()
+		alt.Feature = feat
+		alt.Auto = false
+		*p = Parser{}
+		p.Setup(span.NewSource(src.Path, code), alt)
+		node, e := p.parseProg()
+		if p.lexer.abort != nil {
+			return nil, p.errorAbort()
+		}
+		if e == nil {
+			p.det, p.autoOpts = det.revise(feat), opts
+			return node, nil
+		}
+	}
+	*p = orig
+	return nil, err
+}
+
+func (p *Parser) parseProg() (Node, error) {
 	rng := p.rng()
 	pg := &Prog{N_PROG, span.Range{}, make([]Node, 0, 20)}
 	p.prog = pg
 
-	scope := p.scope()
-	scope.AddKind(SPK_GLOBAL)
-	if p.feat&FEAT_GLOBAL_ASYNC != 0 {
-		scope.AddKind(SPK_ASYNC)
+	if p.maxSize > 0 && p.lexer.src.Len() > p.maxSize {
+		p.lexer.abortAt(fmt.Sprintf(ERR_TPL_SRC_TOO_LARGE, p.maxSize), 0, nil)
 	}
-	if p.feat&FEAT_STRICT != 0 {
-		p.enterStrict(true)
+	if p.lexer.checkCtx(0); p.lexer.abort != nil {
+		return nil, p.errorAbort()
 	}
 
+	scope := p.globalScope()
 	stmts, err := p.stmts(T_ILLEGAL)
 	if err != nil {
 		return nil, err
@@ -244,6 +477,19 @@ func (p *Parser) Prog() (Node, error) {
 	return pg, nil
 }
 
+func (p *Parser) globalScope() *Scope {
+	scope := p.scope()
+	scope.AddKind(SPK_GLOBAL)
+	// the top-level `await` is only available in modules, `await` is an identifier in scripts
+	if p.feat&FEAT_GLOBAL_ASYNC != 0 && p.feat&FEAT_MODULE != 0 {
+		scope.AddKind(SPK_ASYNC)
+	}
+	if p.feat&FEAT_STRICT != 0 {
+		p.enterStrict(true)
+	}
+	return scope
+}
+
 func (p *Parser) resolvingDanglingPvtRefs() error {
 	for _, ref := range p.danglingPvtRefs {
 		if ref.Typ == RDT_PVT_FIELD {
@@ -301,10 +547,9 @@ func (p *Parser) checkExp(exps []*ExportDec) error {
 			}
 		}
 		for _, sn := range subnames {
-			id := sn.(*Ident)
-			name := id.val
+			name := moduleExportName(sn)
 			if _, ok := names[name]; ok {
-				return p.errorAtLoc(id.Range(), fmt.Sprintf(ERR_DUP_EXPORT, name))
+				return p.errorAtLoc(sn.Range(), fmt.Sprintf(ERR_DUP_EXPORT, name))
 			} else {
 				names[name] = true
 			}
@@ -334,6 +579,11 @@ var errEof = errors.New("eof")
 
 // https://tc39.es/ecma262/multipage/ecmascript-language-statements-and-declarations.html#prod-Statement
 func (p *Parser) stmt() (node Node, err error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	tok := p.lexer.PeekStmtBegin()
 
 	scope := p.scope()
@@ -390,6 +640,10 @@ func (p *Parser) stmt() (node Node, err error) {
 		if allowDec {
 			node, err = p.varDecStmt(kind, false)
 		}
+	} else if ok, kind := p.aheadIsUsing(tok, false); ok {
+		if allowDec {
+			node, err = p.varDecStmt(kind, false)
+		}
 	} else if p.aheadIsAsync(tok, false, false) {
 		if tok.ContainsEscape() {
 			return nil, p.errorAt(tok.value, tok.rng, ERR_ESCAPE_IN_KEYWORD)
@@ -400,6 +654,8 @@ func (p *Parser) stmt() (node Node, err error) {
 	} else if p.aheadIsTsTypDec(tok, false) {
 		rng := p.lexer.Next().rng
 		node, err = p.tsTypDec(rng, false)
+	} else if p.aheadIsFlowOpaque(tok) {
+		node, err = p.flowOpaqueTyp(false)
 	} else if p.aheadIsTsItf(tok) {
 		node, err = p.tsItf()
 	} else if p.aheadIsTsNS(tok) {
@@ -464,10 +720,20 @@ func (p *Parser) exportDec() (Node, error) {
 	}
 
 	var err error
-	node := &ExportDec{N_STMT_EXPORT, span.Range{}, false, span.Range{}, nil, nil, nil, false}
+	node := &ExportDec{N_STMT_EXPORT, span.Range{}, false, span.Range{}, nil, nil, nil, nil, span.Range{}, false}
 	specs := make([]Node, 0, 3)
 	tok = p.lexer.Peek()
 	tv := tok.value
+
+	// the decorators after `export` like `export @dec class A {}`
+	if p.aheadIsDecorator(tok) {
+		if err := p.exportDecorators(); err != nil {
+			return nil, err
+		}
+		tok = p.lexer.Peek()
+		tv = tok.value
+	}
+
 	if tv == T_MUL || tv == T_BRACE_L {
 		ss, all, src, err := p.exportFrom(false)
 		node.src = src
@@ -476,6 +742,11 @@ func (p *Parser) exportDec() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
+		if src != nil {
+			if node.attrs, node.attrKw, err = p.importAttrs(); err != nil {
+				return nil, err
+			}
+		}
 		if err := p.advanceIfSemi(true); err != nil {
 			return nil, err
 		}
@@ -505,6 +776,12 @@ func (p *Parser) exportDec() (Node, error) {
 	} else if tv == T_DEFAULT {
 		node.def = p.lexer.Next().rng
 		tok := p.lexer.Peek()
+		if p.aheadIsDecorator(tok) {
+			if err := p.exportDecorators(); err != nil {
+				return nil, err
+			}
+			tok = p.lexer.Peek()
+		}
 		tv = tok.value
 		if tv == T_FUNC {
 			node.dec, err = p.fnDec(false, nil, true)
@@ -561,11 +838,17 @@ func (p *Parser) exportDec() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-	} else if p.aheadIsTsTypDec(tok, false) {
+	} else if p.aheadIsFlowOpaque(tok) {
+		node.tsTyp = true
+		node.dec, err = p.flowOpaqueTyp(false)
+		if err != nil {
+			return nil, err
+		}
+	} else if p.aheadIsTsTypDec(tok, true) {
 		node.tsTyp = true
 		rng := p.lexer.Next().rng // consume `type`
-		// `export type { A };`
-		if p.lexer.Peek().value == T_BRACE_L {
+		// `export type { A };` and `export type * from "a"`
+		if av := p.lexer.Peek().value; av == T_BRACE_L || av == T_MUL {
 			ss, all, src, err := p.exportFrom(true)
 			node.src = src
 			node.all = all
@@ -573,6 +856,11 @@ func (p *Parser) exportDec() (Node, error) {
 			if err != nil {
 				return nil, err
 			}
+			if src != nil {
+				if node.attrs, node.attrKw, err = p.importAttrs(); err != nil {
+					return nil, err
+				}
+			}
 			p.advanceIfSemi(false)
 		} else {
 			node.dec, err = p.tsTypDec(rng, false)
@@ -653,10 +941,15 @@ func (p *Parser) exportFrom(typ bool) ([]Node, bool, Node, error) {
 	if tok.value == T_MUL {
 		ns = true
 		ahead := p.lexer.Peek()
+		if ahead.value == T_NAME && ahead.text == "as" {
+			if err := p.errorVersion(ahead.rng, FEAT_EXPORT_ALL_AS_NS); err != nil {
+				return nil, false, nil, err
+			}
+		}
 		if ahead.value == T_NAME && ahead.text == "as" && p.feat&FEAT_EXPORT_ALL_AS_NS != 0 {
 			p.lexer.Next()
 
-			id, err := p.ident(nil, false)
+			id, err := p.moduleExportName(false)
 			if err != nil {
 				return nil, false, nil, err
 			}
@@ -682,12 +975,17 @@ func (p *Parser) exportFrom(typ bool) ([]Node, bool, Node, error) {
 		if err != nil {
 			return nil, false, nil, err
 		}
-		src = &StrLit{N_LIT_STR, p.finRng(str.rng), p.TokText(str), str.HasLegacyOctalEscapeSeq(), span.Range{}, nil}
+		src = p.newStrLit(StrLit{N_LIT_STR, p.finRng(str.rng), p.TokText(str), str.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
 	} else {
 		// `export { default } from "a"` is legal
 		// `export { default }` is illegal
+		// `export { "a" }` is illegal
 		for _, spec := range specs {
-			id := spec.(*ExportSpec).local.(*Ident)
+			local := spec.(*ExportSpec).local
+			if local.Type() == N_LIT_STR {
+				return nil, false, nil, p.errorAtLoc(local.Range(), ERR_STR_EXPORT_WITHOUT_FROM)
+			}
+			id := local.(*Ident)
 			if id.kw {
 				return nil, false, nil, p.errorAtLoc(id.rng, fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, id.val))
 			}
@@ -735,11 +1033,36 @@ func (p *Parser) identWithKw(scope *Scope, binding bool) (Node, error) {
 	if ahead.IsKw() {
 		p.lexer.Next()
 		str := TokenKinds[ahead.value].Name
-		return &Ident{N_NAME, p.finRng(ahead.rng), str, false, false, span.Range{}, true, p.newTypInfo(N_NAME)}, nil
+		return p.newIdent(Ident{N_NAME, p.finRng(ahead.rng), str, false, false, span.Range{}, true, p.newTypInfo(N_NAME)}), nil
 	}
 	return p.ident(scope, binding)
 }
 
+// the name in the import and export specifiers, the string literal is permitted as the name
+// like `export { a as "a b" }` besides the identifier
+func (p *Parser) moduleExportName(kw bool) (Node, error) {
+	tok := p.lexer.Peek()
+	if tok.value != T_STRING {
+		if kw {
+			return p.identWithKw(nil, false)
+		}
+		return p.ident(nil, false)
+	}
+
+	if p.feat&FEAT_MODULE_STR_NAME == 0 {
+		return nil, p.errorFeat(tok, FEAT_MODULE_STR_NAME)
+	}
+	p.lexer.Next()
+	return p.newStrLit(StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil}), nil
+}
+
+func moduleExportName(node Node) string {
+	if node.Type() == N_LIT_STR {
+		return node.(*StrLit).val
+	}
+	return node.(*Ident).val
+}
+
 func (p *Parser) exportSpec(typ bool) (Node, error) {
 	var local Node
 	var err error
@@ -752,7 +1075,8 @@ func (p *Parser) exportSpec(typ bool) (Node, error) {
 			return nil, p.errorAtLoc(rng, ERR_EXPORT_DUP_TYPE_MODIFIER)
 		}
 		typ = true
-		local, err = p.tsTypDec(rng, true)
+		// the specifier only refers to the exported name, there is no new binding
+		local, err = p.identWithKw(nil, false)
 		if err != nil {
 			return nil, err
 		}
@@ -766,7 +1090,7 @@ func (p *Parser) exportSpec(typ bool) (Node, error) {
 			// `export { type as as if };`
 			_, canProp2 := ahead2nd.CanBePropKey()
 			if canProp && !canProp2 {
-				local = &Ident{N_NAME, typLoc, "type", false, false, span.Range{}, false, p.newTypInfo(N_NAME)}
+				local = p.newIdent(Ident{N_NAME, typLoc, "type", false, false, span.Range{}, false, p.newTypInfo(N_NAME)})
 				id, err := p.identWithKw(nil, false)
 				if err != nil {
 					return nil, err
@@ -775,7 +1099,7 @@ func (p *Parser) exportSpec(typ bool) (Node, error) {
 			}
 		}
 	} else {
-		local, err = p.identWithKw(nil, false)
+		local, err = p.moduleExportName(true)
 		if err != nil {
 			return nil, err
 		}
@@ -787,7 +1111,7 @@ func (p *Parser) exportSpec(typ bool) (Node, error) {
 		if tok.ContainsEscape() {
 			return nil, p.errorAt(tok.value, tok.rng, ERR_ESCAPE_IN_KEYWORD)
 		}
-		id, err = p.identWithKw(nil, false)
+		id, err = p.moduleExportName(true)
 		if err != nil {
 			return nil, err
 		}
@@ -801,11 +1125,14 @@ func (p *Parser) importDec() (Node, error) {
 	rng := p.rng()
 	importTok := p.lexer.Peek()
 
+	ahead := p.lexer.Peek2nd()
 	if p.feat&FEAT_IMPORT_DEC == 0 && p.feat&FEAT_DYNAMIC_IMPORT == 0 {
-		return nil, p.errorTok(importTok)
+		if ahead.value == T_PAREN_L {
+			return nil, p.errorFeat(importTok, FEAT_DYNAMIC_IMPORT)
+		}
+		return nil, p.errorFeat(importTok, FEAT_IMPORT_DEC)
 	}
 
-	ahead := p.lexer.Peek2nd()
 	if ahead.value == T_PAREN_L || ahead.value == T_DOT {
 		return p.exprStmt()
 	}
@@ -814,7 +1141,7 @@ func (p *Parser) importDec() (Node, error) {
 
 	specs := make([]Node, 0, 5)
 	tok := p.lexer.Peek()
-	node := &ImportDec{N_STMT_IMPORT, span.Range{}, specs, nil, false}
+	node := &ImportDec{N_STMT_IMPORT, span.Range{}, specs, nil, nil, span.Range{}, false, false}
 
 	// the second arg set to `true` for stmt like: `import type * as Types`
 	typDec := p.aheadIsTsTypDec(tok, true)
@@ -823,19 +1150,29 @@ func (p *Parser) importDec() (Node, error) {
 		typDec = !(ahead2nd.value == T_NAME && ahead2nd.text == "from")
 	}
 
+	// the flow `import typeof A from "a"`
+	typOf := p.flow && tok.value == T_TYPE_OF
+	if typOf {
+		typDec = true
+		node.typOf = true
+	}
+
 	if typDec {
 		node.tsTyp = true
-		typRng := p.lexer.Next().rng // consume `type`
+		typRng := p.lexer.Next().rng // consume `type` or `typeof`
 		// `import type { A }`
 		// `import type * as Types`
 		ahead := p.lexer.Peek()
 		av := ahead.value
 		if av == T_BRACE_L || av == T_MUL {
 			ss, err := p.importNamedOrNS(true)
-			specs = append(specs, ss...)
 			if err != nil {
 				return nil, err
 			}
+			for _, s := range ss {
+				s.(*ImportSpec).typOf = typOf
+			}
+			specs = append(specs, ss...)
 		} else {
 			// `import type A`
 			tn, err := p.tsTypName(nil)
@@ -858,11 +1195,23 @@ func (p *Parser) importDec() (Node, error) {
 				return alias, nil
 			}
 
-			spec := &ImportSpec{N_IMPORT_SPEC, p.finRng(tn.Range()), true, false, tn, tn, true}
+			spec := &ImportSpec{N_IMPORT_SPEC, p.finRng(tn.Range()), true, false, tn, tn, true, typOf}
 			specs = append(specs, spec)
 
 			if p.lexer.Peek().value == T_COMMA {
-				return nil, p.errorAtLoc(p.lexer.Next().rng, ERR_IMPORT_TYP_MIX_NAMED)
+				// flow permits the default and the named ones to be mixed like `import type A, { B } from "a"`
+				if !p.flow {
+					return nil, p.errorAtLoc(p.lexer.Next().rng, ERR_IMPORT_TYP_MIX_NAMED)
+				}
+				p.lexer.Next()
+				ss, err := p.importNamedOrNS(true)
+				if err != nil {
+					return nil, err
+				}
+				for _, s := range ss {
+					s.(*ImportSpec).typOf = typOf
+				}
+				specs = append(specs, ss...)
 			}
 		}
 	} else if tok.value != T_STRING {
@@ -873,7 +1222,7 @@ func (p *Parser) importDec() (Node, error) {
 			if err != nil {
 				return nil, err
 			}
-			spec := &ImportSpec{N_IMPORT_SPEC, p.finRng(tok.rng), true, false, id, id, false}
+			spec := &ImportSpec{N_IMPORT_SPEC, p.finRng(tok.rng), true, false, id, id, false, false}
 			specs = append(specs, spec)
 		} else {
 			ss, err := p.importNamedOrNS(false)
@@ -914,8 +1263,11 @@ func (p *Parser) importDec() (Node, error) {
 		return nil, p.errorAtLoc(str.rng, ERR_LEGACY_OCTAL_ESCAPE_IN_STRICT_MODE)
 	}
 
-	node.src = &StrLit{N_LIT_STR, p.finRng(str.rng), p.TokText(str), legacyOctalEscapeSeq, span.Range{}, nil}
+	node.src = p.newStrLit(StrLit{N_LIT_STR, p.finRng(str.rng), p.TokText(str), legacyOctalEscapeSeq, span.Range{}, nil})
 	node.specs = specs
+	if node.attrs, node.attrKw, err = p.importAttrs(); err != nil {
+		return nil, err
+	}
 	if err := p.advanceIfSemi(true); err != nil {
 		return nil, err
 	}
@@ -923,7 +1275,7 @@ func (p *Parser) importDec() (Node, error) {
 	if !typDec {
 		for _, spec := range specs {
 			s := spec.(*ImportSpec)
-			ref := NewRef()
+			ref := p.newRef()
 			ref.Id = s.local.(*Ident)
 			ref.Dec = node
 			ref.BindKind = BK_CONST
@@ -938,6 +1290,87 @@ func (p *Parser) importDec() (Node, error) {
 	return node, nil
 }
 
+// the import attributes after the module specifier like `with { type: "json" }`, the legacy keyword
+// `assert` is also permitted however it cannot be preceded by a line terminator since it's not a
+// reserved word, for example the `assert` in below code is the beginning of a new statement:
+//
+//	import a from "./a.json"
+//	assert({ type: "json" })
+func (p *Parser) importAttrs() ([]Node, span.Range, error) {
+	tok := p.lexer.Peek()
+	if tok.value != T_WITH && !(IsName(tok, "assert", false) && !tok.afterLineTerm) {
+		return nil, span.Range{}, nil
+	}
+	if p.feat&FEAT_IMPORT_ATTRS == 0 {
+		return nil, span.Range{}, p.errorFeat(tok, FEAT_IMPORT_ATTRS)
+	}
+	kw := p.lexer.Next().rng
+
+	if _, err := p.nextMustTok(T_BRACE_L); err != nil {
+		return nil, span.Range{}, err
+	}
+
+	attrs := make([]Node, 0, 1)
+	keys := map[string]bool{}
+	for {
+		tok := p.lexer.Peek()
+		if tok.value == T_BRACE_R {
+			break
+		}
+
+		attr, err := p.importAttr()
+		if err != nil {
+			return nil, span.Range{}, err
+		}
+		name := attr.KeyName()
+		if keys[name] {
+			return nil, span.Range{}, p.errorAtLoc(attr.key.Range(), fmt.Sprintf(ERR_TPL_IMPORT_ATTR_DUP_KEY, name))
+		}
+		keys[name] = true
+		attrs = append(attrs, attr)
+
+		ahead := p.lexer.Peek()
+		if ahead.value == T_COMMA {
+			p.lexer.Next()
+		} else if ahead.value != T_BRACE_R {
+			return nil, span.Range{}, p.errorTok(ahead)
+		}
+	}
+
+	if _, err := p.nextMustTok(T_BRACE_R); err != nil {
+		return nil, span.Range{}, err
+	}
+	return attrs, kw, nil
+}
+
+func (p *Parser) importAttr() (*ImportAttr, error) {
+	rng := p.rng()
+	tok := p.lexer.Peek()
+
+	var key Node
+	var err error
+	if tok.value == T_STRING {
+		p.lexer.Next()
+		key = p.newStrLit(StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
+	} else {
+		key, err = p.identWithKw(nil, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.nextMustTok(T_COLON); err != nil {
+		return nil, err
+	}
+
+	tok = p.lexer.Next()
+	if tok.value != T_STRING {
+		return nil, p.errorAtLoc(tok.rng, ERR_IMPORT_ATTR_VALUE_MUST_STR)
+	}
+	val := p.newStrLit(StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
+	return &ImportAttr{N_IMPORT_ATTR, p.finRng(rng), key, val}, nil
+}
+
 func (p *Parser) importNamedOrNS(typ bool) ([]Node, error) {
 	tok := p.lexer.Peek()
 	if tok.value == T_BRACE_L {
@@ -954,6 +1387,21 @@ func (p *Parser) importSpec(typ bool) (Node, error) {
 	var err error
 	rng := p.rng()
 	ahead := p.lexer.Peek()
+	if p.flow && ahead.value == T_TYPE_OF && p.aheadIsFlowTypOfSpec() {
+		// the flow `import { typeof A } from "a"`
+		if typ {
+			return nil, p.errorAtLoc(ahead.rng, ERR_EXPORT_DUP_TYPE_MODIFIER)
+		}
+		p.lexer.Next()
+		spec, err := p.importSpec(true)
+		if err != nil {
+			return nil, err
+		}
+		s := spec.(*ImportSpec)
+		s.rng.Lo = rng.Lo
+		s.typOf = true
+		return s, nil
+	}
 	if p.aheadIsTsTypDec(ahead, false) {
 		rng := p.lexer.Next().rng // consume `type`
 		typLoc := p.finRng(rng)
@@ -975,16 +1423,16 @@ func (p *Parser) importSpec(typ bool) (Node, error) {
 			// `export { type as as if };`
 			_, canProp2 := ahead2nd.CanBePropKey()
 			if canProp && !canProp2 {
-				binding = &Ident{N_NAME, typLoc, "type", false, false, span.Range{}, false, p.newTypInfo(N_NAME)}
+				binding = p.newIdent(Ident{N_NAME, typLoc, "type", false, false, span.Range{}, false, p.newTypInfo(N_NAME)})
 				id, err := p.identWithKw(nil, false)
 				if err != nil {
 					return nil, err
 				}
-				return &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, false, id, binding, false}, nil
+				return &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, false, id, binding, false, false}, nil
 			}
 		}
 	} else {
-		binding, err = p.identWithKw(nil, false)
+		binding, err = p.moduleExportName(true)
 		if err != nil {
 			return nil, err
 		}
@@ -997,6 +1445,9 @@ func (p *Parser) importSpec(typ bool) (Node, error) {
 		if err != nil {
 			return nil, err
 		}
+	} else if binding.Type() == N_LIT_STR {
+		// the string name should be renamed to a binding like `import { "a b" as c } from "mod"`
+		return nil, p.errorTok(p.lexer.Peek())
 	} else if binding.Type() == N_NAME {
 		// for statement like `import { true } from "bar"`, report `true` is a keyword
 		id := binding.(*Ident)
@@ -1005,7 +1456,7 @@ func (p *Parser) importSpec(typ bool) (Node, error) {
 		}
 	}
 
-	return &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, false, binding, id, typ}, nil
+	return &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, false, binding, id, typ, false}, nil
 }
 
 func (p *Parser) importNamed(typ bool) ([]Node, error) {
@@ -1051,13 +1502,16 @@ func (p *Parser) importNS(typ bool) ([]Node, error) {
 	}
 
 	specs := make([]Node, 1)
-	specs[0] = &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, true, id, nil, typ}
+	specs[0] = &ImportSpec{N_IMPORT_SPEC, p.finRng(rng), false, true, id, nil, typ, false}
 	return specs, nil
 }
 
 // https://tc39.es/ecma262/multipage/ecmascript-language-functions-and-classes.html#prod-ClassDeclaration
 func (p *Parser) classDec(expr bool, canNameOmitted bool, declare bool, abstract bool) (Node, error) {
 	declare = declare || p.feat&FEAT_DTS != 0
+	if p.feat&FEAT_CLASS == 0 {
+		return nil, p.errorFeat(p.lexer.Peek(), FEAT_CLASS)
+	}
 	rng := p.lexer.Next().rng
 
 	if abstract {
@@ -1097,7 +1551,7 @@ func (p *Parser) classDec(expr bool, canNameOmitted bool, declare bool, abstract
 		}
 
 		if ti != nil {
-			typParams, err := p.tsTryTypParams()
+			typParams, err := p.tsTryTypParams(true)
 			if err != nil {
 				return nil, err
 			}
@@ -1105,7 +1559,7 @@ func (p *Parser) classDec(expr bool, canNameOmitted bool, declare bool, abstract
 		}
 
 		if id != nil {
-			ref := NewRef()
+			ref := p.newRef()
 			ref.Id = id.(*Ident)
 			ref.Dec = dec
 			ref.BindKind = BK_CONST
@@ -1192,6 +1646,11 @@ func (p *Parser) classBody(declare bool, hasSuper bool) (Node, error) {
 	hasCtor := false
 	pvtNames := make(map[string]Node)
 	scope := p.scope()
+
+	// the decorators of the element are held locally instead of being hung on the parser
+	// until the element is parsed, otherwise they will be reported as the misplaced ones
+	// by the statements in the body of the element
+	var ds []Node
 	for {
 		tok := p.lexer.Peek()
 		if tok.value == T_BRACE_R {
@@ -1199,11 +1658,10 @@ func (p *Parser) classBody(declare bool, hasSuper bool) (Node, error) {
 		} else if tok.value == T_EOF {
 			return nil, p.errorTok(tok)
 		} else if p.aheadIsDecorator(tok) {
-			ds, err := p.decorators()
-			if err != nil {
+			var err error
+			if ds, err = p.decorators(); err != nil {
 				return nil, err
 			}
-			p.hangingDecorators = ds
 			continue
 		}
 		if tok.value == T_SEMI {
@@ -1216,19 +1674,25 @@ func (p *Parser) classBody(declare bool, hasSuper bool) (Node, error) {
 		}
 
 		// attach decorators
-		if len(p.hangingDecorators) > 0 {
-			if wt, ok := elem.(NodeWithTypInfo); ok {
-				ti := wt.TypInfo()
-				if ti != nil {
-					ti.decorators = p.hangingDecorators
-					p.hangingDecorators = nil
+		if len(ds) > 0 {
+			switch n := elem.(type) {
+			case *Method:
+				if n.ti != nil {
+					n.ti.decorators = ds
+					n.rng.Lo = ds[0].Range().Lo
+					ds = nil
+				}
+			case *Field:
+				if n.ti != nil {
+					n.ti.decorators = ds
+					n.rng.Lo = ds[0].Range().Lo
+					ds = nil
 				}
 			}
 		}
 
-		if len(p.hangingDecorators) != 0 {
-			n := p.hangingDecorators[0]
-			return nil, p.errorAtLoc(n.Range(), ERR_DECORATOR_INVALID_POSITION)
+		if len(ds) != 0 {
+			return nil, p.errorAtLoc(ds[0].Range(), ERR_DECORATOR_INVALID_POSITION)
 		}
 
 		if elem.Type() == N_METHOD {
@@ -1267,7 +1731,7 @@ func (p *Parser) classBody(declare bool, hasSuper bool) (Node, error) {
 			if elem.Type() == N_FIELD || elem.(*Method).HasBody() {
 				pvtNames[name] = elem
 
-				ref := NewRef()
+				ref := p.newRef()
 				ref.Id = key.(*Ident)
 				ref.Typ = RDT_PVT_FIELD
 				ref.BindKind = BK_PVT_FIELD
@@ -1330,7 +1794,7 @@ func (p *Parser) classBody(declare bool, hasSuper bool) (Node, error) {
 	return &ClassBody{N_CLASS_BODY, p.finRng(rng), elems}, nil
 }
 
-func (p *Parser) modifiers() (begin, static, access, abstract, readonly, override, declare span.Range,
+func (p *Parser) modifiers() (begin, static, access, abstract, readonly, override, declare, accessor span.Range,
 	isField, escape bool, name string, fieldLoc span.Range, accMod ACC_MOD, ahead *Token, mayStaticBlock bool) {
 	for {
 		ahead = p.lexer.Peek()
@@ -1351,7 +1815,7 @@ func (p *Parser) modifiers() (begin, static, access, abstract, readonly, overrid
 			}
 			mayStaticBlock = p.lexer.Peek().value == T_BRACE_L
 			fieldLoc = static
-		} else if p.ts && accMod == ACC_MOD_NONE && (av == T_PUBLIC || av == T_PRIVATE || av == T_PROTECTED) {
+		} else if p.ts && !p.flow && accMod == ACC_MOD_NONE && (av == T_PUBLIC || av == T_PRIVATE || av == T_PROTECTED) {
 			switch av {
 			case T_PUBLIC:
 				accMod = ACC_MOD_PUB
@@ -1376,7 +1840,7 @@ func (p *Parser) modifiers() (begin, static, access, abstract, readonly, overrid
 				}
 				fieldLoc = access
 			}
-		} else if p.ts && abstract.Empty() && IsName(ahead, "abstract", false) {
+		} else if p.ts && !p.flow && abstract.Empty() && IsName(ahead, "abstract", false) {
 			tok := p.lexer.Next()
 			abstract = tok.rng
 			if begin.Empty() {
@@ -1436,6 +1900,22 @@ func (p *Parser) modifiers() (begin, static, access, abstract, readonly, overrid
 				return
 			}
 			fieldLoc = declare
+		} else if p.ts && accessor.Empty() && IsName(ahead, "accessor", false) {
+			tok := p.lexer.Next()
+			accessor = tok.rng
+			if begin.Empty() {
+				begin = accessor
+			}
+			escape = tok.ContainsEscape()
+			name = p.TokText(tok)
+			isField, ahead = p.isField(false, false)
+			fieldLoc = accessor
+			// `accessor() {}` is the method named `accessor`
+			if isField || p.aheadIsArgList(ahead) {
+				accessor = span.Range{}
+				return
+			}
+			fieldLoc = accessor
 		} else {
 			break
 		}
@@ -1443,12 +1923,43 @@ func (p *Parser) modifiers() (begin, static, access, abstract, readonly, overrid
 	return
 }
 
-func (p *Parser) classElem(inDeclare bool) (Node, error) {
-	beginLoc, staticLoc, accLoc, abstractLoc, readonlyLoc, overrideLoc, declareLoc, isField, escape, fieldName, fieldLoc, accMod, ahead, mayStaticBlock := p.modifiers()
-	if err := p.tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accLoc, abstractLoc, declareLoc, accMod, mayStaticBlock); err != nil {
+func (p *Parser) classElem(inDeclare bool) (node Node, err error) {
+	beginLoc, staticLoc, accLoc, abstractLoc, readonlyLoc, overrideLoc, declareLoc, accessorLoc, isField, escape, fieldName, fieldLoc, accMod, ahead, mayStaticBlock := p.modifiers()
+	if err := p.tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accLoc, abstractLoc, declareLoc, accessorLoc, accMod, mayStaticBlock); err != nil {
 		return nil, err
 	}
 
+	// `accessor` is only permitted on the fields, the element is checked after it's parsed
+	// since the methods, accessors and fields are distinguished in various branches below
+	if !accessorLoc.Empty() {
+		defer func() {
+			if err != nil {
+				return
+			}
+			if node.Type() != N_FIELD {
+				node, err = nil, p.errorAtLoc(accessorLoc, ERR_ACCESSOR_MOD_ON_NON_FIELD)
+				return
+			}
+			node.(*Field).ti.SetAccessor(true)
+		}()
+	}
+
+	// the variance sigil of flow like `+x: T` is only permitted on the fields
+	if p.flow && (ahead.value == T_ADD || ahead.value == T_SUB) {
+		variance := p.lexer.Next().rng
+		ahead = p.lexer.Peek()
+		defer func() {
+			if err != nil {
+				return
+			}
+			if node.Type() != N_FIELD {
+				node, err = nil, p.errorAtLoc(variance, ERR_FLOW_UNEXPECTED_VARIANCE)
+				return
+			}
+			node.(*Field).ti.SetVariance(variance)
+		}()
+	}
+
 	static := !staticLoc.Empty()
 	abstract := !abstractLoc.Empty()
 	override := !overrideLoc.Empty()
@@ -1471,12 +1982,13 @@ func (p *Parser) classElem(inDeclare bool) (Node, error) {
 			return blk, nil
 		}
 		if p.aheadIsArgList(ahead) {
-			key := &Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, p.newTypInfo(N_STMT_CLASS)}
-			return p.method(beginLoc, key, accMod, span.Range{}, false, PK_METHOD, false, false, false, true, false, beginLoc, false, false, false, nil)
+			// `static() {}` is the method named `static` while `static readonly() {}` is the static one
+			key := p.newIdent(Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, p.newTypInfo(N_STMT_CLASS)})
+			return p.method(beginLoc, key, accMod, span.Range{}, false, PK_METHOD, false, false, false, true, fieldLoc != staticLoc, beginLoc, false, false, false, nil)
 		}
 	} else if isField {
 		ti := p.newTypInfo(N_STMT_CLASS)
-		key := &Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, ti}
+		key := p.newIdent(Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, ti})
 		if ti != nil {
 			ti.ques, ti.not = p.tsAdvanceHook(true)
 		}
@@ -1492,14 +2004,14 @@ func (p *Parser) classElem(inDeclare bool) (Node, error) {
 		}
 		return p.method(beginLoc, nil, accMod, span.Range{}, false, PK_METHOD, false, true, true, true, static, beginLoc, declare, abstract, override, nil)
 	} else if p.aheadIsArgList(ahead) {
-		key := &Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, p.newTypInfo(N_STMT_CLASS)}
+		key := p.newIdent(Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, true, p.newTypInfo(N_STMT_CLASS)})
 		return p.method(beginLoc, key, accMod, span.Range{}, false, PK_METHOD, false, false, false, true, false, beginLoc, false, false, false, nil)
 	} else if ahead.value == T_MUL {
 		if !isField && !readonlyLoc.Empty() {
 			return nil, p.errorAtLoc(readonlyLoc, ERR_METHOD_CANNOT_READONLY)
 		}
-		if p.feat&FEAT_ASYNC_GENERATOR == 0 {
-			return nil, p.errorTok(ahead)
+		if p.feat&FEAT_GENERATOR == 0 {
+			return nil, p.errorFeat(ahead, FEAT_GENERATOR)
 		}
 		return p.method(beginLoc, nil, accMod, span.Range{}, false, PK_METHOD, true, false, true, true, static, beginLoc, declare, abstract, override, nil)
 	}
@@ -1512,9 +2024,9 @@ func (p *Parser) classElem(inDeclare bool) (Node, error) {
 
 		var key Node
 		if ahead.value == T_STRING {
-			key = &StrLit{N_LIT_STR, p.finRng(propRng), name, ahead.HasLegacyOctalEscapeSeq(), span.Range{}, nil}
+			key = p.newStrLit(StrLit{N_LIT_STR, p.finRng(propRng), name, ahead.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
 		} else {
-			key = &Ident{N_NAME, p.finRng(propRng), name, false, ahead.ContainsEscape(), span.Range{}, kw, nil}
+			key = p.newIdent(Ident{N_NAME, p.finRng(propRng), name, false, ahead.ContainsEscape(), span.Range{}, kw, nil})
 		}
 
 		ti := p.newTypInfo(N_STMT_CLASS)
@@ -1627,6 +2139,13 @@ func (p *Parser) field(key Node, begin, static span.Range, accMod ACC_MOD, begin
 		rng = key.Range()
 	}
 
+	if p.feat&FEAT_CLASS_PUB_FIELD == 0 {
+		if msg := p.lexer.featErr(FEAT_CLASS_PUB_FIELD, ""); msg != "" {
+			return nil, p.errorAtLoc(rng, msg)
+		}
+		return nil, p.errorTok(p.lexer.Peek())
+	}
+
 	if ti != nil {
 		typAnnot, err := p.tsTypAnnot()
 		if err != nil {
@@ -1710,6 +2229,9 @@ func (p *Parser) classElemName() (Node, span.Range, error) {
 }
 
 func (p *Parser) staticBlock(static span.Range) (Node, error) {
+	if p.feat&FEAT_CLASS_STATIC_BLOCK == 0 {
+		return nil, p.errorFeat(p.lexer.Peek(), FEAT_CLASS_STATIC_BLOCK)
+	}
 	block, err := p.blockStmt(true, SPK_NONE)
 	if err != nil {
 		return nil, err
@@ -1812,7 +2334,7 @@ func (p *Parser) tryStmt() (Node, error) {
 				return nil, err
 			}
 		} else if p.feat&FEAT_OPT_CATCH_PARAM == 0 {
-			return nil, p.errorTok(ahead)
+			return nil, p.errorFeat(ahead, FEAT_OPT_CATCH_PARAM)
 		}
 
 		scope := p.symtab.EnterScope(false, false, true)
@@ -1825,7 +2347,7 @@ func (p *Parser) tryStmt() (Node, error) {
 				if ok := p.isProhibitedName(nil, id.val, true, true, false, false); ok {
 					return nil, p.errorAtLoc(id.Range(), fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, id.val))
 				}
-				ref := NewRef()
+				ref := p.newRef()
 				ref.Id = id
 				ref.BindKind = BK_LET
 				if err := p.addLocalBinding(nil, ref, true, id.val); err != nil {
@@ -2298,10 +2820,12 @@ func (p *Parser) forStmt() (Node, error) {
 	tok := p.lexer.Peek()
 	if ps.IsKind(SPK_ASYNC) && tok.value == T_AWAIT {
 		if p.feat&FEAT_ASYNC_ITERATION == 0 {
-			return nil, p.errorTok(tok)
+			return nil, p.errorFeat(tok, FEAT_ASYNC_ITERATION)
 		}
 		await = true
 		p.lexer.Next()
+	} else if tok.value == T_AWAIT && p.atModuleTopLevel(ps) {
+		return nil, p.errorFeat(tok, FEAT_GLOBAL_ASYNC)
 	}
 
 	if _, err := p.nextMustTok(T_PAREN_L); err != nil {
@@ -2320,6 +2844,11 @@ func (p *Parser) forStmt() (Node, error) {
 		if err != nil {
 			return nil, err
 		}
+	} else if ok, kind := p.aheadIsUsing(tok, true); ok {
+		init, err = p.varDecStmt(kind, true)
+		if err != nil {
+			return nil, err
+		}
 	} else if tok.value != T_SEMI {
 		init, err = p.expr()
 		if err != nil {
@@ -2339,6 +2868,8 @@ func (p *Parser) forStmt() (Node, error) {
 		return nil, p.errorTok(tok)
 	} else if isOf && !await && init.Type() == N_NAME && init.(*Ident).val == "async" {
 		return nil, p.errorAtLoc(init.Range(), ERR_LHS_OF_FOR_OF_CANNOT_ASYNC)
+	} else if isOf && p.feat&FEAT_FOR_OF == 0 {
+		return nil, p.errorFeat(tok, FEAT_FOR_OF)
 	}
 
 	if isIn || isOf {
@@ -2369,6 +2900,9 @@ func (p *Parser) forStmt() (Node, error) {
 			if len(varDec.decList) > 1 {
 				return nil, p.errorAtLoc(varDec.decList[1].Range(), ERR_DUP_BINDING)
 			}
+			if isIn && varDec.IsUsing() {
+				return nil, p.errorAtLoc(varDec.rng, ERR_USING_DEC_IN_FOR_IN)
+			}
 			if p.scope().IsKind(SPK_STRICT) {
 				for _, dec := range varDec.decList {
 					d := dec.(*VarDec)
@@ -2483,11 +3017,16 @@ func (p *Parser) forStmt() (Node, error) {
 }
 
 func (p *Parser) aheadIsAsync(tok *Token, prop bool, pvt bool) bool {
-	if p.feat&FEAT_ASYNC_AWAIT != 0 && IsName(tok, "async", true) {
+	if IsName(tok, "async", true) {
 		ahead := p.lexer.Peek2nd()
 		if ahead.afterLineTerm {
 			return false
 		}
+		// `async function` is reported by `fnDec` if the async functions are turned off by the
+		// version, the others like `async(a)` are the calls of the function named `async`
+		if p.feat&FEAT_ASYNC_AWAIT == 0 {
+			return !prop && ahead.value == T_FUNC && p.offByVersion(FEAT_ASYNC_AWAIT)
+		}
 		if ahead.value == T_FUNC ||
 			(p.aheadIsArgList(ahead) && !prop) ||
 			ahead.value == T_MUL {
@@ -2519,6 +3058,9 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 	asyncHasEscape := false
 	var asyncLoc span.Range
 	if async != nil {
+		if p.feat&FEAT_ASYNC_AWAIT == 0 {
+			return nil, p.errorFeat(async, FEAT_ASYNC_AWAIT)
+		}
 		asyncHasEscape = async.ContainsEscape()
 		rng = async.rng
 		asyncLoc = async.rng
@@ -2538,7 +3080,10 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 	genLoc := tok.rng
 	if generator {
 		if async != nil && p.feat&FEAT_ASYNC_GENERATOR == 0 {
-			return nil, p.errorTok(tok)
+			return nil, p.errorFeat(tok, FEAT_ASYNC_GENERATOR)
+		}
+		if p.feat&FEAT_GENERATOR == 0 {
+			return nil, p.errorFeat(tok, FEAT_GENERATOR)
 		}
 		p.lexer.Next()
 	}
@@ -2561,7 +3106,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 
 		// name of the function expression will not add a ref record
 		if !expr {
-			fnRef = NewRef()
+			fnRef = p.newRef()
 			fnRef.Id = id.(*Ident)
 			fnRef.BindKind = BK_VAR
 			fnRef.Typ = RDT_FN
@@ -2618,7 +3163,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 			if err := p.advanceIfSemi(true); err != nil {
 				return nil, err
 			}
-			return &ExprStmt{N_STMT_EXPR, p.finRng(rng), typArgs, false}, nil
+			return p.newExprStmt(ExprStmt{N_STMT_EXPR, p.finRng(rng), typArgs, false}), nil
 		}
 	}
 
@@ -2670,7 +3215,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 		}
 
 		for _, paramName := range paramNames {
-			ref := NewRef()
+			ref := p.newRef()
 			ref.Id = paramName.(*Ident)
 			ref.BindKind = BK_PARAM
 			// duplicate-checking for params is enable in strict and delegated to below `checkParams`
@@ -2702,7 +3247,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 		// this branch means the input is callExpr like:
 		// `async ({a: b = c})` callExpr
 		// `async* ({a: b = c})` binExpr
-		lhs := &Ident{N_NAME, asyncLoc, "async", false, asyncHasEscape, span.Range{}, true, p.newTypInfo(N_NAME)}
+		lhs := p.newIdent(Ident{N_NAME, asyncLoc, "async", false, asyncHasEscape, span.Range{}, true, p.newTypInfo(N_NAME)})
 
 		var exp Node
 		if generator {
@@ -2715,7 +3260,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 			} else {
 				rhs = &SeqExpr{N_EXPR_SEQ, p.finRng(rng), args, span.Range{}}
 			}
-			exp = &BinExpr{N_EXPR_BIN, p.finRng(rng), T_MUL, genLoc, lhs, rhs, span.Range{}}
+			exp = p.newBinExpr(BinExpr{N_EXPR_BIN, p.finRng(rng), T_MUL, genLoc, lhs, rhs, span.Range{}})
 		} else {
 			if err := p.checkArgs(args, false, true); err != nil {
 				return nil, err
@@ -2729,7 +3274,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 				}
 				ti.SetTypArgs(typArgs)
 			}
-			exp = &CallExpr{N_EXPR_CALL, p.finRng(rng), lhs, args, false, span.Range{}, ti}
+			exp = p.newCallExpr(CallExpr{N_EXPR_CALL, p.finRng(rng), lhs, args, false, span.Range{}, ti})
 		}
 
 		if !expr {
@@ -2745,7 +3290,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 			if err = p.advanceIfSemi(true); err != nil {
 				return nil, err
 			}
-			return &ExprStmt{N_STMT_EXPR, p.finRng(rng), seq, false}, nil
+			return p.newExprStmt(ExprStmt{N_STMT_EXPR, p.finRng(rng), seq, false}), nil
 		}
 		return exp, nil
 	} else {
@@ -2812,7 +3357,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 		if err := p.advanceIfSemi(true); err != nil {
 			return nil, err
 		}
-		return &ExprStmt{N_STMT_EXPR, p.finRng(rng), fn, false}, nil
+		return p.newExprStmt(ExprStmt{N_STMT_EXPR, p.finRng(rng), fn, false}), nil
 	}
 
 	typ := N_STMT_FN
@@ -2845,7 +3390,7 @@ func (p *Parser) fnDec(expr bool, async *Token, canNameOmitted bool) (Node, erro
 }
 
 func (p *Parser) collectNames(nodes []Node) (names []Node, firstComplicated span.Range, err error) {
-	names = make([]Node, 0, 5)
+	names = p.nodeList(5)
 	var ns []Node
 	for _, param := range nodes {
 		if firstComplicated.Empty() && param.Type() != N_NAME {
@@ -2987,12 +3532,19 @@ func (p *Parser) enterStrict(lex bool) *Scope {
 }
 
 func (p *Parser) stmts(terminal TokenValue) ([]Node, error) {
-	stmts := make([]Node, 0, 20)
+	stmts := p.nodeList(20)
 	prologue := 0 // the index in above `stmts` contains the last stmt in Directive Prologue
 
 	scope := p.scope()
+	top := terminal == T_ILLEGAL && scope == p.symtab.Root
+	if top && p.reused != nil && !p.reused.prologue {
+		prologue = -1
+	}
 	for {
 		tok := p.lexer.PeekStmtBegin()
+		if top && p.reused != nil && p.reused.resync(p, tok, stmts, prologue) {
+			break
+		}
 		if terminal != T_ILLEGAL {
 			if tok.value == terminal {
 				p.lexer.Next()
@@ -3004,6 +3556,10 @@ func (p *Parser) stmts(terminal TokenValue) ([]Node, error) {
 			break
 		}
 		cmts := p.lexer.takeStmtCmts()
+		var mark stmtMark
+		if top {
+			mark = p.stmtMark()
+		}
 		stmt, err := p.stmt()
 		if err != nil {
 			return nil, err
@@ -3019,9 +3575,7 @@ func (p *Parser) stmts(terminal TokenValue) ([]Node, error) {
 			if prologue != -1 && (scope.IsKind(SPK_FUNC) || scope.IsKind(SPK_GLOBAL)) {
 				strict, dir := p.isDirective(stmt)
 				if !dir {
-					if prologue == 0 {
-						prologue = -1
-					}
+					prologue = -1
 				} else {
 					stmt.(*ExprStmt).dir = true
 				}
@@ -3056,6 +3610,9 @@ func (p *Parser) stmts(terminal TokenValue) ([]Node, error) {
 				p.postCmts[stmt] = cmts
 			}
 			stmts = append(stmts, stmt)
+			if top {
+				p.marks = append(p.marks, mark)
+			}
 		}
 	}
 	return stmts, nil
@@ -3072,6 +3629,10 @@ func (p *Parser) blockStmt(newScope bool, scopeKind ScopeKind) (*BlockStmt, erro
 		fn := scopeKind == SPK_TS_MODULE
 		scope = p.symtab.EnterScope(fn, false, true)
 		scope.AddKind(scopeKind)
+		// the nested namespace has its own exports
+		if fn {
+			scope.EraseKind(SPK_TS_MODULE_INDIRECT)
+		}
 	}
 	rng := tok.rng
 
@@ -3091,46 +3652,49 @@ func (p *Parser) blockStmt(newScope bool, scopeKind ScopeKind) (*BlockStmt, erro
 		}
 		p.symtab.LeaveScope()
 	}
-	return &BlockStmt{N_STMT_BLOCK, p.finRng(rng), stmts, newScope}, nil
+	return p.newBlockStmt(BlockStmt{N_STMT_BLOCK, p.finRng(rng), stmts, newScope}), nil
 }
 
 func (p *Parser) aheadIsVarDec(tok *Token) (bool, TokenValue) {
 	if tok.value == T_VAR {
 		return true, T_VAR
 	}
-	if p.feat&FEAT_LET_CONST != 0 {
-		var ok bool
-		var v TokenValue
+	// the lexical declarations are reported by `varDecStmt` if they are turned off by the
+	// version, `let` is an identifier if they are turned off explicitly
+	if p.feat&FEAT_LET_CONST == 0 && !p.offByVersion(FEAT_LET_CONST) {
+		return false, T_ILLEGAL
+	}
+	var ok bool
+	var v TokenValue
 
-		if tok.value == T_LET || tok.value == T_CONST {
-			ok = true
-			v = tok.value
-		} else if IsName(tok, "let", false) {
-			ok = true
-			v = T_LET
-		} else if IsName(tok, "const", false) {
-			ok = true
-			v = T_CONST
-		}
+	if tok.value == T_LET || tok.value == T_CONST {
+		ok = true
+		v = tok.value
+	} else if IsName(tok, "let", false) {
+		ok = true
+		v = T_LET
+	} else if IsName(tok, "const", false) {
+		ok = true
+		v = T_CONST
+	}
 
-		if !ok {
-			return false, T_ILLEGAL
-		}
+	if !ok {
+		return false, T_ILLEGAL
+	}
 
-		if p.scope().IsKind(SPK_STRICT) {
-			return true, v
-		}
+	if p.scope().IsKind(SPK_STRICT) {
+		return true, v
+	}
 
-		// an additional lookahead is needed to judge the various:
-		// - `let + 1`
-		// - `let a`
-		ahead := p.lexer.PeekGrow()
-		av := ahead.value
-		if !ahead.afterLineTerm && (av == T_NAME ||
-			(av > T_CTX_KEYWORD_BEGIN && av < T_CTX_KEYWORD_END) ||
-			av == T_BRACE_L || av == T_BRACKET_L) {
-			return true, v
-		}
+	// an additional lookahead is needed to judge the various:
+	// - `let + 1`
+	// - `let a`
+	ahead := p.lexer.PeekGrow()
+	av := ahead.value
+	if !ahead.afterLineTerm && (av == T_NAME ||
+		(av > T_CTX_KEYWORD_BEGIN && av < T_CTX_KEYWORD_END) ||
+		av == T_BRACE_L || av == T_BRACKET_L) {
+		return true, v
 	}
 	return false, T_ILLEGAL
 }
@@ -3152,11 +3716,16 @@ func (p *Parser) addLocalBinding(s *Scope, ref *Ref, checkDup bool, name string)
 // https://tc39.es/ecma262/multipage/ecmascript-language-statements-and-declarations.html#prod-VariableStatement
 func (p *Parser) varDecStmt(kind TokenValue, asExpr bool) (Node, error) {
 	rng := p.rng()
+
+	if (kind == T_LET || kind == T_CONST) && p.feat&FEAT_LET_CONST == 0 {
+		return nil, p.errorFeat(p.lexer.Peek(), FEAT_LET_CONST)
+	}
 	p.lexer.Next()
 
-	node := &VarDecStmt{N_STMT_VAR_DEC, span.Range{}, T_ILLEGAL, make([]Node, 0, 5), nil}
+	node := p.newVarDecStmt(VarDecStmt{N_STMT_VAR_DEC, span.Range{}, T_ILLEGAL, p.nodeList(5), nil})
 
 	isConst := false
+	using := false
 	node.kind = kind
 	bindKind := BK_VAR
 	if kind == T_LET {
@@ -3164,6 +3733,12 @@ func (p *Parser) varDecStmt(kind TokenValue, asExpr bool) (Node, error) {
 	} else if kind == T_CONST {
 		isConst = true
 		bindKind = BK_CONST
+	} else if kind == T_USING || kind == T_AWAIT_USING {
+		using = true
+		bindKind = BK_USING
+		if err := p.checkUsing(kind, rng); err != nil {
+			return nil, err
+		}
 	}
 
 	if p.aheadIsTsEnum(nil) {
@@ -3181,6 +3756,14 @@ func (p *Parser) varDecStmt(kind TokenValue, asExpr bool) (Node, error) {
 		if !p.dts && isConst && dec.init == nil && !p.scope().IsKind(SPK_NOT_IN) && !(p.ts && p.scope().IsKind(SPK_TS_DECLARE)) {
 			return nil, p.errorAtLoc(dec.rng, ERR_CONST_DEC_INIT_REQUIRED)
 		}
+		if using {
+			if dec.id.Type() != N_NAME {
+				return nil, p.errorAtLoc(dec.id.Range(), ERR_USING_DEC_BINDING_PATTERN)
+			}
+			if dec.init == nil && !p.scope().IsKind(SPK_NOT_IN) {
+				return nil, p.errorAtLoc(dec.rng, ERR_USING_DEC_INIT_REQUIRED)
+			}
+		}
 
 		node.decList = append(node.decList, dec)
 		if p.lexer.Peek().value == T_COMMA {
@@ -3202,7 +3785,7 @@ func (p *Parser) varDecStmt(kind TokenValue, asExpr bool) (Node, error) {
 			return nil, p.errorAtLoc(id.rng, fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, id.val))
 		}
 
-		ref := NewRef()
+		ref := p.newRef()
 		ref.Id = id
 		ref.Dec = node
 		ref.BindKind = bindKind
@@ -3220,6 +3803,67 @@ func (p *Parser) varDecStmt(kind TokenValue, asExpr bool) (Node, error) {
 	return node, nil
 }
 
+// the `using` and `await using` declarations of the explicit resource management, there should
+// not be line terminators between the keywords and the binding identifier, `using of` in the
+// head of `for` is the lhs of `for-of` like `for (using of x)` unless it's followed by `=` or `of`
+func (p *Parser) aheadIsUsing(tok *Token, inFor bool) (bool, TokenValue) {
+	// the declarations are reported by `checkUsing` if they are turned off by the version,
+	// `using` is an identifier if they are turned off explicitly
+	if p.feat&FEAT_USING == 0 && !p.offByVersion(FEAT_USING) {
+		return false, T_ILLEGAL
+	}
+
+	kind := T_USING
+	var ahead *Token
+	if tok.value == T_AWAIT {
+		ahead = p.lexer.Peek2nd()
+		if ahead.afterLineTerm || !IsName(ahead, "using", false) {
+			return false, T_ILLEGAL
+		}
+		kind = T_AWAIT_USING
+		ahead = p.lexer.Peek3rd()
+	} else if IsName(tok, "using", false) {
+		ahead = p.lexer.Peek2nd()
+	} else {
+		return false, T_ILLEGAL
+	}
+
+	av := ahead.value
+	if ahead.afterLineTerm || !(av == T_NAME || av > T_CTX_KEYWORD_BEGIN && av < T_CTX_KEYWORD_END) ||
+		IsName(ahead, "in", false) || IsName(ahead, "instanceof", false) {
+		return false, T_ILLEGAL
+	}
+	if inFor && kind == T_USING && IsName(ahead, "of", false) {
+		ahead3rd := p.lexer.Peek3rd()
+		if ahead3rd.value != T_ASSIGN && !IsName(ahead3rd, "of", false) {
+			return false, T_ILLEGAL
+		}
+	}
+	return true, kind
+}
+
+// the `using` declarations are permitted in the blocks, the function bodies, the heads of
+// `for` and the top level of modules, `await using` is further required to be in async context
+func (p *Parser) checkUsing(kind TokenValue, rng span.Range) error {
+	if p.feat&FEAT_USING == 0 {
+		return p.errorFeatAt(rng, FEAT_USING)
+	}
+	if kind == T_AWAIT_USING {
+		p.lexer.Next() // consume `using`
+	}
+	scope := p.scope()
+	if scope.IsKind(SPK_GLOBAL) && p.feat&FEAT_MODULE == 0 {
+		return p.errorAtLoc(rng, ERR_USING_DEC_AT_SCRIPT_TOP_LEVEL)
+	}
+	if scope.IsKind(SPK_SWITCH) {
+		return p.errorAtLoc(rng, ERR_USING_DEC_IN_SWITCH_CASE)
+	}
+	if kind == T_AWAIT_USING && !scope.IsKind(SPK_ASYNC) {
+		return p.errorAtLoc(rng, ERR_AWAIT_OUTSIDE_ASYNC)
+	}
+	return nil
+}
+
 func (p *Parser) varDec(lexical bool) (*VarDec, error) {
 	scope := p.scope()
 	if lexical {
@@ -3233,6 +3877,14 @@ func (p *Parser) varDec(lexical bool) (*VarDec, error) {
 	rng := binding.Range()
 	scope.EraseKind(SPK_LEXICAL_DEC)
 
+	// the definite assignment assertion `let x!: number`
+	if p.ts && !p.flow && binding.Type() == N_NAME && p.lexer.Peek().value == T_NOT {
+		not := p.lexer.Next().rng
+		if ti := binding.(*Ident).TypInfo(); ti != nil {
+			ti.SetNot(not)
+		}
+	}
+
 	typAnnot, err := p.tsTypAnnot()
 	if err != nil {
 		return nil, err
@@ -3252,7 +3904,7 @@ func (p *Parser) varDec(lexical bool) (*VarDec, error) {
 		return nil, p.errorAtLoc(p.rng(), ERR_COMPLEX_BINDING_MISSING_INIT)
 	}
 
-	return &VarDec{N_VAR_DEC, p.finRng(rng), binding, init}, nil
+	return p.newVarDec(VarDec{N_VAR_DEC, p.finRng(rng), binding, init}), nil
 }
 
 var prohibitedNames = map[string]bool{
@@ -3312,7 +3964,7 @@ func (p *Parser) identStrict(scope *Scope, forceStrict bool, binding bool) (Node
 		}
 	}
 
-	return &Ident{N_NAME, rng, name, false, tok.ContainsEscape(), span.Range{}, tok.IsKw(), p.newTypInfo(N_NAME)}, nil
+	return p.newIdent(Ident{N_NAME, rng, name, false, tok.ContainsEscape(), span.Range{}, tok.IsKw(), p.newTypInfo(N_NAME)}), nil
 }
 
 func (p *Parser) ident(scope *Scope, binding bool) (*Ident, error) {
@@ -3325,14 +3977,15 @@ func (p *Parser) ident(scope *Scope, binding bool) (*Ident, error) {
 
 func (p *Parser) accMod() (accMod ACC_MOD, accLoc span.Range, abstractLoc, readonlyLoc, overrideLoc, declareLoc span.Range, beginLoc span.Range,
 	isField, escape bool, name string, fieldLoc span.Range, err error) {
-	if !p.ts {
+	if !p.ts || p.flow {
 		return
 	}
 
 	var staticLoc span.Range
 	var mayStaticBlock bool
-	beginLoc, staticLoc, accLoc, abstractLoc, readonlyLoc, overrideLoc, declareLoc, isField, escape, name, fieldLoc, accMod, _, mayStaticBlock = p.modifiers()
-	if err = p.tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accLoc, abstractLoc, declareLoc, accMod, mayStaticBlock); err != nil {
+	var accessorLoc span.Range
+	beginLoc, staticLoc, accLoc, abstractLoc, readonlyLoc, overrideLoc, declareLoc, accessorLoc, isField, escape, name, fieldLoc, accMod, _, mayStaticBlock = p.modifiers()
+	if err = p.tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accLoc, abstractLoc, declareLoc, accessorLoc, accMod, mayStaticBlock); err != nil {
 		return
 	}
 
@@ -3340,6 +3993,10 @@ func (p *Parser) accMod() (accMod ACC_MOD, accLoc span.Range, abstractLoc, reado
 		err = p.errorAtLoc(staticLoc, ERR_UNEXPECTED_TOKEN)
 		return
 	}
+	if !accessorLoc.Empty() {
+		err = p.errorAtLoc(accessorLoc, ERR_UNEXPECTED_TOKEN)
+		return
+	}
 	if !abstractLoc.Empty() {
 		err = p.errorAtLoc(abstractLoc, ERR_UNEXPECTED_TOKEN)
 		return
@@ -3355,7 +4012,7 @@ func (p *Parser) roughParam(ctor bool) (Node, error) {
 
 	var name Node
 	if isField {
-		name = &Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, false, p.newTypInfo(N_NAME)}
+		name = p.newIdent(Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, false, p.newTypInfo(N_NAME)})
 	} else {
 		if p.ts && !ctor {
 			if accMod != ACC_MOD_NONE {
@@ -3408,7 +4065,7 @@ func (p *Parser) param(methodKind PropKind) (Node, error) {
 	var binding Node
 	var this bool
 	if isField {
-		binding = &Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, false, p.newTypInfo(N_NAME)}
+		binding = p.newIdent(Ident{N_NAME, fieldLoc, fieldName, false, escape, span.Range{}, false, p.newTypInfo(N_NAME)})
 	} else {
 		if p.ts {
 			if accMod != ACC_MOD_NONE {
@@ -3435,7 +4092,7 @@ func (p *Parser) param(methodKind PropKind) (Node, error) {
 				return nil, p.errorAt(ahead.value, ahead.rng, ERR_GETTER_SETTER_WITH_THIS_PARAM)
 			}
 			rng := p.lexer.Next().rng
-			binding = &Ident{N_NAME, p.finRng(rng), "this", false, false, span.Range{}, true, p.newTypInfo(N_NAME)}
+			binding = p.newIdent(Ident{N_NAME, p.finRng(rng), "this", false, false, span.Range{}, true, p.newTypInfo(N_NAME)})
 		} else {
 			binding, err = p.bindingPattern()
 			if err != nil {
@@ -3466,6 +4123,9 @@ func (p *Parser) param(methodKind PropKind) (Node, error) {
 
 	// default value
 	if !this && p.lexer.Peek().value == T_ASSIGN {
+		if p.feat&FEAT_DEFAULT_PARAM == 0 {
+			return nil, p.errorFeat(p.lexer.Peek(), FEAT_DEFAULT_PARAM)
+		}
 		p.lexer.Next()
 		value, err := p.assignExpr(true, false, false, false)
 		if err != nil {
@@ -3510,7 +4170,7 @@ func (p *Parser) paramList(firstRough bool, methodKind PropKind, typParams bool)
 	var err error
 	var tp Node
 	if typParams {
-		tp, err = p.tsTryTypParams()
+		tp, err = p.tsTryTypParams(false)
 		if err != nil {
 			return nil, nil, span.Range{}, err
 		}
@@ -3525,7 +4185,7 @@ func (p *Parser) paramList(firstRough bool, methodKind PropKind, typParams bool)
 	}
 	parenLoc := parenL.rng
 
-	params := make([]Node, 0, 5)
+	params := p.nodeList(5)
 	i := 0
 	for {
 		tok := p.lexer.Peek()
@@ -3608,7 +4268,7 @@ func (p *Parser) bindingPattern() (Node, error) {
 	var err error
 
 	if p.feat&FEAT_BINDING_PATTERN == 0 && (tv == T_BRACE_L || tv == T_BRACKET_L) {
-		return nil, p.errorTok(tok)
+		return nil, p.errorFeat(tok, FEAT_BINDING_PATTERN)
 	}
 
 	if tv == T_BRACE_L {
@@ -3670,7 +4330,10 @@ func (p *Parser) patternObj() (Node, error) {
 }
 
 func (p *Parser) patternProp() (Node, error) {
-	if p.lexer.Peek().value == T_DOT_TRI {
+	if tok := p.lexer.Peek(); tok.value == T_DOT_TRI {
+		if p.feat&FEAT_OBJ_REST_SPREAD == 0 {
+			return nil, p.errorFeat(tok, FEAT_OBJ_REST_SPREAD)
+		}
 		binding, err := p.patternRest(false, false)
 		if err != nil {
 			return nil, err
@@ -3725,10 +4388,6 @@ func (p *Parser) patternProp() (Node, error) {
 func (p *Parser) isField(static bool, getter bool) (bool, *Token) {
 	ahead := p.lexer.Peek()
 	av := ahead.value
-	if p.feat&FEAT_CLASS_PUB_FIELD == 0 {
-		return false, ahead
-	}
-
 	isField := av == T_COLON ||
 		av == T_ASSIGN ||
 		av == T_SEMI ||
@@ -3763,16 +4422,19 @@ func (p *Parser) propName(allowNamePVT bool, maybeMethod bool, tsRough bool) (No
 	var computeLoc span.Range
 	tv := tok.value
 	if allowNamePVT && tv == T_NAME_PVT {
-		key = &Ident{N_NAME, p.finRng(rng), p.TokText(tok), true, tok.ContainsEscape(), span.Range{}, false, p.newTypInfo(N_NAME)}
+		key = p.newIdent(Ident{N_NAME, p.finRng(rng), p.TokText(tok), true, tok.ContainsEscape(), span.Range{}, false, p.newTypInfo(N_NAME)})
 	} else if tv == T_STRING {
 		legacyOctalEscapeSeq := tok.HasLegacyOctalEscapeSeq()
 		if p.scope().IsKind(SPK_STRICT) && legacyOctalEscapeSeq {
 			return nil, span.Range{}, p.errorAtLoc(tok.rng, ERR_LEGACY_OCTAL_ESCAPE_IN_STRICT_MODE)
 		}
-		key = &StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, p.newTypInfo(N_LIT_STR)}
+		key = p.newStrLit(StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, p.newTypInfo(N_LIT_STR)})
 	} else if tv == T_NUM {
-		key = &NumLit{N_LIT_NUM, p.finRng(rng), span.Range{}}
+		key = p.newNumLit(NumLit{N_LIT_NUM, p.finRng(rng), span.Range{}})
 	} else if tv == T_BRACKET_L {
+		if p.feat&FEAT_COMPUTED_KEY == 0 {
+			return nil, span.Range{}, p.errorFeat(tok, FEAT_COMPUTED_KEY)
+		}
 		computeLoc = tok.rng
 		scope.AddKind(SPK_PROP_NAME)
 		name, err := p.assignExpr(true, false, false, false)
@@ -3795,7 +4457,7 @@ func (p *Parser) propName(allowNamePVT bool, maybeMethod bool, tsRough bool) (No
 				return nil, span.Range{}, p.errorAtLoc(rng, fmt.Sprintf(ERR_TPL_FORBIDDEN_LEXICAL_NAME, keyName))
 			}
 		}
-		key = &Ident{N_NAME, p.finRng(rng), keyName, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}
+		key = p.newIdent(Ident{N_NAME, p.finRng(rng), keyName, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)})
 	} else {
 		return nil, span.Range{}, p.errorTok(tok)
 	}
@@ -3877,6 +4539,11 @@ func (p *Parser) elision() []Node {
 }
 
 func (p *Parser) bindingElem(asProp bool) (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	tok := p.lexer.Peek()
 	var binding Node
 	var err error
@@ -3930,7 +4597,7 @@ func (p *Parser) patternRest(arrPat bool, allowNotLast bool) (Node, error) {
 	tok := p.lexer.Next()
 
 	if p.feat&FEAT_BINDING_REST_ELEM == 0 {
-		return nil, p.errorTok(tok)
+		return nil, p.errorFeat(tok, FEAT_BINDING_REST_ELEM)
 	}
 
 	ahead := p.lexer.Peek()
@@ -3970,7 +4637,7 @@ func (p *Parser) patternRest(arrPat bool, allowNotLast bool) (Node, error) {
 
 func (p *Parser) exprStmt() (Node, error) {
 	rng := p.rng()
-	stmt := &ExprStmt{N_STMT_EXPR, span.Range{}, nil, false}
+	stmt := p.newExprStmt(ExprStmt{N_STMT_EXPR, span.Range{}, nil, false})
 	expr, err := p.expr()
 	if err != nil {
 		return nil, err
@@ -4070,6 +4737,11 @@ func (p *Parser) yieldExpr() (Node, error) {
 
 // https://tc39.es/ecma262/multipage/ecmascript-language-expressions.html#prod-AssignmentExpression
 func (p *Parser) assignExpr(checkLhs bool, notGT bool, notHook bool, notColon bool) (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	if p.aheadIsYield() {
 		return p.yieldExpr()
 	}
@@ -4134,7 +4806,7 @@ func (p *Parser) assignExpr(checkLhs bool, notGT bool, notHook bool, notColon bo
 		return nil, err
 	}
 
-	node := &AssignExpr{N_EXPR_ASSIGN, p.finRng(rng), op, opLoc, lhs, rhs, span.Range{}, p.newTypInfo(N_EXPR_ASSIGN)}
+	node := p.newAssignExpr(AssignExpr{N_EXPR_ASSIGN, p.finRng(rng), op, opLoc, lhs, rhs, span.Range{}, p.newTypInfo(N_EXPR_ASSIGN)})
 	return node, nil
 }
 
@@ -4169,7 +4841,7 @@ func (p *Parser) isSimpleLVal(expr Node, pat bool, inParen bool, member bool, op
 		return p.isSimpleLVal(node.expr, pat, true, false, optAssign)
 	case N_EXPR_BIN:
 		node := expr.(*BinExpr)
-		return node.op == T_TS_AS
+		return isTsTypBin(node.op)
 	case N_TS_NO_NULL, N_TS_TYP_ASSERT:
 		return true
 	}
@@ -4243,6 +4915,22 @@ func (p *Parser) condExpr(test Node, notGT bool, notHook bool, notColon bool) (N
 	return node, nil
 }
 
+// whether the scope is in the top level of the module rather than in the functions or the
+// classes, `await` is available there if the top-level await is supported
+func (p *Parser) atModuleTopLevel(scope *Scope) bool {
+	return p.feat&FEAT_MODULE != 0 && !scope.IsKind(SPK_FUNC) && !scope.IsKind(SPK_FUNC_INDIRECT) &&
+		!scope.IsKind(SPK_CLASS) && !scope.IsKind(SPK_CLASS_INDIRECT)
+}
+
+// the version requirement of the top-level await is reported if it's turned off by the target
+// version, otherwise `await` is reported as being outside of the async functions
+func (p *Parser) errorAwaitOutsideAsync(tok *Token, scope *Scope) *ParserError {
+	if msg := p.lexer.featErr(FEAT_GLOBAL_ASYNC, ""); msg != "" && p.atModuleTopLevel(scope) {
+		return p.errorAtLoc(tok.rng, msg)
+	}
+	return p.errorAt(tok.value, tok.rng, ERR_AWAIT_OUTSIDE_ASYNC)
+}
+
 // https://tc39.es/ecma262/multipage/ecmascript-language-functions-and-classes.html#prod-AwaitExpression
 func (p *Parser) awaitExpr(tok *Token) (Node, error) {
 	rng := tok.rng
@@ -4255,24 +4943,27 @@ func (p *Parser) awaitExpr(tok *Token) (Node, error) {
 			if ahead.value == T_PAREN_R || ahead.value == T_COMMA {
 				return nil, p.errorAtLoc(rng, fmt.Sprintf(ERR_TPL_BINDING_RESERVED_WORD, "await"))
 			} else if !scope.IsKind(SPK_ASYNC) {
-				return nil, p.errorAt(tok.value, tok.rng, ERR_AWAIT_OUTSIDE_ASYNC)
+				return nil, p.errorAwaitOutsideAsync(tok, scope)
 			}
 			return nil, p.errorTok(ahead)
 		}
-		return &Ident{N_NAME, p.finRng(rng), "await", false, tok.ContainsEscape(), span.Range{}, true, p.newTypInfo(N_NAME)}, nil
+		return p.newIdent(Ident{N_NAME, p.finRng(rng), "await", false, tok.ContainsEscape(), span.Range{}, true, p.newTypInfo(N_NAME)}), nil
 	}
 
 	if scope.IsKind(SPK_FORMAL_PARAMS) {
 		return nil, p.errorAt(tok.value, tok.rng, ERR_AWAIT_IN_FORMAL_PARAMS)
 	}
 	if !scope.IsKind(SPK_ASYNC) {
-		return nil, p.errorAt(tok.value, tok.rng, ERR_AWAIT_OUTSIDE_ASYNC)
+		return nil, p.errorAwaitOutsideAsync(tok, scope)
 	}
 
-	arg, err := p.unaryExpr(nil, span.Range{}, false)
+	arg, err := p.unaryArg(false)
 	if err != nil {
 		return nil, err
 	}
+	if isPvtName(arg) {
+		return nil, p.errorAtLoc(arg.Range(), ERR_UNEXPECTED_PVT_FIELD)
+	}
 	return &UnaryExpr{N_EXPR_UNARY, p.finRng(rng), T_AWAIT, arg, span.Range{}}, nil
 }
 
@@ -4299,7 +4990,7 @@ func (p *Parser) unaryExpr(typArgs Node, typArgsLoc span.Range, notColon bool) (
 		if op != T_LT {
 			p.lexer.Next()
 		}
-		arg, err := p.unaryExpr(nil, span.Range{}, notColon)
+		arg, err := p.unaryArg(notColon)
 		if err != nil {
 			return nil, err
 		}
@@ -4310,6 +5001,9 @@ func (p *Parser) unaryExpr(typArgs Node, typArgsLoc span.Range, notColon bool) (
 		if arg.Type() == N_EXPR_ARROW {
 			return nil, p.errorAtLoc(arg.Range(), ERR_MALFORMED_ARROW_PARAM)
 		}
+		if isPvtName(arg) {
+			return nil, p.errorAtLoc(arg.Range(), ERR_UNEXPECTED_PVT_FIELD)
+		}
 
 		scope := p.scope()
 		if scope.IsKind(SPK_STRICT) && tok.value == T_DELETE && arg.Type() == N_NAME {
@@ -4347,7 +5041,7 @@ func (p *Parser) unaryExpr(typArgs Node, typArgsLoc span.Range, notColon bool) (
 
 	if tok.value == T_AWAIT {
 		if p.feat&FEAT_ASYNC_AWAIT == 0 {
-			return nil, p.errorTok(tok)
+			return nil, p.errorFeat(tok, FEAT_ASYNC_AWAIT)
 		}
 		p.lexer.Next()
 		return p.awaitExpr(tok)
@@ -4356,20 +5050,32 @@ func (p *Parser) unaryExpr(typArgs Node, typArgsLoc span.Range, notColon bool) (
 	return p.updateExpr(typArgs, typArgsLoc, notColon)
 }
 
+// parses the operand of the unary operators, it's guarded by `enter` since the operators can
+// be nested arbitrarily
+func (p *Parser) unaryArg(notColon bool) (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+	return p.unaryExpr(nil, span.Range{}, notColon)
+}
+
 // https://tc39.es/ecma262/multipage/ecmascript-language-expressions.html#prod-UpdateExpression
 func (p *Parser) updateExpr(typArgs Node, typArgsLoc span.Range, notColon bool) (Node, error) {
 	rng := p.rng()
 	tok := p.lexer.Peek()
 	if tok.value == T_INC || tok.value == T_DEC {
+		// the token is reused by the lexer after it's consumed, so its value is saved here
+		op := tok.value
 		p.lexer.Next()
-		arg, err := p.unaryExpr(nil, span.Range{}, notColon)
+		arg, err := p.unaryArg(notColon)
 		if err != nil {
 			return nil, err
 		}
 		if !p.isSimpleLVal(arg, true, false, true, false) {
 			return nil, p.errorAtLoc(arg.Range(), ERR_ASSIGN_TO_RVALUE)
 		}
-		ud := &UpdateExpr{N_EXPR_UPDATE, p.finRng(rng), tok.value, true, arg, span.Range{}}
+		ud := &UpdateExpr{N_EXPR_UPDATE, p.finRng(rng), op, true, arg, span.Range{}}
 		arg, err = p.tsTypAssert(ud, typArgs)
 		if err != nil {
 			return nil, err
@@ -4444,6 +5150,11 @@ func (p *Parser) lhs(notColon bool) (Node, error) {
 
 // https://tc39.es/ecma262/multipage/ecmascript-language-expressions.html#prod-NewExpression
 func (p *Parser) newExpr() (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	rng := p.rng()
 	new := p.lexer.Next()
 
@@ -4452,8 +5163,13 @@ func (p *Parser) newExpr() (Node, error) {
 
 	scope := p.scope()
 	tok := p.lexer.Peek()
+	if tok.value == T_DOT {
+		if err := p.errorVersion(new.rng, FEAT_META_PROPERTY); err != nil {
+			return nil, err
+		}
+	}
 	if tok.value == T_DOT && p.feat&FEAT_META_PROPERTY != 0 {
-		meta := &Ident{N_NAME, p.finRng(new.rng), "new", false, new.ContainsEscape(), span.Range{}, true, p.newTypInfo(N_NAME)}
+		meta := p.newIdent(Ident{N_NAME, p.finRng(new.rng), "new", false, new.ContainsEscape(), span.Range{}, true, p.newTypInfo(N_NAME)})
 		p.lexer.Next() // consume dot
 
 		id, err := p.ident(nil, false)
@@ -4683,7 +5399,7 @@ func (p *Parser) callExpr(callee Node, root bool, directOpt bool, opt span.Range
 					return nil, span.Range{}, p.errorAtLoc(typArgs.Range(), ERR_UNEXPECTED_TOKEN)
 				}
 			} else {
-				callee = &CallExpr{N_EXPR_CALL, p.finRng(rng), callee, args, directOpt, span.Range{}, ti}
+				callee = p.newCallExpr(CallExpr{N_EXPR_CALL, p.finRng(rng), callee, args, directOpt, span.Range{}, ti})
 			}
 		} else if tv == T_BRACKET_L || tv == T_DOT || tv == T_OPT_CHAIN {
 			callee, fo, err = p.memberExpr(callee, true, root, firstOpt)
@@ -4718,10 +5434,15 @@ func (p *Parser) importCall(tok *Token) (Node, error) {
 	}
 	rng := tok.rng
 
-	meta := &Ident{N_NAME, p.finRng(tok.rng), p.TokText(tok), false, tok.ContainsEscape(), span.Range{}, false, p.newTypInfo(N_NAME)}
+	meta := p.newIdent(Ident{N_NAME, p.finRng(tok.rng), p.TokText(tok), false, tok.ContainsEscape(), span.Range{}, false, p.newTypInfo(N_NAME)})
 
 	ahead := p.lexer.Peek()
-	if ahead.value == T_DOT && p.feat&FEAT_META_PROPERTY != 0 {
+	if ahead.value == T_DOT {
+		if err := p.errorVersion(tok.rng, FEAT_IMPORT_META); err != nil {
+			return nil, err
+		}
+	}
+	if ahead.value == T_DOT && p.feat&FEAT_META_PROPERTY != 0 && p.feat&FEAT_IMPORT_META != 0 {
 		p.lexer.Next()
 		prop, err := p.ident(nil, false)
 		if err != nil {
@@ -4746,24 +5467,46 @@ func (p *Parser) importCall(tok *Token) (Node, error) {
 		return &MetaProp{N_META_PROP, p.finRng(rng), meta, prop}, nil
 	}
 
+	if ahead.value == T_PAREN_L {
+		if err := p.errorVersion(tok.rng, FEAT_DYNAMIC_IMPORT); err != nil {
+			return nil, err
+		}
+	}
 	if ahead.value == T_PAREN_L && p.feat&FEAT_DYNAMIC_IMPORT != 0 {
 		p.lexer.Next()
 		src, err := p.assignExpr(true, false, false, false)
 		if err != nil {
 			return nil, err
 		}
-		_, err = p.nextMustTok(T_PAREN_R)
-		if err != nil {
+
+		// the optional second argument is the options like `{ with: { type: "json" } }`, the
+		// trailing comma is permitted as well as the ordinary calls
+		var opts Node
+		if tok := p.lexer.Peek(); tok.value == T_COMMA {
+			if p.feat&FEAT_IMPORT_ATTRS == 0 {
+				return nil, p.errorFeat(tok, FEAT_IMPORT_ATTRS)
+			}
+			p.lexer.Next()
+			if p.lexer.Peek().value != T_PAREN_R {
+				opts, err = p.assignExpr(true, false, false, false)
+				if err != nil {
+					return nil, err
+				}
+				if p.lexer.Peek().value == T_COMMA {
+					p.lexer.Next()
+					if ahead := p.lexer.Peek(); ahead.value != T_PAREN_R {
+						return nil, p.errorAtLoc(ahead.rng, ERR_DYNAMIC_IMPORT_ARGS)
+					}
+				}
+			}
+		}
+		if _, err = p.nextMustTok(T_PAREN_R); err != nil {
 			return nil, err
 		}
 
-		call := &ImportCall{N_IMPORT_CALL, p.finRng(rng), src, span.Range{}}
+		call := &ImportCall{N_IMPORT_CALL, p.finRng(rng), src, opts, span.Range{}}
 		ahead := p.lexer.Peek()
-		semi := ahead.value == T_SEMI
-		if semi || ahead.afterLineTerm {
-			if semi {
-				p.lexer.Next()
-			}
+		if ahead.value == T_SEMI || ahead.afterLineTerm {
 			return call, nil
 		}
 
@@ -4773,6 +5516,9 @@ func (p *Parser) importCall(tok *Token) (Node, error) {
 }
 
 func (p *Parser) tplExpr(tag Node, ts bool) (Node, error) {
+	if p.feat&FEAT_TPL_LIT == 0 {
+		return nil, p.errorFeat(p.lexer.Peek(), FEAT_TPL_LIT)
+	}
 
 	if tag != nil {
 		if tag.Type() == N_EXPR_CHAIN {
@@ -4800,7 +5546,7 @@ func (p *Parser) tplExpr(tag Node, ts bool) (Node, error) {
 			}
 
 			p.lexer.Next()
-			str := &StrLit{N_LIT_STR, span.Range{Lo: ext.strRng.Lo, Hi: ext.strRng.Hi}, cooked, false, span.Range{}, nil}
+			str := p.newStrLit(StrLit{N_LIT_STR, span.Range{Lo: ext.strRng.Lo, Hi: ext.strRng.Hi}, cooked, false, span.Range{}, nil})
 			elems = append(elems, str)
 
 			if tok.value == T_TPL_TAIL || tok.IsPlainTpl() {
@@ -5148,7 +5894,7 @@ func (p *Parser) argToParam(arg Node, depth int, prop bool, destruct bool, inPar
 		sub := arg.(*ParenExpr).expr
 		if !destruct || !p.isPrimitive(sub) && !p.isTsLhs(sub) {
 			st := sub.Type()
-			if !(destruct && st == N_EXPR_BIN && sub.(*BinExpr).op == T_TS_AS) {
+			if !(destruct && st == N_EXPR_BIN && isTsTypBin(sub.(*BinExpr).op)) {
 				if st != N_LIT_ARR && st != N_LIT_OBJ && st != N_NAME {
 					return nil, p.errorAtLoc(sub.Range(), ERR_ASSIGN_TO_RVALUE)
 				}
@@ -5188,7 +5934,7 @@ func (p *Parser) argToParam(arg Node, depth int, prop bool, destruct bool, inPar
 		}
 	case N_EXPR_BIN:
 		n := arg.(*BinExpr)
-		if destruct && depth > 0 && n.op == T_TS_AS {
+		if destruct && depth > 0 && isTsTypBin(n.op) {
 			if inParen {
 				// however `foo as any = 10;` is illegal
 				return n, nil
@@ -5365,7 +6111,7 @@ func (p *Parser) argList(check bool, incall bool, asyncLoc span.Range, noJsx boo
 	p.lexer.Next()
 
 	var tailingComma span.Range
-	args := make([]Node, 0, 5)
+	args := p.nodeList(5)
 	for {
 		tok := p.lexer.Peek()
 		if tok.value == T_PAREN_R {
@@ -5380,6 +6126,18 @@ func (p *Parser) argList(check bool, incall bool, asyncLoc span.Range, noJsx boo
 
 		ahead := p.lexer.Peek()
 		av := ahead.value
+
+		// the flow type casts like `(a.b: T)`, the ones can have type annotations like `(a: T)`
+		// are already processed by `assignExpr`
+		if p.flow && !incall && av == T_COLON {
+			arg, err = p.flowTypCast(arg)
+			if err != nil {
+				return nil, span.Range{}, nil, nil, err
+			}
+			ahead = p.lexer.Peek()
+			av = ahead.value
+		}
+
 		if av == T_COMMA {
 			tok := p.lexer.Next()
 			// trailing comma is need to be checked when it's in
@@ -5434,7 +6192,7 @@ func (p *Parser) arg() (Node, error) {
 
 func (p *Parser) checkOp(tok *Token) error {
 	if tok.value == T_POW && p.feat&FEAT_POW == 0 {
-		return p.errorTok(tok)
+		return p.errorFeat(tok, FEAT_POW)
 	}
 	return nil
 }
@@ -5486,10 +6244,12 @@ func (p *Parser) binExpr(lhs Node, minPcd int, logic bool, nullish bool, notGT b
 		opLoc := p.lexer.Next().rng
 
 		var rhs Node
-		if op != T_TS_AS {
-			rhs, err = p.unaryExpr(nil, span.Range{}, false)
-		} else {
+		if op == T_TS_AS {
 			rhs, err = p.tsTyp(false, true, false)
+		} else if op == T_TS_SATISFIES {
+			rhs, err = p.tsTyp(false, false, false)
+		} else {
+			rhs, err = p.unaryExpr(nil, span.Range{}, false)
 		}
 		if err != nil {
 			return nil, err
@@ -5520,7 +6280,12 @@ func (p *Parser) binExpr(lhs Node, minPcd int, logic bool, nullish bool, notGT b
 			return nil, p.errorAtLoc(rhs.(*ArrowFn).arrowLoc, fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, "=>"))
 		}
 
-		bin := &BinExpr{N_EXPR_BIN, span.Range{}, T_ILLEGAL, span.Range{}, nil, nil, span.Range{}}
+		// the private name is only permitted as the left operand of `in`, such as `1 + #x in o`
+		if isPvtName(rhs) {
+			return nil, p.errorAtLoc(rhs.Range(), ERR_UNEXPECTED_PVT_FIELD)
+		}
+
+		bin := p.newBinExpr(BinExpr{N_EXPR_BIN, span.Range{}, T_ILLEGAL, span.Range{}, nil, nil, span.Range{}})
 		bin.rng = p.finRng(lhs.Range())
 		bin.op = op
 		bin.opLoc = opLoc
@@ -5626,7 +6391,7 @@ func (p *Parser) memberExprPropSubscript(obj Node, opt bool) (Node, error) {
 	if _, err := p.nextMustTok(T_BRACKET_R); err != nil {
 		return nil, err
 	}
-	node := &MemberExpr{N_EXPR_MEMBER, p.finRng(obj.Range()), obj, prop, true, opt, span.Range{}}
+	node := p.newMemberExpr(MemberExpr{N_EXPR_MEMBER, p.finRng(obj.Range()), obj, prop, true, opt, span.Range{}})
 	return node, nil
 }
 
@@ -5639,13 +6404,13 @@ func (p *Parser) memberExprPropDot(obj Node, opt bool) (Node, error) {
 	var prop Node
 	if (ok && tv != T_NUM) || tv == T_NAME_PVT {
 		pvt := tv == T_NAME_PVT
-		id := &Ident{N_NAME, p.finRng(loc), p.TokText(tok), pvt, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}
+		id := p.newIdent(Ident{N_NAME, p.finRng(loc), p.TokText(tok), pvt, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)})
 		if pvt {
 			scope := p.scope().UpperCls()
 			if scope == nil {
 				return nil, p.errorAtLoc(loc, fmt.Sprintf(ERR_TPL_ALONE_PVT_FIELD, "#"+p.TokText(tok)))
 			}
-			ref := NewRef()
+			ref := p.newRef()
 			ref.Id = id
 			ref.Typ = RDT_PVT_FIELD
 			ref.Scope = scope
@@ -5656,10 +6421,42 @@ func (p *Parser) memberExprPropDot(obj Node, opt bool) (Node, error) {
 		return nil, p.errorTok(tok)
 	}
 
-	node := &MemberExpr{N_EXPR_MEMBER, p.finRng(obj.Range()), obj, prop, false, opt, span.Range{}}
+	node := p.newMemberExpr(MemberExpr{N_EXPR_MEMBER, p.finRng(obj.Range()), obj, prop, false, opt, span.Range{}})
 	return node, nil
 }
 
+// the private name of the ergonomic brand check like `#x in obj`, it should be followed by
+// the `in` operator and be declared in the enclosing classes
+func (p *Parser) pvtName() (Node, error) {
+	tok := p.lexer.Next()
+	if p.feat&FEAT_CLASS_PRIV_IN == 0 {
+		return nil, p.errorFeat(tok, FEAT_CLASS_PRIV_IN)
+	}
+	// the token is reused by the lexer after it's consumed, so its fields are saved here
+	loc := tok.rng
+	name := p.TokText(tok)
+	escape := tok.ContainsEscape()
+	if !IsName(p.lexer.Peek(), "in", false) || p.scope().IsKind(SPK_NOT_IN) {
+		return nil, p.errorAt(T_NAME_PVT, loc, "")
+	}
+
+	scope := p.scope().UpperCls()
+	if scope == nil {
+		return nil, p.errorAtLoc(loc, fmt.Sprintf(ERR_TPL_ALONE_PVT_FIELD, "#"+name))
+	}
+	id := p.newIdent(Ident{N_NAME, loc, name, true, escape, span.Range{}, false, p.newTypInfo(N_NAME)})
+	ref := p.newRef()
+	ref.Id = id
+	ref.Typ = RDT_PVT_FIELD
+	ref.Scope = scope
+	p.danglingPvtRefs = append(p.danglingPvtRefs, ref)
+	return id, nil
+}
+
+func isPvtName(node Node) bool {
+	return node.Type() == N_NAME && node.(*Ident).pvt
+}
+
 func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 	tok := p.lexer.Peek()
 
@@ -5667,7 +6464,7 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 	case T_NUM:
 		loc := tok.rng
 		p.lexer.Next()
-		return &NumLit{N_LIT_NUM, p.finRng(loc), span.Range{}}, nil
+		return p.newNumLit(NumLit{N_LIT_NUM, p.finRng(loc), span.Range{}}), nil
 	case T_STRING:
 		loc := tok.rng
 		p.lexer.Next()
@@ -5675,7 +6472,7 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 		if p.scope().IsKind(SPK_STRICT) && legacyOctalEscapeSeq {
 			return nil, p.errorAtLoc(p.finRng(loc), ERR_LEGACY_OCTAL_ESCAPE_IN_STRICT_MODE)
 		}
-		return &StrLit{N_LIT_STR, p.finRng(loc), p.TokText(tok), legacyOctalEscapeSeq, span.Range{}, nil}, nil
+		return p.newStrLit(StrLit{N_LIT_STR, p.finRng(loc), p.TokText(tok), legacyOctalEscapeSeq, span.Range{}, nil}), nil
 	case T_NULL:
 		loc := tok.rng
 		p.lexer.Next()
@@ -5711,7 +6508,9 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 			return nil, p.errorAtLoc(p.finRng(loc), fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, name))
 		}
 		kw := p.isProhibitedName(nil, name, true, false, false, false)
-		return &Ident{N_NAME, p.finRng(loc), name, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}, nil
+		return p.newIdent(Ident{N_NAME, p.finRng(loc), name, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}), nil
+	case T_NAME_PVT:
+		return p.pvtName()
 	case T_THIS:
 		loc := tok.rng
 		p.lexer.Next()
@@ -5736,9 +6535,25 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 		loc := tok.rng
 		p.lexer.Next()
 		ext := tok.ext.(*TokExtRegexp)
+		if p.feat&FEAT_CHK_REGEXP != 0 || p.feat&regexpPatternFeats != regexpPatternFeats {
+			if err := p.checkRegexp(ext); err != nil {
+				return nil, err
+			}
+		}
 		return &RegLit{N_LIT_REGEXP, p.finRng(loc), p.TokText(tok), p.RngText(ext.pattern), p.RngText(ext.flags), span.Range{}, nil}, nil
 	case T_CLASS:
 		return p.classDec(true, false, false, false)
+	case T_AT:
+		// the decorated class expression like `let A = @dec class {}`
+		ds, err := p.decorators()
+		if err != nil {
+			return nil, err
+		}
+		if p.lexer.Peek().value != T_CLASS {
+			return nil, p.errorAtLoc(ds[0].Range(), ERR_DECORATOR_INVALID_POSITION)
+		}
+		p.hangingDecorators = ds
+		return p.classDec(true, false, false, false)
 	case T_SUPER:
 		loc := tok.rng
 		scope := p.scope()
@@ -5754,7 +6569,7 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 			return nil, p.errorAtLoc(loc, em)
 		}
 
-		if ahead.value != T_DOT && ahead.value != T_PAREN_L {
+		if ahead.value != T_DOT && ahead.value != T_PAREN_L && ahead.value != T_BRACKET_L {
 			return nil, p.errorTok(sup)
 		}
 		return &Super{N_SUPER, p.finRng(loc), span.Range{}, nil}, nil
@@ -5786,7 +6601,66 @@ func (p *Parser) primaryExpr(notColon bool) (Node, error) {
 	return nil, p.errorTok(tok)
 }
 
+// the features of the syntax in the regexp patterns which are not available in all the versions
+const regexpPatternFeats = FEAT_REGEXP_NAMED_GROUP | FEAT_REGEXP_LOOKBEHIND
+
+// reports the early errors of the regexp pattern, the flags are checked by the lexer if
+// `FEAT_CHK_REGEXP_FLAGS` is on, otherwise the pattern is checked only if the flags are valid
+// since its syntax depends on the flags
+//
+// the syntax in `regexpPatternFeats` is reported if it's turned off even if `FEAT_CHK_REGEXP`
+// is off, the other errors of the pattern are not reported in that case
+func (p *Parser) checkRegexp(ext *TokExtRegexp) error {
+	pattern, flags := p.RngText(ext.pattern), p.RngText(ext.flags)
+	fs, err := regex.ParseFlags(flags)
+	if err != nil {
+		if p.feat&FEAT_CHK_REGEXP_FLAGS == 0 {
+			return nil
+		}
+		rng := err.(*regex.Error).Rng
+		return p.errorAtLoc(span.Range{Lo: ext.flags.Lo + rng.Lo, Hi: ext.flags.Lo + rng.Hi},
+			fmt.Sprintf(ERR_TPL_INVALID_REGEXP, pattern, flags, err.(*regex.Error).Msg))
+	}
+
+	pat, err := regex.Parse(pattern, fs)
+	if err != nil {
+		if p.feat&FEAT_CHK_REGEXP == 0 {
+			return nil
+		}
+		e := err.(*regex.Error)
+		return p.errorAtLoc(span.Range{Lo: ext.pattern.Lo + e.Rng.Lo, Hi: ext.pattern.Lo + e.Rng.Hi},
+			fmt.Sprintf(ERR_TPL_INVALID_REGEXP, pattern, flags, e.Msg))
+	}
+
+	var feat Feature
+	var rng span.Range
+	regex.Walk(pat, func(node regex.Node) bool {
+		if feat != FEAT_NONE {
+			return false
+		}
+		switch n := node.(type) {
+		case *regex.CapGroup:
+			if n.Name != "" && p.feat&FEAT_REGEXP_NAMED_GROUP == 0 {
+				feat, rng = FEAT_REGEXP_NAMED_GROUP, n.Rng
+			}
+		case *regex.Assert:
+			if n.Kind == regex.ASSERT_LOOKBEHIND && p.feat&FEAT_REGEXP_LOOKBEHIND == 0 {
+				feat, rng = FEAT_REGEXP_LOOKBEHIND, n.Rng
+			}
+		}
+		return feat == FEAT_NONE
+	})
+	if feat != FEAT_NONE {
+		return p.errorFeatAt(span.Range{Lo: ext.pattern.Lo + rng.Lo, Hi: ext.pattern.Lo + rng.Hi}, feat)
+	}
+	return nil
+}
+
 func (p *Parser) arrowFn(rng span.Range, args []Node, params []Node, ti *TypInfo) (Node, error) {
+	if p.feat&FEAT_ARROW_FN == 0 {
+		return nil, p.errorFeat(p.lexer.Peek(), FEAT_ARROW_FN)
+	}
+
 	var err error
 	if params == nil {
 		params, err = p.argsToParams(args)
@@ -5806,7 +6680,7 @@ func (p *Parser) arrowFn(rng span.Range, args []Node, params []Node, ti *TypInfo
 	}
 
 	for _, paramName := range paramNames {
-		ref := NewRef()
+		ref := p.newRef()
 		ref.Id = paramName.(*Ident)
 		ref.BindKind = BK_PARAM
 		// duplicate-checking is enable in strict mode by below `checkParams`
@@ -5926,6 +6800,12 @@ func (p *Parser) parenExpr(typArgs Node, notColon bool) (Node, error) {
 		return nil, p.errorAt(p.lexer.state.prtVal, p.lexer.state.prtRng, "")
 	}
 
+	if p.flow {
+		if err := p.flowTypCasts(args); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := p.checkArgs(args, false, true); err != nil {
 		return nil, err
 	}
@@ -6017,7 +6897,7 @@ func (p *Parser) spread() (Node, error) {
 	tok := p.lexer.Next()
 
 	if p.feat&FEAT_SPREAD == 0 {
-		return nil, p.errorTok(tok)
+		return nil, p.errorFeat(tok, FEAT_SPREAD)
 	}
 
 	node, err := p.assignExpr(true, false, false, false)
@@ -6082,12 +6962,15 @@ func (p *Parser) objLit() (Node, error) {
 func (p *Parser) objProp() (Node, error) {
 	tok := p.lexer.Peek()
 	if tok.value == T_DOT_TRI {
+		if p.feat&FEAT_OBJ_REST_SPREAD == 0 {
+			return nil, p.errorFeat(tok, FEAT_OBJ_REST_SPREAD)
+		}
 		return p.spread()
 	}
 
 	if tok.value == T_MUL {
-		if p.feat&FEAT_ASYNC_GENERATOR == 0 {
-			return nil, p.errorTok(tok)
+		if p.feat&FEAT_GENERATOR == 0 {
+			return nil, p.errorFeat(tok, FEAT_GENERATOR)
 		}
 		return p.method(span.Range{}, nil, ACC_MOD_NONE, span.Range{}, false, PK_INIT, true, false, false, false, false, span.Range{}, false, false, false, nil)
 	} else if p.aheadIsAsync(tok, true, false) {
@@ -6169,7 +7052,7 @@ func (p *Parser) method(rng span.Range, key Node, accMode ACC_MOD, compute span.
 		ahead := p.lexer.Peek()
 		gen = ahead.value == T_MUL
 		if gen && p.feat&FEAT_ASYNC_GENERATOR == 0 {
-			return nil, p.errorTok(ahead)
+			return nil, p.errorFeat(ahead, FEAT_ASYNC_GENERATOR)
 		}
 	}
 	if gen {
@@ -6235,7 +7118,7 @@ func (p *Parser) method(rng span.Range, key Node, accMode ACC_MOD, compute span.
 	}
 
 	for _, paramName := range paramNames {
-		ref := NewRef()
+		ref := p.newRef()
 		ref.Id = paramName.(*Ident)
 		ref.BindKind = BK_PARAM
 		// duplicate-checking is enable in strict mode so here skip doing checking,
@@ -6374,13 +7257,63 @@ func (p *Parser) decorator() (Node, error) {
 		return nil, nil
 	}
 	loc := p.lexer.Next().rng
-	expr, _, err := p.callExpr(nil, true, false, span.Range{}, false)
+	expr, err := p.decoratorExpr()
 	if err != nil {
 		return nil, err
 	}
 	return &Decorator{N_DECORATOR, p.finRng(loc), expr}, nil
 }
 
+// https://tc39.es/proposal-decorators/#prod-DecoratorMemberExpression
+//
+// the subscript like `a[b]` is not permitted in the decorator expression to distinguish
+// the computed key of the class element in `@dec [key]() {}`, the parenthesized form
+// `@(a[b])` should be used instead
+func (p *Parser) decoratorExpr() (Node, error) {
+	rng := p.rng()
+	expr, err := p.primaryExpr(false)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.lexer.Peek()
+		if tok.value == T_DOT {
+			p.lexer.Next()
+			if expr, err = p.memberExprPropDot(expr, false); err != nil {
+				return nil, err
+			}
+		} else if p.aheadIsArgList(tok) {
+			args, _, typArgs, _, err := p.argList(true, true, span.Range{}, true)
+			if err != nil {
+				return nil, err
+			}
+			ti := p.newTypInfo(N_EXPR_CALL)
+			if ti != nil {
+				ti.SetTypArgs(typArgs)
+			}
+			expr = p.newCallExpr(CallExpr{N_EXPR_CALL, p.finRng(rng), expr, args, false, span.Range{}, ti})
+		} else {
+			break
+		}
+	}
+	return expr, nil
+}
+
+// the decorators are permitted to be either before or after the `export` keyword
+// but not both, they are attached to the class which follows them
+func (p *Parser) exportDecorators() error {
+	tok := p.lexer.Peek()
+	if len(p.hangingDecorators) > 0 {
+		return p.errorAtLoc(tok.rng, ERR_DECORATORS_BEFORE_AND_AFTER_EXPORT)
+	}
+	ds, err := p.decorators()
+	if err != nil {
+		return err
+	}
+	p.hangingDecorators = ds
+	return nil
+}
+
 func (p *Parser) decorators() ([]Node, error) {
 	ds := make([]Node, 0, 5)
 	for {
@@ -6520,6 +7453,39 @@ func (p *Parser) errorTok(tok *Token) *ParserError {
 	return newParserError(p, tok.ErrMsg(), p.lexer.src.Path, tok.rng.Lo)
 }
 
+// reports the syntax of the turned off feature, the version requirement is reported if the
+// feature is turned off by the target version
+func (p *Parser) errorFeat(tok *Token, feat Feature) *ParserError {
+	if msg := p.lexer.featErr(feat, ""); msg != "" {
+		return newParserError(p, msg, p.lexer.src.Path, tok.rng.Lo)
+	}
+	return p.errorTok(tok)
+}
+
+// whether the feature is turned off by the target version rather than explicitly, the syntax
+// of the features turned off explicitly is reported as the unexpected tokens as before
+func (p *Parser) offByVersion(feat Feature) bool {
+	return p.feat&feat == 0 && p.lexer.featErr(feat, "") != ""
+}
+
+// the version requirement of the feature if it's turned off by the target version, otherwise
+// `nil` is returned
+func (p *Parser) errorVersion(loc span.Range, feat Feature) error {
+	if p.offByVersion(feat) {
+		return p.errorAtLoc(loc, p.lexer.featErr(feat, ""))
+	}
+	return nil
+}
+
+// reports the syntax of the turned off feature at `loc`, the version requirement is reported
+// if the feature is turned off by the target version
+func (p *Parser) errorFeatAt(loc span.Range, feat Feature) *ParserError {
+	if msg := p.lexer.featErr(feat, ""); msg != "" {
+		return p.errorAtLoc(loc, msg)
+	}
+	return p.errorAtLoc(loc, ERR_UNEXPECTED_TOKEN)
+}
+
 func (p *Parser) errorAt(tok TokenValue, pos span.Range, errMsg string) *ParserError {
 	if tok != T_ILLEGAL && errMsg == "" {
 		return newParserError(p, fmt.Sprintf(ERR_TPL_UNEXPECTED_TOKEN_TYPE, TokenKinds[tok].Name),
@@ -6531,3 +7497,30 @@ func (p *Parser) errorAt(tok TokenValue, pos span.Range, errMsg string) *ParserE
 func (p *Parser) errorAtLoc(rng span.Range, errMsg string) *ParserError {
 	return newParserError(p, errMsg, p.lexer.src.Path, rng.Lo)
 }
+
+// the error of the exceeded limit or the cancellation which aborts the parsing
+func (p *Parser) errorAbort() *ParserError {
+	a := p.lexer.abort
+	err := newParserError(p, a.msg, p.lexer.src.Path, a.ofst)
+	err.cause = a.cause
+	return err
+}
+
+// enters the nested syntax, the parsing is aborted if the depth exceeds `maxDepth`, it's also
+// the chance to check the cancellation for the loops of the parser which lex no token, `leave`
+// should be deferred if `nil` is returned
+func (p *Parser) enter() error {
+	ofst := p.lexer.state.prtRng.Lo
+	if p.maxDepth > 0 && p.depth >= p.maxDepth {
+		p.lexer.abortAt(fmt.Sprintf(ERR_TPL_NESTING_TOO_DEEP, p.maxDepth), ofst, nil)
+	}
+	if p.lexer.tick(ofst); p.lexer.abort != nil {
+		return p.errorAbort()
+	}
+	p.depth++
+	return nil
+}
+
+func (p *Parser) leave() {
+	p.depth--
+}
@@ -858,6 +858,24 @@ func TestRegexpExpr(t *testing.T) {
 	_ = stmt0.rhs.(*RegLit)
 }
 
+func TestRegexpPattern(t *testing.T) {
+	testPass(t, "a = /(?<a>x)|(?<a>y)/; b = /]{/; c = /[\\p{L}--[a-z]]/v", nil)
+
+	testFail(t, "a = /(?<a>x)(?<a>y)/", "Invalid regular expression: /(?<a>x)(?<a>y)/: Duplicate capture group name at (1:14)", nil)
+	testFail(t, "a = /[z-a]/g", "Invalid regular expression: /[z-a]/g: Range out of order in character class at (1:6)", nil)
+	testFail(t, "a = /\\p{Foo}/u", "Invalid regular expression: /\\p{Foo}/u: Invalid property name at (1:5)", nil)
+	testFail(t, "a = /(?<=a)+/", "Invalid regular expression: /(?<=a)+/: Nothing to repeat at (1:11)", nil)
+
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_CHK_REGEXP_FLAGS).On(FEAT_REGEXP_UNICODE_SETS)
+	testFail(t, "a = /a/gg", "Invalid regular expression: /a/gg: Duplicate regular expression flag at (1:8)", opts)
+	testFail(t, "a = /[a&&&b]/v", "Invalid regular expression: /[a&&&b]/v: Invalid character in character class at (1:9)", opts)
+
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_CHK_REGEXP)
+	testPass(t, "a = /[z-a]/", opts)
+}
+
 func TestParenExpr(t *testing.T) {
 	ast, _, err := compile(`
   a = (b)
@@ -929,6 +947,23 @@ func TestSuper(t *testing.T) {
 	AssertEqual(t, N_SUPER, call.callee.Type(), "should be tag")
 }
 
+func TestSuperComputedMember(t *testing.T) {
+	ast, p, err := compile("class a extends b { c() { super[d] } }", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	m := ast.(*Prog).stmts[0].(*ClassDec).body.(*ClassBody).elems[0].(*Method).val.(*FnDec)
+	member := m.body.(*BlockStmt).body[0].(*ExprStmt).expr.(*MemberExpr)
+	AssertEqual(t, N_SUPER, member.obj.Type(), "should be super")
+	AssertEqual(t, "d", p.NodeText(member.prop), "should be d")
+}
+
+func TestYieldAwait(t *testing.T) {
+	ast, _, err := compile("async function* a() { yield await b }", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	fn := ast.(*Prog).stmts[0].(*FnDec)
+	yield := fn.body.(*BlockStmt).body[0].(*ExprStmt).expr.(*YieldExpr)
+	AssertEqual(t, N_EXPR_UNARY, yield.arg.Type(), "should be await")
+}
+
 func TestImportCall(t *testing.T) {
 	ast, p, err := compile("a = import(b)", nil)
 	AssertEqual(t, nil, err, "should be prog ok")
@@ -1207,7 +1242,7 @@ func TestFail49(t *testing.T) {
 }
 
 func TestFail50(t *testing.T) {
-	testFail(t, "function t(...) { }", "Unexpected token at (1:11)", &ParserOpts{Version: ES5})
+	testFail(t, "function t(...) { }", "Spread and rest syntax requires ES2015 at (1:11)", &ParserOpts{Version: ES5})
 }
 
 func TestFail51(t *testing.T) {
@@ -2473,3 +2508,301 @@ LabelA: LabelB: for (;;) {
 }
 `, "Label `LabelB` already declared at (3:2)", opts)
 }
+
+func TestUpdateExprOpAfterMember(t *testing.T) {
+	ast, _, err := compile("--k[x] <= 0", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	bin := ast.(*Prog).stmts[0].(*ExprStmt).expr.(*BinExpr)
+	ud := bin.lhs.(*UpdateExpr)
+	AssertEqual(t, "--", ud.OpText(), "should be ok")
+	AssertEqual(t, "<=", bin.OpText(), "should be ok")
+}
+
+func TestTsFnOverloadsEnd(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+
+	_, _, err := compile(`declare function f(a: string): void;
+function g(a: string): void;
+function g(a) {}
+function h() {}`, opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	testFail(t, `function f(a: string): void;
+function g(a) {}`, "Function implementation name must be `f` at (2:9)", opts)
+}
+
+func TestTsUnionInTypArgs(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+
+	ast, p, err := compile("let a: Array<string | number>", opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	id := ast.(*Prog).stmts[0].(*VarDecStmt).decList[0].(*VarDec).id.(*Ident)
+	args := id.ti.typAnnot.tsTyp.(*TsRef).Args()
+	AssertEqual(t, 1, len(args), "should be ok")
+	AssertEqual(t, N_TS_UNION_TYP, args[0].Type(), "should be ok")
+	AssertEqual(t, "string | number", p.RngText(args[0].Range()), "should be ok")
+}
+
+func TestVersionFeatures(t *testing.T) {
+	AssertEqual(t, "ES2020", ES11.String(), "should be ok")
+	AssertEqual(t, ES11, FEAT_OPT_EXPR.Version(), "should be ok")
+	AssertEqual(t, ESVersion(0), FEAT_JSX.Version(), "should be ok")
+	AssertEqual(t, true, ES8.Features()&FEAT_ASYNC_AWAIT != 0, "should be ok")
+	AssertEqual(t, true, ES8.Features()&FEAT_OPT_EXPR == 0, "should be ok")
+
+	opts := NewParserOpts()
+	opts.Version = ES8
+	testFail(t, "a?.b", "Optional chaining requires ES2020 at (1:1)", opts.Clone())
+	testFail(t, "a ?? b", "Nullish coalescing requires ES2020 at (1:2)", opts.Clone())
+	testFail(t, "class A {\n  x = 1\n}", "Class field requires ES2022 at (2:2)", opts.Clone())
+	testFail(t, "class A { #x() {} }", "Private name requires ES2022 at (1:10)", opts.Clone())
+	testFail(t, "a = 1_000", "Numeric separator requires ES2021 at (1:5)", opts.Clone())
+	testFail(t, "a = 1n", "BigInt literal requires ES2020 at (1:5)", opts.Clone())
+	testFail(t, "try {} catch {}", "Optional catch binding requires ES2019 at (1:13)", opts.Clone())
+	testFail(t, "async function* f() {}", "Async generator requires ES2018 at (1:14)", opts.Clone())
+	testPass(t, "async function f() { await a; return a ** 2 }", opts.Clone())
+
+	opts.Version = ES6
+	testFail(t, "a ** 2", "Exponentiation operator requires ES2016 at (1:2)", opts.Clone())
+
+	// the version does not restrict the typescript sources
+	opts.Feature = opts.Feature.On(FEAT_TS)
+	testPass(t, "class A { x?: number = a?.b ?? 1 }", opts.Clone())
+
+	// the features turned off explicitly are reported as before
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_OPT_EXPR)
+	testFail(t, "a?.b", "Unexpected token at (1:1)", opts)
+}
+
+func TestVersionGating(t *testing.T) {
+	// each syntax is rejected by the version before `ver` and accepted by `ver`
+	cases := []struct {
+		ver  ESVersion
+		code string
+		err  string
+	}{
+		{ES6, "let a = 1", "Lexical declaration requires ES2015 at (1:0)"},
+		{ES6, "const a = 1", "Lexical declaration requires ES2015 at (1:0)"},
+		{ES6, "a => a", "Arrow function requires ES2015 at (1:2)"},
+		{ES6, "(a, b) => a", "Arrow function requires ES2015 at (1:7)"},
+		{ES6, "class A {}", "Class requires ES2015 at (1:0)"},
+		{ES6, "(class {})", "Class requires ES2015 at (1:1)"},
+		{ES6, "function* g() {}", "Generator requires ES2015 at (1:8)"},
+		{ES6, "({ *g() {} })", "Generator requires ES2015 at (1:3)"},
+		{ES6, "`a${b}`", "Template literal requires ES2015 at (1:0)"},
+		{ES6, "a`b`", "Template literal requires ES2015 at (1:1)"},
+		{ES6, "for (a of b);", "`for-of` statement requires ES2015 at (1:7)"},
+		{ES6, "function f(a = 1) {}", "Default parameter requires ES2015 at (1:13)"},
+		{ES6, "({ [a]: 1 })", "Computed property name requires ES2015 at (1:3)"},
+		{ES6, "function f() { new.target }", "`new.target` requires ES2015 at (1:15)"},
+		{ES8, "async function f() {}", "Async function requires ES2017 at (1:0)"},
+		{ES9, "async function f() { for await (x of y); }", "Async iteration requires ES2018 at (1:25)"},
+		{ES9, "let { ...a } = b", "Object rest and spread requires ES2018 at (1:6)"},
+		{ES9, "({ ...a })", "Object rest and spread requires ES2018 at (1:3)"},
+		{ES9, "/a/s", "RegExp flag `s` requires ES2018 at (1:0)"},
+		{ES9, "/(?<n>a)/", "RegExp named group requires ES2018 at (1:1)"},
+		{ES9, "/(?<=a)b/", "RegExp lookbehind assertion requires ES2018 at (1:1)"},
+		{ES11, "import('a')", "Dynamic import requires ES2020 at (1:0)"},
+		{ES11, "import.meta", "`import.meta` requires ES2020 at (1:0)"},
+		{ES11, "export * as ns from 'a'", "`export * as ns` requires ES2020 at (1:9)"},
+		{ES13, "await a", "Top-level await requires ES2022 at (1:0)"},
+		{ES13, "for await (x of y);", "Top-level await requires ES2022 at (1:4)"},
+		{ES16, "import j from './a.json' with { type: 'json' }", "Import attributes requires ES2025 at (1:25)"},
+		{ESNEXT, "using x = y", "Using declaration requires ESNext at (1:0)"},
+		{ESNEXT, "for (using x of y);", "Using declaration requires ESNext at (1:5)"},
+	}
+	for _, c := range cases {
+		prev := c.ver - 1
+		if c.ver == ES6 {
+			prev = ES5
+		} else if c.ver == ESNEXT {
+			prev = ES16
+		}
+		opts := NewParserOpts()
+		opts.Version = prev
+		testFail(t, c.code, c.err, opts)
+
+		opts = NewParserOpts()
+		opts.Version = c.ver
+		testPass(t, c.code, opts)
+	}
+
+	// `async` and `let` are still the identifiers in ES5 scripts
+	opts := NewParserOpts()
+	opts.Version = ES5
+	opts.Feature = opts.Feature.Off(FEAT_MODULE).Off(FEAT_STRICT)
+	testPass(t, "async(a); var async = 1; let = 1; let + 1", opts)
+
+	// the syntax not yet in a published edition is rejected by all the published versions
+	opts = NewParserOpts()
+	opts.Version = ES8
+	testFail(t, "using x = y", "Using declaration requires ESNext at (1:0)", opts)
+	testFail(t, "import j from './a.json' with { type: 'json' }", "Import attributes requires ES2025 at (1:25)", opts)
+
+	// `using` is an identifier if the declarations are turned off explicitly
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_USING)
+	testPass(t, "using(x); using = 1", opts)
+}
+
+func TestPrivIn(t *testing.T) {
+	ast, _, err := compile("class A { #x; m(o) { return #x in o } }", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	m := ast.(*Prog).stmts[0].(*ClassDec).body.(*ClassBody).elems[1].(*Method)
+	ret := m.val.(*FnDec).body.(*BlockStmt).body[0].(*RetStmt)
+	bin := ret.arg.(*BinExpr)
+	AssertEqual(t, T_IN, bin.op, "should be in")
+	AssertEqual(t, true, bin.lhs.(*Ident).pvt, "should be private")
+	AssertEqual(t, "x", bin.lhs.(*Ident).val, "should be x")
+
+	testPass(t, "class A { #x; m(o) { return #x in o && (#x in o) in p } }", nil)
+	testFail(t, "class A { #x; m(o) { return #x } }", "Unexpected token `private identifier` at (1:28)", nil)
+	testFail(t, "class A { #x; m(o) { return 1 + #x in o } }", "Unexpected private field at (1:32)", nil)
+	testFail(t, "class A { #x; m(o) { return -#x in o } }", "Unexpected private field at (1:29)", nil)
+	testFail(t, "class A { #x; m(o) { for (#x in o); } }", "Unexpected token `private identifier` at (1:26)", nil)
+	testFail(t, "class A { m(o) { return #y in o } }", "Private field `#y` must be declared in an enclosing class at (1:24)", nil)
+	testFail(t, "#x in o", "Private field `#x` must be declared in an enclosing class at (1:0)", nil)
+
+	opts := NewParserOpts()
+	opts.Version = ES12
+	testFail(t, "class A { #x; m(o) { return #x in o } }", "Private name requires ES2022 at (1:10)", opts)
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_CLASS_PRIV_IN)
+	testFail(t, "class A { #x; m(o) { return #x in o } }", "Unexpected token `private identifier` at (1:28)", opts)
+}
+
+func TestHashbang(t *testing.T) {
+	ast, p, err := compile("#!/usr/bin/env node\nlet a = 1", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, 1, len(ast.(*Prog).stmts), "should be 1 stmt")
+	AssertEqual(t, 1, len(p.Comments()), "should be 1 comment")
+	AssertEqual(t, "#!/usr/bin/env node", p.RngText(p.Comments()[0]), "should be hashbang")
+
+	testFail(t, " #!/usr/bin/env node", "Unexpected character at (1:2)", nil)
+	testFail(t, "a\n#!/usr/bin/env node", "Unexpected character at (2:1)", nil)
+
+	opts := NewParserOpts()
+	opts.Version = ES13
+	testFail(t, "#!/usr/bin/env node", "Hashbang comment requires ES2023 at (1:0)", opts)
+}
+
+func TestModuleStrName(t *testing.T) {
+	ast, _, err := compile(`import { "a b" as c } from "m"; export { c as "d e" }; export { "f" as "g" } from "m"`, nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	stmts := ast.(*Prog).stmts
+	is := stmts[0].(*ImportDec).specs[0].(*ImportSpec)
+	AssertEqual(t, "a b", is.id.(*StrLit).val, "should be a b")
+	AssertEqual(t, "c", is.local.(*Ident).val, "should be c")
+	es := stmts[1].(*ExportDec).specs[0].(*ExportSpec)
+	AssertEqual(t, "d e", es.id.(*StrLit).val, "should be d e")
+	es = stmts[2].(*ExportDec).specs[0].(*ExportSpec)
+	AssertEqual(t, "f", es.local.(*StrLit).val, "should be f")
+
+	testPass(t, `export * as "a b" from "m"`, nil)
+	testFail(t, `import { "a b" } from "m"`, "Unexpected token `}` at (1:15)", nil)
+	testFail(t, `export { "a b" }`, "A string literal cannot be used as an exported binding without `from` at (1:9)", nil)
+	testFail(t, `let a; export { a as "b", a as "b" }`, "Duplicate export `b` at (1:31)", nil)
+
+	opts := NewParserOpts()
+	opts.Version = ES12
+	testFail(t, `export { "a" } from "m"`, "String module export name requires ES2022 at (1:9)", opts)
+}
+
+func TestStaticBlockFeat(t *testing.T) {
+	testPass(t, "class A { static { this.a = 1 } }", nil)
+
+	opts := NewParserOpts()
+	opts.Version = ES12
+	testFail(t, "class A { static { } }", "Class static block requires ES2022 at (1:17)", opts)
+}
+
+func TestTopLevelAwaitInScript(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_MODULE)
+	testPass(t, "var await = 1; await", opts.Clone())
+	testFail(t, "await x", "Unexpected token at (1:6)", opts.Clone())
+
+	testPass(t, "await x", nil)
+}
+
+func TestUsingDec(t *testing.T) {
+	ast, _, err := compile("{ using a = b(), c = d() }", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	blk := ast.(*Prog).Body()[0].(*BlockStmt)
+	dec := blk.Body()[0].(*VarDecStmt)
+	AssertEqual(t, "using", dec.Kind(), "should be ok")
+	AssertEqual(t, true, dec.IsUsing(), "should be ok")
+	AssertEqual(t, 2, len(dec.DecList()), "should be ok")
+
+	ast, _, err = compile("async function f() { await using a = b() }", nil)
+	AssertEqual(t, nil, err, "should be prog ok")
+	fn := ast.(*Prog).Body()[0].(*FnDec)
+	dec = fn.Body().(*BlockStmt).Body()[0].(*VarDecStmt)
+	AssertEqual(t, "await using", dec.Kind(), "should be ok")
+
+	testPass(t, "using x = a()", nil)
+	testPass(t, "for (using x of y);", nil)
+	testPass(t, "for (using x = a; ;);", nil)
+	testPass(t, "for (using of of y);", nil)
+	testPass(t, "for (using of y);", nil)
+	testPass(t, "using = 1; using + 1; using.a; using\nx", nil)
+	testPass(t, "{ using [a] = b }", nil)
+	testPass(t, "async () => { for (await using x of y); }", nil)
+	testPass(t, "switch (a) { case 1: { using x = a() } }", nil)
+
+	testFail(t, "{ using x }", "Using declarations require an initialization value at (1:8)", nil)
+	testFail(t, "{ using x = a, y }", "Using declarations require an initialization value at (1:15)", nil)
+	testFail(t, "{ using x = a; let x }", "Identifier `x` has already been declared at (1:19)", nil)
+	testFail(t, "function f() { await using x = a() }", "Cannot use keyword 'await' outside an async function at (1:15)", nil)
+	testFail(t, "switch (a) { case 1: using x = a() }", "Using declarations are not allowed directly in the case clauses at (1:21)", nil)
+	testFail(t, "for (using x in y);", "Using declarations are not allowed in the head of for-in loops at (1:5)", nil)
+	testFail(t, "if (a) using x = b;", "Unexpected token `identifier` at (1:7)", nil)
+	testFail(t, "{ using let = a }", "Invalid binding `let` at (1:8)", nil)
+
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_MODULE)
+	testFail(t, "using x = a()", "Using declarations are not allowed at the top level of scripts at (1:0)", opts.Clone())
+	testPass(t, "{ using x = a() }", opts.Clone())
+
+	opts = NewParserOpts()
+	opts.Feature = opts.Feature.Off(FEAT_USING)
+	testFail(t, "{ using x = a() }", "Unexpected token at (1:8)", opts)
+}
+
+func TestParserOptsOfFile(t *testing.T) {
+	opts := NewParserOpts()
+
+	o := opts.OfFile("a.js")
+	AssertEqual(t, opts.Feature, o.Feature, "should be ok")
+
+	o = opts.OfFile("a.ts")
+	AssertEqual(t, true, o.Feature&FEAT_TS != 0, "should be ok")
+	AssertEqual(t, false, o.Feature&FEAT_JSX != 0, "should be ok")
+
+	o = opts.OfFile("a.tsx")
+	AssertEqual(t, true, o.Feature&FEAT_TS != 0, "should be ok")
+	AssertEqual(t, true, o.Feature&FEAT_JSX != 0, "should be ok")
+
+	o = opts.OfFile("lib/a.d.ts")
+	AssertEqual(t, true, o.Feature&FEAT_DTS != 0, "should be ok")
+	AssertEqual(t, false, opts.Feature&FEAT_TS != 0, "should be ok")
+}
+
+func TestTypInfoOf(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+	ast, _, err := compile("function f(a: string, ...b: number[]) {}", opts)
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	params := ast.(*Prog).stmts[0].(*FnDec).params
+	AssertEqual(t, N_TS_STR, TypInfoOf(params[0]).TypAnnot().TsTyp().Type(), "should be ok")
+	AssertEqual(t, N_TS_ARR, TypInfoOf(params[1]).TypAnnot().TsTyp().Type(), "should be ok")
+	AssertEqual(t, true, TypInfoOf(&NumLit{}) == nil, "should be ok")
+}
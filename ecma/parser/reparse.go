@@ -0,0 +1,539 @@
+package parser
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"unsafe"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// an edit of the source, the text in `Rng` of the source is replaced with `Text`, it's an
+// insertion if `Rng` is empty
+type Edit struct {
+	Rng  span.Range
+	Text string
+}
+
+// the state before a top-level statement is parsed, it's used to find the scopes created by the
+// statement when it's reused
+type stmtMark struct {
+	down int // the count of the child scopes of the global scope
+	seed int // the seed of scope id
+
+	// the lexer mode and the depth of the mode stack, they are affected by the lookahead token like
+	// `{` pushes a mode, so the statements are reusable only if they are same in both the parses
+	mode  LexerModeKind
+	depth int
+
+	// whether the current scope is the global scope, it's `false` if the scope is left unbalanced
+	// by the statements before it
+	global bool
+	kind   ScopeKind // the kind of the global scope
+}
+
+func (p *Parser) stmtMark() stmtMark {
+	modes, root := p.lexer.state.mode, p.symtab.Root
+	return stmtMark{len(root.Down), p.symtab.scopeIdSeed, modes[0].kind, len(modes), p.symtab.Cur == root, root.Kind}
+}
+
+// whether the lexer and the parser are in their initial states before the statement
+func (m stmtMark) balanced() bool {
+	return m.depth == 1 && m.global
+}
+
+// the context of the reparse, the top-level statements of the old program are split into three
+// parts by the edits:
+//
+//   - the statements before the edits which are reused as they are
+//   - the statements affected by the edits which are reparsed, it's named the window
+//   - the statements after the edits which are reused with their ranges shifted
+//
+// the window begins at the statement before the first one touched by the edits since its end
+// may be changed by the tokens after it, like `a \n (b)`, and it ends at the first statement
+// after the edits the parser meets at its beginning, which is called the resync point
+type reparseCtx struct {
+	stmts []Node // the top-level statements of the old program
+	marks []stmtMark
+	lo    uint32 // the range of the old source changed by the edits
+	hi    uint32
+	delta int
+
+	// whether the window begins at the beginning of the program so the directive prologue is
+	// reparsed
+	prologue bool
+
+	next int // the index of the next candidate of the resync point
+	sync int // the index of the resync point in `stmts`, it's `-1` if the window reaches the end
+	cmts []span.Range
+}
+
+func (r *reparseCtx) shift(ofst uint32) uint32 {
+	return uint32(int(ofst) + r.delta)
+}
+
+// reports whether the parser, which is going to parse the top-level statement beginning with
+// `tok`, meets the resync point, the remaining source is the same as the one of the old statements
+// after the point, they are reused only if they would be parsed in the same context, so the
+// parser should be outside the prologue and not in the middle of the TypeScript overloads
+func (r *reparseCtx) resync(p *Parser, tok *Token, stmts []Node, prologue int) bool {
+	if len(stmts) == 0 || prologue != -1 || p.lastTsFnSig != nil || len(p.hangingDecorators) > 0 {
+		return false
+	}
+
+	ofst := tok.rng.Lo
+	for ; r.next < len(r.stmts); r.next++ {
+		lo := r.stmts[r.next].Range().Lo
+		if lo >= r.hi && r.shift(lo) >= ofst {
+			break
+		}
+	}
+	j := r.next
+	if j == 0 || j == len(r.stmts) || r.shift(r.stmts[j].Range().Lo) != ofst {
+		return false
+	}
+	if m, cur := r.marks[j], p.stmtMark(); !m.global || !cur.global || m.mode != cur.mode || m.depth != cur.depth ||
+		m.kind != cur.kind {
+		return false
+	}
+
+	// the trivia between the statements should be unchanged
+	prev := r.stmts[j-1]
+	if prev.Range().Hi < r.hi || r.shift(prev.Range().Hi) != stmts[len(stmts)-1].Range().Hi {
+		return false
+	}
+	if isTsFnSig(prev) || isDirective(r.stmts[j]) {
+		return false
+	}
+
+	r.sync = j
+	r.cmts = p.lexer.takeStmtCmts()
+	return true
+}
+
+func isDirective(node Node) bool {
+	n, ok := node.(*ExprStmt)
+	return ok && n.dir
+}
+
+func isTsFnSig(node Node) bool {
+	switch n := node.(type) {
+	case *FnDec:
+		return util.IsNilPtr(n.body)
+	case *ExportDec:
+		return !util.IsNilPtr(n.dec) && isTsFnSig(n.dec)
+	}
+	return false
+}
+
+func applyEdits(code string, edits []Edit) (string, uint32, uint32, error) {
+	es := make([]Edit, len(edits))
+	copy(es, edits)
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].Rng.Lo < es[j].Rng.Lo
+	})
+
+	b := make([]byte, 0, len(code))
+	var cur uint32
+	for _, e := range es {
+		if e.Rng.Lo < cur || e.Rng.Lo > e.Rng.Hi || int(e.Rng.Hi) > len(code) {
+			return "", 0, 0, errors.New("the edits overlap with each other or are out of the source")
+		}
+		b = append(b, code[cur:e.Rng.Lo]...)
+		b = append(b, e.Text...)
+		cur = e.Rng.Hi
+	}
+	b = append(b, code[cur:]...)
+	return string(b), es[0].Rng.Lo, cur, nil
+}
+
+// reparses the source with the edits applied, the edits are described by the ranges of the
+// source parsed by the parser and they should not overlap with each other
+//
+// the top-level statements which are not affected by the edits are reused as well as their
+// scopes, only the region around the edits is re-lexed and reparsed, the ranges of the reused
+// statements after the edits are shifted, so the result is the same as parsing the new source
+// from scratch, the old AST is taken over by the new one so it should not be used any more
+//
+// the entire source is parsed again if the reuse is unsafe, for instance the directive prologue
+// is edited or the names of the reused statements are redeclared in the reparsed region
+func (p *Parser) Reparse(old Node, edits []Edit) (Node, error) {
+	if len(edits) == 0 {
+		return old, nil
+	}
+
+	src := p.lexer.src
+	code, lo, hi, err := applyEdits(src.Text(0, uint32(src.Len())), edits)
+	if err != nil {
+		return nil, err
+	}
+	nsrc := span.NewSource(src.Path, code)
+
+	prog, ok := old.(*Prog)
+	if !ok || prog != p.prog || len(p.marks) != len(prog.stmts) || len(prog.stmts) == 0 {
+		return p.parseAgain(nsrc)
+	}
+	// the size limit is checked by the full parse
+	tooLarge := p.maxSize > 0 && len(code) > p.maxSize
+	if !tooLarge && p.incrParse(prog, nsrc, lo, hi, len(code)-src.Len()) {
+		return prog, nil
+	}
+	nsrc.Seek(0)
+	return p.parseAgain(nsrc)
+}
+
+func (p *Parser) parseAgain(src *span.Source) (Node, error) {
+	opts := p.autoOpts
+	if opts == nil {
+		opts = &ParserOpts{Externals: p.symtab.Externals, Feature: p.feat, Tokens: p.lexer.keepToks,
+			MaxSize: p.maxSize, MaxDepth: p.maxDepth, MaxTokens: p.lexer.maxToks, Ctx: p.lexer.ctx, Arena: p.arena}
+	}
+	*p = Parser{}
+	p.Setup(src, opts)
+	return p.Prog()
+}
+
+// reparses the window of the program and splices the reused statements, the program node is
+// reused and its statements are replaced, `false` is returned if the reuse is unsafe
+func (p *Parser) incrParse(prog *Prog, src *span.Source, lo, hi uint32, delta int) bool {
+	stmts, marks := prog.stmts, p.marks
+	s := sort.Search(len(stmts), func(i int) bool { return stmts[i].Range().Hi >= lo }) - 1
+	for s > 0 && (isDirective(stmts[s-1]) || isTsFnSig(stmts[s-1]) || !marks[s].balanced()) {
+		s--
+	}
+	if s < 0 {
+		s = 0
+	}
+	var winLo uint32
+	if s > 0 {
+		winLo = stmts[s].Range().Lo
+	}
+
+	ol, root, cur, kind := p.lexer, p.symtab.Root, p.symtab.Cur, p.symtab.Root.Kind
+	oldDown, oldSeed := root.Down, p.symtab.scopeIdSeed
+	oldDecls, oldRefs := root.decls, root.Refs
+	oldLabels, oldExports := root.Labels, root.Exports
+	oldPrev, oldPost := p.prevCmts, p.postCmts
+	r := &reparseCtx{stmts: stmts, marks: marks, lo: lo, hi: hi, delta: delta, prologue: s == 0, sync: -1}
+
+	// restore the states of the parser before the window
+	p.lexer = NewLexer(src)
+	p.lexer.ver = ol.ver
+	p.lexer.feat = ol.feat
+	p.lexer.keepToks = ol.keepToks
+	p.lexer.maxToks = ol.maxToks
+	p.lexer.ctx = ol.ctx
+	if s > 0 {
+		p.lexer.state.mode[0].kind = marks[s].mode
+		root.Kind = marks[s].kind
+	} else if p.ts || p.dts {
+		p.lexer.AddMode(LM_TS)
+	}
+	src.Seek(winLo)
+
+	p.prevCmts = map[Node][]span.Range{}
+	p.postCmts = map[Node][]span.Range{}
+	p.danglingPvtRefs = make([]*Ref, 0)
+	p.lastTsFnSig = nil
+	p.hangingDecorators = nil
+	p.ltTokens = map[uint32]bool{}
+	p.marks = append([]stmtMark(nil), marks[:s]...)
+
+	p.symtab.Cur = root
+	p.symtab.scopeIdSeed = marks[s].seed
+	root.Down = append([]*Scope{}, oldDown[:marks[s].down]...)
+	root.decls = nil
+	root.Refs = map[string]*Ref{}
+	root.Labels = make([]Node, 0, len(oldLabels))
+	root.Exports = nil
+
+	// the bindings are rewound to the ones before the window, the names declared after the
+	// beginning of the window are checked after the window is reparsed
+	for name, ref := range oldRefs {
+		root.Refs[name] = ref
+	}
+	later := map[string]bool{}
+	for _, d := range oldDecls {
+		if d.pos < winLo {
+			root.decls = append(root.decls, d)
+			continue
+		}
+		if later[d.name] {
+			continue
+		}
+		later[d.name] = true
+		if d.had {
+			root.Refs[d.name] = d.prev
+		} else {
+			delete(root.Refs, d.name)
+		}
+	}
+	for _, n := range oldLabels {
+		if n.Range().Lo < winLo {
+			root.Labels = append(root.Labels, n)
+		}
+	}
+	for _, n := range oldExports {
+		if n.Range().Lo < winLo {
+			root.Exports = append(root.Exports, n)
+		}
+	}
+
+	rng := prog.rng
+	if s == 0 {
+		root.Kind = SPK_NONE
+		p.globalScope()
+		rng = p.rng()
+	} else {
+		p.lexer.stmtCmts = append(p.lexer.stmtCmts, oldPrev[stmts[s]]...)
+	}
+
+	p.reused = r
+	win, err := p.stmts(T_ILLEGAL)
+	p.reused = nil
+	if err != nil || p.lexer.abort != nil {
+		return false
+	}
+	if err := p.resolvingDanglingPvtRefs(); err != nil {
+		return false
+	}
+
+	syncLo, newSyncLo := uint32(ol.src.Len())+1, uint32(src.Len())+1
+	var reused []Node
+	if r.sync != -1 {
+		reused = stmts[r.sync:]
+		syncLo = reused[0].Range().Lo
+		newSyncLo = r.shift(syncLo)
+	}
+	if !p.spliceDecls(oldDecls, oldRefs, winLo, syncLo, delta) {
+		return false
+	}
+
+	// collects the states of the reused statements before their ranges are shifted
+	for _, n := range oldLabels {
+		if n.Range().Lo >= syncLo {
+			root.Labels = append(root.Labels, n)
+		}
+	}
+	for _, n := range oldExports {
+		if n.Range().Lo >= syncLo {
+			root.Exports = append(root.Exports, n)
+		}
+	}
+	for _, m := range []map[Node][]span.Range{oldPrev, oldPost} {
+		for node, cmts := range m {
+			if util.IsNilPtr(node) || node.Range().Hi <= winLo {
+				continue
+			}
+			if node.Range().Lo < syncLo {
+				delete(m, node)
+				continue
+			}
+			for i, c := range cmts {
+				cmts[i] = span.Range{Lo: r.shift(c.Lo), Hi: r.shift(c.Hi)}
+			}
+		}
+	}
+	if r.sync != -1 {
+		delete(oldPrev, reused[0])
+		if len(r.cmts) > 0 {
+			oldPrev[reused[0]] = r.cmts
+		}
+	}
+
+	cmts := make([]span.Range, 0, len(ol.cmts)+len(p.lexer.cmts))
+	for _, c := range ol.Comments() {
+		if c.Hi <= winLo {
+			cmts = append(cmts, c)
+		}
+	}
+	for _, c := range p.lexer.Comments() {
+		if c.Lo < newSyncLo {
+			cmts = append(cmts, c)
+		}
+	}
+	var toks []Token
+	for _, tok := range ol.state.toks {
+		if tok.rng.Hi <= winLo {
+			toks = append(toks, tok)
+		} else if s > 0 && tok.rng.Lo == winLo && len(p.lexer.state.toks) > 0 {
+			// the whitespaces before the window are skipped, so the line terminator before its
+			// first token is not met by the lexer
+			p.lexer.state.toks[0].afterLineTerm = tok.afterLineTerm
+		}
+	}
+	toks = append(toks, p.lexer.state.toks...)
+
+	sh := &shifter{delta: delta, seen: map[uintptr]bool{reflect.ValueOf(prog).Pointer(): true}}
+	if r.sync != -1 {
+		for _, node := range reused {
+			sh.shift(reflect.ValueOf(node))
+		}
+		for _, c := range ol.Comments() {
+			if c.Lo >= syncLo {
+				cmts = append(cmts, span.Range{Lo: r.shift(c.Lo), Hi: r.shift(c.Hi)})
+			}
+		}
+		for i := range ol.state.toks {
+			if tok := &ol.state.toks[i]; tok.rng.Lo >= syncLo {
+				sh.shift(reflect.ValueOf(tok))
+				toks = append(toks, *tok)
+			}
+		}
+
+		// the ids of the scopes are generated in the order of their creation
+		mark := marks[r.sync]
+		ids := p.symtab.scopeIdSeed - mark.seed
+		downs := len(root.Down) - mark.down
+		for _, scope := range oldDown[mark.down:] {
+			shiftScopeIds(scope, ids)
+			root.Down = append(root.Down, scope)
+		}
+		for _, m := range marks[r.sync:] {
+			p.marks = append(p.marks, stmtMark{m.down + downs, m.seed + ids, m.mode, m.depth, m.global, m.kind})
+		}
+		p.symtab.scopeIdSeed = oldSeed + ids
+		p.symtab.Cur = cur
+		root.Kind = kind
+	}
+	p.prevCmts = mergeCmts(oldPrev, p.prevCmts)
+	p.postCmts = mergeCmts(oldPost, p.postCmts)
+	p.symtab.rebuildScopes()
+
+	if err := p.checkExp(root.Exports); err != nil {
+		return false
+	}
+
+	rng.Hi = uint32(src.Len())
+	prog.rng = rng
+	prog.stmts = make([]Node, 0, s+len(win)+len(reused))
+	prog.stmts = append(append(append(prog.stmts, stmts[:s]...), win...), reused...)
+	p.prog = prog
+	p.nodeCmts = nil
+	p.lexer.cmts = cmts
+	p.lexer.state.toks = toks
+	return true
+}
+
+// splices the bindings of the global scope, the names declared in the window are independent
+// of the ones declared by the reused statements after it except the plain `var` declarations
+// which can be redeclared freely, `false` is returned if there are other kinds of redeclarations
+func (p *Parser) spliceDecls(oldDecls []scopeDecl, oldRefs map[string]*Ref, winLo, syncLo uint32, delta int) bool {
+	root := p.symtab.Root
+	win := map[string]bool{}
+	for _, d := range root.decls {
+		if d.pos >= winLo {
+			win[d.name] = true
+		}
+	}
+	reused := map[string]bool{}
+	for _, d := range oldDecls {
+		if d.pos >= syncLo {
+			reused[d.name] = true
+		} else if d.pos >= winLo {
+			win[d.name] = true
+		}
+	}
+
+	plainVar := func(d scopeDecl) bool {
+		return d.ref != nil && d.ref.BindKind == BK_VAR && d.ref.Typ == RDT_NONE
+	}
+	for _, decls := range [][]scopeDecl{oldDecls, root.decls} {
+		for _, d := range decls {
+			if win[d.name] && reused[d.name] && !plainVar(d) {
+				return false
+			}
+		}
+	}
+
+	// the later `var` declaration overwrites the former one
+	for _, d := range oldDecls {
+		if d.pos < syncLo {
+			continue
+		}
+		if win[d.name] {
+			d.prev, d.had = root.Refs[d.name]
+			root.Refs[d.name] = d.ref
+		} else {
+			root.Refs[d.name] = oldRefs[d.name]
+		}
+		d.pos = uint32(int(d.pos) + delta)
+		root.decls = append(root.decls, d)
+	}
+	return true
+}
+
+func mergeCmts(a, b map[Node][]span.Range) map[Node][]span.Range {
+	for k, v := range b {
+		a[k] = v
+	}
+	return a
+}
+
+func shiftScopeIds(scope *Scope, delta int) {
+	scope.Id += delta
+	for _, s := range scope.Down {
+		shiftScopeIds(s, delta)
+	}
+}
+
+// rebuilds the scopes indexed by their ids by replaying the writes of `EnterScope` and
+// `LeaveScope`, the temporary scopes share the ids with the settled ones so the last write wins
+func (s *SymTab) rebuildScopes() {
+	s.Scopes = map[int]*Scope{s.Root.Id: s.Root}
+	var replay func(scope *Scope)
+	replay = func(scope *Scope) {
+		for _, c := range scope.Down {
+			s.Scopes[c.Id] = c
+			replay(c)
+			s.Scopes[scope.Id] = scope
+		}
+	}
+	replay(s.Root)
+}
+
+var rangeTyp = reflect.TypeOf(span.Range{})
+
+// shifts the ranges in the nodes, the tokens and the comments by walking their fields, the
+// nodes are visited once since some of them are shared, like the ones in the shorthand props
+type shifter struct {
+	delta int
+	seen  map[uintptr]bool
+}
+
+func (s *shifter) shift(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || s.seen[v.Pointer()] {
+			return
+		}
+		s.seen[v.Pointer()] = true
+		s.shift(v.Elem())
+	case reflect.Interface:
+		if !v.IsNil() {
+			s.shift(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			s.shift(v.Index(i))
+		}
+	case reflect.Struct:
+		if v.Type() != rangeTyp {
+			for i := 0; i < v.NumField(); i++ {
+				s.shift(v.Field(i))
+			}
+			return
+		}
+		if !v.CanAddr() {
+			return
+		}
+		// the empty range means the absence of the part, like `opa` of the nodes not in parens
+		rng := (*span.Range)(unsafe.Pointer(v.UnsafeAddr()))
+		if !rng.Empty() {
+			rng.Lo = uint32(int(rng.Lo) + s.delta)
+			rng.Hi = uint32(int(rng.Hi) + s.delta)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package parser
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func reparseOpts(ts bool) *ParserOpts {
+	opts := NewParserOpts()
+	opts.Tokens = true
+	if ts {
+		opts.Feature = opts.Feature.On(FEAT_TS)
+	}
+	return opts
+}
+
+func cmtsOf(m map[Node][]span.Range) map[string][]span.Range {
+	ret := map[string][]span.Range{}
+	for node, cmts := range m {
+		if IsNilPtr(node) {
+			continue
+		}
+		ret[fmt.Sprintf("%s%v", node.Type(), node.Range())] = cmts
+	}
+	return ret
+}
+
+// checks the result of the reparse is the same as parsing the source from scratch
+func assertReparsed(t *testing.T, name string, p *Parser, ast Node, err error, code string, ts bool) {
+	full := NewParser(span.NewSource("", code), reparseOpts(ts))
+	exp, expErr := full.Prog()
+	if expErr != nil || err != nil {
+		if expErr == nil || err == nil || expErr.Error() != err.Error() {
+			t.Fatalf("%s: errors mismatch, expected %v, actual %v\n%s", name, expErr, err, code)
+		}
+		return
+	}
+
+	mismatch := func(what string) {
+		t.Fatalf("%s: %s mismatch\n%s", name, what, code)
+	}
+	if !reflect.DeepEqual(exp, ast) {
+		mismatch("ast")
+	}
+	if !reflect.DeepEqual(full.Comments(), p.Comments()) {
+		mismatch("comments")
+	}
+	if !reflect.DeepEqual(full.Tokens(), p.Tokens()) {
+		mismatch("tokens")
+	}
+	// the scopes discarded by the speculative parsing are left in the index of the full parse
+	full.symtab.rebuildScopes()
+	if !reflect.DeepEqual(full.symtab, p.symtab) {
+		mismatch("symtab")
+	}
+	if !reflect.DeepEqual(full.marks, p.marks) {
+		mismatch("marks")
+	}
+	if !reflect.DeepEqual(cmtsOf(full.prevCmts), cmtsOf(p.prevCmts)) ||
+		!reflect.DeepEqual(cmtsOf(full.postCmts), cmtsOf(p.postCmts)) {
+		mismatch("stmt comments")
+	}
+}
+
+func TestReparseReuse(t *testing.T) {
+	code := "// a\nlet a = 1\nlet b = a\nfunction f() { return a }\nthrow f()\n/* b */\nclass A { #c = a }"
+	p := NewParser(span.NewSource("", code), reparseOpts(false))
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	old := append([]Node{}, ast.(*Prog).stmts...)
+
+	i := strings.Index(code, "return a")
+	edits := []Edit{{span.Range{Lo: uint32(i + 7), Hi: uint32(i + 8)}, "a + 1"}}
+	ast, err = p.Reparse(ast, edits)
+	AssertEqual(t, nil, err, "should be ok")
+
+	stmts := ast.(*Prog).stmts
+	AssertEqual(t, true, old[0] == stmts[0], "should reuse the statement before the edits")
+	AssertEqual(t, false, old[1] == stmts[1], "should reparse the statement before the edited one")
+	AssertEqual(t, false, old[2] == stmts[2], "should reparse the edited statement")
+	AssertEqual(t, true, old[3] == stmts[3], "should reuse the statement after the edits")
+	AssertEqual(t, true, old[4] == stmts[4], "should reuse the statement after the edits")
+	AssertEqual(t, "class A { #c = a }", p.Source().RngText(stmts[4].Range()), "should shift the range")
+
+	code = code[:i+7] + "a + 1" + code[i+8:]
+	assertReparsed(t, "reuse", p, ast, err, code, false)
+}
+
+func TestReparseFallback(t *testing.T) {
+	code := "'use strict'\nvar a\nlet b"
+	p := NewParser(span.NewSource("", code), reparseOpts(false))
+	ast, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	old := ast.(*Prog).stmts[2]
+
+	// the redeclaration makes the reuse unsafe
+	ast, err = p.Reparse(ast, []Edit{{span.Range{Lo: 17, Hi: 18}, "b"}})
+	AssertEqual(t, "Identifier `b` has already been declared at (3:4)", err.Error(), "should be failed")
+	assertReparsed(t, "fallback", p, ast, err, "'use strict'\nvar b\nlet b", false)
+
+	p = NewParser(span.NewSource("", code), reparseOpts(false))
+	ast, _ = p.Prog()
+	old = ast.(*Prog).stmts[2]
+	ast, err = p.Reparse(ast, []Edit{{span.Range{Lo: 0, Hi: 12}, ""}})
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, true, old == ast.(*Prog).stmts[1], "should reuse the statement")
+	assertReparsed(t, "prologue", p, ast, err, "\nvar a\nlet b", false)
+
+	_, err = p.Reparse(ast, []Edit{{span.Range{Lo: 1, Hi: 3}, ""}, {span.Range{Lo: 2, Hi: 4}, ""}})
+	AssertEqual(t, "the edits overlap with each other or are out of the source", err.Error(), "should be failed")
+}
+
+var reparseSnippets = []string{
+	"a", "1", ";", "\n", " ", "(", ")", "{", "}", "[", "`", "'", "/", "*", ",", ".", "=",
+	"let x = 1;", "var a;", "var a = 2\n", "function f() {}", "/* c */", "// c\n", "'use strict';\n",
+	"class A {}", "throw e;", "x\n", "async ", "label: ", "export ", "import a from 'a'\n", "\n(b)",
+	"function g(): void;\n", "@d\n", "type T = 1\n", "#", "<", ">",
+}
+
+func randEdit(rnd *rand.Rand, code string, stmts []Node) Edit {
+	pos := func() uint32 {
+		for i := 0; i < 8; i++ {
+			ofst := rnd.Intn(len(code) + 1)
+			if ofst == len(code) || utf8.RuneStart(code[ofst]) {
+				return uint32(ofst)
+			}
+		}
+		return uint32(len(code))
+	}
+
+	switch rnd.Intn(4) {
+	case 0:
+		if len(stmts) > 0 {
+			rng := stmts[rnd.Intn(len(stmts))].Range()
+			if rnd.Intn(2) == 0 {
+				return Edit{rng, ""}
+			}
+			return Edit{span.Range{Lo: rng.Hi, Hi: rng.Hi}, "\n" + code[rng.Lo:rng.Hi]}
+		}
+	case 1:
+		lo := pos()
+		hi := lo
+		for i := 0; i < 3 && int(hi) < len(code); i++ {
+			_, n := utf8.DecodeRuneInString(code[hi:])
+			hi += uint32(n)
+		}
+		return Edit{span.Range{Lo: lo, Hi: hi}, ""}
+	}
+	lo := pos()
+	return Edit{span.Range{Lo: lo, Hi: lo}, reparseSnippets[rnd.Intn(len(reparseSnippets))]}
+}
+
+func applyEditsForTest(code string, edits []Edit) string {
+	ret, _, _, err := applyEdits(code, edits)
+	if err != nil {
+		panic(err)
+	}
+	return ret
+}
+
+func TestReparseRandomly(t *testing.T) {
+	files, err := filepath.Glob("../estree/test/fixture/*/*/input.*")
+	AssertEqual(t, nil, err, "should be ok")
+	more, err := filepath.Glob("../estree/test/fixture/*/*/*/input.*")
+	AssertEqual(t, nil, err, "should be ok")
+	files = append(files, more...)
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		AssertEqual(t, nil, err, "should be ok")
+		ts := strings.HasSuffix(file, ".ts")
+		if strings.Contains(file, "/flow/") || strings.HasSuffix(file, ".tsx") {
+			continue
+		}
+
+		code := string(b)
+		p := NewParser(span.NewSource("", code), reparseOpts(ts))
+		ast, err := p.Prog()
+		if err != nil {
+			continue
+		}
+		for round := 0; round < 6; round++ {
+			var stmts []Node
+			if prog, ok := ast.(*Prog); ok && err == nil {
+				stmts = prog.stmts
+			}
+			edits := make([]Edit, 0, 2)
+			for i := 0; i < 1+rnd.Intn(2); i++ {
+				e := randEdit(rnd, code, stmts)
+				ok := true
+				for _, o := range edits {
+					if e.Rng.Lo <= o.Rng.Hi && o.Rng.Lo <= e.Rng.Hi {
+						ok = false
+					}
+				}
+				if ok {
+					edits = append(edits, e)
+				}
+			}
+
+			name := fmt.Sprintf("%s#%d %v", file, round, edits)
+			old := ast
+			if err != nil {
+				old = nil
+			}
+			code = applyEditsForTest(code, edits)
+			ast, err = p.Reparse(old, edits)
+			assertReparsed(t, name, p, ast, err, code, ts)
+		}
+	}
+}
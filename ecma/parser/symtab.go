@@ -50,6 +50,7 @@ const (
 	BK_LET
 	BK_CONST
 	BK_PVT_FIELD
+	BK_USING // `using` and `await using`, they are const-like
 )
 
 type RefDefType uint32
@@ -146,6 +147,27 @@ type Scope struct {
 
 	// exports declared at this scope
 	Exports []*ExportDec
+
+	// the names declared into the scope in their order, it's only recorded for the global scope
+	// to find the bindings of the statements reused by `Parser.Reparse`
+	decls []scopeDecl
+}
+
+type scopeDecl struct {
+	name string
+	ref  *Ref // it's `nil` if the binding is deleted
+	pos  uint32
+
+	// the binding of the name before the declaration
+	prev *Ref
+	had  bool
+}
+
+func (s *Scope) recordDecl(name string, ref *Ref, pos uint32) {
+	if s.IsKind(SPK_GLOBAL) {
+		prev, had := s.Refs[name]
+		s.decls = append(s.decls, scopeDecl{name, ref, pos, prev, had})
+	}
 }
 
 func NewScope() *Scope {
@@ -161,6 +183,16 @@ func NewScope() *Scope {
 	return scope
 }
 
+// empties the scope for the reuse by `Arena`, its maps and slices are kept
+func (s *Scope) reset() {
+	*s = Scope{
+		Down:         emptySlice(s.Down),
+		uniqueLabels: emptyMap(s.uniqueLabels),
+		Labels:       emptySlice(s.Labels),
+		Refs:         emptyMap(s.Refs),
+	}
+}
+
 func (s *Scope) IsKind(kind ScopeKind) bool {
 	return s.Kind&kind != 0
 }
@@ -252,9 +284,14 @@ func (s *Scope) AddLocal(ref *Ref, name string, checkDup bool) bool {
 		return false
 	}
 
+	s.recordDecl(name, ref, ref.Id.rng.Lo)
+
 	// register binding to parent fn scope if it's `BK_VAR`
 	if ref.BindKind == BK_VAR {
 		ps := s.UpperFn()
+		if ps != s {
+			ps.recordDecl(name, ref, ref.Id.rng.Lo)
+		}
 		localInPs := ps.Refs[name]
 
 		if checkDup && localInPs != nil && localInPs.BindKind != BK_PARAM &&
@@ -446,6 +483,7 @@ func IsOneOfNs(r1, r2 *Ref) bool {
 }
 
 func (s *Scope) DelLocal(ref *Ref) {
+	s.recordDecl(ref.Id.val, nil, ref.Id.rng.Lo)
 	s.Refs[ref.Id.val] = nil
 }
 
@@ -493,25 +531,52 @@ type SymTab struct {
 	Cur       *Scope
 
 	scopeIdSeed int // the seed of scope id
+
+	// the scopes are allocated from it if it's not `nil`
+	arena *Arena
 }
 
 func NewSymTab(externals []string) *SymTab {
-	scope := NewScope()
+	return newSymTab(externals, nil)
+}
 
-	symtab := &SymTab{
+func newSymTab(externals []string, arena *Arena) *SymTab {
+	symtab := &SymTab{}
+	symtab.reset(externals, arena)
+	return symtab
+}
+
+// empties the symtab for the reuse, the index of the scopes is kept
+func (s *SymTab) reset(externals []string, arena *Arena) {
+	*s = SymTab{
 		Externals: externals,
-		Scopes:    make(map[int]*Scope),
-		Root:      scope,
-		Cur:       scope,
+		Scopes:    emptyMap(s.Scopes),
+		arena:     arena,
 	}
-	symtab.Scopes[scope.Id] = scope
-	return symtab
+	scope := s.newScope()
+	s.Root = scope
+	s.Cur = scope
+	s.Scopes[scope.Id] = scope
+}
+
+func (s *SymTab) newScope() *Scope {
+	if s.arena == nil {
+		return NewScope()
+	}
+	scope := s.arena.scopes.next()
+	if scope.Refs == nil {
+		scope.Down = make([]*Scope, 0)
+		scope.uniqueLabels = make(map[string]Node)
+		scope.Labels = make([]Node, 0)
+		scope.Refs = make(map[string]*Ref)
+	}
+	return scope
 }
 
 // `settled` to increase the scope id, otherwise the new entered scope will be
 // treated as a temporary one
 func (s *SymTab) EnterScope(fn bool, arrow bool, settled bool) *Scope {
-	scope := NewScope()
+	scope := s.newScope()
 
 	if settled {
 		s.scopeIdSeed += 1
@@ -72,6 +72,9 @@ func (t *Token) IsBin(notIn bool, ts bool) TokenValue {
 	if ts && IsName(t, "as", false) {
 		return T_TS_AS
 	}
+	if ts && IsName(t, "satisfies", false) {
+		return T_TS_SATISFIES
+	}
 	return T_ILLEGAL
 }
 
@@ -87,6 +90,30 @@ func (t *Token) Kind() *TokenKind {
 	return TokenKinds[t.value]
 }
 
+func (t *Token) Range() span.Range {
+	return t.rng
+}
+
+func (t *Token) AfterLineTerm() bool {
+	return t.afterLineTerm
+}
+
+// the pattern and the flags of the regexp token, `nil` is returned if it's not a regexp
+func (t *Token) Regexp() *TokExtRegexp {
+	if ext, ok := t.ext.(*TokExtRegexp); ok && t.value == T_REGEXP {
+		return ext
+	}
+	return nil
+}
+
+// reports whether the token is the name read in the JSX tags
+func (t *Token) IsJsxName() bool {
+	if ext, ok := t.ext.(*TokExtIdent); ok && t.value == T_NAME {
+		return ext.Jsx
+	}
+	return false
+}
+
 func (t *Token) IsPlainTpl() bool {
 	if t.value != T_TPL_HEAD {
 		return false
@@ -125,6 +152,8 @@ type TokExtStr struct {
 
 type TokExtIdent struct {
 	ContainsEscape bool
+	// the name is read in the JSX tags, like the tag names and the attribute names
+	Jsx bool
 }
 
 type IllegalEscapeInfo struct {
@@ -153,12 +182,23 @@ type TokExtRegexp struct {
 	flags   span.Range
 }
 
+func (e *TokExtRegexp) Pattern() span.Range {
+	return e.pattern
+}
+
+func (e *TokExtRegexp) Flags() span.Range {
+	return e.flags
+}
+
 type TokenValue int
 
 const (
 	T_ILLEGAL TokenValue = iota
 	T_EOF
 	T_COMMENT
+	// the whitespaces and the line terminators between the tokens, they are only produced by
+	// `Tokenize` if they are asked for
+	T_WHITESPACE
 
 	// literals
 	T_NULL
@@ -321,9 +361,15 @@ const (
 	T_HYPHEN
 
 	T_TS_AS
+	T_TS_SATISFIES
 	T_TS_NO_NULL
 	T_AT
 
+	// the kinds of the explicit resource management declarations, they are not produced by
+	// the lexer since `using` is a plain identifier
+	T_USING
+	T_AWAIT_USING
+
 	T_TOKEN_DEF_END
 )
 
@@ -349,6 +395,7 @@ var TokenKinds = [T_TOKEN_DEF_END + 1]*TokenKind{
 	{T_ILLEGAL, "T_ILLEGAL", 0, false, false, false},
 	{T_EOF, "EOF", 0, false, false, false},
 	{T_COMMENT, "comment", 0, false, false, false},
+	{T_WHITESPACE, "whitespace", 0, false, false, false},
 
 	// literals
 	{T_NULL, "null", 0, false, true, true},
@@ -411,7 +458,7 @@ var TokenKinds = [T_TOKEN_DEF_END + 1]*TokenKind{
 	{T_CTX_KEYWORD_STRICT_END, "contextual keyword strict end", 0, false, false, false},
 	{T_AS, "as", 0, false, false, false},
 	{T_ASYNC, "async", 0, false, false, false},
-	{T_AWAIT, "await", 0, true, false, false},
+	{T_AWAIT, "await", 0, true, false, true},
 	{T_FROM, "from", 0, false, false, false},
 	{T_GET, "get", 0, false, false, false},
 	{T_META, "meta", 0, false, false, false},
@@ -509,9 +556,13 @@ var TokenKinds = [T_TOKEN_DEF_END + 1]*TokenKind{
 	{T_HYPHEN, "-", 0, false, false, false},
 
 	{T_TS_AS, "as", 12, false, true, false},
+	{T_TS_SATISFIES, "satisfies", 12, false, true, false},
 	{T_TS_NO_NULL, "!", 0, false, false, false},
 	{T_AT, "@", 0, false, false, true},
 
+	{T_USING, "using", 0, false, false, false},
+	{T_AWAIT_USING, "await using", 0, false, false, false},
+
 	{T_TOKEN_DEF_END, "token end def", 0, false, false, false},
 }
 
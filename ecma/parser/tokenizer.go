@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type TokenizeOpts struct {
+	Feature Feature
+	// includes the comments in the tokens, the hashbang is also reported as a comment
+	Comments bool
+	// includes the whitespaces and the line terminators between the tokens
+	Whitespaces bool
+}
+
+func NewTokenizeOpts() *TokenizeOpts {
+	return &TokenizeOpts{
+		Feature: defaultFeatures,
+	}
+}
+
+// tokenizes the source without parsing it, it's used by the syntax highlighters and the lint
+// rules based on tokens
+//
+// since there is no parser to tell the lexer the context, the slashes are resolved as either
+// the regexps or the divisions by the brackets before them:
+//
+// ```js
+// if (a) /b/.test(c) // regexp since the parens belong to `if`
+// f(a) / b / c       // division
+// {} /b/.test(c)     // regexp after the block
+// x = {} / b         // division after the object literal
+// f(function(){} / 1) // division after the body of function expression
+// ```
+//
+// the JSX is not recognized since the lexer needs the parser to switch its modes, the tokens
+// consumed by the parser are available by `Parser.Tokens` for the code which contains JSX
+//
+// the tokens before the illegal one are returned with the error if there is any
+func Tokenize(src *span.Source, opts *TokenizeOpts) ([]Token, error) {
+	if opts == nil {
+		opts = NewTokenizeOpts()
+	}
+
+	t := &tokenizer{
+		src:      src,
+		opts:     opts,
+		toks:     make([]Token, 0, src.Len()/4),
+		brackets: make([]bracket, 0, 16),
+	}
+
+	l := NewLexer(src)
+	l.feat = opts.Feature
+	if opts.Feature&FEAT_STRICT != 0 {
+		l.AddMode(LM_STRICT)
+	}
+	if opts.Feature&FEAT_TS != 0 || opts.Feature&FEAT_DTS != 0 {
+		l.AddMode(LM_TS)
+	}
+	l.isRegexp = t.regexpAllowed
+	t.l = l
+
+	for {
+		tok := l.Next()
+		if tok.value == T_ILLEGAL {
+			return t.toks, newLexerError(tok.ErrMsg(), src.Path, tok.rng.Lo, src)
+		}
+
+		t.trivia(tok.rng.Lo)
+		if tok.value == T_EOF {
+			break
+		}
+		t.track(tok)
+		t.emit(*tok)
+	}
+	return t.toks, nil
+}
+
+type bracket struct {
+	value TokenValue // `(`, `[` or `{`
+	// whether the slash after its closing counterpart is the beginning of regexp, it's `true`
+	// for the parens of `if (a)` and the blocks
+	regexpAfter bool
+	// whether the `{` is the body of function or class expression, it's a block however the
+	// slash after its `}` is division
+	exprBody bool
+}
+
+type tokenizer struct {
+	src  *span.Source
+	opts *TokenizeOpts
+	l    *Lexer
+	toks []Token
+
+	prev Token
+	// whether the last token follows `.` or `?.`, the keywords at there are the property names
+	// like `a.return / 2`
+	prevIsProp bool
+	brackets   []bracket
+	// whether the slash after the last closing bracket is the beginning of regexp
+	closeRegexp bool
+	// the depths of the brackets where the bodies of the pending function or class expressions
+	// will be opened, the brackets in their heads like `function (a = {}) {}` are deeper
+	exprBodies []int
+	// whether the last token begins a statement, it's used to tell `async function` declarations
+	// from the expressions
+	prevAtStmt bool
+
+	ofst   uint32 // the end of the last token or comment, used to find the whitespaces
+	cmtIdx int    // the count of the comments have been emitted
+}
+
+func (t *tokenizer) emit(tok Token) {
+	t.toks = append(t.toks, tok)
+	t.ofst = tok.rng.Hi
+}
+
+// emits the comments and the whitespaces before the offset
+func (t *tokenizer) trivia(ofst uint32) {
+	cmts := t.l.cmts
+	for ; t.cmtIdx < len(cmts); t.cmtIdx++ {
+		rng := cmts[t.cmtIdx]
+		t.whitespace(rng.Lo)
+		if t.opts.Comments {
+			multiline := strings.HasPrefix(t.src.RngText(rng), "/*")
+			t.emit(Token{value: T_COMMENT, rng: rng, ext: multiline})
+		} else {
+			t.ofst = rng.Hi
+		}
+	}
+	t.whitespace(ofst)
+}
+
+func (t *tokenizer) whitespace(ofst uint32) {
+	if t.opts.Whitespaces && ofst > t.ofst {
+		t.emit(Token{value: T_WHITESPACE, rng: span.Range{Lo: t.ofst, Hi: ofst}})
+	}
+	t.ofst = ofst
+}
+
+func (t *tokenizer) track(tok *Token) {
+	switch tok.value {
+	case T_PAREN_L:
+		pv := t.prev.value
+		t.brackets = append(t.brackets, bracket{T_PAREN_L, pv == T_IF || pv == T_WHILE || pv == T_FOR || pv == T_WITH, false})
+	case T_BRACKET_L:
+		t.brackets = append(t.brackets, bracket{T_BRACKET_L, false, false})
+	case T_BRACE_L:
+		b := bracket{T_BRACE_L, t.isBlock(), false}
+		if n := len(t.exprBodies); n > 0 && t.exprBodies[n-1] == len(t.brackets) {
+			b.exprBody = true
+			t.exprBodies = t.exprBodies[:n-1]
+		}
+		t.brackets = append(t.brackets, b)
+	case T_PAREN_R, T_BRACKET_R, T_BRACE_R:
+		t.closeRegexp = true
+		if n := len(t.brackets); n > 0 {
+			t.closeRegexp = t.brackets[n-1].regexpAfter && !t.brackets[n-1].exprBody
+			t.brackets = t.brackets[:n-1]
+		}
+	case T_FUNC, T_CLASS:
+		if t.isExprHead() {
+			t.exprBodies = append(t.exprBodies, len(t.brackets))
+		}
+	}
+	atStmt := t.isStmtStart()
+	t.prevIsProp = t.prev.value == T_DOT || t.prev.value == T_OPT_CHAIN
+	t.prev = *tok
+	t.prevAtStmt = atStmt
+}
+
+// reports whether the `function` or `class` is the beginning of an expression rather than a
+// declaration, the ones after `.` like `a.class` are property names
+func (t *tokenizer) isExprHead() bool {
+	if pv := t.prev.value; pv == T_DOT || pv == T_OPT_CHAIN {
+		return false
+	}
+	if IsName(&t.prev, "async", false) {
+		return !t.prevAtStmt
+	}
+	return t.regexpAllowed() && !t.isStmtStart()
+}
+
+// reports whether the token after the last one begins a statement
+func (t *tokenizer) isStmtStart() bool {
+	n := len(t.brackets)
+	switch t.prev.value {
+	case T_ILLEGAL, T_SEMI, T_BRACE_R, T_ELSE, T_DO, T_DEFAULT:
+		return true
+	case T_PAREN_R:
+		return t.closeRegexp
+	case T_BRACE_L, T_COLON:
+		// `{ function f() {} }` and `a: function f() {}`, not `{ a: function () {} }`
+		return n == 0 || t.brackets[n-1].value == T_BRACE_L && t.brackets[n-1].regexpAfter
+	}
+	return false
+}
+
+// reports whether the `{` is the beginning of a block rather than an object literal
+func (t *tokenizer) isBlock() bool {
+	switch pv := t.prev.value; pv {
+	case T_ILLEGAL, T_SEMI, T_BRACE_L, T_BRACE_R, T_PAREN_R, T_ARROW, T_ELSE, T_DO, T_TRY,
+		T_FINALLY, T_NAME:
+		return true
+	case T_COLON:
+		// `case a: {}` and `a: {}`, not `{ a: {} }`
+		n := len(t.brackets)
+		return n == 0 || t.brackets[n-1].value == T_BRACE_L && t.brackets[n-1].regexpAfter
+	default:
+		return t.prev.IsCtxKw() && pv != T_YIELD && pv != T_AWAIT
+	}
+}
+
+func (t *tokenizer) regexpAllowed() bool {
+	pv := t.prev.value
+	if t.prevIsProp && (pv == T_NAME || t.prev.IsKw()) {
+		return false
+	}
+	switch pv {
+	case T_ILLEGAL:
+		return true
+	case T_PAREN_R, T_BRACKET_R, T_BRACE_R:
+		return t.closeRegexp
+	case T_NAME:
+		// `in` is lexed as name
+		return IsName(&t.prev, "in", false)
+	case T_NAME_PVT, T_NUM, T_STRING, T_NULL, T_TRUE, T_FALSE, T_REGEXP, T_TPL_TAIL,
+		T_THIS, T_SUPER, T_INC, T_DEC:
+		return false
+	case T_TPL_HEAD:
+		return !t.prev.IsPlainTpl()
+	case T_YIELD, T_AWAIT, T_TYPE_OF, T_VOID, T_DELETE, T_INSTANCE_OF:
+		return true
+	default:
+		if t.prev.IsCtxKw() {
+			return false
+		}
+		if pv > T_KEYWORD_BEGIN && pv < T_KEYWORD_END {
+			return true
+		}
+		return TokenKinds[pv].BeforeExpr
+	}
+}
@@ -0,0 +1,160 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func tokenize(code string, opts *TokenizeOpts) ([]Token, *span.Source, error) {
+	src := span.NewSource("", code)
+	toks, err := Tokenize(src, opts)
+	return toks, src, err
+}
+
+func tokTexts(toks []Token, src *span.Source) []string {
+	ret := make([]string, len(toks))
+	for i, tok := range toks {
+		ret[i] = src.RngText(tok.Range())
+	}
+	return ret
+}
+
+func TestTokenizeRegexp(t *testing.T) {
+	toks, src, err := tokenize("if (a) /b/g.test(c)", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_REGEXP, toks[4].Val(), "should be ok")
+	AssertEqual(t, "b", src.RngText(toks[4].Regexp().Pattern()), "should be ok")
+	AssertEqual(t, "g", src.RngText(toks[4].Regexp().Flags()), "should be ok")
+
+	toks, _, err = tokenize("f(a) / b / c", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[4].Val(), "should be ok")
+	AssertEqual(t, T_DIV, toks[6].Val(), "should be ok")
+
+	toks, _, err = tokenize("{} /b/.test(c)", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_REGEXP, toks[2].Val(), "should be ok")
+
+	toks, _, err = tokenize("x = {} / b", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[4].Val(), "should be ok")
+
+	toks, _, err = tokenize("return /a/", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_REGEXP, toks[1].Val(), "should be ok")
+
+	// the operators in keyword form are followed by the operands
+	for code, i := range map[string]int{"typeof /a/": 1, "void /a/": 1, "delete /a/.b": 1, "b in /a/": 2, "b instanceof /a/": 2} {
+		toks, _, err = tokenize(code, nil)
+		AssertEqual(t, nil, err, "should be ok")
+		AssertEqual(t, T_REGEXP, toks[i].Val(), code)
+	}
+
+	// the keywords after `.` or `?.` are the property names
+	toks, _, err = tokenize("a.return / 2 / 1", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[3].Val(), "should be ok")
+	AssertEqual(t, T_DIV, toks[5].Val(), "should be ok")
+
+	toks, _, err = tokenize("a.default / 2", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[3].Val(), "should be ok")
+
+	toks, _, err = tokenize("a.in / 2 / 1", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[3].Val(), "should be ok")
+	AssertEqual(t, T_DIV, toks[5].Val(), "should be ok")
+
+	toks, _, err = tokenize("a?.typeof / 2 / 1", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_DIV, toks[3].Val(), "should be ok")
+
+	toks, _, err = tokenize("a?.[/b/]", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, T_REGEXP, toks[3].Val(), "should be ok")
+	// the slash after the body of function or class expression is division
+	for code, i := range map[string]int{
+		"f(function(){} / 1)":                     7,
+		"x = function f(a = {}) {} / 1":           12,
+		"x = async function () {} / 1":            8,
+		"x = class extends B {} / 1":              7,
+		"x = { a: function () {} / 1 }":           10,
+		"x = function () { function g() {} } / 1": 13,
+	} {
+		toks, _, err = tokenize(code, nil)
+		AssertEqual(t, nil, err, code)
+		AssertEqual(t, T_DIV, toks[i].Val(), code)
+	}
+
+	// the slash after the body of function or class declaration is the beginning of regexp
+	for code, i := range map[string]int{
+		"function f() {} /a/.test(b)":       6,
+		"async function f() {} /a/.test(b)": 7,
+		"class A {} /a/.test(b)":            4,
+		"{ function f() {} /a/.test(b) }":   7,
+		"export default function () {} /a/": 7,
+	} {
+		toks, _, err = tokenize(code, nil)
+		AssertEqual(t, nil, err, code)
+		AssertEqual(t, T_REGEXP, toks[i].Val(), code)
+	}
+}
+
+func TestTokenizeTpl(t *testing.T) {
+	toks, src, err := tokenize("`a${b}c${d}e` / 2", nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, "`a${ b }c${ d }e` / 2", strings.Join(tokTexts(toks, src), " "), "should be ok")
+	AssertEqual(t, T_DIV, toks[len(toks)-2].Val(), "should be ok")
+}
+
+func TestTokenizeTrivia(t *testing.T) {
+	opts := NewTokenizeOpts()
+	opts.Comments = true
+	opts.Whitespaces = true
+
+	code := "#!/usr/bin/env node\na /* b */ + c // d\n"
+	toks, src, err := tokenize(code, opts)
+	AssertEqual(t, nil, err, "should be ok")
+
+	var b strings.Builder
+	for _, text := range tokTexts(toks, src) {
+		b.WriteString(text)
+	}
+	AssertEqual(t, code, b.String(), "should be lossless")
+	AssertEqual(t, T_COMMENT, toks[0].Val(), "should be ok")
+	AssertEqual(t, T_WHITESPACE, toks[1].Val(), "should be ok")
+	AssertEqual(t, true, toks[2].AfterLineTerm(), "should be ok")
+
+	toks, _, err = tokenize(code, nil)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, 3, len(toks), "should be ok")
+}
+
+func TestTokenizeErr(t *testing.T) {
+	toks, _, err := tokenize("a = 'b", nil)
+	AssertEqual(t, 2, len(toks), "should be ok")
+	AssertEqual(t, "Unterminated string constant at (1:4)", err.Error(), "should be ok")
+}
+
+func TestParserTokens(t *testing.T) {
+	opts := NewParserOpts()
+	opts.Feature = opts.Feature.On(FEAT_TS)
+	opts.Tokens = true
+
+	// the tokens consumed during the failed speculation are discarded
+	p := NewParser(span.NewSource("", "a < b > (c); f<Array<T>>(d)"), opts)
+	_, err := p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+
+	texts := tokTexts(p.Tokens(), p.Source())
+	AssertEqual(t, "a < b > ( c ) ; f < Array < T > > ( d )", strings.Join(texts, " "), "should be ok")
+
+	opts = NewParserOpts()
+	p = NewParser(span.NewSource("", "a"), opts)
+	_, err = p.Prog()
+	AssertEqual(t, nil, err, "should be prog ok")
+	AssertEqual(t, 0, len(p.Tokens()), "should be ok")
+}
@@ -37,7 +37,10 @@ func (e ErrTypArgMissingGT) Error() string {
 var errTypArgMaybeJsx = errors.New("maybe jsx")
 
 func (p *Parser) newTypInfo(typ NodeType) *TypInfo {
-	if p.ts || (p.feat&FEAT_DECORATOR != 0 && (typ == N_STMT_CLASS || typ == N_METHOD)) {
+	if p.ts || (p.feat&FEAT_DECORATOR != 0 && (typ == N_STMT_CLASS || typ == N_METHOD || typ == N_FIELD)) {
+		if p.arena != nil {
+			return p.arena.typInfos.next()
+		}
 		return NewTypInfo()
 	}
 	return nil
@@ -60,6 +63,19 @@ func (p *Parser) tsTypAnnot() (Node, error) {
 	return nil, nil
 }
 
+// parses the entire source as a standalone type rather than a program, it's used to parse the
+// types appear out of the typescript code such as the ones in the jsdoc comments
+func (p *Parser) TsTyp() (Node, error) {
+	node, err := p.tsTyp(false, false, true)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.lexer.Peek(); tok.value != T_EOF {
+		return nil, p.errorTok(tok)
+	}
+	return node, nil
+}
+
 // for dealing with the ambiguous between `ParenthesizedType` and the `formalParamList`, eg:
 //
 // ```ts
@@ -74,6 +90,11 @@ func (p *Parser) tsTypAnnot() (Node, error) {
 // as a superset consists of `tsTyp` and `bindingPattern`, the parsed result will be judged by later
 // process by the time via method such as `tsRoughParamToParam`
 func (p *Parser) tsTyp(rough bool, canConst bool, canCond bool) (Node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
 	if p.lexer.Peek().value == T_NEW {
 		return p.tsConstructTyp(span.Range{}, false)
 	}
@@ -113,6 +134,10 @@ func (p *Parser) tsUnionOrIntersectType(lhs Node, minPcd int, rough bool, canCon
 		if av != T_BIT_OR && av != T_BIT_AND {
 			break
 		}
+		// the closing `|}` of the flow exact object
+		if p.flow && av == T_BIT_OR && p.lexer.Peek2nd().value == T_BRACE_R {
+			break
+		}
 
 		kind := TokenKinds[av]
 		pcd := kind.Pcd
@@ -168,6 +193,16 @@ func (p *Parser) tsUnionOrIntersectType(lhs Node, minPcd int, rough bool, canCon
 		}
 	}
 
+	// the pending elems are not combined in the loop if the token after them has the higher
+	// precedence than the bit-op, such as `>` in `Array<a | b>`
+	if lhs != nil && nt != N_ILLEGAL {
+		if nt == N_TS_UNION_TYP {
+			lhs = &TsUnionTyp{N_TS_UNION_TYP, p.finRng(lhs.Range()), firstOp, elems, span.Range{}}
+		} else {
+			lhs = &TsIntersectTyp{N_TS_INTERSECT_TYP, p.finRng(lhs.Range()), firstOp, elems, span.Range{}}
+		}
+	}
+
 	// for type dec with prefix bit-op: `type t = | a | b`
 	if lhs == nil {
 		return rhs, nil
@@ -314,7 +349,7 @@ func (p *Parser) tsRoughParamToParam(node Node) (Node, error) {
 		d := n.(*TsPredef)
 		ti := p.newTypInfo(N_TS_ANY)
 		ti.SetQues(d.ques)
-		return &Ident{N_NAME, d.rng, p.RngText(d.rng), false, false, span.Range{}, false, ti}, nil
+		return p.newIdent(Ident{N_NAME, d.rng, p.RngText(d.rng), false, false, span.Range{}, false, ti}), nil
 	case N_TS_VOID:
 		return nil, p.errorAtLoc(n.Range(), ERR_UNEXPECTED_TOKEN)
 	case N_TS_REF:
@@ -369,7 +404,7 @@ func (p *Parser) tsRoughParamToParam(node Node) (Node, error) {
 		return nil, p.errorAtLoc(n.Range(), ERR_UNEXPECTED_TOKEN)
 	case N_TS_THIS:
 		t := n.(*TsThis)
-		return &Ident{N_NAME, t.rng, p.RngText(t.rng), false, false, span.Range{}, true, p.newTypInfo(N_NAME)}, nil
+		return p.newIdent(Ident{N_NAME, t.rng, p.RngText(t.rng), false, false, span.Range{}, true, p.newTypInfo(N_NAME)}), nil
 	case N_TS_NS_NAME:
 		s := n.(*TsNsName)
 		return nil, p.errorAtLoc(s.dot, ERR_UNEXPECTED_TOKEN)
@@ -424,7 +459,7 @@ func (p *Parser) tsPropToIdxSig(prop *TsProp) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &TsIdxSig{N_TS_IDX_SIG, prop.rng, name, typAnnot, span.Range{}}, nil
+	return &TsIdxSig{N_TS_IDX_SIG, prop.rng, name, typAnnot, span.Range{}, span.Range{}}, nil
 }
 
 // `RoughParam` is something like `a:b` which `a` is a rough-type and `b` is typAnnot
@@ -492,7 +527,7 @@ func (p *Parser) tsRoughParamToTyp(node Node, raise bool) (Node, error) {
 
 // `ParenthesizedType` or`FunctionType`
 func (p *Parser) tsParen(keepParen bool) (Node, error) {
-	typParams, err := p.tsTryTypParams()
+	typParams, err := p.tsTryTypParams(false)
 	if err != nil {
 		return nil, err
 	}
@@ -703,7 +738,7 @@ func (p *Parser) tsToBeTupleLabel(node Node) Node {
 		}
 	}
 	if pd, ok := node.(*TsPredef); ok {
-		return &Ident{N_NAME, pd.rng, p.RngText(pd.rng), false, false, span.Range{}, true, p.newTypInfo(N_NAME)}
+		return p.newIdent(Ident{N_NAME, pd.rng, p.RngText(pd.rng), false, false, span.Range{}, true, p.newTypInfo(N_NAME)})
 	}
 	return nil
 }
@@ -849,7 +884,7 @@ func (p *Parser) tsPredefOrRef(tok *Token) (Node, error) {
 	}
 
 	if name == "intrinsic" && !p.scope().IsKind(SPK_TS_MAY_INTRINSIC) {
-		node = &Ident{N_NAME, node.Range(), "intrinsic", false, tok.ContainsEscape(), span.Range{}, tok.IsKw(), p.newTypInfo(N_NAME)}
+		node = p.newIdent(Ident{N_NAME, node.Range(), "intrinsic", false, tok.ContainsEscape(), span.Range{}, tok.IsKw(), p.newTypInfo(N_NAME)})
 	} else if typ, ok := builtinTyp[name]; ok {
 		// predef
 		if p.lexer.Peek().value != T_DOT {
@@ -894,7 +929,13 @@ func (p *Parser) tsPrimary(rough bool, canConst, canCond bool) (Node, error) {
 	av := ahead.value
 	if av == T_PAREN_L || av == T_LT {
 		// paren type
-		node, err := p.tsParen(true)
+		var node Node
+		var err error
+		if p.flow {
+			node, err = p.flowParen()
+		} else {
+			node, err = p.tsParen(true)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -926,7 +967,7 @@ func (p *Parser) tsPrimary(rough bool, canConst, canCond bool) (Node, error) {
 			str := ahead.text
 			if str == "infer" {
 				node, err = p.tsInfer()
-			} else if str == "keyof" || str == "unique" {
+			} else if !p.flow && (str == "keyof" || str == "unique") {
 				node, err = p.tsTypOp(ahead)
 			} else {
 				ok, err := p.tsAheadIsRo(ahead)
@@ -995,6 +1036,19 @@ func (p *Parser) tsPrimary(rough bool, canConst, canCond bool) (Node, error) {
 	} else if av == T_IMPORT {
 		// `let a: import("a")<a>;`
 		return p.tsImport()
+	} else if av == T_HOOK && p.flow {
+		// the flow nullable type `?T`
+		p.lexer.Next()
+		arg, err := p.tsPrimary(rough, canConst, false)
+		if err != nil {
+			return nil, err
+		}
+		return &TsTypOp{N_TS_TYP_OP, p.finRng(rng), "?", arg, span.Range{}}, nil
+	} else if av == T_INTERFACE && p.flow {
+		node, err = p.flowItfTyp()
+		if err != nil {
+			return nil, err
+		}
 	} else if av == T_SUB {
 		rng := p.lexer.Next().rng
 		tok, err := p.nextMustTok(T_NUM)
@@ -1002,7 +1056,7 @@ func (p *Parser) tsPrimary(rough bool, canConst, canCond bool) (Node, error) {
 			return nil, err
 		}
 		numRng := tok.rng
-		arg := &NumLit{N_LIT_NUM, p.finRng(numRng), span.Range{}}
+		arg := p.newNumLit(NumLit{N_LIT_NUM, p.finRng(numRng), span.Range{}})
 		un := &UnaryExpr{N_EXPR_UNARY, p.finRng(rng), T_SUB, arg, span.Range{}}
 		return &TsLit{N_TS_LIT, un.Range(), un, span.Range{}}, nil
 	} else if av == T_TPL_HEAD {
@@ -1041,7 +1095,7 @@ func (p *Parser) tsImport() (Node, error) {
 		return nil, p.errorAt(tok.value, tok.rng, ERR_IMPORT_ARG_SHOULD_BE_STR)
 	}
 	strRng := tok.rng
-	arg := &StrLit{N_LIT_STR, p.finRng(strRng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, p.newTypInfo(N_LIT_STR)}
+	arg := p.newStrLit(StrLit{N_LIT_STR, p.finRng(strRng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, p.newTypInfo(N_LIT_STR)})
 	if _, err := p.nextMustTok(T_PAREN_R); err != nil {
 		return nil, err
 	}
@@ -1160,6 +1214,9 @@ func (p *Parser) tsMapped(rng span.Range, add, sub bool, roLoc span.Range, key N
 func (p *Parser) tsObj(rough bool) (Node, error) {
 	tok := p.lexer.Next() // `{`
 	rng := tok.rng
+	if p.flow && !rough {
+		return p.flowObj(rng)
+	}
 	props := make([]Node, 0, 1)
 
 	ahead := p.lexer.Peek()
@@ -1190,7 +1247,7 @@ func (p *Parser) tsObj(rough bool) (Node, error) {
 			p.lexer.Next()
 		}
 	}
-	return &TsObj{N_TS_LIT_OBJ, p.finRng(rng), props, span.Range{}}, nil
+	return &TsObj{N_TS_LIT_OBJ, p.finRng(rng), props, span.Range{}, false, false}, nil
 }
 
 func (p *Parser) tsNewSig(rng span.Range) (Node, error) {
@@ -1285,15 +1342,15 @@ func (p *Parser) tsIdxSig(rng span.Range, braceL span.Range, roLoc span.Range, c
 			if err != nil {
 				return nil, err
 			}
-			return &TsProp{N_TS_PROP, p.finRng(rng), key, val, ques, PK_METHOD, span.Range{}, false}, nil
+			return &TsProp{N_TS_PROP, p.finRng(rng), key, val, ques, PK_METHOD, span.Range{}, false, span.Range{}}, nil
 		}
 	}
 	p.advanceIfSemi(false)
 
 	if typAnnot != nil {
-		return &TsIdxSig{N_TS_IDX_SIG, p.finRng(rng), key, val, ques}, nil
+		return &TsIdxSig{N_TS_IDX_SIG, p.finRng(rng), key, val, ques, span.Range{}}, nil
 	}
-	return &TsProp{N_TS_PROP, p.finRng(rng), key, val, ques, PK_INIT, bracketL, !roLoc.Empty()}, nil
+	return &TsProp{N_TS_PROP, p.finRng(rng), key, val, ques, PK_INIT, bracketL, !roLoc.Empty(), span.Range{}}, nil
 }
 
 var modifiers = map[string]int{
@@ -1332,7 +1389,7 @@ func (p *Parser) tsProp(rough bool, canMapped bool, braceL span.Range) (Node, er
 		if !roLoc.Empty() {
 			return nil, p.errorAtLoc(roLoc, ERR_INVALID_RO_MODIFIER_IN_TS_OBJ)
 		}
-		ps, err := p.tsTypParams()
+		ps, err := p.tsTypParams(false)
 		if err != nil {
 			return nil, err
 		}
@@ -1350,6 +1407,9 @@ func (p *Parser) tsProp(rough bool, canMapped bool, braceL span.Range) (Node, er
 	}
 	if !rough && av == T_BRACKET_L {
 		// IndexSignature
+		if p.flow {
+			return p.flowIdxSig(rng)
+		}
 		return p.tsIdxSig(rng, braceL, roLoc, canMapped)
 	}
 	if av == T_PAREN_L {
@@ -1424,7 +1484,7 @@ func (p *Parser) tsProp(rough bool, canMapped bool, braceL span.Range) (Node, er
 		av := ahead.value
 		if av != T_PAREN_L && av != T_LT {
 			p.advanceIfSemi(false)
-			return &TsProp{N_TS_PROP, p.finRng(rng), name, typAnnot, ques, kind, span.Range{}, !roLoc.Empty()}, nil
+			return &TsProp{N_TS_PROP, p.finRng(rng), name, typAnnot, ques, kind, span.Range{}, !roLoc.Empty(), span.Range{}}, nil
 		}
 
 		// MethodSignature is deserved
@@ -1445,7 +1505,7 @@ func (p *Parser) tsProp(rough bool, canMapped bool, braceL span.Range) (Node, er
 			return nil, err
 		}
 
-		return &TsProp{N_TS_PROP, p.finRng(rng), name, callSig, ques, kind, span.Range{}, false}, nil
+		return &TsProp{N_TS_PROP, p.finRng(rng), name, callSig, ques, kind, span.Range{}, false, span.Range{}}, nil
 	}
 
 	key, compute, err := p.propName(false, true, true)
@@ -1476,7 +1536,7 @@ func (p *Parser) tsProp(rough bool, canMapped bool, braceL span.Range) (Node, er
 		}
 		return nil, p.errorTok(tok)
 	}
-	return &TsProp{N_TS_PROP, p.finRng(key.Range()), key, value, ques, PK_INIT, compute, !roLoc.Empty()}, nil
+	return &TsProp{N_TS_PROP, p.finRng(key.Range()), key, value, ques, PK_INIT, compute, !roLoc.Empty(), span.Range{}}, nil
 }
 
 func (p *Parser) tsPropName() (Node, error) {
@@ -1486,21 +1546,21 @@ func (p *Parser) tsPropName() (Node, error) {
 	switch tok.value {
 	case T_NUM:
 		p.lexer.Next()
-		return &NumLit{N_LIT_NUM, p.finRng(rng), span.Range{}}, nil
+		return p.newNumLit(NumLit{N_LIT_NUM, p.finRng(rng), span.Range{}}), nil
 	case T_STRING:
 		p.lexer.Next()
 		legacyOctalEscapeSeq := tok.HasLegacyOctalEscapeSeq()
 		if p.scope().IsKind(SPK_STRICT) && legacyOctalEscapeSeq {
 			return nil, p.errorAtLoc(p.finRng(rng), ERR_LEGACY_OCTAL_ESCAPE_IN_STRICT_MODE)
 		}
-		return &StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), legacyOctalEscapeSeq, span.Range{}, nil}, nil
+		return p.newStrLit(StrLit{N_LIT_STR, p.finRng(rng), p.TokText(tok), legacyOctalEscapeSeq, span.Range{}, nil}), nil
 	case T_NAME:
 		return p.ident(nil, false)
 	}
 	if kw, ok := tok.CanBePropKey(); ok {
 		keyName := p.TokText(tok)
 		p.lexer.Next()
-		return &Ident{N_NAME, p.finRng(rng), keyName, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}, nil
+		return p.newIdent(Ident{N_NAME, p.finRng(rng), keyName, false, tok.ContainsEscape(), span.Range{}, kw, p.newTypInfo(N_NAME)}), nil
 	}
 	return nil, p.errorTok(tok)
 }
@@ -1522,12 +1582,11 @@ func (p *Parser) tsTypParam(id Node, ext, in bool) (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		if p.lexer.Peek().value == T_ASSIGN {
-			p.lexer.Next()
-			val, err = p.tsTyp(false, false, false)
-			if err != nil {
-				return nil, err
-			}
+	} else if ext && p.flow && av == T_COLON {
+		// the bound of flow `<T: string>`
+		cons, err = p.tsTypAnnot()
+		if err != nil {
+			return nil, err
 		}
 	} else if in && av == T_NAME && ahead.text == "in" && !ahead.ContainsEscape() {
 		p.lexer.Next()
@@ -1536,17 +1595,90 @@ func (p *Parser) tsTypParam(id Node, ext, in bool) (Node, error) {
 			return nil, err
 		}
 	}
-	return &TsParam{N_TS_PARAM, p.finRng(id.Range()), id, cons, val}, nil
+	if ext && p.lexer.Peek().value == T_ASSIGN {
+		p.lexer.Next()
+		val, err = p.tsTyp(false, false, false)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &TsParam{N_TS_PARAM, p.finRng(id.Range()), id, cons, val, false, false, false, span.Range{}}, nil
+}
+
+type tsTypParamMods struct {
+	rng      span.Range
+	in       bool
+	out      bool
+	cst      bool
+	variance span.Range
+	inOut    span.Range // the range of the first one of `in` and `out`
+}
+
+func (m *tsTypParamMods) apply(n *TsParam) {
+	if m.rng.Empty() {
+		return
+	}
+	n.rng.Lo = m.rng.Lo
+	n.in, n.out, n.cst = m.in, m.out, m.cst
+	n.variance = m.variance
 }
 
-func (p *Parser) tsTryTypParams() (Node, error) {
+// the modifiers before the name of type parameter: the `const` modifier from ts5.0 and the
+// variance annotations `in` and `out` from ts4.7, they are treated as the modifiers only if
+// they are followed by the names, so `<in>` declares the param named `in` and `<const>x` is
+// the type assertion
+func (p *Parser) tsTypParamMods() tsTypParamMods {
+	var mods tsTypParamMods
+	for {
+		ahead := p.lexer.Peek()
+		if ahead.value == T_CONST && !mods.cst && p.tsAheadIsTypParamName() {
+			mods.cst = true
+		} else if IsName(ahead, "in", false) && !mods.in && !mods.out && p.tsAheadIsTypParamName() {
+			mods.in = true
+			mods.inOut = ahead.rng
+		} else if IsName(ahead, "out", false) && !mods.out && p.tsAheadIsTypParamName() {
+			mods.out = true
+			if mods.inOut.Empty() {
+				mods.inOut = ahead.rng
+			}
+		} else if p.flow && (ahead.value == T_ADD || ahead.value == T_SUB) && mods.variance.Empty() && p.tsAheadIsTypParamName() {
+			// the variance sigils of flow `<+T, -U>`
+			mods.variance = ahead.rng
+		} else {
+			break
+		}
+		tok := p.lexer.Next()
+		if mods.rng.Empty() {
+			mods.rng = tok.rng
+		}
+	}
+	return mods
+}
+
+func (p *Parser) errorVarianceMod(rng span.Range, in bool) error {
+	mod := "out"
+	if in {
+		mod = "in"
+	}
+	return p.errorAtLoc(rng, fmt.Sprintf(ERR_TPL_VARIANCE_ON_NON_TYPE_PARAM, mod))
+}
+
+func (p *Parser) tsAheadIsTypParamName() bool {
+	ahead := p.lexer.Peek2nd()
+	av := ahead.value
+	return av == T_NAME || av > T_CTX_KEYWORD_BEGIN && av < T_CTX_KEYWORD_END
+}
+
+func (p *Parser) tsTryTypParams(variance bool) (Node, error) {
 	if !p.ts || p.lexer.Peek().value != T_LT {
 		return nil, nil
 	}
-	return p.tsTypParams()
+	return p.tsTypParams(variance)
 }
 
-func (p *Parser) tsTypParams() (Node, error) {
+// the variance annotations `in` and `out` are permitted only if `variance` is true, which is
+// the case of the type params of the classes, interfaces and type aliases
+func (p *Parser) tsTypParams(variance bool) (Node, error) {
 	rng := p.lexer.Next().rng
 	ps := make([]Node, 0, 1)
 	for {
@@ -1559,10 +1691,15 @@ func (p *Parser) tsTypParams() (Node, error) {
 			p.lexer.Next()
 		}
 
+		mods := p.tsTypParamMods()
+		if !variance && !mods.inOut.Empty() {
+			return nil, p.errorVarianceMod(mods.inOut, mods.in)
+		}
 		pa, err := p.tsTypParam(nil, true, false)
 		if err != nil {
 			return nil, err
 		}
+		mods.apply(pa.(*TsParam))
 		ps = append(ps, pa)
 	}
 	if len(ps) == 0 {
@@ -1622,7 +1759,23 @@ func (p *Parser) tsTryTypArgs(asyncLoc span.Range, noJsx bool) (Node, error) {
 
 		p.popState()
 		ofst := p.lexer.src.Ofst()
+		if p.flow {
+			p.pushState()
+		}
 		jsx, err := p.jsx(true, true)
+		if err != nil && p.flow {
+			// flow takes `<T>(x: T): T => x` as the generic arrow rather than the unterminated jsxElem
+			p.popState()
+			p.pushState()
+			if node, err := p.tsTypArgs(true, true); err == nil && p.lexer.Peek().value == T_PAREN_L {
+				p.discardState()
+				return node, nil
+			}
+			p.popState()
+			return nil, err
+		} else if p.flow {
+			p.discardState()
+		}
 		if err != nil {
 			if pe, ok := err.(*ParserError); ok {
 				if pe.msg == ERR_UNTERMINATED_JSX_CONTENTS {
@@ -1649,7 +1802,7 @@ func (p *Parser) tsTryTypArgs(asyncLoc span.Range, noJsx bool) (Node, error) {
 // for avoiding lookbehind the process should accept the input as seqExpr then try to
 // transform the subtree of seqExpr to typArgs if its followed by `>`
 func (p *Parser) tsTryTypArgsAfterAsync(asyncLoc span.Range) (Node, error) {
-	name := &Ident{N_NAME, asyncLoc, p.RngText(asyncLoc), false, false, span.Range{}, true, p.newTypInfo(N_NAME)}
+	name := p.newIdent(Ident{N_NAME, asyncLoc, p.RngText(asyncLoc), false, false, span.Range{}, true, p.newTypInfo(N_NAME)})
 	binExpr, err := p.binExpr(name, 0, false, false, true, false)
 	if err != nil {
 		return nil, err
@@ -1702,6 +1855,9 @@ func (p *Parser) tsCheckTypArgs(node Node) error {
 	for i, arg := range nodes {
 		if arg.Type() == N_TS_PARAM {
 			pn := arg.(*TsParam)
+			if pn.hasMods() {
+				return p.errorAtLoc(pn.rng, ERR_UNEXPECTED_TOKEN)
+			}
 			if pn.cons != nil {
 				return p.errorAtLoc(arg.(*TsParam).cons.Range(), ERR_UNEXPECTED_TOKEN)
 			}
@@ -1719,9 +1875,13 @@ func (p *Parser) tsTypArgsToTypParams(node Node) (Node, error) {
 
 	var err error
 	for i, n := range nodes {
+		// the type params of the arrow functions can not have the variance annotations
+		if pn, ok := n.(*TsParam); ok && (pn.in || pn.out) {
+			return nil, p.errorVarianceMod(pn.rng, pn.in)
+		}
 		n, err = p.tsRoughParamToParam(n)
 		if n.Type() == N_NAME {
-			n = &TsParam{N_TS_PARAM, n.Range(), n, nil, nil}
+			n = &TsParam{N_TS_PARAM, n.Range(), n, nil, nil, false, false, false, span.Range{}}
 		}
 		nodes[i] = n
 		if err != nil {
@@ -1752,6 +1912,11 @@ func (p *Parser) tsTypArgs(canConst bool, noJsx bool) (Node, error) {
 	args := make([]Node, 0, 1)
 	jsx := p.feat&FEAT_JSX != 0
 	for {
+		// the type parameters of the generic arrow functions like `<const T,>(a: T) => a` are
+		// firstly parsed as type arguments, so the modifiers of type parameter are accepted here
+		// and reported by `tsCheckTypArgs` if the arguments turn out to be type arguments
+		mods := p.tsTypParamMods()
+
 		ahead := p.lexer.Peek()
 		av := ahead.value
 		if av == T_GT {
@@ -1789,11 +1954,33 @@ func (p *Parser) tsTypArgs(canConst bool, noJsx bool) (Node, error) {
 			if err != nil {
 				return nil, err
 			}
-			arg = &TsParam{N_TS_PARAM, p.finRng(id.Range()), id, cons, nil}
-		} else if !noJsx && jsx && (av == T_NAME || ahead.IsKw() || av == T_DIV || av == T_BRACE_L || (av == T_GT && nameLike && len(args) == 0)) {
+			arg = &TsParam{N_TS_PARAM, p.finRng(id.Range()), id, cons, nil, false, false, false, span.Range{}}
+		} else if p.flow && av == T_COLON {
+			// the bound of flow `<T: string>(a: T) => a`
+			id, err := p.tsPredefToName(arg)
+			if err != nil {
+				return nil, err
+			}
+			cons, err := p.tsTypAnnot()
+			if err != nil {
+				return nil, err
+			}
+			arg = &TsParam{N_TS_PARAM, p.finRng(id.Range()), id, cons, nil, false, false, false, span.Range{}}
+		} else if !noJsx && jsx && mods.rng.Empty() && (av == T_NAME || ahead.IsKw() || av == T_DIV || av == T_BRACE_L || (av == T_GT && nameLike && len(args) == 0)) {
 			return nil, errTypArgMaybeJsx
 		}
 
+		if !mods.rng.Empty() {
+			if arg.Type() != N_TS_PARAM {
+				id, err := p.tsPredefToName(arg)
+				if err != nil {
+					return nil, err
+				}
+				arg = &TsParam{N_TS_PARAM, id.Range(), id, nil, nil, false, false, false, span.Range{}}
+			}
+			mods.apply(arg.(*TsParam))
+		}
+
 		args = append(args, arg)
 
 		ahead = p.lexer.Peek()
@@ -1817,20 +2004,37 @@ func (p *Parser) tsCallSig(typParams Node, rng span.Range, kind PropKind) (Node,
 		p.scope().AddKind(SPK_METHOD)
 	}
 
-	params, tp, _, err := p.paramList(false, kind, typParams == nil)
-	if err != nil {
-		return nil, err
-	}
-	if tp != nil && (kind == PK_GETTER || kind == PK_SETTER) {
-		return nil, p.errorAtLoc(tp.Range(), ERR_ACCESSOR_WITH_TYPE_PARAMS)
-	}
-
+	var params []Node
+	var tp Node
+	var err error
 	opts := NewTsCheckParamOpts()
 	opts.getter = kind == PK_GETTER
 	opts.setter = kind == PK_SETTER
 	opts.rng = rng
-	if err = p.tsCheckParams(params, opts); err != nil {
-		return nil, err
+	if p.flow {
+		// the params of flow can have no name like `{ m(string): void }`
+		if typParams == nil {
+			if tp, err = p.tsTryTypParams(false); err != nil {
+				return nil, err
+			}
+		}
+		if _, err = p.nextMustTok(T_PAREN_L); err != nil {
+			return nil, err
+		}
+		if params, _, err = p.flowFnParams(); err != nil {
+			return nil, err
+		}
+	} else {
+		params, tp, _, err = p.paramList(false, kind, typParams == nil)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.tsCheckParams(params, opts); err != nil {
+			return nil, err
+		}
+	}
+	if tp != nil && (kind == PK_GETTER || kind == PK_SETTER) {
+		return nil, p.errorAtLoc(tp.Range(), ERR_ACCESSOR_WITH_TYPE_PARAMS)
 	}
 
 	typAnnot, err := p.tsTypAnnot()
@@ -1862,7 +2066,7 @@ func (p *Parser) tsTypDec(rng span.Range, skipDec bool) (Node, error) {
 		return nil, err
 	}
 
-	ref := NewRef()
+	ref := p.newRef()
 	ref.Id = name
 	ref.BindKind = BK_LET
 	ref.Typ = RDT_TYPE
@@ -1875,7 +2079,7 @@ func (p *Parser) tsTypDec(rng span.Range, skipDec bool) (Node, error) {
 		return name, nil
 	}
 
-	params, err := p.tsTryTypParams()
+	params, err := p.tsTryTypParams(true)
 	if err != nil {
 		return nil, err
 	}
@@ -1900,7 +2104,7 @@ func (p *Parser) tsTypDec(rng span.Range, skipDec bool) (Node, error) {
 
 	ti.SetTypParams(params)
 	ti.SetTypAnnot(typAnnot)
-	return &TsTypDec{N_TS_TYP_DEC, p.finRng(rng), name, ti}, nil
+	return &TsTypDec{N_TS_TYP_DEC, p.finRng(rng), name, ti, false, nil}, nil
 }
 
 func (p *Parser) tsIsFnSigValid(name string) error {
@@ -1921,6 +2125,8 @@ func (p *Parser) tsIsFnImplValid(id Node) error {
 	ecp := p.nameOfNode(p.lastTsFnSig.id)
 	act := p.nameOfNode(id)
 	if ecp == act {
+		// the overloads end with their implementation
+		p.lastTsFnSig = nil
 		return nil
 	}
 	return p.errorAtLoc(id.(*Ident).rng, fmt.Sprintf(ERR_TPL_INVALID_FN_IMPL_NAME, ecp))
@@ -1979,7 +2185,7 @@ func (p *Parser) tsItf() (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	ref := NewRef()
+	ref := p.newRef()
 	ref.Id = name
 	ref.BindKind = BK_CONST
 	ref.Typ = RDT_ITF | RDT_TYPE
@@ -1987,7 +2193,7 @@ func (p *Parser) tsItf() (Node, error) {
 		return nil, err
 	}
 
-	params, err := p.tsTryTypParams()
+	params, err := p.tsTryTypParams(true)
 	if err != nil {
 		return nil, err
 	}
@@ -2046,7 +2252,7 @@ func (p *Parser) tsEnumBody() ([]Node, error) {
 }
 
 func (p *Parser) aheadIsTsEnum(tok *Token) bool {
-	if !p.ts {
+	if !p.ts || p.flow {
 		return false
 	}
 	if tok == nil {
@@ -2067,7 +2273,7 @@ func (p *Parser) tsEnum(rng span.Range, cst bool) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	ref := NewRef()
+	ref := p.newRef()
 	ref.Id = name
 	ref.BindKind = BK_CONST
 	ref.Typ = RDT_TYPE
@@ -2145,7 +2351,7 @@ func (p *Parser) tsImportAliasRsh(rng span.Range, typ span.Range, name Node, exp
 		node = &TsImportAlias{N_TS_IMPORT_ALIAS, p.finRng(rng), name, val, export}
 	}
 
-	ref := NewRef()
+	ref := p.newRef()
 	ref.Id = name.(*Ident)
 	ref.BindKind = BK_LET
 	ref.Typ = RDT_TYPE
@@ -2157,7 +2363,7 @@ func (p *Parser) tsImportAliasRsh(rng span.Range, typ span.Range, name Node, exp
 }
 
 func (p *Parser) aheadIsTsNS(tok *Token) bool {
-	if !p.ts || tok.value != T_NAME {
+	if !p.ts || p.flow || tok.value != T_NAME {
 		return false
 	}
 	str := tok.text
@@ -2228,7 +2434,7 @@ func (p *Parser) tsNS() (Node, error) {
 	if mod != nil {
 		def = mod.name
 	}
-	ref := NewRef()
+	ref := p.newRef()
 	ref.Id = def.(*Ident)
 	ref.BindKind = BK_CONST
 	ref.Typ = RDT_NS | RDT_TYPE
@@ -2256,6 +2462,10 @@ func (p *Parser) aheadIsModDec(tok *Token) bool {
 	}
 	str := tok.text
 	ahead := p.lexer.Peek2nd()
+	if p.flow {
+		// flow only has the ambient modules `declare module "m" {}`
+		return str == "module" && !ahead.afterLineTerm && ahead.value != T_DOT && p.scope().IsKind(SPK_TS_DECLARE)
+	}
 	return (str == "module" || str == "global") && !ahead.afterLineTerm
 }
 
@@ -2274,9 +2484,9 @@ func (p *Parser) tsModDec() (*TsDec, error) {
 			return nil, p.errorAtLoc(rng, ERR_ONLY_AMBIENT_MOD_WITH_STR_NAME)
 		}
 		str = true
-		name = &StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil}
+		name = p.newStrLit(StrLit{N_LIT_STR, p.finRng(tok.rng), p.TokText(tok), tok.HasLegacyOctalEscapeSeq(), span.Range{}, nil})
 	} else if global {
-		name = &Ident{N_NAME, p.finRng(rng), "global", false, false, span.Range{}, true, p.newTypInfo(N_NAME)}
+		name = p.newIdent(Ident{N_NAME, p.finRng(rng), "global", false, false, span.Range{}, true, p.newTypInfo(N_NAME)})
 	} else {
 		name, err = p.identStrict(nil, false, false)
 		if err != nil {
@@ -2304,16 +2514,36 @@ func (p *Parser) tsModDec() (*TsDec, error) {
 func (p *Parser) tsDec() (Node, error) {
 	rng := p.lexer.Next().rng
 
-	tok := p.lexer.Peek()
-	tv := tok.value
-
 	scope := p.scope()
 	scope.AddKind(SPK_TS_DECLARE)
 
+	dec, err := p.tsDecAt(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	scope.EraseKind(SPK_TS_DECLARE)
+	return dec, nil
+}
+
+// parses the declaration after `declare`, `rng` is the start of the result node
+func (p *Parser) tsDecAt(rng span.Range) (Node, error) {
+	tok := p.lexer.Peek()
+	tv := tok.value
+
 	var err error
 	typ := N_ILLEGAL
 	dec := &TsDec{typ, span.Range{}, nil, nil}
-	if ok, kind := p.aheadIsVarDec(tok); ok {
+	if p.flow && tv == T_EXPORT {
+		dec.inner, err = p.flowDecExport()
+		typ = N_TS_DEC_EXPORT
+	} else if p.aheadIsFlowOpaque(tok) {
+		dec.inner, err = p.flowOpaqueTyp(true)
+		typ = N_TS_DEC_TYP_DEC
+	} else if p.aheadIsFlowModExps(tok) {
+		dec.inner, err = p.flowModExps()
+		typ = N_TS_DEC_MOD_EXPS
+	} else if ok, kind := p.aheadIsVarDec(tok); ok {
 		dec.inner, err = p.varDecStmt(kind, false)
 		if err != nil {
 			return nil, err
@@ -2329,8 +2559,13 @@ func (p *Parser) tsDec() (Node, error) {
 			return nil, err
 		}
 		typ = N_TS_DEC_FN
-		if err := p.advanceIfSemi(true); err != nil {
-			return nil, err
+		// the ambient functions have no implementation
+		p.lastTsFnSig = nil
+		// the optional semi of the signature may have been consumed by `fnDec`
+		if p.lexer.PrevTok() != T_SEMI {
+			if err := p.advanceIfSemi(true); err != nil {
+				return nil, err
+			}
 		}
 	} else if p.aheadIsAsync(tok, false, false) {
 		if tok.ContainsEscape() {
@@ -2374,8 +2609,6 @@ func (p *Parser) tsDec() (Node, error) {
 
 	dec.typ = typ
 	dec.rng = p.finRng(rng)
-	scope.EraseKind(SPK_TS_DECLARE)
-
 	return dec, nil
 }
 
@@ -2394,7 +2627,7 @@ func (p *Parser) checkAmbient(typ NodeType, dec Node) error {
 }
 
 func (p *Parser) tsNoNull(node Node) Node {
-	if !p.ts {
+	if !p.ts || p.flow {
 		return node
 	}
 
@@ -2417,9 +2650,14 @@ func (p *Parser) isTsLhs(node Node) bool {
 	return nt == N_TS_NO_NULL || nt == N_TS_TYP_ASSERT
 }
 
+// the binary operators whose rhs are types: `a as T` and `a satisfies T`
+func isTsTypBin(op TokenValue) bool {
+	return op == T_TS_AS || op == T_TS_SATISFIES
+}
+
 // `new` for expr: `let x: abstract new () => void = X;`
 func (p *Parser) tsAheadIsAbstract(tok *Token, prop bool, pvt bool, new bool) (bool, bool, bool) {
-	if p.ts && IsName(tok, "abstract", false) {
+	if p.ts && !p.flow && IsName(tok, "abstract", false) {
 		ahead := p.lexer.Peek2nd()
 		if ahead.afterLineTerm {
 			return false, false, false
@@ -2483,13 +2721,19 @@ func (p *Parser) tsCheckLabeledOrder(orders []ModifierNameLoc) error {
 	return nil
 }
 
-func (p *Parser) tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accessLoc, abstractLoc, declareLoc span.Range, accMod ACC_MOD, mayStaticBlock bool) error {
+func (p *Parser) tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accessLoc, abstractLoc, declareLoc, accessorLoc span.Range, accMod ACC_MOD, mayStaticBlock bool) error {
 	if !staticLoc.Empty() && !abstractLoc.Empty() {
 		return p.errorAtLoc(abstractLoc, ERR_ABSTRACT_MIXED_WITH_STATIC)
 	}
 	if !declareLoc.Empty() && !overrideLoc.Empty() {
 		return p.errorAtLoc(overrideLoc, ERR_DECLARE_MIXED_WITH_OVERRIDE)
 	}
+	if !accessorLoc.Empty() && !readonlyLoc.Empty() {
+		return p.errorAtLoc(readonlyLoc, fmt.Sprintf(ERR_TPL_ACCESSOR_MIXED_WITH, "readonly"))
+	}
+	if !accessorLoc.Empty() && !declareLoc.Empty() {
+		return p.errorAtLoc(declareLoc, fmt.Sprintf(ERR_TPL_ACCESSOR_MIXED_WITH, "declare"))
+	}
 
 	if !staticLoc.Empty() && p.lexer.Peek().value == T_BRACE_L &&
 		(!accessLoc.Empty() || !overrideLoc.Empty() || !readonlyLoc.Empty() || !declareLoc.Empty()) {
@@ -2505,6 +2749,7 @@ func (p *Parser) tsModifierOrder(staticLoc, overrideLoc, readonlyLoc, accessLoc,
 		{"static", staticLoc, nil},
 		{"override", overrideLoc, nil},
 		{"readonly", readonlyLoc, nil},
+		{"accessor", accessorLoc, nil},
 	}
 	return p.tsCheckLabeledOrder(orders)
 }
@@ -2529,6 +2774,9 @@ func (p *Parser) tsImplements() ([]Node, error) {
 			return nil, err
 		}
 		impl = append(impl, typ)
+		if p.advanceIfTok(T_COMMA) == nil {
+			break
+		}
 	}
 	if len(impl) == 0 {
 		return nil, p.errorAtLoc(implRng, ERR_IMPLEMENT_LIST_EMPTY)
@@ -380,6 +380,10 @@ type TsObj struct {
 	rng   span.Range
 	props []Node
 	opa   span.Range
+
+	// the flow exact object `{| a: T |}` and the explicit inexact object `{ a: T, ... }`
+	exact   bool
+	inexact bool
 }
 
 func (n *TsObj) Type() NodeType {
@@ -402,6 +406,14 @@ func (n *TsObj) Props() []Node {
 	return n.props
 }
 
+func (n *TsObj) Exact() bool {
+	return n.exact
+}
+
+func (n *TsObj) Inexact() bool {
+	return n.inexact
+}
+
 // #[visitor(Key,Val)]
 type TsProp struct {
 	typ      NodeType
@@ -412,6 +424,7 @@ type TsProp struct {
 	kind     PropKind
 	compute  span.Range
 	readonly bool
+	variance span.Range // the flow variance sigil `+` or `-`
 }
 
 func (n *TsProp) Type() NodeType {
@@ -449,6 +462,10 @@ func (n *TsProp) Computed() bool {
 	return !n.compute.Empty()
 }
 
+func (n *TsProp) Variance() span.Range {
+	return n.variance
+}
+
 func (n *TsProp) Method() *TsCallSig {
 	if n.val == nil {
 		return nil
@@ -528,11 +545,12 @@ func (n *TsNewSig) RetTyp() Node {
 
 // #[visitor(Key,KeyType,Val)]
 type TsIdxSig struct {
-	typ  NodeType
-	rng  span.Range
-	key  Node
-	val  Node
-	ques span.Range
+	typ      NodeType
+	rng      span.Range
+	key      Node
+	val      Node
+	ques     span.Range
+	variance span.Range
 }
 
 func (n *TsIdxSig) Type() NodeType {
@@ -547,8 +565,25 @@ func (n *TsIdxSig) Key() Node {
 	return n.key
 }
 
+// the key of the flow indexer can be a type without name like `[string]: number`,
+// the key itself is returned in that case
 func (n *TsIdxSig) KeyType() Node {
-	return n.key.(NodeWithTypInfo).TypInfo().typAnnot
+	if wt, ok := n.key.(NodeWithTypInfo); ok {
+		return wt.TypInfo().typAnnot
+	}
+	return n.key
+}
+
+// the name of the key, it's nil if the key of the flow indexer has no name
+func (n *TsIdxSig) KeyName() Node {
+	if _, ok := n.key.(NodeWithTypInfo); ok {
+		return n.key
+	}
+	return nil
+}
+
+func (n *TsIdxSig) Variance() span.Range {
+	return n.variance
 }
 
 func (n *TsIdxSig) Optional() bool {
@@ -620,6 +655,11 @@ type TsParam struct {
 	name Node
 	cons Node // the constraint
 	val  Node // the default
+	in   bool // the variance annotations `in` and `out`
+	out  bool
+	cst  bool // the `const` modifier
+
+	variance span.Range // the flow variance sigil `+` or `-`
 }
 
 func (n *TsParam) Type() NodeType {
@@ -642,6 +682,26 @@ func (n *TsParam) Default() Node {
 	return n.val
 }
 
+func (n *TsParam) In() bool {
+	return n.in
+}
+
+func (n *TsParam) Out() bool {
+	return n.out
+}
+
+func (n *TsParam) Const() bool {
+	return n.cst
+}
+
+func (n *TsParam) Variance() span.Range {
+	return n.variance
+}
+
+func (n *TsParam) hasMods() bool {
+	return n.in || n.out || n.cst || !n.variance.Empty()
+}
+
 // #[visitor(TypParams,Params,RetTyp)]
 type TsFnTyp struct {
 	typ       NodeType
@@ -771,12 +831,22 @@ func (n *TsTypAssert) Expr() Node {
 	return n.arg
 }
 
-// #[visitor(Id,TypParams)]
+// the flow type cast like `(a: T)`, its `Typ` is the type annotation
+func (n *TsTypAssert) Cast() bool {
+	return n.des != nil && n.des.Type() == N_TS_TYP_ANNOT
+}
+
+// #[visitor(Id,TypParams,Super)]
 type TsTypDec struct {
 	typ  NodeType
 	rng  span.Range
 	name Node
 	ti   *TypInfo
+
+	// the flow opaque type `opaque type A: Super = T`, the underlying type is absent in the
+	// ambient one `declare opaque type A: Super`
+	opaque bool
+	super  Node
 }
 
 func (n *TsTypDec) Type() NodeType {
@@ -799,6 +869,16 @@ func (n *TsTypDec) TypInfo() *TypInfo {
 	return n.ti
 }
 
+func (n *TsTypDec) Opaque() bool {
+	return n.opaque
+}
+
+func (n *TsTypDec) Super() Node {
+	return n.super
+}
+
+// the name is nil for the inline interface type of flow like `let a: interface { m(): void }`
+//
 // #[visitor(Id,TypParams,Supers,Body)]
 type TsInterface struct {
 	typ    NodeType
@@ -833,7 +913,7 @@ func (n *TsInterface) Body() Node {
 	return n.body
 }
 
-// #[visitor(Body)]
+// #[visitor(PUSH_SCOPE,Body)]
 type TsInterfaceBody struct {
 	typ  NodeType
 	rng  span.Range
@@ -56,8 +56,8 @@ func (ti *TypInfo) SetQues(rng span.Range) {
 	ti.ques = rng
 }
 
-func (ti *TypInfo) Not(rng span.Range) {
-	ti.ques = rng
+func (ti *TypInfo) Not() span.Range {
+	return ti.not
 }
 
 func (ti *TypInfo) SetNot(rng span.Range) {
@@ -112,6 +112,8 @@ type ClsTypInfo struct {
 	readonly     bool
 	override     bool
 	declare      bool
+	accessor     bool
+	variance     span.Range
 }
 
 func (ti *TypInfo) intiClsTyp() {
@@ -205,6 +207,19 @@ func (ti *TypInfo) SetDeclare(flag bool) {
 	ti.clsTyp.declare = flag
 }
 
+// the `accessor` keyword of the auto-accessor fields like `accessor x = 1`
+func (ti *TypInfo) Accessor() bool {
+	if util.IsNilPtr(ti.clsTyp) {
+		return false
+	}
+	return ti.clsTyp.accessor
+}
+
+func (ti *TypInfo) SetAccessor(flag bool) {
+	ti.intiClsTyp()
+	ti.clsTyp.accessor = flag
+}
+
 func (ti *TypInfo) Implements() []Node {
 	if util.IsNilPtr(ti.clsTyp) {
 		return nil
@@ -216,3 +231,16 @@ func (ti *TypInfo) SetImplements(nodes []Node) {
 	ti.intiClsTyp()
 	ti.clsTyp.implements = nodes
 }
+
+// the flow variance sigil of the class fields like `+x: T`
+func (ti *TypInfo) Variance() span.Range {
+	if util.IsNilPtr(ti.clsTyp) {
+		return span.Range{}
+	}
+	return ti.clsTyp.variance
+}
+
+func (ti *TypInfo) SetVariance(rng span.Range) {
+	ti.intiClsTyp()
+	ti.clsTyp.variance = rng
+}
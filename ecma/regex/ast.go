@@ -0,0 +1,372 @@
+package regex
+
+import (
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type NodeType uint8
+
+const (
+	N_ILLEGAL NodeType = iota
+	N_PATTERN
+	N_ALT
+	N_GROUP
+	N_CAP_GROUP
+	N_ASSERT
+	N_QUANTIFIER
+	N_CHAR
+	N_CHAR_SET
+	N_CLASS
+	N_CLASS_RANGE
+	N_CLASS_STR_DISJ
+	N_CLASS_STR
+	N_CLASS_INTERSECT
+	N_CLASS_SUBTRACT
+	N_BACKREF
+)
+
+var nodeTypeNames = [...]string{
+	N_ILLEGAL:         "Illegal",
+	N_PATTERN:         "Pattern",
+	N_ALT:             "Alternative",
+	N_GROUP:           "Group",
+	N_CAP_GROUP:       "CapturingGroup",
+	N_ASSERT:          "Assertion",
+	N_QUANTIFIER:      "Quantifier",
+	N_CHAR:            "Character",
+	N_CHAR_SET:        "CharacterSet",
+	N_CLASS:           "CharacterClass",
+	N_CLASS_RANGE:     "CharacterClassRange",
+	N_CLASS_STR_DISJ:  "ClassStringDisjunction",
+	N_CLASS_STR:       "ClassString",
+	N_CLASS_INTERSECT: "ClassIntersection",
+	N_CLASS_SUBTRACT:  "ClassSubtraction",
+	N_BACKREF:         "Backreference",
+}
+
+func (t NodeType) String() string {
+	if int(t) < len(nodeTypeNames) {
+		return nodeTypeNames[t]
+	}
+	return nodeTypeNames[N_ILLEGAL]
+}
+
+// the ranges of the nodes are the byte offsets relative to the start of the pattern
+type Node interface {
+	Type() NodeType
+	Range() span.Range
+}
+
+// the root of the regex AST, `Groups` are the capturing groups in the order of their
+// left parentheses, so the group referenced by `\n` is `Groups[n-1]`
+type Pattern struct {
+	Rng    span.Range
+	Src    string
+	Flags  Flags
+	Alts   []*Alt
+	Groups []*CapGroup
+}
+
+func (n *Pattern) Type() NodeType {
+	return N_PATTERN
+}
+
+func (n *Pattern) Range() span.Range {
+	return n.Rng
+}
+
+// the source text of the node, it's useful to tell how the node is written, for example the
+// character `a` can be written as `a`, `\x61` or `\u0061`
+func (n *Pattern) Raw(node Node) string {
+	rng := node.Range()
+	return n.Src[rng.Lo:rng.Hi]
+}
+
+type Alt struct {
+	Rng   span.Range
+	Terms []Node
+}
+
+func (n *Alt) Type() NodeType {
+	return N_ALT
+}
+
+func (n *Alt) Range() span.Range {
+	return n.Rng
+}
+
+// the non-capturing group `(?:...)`
+type Group struct {
+	Rng  span.Range
+	Alts []*Alt
+}
+
+func (n *Group) Type() NodeType {
+	return N_GROUP
+}
+
+func (n *Group) Range() span.Range {
+	return n.Rng
+}
+
+// the capturing group `(...)` or the named one `(?<name>...)`, `Index` starts from `1`
+type CapGroup struct {
+	Rng   span.Range
+	Name  string
+	Index int
+	Alts  []*Alt
+}
+
+func (n *CapGroup) Type() NodeType {
+	return N_CAP_GROUP
+}
+
+func (n *CapGroup) Range() span.Range {
+	return n.Rng
+}
+
+type AssertKind uint8
+
+const (
+	ASSERT_START         AssertKind = iota // `^`
+	ASSERT_END                             // `$`
+	ASSERT_WORD_BOUNDARY                   // `\b`, or `\B` if negated
+	ASSERT_LOOKAHEAD                       // `(?=...)`, or `(?!...)` if negated
+	ASSERT_LOOKBEHIND                      // `(?<=...)`, or `(?<!...)` if negated
+)
+
+// `Alts` is only available for the lookaround assertions
+type Assert struct {
+	Rng    span.Range
+	Kind   AssertKind
+	Negate bool
+	Alts   []*Alt
+}
+
+func (n *Assert) Type() NodeType {
+	return N_ASSERT
+}
+
+func (n *Assert) Range() span.Range {
+	return n.Rng
+}
+
+// `Max` is `-1` if there is no upper bound like `a*` and `a{1,}`
+type Quantifier struct {
+	Rng    span.Range
+	Min    int
+	Max    int
+	Greedy bool
+	Elem   Node
+}
+
+func (n *Quantifier) Type() NodeType {
+	return N_QUANTIFIER
+}
+
+func (n *Quantifier) Range() span.Range {
+	return n.Rng
+}
+
+// the single character, `Value` is the code point it represents no matter it's written
+// literally or in the escape form
+type Char struct {
+	Rng   span.Range
+	Value rune
+}
+
+func (n *Char) Type() NodeType {
+	return N_CHAR
+}
+
+func (n *Char) Range() span.Range {
+	return n.Rng
+}
+
+type CharSetKind uint8
+
+const (
+	CHAR_SET_ANY      CharSetKind = iota // `.`
+	CHAR_SET_DIGIT                       // `\d` or `\D`
+	CHAR_SET_SPACE                       // `\s` or `\S`
+	CHAR_SET_WORD                        // `\w` or `\W`
+	CHAR_SET_PROPERTY                    // `\p{...}` or `\P{...}`
+)
+
+// `Key` and `Value` are available for the unicode property escapes, for `\p{Lu}` the key
+// is `General_Category` and the value is `Lu`, for the binary properties like `\p{ASCII}`
+// the value is empty, `Strings` reports the property of strings like `\p{RGI_Emoji}`
+type CharSet struct {
+	Rng     span.Range
+	Kind    CharSetKind
+	Negate  bool
+	Key     string
+	Value   string
+	Strings bool
+}
+
+func (n *CharSet) Type() NodeType {
+	return N_CHAR_SET
+}
+
+func (n *CharSet) Range() span.Range {
+	return n.Rng
+}
+
+// the character class `[...]`, in the `v` mode its element is a single `ClassIntersect` or
+// `ClassSubtract` if the class is written in the set notation, `Strings` reports whether
+// the class may contain strings which is only possible in the `v` mode
+type Class struct {
+	Rng     span.Range
+	Negate  bool
+	Elems   []Node
+	Strings bool
+}
+
+func (n *Class) Type() NodeType {
+	return N_CLASS
+}
+
+func (n *Class) Range() span.Range {
+	return n.Rng
+}
+
+type ClassRange struct {
+	Rng span.Range
+	Min *Char
+	Max *Char
+}
+
+func (n *ClassRange) Type() NodeType {
+	return N_CLASS_RANGE
+}
+
+func (n *ClassRange) Range() span.Range {
+	return n.Rng
+}
+
+// the `\q{abc|def}` in the `v` mode
+type ClassStrDisj struct {
+	Rng  span.Range
+	Alts []*ClassStr
+}
+
+func (n *ClassStrDisj) Type() NodeType {
+	return N_CLASS_STR_DISJ
+}
+
+func (n *ClassStrDisj) Range() span.Range {
+	return n.Rng
+}
+
+type ClassStr struct {
+	Rng   span.Range
+	Chars []*Char
+}
+
+func (n *ClassStr) Type() NodeType {
+	return N_CLASS_STR
+}
+
+func (n *ClassStr) Range() span.Range {
+	return n.Rng
+}
+
+// the `A&&B` in the `v` mode, the `A&&B&&C` is represented as `(A&&B)&&C`
+type ClassIntersect struct {
+	Rng span.Range
+	Lhs Node
+	Rhs Node
+}
+
+func (n *ClassIntersect) Type() NodeType {
+	return N_CLASS_INTERSECT
+}
+
+func (n *ClassIntersect) Range() span.Range {
+	return n.Rng
+}
+
+// the `A--B` in the `v` mode, the `A--B--C` is represented as `(A--B)--C`
+type ClassSubtract struct {
+	Rng span.Range
+	Lhs Node
+	Rhs Node
+}
+
+func (n *ClassSubtract) Type() NodeType {
+	return N_CLASS_SUBTRACT
+}
+
+func (n *ClassSubtract) Range() span.Range {
+	return n.Rng
+}
+
+// the `\1` or `\k<name>`, `Index` is `0` for the named one, `Groups` are the referenced
+// groups, there may be more than one group for the name since the duplicated names are
+// permitted in the different alternatives
+type Backref struct {
+	Rng    span.Range
+	Index  int
+	Name   string
+	Groups []*CapGroup
+}
+
+func (n *Backref) Type() NodeType {
+	return N_BACKREF
+}
+
+func (n *Backref) Range() span.Range {
+	return n.Rng
+}
+
+// visits the node and its descendants in the depth-first order, the children of the node
+// are skipped if `fn` returns false
+func Walk(node Node, fn func(node Node) bool) {
+	if node == nil || !fn(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *Pattern:
+		walkAlts(n.Alts, fn)
+	case *Alt:
+		for _, term := range n.Terms {
+			Walk(term, fn)
+		}
+	case *Group:
+		walkAlts(n.Alts, fn)
+	case *CapGroup:
+		walkAlts(n.Alts, fn)
+	case *Assert:
+		walkAlts(n.Alts, fn)
+	case *Quantifier:
+		Walk(n.Elem, fn)
+	case *Class:
+		for _, elem := range n.Elems {
+			Walk(elem, fn)
+		}
+	case *ClassRange:
+		Walk(n.Min, fn)
+		Walk(n.Max, fn)
+	case *ClassStrDisj:
+		for _, alt := range n.Alts {
+			Walk(alt, fn)
+		}
+	case *ClassStr:
+		for _, c := range n.Chars {
+			Walk(c, fn)
+		}
+	case *ClassIntersect:
+		Walk(n.Lhs, fn)
+		Walk(n.Rhs, fn)
+	case *ClassSubtract:
+		Walk(n.Lhs, fn)
+		Walk(n.Rhs, fn)
+	}
+}
+
+func walkAlts(alts []*Alt, fn func(node Node) bool) {
+	for _, alt := range alts {
+		Walk(alt, fn)
+	}
+}
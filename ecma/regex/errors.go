@@ -0,0 +1,46 @@
+package regex
+
+import (
+	"fmt"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+const (
+	ERR_INVALID_FLAG              = "Invalid regular expression flag"
+	ERR_DUP_FLAG                  = "Duplicate regular expression flag"
+	ERR_FLAG_U_AND_V              = "Regular expression flags `u` and `v` cannot be used together"
+	ERR_UNTERMINATED_GROUP        = "Unterminated group"
+	ERR_UNMATCHED_PAREN           = "Unmatched ')'"
+	ERR_LONE_QUANTIFIER_BRACKET   = "Lone quantifier brackets"
+	ERR_NOTHING_TO_REPEAT         = "Nothing to repeat"
+	ERR_INCOMPLETE_QUANTIFIER     = "Incomplete quantifier"
+	ERR_QUANTIFIER_OUT_OF_ORDER   = "Numbers out of order in {} quantifier"
+	ERR_INVALID_GROUP             = "Invalid group"
+	ERR_INVALID_GROUP_NAME        = "Invalid capture group name"
+	ERR_DUP_GROUP_NAME            = "Duplicate capture group name"
+	ERR_INVALID_NAMED_REF         = "Invalid named reference"
+	ERR_INVALID_NAMED_CAPTURE_REF = "Invalid named capture referenced"
+	ERR_INVALID_ESCAPE            = "Invalid escape"
+	ERR_END_BACKSLASH             = "\\ at end of pattern"
+	ERR_INVALID_UNICODE_ESCAPE    = "Invalid Unicode escape"
+	ERR_INVALID_DECIMAL_ESCAPE    = "Invalid decimal escape"
+	ERR_INVALID_CLASS_ESCAPE      = "Invalid class escape"
+	ERR_INVALID_PROPERTY_NAME     = "Invalid property name"
+	ERR_UNTERMINATED_CLASS        = "Unterminated character class"
+	ERR_INVALID_CLASS             = "Invalid character class"
+	ERR_CLASS_RANGE_OUT_OF_ORDER  = "Range out of order in character class"
+	ERR_INVALID_CLASS_CHAR        = "Invalid character in character class"
+	ERR_INVALID_SET_OPERATION     = "Invalid set operation in character class"
+	ERR_NEGATED_CLASS_STRINGS     = "Negated character class may contain strings"
+)
+
+// the ranges are relative to the start of the pattern or the flags
+type Error struct {
+	Msg string
+	Rng span.Range
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at %d", e.Msg, e.Rng.Lo)
+}
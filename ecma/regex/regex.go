@@ -0,0 +1,1137 @@
+// the parser of the patterns of the regular expressions, the pattern is parsed into the regex
+// AST and the early errors of the spec are reported, for example:
+//
+//	flags, _ := regex.ParseFlags("u")
+//	pat, err := regex.Parse(`(?<year>\d{4})-\k<year>`, flags)
+//
+// the grammar of Annex B is applied if neither the `u` nor the `v` flag is present, so the
+// legacy patterns like `/]/`, `/\8/` and `/a{/` are accepted as they are in the browsers:
+// https://tc39.es/ecma262/multipage/text-processing.html#sec-patterns
+// https://tc39.es/ecma262/multipage/additional-ecmascript-features-for-web-browsers.html#sec-regular-expressions-patterns
+//
+// the code points are decoded from the pattern in UTF-8 so the character outside the BMP is
+// a single `Char` even if the `u` flag is absent
+package regex
+
+import (
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+type Flags struct {
+	HasIndices  bool // `d`
+	Global      bool // `g`
+	IgnoreCase  bool // `i`
+	Multiline   bool // `m`
+	DotAll      bool // `s`
+	Unicode     bool // `u`
+	UnicodeSets bool // `v`
+	Sticky      bool // `y`
+}
+
+// the error of the flags is reported if the flag is unknown or duplicated, or both `u` and `v`
+// are present
+func ParseFlags(flags string) (Flags, error) {
+	var f Flags
+	for i, c := range flags {
+		var on *bool
+		switch c {
+		case 'd':
+			on = &f.HasIndices
+		case 'g':
+			on = &f.Global
+		case 'i':
+			on = &f.IgnoreCase
+		case 'm':
+			on = &f.Multiline
+		case 's':
+			on = &f.DotAll
+		case 'u':
+			on = &f.Unicode
+		case 'v':
+			on = &f.UnicodeSets
+		case 'y':
+			on = &f.Sticky
+		}
+		rng := span.Range{Lo: uint32(i), Hi: uint32(i + utf8.RuneLen(c))}
+		if on == nil {
+			return f, &Error{ERR_INVALID_FLAG, rng}
+		}
+		if *on {
+			return f, &Error{ERR_DUP_FLAG, rng}
+		}
+		*on = true
+		if f.Unicode && f.UnicodeSets {
+			return f, &Error{ERR_FLAG_U_AND_V, rng}
+		}
+	}
+	return f, nil
+}
+
+// the flags in the canonical order which is the same as `RegExp.prototype.flags`
+func (f Flags) String() string {
+	var b strings.Builder
+	for _, flag := range []struct {
+		on bool
+		c  byte
+	}{{f.HasIndices, 'd'}, {f.Global, 'g'}, {f.IgnoreCase, 'i'}, {f.Multiline, 'm'},
+		{f.DotAll, 's'}, {f.Unicode, 'u'}, {f.UnicodeSets, 'v'}, {f.Sticky, 'y'}} {
+		if flag.on {
+			b.WriteByte(flag.c)
+		}
+	}
+	return b.String()
+}
+
+func Parse(pattern string, flags Flags) (*Pattern, error) {
+	p := newParser(pattern, flags)
+	pat, err := p.pattern()
+	if err != nil {
+		return nil, err
+	}
+	return pat, nil
+}
+
+const eof rune = -1
+
+type parser struct {
+	src   string
+	pos   int
+	flags Flags
+
+	// the unicode mode is enabled by either the `u` or the `v` flag
+	u bool
+	v bool
+	// the group names are recognized if the unicode mode is enabled or there is any named
+	// group in the pattern, otherwise `\k` is an identity escape
+	n bool
+
+	// the count of the capturing groups in the entire pattern, it's needed to tell whether the
+	// `\n` is a backreference or a legacy octal escape before the groups after it are parsed
+	numCaps int
+
+	groups   []*CapGroup
+	named    map[string][]*CapGroup
+	branches map[*CapGroup]*branch
+	branch   *branch
+	backrefs []*Backref
+}
+
+func newParser(src string, flags Flags) *parser {
+	p := &parser{
+		src:      src,
+		flags:    flags,
+		u:        flags.Unicode || flags.UnicodeSets,
+		v:        flags.UnicodeSets,
+		groups:   make([]*CapGroup, 0),
+		named:    map[string][]*CapGroup{},
+		branches: map[*CapGroup]*branch{},
+		backrefs: make([]*Backref, 0),
+	}
+	var named bool
+	p.numCaps, named = countCaps(src, p.v)
+	p.n = p.u || named
+	return p
+}
+
+// the alternatives the named group belongs to, the groups with the same name are permitted
+// if they are in the different alternatives of the same disjunction like `(?<a>x)|(?<a>y)`
+type branch struct {
+	parent *branch
+	base   *branch
+}
+
+func newBranch(parent *branch) *branch {
+	b := &branch{parent: parent}
+	b.base = b
+	return b
+}
+
+func (b *branch) sibling() *branch {
+	return &branch{parent: b.parent, base: b.base}
+}
+
+func (b *branch) separatedFrom(other *branch) bool {
+	for x := b; x != nil; x = x.parent {
+		for y := other; y != nil; y = y.parent {
+			if x != y && x.base == y.base {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countCaps(src string, v bool) (int, bool) {
+	n := 0
+	named := false
+	depth := 0
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\\':
+			i++
+		case '[':
+			// the classes can be nested only in the `v` mode
+			if depth == 0 || v {
+				depth++
+			}
+		case ']':
+			if depth > 0 {
+				depth--
+			}
+		case '(':
+			if depth > 0 {
+				continue
+			}
+			if i+1 < len(src) && src[i+1] == '?' {
+				if i+3 < len(src) && src[i+2] == '<' && src[i+3] != '=' && src[i+3] != '!' {
+					n++
+					named = true
+				}
+			} else {
+				n++
+			}
+		}
+	}
+	return n, named
+}
+
+func (p *parser) peek() rune {
+	if p.pos >= len(p.src) {
+		return eof
+	}
+	c, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return c
+}
+
+// the ascii character at the offset relative to the current position
+func (p *parser) peekAt(i int) rune {
+	if p.pos+i >= len(p.src) {
+		return eof
+	}
+	return rune(p.src[p.pos+i])
+}
+
+func (p *parser) next() rune {
+	if p.pos >= len(p.src) {
+		return eof
+	}
+	c, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	return c
+}
+
+func (p *parser) eat(c rune) bool {
+	if p.peek() == c {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) aheadIs(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *parser) rng(lo int) span.Range {
+	return span.Range{Lo: uint32(lo), Hi: uint32(p.pos)}
+}
+
+func (p *parser) errAt(lo int, msg string) *Error {
+	hi := p.pos
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return &Error{msg, span.Range{Lo: uint32(lo), Hi: uint32(hi)}}
+}
+
+func (p *parser) pattern() (*Pattern, error) {
+	alts, err := p.disjunction()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() == ')' {
+		return nil, p.errAt(p.pos, ERR_UNMATCHED_PAREN)
+	}
+
+	for _, ref := range p.backrefs {
+		if ref.Name != "" {
+			groups := p.named[ref.Name]
+			if len(groups) == 0 {
+				return nil, &Error{ERR_INVALID_NAMED_CAPTURE_REF, ref.Rng}
+			}
+			ref.Groups = groups
+			continue
+		}
+		// only reachable in the unicode mode since the out-of-range `\n` is treated as the
+		// legacy octal escape in Annex B
+		if ref.Index > len(p.groups) {
+			return nil, &Error{ERR_INVALID_ESCAPE, ref.Rng}
+		}
+		ref.Groups = []*CapGroup{p.groups[ref.Index-1]}
+	}
+
+	return &Pattern{p.rng(0), p.src, p.flags, alts, p.groups}, nil
+}
+
+func (p *parser) disjunction() ([]*Alt, *Error) {
+	parent := p.branch
+	p.branch = newBranch(parent)
+	defer func() { p.branch = parent }()
+
+	alts := make([]*Alt, 0, 1)
+	for {
+		alt, err := p.alternative()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, alt)
+		if !p.eat('|') {
+			break
+		}
+		p.branch = p.branch.sibling()
+	}
+	return alts, nil
+}
+
+func (p *parser) alternative() (*Alt, *Error) {
+	start := p.pos
+	terms := make([]Node, 0)
+	for {
+		c := p.peek()
+		if c == eof || c == '|' || c == ')' {
+			break
+		}
+		term, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	return &Alt{p.rng(start), terms}, nil
+}
+
+func (p *parser) term() (Node, *Error) {
+	start := p.pos
+	node, quantifiable, err := p.assertion()
+	if err != nil {
+		return nil, err
+	}
+	if node != nil {
+		if quantifiable {
+			return p.quantifier(node, start)
+		}
+		return node, nil
+	}
+
+	atom, err := p.atom()
+	if err != nil {
+		return nil, err
+	}
+	return p.quantifier(atom, start)
+}
+
+// the lookahead assertions are quantifiable in Annex B
+func (p *parser) assertion() (Node, bool, *Error) {
+	start := p.pos
+	switch p.peek() {
+	case '^':
+		p.next()
+		return &Assert{p.rng(start), ASSERT_START, false, nil}, false, nil
+	case '$':
+		p.next()
+		return &Assert{p.rng(start), ASSERT_END, false, nil}, false, nil
+	case '\\':
+		if c := p.peekAt(1); c == 'b' || c == 'B' {
+			p.pos += 2
+			return &Assert{p.rng(start), ASSERT_WORD_BOUNDARY, c == 'B', nil}, false, nil
+		}
+	case '(':
+		kind := ASSERT_LOOKAHEAD
+		var negate bool
+		if p.aheadIs("(?=") || p.aheadIs("(?!") {
+			negate = p.peekAt(2) == '!'
+			p.pos += 3
+		} else if p.aheadIs("(?<=") || p.aheadIs("(?<!") {
+			kind = ASSERT_LOOKBEHIND
+			negate = p.peekAt(3) == '!'
+			p.pos += 4
+		} else {
+			return nil, false, nil
+		}
+
+		alts, err := p.disjunction()
+		if err != nil {
+			return nil, false, err
+		}
+		if !p.eat(')') {
+			return nil, false, p.errAt(start, ERR_UNTERMINATED_GROUP)
+		}
+		return &Assert{p.rng(start), kind, negate, alts}, kind == ASSERT_LOOKAHEAD && !p.u, nil
+	}
+	return nil, false, nil
+}
+
+func (p *parser) quantifier(elem Node, start int) (Node, *Error) {
+	min, max, ok, err := p.quantifierPrefix()
+	if err != nil || !ok {
+		return elem, err
+	}
+	greedy := !p.eat('?')
+	return &Quantifier{p.rng(start), min, max, greedy, elem}, nil
+}
+
+func (p *parser) quantifierPrefix() (int, int, bool, *Error) {
+	switch p.peek() {
+	case '*':
+		p.next()
+		return 0, -1, true, nil
+	case '+':
+		p.next()
+		return 1, -1, true, nil
+	case '?':
+		p.next()
+		return 0, 1, true, nil
+	case '{':
+		start := p.pos
+		min, max, ok := p.bracedQuantifier()
+		if !ok {
+			if p.u {
+				return 0, 0, false, p.errAt(start, ERR_INCOMPLETE_QUANTIFIER)
+			}
+			return 0, 0, false, nil
+		}
+		if max != -1 && min > max {
+			return 0, 0, false, p.errAt(start, ERR_QUANTIFIER_OUT_OF_ORDER)
+		}
+		return min, max, true, nil
+	}
+	return 0, 0, false, nil
+}
+
+// the position is restored if the braced quantifier is not matched
+func (p *parser) bracedQuantifier() (int, int, bool) {
+	start := p.pos
+	p.next()
+	min, ok := p.decimal()
+	if ok {
+		max := min
+		if p.eat(',') {
+			if max, ok = p.decimal(); !ok {
+				max = -1
+			}
+		}
+		if p.eat('}') {
+			return min, max, true
+		}
+	}
+	p.pos = start
+	return 0, 0, false
+}
+
+// the value is saturated to avoid the overflow of the huge numbers like `a{99999999999}`
+func (p *parser) decimal() (int, bool) {
+	start := p.pos
+	n := 0
+	for c := p.peek(); c >= '0' && c <= '9'; c = p.peek() {
+		p.next()
+		if n < math.MaxInt32 {
+			n = n*10 + int(c-'0')
+		}
+	}
+	if n > math.MaxInt32 {
+		n = math.MaxInt32
+	}
+	return n, p.pos > start
+}
+
+func (p *parser) atom() (Node, *Error) {
+	start := p.pos
+	c := p.peek()
+	switch c {
+	case '.':
+		p.next()
+		return &CharSet{Rng: p.rng(start), Kind: CHAR_SET_ANY}, nil
+	case '\\':
+		return p.atomEscape()
+	case '[':
+		return p.class()
+	case '(':
+		return p.group()
+	case '*', '+', '?':
+		return nil, p.errAt(start, ERR_NOTHING_TO_REPEAT)
+	case '{':
+		if _, _, ok := p.bracedQuantifier(); ok {
+			return nil, p.errAt(start, ERR_NOTHING_TO_REPEAT)
+		}
+		if p.u {
+			return nil, p.errAt(start, ERR_LONE_QUANTIFIER_BRACKET)
+		}
+	case '}', ']':
+		if p.u {
+			return nil, p.errAt(start, ERR_LONE_QUANTIFIER_BRACKET)
+		}
+	}
+	p.next()
+	return &Char{p.rng(start), c}, nil
+}
+
+func (p *parser) atomEscape() (Node, *Error) {
+	start := p.pos
+	p.next()
+
+	c := p.peek()
+	if c == eof {
+		return nil, p.errAt(start, ERR_END_BACKSLASH)
+	}
+
+	if c >= '1' && c <= '9' {
+		n, _ := p.decimal()
+		if p.u || n <= p.numCaps {
+			ref := &Backref{Rng: p.rng(start), Index: n}
+			p.backrefs = append(p.backrefs, ref)
+			return ref, nil
+		}
+		// the out-of-range backreference is the legacy octal escape or the identity escape
+		// in Annex B
+		p.pos = start + 1
+		return p.charEscape(start, false)
+	}
+
+	if c == 'k' && p.n {
+		p.next()
+		name, err := p.groupName()
+		if err != nil {
+			return nil, p.errAt(start, ERR_INVALID_NAMED_REF)
+		}
+		ref := &Backref{Rng: p.rng(start), Name: name}
+		p.backrefs = append(p.backrefs, ref)
+		return ref, nil
+	}
+
+	set, err := p.charSetEscape(start)
+	if err != nil {
+		return nil, err
+	}
+	if set != nil {
+		return set, nil
+	}
+	return p.charEscape(start, false)
+}
+
+// the `\d`, `\s`, `\w` and their negations, also the `\p{...}` and `\P{...}` in the unicode
+// mode, `nil` is returned if none of them is matched
+func (p *parser) charSetEscape(start int) (*CharSet, *Error) {
+	c := p.peek()
+	var kind CharSetKind
+	switch c {
+	case 'd', 'D':
+		kind = CHAR_SET_DIGIT
+	case 's', 'S':
+		kind = CHAR_SET_SPACE
+	case 'w', 'W':
+		kind = CHAR_SET_WORD
+	case 'p', 'P':
+		if !p.u {
+			return nil, nil
+		}
+		kind = CHAR_SET_PROPERTY
+	default:
+		return nil, nil
+	}
+	p.next()
+	negate := c >= 'A' && c <= 'Z'
+	if kind != CHAR_SET_PROPERTY {
+		return &CharSet{Rng: p.rng(start), Kind: kind, Negate: negate}, nil
+	}
+
+	if !p.eat('{') {
+		return nil, p.errAt(start, ERR_INVALID_PROPERTY_NAME)
+	}
+	name := p.propertyChars()
+	var value string
+	if p.eat('=') {
+		value = p.propertyChars()
+		if value == "" {
+			return nil, p.errAt(start, ERR_INVALID_PROPERTY_NAME)
+		}
+	}
+	if !p.eat('}') {
+		return nil, p.errAt(start, ERR_INVALID_PROPERTY_NAME)
+	}
+
+	key, val, strs, ok := resolveProperty(name, value, p.v)
+	if !ok || strs && negate {
+		return nil, p.errAt(start, ERR_INVALID_PROPERTY_NAME)
+	}
+	return &CharSet{p.rng(start), kind, negate, key, val, strs}, nil
+}
+
+func (p *parser) propertyChars() string {
+	start := p.pos
+	for c := p.peek(); c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'; c = p.peek() {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+// the `CharacterEscape` in the spec, the position is right after the backslash
+func (p *parser) charEscape(start int, inClass bool) (*Char, *Error) {
+	errMsg := ERR_INVALID_ESCAPE
+	if inClass {
+		errMsg = ERR_INVALID_CLASS_ESCAPE
+	}
+
+	c := p.peek()
+	switch c {
+	case 'f':
+		p.next()
+		return &Char{p.rng(start), '\f'}, nil
+	case 'n':
+		p.next()
+		return &Char{p.rng(start), '\n'}, nil
+	case 'r':
+		p.next()
+		return &Char{p.rng(start), '\r'}, nil
+	case 't':
+		p.next()
+		return &Char{p.rng(start), '\t'}, nil
+	case 'v':
+		p.next()
+		return &Char{p.rng(start), '\v'}, nil
+	case 'c':
+		l := p.peekAt(1)
+		if l >= 'a' && l <= 'z' || l >= 'A' && l <= 'Z' ||
+			// `ClassControlLetter` of Annex B
+			inClass && !p.u && (l >= '0' && l <= '9' || l == '_') {
+			p.pos += 2
+			return &Char{p.rng(start), l % 32}, nil
+		}
+		if p.u {
+			return nil, p.errAt(start, errMsg)
+		}
+		// the backslash itself is the character in Annex B
+		return &Char{p.rng(start), '\\'}, nil
+	case 'x':
+		p.next()
+		if v, ok := p.hex(2); ok {
+			return &Char{p.rng(start), v}, nil
+		}
+		if p.u {
+			return nil, p.errAt(start, errMsg)
+		}
+		return &Char{p.rng(start), 'x'}, nil
+	case 'u':
+		v, err := p.unicodeEscape(start, p.u)
+		if err != nil {
+			return nil, err
+		}
+		return &Char{p.rng(start), v}, nil
+	case '0':
+		if d := p.peekAt(1); d < '0' || d > '9' {
+			p.next()
+			return &Char{p.rng(start), 0}, nil
+		}
+		if p.u {
+			p.next()
+			return nil, p.errAt(start, ERR_INVALID_DECIMAL_ESCAPE)
+		}
+	}
+
+	if c >= '0' && c <= '9' {
+		if p.u {
+			p.next()
+			return nil, p.errAt(start, errMsg)
+		}
+		if c <= '7' {
+			return &Char{p.rng(start), p.legacyOctal()}, nil
+		}
+	}
+
+	// the identity escape
+	if p.u {
+		if !isSyntaxChar(c) && c != '/' {
+			return nil, p.errAt(start, errMsg)
+		}
+	} else if c == 'k' && p.n {
+		return nil, p.errAt(start, ERR_INVALID_NAMED_REF)
+	}
+	p.next()
+	return &Char{p.rng(start), c}, nil
+}
+
+func (p *parser) legacyOctal() rune {
+	n := p.next() - '0'
+	max := 2
+	if n > 3 {
+		max = 1
+	}
+	for i := 0; i < max; i++ {
+		c := p.peek()
+		if c < '0' || c > '7' {
+			break
+		}
+		p.next()
+		n = n*8 + c - '0'
+	}
+	return n
+}
+
+func (p *parser) hex(n int) (rune, bool) {
+	start := p.pos
+	var v rune
+	for i := 0; i < n; i++ {
+		d := hexVal(p.peek())
+		if d < 0 {
+			p.pos = start
+			return 0, false
+		}
+		p.next()
+		v = v*16 + d
+	}
+	return v, true
+}
+
+func hexVal(c rune) rune {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10
+	}
+	return -1
+}
+
+// the `RegExpUnicodeEscapeSequence` in the spec, the position is at `u`, the surrogate pair
+// like `\uD83D\uDE00` and the form `\u{...}` are permitted in the unicode mode
+func (p *parser) unicodeEscape(start int, u bool) (rune, *Error) {
+	p.next()
+	if v, ok := p.hex(4); ok {
+		if u && v >= 0xD800 && v <= 0xDBFF && p.aheadIs(`\u`) {
+			save := p.pos
+			p.pos += 2
+			if t, ok := p.hex(4); ok && t >= 0xDC00 && t <= 0xDFFF {
+				return (v-0xD800)*0x400 + t - 0xDC00 + 0x10000, nil
+			}
+			p.pos = save
+		}
+		return v, nil
+	}
+
+	if u && p.eat('{') {
+		var v rune
+		digits := 0
+		for d := hexVal(p.peek()); d >= 0; d = hexVal(p.peek()) {
+			p.next()
+			digits++
+			if v <= unicode.MaxRune {
+				v = v*16 + d
+			}
+		}
+		if digits > 0 && v <= unicode.MaxRune && p.eat('}') {
+			return v, nil
+		}
+	}
+
+	if u {
+		return 0, p.errAt(start, ERR_INVALID_UNICODE_ESCAPE)
+	}
+	return 'u', nil
+}
+
+func isSyntaxChar(c rune) bool {
+	return strings.ContainsRune(`^$\.*+?()[]{}|`, c)
+}
+
+func (p *parser) group() (Node, *Error) {
+	start := p.pos
+	p.next()
+
+	var name string
+	if p.eat('?') {
+		if p.eat(':') {
+			alts, err := p.disjunction()
+			if err != nil {
+				return nil, err
+			}
+			if !p.eat(')') {
+				return nil, p.errAt(start, ERR_UNTERMINATED_GROUP)
+			}
+			return &Group{p.rng(start), alts}, nil
+		}
+		if p.peek() != '<' {
+			return nil, p.errAt(start, ERR_INVALID_GROUP)
+		}
+		nameStart := p.pos
+		var err *Error
+		if name, err = p.groupName(); err != nil {
+			return nil, err
+		}
+		for _, g := range p.named[name] {
+			if !p.branch.separatedFrom(p.branches[g]) {
+				return nil, p.errAt(nameStart, ERR_DUP_GROUP_NAME)
+			}
+		}
+	}
+
+	g := &CapGroup{Name: name, Index: len(p.groups) + 1}
+	p.groups = append(p.groups, g)
+	if name != "" {
+		p.named[name] = append(p.named[name], g)
+		p.branches[g] = p.branch
+	}
+
+	alts, err := p.disjunction()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eat(')') {
+		return nil, p.errAt(start, ERR_UNTERMINATED_GROUP)
+	}
+	g.Rng = p.rng(start)
+	g.Alts = alts
+	return g, nil
+}
+
+// the `<name>` of the named groups and the named backreferences, the unicode escapes are
+// permitted in the name regardless of the unicode mode
+func (p *parser) groupName() (string, *Error) {
+	start := p.pos
+	if !p.eat('<') {
+		return "", p.errAt(start, ERR_INVALID_GROUP_NAME)
+	}
+
+	var b strings.Builder
+	for {
+		c := p.peek()
+		if c == '>' {
+			break
+		}
+		cs := p.pos
+		if c == '\\' {
+			p.next()
+			if p.peek() != 'u' {
+				return "", p.errAt(cs, ERR_INVALID_GROUP_NAME)
+			}
+			v, err := p.unicodeEscape(cs, true)
+			if err != nil {
+				return "", p.errAt(cs, ERR_INVALID_GROUP_NAME)
+			}
+			c = v
+		} else {
+			p.next()
+		}
+
+		if b.Len() == 0 && !isIdStart(c) || b.Len() > 0 && !isIdPart(c) {
+			return "", p.errAt(cs, ERR_INVALID_GROUP_NAME)
+		}
+		b.WriteRune(c)
+	}
+
+	if b.Len() == 0 {
+		return "", p.errAt(start, ERR_INVALID_GROUP_NAME)
+	}
+	p.next()
+	return b.String(), nil
+}
+
+func isIdStart(c rune) bool {
+	return c == '$' || c == '_' || unicode.In(c, unicode.Letter, unicode.Nl, unicode.Other_ID_Start)
+}
+
+func isIdPart(c rune) bool {
+	return isIdStart(c) || c == 0x200C || c == 0x200D ||
+		unicode.In(c, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue)
+}
+
+func (p *parser) class() (Node, *Error) {
+	start := p.pos
+	p.next()
+	negate := p.eat('^')
+
+	var elems []Node
+	var strs bool
+	var err *Error
+	if p.v {
+		elems, strs, err = p.classSetExpr()
+	} else {
+		elems, err = p.classRanges()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.eat(']') {
+		return nil, p.errAt(start, ERR_UNTERMINATED_CLASS)
+	}
+	if negate && strs {
+		return nil, p.errAt(start, ERR_NEGATED_CLASS_STRINGS)
+	}
+	return &Class{p.rng(start), negate, elems, strs}, nil
+}
+
+func (p *parser) classRanges() ([]Node, *Error) {
+	elems := make([]Node, 0)
+	for {
+		c := p.peek()
+		if c == ']' || c == eof {
+			return elems, nil
+		}
+
+		start := p.pos
+		lhs, err := p.classAtom()
+		if err != nil {
+			return nil, err
+		}
+		// the trailing `-` like `[a-]` is a character
+		if p.peek() != '-' || p.peekAt(1) == ']' || p.peekAt(1) == eof {
+			elems = append(elems, lhs)
+			continue
+		}
+
+		dashStart := p.pos
+		p.next()
+		dash := &Char{p.rng(dashStart), '-'}
+		rhs, err := p.classAtom()
+		if err != nil {
+			return nil, err
+		}
+
+		lc, lok := lhs.(*Char)
+		rc, rok := rhs.(*Char)
+		if !lok || !rok {
+			if p.u {
+				return nil, p.errAt(start, ERR_INVALID_CLASS)
+			}
+			// the range with the class escape like `[\w-a]` is the union in Annex B
+			elems = append(elems, lhs, dash, rhs)
+			continue
+		}
+		if lc.Value > rc.Value {
+			return nil, p.errAt(start, ERR_CLASS_RANGE_OUT_OF_ORDER)
+		}
+		elems = append(elems, &ClassRange{p.rng(start), lc, rc})
+	}
+}
+
+func (p *parser) classAtom() (Node, *Error) {
+	start := p.pos
+	c := p.next()
+	if c != '\\' {
+		return &Char{p.rng(start), c}, nil
+	}
+
+	switch p.peek() {
+	case eof:
+		return nil, p.errAt(start, ERR_END_BACKSLASH)
+	case 'b':
+		p.next()
+		return &Char{p.rng(start), '\b'}, nil
+	case '-':
+		if p.u {
+			p.next()
+			return &Char{p.rng(start), '-'}, nil
+		}
+	}
+
+	set, err := p.charSetEscape(start)
+	if err != nil {
+		return nil, err
+	}
+	if set != nil {
+		return set, nil
+	}
+	return p.charEscape(start, true)
+}
+
+// the `ClassSetExpression` in the `v` mode, the union is returned as the elements while the
+// intersection or the subtraction is returned as the single element, the second result
+// reports whether the expression may contain strings
+func (p *parser) classSetExpr() ([]Node, bool, *Error) {
+	if p.peek() == ']' {
+		return []Node{}, false, nil
+	}
+
+	start := p.pos
+	first, strs, err := p.classSetRangeOrOperand()
+	if err != nil {
+		return nil, false, err
+	}
+
+	if p.aheadIs("&&") || p.aheadIs("--") {
+		op := p.src[p.pos : p.pos+2]
+		if _, ok := first.(*ClassRange); ok {
+			return nil, false, p.errAt(p.pos, ERR_INVALID_SET_OPERATION)
+		}
+
+		lhs := first
+		for p.aheadIs(op) {
+			p.pos += 2
+			if op == "&&" && p.peek() == '&' {
+				return nil, false, p.errAt(p.pos, ERR_INVALID_CLASS_CHAR)
+			}
+			rhs, rstrs, err := p.classSetOperand()
+			if err != nil {
+				return nil, false, err
+			}
+			if op == "&&" {
+				lhs = &ClassIntersect{p.rng(start), lhs, rhs}
+				strs = strs && rstrs
+			} else {
+				lhs = &ClassSubtract{p.rng(start), lhs, rhs}
+			}
+		}
+		// the operators cannot be mixed without the nested class like `[a&&b--c]`
+		if p.peek() != ']' {
+			return nil, false, p.errAt(p.pos, ERR_INVALID_SET_OPERATION)
+		}
+		return []Node{lhs}, strs, nil
+	}
+
+	elems := []Node{first}
+	for {
+		c := p.peek()
+		if c == ']' || c == eof {
+			return elems, strs, nil
+		}
+		if p.aheadIs("&&") || p.aheadIs("--") {
+			return nil, false, p.errAt(p.pos, ERR_INVALID_SET_OPERATION)
+		}
+		elem, s, err := p.classSetRangeOrOperand()
+		if err != nil {
+			return nil, false, err
+		}
+		elems = append(elems, elem)
+		strs = strs || s
+	}
+}
+
+func (p *parser) classSetRangeOrOperand() (Node, bool, *Error) {
+	start := p.pos
+	node, strs, err := p.classSetOperand()
+	if err != nil {
+		return nil, false, err
+	}
+
+	lhs, ok := node.(*Char)
+	if !ok || p.peek() != '-' || p.peekAt(1) == '-' {
+		return node, strs, nil
+	}
+	p.next()
+	rhs, err := p.classSetChar()
+	if err != nil {
+		return nil, false, err
+	}
+	if lhs.Value > rhs.Value {
+		return nil, false, p.errAt(start, ERR_CLASS_RANGE_OUT_OF_ORDER)
+	}
+	return &ClassRange{p.rng(start), lhs, rhs}, false, nil
+}
+
+func (p *parser) classSetOperand() (Node, bool, *Error) {
+	start := p.pos
+	switch p.peek() {
+	case '[':
+		p.next()
+		negate := p.eat('^')
+		elems, strs, err := p.classSetExpr()
+		if err != nil {
+			return nil, false, err
+		}
+		if !p.eat(']') {
+			return nil, false, p.errAt(start, ERR_UNTERMINATED_CLASS)
+		}
+		if negate && strs {
+			return nil, false, p.errAt(start, ERR_NEGATED_CLASS_STRINGS)
+		}
+		return &Class{p.rng(start), negate, elems, strs}, strs, nil
+	case '\\':
+		if p.aheadIs(`\q{`) {
+			return p.classStrDisj()
+		}
+		p.next()
+		set, err := p.charSetEscape(start)
+		if err != nil {
+			return nil, false, err
+		}
+		if set != nil {
+			return set, set.Strings, nil
+		}
+		p.pos = start
+	}
+
+	c, err := p.classSetChar()
+	if err != nil {
+		return nil, false, err
+	}
+	return c, false, nil
+}
+
+// the `\q{abc|d}`, it may contain strings if any of its alternatives is not a single character
+func (p *parser) classStrDisj() (Node, bool, *Error) {
+	start := p.pos
+	p.pos += 3
+
+	alts := make([]*ClassStr, 0, 1)
+	strs := false
+	for {
+		strStart := p.pos
+		chars := make([]*Char, 0, 1)
+		for c := p.peek(); c != '|' && c != '}'; c = p.peek() {
+			if c == eof {
+				return nil, false, p.errAt(start, ERR_UNTERMINATED_CLASS)
+			}
+			char, err := p.classSetChar()
+			if err != nil {
+				return nil, false, err
+			}
+			chars = append(chars, char)
+		}
+		alts = append(alts, &ClassStr{p.rng(strStart), chars})
+		strs = strs || len(chars) != 1
+		if !p.eat('|') {
+			break
+		}
+	}
+	p.next()
+	return &ClassStrDisj{p.rng(start), alts}, strs, nil
+}
+
+func (p *parser) classSetChar() (*Char, *Error) {
+	start := p.pos
+	c := p.peek()
+	switch {
+	case c == eof:
+		return nil, p.errAt(start, ERR_UNTERMINATED_CLASS)
+	case c == '\\':
+		p.next()
+		e := p.peek()
+		if e == eof {
+			return nil, p.errAt(start, ERR_END_BACKSLASH)
+		}
+		if e == 'b' {
+			p.next()
+			return &Char{p.rng(start), '\b'}, nil
+		}
+		if strings.ContainsRune("&-!#%,:;<=>@`~", e) {
+			p.next()
+			return &Char{p.rng(start), e}, nil
+		}
+		return p.charEscape(start, true)
+	case strings.ContainsRune("&!#$%*+,.:;<=>?@^`~", c) && p.peekAt(1) == c:
+		// the reserved double punctuators like `&&` and `!!`
+		p.pos += 2
+		return nil, p.errAt(start, ERR_INVALID_SET_OPERATION)
+	case strings.ContainsRune("()[]{}/-|", c):
+		p.next()
+		return nil, p.errAt(start, ERR_INVALID_CLASS_CHAR)
+	}
+	p.next()
+	return &Char{p.rng(start), c}, nil
+}
@@ -0,0 +1,253 @@
+package regex
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func parse(t *testing.T, pattern, flags string) *Pattern {
+	fs, err := ParseFlags(flags)
+	AssertEqual(t, nil, err, "should be ok")
+	pat, err := Parse(pattern, fs)
+	if err != nil {
+		t.Fatalf("should pass pattern /%s/%s: %v", pattern, flags, err)
+	}
+	return pat
+}
+
+func testFail(t *testing.T, pattern, flags, errMs string) {
+	fs, err := ParseFlags(flags)
+	AssertEqual(t, nil, err, "should be ok")
+	pat, err := Parse(pattern, fs)
+	if err == nil {
+		t.Fatalf("should not pass pattern /%s/%s: %v", pattern, flags, pat)
+	}
+	AssertEqual(t, errMs, err.Error(), "should be ok")
+}
+
+func TestFlags(t *testing.T) {
+	fs, err := ParseFlags("yvgd")
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, true, fs.UnicodeSets, "should be ok")
+	AssertEqual(t, "dgvy", fs.String(), "should be ok")
+
+	_, err = ParseFlags("gig")
+	AssertEqual(t, "Duplicate regular expression flag at 2", err.Error(), "should be ok")
+
+	_, err = ParseFlags("gx")
+	AssertEqual(t, "Invalid regular expression flag at 1", err.Error(), "should be ok")
+
+	_, err = ParseFlags("uv")
+	AssertEqual(t, "Regular expression flags `u` and `v` cannot be used together at 1", err.Error(), "should be ok")
+}
+
+func TestPattern(t *testing.T) {
+	pat := parse(t, `a|(?<y>\d{4})-\k<y>+?`, "")
+	AssertEqual(t, 2, len(pat.Alts), "should be ok")
+
+	alt := pat.Alts[1]
+	AssertEqual(t, 3, len(alt.Terms), "should be ok")
+
+	g := alt.Terms[0].(*CapGroup)
+	AssertEqual(t, "y", g.Name, "should be ok")
+	AssertEqual(t, 1, g.Index, "should be ok")
+	AssertEqual(t, `(?<y>\d{4})`, pat.Raw(g), "should be ok")
+
+	q := g.Alts[0].Terms[0].(*Quantifier)
+	AssertEqual(t, 4, q.Min, "should be ok")
+	AssertEqual(t, 4, q.Max, "should be ok")
+	AssertEqual(t, CHAR_SET_DIGIT, q.Elem.(*CharSet).Kind, "should be ok")
+
+	lazy := alt.Terms[2].(*Quantifier)
+	AssertEqual(t, false, lazy.Greedy, "should be ok")
+	AssertEqual(t, -1, lazy.Max, "should be ok")
+	ref := lazy.Elem.(*Backref)
+	AssertEqual(t, g, ref.Groups[0], "should be ok")
+}
+
+func TestPatternAssert(t *testing.T) {
+	pat := parse(t, `^(?<=\$)\b(?!x)$`, "u")
+	terms := pat.Alts[0].Terms
+	AssertEqual(t, 5, len(terms), "should be ok")
+	AssertEqual(t, ASSERT_START, terms[0].(*Assert).Kind, "should be ok")
+	AssertEqual(t, ASSERT_LOOKBEHIND, terms[1].(*Assert).Kind, "should be ok")
+	AssertEqual(t, '$', terms[1].(*Assert).Alts[0].Terms[0].(*Char).Value, "should be ok")
+	AssertEqual(t, ASSERT_WORD_BOUNDARY, terms[2].(*Assert).Kind, "should be ok")
+	AssertEqual(t, true, terms[3].(*Assert).Negate, "should be ok")
+	AssertEqual(t, ASSERT_END, terms[4].(*Assert).Kind, "should be ok")
+
+	// the lookahead is quantifiable in Annex B
+	pat = parse(t, `(?=a)*`, "")
+	AssertEqual(t, N_QUANTIFIER, pat.Alts[0].Terms[0].Type(), "should be ok")
+	testFail(t, `(?=a)*`, "u", "Nothing to repeat at 5")
+	testFail(t, `(?<=a)*`, "", "Nothing to repeat at 6")
+}
+
+func TestPatternChar(t *testing.T) {
+	pat := parse(t, `\x41B\u{43}\cJ\0\/😀\uD83D\uDE00`, "u")
+	values := []rune{}
+	Walk(pat, func(node Node) bool {
+		if c, ok := node.(*Char); ok {
+			values = append(values, c.Value)
+		}
+		return true
+	})
+	AssertEqual(t, []rune{'A', 'B', 'C', '\n', 0, '/', 0x1F600, 0x1F600}, values, "should be ok")
+
+	// the legacy syntax of Annex B
+	pat = parse(t, `\8\101\c]{}\u{2}`, "")
+	terms := pat.Alts[0].Terms
+	AssertEqual(t, '8', terms[0].(*Char).Value, "should be ok")
+	AssertEqual(t, 'A', terms[1].(*Char).Value, "should be ok")
+	AssertEqual(t, '\\', terms[2].(*Char).Value, "should be ok")
+	AssertEqual(t, 'c', terms[3].(*Char).Value, "should be ok")
+	AssertEqual(t, ']', terms[4].(*Char).Value, "should be ok")
+	q := terms[len(terms)-1].(*Quantifier)
+	AssertEqual(t, 'u', q.Elem.(*Char).Value, "should be ok")
+	AssertEqual(t, 2, q.Min, "should be ok")
+
+	// `\1` is a backreference only if there are enough groups
+	pat = parse(t, `\1(a)\2`, "")
+	terms = pat.Alts[0].Terms
+	AssertEqual(t, N_BACKREF, terms[0].Type(), "should be ok")
+	AssertEqual(t, rune(2), terms[2].(*Char).Value, "should be ok")
+}
+
+func TestPatternClass(t *testing.T) {
+	pat := parse(t, `[^a-z\d\-]`, "u")
+	cls := pat.Alts[0].Terms[0].(*Class)
+	AssertEqual(t, true, cls.Negate, "should be ok")
+	AssertEqual(t, 3, len(cls.Elems), "should be ok")
+	rng := cls.Elems[0].(*ClassRange)
+	AssertEqual(t, 'a', rng.Min.Value, "should be ok")
+	AssertEqual(t, 'z', rng.Max.Value, "should be ok")
+	AssertEqual(t, '-', cls.Elems[2].(*Char).Value, "should be ok")
+
+	// the range with the class escape is the union in Annex B
+	pat = parse(t, `[\w-a-]`, "")
+	AssertEqual(t, 4, len(pat.Alts[0].Terms[0].(*Class).Elems), "should be ok")
+
+	prop := parse(t, `\p{Script=Grek}\P{Lu}`, "u").Alts[0].Terms
+	AssertEqual(t, "Script", prop[0].(*CharSet).Key, "should be ok")
+	AssertEqual(t, "Greek", prop[0].(*CharSet).Value, "should be ok")
+	AssertEqual(t, "General_Category", prop[1].(*CharSet).Key, "should be ok")
+	AssertEqual(t, "Uppercase_Letter", prop[1].(*CharSet).Value, "should be ok")
+	AssertEqual(t, true, prop[1].(*CharSet).Negate, "should be ok")
+
+	// `\p` is an identity escape without the unicode mode
+	pat = parse(t, `\p{Foo}`, "")
+	AssertEqual(t, 'p', pat.Alts[0].Terms[0].(*Char).Value, "should be ok")
+}
+
+func TestPatternUnicodeSets(t *testing.T) {
+	pat := parse(t, `[[a-z]--[aeiou]]`, "v")
+	AssertEqual(t, N_CLASS, pat.Alts[0].Terms[0].Type(), "should be ok")
+
+	pat = parse(t, `[\p{L}&&\p{ASCII}&&[^q]]`, "v")
+	cls := pat.Alts[0].Terms[0].(*Class)
+	AssertEqual(t, 1, len(cls.Elems), "should be ok")
+	outer := cls.Elems[0].(*ClassIntersect)
+	AssertEqual(t, N_CLASS_INTERSECT, outer.Lhs.Type(), "should be ok")
+	AssertEqual(t, `[^q]`, pat.Raw(outer.Rhs), "should be ok")
+
+	pat = parse(t, `[\q{abc|d}\p{RGI_Emoji}]`, "v")
+	cls = pat.Alts[0].Terms[0].(*Class)
+	AssertEqual(t, true, cls.Strings, "should be ok")
+	disj := cls.Elems[0].(*ClassStrDisj)
+	AssertEqual(t, 2, len(disj.Alts), "should be ok")
+	AssertEqual(t, 3, len(disj.Alts[0].Chars), "should be ok")
+	AssertEqual(t, true, cls.Elems[1].(*CharSet).Strings, "should be ok")
+
+	// the intersection contains strings only if all the operands contain strings
+	parse(t, `[^\q{abc}&&a]`, "v")
+
+	testFail(t, `[^\q{abc}]`, "v", "Negated character class may contain strings at 0")
+	testFail(t, `[^[\p{RGI_Emoji}]]`, "v", "Negated character class may contain strings at 0")
+	testFail(t, `\P{RGI_Emoji}`, "v", "Invalid property name at 0")
+	testFail(t, `\p{RGI_Emoji}`, "u", "Invalid property name at 0")
+	testFail(t, `[a&&b--c]`, "v", "Invalid set operation in character class at 5")
+	testFail(t, `[a&&&b]`, "v", "Invalid character in character class at 4")
+	testFail(t, `[ab&&c]`, "v", "Invalid set operation in character class at 3")
+	testFail(t, `[a-z&&b]`, "v", "Invalid set operation in character class at 4")
+	testFail(t, `[a!!b]`, "v", "Invalid set operation in character class at 2")
+	testFail(t, `[(]`, "v", "Invalid character in character class at 1")
+	testFail(t, `[a-]`, "v", "Invalid character in character class at 3")
+}
+
+func TestPatternGroupName(t *testing.T) {
+	pat := parse(t, `(?<a>x)|(?<a>y)\k<a>`, "")
+	ref := pat.Alts[1].Terms[1].(*Backref)
+	AssertEqual(t, 2, len(ref.Groups), "should be ok")
+
+	pat = parse(t, `(?<ab$>.)(?<ø>.)`, "")
+	AssertEqual(t, "ab$", pat.Groups[0].Name, "should be ok")
+	AssertEqual(t, "ø", pat.Groups[1].Name, "should be ok")
+
+	// `\k` is an identity escape if there is no named group in Annex B
+	pat = parse(t, `\k<a>`, "")
+	AssertEqual(t, 'k', pat.Alts[0].Terms[0].(*Char).Value, "should be ok")
+
+	testFail(t, `(?<a>x)(?<a>y)`, "", "Duplicate capture group name at 9")
+	testFail(t, `(?<a>x)|((?<a>y)|z)(?<a>w)`, "", "Duplicate capture group name at 21")
+	testFail(t, `(?<1a>x)`, "", "Invalid capture group name at 3")
+	testFail(t, `(?<>x)`, "", "Invalid capture group name at 2")
+	testFail(t, `(?<a>x)\k<b>`, "", "Invalid named capture referenced at 7")
+	testFail(t, `(?<a>x)\k`, "", "Invalid named reference at 7")
+	testFail(t, `\k<a>`, "u", "Invalid named capture referenced at 0")
+}
+
+func TestPatternFail(t *testing.T) {
+	testFail(t, `[z-a]`, "", "Range out of order in character class at 1")
+	testFail(t, `[\w-a]`, "u", "Invalid character class at 1")
+	testFail(t, `a{2,1}`, "", "Numbers out of order in {} quantifier at 1")
+	testFail(t, `a{2`, "u", "Incomplete quantifier at 1")
+	testFail(t, `*a`, "", "Nothing to repeat at 0")
+	testFail(t, `a**`, "", "Nothing to repeat at 2")
+	testFail(t, `{1}`, "", "Nothing to repeat at 0")
+	testFail(t, `]`, "u", "Lone quantifier brackets at 0")
+	testFail(t, `{`, "u", "Lone quantifier brackets at 0")
+	testFail(t, `(a`, "", "Unterminated group at 0")
+	testFail(t, `a)`, "", "Unmatched ')' at 1")
+	testFail(t, `(?a)`, "", "Invalid group at 0")
+	testFail(t, `[a`, "", "Unterminated character class at 0")
+	testFail(t, `a\`, "", "\\ at end of pattern at 1")
+	testFail(t, `\-`, "u", "Invalid escape at 0")
+	testFail(t, `[\z]`, "u", "Invalid class escape at 1")
+	testFail(t, `\c1`, "u", "Invalid escape at 0")
+	testFail(t, `\x1`, "u", "Invalid escape at 0")
+	testFail(t, `\u{110000}`, "u", "Invalid Unicode escape at 0")
+	testFail(t, `\u12`, "u", "Invalid Unicode escape at 0")
+	testFail(t, `\00`, "u", "Invalid decimal escape at 0")
+	testFail(t, `(a)\2`, "u", "Invalid escape at 3")
+	testFail(t, `\p{Foo}`, "u", "Invalid property name at 0")
+	testFail(t, `\p{sc=Foo}`, "u", "Invalid property name at 0")
+	testFail(t, `\p{L`, "u", "Invalid property name at 0")
+}
+
+// the escapes which are not necessary like `\a` in Annex B or `\-` outside of the classes
+// can be found by inspecting the raw text of the characters
+func TestUselessEscape(t *testing.T) {
+	pat := parse(t, `\a[\-\]]\.-\-`, "")
+	useless := []string{}
+	var walk func(node Node, inClass bool)
+	walk = func(node Node, inClass bool) {
+		Walk(node, func(node Node) bool {
+			switch n := node.(type) {
+			case *Class:
+				for _, elem := range n.Elems {
+					walk(elem, true)
+				}
+				return false
+			case *Char:
+				raw := pat.Raw(n)
+				if len(raw) == 2 && raw[0] == '\\' && !isSyntaxChar(n.Value) && !(inClass && n.Value == '-') {
+					useless = append(useless, raw)
+				}
+			}
+			return true
+		})
+	}
+	walk(pat, false)
+	AssertEqual(t, []string{`\a`, `\-`}, useless, "should be ok")
+}
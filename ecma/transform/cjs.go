@@ -0,0 +1,591 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/astutil"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// converts the CommonJS module to the ES module, the top-level `require` calls are rewritten
+// to the import declarations and the assignments to `exports.x` and `module.exports` are
+// rewritten to the export declarations, for example:
+//
+//	const { a, b: c } = require("m");
+//	exports.x = 1;
+//
+// is converted to:
+//
+//	import { a, b as c } from "m";
+//	export const x = 1;
+//
+// the module is converted entirely or kept as it is, if any construct can not be converted
+// without changing the semantics, such as the dynamic requires, the requires not at the top
+// level and the reassigned `module`, the module is kept as it is and the constructs are
+// reported as the warnings of the transform, since the partially converted module would
+// reference `require` or `exports` which are not available in the ES modules
+type CjsToEsm struct{}
+
+func NewCjsToEsm() *CjsToEsm {
+	return &CjsToEsm{}
+}
+
+func (c *CjsToEsm) Name() string {
+	return "cjs-to-esm"
+}
+
+func (c *CjsToEsm) ParserOpts(opts *parser.ParserOpts) {}
+
+func (c *CjsToEsm) Transform(ctx *Ctx) error {
+	v := &cjsConverter{
+		ctx:      ctx,
+		p:        ctx.Printer,
+		code:     ctx.Printer.Source().Text(0, uint32(ctx.Printer.Source().Len())),
+		refs:     make([]*cjsRef, 0),
+		names:    map[string]bool{},
+		assigned: map[string]bool{},
+	}
+	v.run()
+	return nil
+}
+
+// the reference of the free `require`, `module` or `exports`
+type cjsRef struct {
+	id   *parser.Ident
+	path []parser.Node // the ancestors of the reference from its parent up to the root
+}
+
+// the export assigned at the top level by `exports.x = value`
+type cjsExport struct {
+	name  string
+	stmt  parser.Node
+	value parser.Node
+}
+
+type cjsConverter struct {
+	ctx  *Ctx
+	p    *Printer
+	code string
+
+	refs     []*cjsRef
+	names    map[string]bool // the names of the identifiers in the source
+	assigned map[string]bool // the top-level bindings which are reassigned
+
+	unsafe bool // whether any construct can not be converted
+}
+
+func (v *cjsConverter) run() {
+	ctx := walk.NewWalkCtx(v.ctx.Ast, v.ctx.Parser.Symtab())
+	walk.AddNodeBeforeListener(&ctx.Listeners, parser.N_NAME, &walk.Listener{
+		Id:     "cjs-to-esm-name",
+		Handle: v.onName,
+	})
+	walk.AddNodeAfterListener(&ctx.Listeners, parser.N_EXPR_ASSIGN, &walk.Listener{
+		Id: "cjs-to-esm-assign",
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			v.onAssign(node.(*parser.AssignExpr).Lhs(), vc)
+		},
+	})
+	walk.AddNodeAfterListener(&ctx.Listeners, parser.N_EXPR_UPDATE, &walk.Listener{
+		Id: "cjs-to-esm-update",
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			v.onAssign(node.(*parser.UpdateExpr).Arg(), vc)
+		},
+	})
+	walk.AddNodeAfterListener(&ctx.Listeners, parser.N_STMT_FOR_IN_OF, &walk.Listener{
+		Id: "cjs-to-esm-for-in-of",
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			if left := node.(*parser.ForInOfStmt).Left(); left.Type() != parser.N_STMT_VAR_DEC {
+				v.onAssign(left, vc)
+			}
+		},
+	})
+	walk.VisitNode(v.ctx.Ast, "", ctx.VisitorCtx())
+
+	exports := v.convertExports()
+	imports := v.convertImports()
+	if v.unsafe {
+		v.ctx.Warn(span.Range{}, "the module is kept as it is since some of its CommonJS constructs can not be converted")
+		return
+	}
+
+	// the exports are converted before the imports since the texts of the declarations which
+	// are kept in the converted imports should include the edits of the exports
+	exports()
+	imports()
+}
+
+// reports the construct which can not be converted, the module is kept as it is
+func (v *cjsConverter) warn(rng span.Range, msg string) {
+	v.ctx.Warn(rng, msg)
+	v.unsafe = true
+}
+
+func (v *cjsConverter) onName(node parser.Node, key string, vc *walk.VisitorCtx) {
+	id := node.(*parser.Ident)
+	if id.IsPrivate() || !astutil.IsRefIdent(id, vc.ParentNode()) {
+		return
+	}
+	name := id.Val()
+	v.names[name] = true
+
+	switch name {
+	case "require", "module", "exports", "__dirname", "__filename":
+		if vc.Scope().BindingOf(name) != nil {
+			return
+		}
+	default:
+		return
+	}
+	if name == "__dirname" || name == "__filename" {
+		v.warn(id.Range(), fmt.Sprintf("`%s` is not available in the ES modules", name))
+		return
+	}
+
+	path := make([]parser.Node, 0)
+	for c := vc.Parent; c != nil; c = c.Parent {
+		path = append(path, c.Node)
+	}
+	v.refs = append(v.refs, &cjsRef{id, path})
+}
+
+// records the top-level bindings assigned by the target of the assignment
+func (v *cjsConverter) onAssign(lhs parser.Node, vc *walk.VisitorCtx) {
+	names := map[string]bool{}
+	patNames(lhs, names)
+	for name := range names {
+		if ref := vc.Scope().BindingOf(name); ref != nil && ref.Scope.Id == 0 {
+			v.assigned[name] = true
+		}
+	}
+}
+
+// returns a name based on `base` which is not used in the source
+func (v *cjsConverter) name(base string) string {
+	name := base
+	for i := 1; v.names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	v.names[name] = true
+	return name
+}
+
+func (v *cjsConverter) text(node parser.Node) string {
+	return v.p.Text(node.Range())
+}
+
+// the static key of the member expression, `ok` is false if the key is computed by an
+// expression or it's not a valid identifier
+func staticKeyOf(m *parser.MemberExpr) (string, bool) {
+	if !m.Compute() {
+		return m.Prop().(*parser.Ident).Val(), true
+	}
+	str, ok := m.Prop().(*parser.StrLit)
+	if !ok || !isIdName(str.Val()) {
+		return "", false
+	}
+	return str.Val(), true
+}
+
+func isIdName(s string) bool {
+	for i, r := range s {
+		if r == '\\' || !parser.IsIdStart(r) && (i == 0 || !parser.IsIdPart(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isTopLevel(path []parser.Node, i int) bool {
+	return len(path) > i && path[i].Type() == parser.N_PROG
+}
+
+// the node which stands for the exports object, it's `exports` or `module.exports`, nil is
+// returned if the reference is `module` which is not followed by `.exports`
+func (v *cjsConverter) exportsOf(ref *cjsRef) (parser.Node, []parser.Node) {
+	if ref.id.Val() == "exports" {
+		return ref.id, ref.path
+	}
+	if m, ok := ref.path[0].(*parser.MemberExpr); ok && m.Obj() == ref.id {
+		if key, ok := staticKeyOf(m); ok && key == "exports" {
+			return m, ref.path[1:]
+		}
+	}
+	return nil, nil
+}
+
+// whether the statement is `Object.defineProperty(exports, "__esModule", { value: true })`
+func isEsModuleDef(call *parser.CallExpr, obj parser.Node) bool {
+	callee, ok := call.Callee().(*parser.MemberExpr)
+	if !ok || len(call.Args()) < 2 || call.Args()[0] != obj {
+		return false
+	}
+	o, ok := callee.Obj().(*parser.Ident)
+	if !ok || o.Val() != "Object" {
+		return false
+	}
+	key, ok := staticKeyOf(callee)
+	str, isStr := call.Args()[1].(*parser.StrLit)
+	return ok && key == "defineProperty" && isStr && str.Val() == "__esModule"
+}
+
+// checks the exports and returns the function to convert them, `unsafe` is set if they can
+// not be converted
+func (v *cjsConverter) convertExports() func() {
+	var def parser.Node // the statement `module.exports = value`
+	exports := make([]*cjsExport, 0)
+	named := map[string]*cjsExport{}
+	reads := map[string][]parser.Node{}
+	readNames := make([]string, 0)
+	removed := make([]parser.Node, 0)
+
+	for _, ref := range v.refs {
+		if ref.id.Val() == "require" {
+			continue
+		}
+
+		obj, path := v.exportsOf(ref)
+		if obj == nil {
+			if a, ok := ref.path[0].(*parser.AssignExpr); ok && a.Lhs() == ref.id {
+				v.warn(ref.id.Range(), "`module` is reassigned, the exports are kept as they are")
+			} else if m, ok := ref.path[0].(*parser.MemberExpr); !ok || m.Obj() != ref.id {
+				v.warn(ref.id.Range(), "`module` is referenced as a value, the exports are kept as they are")
+			}
+			continue
+		}
+
+		switch p := path[0].(type) {
+		case *parser.AssignExpr:
+			if p.Lhs() != obj {
+				break
+			}
+			if obj != ref.id && p.Op() == parser.T_ASSIGN && path[1].Type() == parser.N_STMT_EXPR && isTopLevel(path, 2) {
+				if def != nil {
+					v.warn(p.Range(), "`module.exports` is assigned more than once, the exports are kept as they are")
+				}
+				def = path[1]
+				continue
+			}
+			v.warn(p.Range(), fmt.Sprintf("`%s` is reassigned, the exports are kept as they are", v.text(obj)))
+			continue
+
+		case *parser.MemberExpr:
+			if p.Obj() != obj {
+				break
+			}
+			name, ok := staticKeyOf(p)
+			if !ok {
+				v.warn(p.Range(), "the export with the computed name can not be converted, the exports are kept as they are")
+				continue
+			}
+
+			switch pp := path[1].(type) {
+			case *parser.AssignExpr:
+				if pp.Lhs() != p {
+					break
+				}
+				if pp.Op() == parser.T_ASSIGN && path[2].Type() == parser.N_STMT_EXPR && isTopLevel(path, 3) {
+					if name == "__esModule" {
+						removed = append(removed, path[2])
+						continue
+					}
+					if named[name] != nil {
+						v.warn(pp.Range(), fmt.Sprintf("the export `%s` is assigned more than once, the exports are kept as they are", name))
+						continue
+					}
+					e := &cjsExport{name, path[2], pp.Rhs()}
+					exports = append(exports, e)
+					named[name] = e
+					continue
+				}
+				v.warn(pp.Range(), fmt.Sprintf("the export `%s` is assigned not at the top level, the exports are kept as they are", name))
+				continue
+			case *parser.UpdateExpr:
+				v.warn(pp.Range(), fmt.Sprintf("the export `%s` is assigned not at the top level, the exports are kept as they are", name))
+				continue
+			}
+			if reads[name] == nil {
+				readNames = append(readNames, name)
+			}
+			reads[name] = append(reads[name], p)
+			continue
+
+		case *parser.CallExpr:
+			if isEsModuleDef(p, obj) && path[1].Type() == parser.N_STMT_EXPR && isTopLevel(path, 2) {
+				removed = append(removed, path[1])
+				continue
+			}
+		}
+		v.warn(obj.Range(), fmt.Sprintf("`%s` is referenced as a value, the exports are kept as they are", v.text(obj)))
+	}
+
+	if def != nil && len(exports) > 0 {
+		v.warn(def.Range(), "`module.exports` and `exports.x` are both assigned, the exports are kept as they are")
+	}
+	for _, name := range readNames {
+		if named[name] == nil {
+			v.warn(reads[name][0].Range(), fmt.Sprintf("the export `%s` is read without being exported at the top level, the exports are kept as they are", name))
+		}
+	}
+	return func() {
+		v.applyExports(def, exports, reads, removed)
+	}
+}
+
+func (v *cjsConverter) applyExports(def parser.Node, exports []*cjsExport, reads map[string][]parser.Node, removed []parser.Node) {
+	for _, stmt := range removed {
+		v.removeStmt(stmt)
+	}
+
+	if def != nil {
+		rhs := def.(*parser.ExprStmt).Expr().(*parser.AssignExpr).Rhs()
+		head := "export default "
+		if v.needParen(rhs) && outerRange(rhs) == rhs.Range() {
+			v.p.Insert(rhs.Range().Hi, ")")
+			head += "("
+		}
+		v.p.Replace(span.Range{Lo: def.Range().Lo, Hi: outerRange(rhs).Lo}, head)
+		return
+	}
+
+	for _, e := range exports {
+		local := e.name
+		if id, ok := e.value.(*parser.Ident); ok && !v.assigned[id.Val()] {
+			if ref := v.ctx.Parser.Symtab().Scopes[0].Local(id.Val()); ref != nil {
+				// the binding is exported directly since it's never reassigned
+				local = id.Val()
+				v.p.Replace(e.stmt.Range(), exportSpec(local, e.name))
+				v.replaceReads(reads[e.name], local)
+				continue
+			}
+		}
+
+		if v.names[local] {
+			local = v.name("_" + e.name)
+		}
+		head := fmt.Sprintf("export const %s = ", local)
+		if local != e.name {
+			head = fmt.Sprintf("const %s = ", local)
+		}
+		v.p.Replace(span.Range{Lo: e.stmt.Range().Lo, Hi: outerRange(e.value).Lo}, head)
+		if !strings.HasSuffix(v.code[:e.stmt.Range().Hi], ";") {
+			v.p.Insert(e.stmt.Range().Hi, ";")
+		}
+		if local != e.name {
+			v.p.Insert(e.stmt.Range().Hi, " "+exportSpec(local, e.name))
+		}
+		v.replaceReads(reads[e.name], local)
+	}
+}
+
+// whether the value of `export default` should be parenthesized, the named function or class
+// becomes a declaration after `export default` which may conflict with the top-level bindings
+func (v *cjsConverter) needParen(value parser.Node) bool {
+	var id parser.Node
+	switch n := value.(type) {
+	case *parser.FnDec:
+		id = n.Id()
+	case *parser.ClassDec:
+		id = n.Id()
+	default:
+		return value.Type() == parser.N_EXPR_SEQ
+	}
+	return id != nil && v.ctx.Parser.Symtab().Scopes[0].Local(id.(*parser.Ident).Val()) != nil
+}
+
+func exportSpec(local, name string) string {
+	if local == name {
+		return fmt.Sprintf("export { %s };", name)
+	}
+	return fmt.Sprintf("export { %s as %s };", local, name)
+}
+
+func (v *cjsConverter) replaceReads(reads []parser.Node, local string) {
+	for _, r := range reads {
+		v.p.Replace(r.Range(), local)
+	}
+}
+
+// removes the statement with its line if the line has nothing else
+func (v *cjsConverter) removeStmt(stmt parser.Node) {
+	rng := stmt.Range()
+	lo := rng.Lo
+	for lo > 0 && isBlank(v.code[lo-1]) {
+		lo--
+	}
+	hi := rng.Hi
+	for int(hi) < len(v.code) && isBlank(v.code[hi]) {
+		hi++
+	}
+	if (lo == 0 || v.code[lo-1] == '\n') && (int(hi) == len(v.code) || v.code[hi] == '\n' || v.code[hi] == '\r') {
+		if int(hi) < len(v.code) && v.code[hi] == '\r' {
+			hi++
+		}
+		if int(hi) < len(v.code) && v.code[hi] == '\n' {
+			hi++
+		}
+		rng = span.Range{Lo: lo, Hi: hi}
+	}
+	v.p.Remove(rng)
+}
+
+// the import converted from the declarator whose value is a `require` call, `ok` is false
+// if the declarator can not be converted
+func (v *cjsConverter) importOf(dec *parser.VarDec, src string, member string) (string, bool) {
+	names := map[string]bool{}
+	patNames(dec.Id(), names)
+	for _, name := range sortedKeys(names) {
+		if v.assigned[name] {
+			v.warn(dec.Range(), fmt.Sprintf("`%s` is reassigned, the `require` call is kept as it is", name))
+			return "", false
+		}
+	}
+
+	switch id := dec.Id().(type) {
+	case *parser.Ident:
+		switch member {
+		case "", "default":
+			return fmt.Sprintf("import %s from %s;", id.Val(), src), true
+		case id.Val():
+			return fmt.Sprintf("import { %s } from %s;", member, src), true
+		}
+		return fmt.Sprintf("import { %s as %s } from %s;", member, id.Val(), src), true
+
+	case *parser.ObjPat:
+		if member != "" {
+			break
+		}
+		specs := make([]string, 0, len(id.Props()))
+		for _, prop := range id.Props() {
+			p, ok := prop.(*parser.Prop)
+			if !ok || p.Computed() {
+				return v.unsafePat(dec)
+			}
+			local, ok := p.Val().(*parser.Ident)
+			if !ok {
+				return v.unsafePat(dec)
+			}
+			var key string
+			switch k := p.Key().(type) {
+			case *parser.Ident:
+				key = k.Val()
+			case *parser.StrLit:
+				key = k.Val()
+			}
+			if !isIdName(key) {
+				return v.unsafePat(dec)
+			}
+			if key == local.Val() {
+				specs = append(specs, key)
+			} else {
+				specs = append(specs, key+" as "+local.Val())
+			}
+		}
+		return fmt.Sprintf("import { %s } from %s;", strings.Join(specs, ", "), src), true
+	}
+	return v.unsafePat(dec)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *cjsConverter) unsafePat(dec *parser.VarDec) (string, bool) {
+	v.warn(dec.Id().Range(), "the pattern can not be converted to the import specifiers, the `require` call is kept as it is")
+	return "", false
+}
+
+// checks the requires and returns the function to convert them to the imports, `unsafe` is
+// set if they can not be converted
+func (v *cjsConverter) convertImports() func() {
+	stmts := make([]*parser.VarDecStmt, 0)
+	sides := make([]parser.Node, 0)     // the statements of the requires for the side effects
+	imports := map[parser.Node]string{} // the declarators or the statements to their imports
+
+	for _, ref := range v.refs {
+		if ref.id.Val() != "require" {
+			continue
+		}
+
+		call, ok := ref.path[0].(*parser.CallExpr)
+		if !ok || call.Callee() != ref.id {
+			v.warn(ref.id.Range(), "`require` is referenced as a value, it's kept as it is")
+			continue
+		}
+		if len(call.Args()) != 1 || call.Args()[0].Type() != parser.N_LIT_STR {
+			v.warn(call.Range(), "the dynamic `require` can not be converted, it's kept as it is")
+			continue
+		}
+		src := v.text(call.Args()[0])
+
+		if ref.path[1].Type() == parser.N_STMT_EXPR && isTopLevel(ref.path, 2) {
+			imports[ref.path[1]] = fmt.Sprintf("import %s;", src)
+			sides = append(sides, ref.path[1])
+			continue
+		}
+
+		// `require("m")` or `require("m").x` as the value of a top-level declarator
+		i, member := 1, ""
+		if m, ok := ref.path[1].(*parser.MemberExpr); ok && m.Obj() == call {
+			if key, ok := staticKeyOf(m); ok {
+				i, member = 2, key
+			}
+		}
+		dec, ok := ref.path[i].(*parser.VarDec)
+		if !ok || dec.Init() != ref.path[i-1] || !isTopLevel(ref.path, i+2) {
+			v.warn(call.Range(), "the `require` call which is conditional or not at the top level can not be converted, it's kept as it is")
+			continue
+		}
+		if imp, ok := v.importOf(dec, src, member); ok {
+			stmt := ref.path[i+1].(*parser.VarDecStmt)
+			if !containsStmt(stmts, stmt) {
+				stmts = append(stmts, stmt)
+			}
+			imports[dec] = imp
+		}
+	}
+
+	return func() {
+		v.applyImports(stmts, sides, imports)
+	}
+}
+
+func (v *cjsConverter) applyImports(stmts []*parser.VarDecStmt, sides []parser.Node, imports map[parser.Node]string) {
+	for _, stmt := range sides {
+		v.p.Replace(stmt.Range(), imports[stmt])
+	}
+	for _, stmt := range stmts {
+		parts := make([]string, 0)
+		rest := make([]string, 0)
+		for _, dec := range stmt.DecList() {
+			if imp, ok := imports[dec]; ok {
+				parts = append(parts, imp)
+			} else {
+				rest = append(rest, v.text(dec))
+			}
+		}
+		if len(rest) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s;", stmt.Kind(), strings.Join(rest, ", ")))
+		}
+		v.p.Replace(stmt.Range(), strings.Join(parts, "\n"+util.IndentOf(v.code, stmt.Range().Lo)))
+	}
+}
+
+func containsStmt(stmts []*parser.VarDecStmt, stmt *parser.VarDecStmt) bool {
+	for _, s := range stmts {
+		if s == stmt {
+			return true
+		}
+	}
+	return false
+}
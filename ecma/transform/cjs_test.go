@@ -0,0 +1,169 @@
+package transform
+
+import (
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func cjsToEsm(t *testing.T, code string) *Result {
+	ret, err := Run("a.js", code, nil, NewCjsToEsm())
+	AssertEqual(t, nil, err, "should be ok")
+	return ret
+}
+
+func warningsOf(ret *Result) []string {
+	msgs := make([]string, len(ret.Warnings))
+	for i, w := range ret.Warnings {
+		msgs[i] = w.Error()
+	}
+	return msgs
+}
+
+func TestCjsToEsmRequire(t *testing.T) {
+	ret := cjsToEsm(t, `const fs = require("fs"), n = 1, { join, resolve: r } = require("path");
+const dft = require("./x").default;
+const y = require('./y').y;
+let z = require("./y").x;
+require("./side");`)
+
+	AssertEqualString(t, `import fs from "fs";
+import { join, resolve as r } from "path";
+const n = 1;
+import dft from "./x";
+import { y } from './y';
+import { x as z } from "./y";
+import "./side";`, ret.Code, "should be ok")
+	AssertEqual(t, 0, len(ret.Warnings), "should be ok")
+}
+
+func TestCjsToEsmRequireUnsafe(t *testing.T) {
+	ret := cjsToEsm(t, `if (a) require("./cond");
+const m = require(name);
+let x = require("x");
+x = 2;
+const [b] = require("b");
+const r = require;
+function f(require) { return require("y"); }`)
+
+	AssertEqualString(t, `if (a) require("./cond");
+const m = require(name);
+let x = require("x");
+x = 2;
+const [b] = require("b");
+const r = require;
+function f(require) { return require("y"); }`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"the `require` call which is conditional or not at the top level can not be converted, it's kept as it is at (1:7)",
+		"the dynamic `require` can not be converted, it's kept as it is at (2:10)",
+		"`x` is reassigned, the `require` call is kept as it is at (3:4)",
+		"the pattern can not be converted to the import specifiers, the `require` call is kept as it is at (5:6)",
+		"`require` is referenced as a value, it's kept as it is at (6:10)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+}
+
+func TestCjsToEsmPartial(t *testing.T) {
+	ret := cjsToEsm(t, `const a = require("a");
+exports.x = a;
+if (b) exports.x = 2;`)
+	AssertEqualString(t, `const a = require("a");
+exports.x = a;
+if (b) exports.x = 2;`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"the export `x` is assigned not at the top level, the exports are kept as they are at (3:7)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+
+	ret = cjsToEsm(t, `const a = require("a");
+const m = require(name);
+exports.x = a;`)
+	AssertEqualString(t, `const a = require("a");
+const m = require(name);
+exports.x = a;`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"the dynamic `require` can not be converted, it's kept as it is at (2:10)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+
+	ret = cjsToEsm(t, `var a = require("a");
+a = 2;
+exports.q = a;`)
+	AssertEqualString(t, `var a = require("a");
+a = 2;
+exports.q = a;`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"`a` is reassigned, the `require` call is kept as it is at (1:4)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+
+	ret = cjsToEsm(t, `const { a: { b } } = require("a");
+exports.q = b;`)
+	AssertEqualString(t, `const { a: { b } } = require("a");
+exports.q = b;`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"the pattern can not be converted to the import specifiers, the `require` call is kept as it is at (1:6)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+
+	ret = cjsToEsm(t, `const a = require("a");
+console.log(__dirname);`)
+	AssertEqualString(t, `const a = require("a");
+console.log(__dirname);`, ret.Code, "should be ok")
+}
+
+func TestCjsToEsmExports(t *testing.T) {
+	ret := cjsToEsm(t, `"use strict";
+Object.defineProperty(exports, "__esModule", { value: true });
+const n = 1;
+function foo() { return exports.bar + 1; }
+exports.foo = foo;
+exports.bar = 2;
+exports.n = n;
+module.exports.baz = function () {};
+exports.setTimeout = setTimeout`)
+
+	AssertEqualString(t, `"use strict";
+const n = 1;
+function foo() { return bar + 1; }
+export { foo };
+export const bar = 2;
+export { n };
+export const baz = function () {};
+const _setTimeout = setTimeout; export { _setTimeout as setTimeout };`, ret.Code, "should be ok")
+	AssertEqual(t, 0, len(ret.Warnings), "should be ok")
+}
+
+func TestCjsToEsmDefaultExport(t *testing.T) {
+	ret := cjsToEsm(t, `const a = require("a");
+module.exports = (a, 1);`)
+	AssertEqualString(t, `import a from "a";
+export default (a, 1);`, ret.Code, "should be ok")
+
+	ret = cjsToEsm(t, `let f = 1;
+module.exports = function f() {};`)
+	AssertEqualString(t, `let f = 1;
+export default (function f() {});`, ret.Code, "should be ok")
+}
+
+func TestCjsToEsmExportsUnsafe(t *testing.T) {
+	ret := cjsToEsm(t, `exports.a = 1;
+module.exports = {};`)
+	AssertEqualString(t, `exports.a = 1;
+module.exports = {};`, ret.Code, "should be ok")
+	AssertEqual(t, []string{
+		"`module.exports` and `exports.x` are both assigned, the exports are kept as they are at (2:0)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+
+	ret = cjsToEsm(t, `exports.a = 1;
+if (b) exports.a = 2;
+module = {};
+f(exports);`)
+	AssertEqual(t, []string{
+		"the export `a` is assigned not at the top level, the exports are kept as they are at (2:7)",
+		"`module` is reassigned, the exports are kept as they are at (3:0)",
+		"`exports` is referenced as a value, the exports are kept as they are at (4:2)",
+		"the module is kept as it is since some of its CommonJS constructs can not be converted at (1:0)",
+	}, warningsOf(ret), "should be ok")
+}
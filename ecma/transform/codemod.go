@@ -0,0 +1,168 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+type CodemodOpts struct {
+	// the extensions of the files to transform
+	Exts []string
+
+	// only reports the diffs of the files instead of writing them
+	DryRun bool
+
+	// the number of the files transformed concurrently
+	Concurrent int
+
+	// the options used to parse the files, they are adjusted by the extensions of the files,
+	// see `parser.ParserOpts.OfFile`
+	ParserOpts *parser.ParserOpts
+}
+
+func NewCodemodOpts() *CodemodOpts {
+	return &CodemodOpts{
+		Exts:       []string{".js", ".mjs", ".cjs", ".jsx", ".ts", ".mts", ".cts", ".tsx"},
+		Concurrent: runtime.NumCPU(),
+		ParserOpts: parser.NewParserOpts(),
+	}
+}
+
+type CodemodResult struct {
+	File     string
+	Changed  bool
+	Diff     string // the unified diff of the changes, it's empty if the file is not changed
+	Warnings []*Warning
+
+	// the error occurs when processing the file, such as the syntax error, it does not
+	// stop the other files from being processed
+	Err error
+}
+
+func (o *CodemodOpts) accept(file string) bool {
+	ext := filepath.Ext(file)
+	for _, e := range o.Exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// runs the transformers on the file, the file is rewritten in place unless `opts.DryRun` is
+// turned on, the untouched source text is kept as it is since the transformers only record
+// the edits of the changed ranges
+func CodemodFile(file string, opts *CodemodOpts, transformers ...Transformer) *CodemodResult {
+	if opts == nil {
+		opts = NewCodemodOpts()
+	}
+	ret := &CodemodResult{File: file}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		ret.Err = err
+		return ret
+	}
+	b, err := os.ReadFile(file)
+	if err != nil {
+		ret.Err = err
+		return ret
+	}
+	code := string(b)
+
+	out, err := Run(file, code, opts.ParserOpts.OfFile(file), transformers...)
+	if err != nil {
+		ret.Err = err
+		return ret
+	}
+	ret.Warnings = out.Warnings
+	if out.Code == code {
+		return ret
+	}
+
+	ret.Changed = true
+	ret.Diff = util.UnifiedDiff(file, file, code, out.Code, 3)
+	if !opts.DryRun {
+		ret.Err = os.WriteFile(file, []byte(out.Code), info.Mode().Perm())
+	}
+	return ret
+}
+
+// whether the file is in the directories which are skipped by the codemod, they are the
+// dependencies in `node_modules` and the hidden directories like `.git`
+func skippedByCodemod(rel string) bool {
+	for _, seg := range strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/") {
+		if seg == "node_modules" || len(seg) > 1 && seg[0] == '.' && seg != ".." {
+			return true
+		}
+	}
+	return false
+}
+
+// runs the transformers on the files under `dir` concurrently, the transformers are shared by
+// the files so they should keep the states of a transform in the struct created by their
+// `Transform` method, the results are sorted by the paths of the files and the error is only
+// returned if the directory can not be walked
+func Codemod(dir string, opts *CodemodOpts, transformers ...Transformer) ([]*CodemodResult, error) {
+	if opts == nil {
+		opts = NewCodemodOpts()
+	}
+	concurrent := opts.Concurrent
+	if concurrent <= 0 {
+		concurrent = 1
+	}
+
+	files := make(chan string, concurrent)
+	rets := make([]*CodemodResult, 0)
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range files {
+				ret := CodemodFile(file, opts, transformers...)
+				lock.Lock()
+				rets = append(rets, ret)
+				lock.Unlock()
+			}
+		}()
+	}
+
+	// the walker returns once it fails to read a directory while its other workers may still
+	// be handling the files, so the channel is closed under the lock which guards the sends
+	// and the files handled after that are dropped instead of being sent to the closed channel
+	var sendLock sync.Mutex
+	closed := false
+	w := util.NewDirWalker(dir, 0, func(file string, isDir bool, dw *util.DirWalker) {
+		if isDir || !opts.accept(file) {
+			return
+		}
+		if rel, err := filepath.Rel(dir, file); err == nil && skippedByCodemod(rel) {
+			return
+		}
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		if !closed {
+			files <- file
+		}
+	})
+	w.Walk()
+	sendLock.Lock()
+	closed = true
+	close(files)
+	sendLock.Unlock()
+	wg.Wait()
+
+	sort.Slice(rets, func(i, j int) bool {
+		return rets[i].File < rets[j].File
+	})
+	return rets, w.Err()
+}
@@ -0,0 +1,85 @@
+package transform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, code := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readFile(t *testing.T, file string) string {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCodemod(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"a.js":              "// a\nconst fs = require(\"fs\");\n\nfs.readFileSync(x);\n",
+		"b.js":              "let x = 1;\n",
+		"sub/c.js":          "const = 1;\n",
+		"sub/d.ts":          "const e: number = require(e);\n",
+		"node_modules/m.js": "const m = require(\"m\");\n",
+		"e.txt":             "const e = require(\"e\");\n",
+	})
+
+	opts := NewCodemodOpts()
+	opts.DryRun = true
+	rets, err := Codemod(dir, opts, NewCjsToEsm())
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, 4, len(rets), "should be ok")
+
+	a := filepath.Join(dir, "a.js")
+	AssertEqual(t, a, rets[0].File, "should be ok")
+	AssertEqual(t, true, rets[0].Changed, "should be ok")
+	AssertEqualString(t, "--- "+a+"\n+++ "+a+`
+@@ -1,4 +1,4 @@
+ // a
+-const fs = require("fs");
++import fs from "fs";
+`+" \n"+` fs.readFileSync(x);
+`, rets[0].Diff, "should be ok")
+	AssertEqual(t, "// a\nconst fs = require(\"fs\");\n\nfs.readFileSync(x);\n", readFile(t, a), "should be ok")
+
+	AssertEqual(t, false, rets[1].Changed, "should be ok")
+	AssertEqual(t, nil, rets[1].Err, "should be ok")
+	AssertEqual(t, true, rets[2].Err != nil, "should be ok")
+	AssertEqual(t, 2, len(rets[3].Warnings), "should be ok")
+
+	opts.DryRun = false
+	rets, err = Codemod(dir, opts, NewCjsToEsm())
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, 4, len(rets), "should be ok")
+	AssertEqual(t, "// a\nimport fs from \"fs\";\n\nfs.readFileSync(x);\n", readFile(t, a), "should be ok")
+	AssertEqual(t, "const m = require(\"m\");\n", readFile(t, filepath.Join(dir, "node_modules/m.js")), "should be ok")
+}
+
+func TestRegistry(t *testing.T) {
+	ts, ok := Lookup("cjs-to-esm")
+	AssertEqual(t, true, ok, "should be ok")
+	AssertEqual(t, "cjs-to-esm", ts[0].Name(), "should be ok")
+
+	_, ok = Lookup("none")
+	AssertEqual(t, false, ok, "should be ok")
+
+	Register("test-noop", func() []Transformer { return nil })
+	ts, ok = Lookup("test-noop")
+	AssertEqual(t, true, ok, "should be ok")
+	AssertEqual(t, 0, len(ts), "should be ok")
+}
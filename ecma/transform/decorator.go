@@ -0,0 +1,985 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+type DecoratorVersion int
+
+const (
+	// the legacy decorators which are enabled by the `experimentalDecorators` of typescript
+	DV_LEGACY DecoratorVersion = iota
+
+	// the decorators proposal reached stage 3 in 2023, which is supported by typescript 5.0+
+	DV_2023
+)
+
+type DecoratorOpts struct {
+	Version DecoratorVersion
+
+	// emits the design-time types of the decorated elements by `__metadata`, it's the
+	// equivalent of `emitDecoratorMetadata` of typescript and only works with `DV_LEGACY`
+	EmitMetadata bool
+}
+
+func NewDecoratorOpts() *DecoratorOpts {
+	return &DecoratorOpts{Version: DV_LEGACY}
+}
+
+// lowers the decorators to the plain javascript, the output of `DV_LEGACY` is compatible
+// with `tsc --experimentalDecorators` and the output of `DV_2023` follows the semantics
+// of the stage 3 proposal
+type Decorators struct {
+	opts *DecoratorOpts
+}
+
+func NewDecorators(opts *DecoratorOpts) *Decorators {
+	if opts == nil {
+		opts = NewDecoratorOpts()
+	}
+	return &Decorators{opts}
+}
+
+func (t *Decorators) Name() string {
+	return "decorators"
+}
+
+func (t *Decorators) ParserOpts(opts *parser.ParserOpts) {
+	opts.Feature = opts.Feature.On(parser.FEAT_DECORATOR)
+}
+
+func (t *Decorators) Transform(ctx *Ctx) error {
+	d := &decLowering{
+		ctx:     ctx,
+		opts:    t.opts,
+		p:       ctx.Printer,
+		code:    ctx.Printer.Source().Text(0, uint32(ctx.Printer.Source().Len())),
+		helpers: map[string]bool{},
+	}
+
+	wc := walk.NewWalkCtx(ctx.Ast, ctx.Parser.Symtab())
+	// the classes are lowered in post-order, so the nested classes are lowered before
+	// their outer classes take the transformed text of them
+	lower := &walk.Listener{
+		Id: "decorators",
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			if d.err == nil {
+				d.err = d.lower(node.(*parser.ClassDec), vc)
+			}
+		},
+	}
+	walk.AddNodeAfterListener(&wc.Listeners, parser.N_STMT_CLASS, lower)
+	walk.AddNodeAfterListener(&wc.Listeners, parser.N_EXPR_CLASS, lower)
+	walk.VisitNode(ctx.Ast, "", wc.VisitorCtx())
+	if d.err != nil {
+		return d.err
+	}
+
+	injectHelpers(ctx, decHelpers, d.helpers)
+	return nil
+}
+
+type decLowering struct {
+	ctx     *Ctx
+	opts    *DecoratorOpts
+	p       *Printer
+	code    string
+	helpers map[string]bool
+	err     error
+
+	// the names of the temporary variables used in the class being lowered
+	tmps map[string]bool
+}
+
+// the class element which is a method, an accessor, a field or an auto-accessor field like
+// `accessor x = 1`
+type decElem struct {
+	node     parser.Node
+	kind     string
+	static   bool
+	key      parser.Node
+	computed bool
+	fn       *parser.FnDec
+	val      parser.Node
+	decs     []parser.Node
+}
+
+func (e *decElem) private() bool {
+	id, ok := e.key.(*parser.Ident)
+	return ok && !e.computed && id.IsPrivate()
+}
+
+func hasParamDecorators(fn *parser.FnDec) bool {
+	if fn == nil {
+		return false
+	}
+	for _, param := range fn.Params() {
+		if len(parser.DecoratorsOf(param)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *decLowering) errorf(rng span.Range, format string, args ...interface{}) error {
+	return &Warning{d.p.Source(), rng, fmt.Sprintf(format, args...)}
+}
+
+// collects the elements of the class, the constructor is returned separately
+func (d *decLowering) elems(cls *parser.ClassDec) ([]*decElem, *parser.Method) {
+	elems := make([]*decElem, 0)
+	var ctor *parser.Method
+	for _, elem := range cls.Body().(*parser.ClassBody).Elems() {
+		switch n := elem.(type) {
+		case *parser.Method:
+			fn, _ := n.Val().(*parser.FnDec)
+			kind := n.Kind()
+			if kind == "constructor" {
+				ctor = n
+				continue
+			}
+			// the overload signatures
+			if !n.HasBody() {
+				continue
+			}
+			switch kind {
+			case "get":
+				kind = "getter"
+			case "set":
+				kind = "setter"
+			default:
+				kind = "method"
+			}
+			elems = append(elems, &decElem{n, kind, n.Static(), n.Key(), n.Computed(), fn, nil, parser.DecoratorsOf(n)})
+		case *parser.Field:
+			if n.IsTsSig() {
+				continue
+			}
+			kind := "field"
+			if ti := n.TypInfo(); ti != nil && ti.Accessor() {
+				kind = "accessor"
+			}
+			elems = append(elems, &decElem{n, kind, n.Static(), n.Key(), n.Computed(), nil, n.Val(), parser.DecoratorsOf(n)})
+		}
+	}
+	return elems, ctor
+}
+
+func (d *decLowering) lower(cls *parser.ClassDec, vc *walk.VisitorCtx) error {
+	elems, ctor := d.elems(cls)
+	var ctorFn *parser.FnDec
+	if ctor != nil {
+		ctorFn, _ = ctor.Val().(*parser.FnDec)
+	}
+
+	decorated := len(parser.DecoratorsOf(cls)) > 0 || hasParamDecorators(ctorFn)
+	for _, e := range elems {
+		if len(e.decs) > 0 || hasParamDecorators(e.fn) {
+			decorated = true
+			if e.private() {
+				return d.errorf(e.node.Range(), "decorating the private element `%s` is not supported", d.p.NodeText(e.key))
+			}
+		}
+	}
+	if !decorated {
+		return nil
+	}
+
+	d.tmps = map[string]bool{}
+	if d.opts.Version == DV_2023 {
+		return d.lower2023(cls, elems, ctor, vc)
+	}
+	return d.lowerLegacy(cls, elems, ctorFn, vc)
+}
+
+// removes the decorators with the whitespaces after them
+func (d *decLowering) removeDecs(decs []parser.Node) {
+	for _, dec := range decs {
+		rng := dec.Range()
+		d.p.Remove(span.Range{Lo: rng.Lo, Hi: skipSpaces(d.code, rng.Hi)})
+	}
+}
+
+func (d *decLowering) removeParamDecs(fn *parser.FnDec) {
+	if fn == nil {
+		return
+	}
+	for _, param := range fn.Params() {
+		d.removeDecs(parser.DecoratorsOf(param))
+	}
+}
+
+func (d *decLowering) decTexts(decs []parser.Node) []string {
+	ret := make([]string, len(decs))
+	for i, dec := range decs {
+		rng := dec.Range()
+		ret[i] = d.p.Text(span.Range{Lo: rng.Lo + 1, Hi: rng.Hi})
+	}
+	return ret
+}
+
+// the decorators of the params wrapped by `__param`
+func (d *decLowering) paramDecTexts(fn *parser.FnDec) []string {
+	ret := make([]string, 0)
+	if fn == nil {
+		return ret
+	}
+	for i, param := range fn.Params() {
+		for _, txt := range d.decTexts(parser.DecoratorsOf(param)) {
+			d.helpers["__param"] = true
+			ret = append(ret, fmt.Sprintf("__param(%d, %s)", i, txt))
+		}
+	}
+	return ret
+}
+
+// the offset of the `class` keyword, the decorators and the modifiers before it are skipped
+func (d *decLowering) classKw(cls *parser.ClassDec) uint32 {
+	ofst := cls.Range().Lo
+	if decs := parser.DecoratorsOf(cls); len(decs) > 0 {
+		ofst = decs[len(decs)-1].Range().Hi
+	}
+	for {
+		ofst = skipSpaces(d.code, ofst)
+		matched := false
+		for _, kw := range []string{"export", "default", "abstract"} {
+			end := int(ofst) + len(kw)
+			if strings.HasPrefix(d.code[ofst:], kw) && (end == len(d.code) || !isIdByte(d.code[end])) {
+				ofst = uint32(end)
+				matched = true
+			}
+		}
+		if !matched {
+			return ofst
+		}
+	}
+}
+
+func isIdByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// the range of the statement which declares the class, it's the `export` statement if the class
+// is exported, the decorators before `export` are also included
+func stmtOfClass(cls *parser.ClassDec, vc *walk.VisitorCtx) (span.Range, *parser.ExportDec) {
+	rng := cls.Range()
+	if vc.ParentNodeType() != parser.N_STMT_EXPORT {
+		return rng, nil
+	}
+	exp := vc.ParentNode().(*parser.ExportDec)
+	er := exp.Range()
+	if er.Lo < rng.Lo {
+		rng.Lo = er.Lo
+	}
+	if er.Hi > rng.Hi {
+		rng.Hi = er.Hi
+	}
+	return rng, exp
+}
+
+var reNonIdChars = regexp.MustCompile(`[^A-Za-z0-9_$]+`)
+
+// the name of the temporary variable, it's unique in the class being lowered
+func (d *decLowering) tmp(name string) string {
+	name = reNonIdChars.ReplaceAllString(name, "_")
+	ret := name
+	for i := 1; d.tmps[ret]; i++ {
+		ret = fmt.Sprintf("%s_%d", name, i)
+	}
+	d.tmps[ret] = true
+	return ret
+}
+
+// the name of the element key used in the generated names, such as `_foo_decorators`
+func (d *decLowering) keyName(e *decElem) string {
+	if !e.computed {
+		switch k := e.key.(type) {
+		case *parser.Ident:
+			return k.Val()
+		case *parser.StrLit:
+			if k.Val() != "" {
+				return k.Val()
+			}
+		case *parser.NumLit:
+			return d.p.NodeText(k)
+		}
+	}
+	return "member"
+}
+
+// the property key of the element in javascript, the computed key is evaluated once by saving it
+// into a temporary variable declared by `decl`
+func (d *decLowering) propKey(e *decElem, decl func(string)) string {
+	if e.computed {
+		name := d.tmp("_" + d.keyName(e) + "_key")
+		decl(name)
+		rng := outerRange(e.key)
+		if d.opts.Version == DV_2023 {
+			d.helpers["__propKey"] = true
+			d.p.Replace(rng, fmt.Sprintf("%s = __propKey(%s)", name, d.p.Text(rng)))
+		} else {
+			d.p.Replace(rng, fmt.Sprintf("%s = %s", name, d.p.Text(rng)))
+		}
+		return name
+	}
+	switch k := e.key.(type) {
+	case *parser.Ident:
+		return strconv.Quote(k.Val())
+	case *parser.StrLit:
+		return d.p.NodeText(k)
+	case *parser.NumLit:
+		v := &tsEnumVal{num: parser.NodeToFloat(k, d.p.Source())}
+		return strconv.Quote(v.String())
+	}
+	return d.p.NodeText(e.key)
+}
+
+// lowers the legacy decorators in the manner of `tsc`:
+//
+//	let A = class A {
+//	  m() {}
+//	};
+//	__decorate([dec], A.prototype, "m", null);
+//	A = __decorate([clsDec], A);
+func (d *decLowering) lowerLegacy(cls *parser.ClassDec, elems []*decElem, ctorFn *parser.FnDec, vc *walk.VisitorCtx) error {
+	if cls.Type() == parser.N_EXPR_CLASS {
+		return d.errorf(cls.Range(), "the legacy decorators are not valid on the class expression")
+	}
+
+	rng, exp := stmtOfClass(cls, vc)
+	name := "default_1"
+	if cls.Id() != nil {
+		name = d.p.NodeText(cls.Id())
+	}
+
+	d.helpers["__decorate"] = true
+	before := make([]string, 0)
+	stmts := make([]string, 0)
+	// the instance elements are decorated before the static ones as `tsc` does
+	ordered := make([]*decElem, 0, len(elems))
+	for _, static := range []bool{false, true} {
+		for _, e := range elems {
+			if e.static == static && (len(e.decs) > 0 || hasParamDecorators(e.fn)) {
+				ordered = append(ordered, e)
+			}
+		}
+	}
+	for _, e := range ordered {
+		list := append(d.decTexts(e.decs), d.paramDecTexts(e.fn)...)
+		if d.opts.EmitMetadata {
+			list = append(list, d.metadata(e)...)
+		}
+		target := name + ".prototype"
+		if e.static {
+			target = name
+		}
+		desc := "null"
+		if e.kind == "field" {
+			desc = "void 0"
+		}
+		key := d.propKey(e, func(n string) { before = append(before, fmt.Sprintf("let %s;", n)) })
+		stmts = append(stmts, fmt.Sprintf("__decorate([%s], %s, %s, %s);", strings.Join(list, ", "), target, key, desc))
+		d.removeDecs(e.decs)
+		d.removeParamDecs(e.fn)
+	}
+
+	clsDecs := parser.DecoratorsOf(cls)
+	if len(clsDecs) > 0 || hasParamDecorators(ctorFn) {
+		list := append(d.decTexts(clsDecs), d.paramDecTexts(ctorFn)...)
+		if d.opts.EmitMetadata && ctorFn != nil {
+			d.helpers["__metadata"] = true
+			list = append(list, fmt.Sprintf("__metadata(\"design:paramtypes\", %s)", d.paramTypes(ctorFn)))
+		}
+		stmts = append(stmts, fmt.Sprintf("%s = __decorate([%s], %s);", name, strings.Join(list, ", "), name))
+	}
+	d.removeParamDecs(ctorFn)
+
+	sep := "\n" + util.IndentOf(d.code, rng.Lo)
+	if len(clsDecs) == 0 && !hasParamDecorators(ctorFn) {
+		if len(before) > 0 {
+			d.p.Insert(rng.Lo, strings.Join(before, sep)+sep)
+		}
+		d.p.Insert(rng.Hi, sep+strings.Join(stmts, sep))
+		return nil
+	}
+
+	// the class is rewritten to the class expression to make its binding be reassigned
+	// by the result of the class decorators
+	body := d.p.Text(span.Range{Lo: d.classKw(cls), Hi: cls.Range().Hi})
+	out := append(before, fmt.Sprintf("let %s = %s;", name, body))
+	out = append(out, stmts...)
+	if exp != nil {
+		if exp.Default() {
+			out = append(out, fmt.Sprintf("export default %s;", name))
+		} else {
+			out = append(out, fmt.Sprintf("export { %s };", name))
+		}
+	}
+	d.p.Replace(rng, strings.Join(out, sep))
+	return nil
+}
+
+func typAnnotOf(node parser.Node) parser.Node {
+	if node == nil {
+		return nil
+	}
+	if ti := parser.TypInfoOf(node); ti != nil && ti.TypAnnot() != nil {
+		return ti.TypAnnot()
+	}
+	if n, ok := node.(*parser.AssignPat); ok {
+		return typAnnotOf(n.Lhs())
+	}
+	return nil
+}
+
+func (d *decLowering) paramTypes(fn *parser.FnDec) string {
+	ts := make([]string, 0)
+	if fn != nil {
+		for _, param := range fn.Params() {
+			ts = append(ts, d.serializeTyp(typAnnotOf(param)))
+		}
+	}
+	return "[" + strings.Join(ts, ", ") + "]"
+}
+
+// the `__metadata` calls of the design-time types
+func (d *decLowering) metadata(e *decElem) []string {
+	d.helpers["__metadata"] = true
+	md := func(key, val string) string {
+		return fmt.Sprintf("__metadata(%q, %s)", key, val)
+	}
+	switch e.kind {
+	case "field", "accessor":
+		return []string{md("design:type", d.serializeTyp(typAnnotOf(e.node)))}
+	case "getter":
+		return []string{md("design:type", d.serializeTyp(typAnnotOf(e.fn))), md("design:paramtypes", "[]")}
+	case "setter":
+		var typ parser.Node
+		if ps := e.fn.Params(); len(ps) > 0 {
+			typ = typAnnotOf(ps[0])
+		}
+		return []string{md("design:type", d.serializeTyp(typ)), md("design:paramtypes", d.paramTypes(e.fn))}
+	}
+
+	ret := "void 0"
+	if annot := typAnnotOf(e.fn); annot != nil {
+		ret = d.serializeTyp(annot)
+	} else if e.fn.Async() {
+		ret = "Promise"
+	}
+	return []string{md("design:type", "Function"), md("design:paramtypes", d.paramTypes(e.fn)), md("design:returntype", ret)}
+}
+
+// the globals which are referenced directly in the serialized types
+var decGlobalTyps = map[string]bool{
+	"Array": true, "Boolean": true, "Date": true, "Error": true, "Function": true, "Map": true, "Number": true,
+	"Object": true, "Promise": true, "RegExp": true, "Set": true, "String": true, "Symbol": true,
+	"WeakMap": true, "WeakSet": true,
+}
+
+// serializes the type annotation to the runtime value in the manner of `emitDecoratorMetadata`
+func (d *decLowering) serializeTyp(node parser.Node) string {
+	if node == nil {
+		return "Object"
+	}
+	switch n := node.(type) {
+	case *parser.TsTypAnnot:
+		return d.serializeTyp(n.TsTyp())
+	case *parser.TsParen:
+		return d.serializeTyp(n.Arg())
+	case *parser.TsLit:
+		switch n.Lit().Type() {
+		case parser.N_LIT_NUM, parser.N_EXPR_UNARY:
+			return "Number"
+		case parser.N_LIT_STR, parser.N_EXPR_TPL:
+			return "String"
+		case parser.N_LIT_BOOL:
+			return "Boolean"
+		case parser.N_LIT_NULL:
+			return "void 0"
+		}
+		return "Object"
+	case *parser.TsRef:
+		return d.serializeRef(n.Name())
+	case *parser.TsUnionTyp:
+		ret := ""
+		for _, elem := range n.Elems() {
+			switch elem.Type() {
+			case parser.N_TS_NULL, parser.N_TS_UNDEF, parser.N_TS_NEVER:
+				continue
+			}
+			t := d.serializeTyp(elem)
+			if ret != "" && ret != t {
+				return "Object"
+			}
+			ret = t
+		}
+		if ret == "" {
+			return "void 0"
+		}
+		return ret
+	}
+
+	switch node.Type() {
+	case parser.N_TS_NUM:
+		return "Number"
+	case parser.N_TS_STR:
+		return "String"
+	case parser.N_TS_BOOL, parser.N_TS_TYP_PREDICATE:
+		return "Boolean"
+	case parser.N_TS_SYM:
+		return "Symbol"
+	case parser.N_TS_BIGINT:
+		return "BigInt"
+	case parser.N_TS_VOID, parser.N_TS_UNDEF, parser.N_TS_NEVER, parser.N_TS_NULL:
+		return "void 0"
+	case parser.N_TS_ARR, parser.N_TS_TUPLE:
+		return "Array"
+	case parser.N_TS_FN_TYP, parser.N_TS_NEW:
+		return "Function"
+	}
+	return "Object"
+}
+
+// the referenced type may not exist at runtime, such as the interfaces, so it's
+// guarded by `typeof` before being used
+func (d *decLowering) serializeRef(name parser.Node) string {
+	if id, ok := name.(*parser.Ident); ok && decGlobalTyps[id.Val()] {
+		return id.Val()
+	}
+
+	conds := make([]string, 0)
+	var path func(node parser.Node) string
+	path = func(node parser.Node) string {
+		if ns, ok := node.(*parser.TsNsName); ok {
+			p := path(ns.Lhs()) + "." + d.p.NodeText(ns.Rhs())
+			conds = append(conds, fmt.Sprintf("typeof %s === \"undefined\"", p))
+			return p
+		}
+		p := d.p.NodeText(node)
+		conds = append(conds, fmt.Sprintf("typeof %s === \"undefined\"", p))
+		return p
+	}
+	ref := path(name)
+	return fmt.Sprintf("%s ? Object : %s", strings.Join(conds, " || "), ref)
+}
+
+// lowers the decorators to the code following the semantics of the stage 3 proposal:
+//
+//	let C = (() => {
+//	  let _classDecorators = [dec];
+//	  let _classDescriptor;
+//	  let _classExtraInitializers = [];
+//	  let _classThis;
+//	  let _m_decorators;
+//	  var C = class {
+//	    static { _classThis = this; }
+//	    static {
+//	      const _metadata = ...;
+//	      _m_decorators = [m];
+//	      __esDecorate(this, null, _m_decorators, { kind: "method", name: "m", ... }, null, _instanceExtraInitializers);
+//	      __esDecorate(null, _classDescriptor = { value: _classThis }, _classDecorators, { kind: "class", ... }, null, _classExtraInitializers);
+//	      C = _classThis = _classDescriptor.value;
+//	    }
+//	    m() {}
+//	    static { __runInitializers(_classThis, _classExtraInitializers); }
+//	  };
+//	  return C = _classThis;
+//	})();
+func (d *decLowering) lower2023(cls *parser.ClassDec, elems []*decElem, ctor *parser.Method, vc *walk.VisitorCtx) error {
+	var ctorFn *parser.FnDec
+	if ctor != nil {
+		ctorFn, _ = ctor.Val().(*parser.FnDec)
+	}
+	if hasParamDecorators(ctorFn) {
+		return d.errorf(ctor.Range(), "the parameter decorators are not supported by the 2023 decorators")
+	}
+	for _, e := range elems {
+		if hasParamDecorators(e.fn) {
+			return d.errorf(e.node.Range(), "the parameter decorators are not supported by the 2023 decorators")
+		}
+	}
+
+	d.helpers["__esDecorate"] = true
+	d.helpers["__runInitializers"] = true
+
+	clsDecs := parser.DecoratorsOf(cls)
+	clsDecorated := len(clsDecs) > 0
+	name := d.className(cls, vc)
+	d.tmps[name] = true
+
+	// the class in the static blocks, the one replaced by the class decorators
+	// is used if there are class decorators
+	self := "this"
+	if clsDecorated {
+		self = "_classThis"
+	}
+
+	decls := make([]string, 0)
+	decl := func(n string) { decls = append(decls, fmt.Sprintf("let %s;", n)) }
+
+	metaProto := "null"
+	if sup := cls.Super(); sup != nil {
+		rng := outerRange(sup)
+		decls = append(decls, fmt.Sprintf("let _classSuper = %s;", d.p.Text(rng)))
+		d.p.Replace(rng, "_classSuper")
+		metaProto = "_classSuper[Symbol.metadata] ?? null"
+	}
+	if clsDecorated {
+		decls = append(decls,
+			fmt.Sprintf("let _classDecorators = [%s];", strings.Join(d.decTexts(clsDecs), ", ")),
+			"let _classDescriptor;",
+			"let _classExtraInitializers = [];",
+			"let _classThis;")
+	}
+
+	decorating := []string{
+		fmt.Sprintf("const _metadata = typeof Symbol === \"function\" && Symbol.metadata ? Object.create(%s) : void 0;", metaProto),
+	}
+	instExtra, staticExtra := false, false
+	fieldInits := map[*decElem][2]string{}
+	accessorKeys := map[*decElem]string{}
+
+	// the static elements are decorated before the instance ones
+	ordered := make([]*decElem, 0, len(elems))
+	for _, static := range []bool{true, false} {
+		for _, e := range elems {
+			if e.static == static && len(e.decs) > 0 {
+				ordered = append(ordered, e)
+			}
+		}
+	}
+	for _, e := range ordered {
+		base := "_"
+		if e.static {
+			base += "static_"
+		}
+		switch e.kind {
+		case "getter":
+			base += "get_"
+		case "setter":
+			base += "set_"
+		}
+		base += d.keyName(e)
+
+		decs := d.tmp(base + "_decorators")
+		decl(decs)
+		decorating = append(decorating, fmt.Sprintf("%s = [%s];", decs, strings.Join(d.decTexts(e.decs), ", ")))
+
+		key := d.propKey(e, decl)
+		ref := "obj[" + key + "]"
+		if id, ok := e.key.(*parser.Ident); ok && !e.computed {
+			ref = "obj." + id.Val()
+		}
+		access := fmt.Sprintf("has: obj => %s in obj", key)
+		switch e.kind {
+		case "method", "getter":
+			access += fmt.Sprintf(", get: obj => %s", ref)
+		case "setter":
+			access += fmt.Sprintf(", set: (obj, value) => { %s = value; }", ref)
+		case "field", "accessor":
+			access += fmt.Sprintf(", get: obj => %s, set: (obj, value) => { %s = value; }", ref, ref)
+		}
+		context := fmt.Sprintf("{ kind: %q, name: %s, static: %v, private: false, access: { %s }, metadata: _metadata }", e.kind, key, e.static, access)
+
+		if e.kind == "field" || e.kind == "accessor" {
+			inits, extras := d.tmp(base+"_initializers"), d.tmp(base+"_extraInitializers")
+			decls = append(decls, fmt.Sprintf("let %s = [];", inits), fmt.Sprintf("let %s = [];", extras))
+			// the accessor pair is defined on the class, so it's decorated like the methods
+			ctor := "null"
+			if e.kind == "accessor" {
+				ctor = "this"
+				accessorKeys[e] = key
+			}
+			decorating = append(decorating, fmt.Sprintf("__esDecorate(%s, null, %s, %s, %s, %s);", ctor, decs, context, inits, extras))
+			fieldInits[e] = [2]string{inits, extras}
+		} else {
+			extras := "_instanceExtraInitializers"
+			if e.static {
+				extras = "_staticExtraInitializers"
+				staticExtra = true
+			} else {
+				instExtra = true
+			}
+			decorating = append(decorating, fmt.Sprintf("__esDecorate(this, null, %s, %s, null, %s);", decs, context, extras))
+		}
+		d.removeDecs(e.decs)
+	}
+	if instExtra {
+		decls = append(decls, "let _instanceExtraInitializers = [];")
+	}
+	if staticExtra {
+		decls = append(decls, "let _staticExtraInitializers = [];")
+	}
+
+	if clsDecorated {
+		decorating = append(decorating,
+			"__esDecorate(null, _classDescriptor = { value: _classThis }, _classDecorators, { kind: \"class\", name: _classThis.name, metadata: _metadata }, null, _classExtraInitializers);",
+			fmt.Sprintf("%s = _classThis = _classDescriptor.value;", name))
+	}
+	decorating = append(decorating, fmt.Sprintf("if (_metadata) Object.defineProperty(%s, Symbol.metadata, { enumerable: true, configurable: true, writable: true, value: _metadata });", self))
+	if staticExtra {
+		decorating = append(decorating, fmt.Sprintf("__runInitializers(%s, _staticExtraInitializers);", self))
+	}
+
+	// the initializers of the decorated fields are wrapped to run the initializers added by the
+	// decorators, the extra initializers of the field run before the initializer of the next field
+	pending := map[bool][]string{}
+	if instExtra {
+		pending[false] = []string{"__runInitializers(this, _instanceExtraInitializers)"}
+	}
+	for _, e := range elems {
+		if e.kind != "field" && e.kind != "accessor" {
+			continue
+		}
+		val := "void 0"
+		if e.val != nil {
+			val = d.p.Text(outerRange(e.val))
+		}
+		fi, decorated := fieldInits[e]
+		if decorated {
+			val = fmt.Sprintf("__runInitializers(this, %s, %s)", fi[0], val)
+		}
+		if ps := pending[e.static]; len(ps) > 0 {
+			val = "(" + strings.Join(ps, ", ") + ", " + val + ")"
+			pending[e.static] = nil
+		} else if !decorated {
+			continue
+		}
+		if decorated {
+			pending[e.static] = append(pending[e.static], fmt.Sprintf("__runInitializers(this, %s)", fi[1]))
+		}
+
+		if key, ok := accessorKeys[e]; ok {
+			d.lowerAccessor(e, key, val)
+			continue
+		}
+		if e.val != nil {
+			d.p.Replace(outerRange(e.val), val)
+		} else {
+			hi := e.node.Range().Hi
+			if d.code[hi-1] == ';' {
+				hi = skipSpacesBackward(d.code, hi-1)
+			}
+			d.p.Insert(hi, " = "+val)
+		}
+	}
+
+	body := cls.Body().Range()
+	ind := util.IndentOf(d.code, d.classKw(cls))
+	nl := " "
+	if elems := cls.Body().(*parser.ClassBody).Elems(); len(elems) > 0 && strings.ContainsAny(d.code[body.Lo:elems[0].Range().Lo], "\r\n") {
+		nl = "\n" + util.IndentOf(d.code, elems[0].Range().Lo)
+	}
+	block := func(stmts []string) string {
+		if nl == " " {
+			return "static { " + strings.Join(stmts, " ") + " }"
+		}
+		in := nl + "  "
+		return "static {" + in + strings.Join(stmts, in) + nl + "}"
+	}
+
+	head := make([]string, 0)
+	if clsDecorated {
+		head = append(head, "static { _classThis = this; }")
+	}
+	head = append(head, block(decorating))
+	d.p.Insert(body.Lo+1, nl+strings.Join(head, nl))
+
+	tail := make([]string, 0)
+	if ps := pending[false]; len(ps) > 0 {
+		stmts := strings.Join(ps, "; ") + ";"
+		if ctorFn != nil {
+			d.runInCtor(cls, ctorFn, stmts)
+		} else if cls.Super() != nil {
+			tail = append(tail, fmt.Sprintf("constructor(...args) { super(...args); %s }", stmts))
+		} else {
+			tail = append(tail, fmt.Sprintf("constructor() { %s }", stmts))
+		}
+	}
+	if ps := pending[true]; len(ps) > 0 {
+		tail = append(tail, fmt.Sprintf("static { %s; }", strings.Join(ps, "; ")))
+	}
+	if clsDecorated {
+		tail = append(tail, "static { __runInitializers(_classThis, _classExtraInitializers); }")
+	}
+	if len(tail) > 0 {
+		d.p.Insert(skipSpacesBackward(d.code, body.Hi-1), nl+strings.Join(tail, nl))
+	}
+
+	// the binding of the class is reassigned by the class decorators, so the class itself
+	// is made anonymous to let the references in its body resolve to the outer binding
+	if clsDecorated && cls.Id() != nil {
+		id := cls.Id().Range()
+		d.p.Remove(span.Range{Lo: id.Lo, Hi: skipSpaces(d.code, id.Hi)})
+	}
+
+	in := "\n" + ind + "  "
+	iife := "(() => {" + in + strings.Join(decls, in)
+	text := d.p.Text(span.Range{Lo: d.classKw(cls), Hi: cls.Range().Hi})
+	if clsDecorated {
+		iife += fmt.Sprintf("%svar %s = %s;%sreturn %s = _classThis;", in, name, text, in, name)
+	} else {
+		iife += fmt.Sprintf("%sreturn %s;", in, text)
+	}
+	iife += "\n" + ind + "})()"
+
+	if cls.Type() == parser.N_EXPR_CLASS {
+		d.p.Replace(outerRange(cls), iife)
+		return nil
+	}
+
+	rng, exp := stmtOfClass(cls, vc)
+	out := fmt.Sprintf("let %s = %s;", name, iife)
+	if exp != nil {
+		if exp.Default() {
+			if cls.Id() != nil {
+				out += fmt.Sprintf("\n%sexport default %s;", ind, name)
+			} else {
+				out = fmt.Sprintf("export default %s;", iife)
+			}
+		} else {
+			out = "export " + out
+		}
+	}
+	d.p.Replace(rng, out)
+	return nil
+}
+
+// replaces the decorated auto-accessor field with a private storage and the getter and setter
+// of it, the getter and setter are the ones passed to the decorators:
+//
+//	#x_accessor_storage = __runInitializers(this, _x_initializers, 1);
+//	get x() { return this.#x_accessor_storage; }
+//	set x(value) { this.#x_accessor_storage = value; }
+//
+// `key` is the property key evaluated by the decorating code, the computed key is saved into
+// it by the getter which comes first
+func (d *decLowering) lowerAccessor(e *decElem, key, val string) {
+	// the range of the field includes its decorators and modifiers, only the part starts from
+	// the `accessor` keyword is replaced
+	rng := e.node.Range()
+	lo := rng.Lo + uint32(strings.LastIndex(d.code[rng.Lo:outerRange(e.key).Lo], "accessor"))
+	hi := rng.Hi
+	if d.code[hi-1] == ';' {
+		hi = skipSpacesBackward(d.code, hi-1)
+	}
+
+	getKey, setKey := d.p.Text(outerRange(e.key)), d.p.Text(outerRange(e.key))
+	if e.computed {
+		getKey, setKey = "["+getKey+"]", "["+key+"]"
+	}
+	storage := "#" + d.tmp(d.keyName(e)+"_accessor_storage")
+	static := ""
+	if e.static {
+		static = "static "
+	}
+	d.p.Replace(span.Range{Lo: lo, Hi: hi}, fmt.Sprintf("%s = %s; %sget %s() { return this.%s; } %sset %s(value) { this.%s = value; }",
+		storage, val, static, getKey, storage, static, setKey, storage))
+}
+
+// the name of the class, it's inferred from the context if the class is anonymous
+func (d *decLowering) className(cls *parser.ClassDec, vc *walk.VisitorCtx) string {
+	if cls.Id() != nil {
+		return d.p.NodeText(cls.Id())
+	}
+	switch vc.ParentNodeType() {
+	case parser.N_VAR_DEC:
+		if id, ok := vc.ParentNode().(*parser.VarDec).Id().(*parser.Ident); ok {
+			return id.Val()
+		}
+	case parser.N_STMT_EXPORT:
+		return "_default"
+	}
+	return "_class"
+}
+
+// runs the initializers at the beginning of the constructor, or right after the `super()`
+// call in the derived class
+func (d *decLowering) runInCtor(cls *parser.ClassDec, fn *parser.FnDec, stmts string) {
+	body, ok := fn.Body().(*parser.BlockStmt)
+	if !ok {
+		return
+	}
+	if cls.Super() != nil {
+		for _, stmt := range body.Body() {
+			es, ok := stmt.(*parser.ExprStmt)
+			if !ok {
+				continue
+			}
+			if call, ok := es.Expr().(*parser.CallExpr); ok && call.Callee().Type() == parser.N_SUPER {
+				d.p.Insert(stmt.Range().Hi, " "+stmts)
+				return
+			}
+		}
+		d.ctx.Warn(fn.Range(), "the initializers of the decorated elements are not run since `super()` is not found at the top level of the constructor")
+		return
+	}
+	d.p.Insert(body.Range().Lo+1, " "+stmts)
+}
+
+var decHelpers = []*helper{
+	{"__decorate", `var __decorate = (this && this.__decorate) || function (decorators, target, key, desc) {
+  var c = arguments.length, r = c < 3 ? target : desc === null ? desc = Object.getOwnPropertyDescriptor(target, key) : desc, d;
+  if (typeof Reflect === "object" && typeof Reflect.decorate === "function") r = Reflect.decorate(decorators, target, key, desc);
+  else for (var i = decorators.length - 1; i >= 0; i--) if (d = decorators[i]) r = (c < 3 ? d(r) : c > 3 ? d(target, key, r) : d(target, key)) || r;
+  return c > 3 && r && Object.defineProperty(target, key, r), r;
+};`},
+	{"__metadata", `var __metadata = (this && this.__metadata) || function (k, v) {
+  if (typeof Reflect === "object" && typeof Reflect.metadata === "function") return Reflect.metadata(k, v);
+};`},
+	{"__param", `var __param = (this && this.__param) || function (index, decorator) {
+  return function (target, key) { decorator(target, key, index); };
+};`},
+	{"__esDecorate", `var __esDecorate = (this && this.__esDecorate) || function (ctor, descriptorIn, decorators, contextIn, initializers, extraInitializers) {
+  function accept(f) {
+    if (f !== void 0 && typeof f !== "function") throw new TypeError("Function expected");
+    return f;
+  }
+  var kind = contextIn.kind, key = kind === "getter" ? "get" : kind === "setter" ? "set" : "value";
+  var target = !descriptorIn && ctor ? (contextIn.static ? ctor : ctor.prototype) : null;
+  var descriptor = descriptorIn || (target ? Object.getOwnPropertyDescriptor(target, contextIn.name) : {});
+  var done = false;
+  for (var i = decorators.length - 1; i >= 0; i--) {
+    var context = {};
+    for (var p in contextIn) context[p] = p === "access" ? {} : contextIn[p];
+    for (var p in contextIn.access) context.access[p] = contextIn.access[p];
+    context.addInitializer = function (f) {
+      if (done) throw new TypeError("Cannot add initializers after decoration has completed");
+      extraInitializers.push(accept(f || null));
+    };
+    var result = (0, decorators[i])(kind === "accessor" ? { get: descriptor.get, set: descriptor.set } : kind === "field" ? void 0 : descriptor[key], context);
+    if (kind === "accessor") {
+      if (result === void 0) continue;
+      if (result === null || typeof result !== "object") throw new TypeError("Object expected");
+      if (accept(result.get)) descriptor.get = result.get;
+      if (accept(result.set)) descriptor.set = result.set;
+      if (accept(result.init)) initializers.unshift(result.init);
+    } else if (kind === "field") {
+      if (accept(result)) initializers.unshift(result);
+    } else if (accept(result)) {
+      descriptor[key] = result;
+    }
+  }
+  if (target) Object.defineProperty(target, contextIn.name, descriptor);
+  done = true;
+};`},
+	{"__runInitializers", `var __runInitializers = (this && this.__runInitializers) || function (thisArg, initializers, value) {
+  var useValue = arguments.length > 2;
+  for (var i = 0; i < initializers.length; i++) {
+    value = useValue ? initializers[i].call(thisArg, value) : initializers[i].call(thisArg);
+  }
+  return useValue ? value : void 0;
+};`},
+	{"__propKey", `var __propKey = (this && this.__propKey) || function (x) {
+  return typeof x === "symbol" ? x : "".concat(x);
+};`},
+}
@@ -0,0 +1,258 @@
+package transform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func lowerDecorators(code string, opts *DecoratorOpts, strip bool) (*Result, error) {
+	popts := parser.NewParserOpts()
+	popts.Feature = popts.Feature.Off(parser.FEAT_JSX).On(parser.FEAT_TS)
+	ts := []Transformer{NewDecorators(opts)}
+	if strip {
+		ts = append(ts, NewTsStrip(nil))
+	}
+	return Run("a.ts", code, popts, ts...)
+}
+
+// the output of the lowered code without the helpers
+func withoutHelpers(code string) string {
+	lines := strings.Split(code, "\n")
+	ret := make([]string, 0, len(lines))
+	skip := false
+	for _, line := range lines {
+		if strings.HasPrefix(line, "var __") {
+			skip = true
+		}
+		if !skip {
+			ret = append(ret, line)
+		}
+		if skip && strings.HasPrefix(line, "};") {
+			skip = false
+		}
+	}
+	return strings.Join(ret, "\n")
+}
+
+// runs the code by node and returns its stdout, the test is skipped if node is unavailable
+func runNode(t *testing.T, code string) string {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not found")
+	}
+	file := filepath.Join(t.TempDir(), "main.mjs")
+	if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(node, file).CombinedOutput()
+	if err != nil {
+		t.Fatalf("failed to run the code: %s\n%s", err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestDecoratorLegacy(t *testing.T) {
+	ret, err := lowerDecorators(`"use strict";
+@sealed
+export class A {
+  @readonly name = "a";
+  @log greet(@inject("x") a) {}
+  @log static create() {}
+  constructor(@inject("y") b) {}
+}
+class B {
+  @log get x() { return 1; }
+}`, nil, false)
+	AssertEqual(t, nil, err, "should be ok")
+
+	AssertEqual(t, true, strings.HasPrefix(ret.Code, "\"use strict\";\nvar __decorate = "), "should be ok")
+	AssertEqualString(t, `"use strict";
+let A = class A {
+  name = "a";
+  greet(a) {}
+  static create() {}
+  constructor(b) {}
+};
+__decorate([readonly], A.prototype, "name", void 0);
+__decorate([log, __param(0, inject("x"))], A.prototype, "greet", null);
+__decorate([log], A, "create", null);
+A = __decorate([sealed, __param(0, inject("y"))], A);
+export { A };
+class B {
+  get x() { return 1; }
+}
+__decorate([log], B.prototype, "x", null);`, withoutHelpers(ret.Code), "should be ok")
+}
+
+func TestDecoratorLegacyDefault(t *testing.T) {
+	ret, err := lowerDecorators(`export default @dec class {}`, nil, false)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `let default_1 = class {};
+default_1 = __decorate([dec], default_1);
+export default default_1;`, withoutHelpers(ret.Code), "should be ok")
+}
+
+func TestDecoratorLegacyClassExpr(t *testing.T) {
+	_, err := lowerDecorators(`let A = @dec class {}`, nil, false)
+	AssertEqual(t, "the legacy decorators are not valid on the class expression at (1:8)", err.Error(), "should be ok")
+}
+
+func TestDecoratorLegacyMetadata(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.EmitMetadata = true
+	ret, err := lowerDecorators(`class A {
+  @f a: number | null;
+  @f b: "x" | "y";
+  @f c: I[];
+  @f d: ns.T;
+  @f set s(v: boolean) {}
+  @f async m(a: string, b): Promise<void> {}
+  constructor(@f x: Foo) {}
+}`, opts, false)
+	AssertEqual(t, nil, err, "should be ok")
+
+	AssertEqualString(t, `let A = class A {
+  a: number | null;
+  b: "x" | "y";
+  c: I[];
+  d: ns.T;
+  set s(v: boolean) {}
+  async m(a: string, b): Promise<void> {}
+  constructor(x: Foo) {}
+};
+__decorate([f, __metadata("design:type", Number)], A.prototype, "a", void 0);
+__decorate([f, __metadata("design:type", String)], A.prototype, "b", void 0);
+__decorate([f, __metadata("design:type", Array)], A.prototype, "c", void 0);
+__decorate([f, __metadata("design:type", typeof ns === "undefined" || typeof ns.T === "undefined" ? Object : ns.T)], A.prototype, "d", void 0);
+__decorate([f, __metadata("design:type", Boolean), __metadata("design:paramtypes", [Boolean])], A.prototype, "s", null);
+__decorate([f, __metadata("design:type", Function), __metadata("design:paramtypes", [String, Object]), __metadata("design:returntype", Promise)], A.prototype, "m", null);
+A = __decorate([__param(0, f), __metadata("design:paramtypes", [typeof Foo === "undefined" ? Object : Foo])], A);`, withoutHelpers(ret.Code), "should be ok")
+}
+
+func TestDecoratorLegacyExec(t *testing.T) {
+	ret, err := lowerDecorators(`const log = [];
+function m(t, k, d) { log.push("m:" + k); return { ...d, value: () => "decorated" }; }
+function f(t, k) { log.push("f:" + String(k)); }
+function c(C) { log.push("c:" + C.name); return class extends C { x = 42 }; }
+function p(t, k, i) { log.push("p:" + String(k) + i); }
+@c
+class A {
+  @f prop: string = "a";
+  @m foo(@p a: number) { return "foo"; }
+  @m static bar() {}
+  @f ["comp" + 1] = 2;
+  constructor(@p q: string) {}
+}
+console.log(JSON.stringify(log), new A().x, new A().foo());`, nil, true)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `["f:prop","p:foo0","m:foo","f:comp1","m:bar","p:undefined0","c:A"] 42 decorated`, runNode(t, ret.Code), "should be ok")
+}
+
+func TestDecorator2023(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.Version = DV_2023
+	ret, err := lowerDecorators(`class A { @dec m() {} }`, opts, false)
+	AssertEqual(t, nil, err, "should be ok")
+
+	AssertEqualString(t, `let A = (() => {
+  let _m_decorators;
+  let _instanceExtraInitializers = [];
+  return class A { static { const _metadata = typeof Symbol === "function" && Symbol.metadata ? Object.create(null) : void 0; _m_decorators = [dec]; __esDecorate(this, null, _m_decorators, { kind: "method", name: "m", static: false, private: false, access: { has: obj => "m" in obj, get: obj => obj.m }, metadata: _metadata }, null, _instanceExtraInitializers); if (_metadata) Object.defineProperty(this, Symbol.metadata, { enumerable: true, configurable: true, writable: true, value: _metadata }); } m() {} constructor() { __runInitializers(this, _instanceExtraInitializers); } };
+})();`, withoutHelpers(ret.Code), "should be ok")
+}
+
+func TestDecorator2023ParamDecorator(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.Version = DV_2023
+	_, err := lowerDecorators(`class A { m(@dec a) {} }`, opts, false)
+	AssertEqual(t, "the parameter decorators are not supported by the 2023 decorators at (1:10)", err.Error(), "should be ok")
+}
+
+func TestDecorator2023Accessor(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.Version = DV_2023
+	ret, err := lowerDecorators(`class A { @acc accessor g = 2; }`, opts, false)
+	AssertEqual(t, nil, err, "should be ok")
+
+	AssertEqualString(t, `let A = (() => {
+  let _g_decorators;
+  let _g_initializers = [];
+  let _g_extraInitializers = [];
+  return class A { static { const _metadata = typeof Symbol === "function" && Symbol.metadata ? Object.create(null) : void 0; _g_decorators = [acc]; __esDecorate(this, null, _g_decorators, { kind: "accessor", name: "g", static: false, private: false, access: { has: obj => "g" in obj, get: obj => obj.g, set: (obj, value) => { obj.g = value; } }, metadata: _metadata }, _g_initializers, _g_extraInitializers); if (_metadata) Object.defineProperty(this, Symbol.metadata, { enumerable: true, configurable: true, writable: true, value: _metadata }); } #g_accessor_storage = __runInitializers(this, _g_initializers, 2); get g() { return this.#g_accessor_storage; } set g(value) { this.#g_accessor_storage = value; }; constructor() { __runInitializers(this, _g_extraInitializers); } };
+})();`, withoutHelpers(ret.Code), "should be ok")
+}
+
+func TestDecorator2023AccessorExec(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.Version = DV_2023
+	ret, err := lowerDecorators(`Symbol.metadata ??= Symbol("Symbol.metadata");
+const log: string[] = [];
+function acc(value: any, ctx: any) {
+  log.push(ctx.kind + ":" + String(ctx.name) + ":" + ctx.static + ":" + typeof value.get + ":" + typeof value.set);
+  ctx.addInitializer(function () { log.push("init:" + String(ctx.name)); });
+  return {
+    get() { return value.get.call(this) * 2; },
+    set(v: number) { value.set.call(this, v + 1); },
+    init(v: number) { return v * 10; },
+  };
+}
+class A {
+  @acc accessor g = 2;
+  @acc static accessor ["s" + 1] = 3;
+}
+const a = new A();
+log.push(a.g, A.s1);
+a.g = 5;
+log.push(a.g, Object.keys(a).length);
+console.log(log.join(" "));`, opts, true)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, "accessor:s1:true:function:function accessor:g:false:function:function init:s1 init:g 40 60 12 0",
+		runNode(t, ret.Code), "should be ok")
+}
+
+func TestDecorator2023Exec(t *testing.T) {
+	opts := NewDecoratorOpts()
+	opts.Version = DV_2023
+	ret, err := lowerDecorators(`Symbol.metadata ??= Symbol("Symbol.metadata");
+const log: string[] = [];
+function logged(value: any, ctx: any) {
+  log.push(ctx.kind + ":" + String(ctx.name) + ":" + ctx.static);
+  ctx.addInitializer(function () { log.push("init:" + String(ctx.name)); });
+  if (ctx.kind === "method") return function (...args: any[]) { log.push("call:" + ctx.name); return value.call(this, ...args); };
+  if (ctx.kind === "field") return (v: number) => v * 10;
+  if (ctx.kind === "getter") return function () { return value.call(this) + 1; };
+}
+function cls(value: any, ctx: any) {
+  log.push("class:" + ctx.name);
+  ctx.metadata.tag = "meta";
+  ctx.addInitializer(function () { log.push("classinit"); });
+  return class extends value { extra = true; };
+}
+class Base { b = 1; }
+@cls
+export class A extends Base {
+  @logged x = 1;
+  @logged y;
+  z = 3;
+  @logged foo() { return this.x; }
+  @logged static s = 2;
+  @logged get g() { return 5; }
+  @logged ["k" + 1]() { return "k"; }
+  static make() { return new A(); }
+}
+const a = A.make();
+log.push(a.x, a.y, a.z, a.foo(), A.s, a.g, a.k1(), a.extra, a instanceof Base, A[Symbol.metadata].tag);
+const E = @cls class { @logged m() { return 1; } };
+log.push(new E().m());
+console.log(log.join(" "));`, opts, true)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, "field:s:true field:x:false field:y:false method:foo:false getter:g:false method:k1:false class:A "+
+		"init:s classinit init:foo init:g init:k1 init:x init:y call:foo call:k1 10 NaN 3 10 20 6 k true true meta "+
+		"method:m:false class:E classinit init:m call:m 1", runNode(t, ret.Code), "should be ok")
+}
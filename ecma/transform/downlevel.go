@@ -0,0 +1,596 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// the syntax lowerings, each of them rewrites a syntax to its equivalent in the older
+// versions of ecmascript
+type Lowering uint64
+
+const (
+	LW_NONE Lowering = 0
+
+	LW_TPL           Lowering = 1 << iota // template literals, from es6
+	LW_DESTRUCTURING                      // from es6
+	LW_ARROW                              // arrow functions, from es6
+
+	LW_POW // exponentiation operator, from es7
+
+	LW_ASYNC // async functions and `await`, from es8
+
+	LW_OBJ_REST_SPREAD // object rest and spread properties, from es9
+	LW_ASYNC_GENERATOR // async generators and `for await`, from es9
+
+	LW_OPT_CHAIN // optional chaining, from es11
+	LW_NULLISH   // nullish coalescing operator, from es11
+
+	LW_LOGIC_ASSIGN // logical assignment operators, from es12
+	LW_NUM_SEP      // numeric separators, from es12
+
+	LW_CLASS_FIELD   // public instance and static fields, from es13
+	LW_CLASS_PRIV    // private fields, methods, accessors and `#x in obj`, from es13
+	LW_STATIC_BLOCK  // class static blocks, from es13
+	LW_ALL_LOWERINGS = LW_STATIC_BLOCK<<1 - LW_TPL
+)
+
+func (l Lowering) On(flag Lowering) Lowering {
+	return l | flag
+}
+
+func (l Lowering) Off(flag Lowering) Lowering {
+	return l & ^flag
+}
+
+func (l Lowering) Turn(flag Lowering, on bool) Lowering {
+	if on {
+		return l.On(flag)
+	}
+	return l.Off(flag)
+}
+
+func (l Lowering) Has(flag Lowering) bool {
+	return l&flag != 0
+}
+
+type DownlevelOpts struct {
+	// the version of ecmascript the output is expected to run on
+	Target parser.ESVersion
+
+	// the lowerings to apply, it's derived from `Target` by `NewDownlevelOpts` and can be
+	// adjusted afterwards to turn on or off the specific lowerings
+	Lowerings Lowering
+}
+
+func NewDownlevelOpts(target parser.ESVersion) *DownlevelOpts {
+	return &DownlevelOpts{Target: target, Lowerings: LoweringsOf(target)}
+}
+
+// the pass of a lowering, the passes are run in the order of `downlevelPasses` and each of
+// them works on the output of its predecessor, so a pass can leave the syntax it produces to
+// the subsequent passes, for example the logical assignment `a ??= b` is rewritten to
+// `a ?? (a = b)` and the remaining `??` is lowered by the pass of the nullish coalescing
+type downlevelPass struct {
+	lw   Lowering
+	ver  parser.ESVersion // the version which introduces the syntax
+	name string
+	run  func(l *lowering)
+}
+
+var downlevelPasses = []*downlevelPass{
+	{LW_LOGIC_ASSIGN, parser.ES12, "logical-assignment", lowerLogicAssign},
+	{LW_NUM_SEP, parser.ES12, "numeric-separator", lowerNumSep},
+	{LW_OPT_CHAIN, parser.ES11, "optional-chaining", lowerOptChain},
+	{LW_NULLISH, parser.ES11, "nullish-coalescing", lowerNullish},
+	{LW_CLASS_FIELD | LW_CLASS_PRIV | LW_STATIC_BLOCK, parser.ES13, "class-fields", lowerClassFields},
+	{LW_OBJ_REST_SPREAD, parser.ES9, "object-rest-spread", lowerObjRestSpread},
+	{LW_ASYNC_GENERATOR, parser.ES9, "async-generator", lowerAsyncGenerator},
+	{LW_ASYNC, parser.ES8, "async", lowerAsync},
+	{LW_POW, parser.ES7, "exponentiation", lowerPow},
+	{LW_TPL, parser.ES6, "template-literal", lowerTpl},
+	{LW_DESTRUCTURING, parser.ES6, "destructuring", lowerDestructuring},
+	{LW_ARROW, parser.ES6, "arrow-function", lowerArrow},
+}
+
+// the lowerings required to run the output on the `target` version of ecmascript
+func LoweringsOf(target parser.ESVersion) Lowering {
+	lws := LW_NONE
+	for _, pass := range downlevelPasses {
+		if target < pass.ver {
+			lws = lws.On(pass.lw)
+		}
+	}
+	return lws
+}
+
+// returns the transformers to lower the syntaxes specified in `opts`, they are expected to run
+// on plain javascript so they should be placed after the transformers like `TsStrip` which
+// produce javascript
+//
+// the lowerings are limited to the syntaxes listed in `Lowering`, the others introduced by es6
+// such as classes, generators and block scoped declarations are kept as they are, so the async
+// functions are lowered to generators rather than the state machines
+func Downlevel(opts *DownlevelOpts) []Transformer {
+	if opts == nil {
+		opts = NewDownlevelOpts(parser.ES5)
+	}
+	ts := make([]Transformer, 0)
+	for _, pass := range downlevelPasses {
+		if opts.Lowerings.Has(pass.lw) {
+			ts = append(ts, &downlevel{pass, opts.Lowerings & pass.lw})
+		}
+	}
+	return ts
+}
+
+type downlevel struct {
+	pass *downlevelPass
+	lws  Lowering // the lowerings of the pass which are turned on
+}
+
+func (t *downlevel) Name() string {
+	return "downlevel-" + t.pass.name
+}
+
+func (t *downlevel) ParserOpts(opts *parser.ParserOpts) {}
+
+func (t *downlevel) Transform(ctx *Ctx) error {
+	l := newLowering(ctx, t.lws)
+	t.pass.run(l)
+	walk.VisitNode(ctx.Ast, "", l.wc.VisitorCtx())
+	if l.err != nil {
+		return l.err
+	}
+	l.flush(ctx.Ast)
+	injectHelpers(ctx, dlHelpers, l.helpers)
+	return nil
+}
+
+// the shared states and utilities of the lowering passes
+type lowering struct {
+	ctx  *Ctx
+	p    *Printer
+	code string
+	lws  Lowering
+	wc   *walk.WalkCtx
+	err  error
+
+	names     map[string]bool          // the names appear in the source
+	tmps      map[parser.Node][]string // the temporary variables declared in the functions
+	prologues map[parser.Node][]string
+	helpers   map[string]bool
+	seq       int
+
+	// the handlers run when the functions are left, before the temporary variables are declared
+	exits []func(fn parser.Node, vc *walk.VisitorCtx)
+}
+
+var reName = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+func newLowering(ctx *Ctx, lws Lowering) *lowering {
+	code := ctx.Printer.Source().Text(0, uint32(ctx.Printer.Source().Len()))
+	l := &lowering{
+		ctx:       ctx,
+		p:         ctx.Printer,
+		code:      code,
+		lws:       lws,
+		wc:        walk.NewWalkCtx(ctx.Ast, ctx.Parser.Symtab()),
+		names:     map[string]bool{},
+		tmps:      map[parser.Node][]string{},
+		prologues: map[parser.Node][]string{},
+		helpers:   map[string]bool{},
+	}
+	// the names are collected from the raw source instead of the identifiers in the AST to
+	// avoid the conflicts with the names in the comments and the strings, which is harmless
+	// and keeps the generated names stable
+	for _, name := range reName.FindAllString(code, -1) {
+		l.names[name] = true
+	}
+
+	// the temporary variables are declared once the function is left, it should be done before
+	// the function is rewritten by the listeners of the passes which are registered later
+	for _, t := range []parser.NodeType{parser.N_STMT_FN, parser.N_EXPR_FN, parser.N_EXPR_ARROW} {
+		l.on(t, func(node parser.Node, vc *walk.VisitorCtx) {
+			for _, exit := range l.exits {
+				exit(node, vc)
+			}
+			l.flush(node)
+		})
+	}
+	return l
+}
+
+func (l *lowering) on(t parser.NodeType, handle func(node parser.Node, vc *walk.VisitorCtx)) {
+	l.seq += 1
+	walk.AddNodeAfterListener(&l.wc.Listeners, t, &walk.Listener{
+		Id: fmt.Sprintf("downlevel-%d", l.seq),
+		Handle: func(node parser.Node, key string, vc *walk.VisitorCtx) {
+			if l.err == nil {
+				handle(node, vc)
+			}
+		},
+	})
+}
+
+func (l *lowering) errorf(rng span.Range, format string, args ...interface{}) {
+	if l.err == nil {
+		l.err = &Warning{l.p.Source(), rng, fmt.Sprintf(format, args...)}
+	}
+}
+
+func (l *lowering) use(helpers ...string) {
+	for _, h := range helpers {
+		l.helpers[h] = true
+	}
+}
+
+// returns a name based on `base` which is unique in the source
+func (l *lowering) name(base string) string {
+	name := base
+	for i := 1; l.names[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	l.names[name] = true
+	return name
+}
+
+// returns a name in sequence `_a`, `_b`, ..., `_z`, `_a1`, ... which is unique in the source
+func (l *lowering) seqName() string {
+	for i := 0; ; i++ {
+		name := "_" + string(rune('a'+i%26))
+		if i >= 26 {
+			name += fmt.Sprint(i / 26)
+		}
+		if !l.names[name] {
+			l.names[name] = true
+			return name
+		}
+	}
+}
+
+func isFn(node parser.Node) bool {
+	switch node.Type() {
+	case parser.N_STMT_FN, parser.N_EXPR_FN, parser.N_EXPR_ARROW:
+		return true
+	}
+	return false
+}
+
+func fnBody(node parser.Node) parser.Node {
+	switch n := node.(type) {
+	case *parser.FnDec:
+		return n.Body()
+	case *parser.ArrowFn:
+		return n.Body()
+	}
+	return nil
+}
+
+// the nearest function or program which contains the node of `vc` in its body, the functions
+// whose parameters contain the node are skipped since the declarations in the function body are
+// invisible to the parameters
+func varScopeOf(vc *walk.VisitorCtx) parser.Node {
+	child := vc.Node
+	for c := vc.Parent; c != nil; c = c.Parent {
+		if c.Node.Type() == parser.N_PROG {
+			return c.Node
+		}
+		if isFn(c.Node) && fnBody(c.Node) == child {
+			return c.Node
+		}
+		child = c.Node
+	}
+	return nil
+}
+
+// the nearest function which is not an arrow function, or the program
+func thisScopeOf(vc *walk.VisitorCtx) parser.Node {
+	for c := vc.Parent; c != nil; c = c.Parent {
+		switch c.Node.Type() {
+		case parser.N_PROG, parser.N_STMT_FN, parser.N_EXPR_FN:
+			return c.Node
+		}
+	}
+	return nil
+}
+
+// the nearest function of the node, the node itself is excluded
+func fnOf(vc *walk.VisitorCtx) parser.Node {
+	for c := vc.Parent; c != nil; c = c.Parent {
+		if isFn(c.Node) {
+			return c.Node
+		}
+	}
+	return nil
+}
+
+// declares a temporary variable in the nearest function of the node of `vc`
+func (l *lowering) tmp(vc *walk.VisitorCtx) string {
+	return l.declare(varScopeOf(vc), l.seqName())
+}
+
+// declares the variable in the function or the program, `name` can be followed by its initializer
+func (l *lowering) declare(scope parser.Node, name string) string {
+	l.tmps[scope] = append(l.tmps[scope], name)
+	return name
+}
+
+// the statements to run at the beginning of the function body
+func (l *lowering) prologue(fn parser.Node, stmts string) {
+	l.prologues[fn] = append(l.prologues[fn], stmts)
+}
+
+// inserts the declarations of the temporary variables and the prologue statements into the
+// function or the program
+func (l *lowering) flush(scope parser.Node) {
+	names := l.tmps[scope]
+	stmts := l.prologues[scope]
+	if len(names) == 0 && len(stmts) == 0 {
+		return
+	}
+	delete(l.tmps, scope)
+	delete(l.prologues, scope)
+	if len(names) > 0 {
+		stmts = append([]string{"var " + strings.Join(names, ", ") + ";"}, stmts...)
+	}
+	dec := strings.Join(stmts, " ")
+
+	if prog, ok := scope.(*parser.Prog); ok {
+		if ofst := directivesEnd(prog.Body()); ofst > 0 {
+			l.p.Insert(ofst, "\n"+dec)
+		} else {
+			l.p.Insert(0, dec+"\n")
+		}
+		return
+	}
+
+	body := fnBody(scope)
+	if body.Type() != parser.N_STMT_BLOCK {
+		l.p.Replace(outerRange(body), fmt.Sprintf("{ %s return %s; }", dec, l.p.Text(outerRange(body))))
+		return
+	}
+	l.prepend(body.(*parser.BlockStmt), dec)
+}
+
+// inserts the statements at the beginning of the block, after the directives if any
+func (l *lowering) prepend(block *parser.BlockStmt, stmts string) {
+	ofst := block.Range().Lo + 1
+	if end := directivesEnd(block.Body()); end > 0 {
+		ofst = end
+	}
+	if body := block.Body(); len(body) > 0 && strings.ContainsAny(l.code[block.Range().Lo:body[0].Range().Lo], "\r\n") {
+		l.p.Insert(ofst, "\n"+util.IndentOf(l.code, body[0].Range().Lo)+stmts)
+		return
+	}
+	l.p.Insert(ofst, " "+stmts)
+}
+
+// the source text of the node with the edits applied, the parentheses around the node are included
+func (l *lowering) text(node parser.Node) string {
+	return l.p.Text(outerRange(node))
+}
+
+// whether the node can be evaluated more than once without the side effects
+func isSimple(node parser.Node) bool {
+	switch node.Type() {
+	case parser.N_NAME, parser.N_EXPR_THIS, parser.N_LIT_NULL, parser.N_LIT_BOOL, parser.N_LIT_NUM, parser.N_LIT_STR:
+		return true
+	}
+	return false
+}
+
+// the precedences of the generated expressions, they are used to decide whether the
+// parentheses are required when they are used to replace the nodes
+const (
+	precSeq    = iota // the comma operator
+	precAssign        // the assignment and the conditional operator
+	precUnary         // the unary operators
+)
+
+func hasParen(node parser.Node) bool {
+	n, ok := node.(parser.InParenNode)
+	return ok && !n.OuterParen().Empty()
+}
+
+func needParen(prec int, node, parent parser.Node) bool {
+	if hasParen(node) {
+		return false
+	}
+	switch n := parent.(type) {
+	case nil, *parser.ParenExpr, *parser.ExprStmt, *parser.RetStmt, *parser.ThrowStmt, *parser.IfStmt,
+		*parser.WhileStmt, *parser.DoWhileStmt, *parser.SwitchStmt, *parser.SeqExpr:
+		return false
+	case *parser.ForStmt:
+		return n.Init() == node && prec == precSeq
+	case *parser.TplExpr:
+		return n.Tag() == node
+	case *parser.MemberExpr:
+		return n.Obj() == node
+	case *parser.CallExpr:
+		return n.Callee() == node
+	case *parser.NewExpr:
+		return n.Callee() == node || prec == precSeq
+	case *parser.UnaryExpr, *parser.UpdateExpr:
+		return prec < precUnary
+	case *parser.BinExpr:
+		return true
+	case *parser.CondExpr:
+		return prec == precSeq || n.Test() == node
+	}
+	return prec == precSeq
+}
+
+// wraps the text in the parentheses if it's required to replace the node of `vc`
+func (l *lowering) paren(text string, prec int, vc *walk.VisitorCtx) string {
+	if needParen(prec, vc.Node, vc.ParentNode()) {
+		return "(" + text + ")"
+	}
+	return text
+}
+
+// replaces the node of `vc` with the expression in `text` whose precedence is `prec`
+func (l *lowering) replace(vc *walk.VisitorCtx, text string, prec int) {
+	l.p.Replace(vc.Node.Range(), l.paren(text, prec, vc))
+}
+
+// quotes the string as a javascript string literal
+func jsQuote(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\u2028', '\u2029':
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\x%02x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// returns the texts to reference the assignment target `node` twice, the first one evaluates the
+// object and the computed key of the member expression into the temporary variables and the
+// second one reuses them, so they are evaluated only once
+func (l *lowering) reuse(node parser.Node, vc *walk.VisitorCtx) (string, string) {
+	n, ok := node.(*parser.MemberExpr)
+	if !ok {
+		s := l.text(node)
+		return s, s
+	}
+
+	obj, objRef := l.text(n.Obj()), l.text(n.Obj())
+	if !isSimple(n.Obj()) && n.Obj().Type() != parser.N_SUPER {
+		objRef = l.tmp(vc)
+		obj = fmt.Sprintf("(%s = %s)", objRef, obj)
+	}
+	if !n.Compute() {
+		prop := "." + l.text(n.Prop())
+		return obj + prop, objRef + prop
+	}
+
+	prop, propRef := l.text(n.Prop()), l.text(n.Prop())
+	switch n.Prop().Type() {
+	case parser.N_LIT_NUM, parser.N_LIT_STR:
+	default:
+		propRef = l.tmp(vc)
+		prop = fmt.Sprintf("%s = %s", propRef, prop)
+	}
+	return fmt.Sprintf("%s[%s]", obj, prop), fmt.Sprintf("%s[%s]", objRef, propRef)
+}
+
+// the helpers are in the order of their dependencies, most of them are the same as the ones
+// in `tslib` to produce the output familiar to the users
+var dlHelpers = []*helper{
+	{"__assign", `var __assign = (this && this.__assign) || function () {
+  __assign = Object.assign || function (t) {
+    for (var s, i = 1, n = arguments.length; i < n; i++) {
+      s = arguments[i];
+      for (var p in s) if (Object.prototype.hasOwnProperty.call(s, p)) t[p] = s[p];
+    }
+    return t;
+  };
+  return __assign.apply(this, arguments);
+};`},
+	{"__rest", `var __rest = (this && this.__rest) || function (s, e) {
+  var t = {};
+  for (var p in s) if (Object.prototype.hasOwnProperty.call(s, p) && e.indexOf(p) < 0) t[p] = s[p];
+  if (s != null && typeof Object.getOwnPropertySymbols === "function")
+    for (var i = 0, p = Object.getOwnPropertySymbols(s); i < p.length; i++) {
+      if (e.indexOf(p[i]) < 0 && Object.prototype.propertyIsEnumerable.call(s, p[i])) t[p[i]] = s[p[i]];
+    }
+  return t;
+};`},
+	{"__read", `var __read = (this && this.__read) || function (o, n) {
+  var m = typeof Symbol === "function" && o[Symbol.iterator];
+  if (!m) return o;
+  var i = m.call(o), r, ar = [], e;
+  try {
+    while ((n === void 0 || n-- > 0) && !(r = i.next()).done) ar.push(r.value);
+  } catch (error) {
+    e = { error: error };
+  } finally {
+    try {
+      if (r && !r.done && (m = i["return"])) m.call(i);
+    } finally {
+      if (e) throw e.error;
+    }
+  }
+  return ar;
+};`},
+	{"__makeTemplateObject", `var __makeTemplateObject = (this && this.__makeTemplateObject) || function (cooked, raw) {
+  if (Object.defineProperty) { Object.defineProperty(cooked, "raw", { value: raw }); } else { cooked.raw = raw; }
+  return cooked;
+};`},
+	{"__awaiter", `var __awaiter = (this && this.__awaiter) || function (thisArg, _arguments, P, generator) {
+  function adopt(value) { return value instanceof P ? value : new P(function (resolve) { resolve(value); }); }
+  return new (P || (P = Promise))(function (resolve, reject) {
+    function fulfilled(value) { try { step(generator.next(value)); } catch (e) { reject(e); } }
+    function rejected(value) { try { step(generator["throw"](value)); } catch (e) { reject(e); } }
+    function step(result) { result.done ? resolve(result.value) : adopt(result.value).then(fulfilled, rejected); }
+    step((generator = generator.apply(thisArg, _arguments || [])).next());
+  });
+};`},
+	{"__await", `var __await = (this && this.__await) || function (v) {
+  return this instanceof __await ? (this.v = v, this) : new __await(v);
+};`},
+	{"__asyncGenerator", `var __asyncGenerator = (this && this.__asyncGenerator) || function (thisArg, _arguments, generator) {
+  if (!Symbol.asyncIterator) throw new TypeError("Symbol.asyncIterator is not defined.");
+  var g = generator.apply(thisArg, _arguments || []), i, q = [];
+  return i = {}, verb("next"), verb("throw"), verb("return"), i[Symbol.asyncIterator] = function () { return this; }, i;
+  function verb(n) { if (g[n]) i[n] = function (v) { return new Promise(function (a, b) { q.push([n, v, a, b]) > 1 || resume(n, v); }); }; }
+  function resume(n, v) { try { step(g[n](v)); } catch (e) { settle(q[0][3], e); } }
+  function step(r) { r.value instanceof __await ? Promise.resolve(r.value.v).then(fulfill, reject) : settle(q[0][2], r); }
+  function fulfill(value) { resume("next", value); }
+  function reject(value) { resume("throw", value); }
+  function settle(f, v) { if (f(v), q.shift(), q.length) resume(q[0][0], q[0][1]); }
+};`},
+	{"__asyncDelegator", `var __asyncDelegator = (this && this.__asyncDelegator) || function (o) {
+  var i, p;
+  return i = {}, verb("next"), verb("throw", function (e) { throw e; }), verb("return"), i[Symbol.iterator] = function () { return this; }, i;
+  function verb(n, f) { i[n] = o[n] ? function (v) { return (p = !p) ? { value: __await(o[n](v)), done: false } : f ? f(v) : v; } : f; }
+};`},
+	{"__asyncValues", `var __asyncValues = (this && this.__asyncValues) || function (o) {
+  if (!Symbol.asyncIterator) throw new TypeError("Symbol.asyncIterator is not defined.");
+  var m = o[Symbol.asyncIterator], i;
+  return m ? m.call(o) : (o = o[Symbol.iterator](), i = {}, verb("next"), verb("throw"), verb("return"), i[Symbol.asyncIterator] = function () { return this; }, i);
+  function verb(n) { i[n] = o[n] && function (v) { return new Promise(function (resolve, reject) { v = o[n](v), settle(resolve, reject, v.done, v.value); }); }; }
+  function settle(resolve, reject, d, v) { Promise.resolve(v).then(function (v) { resolve({ value: v, done: d }); }, reject); }
+};`},
+	{"__classPrivateFieldGet", `var __classPrivateFieldGet = (this && this.__classPrivateFieldGet) || function (receiver, state, kind, f) {
+  if (kind === "a" && !f) throw new TypeError("Private accessor was defined without a getter");
+  if (typeof state === "function" ? receiver !== state || !f : !state.has(receiver)) throw new TypeError("Cannot read private member from an object whose class did not declare it");
+  return kind === "m" ? f : kind === "a" ? f.call(receiver) : f ? f.value : state.get(receiver);
+};`},
+	{"__classPrivateFieldSet", `var __classPrivateFieldSet = (this && this.__classPrivateFieldSet) || function (receiver, state, value, kind, f) {
+  if (kind === "m") throw new TypeError("Private method is not writable");
+  if (kind === "a" && !f) throw new TypeError("Private accessor was defined without a setter");
+  if (typeof state === "function" ? receiver !== state || !f : !state.has(receiver)) throw new TypeError("Cannot write private member to an object whose class did not declare it");
+  return (kind === "a" ? f.call(receiver, value) : f ? f.value = value : state.set(receiver, value)), value;
+};`},
+	{"__classPrivateFieldIn", `var __classPrivateFieldIn = (this && this.__classPrivateFieldIn) || function (state, receiver) {
+  if (receiver === null || (typeof receiver !== "object" && typeof receiver !== "function")) throw new TypeError("Cannot use 'in' operator on non-object");
+  return typeof state === "function" ? receiver === state : state.has(receiver);
+};`},
+}
@@ -0,0 +1,260 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func lowerCode(code string, lws Lowering) (*Result, error) {
+	popts := parser.NewParserOpts()
+	popts.Feature = popts.Feature.Off(parser.FEAT_JSX)
+	opts := NewDownlevelOpts(parser.ES5)
+	opts.Lowerings = lws
+	return Run("a.js", code, popts, Downlevel(opts)...)
+}
+
+// the lowered code is expected to print the same output as the original code, it's checked
+// with the lowering alone and with all the lowerings turned on
+func assertEquivalent(t *testing.T, code string, lw Lowering) {
+	want := runNode(t, code)
+	for _, lws := range []Lowering{lw, LW_ALL_LOWERINGS} {
+		ret, err := lowerCode(code, lws)
+		AssertEqual(t, nil, err, "should be ok")
+		AssertEqualString(t, want, runNode(t, ret.Code), "should be ok:\n"+ret.Code)
+	}
+}
+
+func TestLoweringsOf(t *testing.T) {
+	AssertEqual(t, LW_ALL_LOWERINGS, LoweringsOf(parser.ES5), "should be ok")
+	AssertEqual(t, LW_NONE, LoweringsOf(parser.ES13), "should be ok")
+
+	lws := LoweringsOf(parser.ES11)
+	AssertEqual(t, true, lws.Has(LW_LOGIC_ASSIGN|LW_NUM_SEP), "should be ok")
+	AssertEqual(t, false, lws.Has(LW_OPT_CHAIN|LW_NULLISH|LW_ASYNC|LW_ARROW), "should be ok")
+	AssertEqual(t, 3, len(Downlevel(NewDownlevelOpts(parser.ES11))), "should be ok")
+}
+
+func TestDownlevelLogicAssign(t *testing.T) {
+	ret, err := lowerCode(`a.b ||= c; a[k()] ??= d;`, LW_LOGIC_ASSIGN)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `var _a;
+a.b || (a.b = c); a[_a = k()] ?? (a[_a] = d);`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `const log = [];
+const o = { a: 0, b: 1, c: null };
+let n = 0;
+function k() { n++; return "c"; }
+o.a ||= 2; o.b &&= 3; o[k()] ??= 4; o[k()] ??= 5;
+let x = null; x ??= "x";
+console.log(JSON.stringify(o), n, x);`, LW_LOGIC_ASSIGN)
+}
+
+func TestDownlevelNumSep(t *testing.T) {
+	ret, err := lowerCode(`a = 1_000_000 + 0xf_f + 1_0.0_1e1_0;`, LW_NUM_SEP)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `a = 1000000 + 0xff + 10.01e10;`, ret.Code, "should be ok")
+}
+
+func TestDownlevelOptChain(t *testing.T) {
+	ret, err := lowerCode(`a?.b.c(); f()?.[0];`, LW_OPT_CHAIN)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `var _a;
+a === null || a === void 0 ? void 0 : a.b.c(); (_a = f()) === null || _a === void 0 ? void 0 : _a[0];`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `const o = { a: { b() { return this.v; }, v: 1 }, n: null };
+let calls = 0;
+const f = () => (calls++, o);
+console.log(o?.a.b(), o.n?.x.y, f()?.a?.["v"], o.a.c?.(), o.a.b?.(), delete o?.n, calls);`, LW_OPT_CHAIN)
+}
+
+func TestDownlevelNullish(t *testing.T) {
+	assertEquivalent(t, `let calls = 0;
+const f = () => (calls++, 0);
+console.log(null ?? 1, f() ?? 2, void 0 ?? (false ?? 3), calls);`, LW_NULLISH)
+}
+
+func TestDownlevelPow(t *testing.T) {
+	ret, err := lowerCode(`a = b ** c; d **= 2;`, LW_POW)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `a = Math.pow(b, c); d = Math.pow(d, 2);`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `let a = 2; const o = { v: 3 };
+a **= 3; o.v **= 2;
+console.log(2 ** 3 ** 2, (-2) ** 2, a, o.v);`, LW_POW)
+}
+
+func TestDownlevelTpl(t *testing.T) {
+	ret, err := lowerCode("a = `x${b}y`;", LW_TPL)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `a = "x".concat(b, "y");`, ret.Code, "should be ok")
+
+	assertEquivalent(t, "function tag(s, ...v) { return s.raw.join(\"|\") + s.join(\"|\") + v.join(); }\n"+
+		"const a = 1, b = { toString() { return \"B\"; } };\n"+
+		"console.log(`a${a}b${b}c`, `${a + 1}`, tag`x\\n${a}y${b}`, `line\nnext \"q\"`);", LW_TPL)
+}
+
+func TestDownlevelDestructuring(t *testing.T) {
+	ret, err := lowerCode(`const { a, b: [c] = [] } = o;`, LW_DESTRUCTURING)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `const a = o.a, _a = o.b, _b = __read(_a === void 0 ? [] : _a, 1), c = _b[0];`,
+		withoutHelpers(ret.Code), "should be ok")
+
+	assertEquivalent(t, `const log = [];
+const { a, b: { c = 2 }, ...r } = { a: 1, b: {}, d: 4 };
+const [x, , y = 3, ...z] = [1, 2, undefined, 4, 5];
+let m, n;
+[m, n] = [n, m] = [1, 2];
+function f({ p } = { p: "p" }, [q] = ["q"]) { return p + q; }
+for (const [k, v] of Object.entries({ k: "v" })) log.push(k + v);
+try { throw { e: "e" }; } catch ({ e }) { log.push(e); }
+console.log(a, c, JSON.stringify(r), x, y, z, m, n, f(), f({ p: 1 }, [2]), log);`, LW_DESTRUCTURING)
+}
+
+func TestDownlevelArrow(t *testing.T) {
+	ret, err := lowerCode(`function f() { return () => this.a; }`, LW_ARROW)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `function f() { var _this = this; return function () { return _this.a; }; }`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `function F() {
+  this.v = 1;
+  const get = () => this.v;
+  const args = () => arguments.length;
+  const nt = () => new.target === F;
+  this.r = [get(), args(), nt(), [1, 2].map((x) => ({ x }))[1].x];
+}
+class A { m() { return [1].map(() => super.toString === Object.prototype.toString)[0]; } }
+console.log(JSON.stringify(new F(1, 2)), new A().m());`, LW_ARROW)
+}
+
+func TestDownlevelAsync(t *testing.T) {
+	ret, err := lowerCode(`async function f() { await g(); }`, LW_ASYNC)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `function f() { return __awaiter(this, void 0, void 0, function* () { yield g(); }); }`,
+		withoutHelpers(ret.Code), "should be ok")
+
+	assertEquivalent(t, `class B { m(x) { return "b" + x; } }
+class C extends B { async m(x) { return (await super.m(x)) + super["m"](1); } }
+const f = async (x) => (await x) * 2;
+const g = async function () { return arguments.length + await 1; };
+const o = { async k() { return await 5; } };
+(async () => {
+  const r = [await f(1), await g(1, 2), await new C().m("x"), await o.k()];
+  try { await Promise.reject(new Error("boom")); } catch (e) { r.push(e.message); }
+  console.log(JSON.stringify(r));
+})();`, LW_ASYNC)
+
+	_, err = lowerCode(`async function f() { for await (const x of xs); }`, LW_ASYNC)
+	AssertEqual(t, "`for await` can not be lowered without lowering the async generators at (1:21)", err.Error(), "should be ok")
+}
+
+func TestDownlevelAsyncGenerator(t *testing.T) {
+	assertEquivalent(t, `const log = [];
+async function* gen(n) {
+  for (let i = 0; i < n; i++) yield await Promise.resolve(i);
+  yield* [10, 11];
+  return "done";
+}
+async function* closing() { try { yield 1; yield 2; } finally { log.push("closed"); } }
+async function main() {
+  for await (const v of gen(2)) log.push(v);
+  outer: for await (const v of closing()) { for (;;) continue outer; }
+  for await (const v of closing()) if (v === 1) break;
+  let w;
+  for await (w of [Promise.resolve(7), 8]) log.push(w);
+  const it = gen(0);
+  log.push(JSON.stringify(await it.next()), JSON.stringify(await it.next()), JSON.stringify(await it.next()));
+  const o = { async *m() { yield arguments.length; } };
+  for await (const v of o.m(1, 2)) log.push(v);
+}
+main().then(() => console.log(JSON.stringify(log)));`, LW_ASYNC_GENERATOR)
+}
+
+func TestDownlevelObjRestSpread(t *testing.T) {
+	ret, err := lowerCode(`a = { ...b, c, ...d }; const { e, ...f } = a;`, LW_OBJ_REST_SPREAD)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `a = __assign(__assign(__assign({}, b), { c }), d); const e = a.e, f = __rest(a, ["e"]);`,
+		withoutHelpers(ret.Code), "should be ok")
+
+	assertEquivalent(t, `const b = { x: 1, y: 2 }, d = { z: 3 };
+const o = { a: 0, ...b, c: 1, ...d };
+const { x, ...rest } = o;
+const k = "c";
+let cc, r2;
+({ [k]: cc, ...r2 } = o);
+function f({ a, ...r } = { a: 1, q: 2 }, [m, { n, ...p }] = [1, { n: 2, o: 3 }]) { return JSON.stringify([a, r, m, n, p]); }
+console.log(JSON.stringify(o), x, JSON.stringify(rest), cc, JSON.stringify(r2), f(), f({ a: 5, w: 6 }));`, LW_OBJ_REST_SPREAD)
+}
+
+func TestDownlevelClassFields(t *testing.T) {
+	ret, err := lowerCode(`class A { a = 1; static b = this.a; }`, LW_CLASS_FIELD)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `class A { constructor() { Object.defineProperty(this, "a", { enumerable: true, configurable: true, writable: true, value: 1 }); } }
+Object.defineProperty(A, "b", { enumerable: true, configurable: true, writable: true, value: A.a });`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `let k = "dyn";
+class C { a = 1; b = this.a + 1; [k] = 2; static s = "s"; static t = this.s + 1; }
+class D extends C { c = 3; constructor(x) { if (x) { super(); } else { super(); } } }
+class F extends C { d = () => this.a; }
+const E = class Named { static self = Named; static f = () => this.self === Named; };
+console.log(JSON.stringify(new C()), C.s, C.t, JSON.stringify(new D(1)), JSON.stringify(new D(0)), new F().d(), E.self === E, E.f());`, LW_CLASS_FIELD)
+}
+
+func TestDownlevelClassPriv(t *testing.T) {
+	assertEquivalent(t, `class A {
+  #x = 1;
+  static #s = 42;
+  #m(a) { return this.#x + a; }
+  get #g() { return this.#x * 10; }
+  set #g(v) { this.#x = v; }
+  static #sm() { return "sm"; }
+  run() {
+    this.#x += 5;
+    const old = this.#x++;
+    const nw = ++this.#x;
+    this.#g = 100;
+    this.#x ||= 7;
+    return [old, nw, this.#m(1), this.#g, A.#sm(), A.#s, this.#x].join();
+  }
+  static has(o) { try { o.#x; return true; } catch (e) { return false; } }
+}
+console.log(new A().run(), A.has(new A()), A.has({}));`, LW_CLASS_PRIV)
+
+	assertEquivalent(t, `class B {
+  #x;
+  #m() {}
+  static #s = 1;
+  static is(o) { return [#x in o, #m in o, #s in o].join(); }
+}
+console.log(B.is(new B()), B.is({}), B.is(B));`, LW_CLASS_PRIV)
+
+	_, err := lowerCode(`class A { static #x = 1; static y = A.#x; }`, LW_CLASS_FIELD)
+	AssertEqual(t, "the private member `#x` can not be referenced in the class element which is moved out "+
+		"of the class body without lowering the private members at (1:38)", err.Error(), "should be ok")
+}
+
+func TestDownlevelStaticBlock(t *testing.T) {
+	ret, err := lowerCode(`class A { static { this.a = 1; } }`, LW_STATIC_BLOCK)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqualString(t, `class A { }
+(function () { this.a = 1; }).call(A);`, ret.Code, "should be ok")
+
+	assertEquivalent(t, `const log = [];
+class A {
+  static a = 1;
+  static { log.push(this.a); var v = 2; this.b = v; }
+  static c = this.b + 1;
+}
+console.log(JSON.stringify(log), A.b, A.c);`, LW_STATIC_BLOCK)
+}
+
+func TestDownlevelES5(t *testing.T) {
+	ret, err := lowerCode(`const f = async ({ a, ...r }) => a?.b ?? `+"`${r}`"+`;`, LW_ALL_LOWERINGS)
+	AssertEqual(t, nil, err, "should be ok")
+	code := withoutHelpers(ret.Code)
+	for _, s := range []string{"=>", "async ", "await ", "?.", "??", "`", "..."} {
+		AssertEqual(t, false, strings.Contains(code, s), "should not contain "+s+":\n"+code)
+	}
+}
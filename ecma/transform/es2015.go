@@ -0,0 +1,488 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+var reLineTerm = regexp.MustCompile(`\r\n?`)
+
+// the untagged templates are lowered to the concatenations like `"a".concat(b, "c")` and the
+// tagged ones are lowered to the calls with the template objects which are cached in the
+// variables at the top level, for example t`a${b}` is lowered to:
+//
+//	t(_a || (_a = __makeTemplateObject(["a", ""], ["a", ""])), b)
+func lowerTpl(l *lowering) {
+	l.on(parser.N_EXPR_TPL, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.TplExpr)
+		elems := n.Elems()
+
+		if n.Tag() != nil {
+			cooked := make([]string, 0)
+			raw := make([]string, 0)
+			args := make([]string, 0)
+			for i, elem := range elems {
+				if i%2 == 1 {
+					args = append(args, l.tplArg(elem))
+					continue
+				}
+				cooked = append(cooked, jsQuote(elem.(*parser.StrLit).Val()))
+				rng := elem.Range()
+				raw = append(raw, jsQuote(reLineTerm.ReplaceAllString(l.code[rng.Lo:rng.Hi], "\n")))
+			}
+
+			l.use("__makeTemplateObject")
+			obj := l.declare(l.ctx.Ast, l.seqName())
+			args = append([]string{fmt.Sprintf("%s || (%s = __makeTemplateObject([%s], [%s]))", obj, obj,
+				strings.Join(cooked, ", "), strings.Join(raw, ", "))}, args...)
+			rng := n.Range()
+			rng.Lo = outerRange(n.Tag()).Lo
+			l.p.Replace(rng, fmt.Sprintf("%s(%s)", l.text(n.Tag()), strings.Join(args, ", ")))
+			return
+		}
+
+		head := jsQuote(elems[0].(*parser.StrLit).Val())
+		if len(elems) == 1 {
+			l.p.Replace(n.Range(), head)
+			return
+		}
+		args := make([]string, 0)
+		for i, elem := range elems[1:] {
+			if i%2 == 0 {
+				args = append(args, l.tplArg(elem))
+			} else if s := elem.(*parser.StrLit).Val(); s != "" {
+				args = append(args, jsQuote(s))
+			}
+		}
+		l.p.Replace(n.Range(), fmt.Sprintf("%s.concat(%s)", head, strings.Join(args, ", ")))
+	})
+}
+
+func (l *lowering) tplArg(node parser.Node) string {
+	if node.Type() == parser.N_EXPR_SEQ && !hasParen(node) {
+		return "(" + l.text(node) + ")"
+	}
+	return l.text(node)
+}
+
+func isPat(node parser.Node) bool {
+	switch node.Type() {
+	case parser.N_PAT_OBJ, parser.N_PAT_ARRAY:
+		return true
+	}
+	return false
+}
+
+// the pattern is flattened to a list of the assignments from the values to the targets, for
+// example `{ a, b: [c] }` with the value `o` is flattened to:
+//
+//	a = o.a, _a = __read(o.b, 1), c = _a[0]
+type destructurer struct {
+	l   *lowering
+	tmp func() string
+	out []string
+}
+
+func (d *destructurer) add(target, value string) {
+	d.out = append(d.out, target+" = "+value)
+}
+
+// the value is referenced more than once, so it's stored in a temporary variable if it's not simple
+func (d *destructurer) reusable(value string, simple bool) string {
+	if simple {
+		return value
+	}
+	t := d.tmp()
+	d.add(t, value)
+	return t
+}
+
+func (d *destructurer) flatten(pat parser.Node, value string, simple bool) {
+	l := d.l
+	switch n := pat.(type) {
+	case *parser.AssignPat:
+		v := d.reusable(value, simple)
+		d.flatten(n.Lhs(), fmt.Sprintf("%s === void 0 ? %s : %s", v, l.text(n.Rhs()), v), false)
+
+	case *parser.ObjPat:
+		v := d.reusable(value, simple)
+		props := n.Props()
+		rest := len(props) > 0 && props[len(props)-1].Type() == parser.N_PAT_REST
+		keys := make([]string, 0)
+		for _, prop := range props {
+			switch p := prop.(type) {
+			case *parser.Prop:
+				key := p.Key()
+				var access string
+				if p.Computed() {
+					k := l.text(key)
+					if rest {
+						k = d.reusable(k, false)
+						keys = append(keys, fmt.Sprintf(`typeof %s === "symbol" ? %s : %s + ""`, k, k, k))
+					}
+					access = fmt.Sprintf("%s[%s]", v, k)
+				} else {
+					switch k := key.(type) {
+					case *parser.Ident:
+						keys = append(keys, jsQuote(k.Val()))
+						access = v + "." + k.Val()
+					case *parser.StrLit:
+						keys = append(keys, jsQuote(k.Val()))
+						access = fmt.Sprintf("%s[%s]", v, l.text(k))
+					default:
+						keys = append(keys, `"" + `+l.text(k))
+						access = fmt.Sprintf("%s[%s]", v, l.text(k))
+					}
+				}
+				d.flatten(p.Val(), access, false)
+			case *parser.RestPat:
+				l.use("__rest")
+				d.flatten(p.Arg(), fmt.Sprintf("__rest(%s, [%s])", v, strings.Join(keys, ", ")), false)
+			}
+		}
+
+	case *parser.ArrPat:
+		elems := n.Elems()
+		l.use("__read")
+		read := fmt.Sprintf("__read(%s, %d)", value, len(elems))
+		if len(elems) > 0 && elems[len(elems)-1] != nil && elems[len(elems)-1].Type() == parser.N_PAT_REST {
+			read = fmt.Sprintf("__read(%s)", value)
+		}
+		v := d.reusable(read, false)
+		for i, elem := range elems {
+			if elem == nil {
+				continue
+			}
+			if r, ok := elem.(*parser.RestPat); ok {
+				d.flatten(r.Arg(), fmt.Sprintf("%s.slice(%d)", v, i), false)
+			} else {
+				d.flatten(elem, fmt.Sprintf("%s[%d]", v, i), false)
+			}
+		}
+
+	default:
+		d.add(l.text(pat), value)
+	}
+}
+
+// collects the names of the identifiers bound by the pattern
+func patNames(pat parser.Node, names map[string]bool) {
+	switch n := pat.(type) {
+	case *parser.Ident:
+		names[n.Val()] = true
+	case *parser.AssignPat:
+		patNames(n.Lhs(), names)
+	case *parser.RestPat:
+		patNames(n.Arg(), names)
+	case *parser.Prop:
+		patNames(n.Val(), names)
+	case *parser.ObjPat:
+		for _, prop := range n.Props() {
+			patNames(prop, names)
+		}
+	case *parser.ArrPat:
+		for _, elem := range n.Elems() {
+			if elem != nil {
+				patNames(elem, names)
+			}
+		}
+	}
+}
+
+// whether the value can be referenced more than once in the flattened assignments of the pattern
+func simpleValueOf(pat, value parser.Node) bool {
+	id, ok := value.(*parser.Ident)
+	if !ok {
+		return value.Type() == parser.N_EXPR_THIS
+	}
+	names := map[string]bool{}
+	patNames(pat, names)
+	return !names[id.Val()]
+}
+
+// flattens the pattern into the declarators, the temporary variables are declared by themselves
+func (l *lowering) declarators(pat parser.Node, value string, simple bool) string {
+	d := &destructurer{l: l, tmp: l.seqName}
+	d.flatten(pat, value, simple)
+	return strings.Join(d.out, ", ")
+}
+
+// replaces the pattern with a temporary variable and returns the declarators of the pattern
+func (l *lowering) bindTmp(pat parser.Node) string {
+	t := l.seqName()
+	decs := l.declarators(pat, t, true)
+	l.p.Replace(pat.Range(), t)
+	return decs
+}
+
+// inserts the statements at the beginning of the body, the body is wrapped into a block if
+// it's not a block
+func (l *lowering) prependStmt(body parser.Node, stmts string) {
+	if b, ok := body.(*parser.BlockStmt); ok {
+		l.prepend(b, stmts)
+		return
+	}
+	l.p.Replace(body.Range(), fmt.Sprintf("{ %s %s }", stmts, l.text(body)))
+}
+
+func lowerDestructuring(l *lowering) {
+	destructure(l, isPat)
+}
+
+// flattens the patterns which satisfy `match` in all the places where the patterns can appear
+func destructure(l *lowering, match func(pat parser.Node) bool) {
+	l.on(parser.N_VAR_DEC, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.VarDec)
+		if !match(n.Id()) || n.Init() == nil {
+			return
+		}
+		l.p.Replace(n.Range(), l.declarators(n.Id(), l.text(n.Init()), simpleValueOf(n.Id(), n.Init())))
+	})
+
+	l.on(parser.N_EXPR_ASSIGN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.AssignExpr)
+		if n.Op() != parser.T_ASSIGN || !match(n.Lhs()) {
+			return
+		}
+
+		// the value of the assignment is discarded if it's an expression statement
+		stmt := false
+		for c := vc.Parent; c != nil; c = c.Parent {
+			if c.Node.Type() == parser.N_STMT_EXPR {
+				stmt = true
+			} else if c.Node.Type() == parser.N_EXPR_PAREN {
+				continue
+			}
+			break
+		}
+
+		d := &destructurer{l: l, tmp: func() string { return l.tmp(vc) }}
+		value, simple := l.text(n.Rhs()), simpleValueOf(n.Lhs(), n.Rhs())
+		if !stmt {
+			value = d.reusable(value, simple)
+			simple = true
+		}
+		d.flatten(n.Lhs(), value, simple)
+		if !stmt {
+			d.out = append(d.out, value)
+		}
+		l.replace(vc, strings.Join(d.out, ", "), precSeq)
+	})
+
+	// the patterns in the parameters are replaced with the temporary variables which are
+	// destructured at the beginning of the function body
+	l.exits = append(l.exits, func(fn parser.Node, vc *walk.VisitorCtx) {
+		var params []parser.Node
+		switch n := fn.(type) {
+		case *parser.FnDec:
+			params = n.Params()
+		case *parser.ArrowFn:
+			params = n.Params()
+		}
+		decs := make([]string, 0)
+		for _, param := range params {
+			pat := param
+			switch n := param.(type) {
+			case *parser.AssignPat:
+				pat = n.Lhs()
+			case *parser.RestPat:
+				pat = n.Arg()
+			}
+			if match(pat) {
+				decs = append(decs, l.bindTmp(pat))
+			}
+		}
+		if len(decs) > 0 {
+			l.prologue(fn, "var "+strings.Join(decs, ", ")+";")
+		}
+	})
+
+	l.on(parser.N_CATCH, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.Catch)
+		if n.Param() != nil && match(n.Param()) {
+			l.prependStmt(n.Body(), "let "+l.bindTmp(n.Param())+";")
+		}
+	})
+
+	l.on(parser.N_STMT_FOR_IN_OF, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.ForInOfStmt)
+		switch left := n.Left().(type) {
+		case *parser.VarDecStmt:
+			dec := left.DecList()[0].(*parser.VarDec)
+			if match(dec.Id()) {
+				l.prependStmt(n.Body(), left.Kind()+" "+l.bindTmp(dec.Id())+";")
+			}
+		default:
+			if match(left) {
+				t := l.tmp(vc)
+				d := &destructurer{l: l, tmp: func() string { return l.tmp(vc) }}
+				d.flatten(left, t, true)
+				l.p.Replace(left.Range(), t)
+				l.prependStmt(n.Body(), strings.Join(d.out, ", ")+";")
+			}
+		}
+	})
+}
+
+// whether the function of `vc` is the constructor of a derived class
+func isDerivedCtor(vc *walk.VisitorCtx) bool {
+	m, ok := vc.ParentNode().(*parser.Method)
+	if !ok || m.PropKind() != parser.PK_CTOR {
+		return false
+	}
+	cls, ok := vc.Parent.Parent.ParentNode().(*parser.ClassDec)
+	return ok && cls.Super() != nil
+}
+
+// the arrow functions are lowered to the function expressions, `this`, `arguments` and
+// `new.target` in them are replaced with the variables which capture them in the nearest
+// functions which are not arrow functions
+func lowerArrow(l *lowering) {
+	captures := map[parser.Node]map[string]string{}
+	// the arrows which are kept, the ones which use `super` are kept silently since `super` is
+	// only available in the classes and methods which are kept as well
+	keep := map[parser.Node]bool{}
+	warn := map[parser.Node]bool{}
+
+	// `this` is unavailable before `super(...)` in the constructors of the derived classes, so
+	// it's captured by the value of `super(...)` instead of at the beginning of the constructors
+	derived := map[parser.Node]string{}
+	supers := map[parser.Node][]parser.Node{}
+
+	// the arrows between the node of `vc` and the function which provides `this`
+	capture := func(vc *walk.VisitorCtx, what, base string) {
+		arrows := make([]parser.Node, 0)
+		var scope parser.Node
+		var svc *walk.VisitorCtx
+	loop:
+		for c := vc.Parent; c != nil; c = c.Parent {
+			switch c.Node.Type() {
+			case parser.N_EXPR_ARROW:
+				arrows = append(arrows, c.Node)
+			case parser.N_PROG, parser.N_STMT_FN, parser.N_EXPR_FN, parser.N_FIELD, parser.N_STATIC_BLOCK:
+				scope, svc = c.Node, c
+				break loop
+			}
+		}
+		if len(arrows) == 0 {
+			return
+		}
+
+		switch scope.Type() {
+		case parser.N_FIELD, parser.N_STATIC_BLOCK:
+			// there is no place to declare the variable
+			for _, arrow := range arrows {
+				keep[arrow] = true
+				warn[arrow] = true
+			}
+			return
+		case parser.N_PROG:
+			if what == "arguments" {
+				return
+			}
+		}
+
+		if captures[scope] == nil {
+			captures[scope] = map[string]string{}
+		}
+		name, ok := captures[scope][what]
+		if !ok {
+			name = l.name(base)
+			captures[scope][what] = name
+			if what == "this" && isDerivedCtor(svc) {
+				derived[scope] = name
+				l.declare(scope, name)
+			} else {
+				l.declare(scope, name+" = "+what)
+			}
+		}
+		rng := vc.Node.Range()
+		l.p.Replace(rng, name)
+	}
+
+	l.on(parser.N_EXPR_THIS, func(node parser.Node, vc *walk.VisitorCtx) {
+		capture(vc, "this", "_this")
+	})
+	l.on(parser.N_EXPR_CALL, func(node parser.Node, vc *walk.VisitorCtx) {
+		if node.(*parser.CallExpr).Callee().Type() == parser.N_SUPER {
+			fn := thisScopeOf(vc)
+			supers[fn] = append(supers[fn], node)
+		}
+	})
+	l.exits = append(l.exits, func(fn parser.Node, vc *walk.VisitorCtx) {
+		if name, ok := derived[fn]; ok {
+			for _, call := range supers[fn] {
+				l.p.Replace(call.Range(), fmt.Sprintf("(%s = %s)", name, l.p.Text(call.Range())))
+			}
+		}
+	})
+	l.on(parser.N_NAME, func(node parser.Node, vc *walk.VisitorCtx) {
+		if node.(*parser.Ident).Val() != "arguments" {
+			return
+		}
+		switch p := vc.ParentNode().(type) {
+		case *parser.MemberExpr:
+			if p.Prop() == node && !p.Compute() {
+				return
+			}
+		case *parser.Prop:
+			if p.Key() == node && !p.Computed() && !p.Shorthand() {
+				return
+			}
+		}
+		capture(vc, "arguments", "_arguments")
+	})
+	l.on(parser.N_META_PROP, func(node parser.Node, vc *walk.VisitorCtx) {
+		if l.text(node.(*parser.MetaProp).Meta()) == "new" {
+			capture(vc, "new.target", "_newTarget")
+		}
+	})
+	l.on(parser.N_SUPER, func(node parser.Node, vc *walk.VisitorCtx) {
+		for c := vc.Parent; c != nil && c.Node.Type() != parser.N_STMT_FN && c.Node.Type() != parser.N_EXPR_FN; c = c.Parent {
+			if c.Node.Type() == parser.N_EXPR_ARROW {
+				keep[c.Node] = true
+			}
+		}
+	})
+
+	l.on(parser.N_EXPR_ARROW, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.ArrowFn)
+		if keep[n] {
+			if warn[n] {
+				l.ctx.Warn(n.Range(), "the arrow function is kept since `this` in it can not be captured")
+			}
+			return
+		}
+
+		ofst := n.Range().Lo
+		if params := n.Params(); len(params) > 0 {
+			ofst = outerRange(params[len(params)-1]).Hi
+		}
+		arrow := ofst + uint32(strings.Index(l.code[ofst:], "=>"))
+		head := strings.TrimSpace(l.p.Text(span.Range{Lo: n.Range().Lo, Hi: arrow}))
+		if n.Async() {
+			head = strings.TrimSpace(strings.TrimPrefix(head, "async"))
+		}
+		if !strings.HasPrefix(head, "(") {
+			head = "(" + head + ")"
+		}
+
+		body := l.text(n.Body())
+		if n.Expr() {
+			body = fmt.Sprintf("{ return %s; }", body)
+		}
+		fn := fmt.Sprintf("function %s %s", head, body)
+		if n.Async() {
+			fn = "async " + fn
+		}
+		if es, ok := vc.ParentNode().(*parser.ExprStmt); ok && es.Range().Lo == n.Range().Lo && !hasParen(n) {
+			fn = "(" + fn + ")"
+		}
+		l.p.Replace(n.Range(), fn)
+	})
+}
@@ -0,0 +1,25 @@
+package transform
+
+import (
+	"fmt"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+)
+
+// `a ** b` to `Math.pow(a, b)` and `a **= b` to `a = Math.pow(a, b)`
+func lowerPow(l *lowering) {
+	l.on(parser.N_EXPR_BIN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.BinExpr)
+		if n.Op() == parser.T_POW {
+			l.p.Replace(n.Range(), fmt.Sprintf("Math.pow(%s, %s)", l.text(n.Lhs()), l.text(n.Rhs())))
+		}
+	})
+	l.on(parser.N_EXPR_ASSIGN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.AssignExpr)
+		if n.Op() == parser.T_ASSIGN_POW {
+			lhs, ref := l.reuse(n.Lhs(), vc)
+			l.p.Replace(n.Range(), fmt.Sprintf("%s = Math.pow(%s, %s)", lhs, ref, l.text(n.Rhs())))
+		}
+	})
+}
@@ -0,0 +1,218 @@
+package transform
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+func isAsyncFn(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.FnDec:
+		return n.Async()
+	case *parser.ArrowFn:
+		return n.Async()
+	}
+	return false
+}
+
+func isGenerator(node parser.Node) bool {
+	n, ok := node.(*parser.FnDec)
+	return ok && n.Generator()
+}
+
+// the shared states of lowering the async functions and the async generators, the bodies of
+// them are moved into the generator functions, so `arguments` and `super` in the bodies are
+// captured before the bodies are moved
+type asyncLowering struct {
+	*lowering
+	gen bool // lowers the async generators if it's true otherwise the async functions
+
+	args   map[parser.Node]bool   // the functions whose `arguments` are referenced
+	supers map[parser.Node]string // the functions whose `super` are referenced
+}
+
+func newAsyncLowering(l *lowering, gen bool) *asyncLowering {
+	a := &asyncLowering{l, gen, map[parser.Node]bool{}, map[parser.Node]string{}}
+
+	l.on(parser.N_NAME, func(node parser.Node, vc *walk.VisitorCtx) {
+		if node.(*parser.Ident).Val() != "arguments" {
+			return
+		}
+		if m, ok := vc.ParentNode().(*parser.MemberExpr); ok && m.Prop() == node && !m.Compute() {
+			return
+		}
+		// `arguments` in the arrow functions is the one of the enclosing function, so all the
+		// functions up to the one which is not an arrow function need it
+		for c := vc.Parent; c != nil; c = c.Parent {
+			if isFn(c.Node) {
+				a.args[c.Node] = true
+				if c.Node.Type() != parser.N_EXPR_ARROW {
+					break
+				}
+			}
+		}
+	})
+
+	l.on(parser.N_SUPER, func(node parser.Node, vc *walk.VisitorCtx) {
+		fn := thisScopeOf(vc)
+		if !a.lowered(fn) {
+			return
+		}
+		mvc := vc.Parent
+		m, ok := mvc.Node.(*parser.MemberExpr)
+		if !ok {
+			return
+		}
+		name, ok := a.supers[fn]
+		if !ok {
+			name = l.name("_superIndex")
+			a.supers[fn] = name
+		}
+		key := l.text(m.Prop())
+		if !m.Compute() {
+			key = jsQuote(key)
+		}
+		access := fmt.Sprintf("%s(%s)", name, key)
+
+		switch p := mvc.ParentNode().(type) {
+		case *parser.CallExpr:
+			if p.Callee() == m {
+				args := []string{"this"}
+				for _, arg := range p.Args() {
+					args = append(args, l.text(arg))
+				}
+				l.p.Replace(p.Range(), fmt.Sprintf("%s.call(%s)", access, strings.Join(args, ", ")))
+				return
+			}
+		case *parser.AssignExpr:
+			if p.Lhs() == m {
+				l.ctx.Warn(m.Range(), "the assignment to the property of `super` is not supported in the async functions")
+				return
+			}
+		}
+		l.p.Replace(m.Range(), access)
+	})
+	return a
+}
+
+// whether the function is lowered by the pass
+func (a *asyncLowering) lowered(fn parser.Node) bool {
+	return fn != nil && isAsyncFn(fn) && isGenerator(fn) == a.gen
+}
+
+// the function whose `await` is the node of `vc`
+func (a *asyncLowering) awaitFn(vc *walk.VisitorCtx) parser.Node {
+	fn := fnOf(vc)
+	if a.lowered(fn) {
+		return fn
+	}
+	return nil
+}
+
+var reAsyncKw = regexp.MustCompile(`\basync\b\s*`)
+
+// removes the `async` and `*` before the function
+func (a *asyncLowering) removeModifiers(fn parser.Node, vc *walk.VisitorCtx) {
+	lo := fn.Range().Lo
+	var hi uint32
+	switch p := vc.ParentNode().(type) {
+	case *parser.Method:
+		lo, hi = p.Range().Lo, p.Key().Range().Lo
+	case *parser.Prop:
+		if p.Val() == fn && p.Method() {
+			lo, hi = p.Range().Lo, p.Key().Range().Lo
+		}
+	}
+	if hi == 0 {
+		switch n := fn.(type) {
+		case *parser.FnDec:
+			if n.Id() != nil {
+				hi = n.Id().Range().Lo
+			} else {
+				hi = lo + uint32(strings.IndexByte(a.code[lo:], '('))
+			}
+		case *parser.ArrowFn:
+			hi = skipSpaces(a.code, lo+5)
+		}
+	}
+
+	head := a.code[lo:hi]
+	if loc := reAsyncKw.FindStringIndex(head); loc != nil {
+		a.p.Remove(span.Range{Lo: lo + uint32(loc[0]), Hi: lo + uint32(loc[1])})
+	}
+	if isGenerator(fn) {
+		if i := strings.IndexByte(head, '*'); i != -1 {
+			star := lo + uint32(i)
+			if star > 0 && isIdByte(a.code[star-1]) && isIdByte(a.code[star+1]) {
+				a.p.Replace(span.Range{Lo: star, Hi: star + 1}, " ")
+			} else {
+				a.p.Remove(span.Range{Lo: star, Hi: star + 1})
+			}
+		}
+	}
+}
+
+// rewrites the function to call `wrap` with the generator function which has the body of
+// the original function, `wrap` is formatted with `this` and `arguments`
+func (a *asyncLowering) wrap(fn parser.Node, vc *walk.VisitorCtx, wrap string) {
+	a.removeModifiers(fn, vc)
+
+	args := "void 0"
+	if a.args[fn] {
+		args = "arguments"
+	}
+	body := fnBody(fn)
+	text := a.text(body)
+	if body.Type() != parser.N_STMT_BLOCK {
+		text = fmt.Sprintf("{ return %s; }", text)
+	}
+	call := fmt.Sprintf(wrap, args, "function* () "+text)
+
+	if fn.Type() == parser.N_EXPR_ARROW {
+		a.p.Replace(outerRange(body), call)
+		return
+	}
+	stmts := make([]string, 0)
+	if name, ok := a.supers[fn]; ok {
+		stmts = append(stmts, fmt.Sprintf("const %s = (name) => super[name];", name))
+	}
+	stmts = append(stmts, "return "+call+";")
+	a.p.Replace(body.Range(), "{ "+strings.Join(stmts, " ")+" }")
+}
+
+// the async functions are lowered to the generator functions driven by `__awaiter`, for example:
+//
+//	async function f(a) { await a; }
+//
+// is lowered to:
+//
+//	function f(a) { return __awaiter(this, void 0, void 0, function* () { yield a; }); }
+func lowerAsync(l *lowering) {
+	a := newAsyncLowering(l, false)
+
+	l.on(parser.N_EXPR_UNARY, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.UnaryExpr)
+		if n.Op() == parser.T_AWAIT && a.awaitFn(vc) != nil {
+			l.replace(vc, "yield "+l.text(n.Arg()), precAssign)
+		}
+	})
+	l.on(parser.N_STMT_FOR_IN_OF, func(node parser.Node, vc *walk.VisitorCtx) {
+		if node.(*parser.ForInOfStmt).Await() && a.awaitFn(vc) != nil {
+			l.errorf(node.Range(), "`for await` can not be lowered without lowering the async generators")
+		}
+	})
+
+	for _, t := range []parser.NodeType{parser.N_STMT_FN, parser.N_EXPR_FN, parser.N_EXPR_ARROW} {
+		l.on(t, func(node parser.Node, vc *walk.VisitorCtx) {
+			if a.lowered(node) {
+				l.use("__awaiter")
+				a.wrap(node, vc, "__awaiter(this, %s, void 0, %s)")
+			}
+		})
+	}
+}
@@ -0,0 +1,194 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+)
+
+// whether there is an object pattern with the rest element in the pattern
+func hasObjRest(pat parser.Node) bool {
+	switch n := pat.(type) {
+	case *parser.AssignPat:
+		return hasObjRest(n.Lhs())
+	case *parser.RestPat:
+		return hasObjRest(n.Arg())
+	case *parser.Prop:
+		return hasObjRest(n.Val())
+	case *parser.ObjPat:
+		for _, prop := range n.Props() {
+			if prop.Type() == parser.N_PAT_REST || hasObjRest(prop) {
+				return true
+			}
+		}
+	case *parser.ArrPat:
+		for _, elem := range n.Elems() {
+			if elem != nil && hasObjRest(elem) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// the object spread is lowered to the calls of `__assign`, for example `{ a, ...b, c }` is
+// lowered to `__assign(__assign({ a }, b), { c })`, and the patterns with the object rest
+// element are flattened as the way of the destructuring lowering
+func lowerObjRestSpread(l *lowering) {
+	l.on(parser.N_LIT_OBJ, func(node parser.Node, vc *walk.VisitorCtx) {
+		props := node.(*parser.ObjLit).Props()
+		spread := false
+		for _, prop := range props {
+			if prop.Type() == parser.N_SPREAD {
+				spread = true
+				break
+			}
+		}
+		if !spread {
+			return
+		}
+
+		l.use("__assign")
+		ret := ""
+		chunk := make([]string, 0)
+		merge := func(part string) {
+			if ret == "" {
+				ret = part
+			} else {
+				ret = fmt.Sprintf("__assign(%s, %s)", ret, part)
+			}
+		}
+		for _, prop := range props {
+			if s, ok := prop.(*parser.Spread); ok {
+				if len(chunk) > 0 {
+					merge("{ " + strings.Join(chunk, ", ") + " }")
+					chunk = chunk[:0]
+				}
+				if ret == "" {
+					ret = "{}"
+				}
+				merge(l.text(s.Arg()))
+				continue
+			}
+			chunk = append(chunk, l.text(prop))
+		}
+		if len(chunk) > 0 {
+			merge("{ " + strings.Join(chunk, ", ") + " }")
+		}
+		l.p.Replace(node.Range(), ret)
+	})
+
+	destructure(l, hasObjRest)
+}
+
+// the async generators are lowered to the generators driven by `__asyncGenerator`, and the
+// `for await` statements are lowered to the `for` statements which iterate the values of
+// `__asyncValues`, for example:
+//
+//	async function* g(a) { for await (const x of a) yield x; }
+//
+// is lowered to:
+//
+//	function g(a) { return __asyncGenerator(this, arguments, function* () { ... }); }
+func lowerAsyncGenerator(l *lowering) {
+	a := newAsyncLowering(l, true)
+
+	l.on(parser.N_EXPR_UNARY, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.UnaryExpr)
+		if n.Op() == parser.T_AWAIT && a.awaitFn(vc) != nil {
+			l.use("__await")
+			l.replace(vc, fmt.Sprintf("yield __await(%s)", l.text(n.Arg())), precAssign)
+		}
+	})
+
+	l.on(parser.N_EXPR_YIELD, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.YieldExpr)
+		if a.awaitFn(vc) == nil {
+			return
+		}
+		arg := "void 0"
+		if n.Arg() != nil {
+			arg = l.text(n.Arg())
+		}
+		l.use("__await")
+		if n.Delegate() {
+			l.use("__asyncDelegator", "__asyncValues")
+			l.replace(vc, fmt.Sprintf("yield __await(yield* __asyncDelegator(__asyncValues(%s)))", arg), precAssign)
+			return
+		}
+		l.replace(vc, fmt.Sprintf("yield yield __await(%s)", arg), precAssign)
+	})
+
+	l.on(parser.N_STMT_RET, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.RetStmt)
+		if n.Arg() != nil && a.awaitFn(vc) != nil {
+			l.use("__await")
+			l.p.Replace(outerRange(n.Arg()), fmt.Sprintf("yield __await(%s)", l.text(n.Arg())))
+		}
+	})
+
+	l.on(parser.N_STMT_FOR_IN_OF, func(node parser.Node, vc *walk.VisitorCtx) {
+		if node.(*parser.ForInOfStmt).Await() {
+			a.forAwait(node.(*parser.ForInOfStmt), vc)
+		}
+	})
+
+	for _, t := range []parser.NodeType{parser.N_STMT_FN, parser.N_EXPR_FN} {
+		l.on(t, func(node parser.Node, vc *walk.VisitorCtx) {
+			if a.lowered(node) {
+				l.use("__await", "__asyncGenerator")
+				a.args[node] = true
+				a.wrap(node, vc, "__asyncGenerator(this, %s, %s)")
+			}
+		})
+	}
+}
+
+// lowers the `for await` statement to the `for` statement which closes the iterator if the
+// loop is exited abruptly, `await` in the output is left to the async lowering if the
+// statement is in an async function
+func (a *asyncLowering) forAwait(n *parser.ForInOfStmt, vc *walk.VisitorCtx) {
+	l := a.lowering
+	await := func(expr string) string {
+		return "await " + expr
+	}
+	if a.awaitFn(vc) != nil {
+		await = func(expr string) string {
+			return fmt.Sprintf("yield __await(%s)", expr)
+		}
+		l.use("__await")
+	}
+	l.use("__asyncValues")
+
+	it, step, done, val, ret, err := l.tmp(vc), l.tmp(vc), l.tmp(vc), l.tmp(vc), l.tmp(vc), l.tmp(vc)
+	ok := l.tmp(vc)
+	e := l.seqName()
+
+	var bind string
+	switch left := n.Left().(type) {
+	case *parser.VarDecStmt:
+		bind = fmt.Sprintf("%s %s = %s;", left.Kind(), l.text(left.DecList()[0].(*parser.VarDec).Id()), val)
+	default:
+		bind = fmt.Sprintf("(%s = %s);", l.text(left), val)
+	}
+
+	loop := fmt.Sprintf("for (%s = true, %s = __asyncValues(%s); %s = %s, %s = %s.done, !%s; %s = true) { %s = %s.value; %s = false; %s %s }",
+		ok, it, l.text(n.Right()), step, await(it+".next()"), done, step, done, ok, val, step, ok, bind, l.text(n.Body()))
+
+	// the labels of the statement are moved to the inner loop to keep `continue` working
+	rng := n.Range()
+	for c := vc.Parent; c != nil; c = c.Parent {
+		label, isLabel := c.Node.(*parser.LabelStmt)
+		if !isLabel {
+			break
+		}
+		loop = l.text(label.Label()) + ": " + loop
+		rng.Lo = label.Range().Lo
+	}
+
+	stmt := fmt.Sprintf("try { %s } catch (%s) { %s = { error: %s }; } finally { try { if (!%s && !%s && (%s = %s.return)) %s; } finally { if (%s) throw %s.error; } }",
+		loop, e, err, e, ok, done, ret, it, await(ret+".call("+it+")"), err, err)
+	l.p.Replace(rng, stmt)
+}
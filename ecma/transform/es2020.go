@@ -0,0 +1,120 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+)
+
+// the chain is flattened to the conditions joined by `||` and the expression evaluated if none
+// of them is met, for example `a?.b.c?.()` is lowered to:
+//
+//	a === null || a === void 0 ? void 0 : (_b = (_a = a.b).c) === null || _b === void 0 ? void 0 : _b.call(_a)
+//
+// the conditions are tested from left to right, so the short-circuiting is preserved
+func lowerOptChain(l *lowering) {
+	l.on(parser.N_EXPR_CHAIN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.ChainExpr)
+
+		links := make([]parser.Node, 0)
+		cur := n.Expr()
+	loop:
+		for {
+			switch c := cur.(type) {
+			case *parser.MemberExpr:
+				links = append(links, c)
+				cur = c.Obj()
+			case *parser.CallExpr:
+				links = append(links, c)
+				cur = c.Callee()
+			default:
+				break loop
+			}
+		}
+
+		expr := l.text(cur)
+		simple := isSimple(cur)
+		conds := make([]string, 0)
+		var this string // the object of the member expression which is the callee of the next call
+		for i := len(links) - 1; i >= 0; i-- {
+			link := links[i]
+			optional := false
+			switch c := link.(type) {
+			case *parser.MemberExpr:
+				optional = c.Optional()
+			case *parser.CallExpr:
+				optional = c.Optional()
+			}
+			if optional {
+				if !simple {
+					t := l.tmp(vc)
+					conds = append(conds, fmt.Sprintf("(%s = %s) === null || %s === void 0", t, expr, t))
+					expr = t
+				} else {
+					conds = append(conds, fmt.Sprintf("%s === null || %s === void 0", expr, expr))
+				}
+			}
+
+			switch c := link.(type) {
+			case *parser.MemberExpr:
+				obj := expr
+				this = ""
+				// the object is kept to be the receiver of the optional call
+				if i > 0 {
+					if call, ok := links[i-1].(*parser.CallExpr); ok && call.Optional() {
+						if c.Obj().Type() == parser.N_SUPER {
+							this = "this"
+						} else if simple {
+							this = expr
+						} else {
+							this = l.tmp(vc)
+							obj = fmt.Sprintf("(%s = %s)", this, expr)
+						}
+					}
+				}
+				if c.Compute() {
+					expr = fmt.Sprintf("%s[%s]", obj, l.text(c.Prop()))
+				} else {
+					expr = fmt.Sprintf("%s.%s", obj, l.text(c.Prop()))
+				}
+			case *parser.CallExpr:
+				args := make([]string, 0, len(c.Args()))
+				for _, arg := range c.Args() {
+					args = append(args, l.text(arg))
+				}
+				if optional && this != "" {
+					expr = fmt.Sprintf("%s.call(%s)", expr, strings.Join(append([]string{this}, args...), ", "))
+				} else {
+					expr = fmt.Sprintf("%s(%s)", expr, strings.Join(args, ", "))
+				}
+				this = ""
+			}
+			simple = false
+		}
+
+		test := strings.Join(conds, " || ")
+		if u, ok := vc.ParentNode().(*parser.UnaryExpr); ok && u.Op() == parser.T_DELETE && !hasParen(n) {
+			l.replace(vc.Parent, fmt.Sprintf("%s ? true : delete %s", test, expr), precAssign)
+			return
+		}
+		l.replace(vc, fmt.Sprintf("%s ? void 0 : %s", test, expr), precAssign)
+	})
+}
+
+// `a ?? b` to `a !== null && a !== void 0 ? a : b`
+func lowerNullish(l *lowering) {
+	l.on(parser.N_EXPR_BIN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.BinExpr)
+		if n.Op() != parser.T_NULLISH {
+			return
+		}
+		lhs, ref := l.text(n.Lhs()), l.text(n.Lhs())
+		if !isSimple(n.Lhs()) {
+			ref = l.tmp(vc)
+			lhs = fmt.Sprintf("(%s = %s)", ref, lhs)
+		}
+		l.replace(vc, fmt.Sprintf("%s !== null && %s !== void 0 ? %s : %s", lhs, ref, ref, l.text(n.Rhs())), precAssign)
+	})
+}
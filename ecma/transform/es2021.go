@@ -0,0 +1,39 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+)
+
+// `a ||= b` to `a || (a = b)`, the object and the computed key of the member expression
+// are evaluated only once
+func lowerLogicAssign(l *lowering) {
+	l.on(parser.N_EXPR_ASSIGN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.AssignExpr)
+		var op string
+		switch n.Op() {
+		case parser.T_ASSIGN_OR:
+			op = "||"
+		case parser.T_ASSIGN_AND:
+			op = "&&"
+		case parser.T_ASSIGN_NULLISH:
+			op = "??"
+		default:
+			return
+		}
+		lhs, ref := l.reuse(n.Lhs(), vc)
+		l.replace(vc, fmt.Sprintf("%s %s (%s = %s)", lhs, op, ref, l.text(n.Rhs())), precAssign)
+	})
+}
+
+func lowerNumSep(l *lowering) {
+	l.on(parser.N_LIT_NUM, func(node parser.Node, vc *walk.VisitorCtx) {
+		raw := l.code[node.Range().Lo:node.Range().Hi]
+		if strings.IndexByte(raw, '_') != -1 {
+			l.p.Replace(node.Range(), strings.ReplaceAll(raw, "_", ""))
+		}
+	})
+}
@@ -0,0 +1,691 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/astutil"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// the kinds of the private members, they are the values of the argument `kind` of the
+// helpers `__classPrivateFieldGet` and `__classPrivateFieldSet`
+const (
+	pkField    = "f"
+	pkMethod   = "m"
+	pkAccessor = "a"
+)
+
+type privMember struct {
+	static bool
+	kind   string
+
+	// the `WeakMap` of the instance field, the `WeakSet` of the instances which have the
+	// private methods, or the class itself for the static members
+	state string
+
+	fn  string // the function of the method, or the holder `{ value }` of the static field
+	get string
+	set string
+}
+
+type classInfo struct {
+	name string // the name of the class, it's empty if the class is anonymous
+	ref  string // the reference to the class outside of its body
+
+	priv   bool // the private members are lowered
+	inst   bool // the instance fields are moved into the constructor
+	static bool // the static fields and blocks are moved after the class
+
+	privs map[string]*privMember
+	brand string // the `WeakSet` of the instances which have the private methods
+}
+
+type classLowering struct {
+	*lowering
+	infos  map[parser.Node]*classInfo
+	supers map[parser.Node][]*parser.CallExpr // the `super(...)` calls in the constructors
+}
+
+func privKey(key parser.Node) (string, bool) {
+	if id, ok := key.(*parser.Ident); ok && id.IsPrivate() {
+		return id.Val(), true
+	}
+	return "", false
+}
+
+func elemKey(elem parser.Node) parser.Node {
+	switch n := elem.(type) {
+	case *parser.Field:
+		return n.Key()
+	case *parser.Method:
+		return n.Key()
+	}
+	return nil
+}
+
+// the info of the class of `vc`, the class elements which are evaluated in order with the
+// lowered ones are moved as well to keep the order of the evaluation, for example the static
+// blocks are moved with the lowered static fields even if `LW_STATIC_BLOCK` is off
+func (c *classLowering) infoOf(vc *walk.VisitorCtx) *classInfo {
+	node := vc.Node.(*parser.ClassDec)
+	if info, ok := c.infos[node]; ok {
+		return info
+	}
+
+	info := &classInfo{privs: map[string]*privMember{}}
+	c.infos[node] = info
+	if node.Id() != nil {
+		info.name = node.Id().(*parser.Ident).Val()
+	}
+
+	elems := node.Body().(*parser.ClassBody).Elems()
+	for _, elem := range elems {
+		if _, ok := privKey(elemKey(elem)); ok {
+			info.priv = c.lws.Has(LW_CLASS_PRIV)
+			break
+		}
+	}
+	for _, elem := range elems {
+		switch n := elem.(type) {
+		case *parser.Field:
+			_, pvt := privKey(n.Key())
+			lower := c.lws.Has(LW_CLASS_FIELD) || pvt && info.priv
+			if n.Static() {
+				info.static = info.static || lower
+			} else {
+				info.inst = info.inst || lower
+			}
+		case *parser.StaticBlock:
+			info.static = info.static || c.lws.Has(LW_STATIC_BLOCK)
+		case *parser.Method:
+			if _, pvt := privKey(n.Key()); pvt && info.priv {
+				if n.Static() {
+					info.static = true
+				} else {
+					info.inst = true
+				}
+			}
+		}
+	}
+
+	scope := varScopeOf(vc)
+	if info.static || info.priv {
+		if vc.Node.Type() == parser.N_STMT_CLASS && info.name != "" {
+			info.ref = info.name
+		} else {
+			info.ref = c.declare(scope, c.seqName())
+		}
+	}
+
+	prefix := "_" + strings.TrimLeft(info.ref, "_") + "_"
+	declare := func(name string) string {
+		if !info.priv {
+			return ""
+		}
+		return c.declare(scope, c.name(prefix+name))
+	}
+	for _, elem := range elems {
+		name, ok := privKey(elemKey(elem))
+		if !ok {
+			continue
+		}
+		m := info.privs[name]
+		if m == nil {
+			m = &privMember{}
+			info.privs[name] = m
+		}
+		switch n := elem.(type) {
+		case *parser.Field:
+			m.kind, m.static = pkField, n.Static()
+			if m.static {
+				m.state, m.fn = info.ref, declare(name)
+			} else {
+				m.state = declare(name)
+			}
+		case *parser.Method:
+			m.static = n.Static()
+			if m.static {
+				m.state = info.ref
+			} else {
+				if info.brand == "" {
+					info.brand = declare("instances")
+				}
+				m.state = info.brand
+			}
+			switch n.PropKind() {
+			case parser.PK_GETTER:
+				m.kind, m.get = pkAccessor, declare(name+"_get")
+			case parser.PK_SETTER:
+				m.kind, m.set = pkAccessor, declare(name+"_set")
+			default:
+				m.kind, m.fn = pkMethod, declare(name)
+			}
+		}
+	}
+	return info
+}
+
+// the private member referenced by the name in the node of `vc`, it's resolved in the
+// nearest class which declares the name
+func (c *classLowering) resolve(vc *walk.VisitorCtx, name string) (*classInfo, *privMember) {
+	for p := vc.Parent; p != nil; p = p.Parent {
+		if p.Node.Type() == parser.N_CLASS_BODY {
+			info := c.infoOf(p.Parent)
+			if m, ok := info.privs[name]; ok {
+				if !info.priv {
+					return nil, nil
+				}
+				return info, m
+			}
+		}
+	}
+	return nil, nil
+}
+
+// the lowered private member accessed by the member expression
+func (c *classLowering) privOf(node parser.Node, vc *walk.VisitorCtx) *privMember {
+	n, ok := node.(*parser.MemberExpr)
+	if !ok || n.Compute() {
+		return nil
+	}
+	name, ok := privKey(n.Prop())
+	if !ok {
+		return nil
+	}
+	_, m := c.resolve(vc, name)
+	return m
+}
+
+// the private name which is not lowered is invalid in the code moved out of the class body
+func (c *classLowering) checkPriv(vc *walk.VisitorCtx, name string, rng span.Range) {
+	for p := vc; p != nil; {
+		elem, _, cls := elemOf(p, false)
+		if cls == nil {
+			return
+		}
+		info := c.infoOf(cls)
+		if info.moved(elem) {
+			c.errorf(rng, "the private member `#%s` can not be referenced in the class element which is moved out of the class body without lowering the private members", name)
+			return
+		}
+		if _, ok := info.privs[name]; ok {
+			return
+		}
+		p = cls
+	}
+}
+
+func (c *classLowering) get(recv string, m *privMember) string {
+	c.use("__classPrivateFieldGet")
+	switch m.kind {
+	case pkMethod:
+		return fmt.Sprintf(`__classPrivateFieldGet(%s, %s, "m", %s)`, recv, m.state, m.fn)
+	case pkAccessor:
+		return fmt.Sprintf(`__classPrivateFieldGet(%s, %s, "a", %s)`, recv, m.state, orVoid(m.get))
+	}
+	if m.static {
+		return fmt.Sprintf(`__classPrivateFieldGet(%s, %s, "f", %s)`, recv, m.state, m.fn)
+	}
+	return fmt.Sprintf(`__classPrivateFieldGet(%s, %s, "f")`, recv, m.state)
+}
+
+func (c *classLowering) set(recv string, m *privMember, value string) string {
+	c.use("__classPrivateFieldSet")
+	switch m.kind {
+	case pkMethod:
+		return fmt.Sprintf(`__classPrivateFieldSet(%s, %s, %s, "m")`, recv, m.state, value)
+	case pkAccessor:
+		return fmt.Sprintf(`__classPrivateFieldSet(%s, %s, %s, "a", %s)`, recv, m.state, value, orVoid(m.set))
+	}
+	if m.static {
+		return fmt.Sprintf(`__classPrivateFieldSet(%s, %s, %s, "f", %s)`, recv, m.state, value, m.fn)
+	}
+	return fmt.Sprintf(`__classPrivateFieldSet(%s, %s, %s, "f")`, recv, m.state, value)
+}
+
+func orVoid(s string) string {
+	if s == "" {
+		return "void 0"
+	}
+	return s
+}
+
+// the receiver of the member expression, `first` is used at its first occurrence which
+// stores the receiver into a temporary variable if it's not simple
+func (c *classLowering) recv(m *parser.MemberExpr, vc *walk.VisitorCtx) (first, again string) {
+	obj := c.text(m.Obj())
+	if isSimple(m.Obj()) {
+		return obj, obj
+	}
+	t := c.tmp(vc)
+	return t + " = " + obj, t
+}
+
+// the class element contains the node of `vc` and the class of the element, `sub` is the
+// child of the element which contains the node, the walk stops at the functions which
+// rebind `this` if `fnStop` is true
+func elemOf(vc *walk.VisitorCtx, fnStop bool) (elem, sub parser.Node, cls *walk.VisitorCtx) {
+	child, grand := vc.Node, parser.Node(nil)
+	for c := vc.Parent; c != nil; c = c.Parent {
+		switch c.Node.Type() {
+		case parser.N_CLASS_BODY:
+			return child, grand, c.Parent
+		case parser.N_STMT_FN, parser.N_EXPR_FN:
+			if fnStop && c.ParentNode() != nil && c.ParentNode().Type() != parser.N_METHOD {
+				return nil, nil, nil
+			}
+		case parser.N_PROG:
+			return nil, nil, nil
+		}
+		child, grand = c.Node, child
+	}
+	return nil, nil, nil
+}
+
+// whether the code of the class element is moved out of the class body
+func (info *classInfo) moved(elem parser.Node) bool {
+	switch n := elem.(type) {
+	case *parser.Field:
+		return n.Static() && info.static
+	case *parser.StaticBlock:
+		return info.static
+	case *parser.Method:
+		_, pvt := privKey(n.Key())
+		return pvt && info.priv
+	}
+	return false
+}
+
+// the class fields are lowered in the way of `tsc` with `useDefineForClassFields`, for example:
+//
+//	class A { #x = 1; static y = 2; m() { return this.#x; } }
+//
+// is lowered to:
+//
+//	class A { constructor() { _A_x.set(this, 1); } m() { return __classPrivateFieldGet(this, _A_x, "f"); } }
+//	_A_x = new WeakMap();
+//	Object.defineProperty(A, "y", { enumerable: true, configurable: true, writable: true, value: 2 });
+func lowerClassFields(l *lowering) {
+	c := &classLowering{l, map[parser.Node]*classInfo{}, map[parser.Node][]*parser.CallExpr{}}
+
+	l.on(parser.N_EXPR_THIS, func(node parser.Node, vc *walk.VisitorCtx) {
+		elem, sub, cls := elemOf(vc, true)
+		if f, ok := elem.(*parser.Field); ok && f.Val() == sub && c.infoOf(cls).moved(f) {
+			l.p.Replace(node.Range(), c.infoOf(cls).ref)
+		}
+	})
+
+	l.on(parser.N_SUPER, func(node parser.Node, vc *walk.VisitorCtx) {
+		if call, ok := vc.ParentNode().(*parser.CallExpr); ok && call.Callee() == node {
+			fn := thisScopeOf(vc)
+			c.supers[fn] = append(c.supers[fn], call)
+			return
+		}
+		if elem, _, cls := elemOf(vc, true); elem != nil && c.infoOf(cls).moved(elem) {
+			l.errorf(node.Range(), "`super` can not be used in the class element which is moved out of the class body")
+		}
+	})
+
+	// the inner name of the class expression is invisible outside of the class body
+	l.on(parser.N_NAME, func(node parser.Node, vc *walk.VisitorCtx) {
+		id := node.(*parser.Ident)
+		if id.IsPrivate() || !astutil.IsRefIdent(id, vc.ParentNode()) {
+			return
+		}
+		if cls, ok := vc.ParentNode().(*parser.ClassDec); ok && cls.Id() == id {
+			return
+		}
+		for p := vc; p != nil; {
+			elem, _, cls := elemOf(p, false)
+			if cls == nil {
+				return
+			}
+			if info := c.infoOf(cls); info.name == id.Val() {
+				if info.ref != info.name && info.moved(elem) {
+					l.p.Replace(id.Range(), info.ref)
+				}
+				return
+			}
+			p = cls
+		}
+	})
+
+	l.on(parser.N_EXPR_MEMBER, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.MemberExpr)
+		m := c.privOf(n, vc)
+		if m == nil {
+			if name, ok := privKey(n.Prop()); ok {
+				c.checkPriv(vc, name, n.Prop().Range())
+			}
+			return
+		}
+		if n.Optional() {
+			l.errorf(n.Range(), "the optional chaining of the private member can not be lowered without lowering the optional chaining")
+			return
+		}
+		switch p := vc.ParentNode().(type) {
+		case *parser.AssignExpr:
+			if p.Lhs() == n {
+				return
+			}
+		case *parser.UpdateExpr:
+			return
+		case *parser.CallExpr:
+			if p.Callee() == n {
+				return
+			}
+		case *parser.ForInOfStmt:
+			if p.Left() == n {
+				l.errorf(n.Range(), "the private member as the target of the loop can not be lowered")
+				return
+			}
+		case *parser.ArrPat, *parser.ObjPat, *parser.AssignPat, *parser.RestPat, *parser.Prop:
+			if _, ok := p.(*parser.Prop); !ok || vc.Parent.ParentNode().Type() == parser.N_PAT_OBJ {
+				l.errorf(n.Range(), "the private member as the destructuring target can not be lowered")
+				return
+			}
+		}
+		l.p.Replace(n.Range(), c.get(l.text(n.Obj()), m))
+	})
+
+	l.on(parser.N_EXPR_ASSIGN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.AssignExpr)
+		m := c.privOf(n.Lhs(), vc)
+		if m == nil {
+			return
+		}
+		lhs := n.Lhs().(*parser.MemberExpr)
+		rhs := l.text(n.Rhs())
+		if n.Op() == parser.T_ASSIGN {
+			l.replace(vc, c.set(l.text(lhs.Obj()), m, rhs), precUnary)
+			return
+		}
+
+		first, again := c.recv(lhs, vc)
+		switch n.Op() {
+		case parser.T_ASSIGN_OR:
+			l.replace(vc, fmt.Sprintf("%s || %s", c.get(first, m), c.set(again, m, rhs)), precAssign)
+		case parser.T_ASSIGN_AND:
+			l.replace(vc, fmt.Sprintf("%s && %s", c.get(first, m), c.set(again, m, rhs)), precAssign)
+		case parser.T_ASSIGN_NULLISH:
+			t := l.tmp(vc)
+			l.replace(vc, fmt.Sprintf("(%s = %s) !== null && %s !== void 0 ? %s : %s", t, c.get(first, m), t, t, c.set(again, m, rhs)), precAssign)
+		default:
+			if !isSimple(n.Rhs()) && !hasParen(n.Rhs()) {
+				rhs = "(" + rhs + ")"
+			}
+			op := strings.TrimSuffix(n.OpName(), "=")
+			l.replace(vc, c.set(first, m, fmt.Sprintf("%s %s %s", c.get(again, m), op, rhs)), precUnary)
+		}
+	})
+
+	l.on(parser.N_EXPR_UPDATE, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.UpdateExpr)
+		m := c.privOf(n.Arg(), vc)
+		if m == nil {
+			return
+		}
+		first, again := c.recv(n.Arg().(*parser.MemberExpr), vc)
+		t := l.tmp(vc)
+		if n.Prefix() {
+			l.replace(vc, c.set(first, m, fmt.Sprintf("(%s = %s, %s%s)", t, c.get(again, m), n.OpText(), t)), precUnary)
+			return
+		}
+		r := l.tmp(vc)
+		l.replace(vc, fmt.Sprintf("(%s, %s)", c.set(first, m, fmt.Sprintf("(%s = %s, %s = %s%s, %s)", t, c.get(again, m), r, t, n.OpText(), t)), r), precUnary)
+	})
+
+	l.on(parser.N_EXPR_CALL, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.CallExpr)
+		m := c.privOf(n.Callee(), vc)
+		if m == nil {
+			return
+		}
+		if n.Optional() {
+			l.errorf(n.Range(), "the optional call of the private method can not be lowered without lowering the optional chaining")
+			return
+		}
+		first, again := c.recv(n.Callee().(*parser.MemberExpr), vc)
+		args := []string{again}
+		for _, arg := range n.Args() {
+			args = append(args, l.text(arg))
+		}
+		l.p.Replace(n.Range(), fmt.Sprintf("%s.call(%s)", c.get(first, m), strings.Join(args, ", ")))
+	})
+
+	// `#x in obj`
+	l.on(parser.N_EXPR_BIN, func(node parser.Node, vc *walk.VisitorCtx) {
+		n := node.(*parser.BinExpr)
+		if n.Op() != parser.T_IN {
+			return
+		}
+		name, ok := privKey(n.Lhs())
+		if !ok {
+			return
+		}
+		_, m := c.resolve(vc, name)
+		if m == nil {
+			c.checkPriv(vc, name, n.Lhs().Range())
+			return
+		}
+		l.use("__classPrivateFieldIn")
+		l.replace(vc, fmt.Sprintf("__classPrivateFieldIn(%s, %s)", m.state, l.text(n.Rhs())), precUnary)
+	})
+
+	l.on(parser.N_STMT_CLASS, c.lower)
+	l.on(parser.N_EXPR_CLASS, c.lower)
+}
+
+func (c *classLowering) lower(node parser.Node, vc *walk.VisitorCtx) {
+	n := node.(*parser.ClassDec)
+	info := c.infoOf(vc)
+	if !info.inst && !info.static && !info.priv {
+		return
+	}
+
+	// the statements to run before the class, and the ones to run after the class
+	before, states, defs, statics, inits := []string{}, []string{}, []string{}, []string{}, []string{}
+	if info.brand != "" {
+		states = append(states, info.brand+" = new WeakSet()")
+		inits = append(inits, info.brand+".add(this)")
+	}
+
+	var ctor *parser.Method
+	body := n.Body().(*parser.ClassBody)
+	for _, elem := range body.Elems() {
+		switch e := elem.(type) {
+		case *parser.Method:
+			if e.PropKind() == parser.PK_CTOR {
+				ctor = e
+				continue
+			}
+			name, pvt := privKey(e.Key())
+			if !pvt || !info.priv {
+				continue
+			}
+			m := info.privs[name]
+			fn := m.fn
+			switch e.PropKind() {
+			case parser.PK_GETTER:
+				fn = m.get
+			case parser.PK_SETTER:
+				fn = m.set
+			}
+			defs = append(defs, fmt.Sprintf("%s = %s", fn, c.fnText(e, fn)))
+			c.removeElem(e)
+
+		case *parser.Field:
+			if e.Static() && !info.static || !e.Static() && !info.inst {
+				continue
+			}
+			name, pvt := privKey(e.Key())
+			if pvt && !info.priv {
+				continue
+			}
+			val := "void 0"
+			if e.Val() != nil {
+				val = c.text(e.Val())
+			}
+			var init string
+			if pvt {
+				m := info.privs[name]
+				if m.static {
+					init = fmt.Sprintf("%s = { value: %s }", m.fn, val)
+				} else {
+					states = append(states, m.state+" = new WeakMap()")
+					init = fmt.Sprintf("%s.set(this, %s)", m.state, val)
+				}
+			} else {
+				recv := "this"
+				if e.Static() {
+					recv = info.ref
+				}
+				init = fmt.Sprintf("Object.defineProperty(%s, %s, { enumerable: true, configurable: true, writable: true, value: %s })",
+					recv, c.fieldKey(e, vc, &before), val)
+			}
+			if e.Static() {
+				statics = append(statics, init)
+			} else {
+				inits = append(inits, init)
+			}
+			c.removeElem(e)
+
+		case *parser.StaticBlock:
+			if !info.static {
+				continue
+			}
+			lo := e.Range().Lo + uint32(strings.IndexByte(c.code[e.Range().Lo:], '{'))
+			statics = append(statics, fmt.Sprintf("(function () %s).call(%s)", c.p.Text(span.Range{Lo: lo, Hi: e.Range().Hi}), info.ref))
+			c.removeElem(e)
+		}
+	}
+
+	if len(inits) > 0 {
+		c.initCtor(n, body, ctor, inits)
+	}
+
+	after := append(append(states, defs...), statics...)
+	if len(before) == 0 && len(after) == 0 {
+		return
+	}
+
+	if vc.Node.Type() == parser.N_STMT_CLASS && info.ref == info.name {
+		rng := n.Range()
+		if ex, ok := vc.ParentNode().(*parser.ExportDec); ok {
+			rng = ex.Range()
+		}
+		indent := "\n" + util.IndentOf(c.code, rng.Lo)
+		text := c.p.Text(rng)
+		if len(before) > 0 {
+			text = strings.Join(before, ";"+indent) + ";" + indent + text
+		}
+		if len(after) > 0 {
+			text += indent + strings.Join(after, ";"+indent) + ";"
+		}
+		c.p.Replace(rng, text)
+		return
+	}
+
+	seq := append(before, info.ref+" = "+c.p.Text(n.Range()))
+	seq = append(append(seq, after...), info.ref)
+	c.p.Replace(n.Range(), "("+strings.Join(seq, ", ")+")")
+}
+
+// removes the class element, the line is removed as well if it's occupied by the element
+func (c *classLowering) removeElem(elem parser.Node) {
+	rng := elem.Range()
+	lo := strings.LastIndexAny(c.code[:rng.Lo], "\r\n") + 1
+	hi := skipSpaces(c.code, rng.Hi)
+	if strings.TrimSpace(c.code[lo:rng.Lo]) == "" && strings.ContainsAny(c.code[rng.Hi:hi], "\r\n") {
+		nl := strings.IndexAny(c.code[rng.Hi:], "\r\n")
+		c.p.Remove(span.Range{Lo: uint32(lo), Hi: rng.Hi + uint32(nl) + 1})
+		return
+	}
+	c.p.Remove(span.Range{Lo: rng.Lo, Hi: hi})
+}
+
+// the key of the field which is used as the argument of `Object.defineProperty`, the computed
+// key is evaluated before the class
+func (c *classLowering) fieldKey(f *parser.Field, vc *walk.VisitorCtx, before *[]string) string {
+	key := f.Key()
+	if f.Computed() {
+		switch key.Type() {
+		case parser.N_LIT_STR, parser.N_LIT_NUM:
+			return c.text(key)
+		}
+		t := c.tmp(vc)
+		*before = append(*before, t+" = "+c.text(key))
+		return t
+	}
+	if id, ok := key.(*parser.Ident); ok {
+		return jsQuote(id.Val())
+	}
+	return c.text(key)
+}
+
+// the function expression of the private method
+func (c *classLowering) fnText(m *parser.Method, name string) string {
+	head := c.code[m.Range().Lo:m.Key().Range().Lo]
+	fn := "function "
+	if strings.IndexByte(head, '*') != -1 {
+		fn = "function* "
+	}
+	if reAsyncKw.MatchString(head) {
+		fn = "async " + fn
+	}
+	return fn + name + c.text(m.Val())
+}
+
+// inserts the initializers of the instance fields into the constructor, they are run right
+// after `super(...)` in the derived class
+func (c *classLowering) initCtor(n *parser.ClassDec, body *parser.ClassBody, ctor *parser.Method, inits []string) {
+	stmts := strings.Join(inits, "; ") + ";"
+	if ctor == nil {
+		ctor := "constructor() { " + stmts + " }"
+		if n.Super() != nil {
+			ctor = "constructor(...args) { super(...args); " + stmts + " }"
+		}
+		c.p.Insert(body.Range().Lo+1, " "+ctor)
+		return
+	}
+
+	fn := ctor.Val().(*parser.FnDec)
+	block := fn.Body().(*parser.BlockStmt)
+	names := map[string]bool{}
+	for _, param := range fn.Params() {
+		patNames(param, names)
+	}
+	for _, init := range inits {
+		for _, name := range reName.FindAllString(init, -1) {
+			if names[name] {
+				c.ctx.Warn(ctor.Range(), fmt.Sprintf("the field initializer referencing `%s` is shadowed by the parameter of the constructor", name))
+				names[name] = false
+			}
+		}
+	}
+
+	if n.Super() == nil {
+		c.prepend(block, stmts)
+		return
+	}
+	calls := c.supers[fn]
+	if len(calls) == 1 {
+		for _, stmt := range block.Body() {
+			if es, ok := stmt.(*parser.ExprStmt); ok && es.Expr() == calls[0] {
+				sep := " "
+				if c.code[stmt.Range().Hi-1] != ';' {
+					sep = "; "
+				}
+				c.p.Insert(stmt.Range().Hi, sep+stmts)
+				return
+			}
+		}
+	}
+	for _, call := range calls {
+		c.p.Replace(call.Range(), fmt.Sprintf("(%s, %s, this)", c.p.Text(call.Range()), strings.Join(inits, ", ")))
+	}
+}
@@ -0,0 +1,246 @@
+package transform
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// an edit replaces the source in range `[lo, hi)` with `text`, it's an insertion if
+// `lo` equals to `hi`
+type edit struct {
+	lo   uint32
+	hi   uint32
+	text string
+	seq  int
+}
+
+func (e *edit) insertion() bool {
+	return e.lo == e.hi
+}
+
+// the nodes in AST are immutable outside the `parser` package, so instead of building a new
+// tree and printing it from scratch, `Printer` records the edits against the original source
+// and prints the source with these edits applied, the benefits of this manner are:
+//
+//   - the untouched source text including its comments and formatting is kept as it is
+//   - the source map can be generated straightforwardly since every piece of the output
+//     is either copied from the source or produced by an edit which has its origin
+//
+// the later edit supersedes the earlier ones it covers, so the common pattern to rewrite
+// a node is to take its transformed children by `Text` and then `Replace` the entire node
+type Printer struct {
+	src   *span.Source
+	li    *LineIndex
+	edits []*edit
+	seq   int
+}
+
+func NewPrinter(src *span.Source) *Printer {
+	return &Printer{
+		src:   src,
+		li:    NewLineIndex(src.Text(0, uint32(src.Len()))),
+		edits: make([]*edit, 0),
+	}
+}
+
+func (p *Printer) Source() *span.Source {
+	return p.src
+}
+
+func (p *Printer) LineIndex() *LineIndex {
+	return p.li
+}
+
+func (p *Printer) Changed() bool {
+	return len(p.edits) > 0
+}
+
+func (p *Printer) add(lo, hi uint32, text string) {
+	if lo < hi {
+		es := p.edits[:0]
+		for _, e := range p.edits {
+			if e.lo >= lo && e.hi <= hi && !(e.insertion() && e.lo == hi) {
+				continue
+			}
+			es = append(es, e)
+		}
+		p.edits = es
+	}
+	p.seq += 1
+	p.edits = append(p.edits, &edit{lo, hi, text, p.seq})
+}
+
+func (p *Printer) Remove(rng span.Range) {
+	if rng.Lo >= rng.Hi {
+		return
+	}
+	p.add(rng.Lo, rng.Hi, "")
+}
+
+func (p *Printer) Replace(rng span.Range, text string) {
+	p.add(rng.Lo, rng.Hi, text)
+}
+
+// insert `text` at the offset `ofst` of the source, the insertions at the same offset
+// are printed in the order they are added
+func (p *Printer) Insert(ofst uint32, text string) {
+	if text == "" {
+		return
+	}
+	p.add(ofst, ofst, text)
+}
+
+func (p *Printer) sorted() []*edit {
+	es := make([]*edit, len(p.edits))
+	copy(es, p.edits)
+	sort.SliceStable(es, func(i, j int) bool {
+		a, b := es[i], es[j]
+		if a.lo != b.lo {
+			return a.lo < b.lo
+		}
+		if a.insertion() != b.insertion() {
+			return a.insertion()
+		}
+		if a.hi != b.hi {
+			return a.hi > b.hi
+		}
+		return a.seq < b.seq
+	})
+	return es
+}
+
+type emitter interface {
+	copy(lo, hi uint32)
+	write(text string, origin uint32)
+}
+
+// the insertions at the end of the range are excluded unless `tail` is true, since they are
+// considered to be after the range, for example the statements inserted after a node
+func (p *Printer) render(rng span.Range, em emitter, tail bool) {
+	cur := rng.Lo
+	for _, e := range p.sorted() {
+		if e.lo < rng.Lo || e.hi > rng.Hi || (!tail && e.insertion() && e.lo == rng.Hi && rng.Lo != rng.Hi) {
+			continue
+		}
+		if e.lo < cur {
+			continue
+		}
+		em.copy(cur, e.lo)
+		em.write(e.text, e.lo)
+		cur = e.hi
+	}
+	em.copy(cur, rng.Hi)
+}
+
+type textEmitter struct {
+	src *span.Source
+	sb  strings.Builder
+}
+
+func (t *textEmitter) copy(lo, hi uint32) {
+	if lo < hi {
+		t.sb.WriteString(t.src.Text(lo, hi))
+	}
+}
+
+func (t *textEmitter) write(text string, origin uint32) {
+	t.sb.WriteString(text)
+}
+
+type mapEmitter struct {
+	textEmitter
+	li  *LineIndex
+	pos genPos
+	smb *SourceMapBuilder
+}
+
+func (m *mapEmitter) mark(ofst uint32) {
+	line, col := m.li.Pos(int(ofst))
+	m.smb.Add(Mapping{m.pos.line, m.pos.col, line, col})
+}
+
+func (m *mapEmitter) copy(lo, hi uint32) {
+	if lo >= hi {
+		return
+	}
+	text := m.src.Text(lo, hi)
+	m.mark(lo)
+	m.textEmitter.copy(lo, hi)
+
+	// map each line start in the copied chunk back to the source
+	ofst := lo
+	for len(text) > 0 {
+		i := strings.IndexAny(text, "\r\n")
+		if i == -1 {
+			m.pos.advance(text)
+			break
+		}
+		if text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n' {
+			i++
+		}
+		m.pos.advance(text[:i+1])
+		ofst += uint32(i + 1)
+		text = text[i+1:]
+		if len(text) > 0 {
+			m.mark(ofst)
+		}
+	}
+}
+
+func (m *mapEmitter) write(text string, origin uint32) {
+	if text == "" {
+		return
+	}
+	m.mark(origin)
+	m.textEmitter.write(text, origin)
+	m.pos.advance(text)
+}
+
+// returns the text in the given range with the edits inside it applied
+func (p *Printer) Text(rng span.Range) string {
+	em := &textEmitter{src: p.src}
+	p.render(rng, em, false)
+	return em.sb.String()
+}
+
+func (p *Printer) NodeText(node interface{ Range() span.Range }) string {
+	return p.Text(node.Range())
+}
+
+func (p *Printer) all() span.Range {
+	return span.Range{Lo: 0, Hi: uint32(p.src.Len())}
+}
+
+func (p *Printer) String() string {
+	em := &textEmitter{src: p.src}
+	p.render(p.all(), em, true)
+	return em.sb.String()
+}
+
+// prints the entire source with the edits applied as well as the source map
+// of the output, `file` is the name of the generated file
+func (p *Printer) Print(file string) (string, *SourceMap) {
+	smb := NewSourceMapBuilder(file, p.src)
+	em := &mapEmitter{textEmitter{src: p.src}, p.li, genPos{}, smb}
+	p.render(p.all(), em, true)
+	return em.sb.String(), smb.Build()
+}
+
+// the ranges of the source which are changed by the edits, the overlapped
+// and the adjacent ranges are merged
+func (p *Printer) ChangedRanges() []span.Range {
+	ret := []span.Range{}
+	for _, e := range p.sorted() {
+		n := len(ret)
+		if n > 0 && e.lo <= ret[n-1].Hi {
+			if e.hi > ret[n-1].Hi {
+				ret[n-1].Hi = e.hi
+			}
+			continue
+		}
+		ret = append(ret, span.Range{Lo: e.lo, Hi: e.hi})
+	}
+	return ret
+}
@@ -0,0 +1,64 @@
+package transform
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+)
+
+// creates the transformers to run for a registered transform, a transform may consist of
+// more than one transformers such as the downleveling
+type Factory func() []Transformer
+
+var (
+	registry     = map[string]Factory{}
+	registryLock sync.RWMutex
+)
+
+// registers the transform by its name to make it available to the tools like `mole codemod`,
+// the go plugins register their transforms in their `init` functions which are run once the
+// plugins are loaded
+func Register(name string, factory Factory) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry[name] = factory
+}
+
+// returns the transformers of the registered transform, `ok` is false if it's not registered
+func Lookup(name string) ([]Transformer, bool) {
+	registryLock.RLock()
+	factory, ok := registry[name]
+	registryLock.RUnlock()
+	if !ok {
+		return nil, false
+	}
+	return factory(), true
+}
+
+// the names of the registered transforms in lexical order
+func Registered() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func init() {
+	Register("ts-strip", func() []Transformer {
+		return []Transformer{NewTsStrip(nil)}
+	})
+	Register("decorators", func() []Transformer {
+		return []Transformer{NewDecorators(nil)}
+	})
+	Register("downlevel", func() []Transformer {
+		return Downlevel(NewDownlevelOpts(parser.ES5))
+	})
+	Register("cjs-to-esm", func() []Transformer {
+		return []Transformer{NewCjsToEsm()}
+	})
+}
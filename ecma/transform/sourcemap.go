@@ -0,0 +1,260 @@
+package transform
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// the revision 3 of the source map format, refer:
+// https://sourcemaps.info/spec.html
+type SourceMap struct {
+	Version        int      `json:"version"`
+	File           string   `json:"file,omitempty"`
+	SourceRoot     string   `json:"sourceRoot,omitempty"`
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent,omitempty"`
+	Names          []string `json:"names"`
+	Mappings       string   `json:"mappings"`
+}
+
+func (m *SourceMap) JSON() string {
+	b, _ := json.Marshal(m)
+	return string(b)
+}
+
+// the inline form which can be appended to the end of the generated code
+func (m *SourceMap) Comment() string {
+	return "//# sourceMappingURL=data:application/json;charset=utf-8;base64," + base64.StdEncoding.EncodeToString([]byte(m.JSON()))
+}
+
+// a segment of the mappings, all the positions are 0-based and the columns are
+// counted in UTF-16 code units as the spec requires
+type Mapping struct {
+	GenLine int
+	GenCol  int
+	SrcLine int
+	SrcCol  int
+}
+
+// resolve the `[line, column]` of the offsets in source, the line starts are
+// calculated once and then be used for the binary search
+type LineIndex struct {
+	code   string
+	starts []int
+}
+
+func NewLineIndex(code string) *LineIndex {
+	starts := []int{0}
+	for i := 0; i < len(code); i++ {
+		c := code[i]
+		if c == '\n' {
+			starts = append(starts, i+1)
+		} else if c == '\r' {
+			if i+1 < len(code) && code[i+1] == '\n' {
+				i++
+			}
+			starts = append(starts, i+1)
+		}
+	}
+	return &LineIndex{code, starts}
+}
+
+// returns the 0-based line and the 0-based column in UTF-16 code units
+func (li *LineIndex) Pos(ofst int) (int, int) {
+	line := sort.Search(len(li.starts), func(i int) bool { return li.starts[i] > ofst }) - 1
+	if line < 0 {
+		line = 0
+	}
+	return line, utf16Len(li.code[li.starts[line]:ofst])
+}
+
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n += 1
+		}
+	}
+	return n
+}
+
+// tracks the generated position while the output is being produced
+type genPos struct {
+	line int
+	col  int
+}
+
+func (g *genPos) advance(s string) {
+	for len(s) > 0 {
+		i := strings.IndexAny(s, "\r\n")
+		if i == -1 {
+			g.col += utf16Len(s)
+			return
+		}
+		if s[i] == '\r' && i+1 < len(s) && s[i+1] == '\n' {
+			i++
+		}
+		g.line += 1
+		g.col = 0
+		s = s[i+1:]
+	}
+}
+
+type SourceMapBuilder struct {
+	file     string
+	src      *span.Source
+	mappings []Mapping
+}
+
+func NewSourceMapBuilder(file string, src *span.Source) *SourceMapBuilder {
+	return &SourceMapBuilder{file: file, src: src}
+}
+
+func (b *SourceMapBuilder) Add(m Mapping) {
+	n := len(b.mappings)
+	if n > 0 {
+		last := b.mappings[n-1]
+		if last.GenLine == m.GenLine && last.GenCol == m.GenCol {
+			b.mappings[n-1] = m
+			return
+		}
+	}
+	b.mappings = append(b.mappings, m)
+}
+
+func (b *SourceMapBuilder) Mappings() []Mapping {
+	return b.mappings
+}
+
+func (b *SourceMapBuilder) Build() *SourceMap {
+	sm := &SourceMap{
+		Version:  3,
+		File:     b.file,
+		Sources:  []string{b.src.Path},
+		Names:    []string{},
+		Mappings: EncodeMappings(b.mappings),
+	}
+	sm.SourcesContent = []string{b.src.Text(0, uint32(b.src.Len()))}
+	return sm
+}
+
+// encode the mappings into the `mappings` field of the source map, all the mappings
+// are considered to be from the first source
+func EncodeMappings(ms []Mapping) string {
+	sb := strings.Builder{}
+	line, prevGenCol, prevSrcLine, prevSrcCol := 0, 0, 0, 0
+	first := true
+	for _, m := range ms {
+		for line < m.GenLine {
+			sb.WriteByte(';')
+			line++
+			prevGenCol = 0
+			first = true
+		}
+		if !first {
+			sb.WriteByte(',')
+		}
+		first = false
+		vlqEncode(&sb, m.GenCol-prevGenCol)
+		vlqEncode(&sb, 0)
+		vlqEncode(&sb, m.SrcLine-prevSrcLine)
+		vlqEncode(&sb, m.SrcCol-prevSrcCol)
+		prevGenCol, prevSrcLine, prevSrcCol = m.GenCol, m.SrcLine, m.SrcCol
+	}
+	return sb.String()
+}
+
+// decode the `mappings` field, the source index and the names are ignored
+func DecodeMappings(s string) []Mapping {
+	ret := []Mapping{}
+	line, genCol, srcLine, srcCol := 0, 0, 0, 0
+	for _, ln := range strings.Split(s, ";") {
+		genCol = 0
+		for _, seg := range strings.Split(ln, ",") {
+			if seg == "" {
+				continue
+			}
+			fields := vlqDecode(seg)
+			if len(fields) < 4 {
+				continue
+			}
+			genCol += fields[0]
+			srcLine += fields[2]
+			srcCol += fields[3]
+			ret = append(ret, Mapping{line, genCol, srcLine, srcCol})
+		}
+		line++
+	}
+	return ret
+}
+
+// chain two mappings, `outer` maps the final output to an intermediate output and `inner`
+// maps that intermediate output to the original source, the result maps the final output to
+// the original source directly
+//
+// this is used when multiple transforms run one after the other
+func ComposeMappings(outer, inner []Mapping) []Mapping {
+	ret := make([]Mapping, 0, len(outer))
+	for _, m := range outer {
+		i := sort.Search(len(inner), func(i int) bool {
+			im := inner[i]
+			return im.GenLine > m.SrcLine || (im.GenLine == m.SrcLine && im.GenCol > m.SrcCol)
+		}) - 1
+		if i < 0 || inner[i].GenLine != m.SrcLine {
+			continue
+		}
+		im := inner[i]
+		ret = append(ret, Mapping{m.GenLine, m.GenCol, im.SrcLine, im.SrcCol + (m.SrcCol - im.GenCol)})
+	}
+	return ret
+}
+
+const b64chars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+func vlqEncode(sb *strings.Builder, v int) {
+	if v < 0 {
+		v = (-v << 1) | 1
+	} else {
+		v <<= 1
+	}
+	for {
+		digit := v & 31
+		v >>= 5
+		if v > 0 {
+			digit |= 32
+		}
+		sb.WriteByte(b64chars[digit])
+		if v == 0 {
+			break
+		}
+	}
+}
+
+func vlqDecode(s string) []int {
+	ret := []int{}
+	v, shift := 0, 0
+	for _, c := range s {
+		digit := strings.IndexRune(b64chars, c)
+		if digit < 0 {
+			return ret
+		}
+		v += (digit & 31) << shift
+		if digit&32 != 0 {
+			shift += 5
+			continue
+		}
+		if v&1 != 0 {
+			ret = append(ret, -(v >> 1))
+		} else {
+			ret = append(ret, v>>1)
+		}
+		v, shift = 0, 0
+	}
+	return ret
+}
@@ -0,0 +1,52 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/span"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func TestMappingsRoundTrip(t *testing.T) {
+	ms := []Mapping{
+		{0, 0, 0, 0},
+		{0, 4, 0, 10},
+		{2, 1, 5, 3},
+		{2, 30, 1, 0},
+		{3, 0, 100, 1000},
+	}
+	AssertEqual(t, ms, DecodeMappings(EncodeMappings(ms)), "should be ok")
+	AssertEqual(t, "AAAA,CAAC;AAAD", EncodeMappings([]Mapping{{0, 0, 0, 0}, {0, 1, 0, 1}, {1, 0, 0, 0}}), "should be ok")
+}
+
+func TestComposeMappings(t *testing.T) {
+	inner := []Mapping{{0, 0, 1, 0}, {0, 6, 1, 14}}
+	outer := []Mapping{{0, 0, 0, 0}, {1, 2, 0, 8}}
+	AssertEqual(t, []Mapping{{0, 0, 1, 0}, {1, 2, 1, 16}}, ComposeMappings(outer, inner), "should be ok")
+}
+
+func TestLineIndexUtf16(t *testing.T) {
+	li := NewLineIndex("a\r\n😀b\nc")
+	line, col := li.Pos(7)
+	AssertEqual(t, 1, line, "should be ok")
+	AssertEqual(t, 2, col, "should be ok")
+}
+
+func TestPrinterEdits(t *testing.T) {
+	p := NewPrinter(span.NewSource("", "let a = b + c;"))
+	p.Replace(span.Range{Lo: 8, Hi: 9}, "x")
+	p.Insert(13, " * 2")
+	AssertEqual(t, "x + c * 2;", p.Text(span.Range{Lo: 8, Hi: 14}), "should be ok")
+
+	// the outer edit supersedes the inner ones
+	p.Replace(span.Range{Lo: 8, Hi: 13}, "(x + c)")
+	AssertEqual(t, "let a = (x + c) * 2;", p.String(), "should be ok")
+	AssertEqual(t, []span.Range{{Lo: 8, Hi: 13}}, p.ChangedRanges(), "should be ok")
+}
+
+func TestPrinterInsertAtEnd(t *testing.T) {
+	p := NewPrinter(span.NewSource("", "a"))
+	p.Insert(1, ";")
+	AssertEqual(t, "a", p.Text(span.Range{Lo: 0, Hi: 1}), "should be ok")
+	AssertEqual(t, "a;", p.String(), "should be ok")
+}
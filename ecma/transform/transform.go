@@ -0,0 +1,175 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/span"
+)
+
+// the context of a transform, it holds the parsed result of the source and the
+// printer to record the edits
+type Ctx struct {
+	Parser  *parser.Parser
+	Ast     parser.Node
+	Printer *Printer
+
+	// the problems found during the transform which do not stop the transform,
+	// such as the constructs can not be transformed safely
+	Warnings []*Warning
+}
+
+func NewCtx(p *parser.Parser, ast parser.Node) *Ctx {
+	return &Ctx{
+		Parser:   p,
+		Ast:      ast,
+		Printer:  NewPrinter(p.Source()),
+		Warnings: make([]*Warning, 0),
+	}
+}
+
+func (c *Ctx) Warn(rng span.Range, msg string) {
+	c.Warnings = append(c.Warnings, &Warning{c.Parser.Source(), rng, msg})
+}
+
+type Warning struct {
+	src *span.Source
+	Rng span.Range
+	Msg string
+}
+
+func (w *Warning) Error() string {
+	pos := w.src.OfstLineCol(w.Rng.Lo)
+	return fmt.Sprintf("%s at (%d:%d)", w.Msg, pos.Line, pos.Col)
+}
+
+type Transformer interface {
+	Name() string
+
+	// adjust the options used to parse the source before the transform
+	ParserOpts(opts *parser.ParserOpts)
+
+	// records the edits into `ctx.Printer`
+	Transform(ctx *Ctx) error
+}
+
+type Result struct {
+	Code     string
+	Map      *SourceMap
+	Warnings []*Warning
+}
+
+// runs the transformers one by one, each transformer works on the output of its predecessor
+// and the source maps of all the passes are composed into a single one which maps the final
+// output to the original source
+func Run(file, code string, opts *parser.ParserOpts, transformers ...Transformer) (*Result, error) {
+	if opts == nil {
+		opts = parser.NewParserOpts()
+	}
+	orig := span.NewSource(file, code)
+	ret := &Result{Code: code, Warnings: make([]*Warning, 0)}
+
+	var mappings []Mapping
+	for _, t := range transformers {
+		o := opts.Clone()
+		t.ParserOpts(o)
+
+		s := span.NewSource(file, ret.Code)
+		p := parser.NewParser(s, o)
+		ast, err := p.Prog()
+		if err != nil {
+			return nil, err
+		}
+
+		ctx := NewCtx(p, ast)
+		if err := t.Transform(ctx); err != nil {
+			return nil, err
+		}
+		ret.Warnings = append(ret.Warnings, ctx.Warnings...)
+
+		if !ctx.Printer.Changed() {
+			continue
+		}
+
+		out, sm := ctx.Printer.Print(file)
+		ms := DecodeMappings(sm.Mappings)
+		if mappings == nil {
+			mappings = ms
+		} else {
+			mappings = ComposeMappings(ms, mappings)
+		}
+		ret.Code = out
+	}
+
+	if mappings == nil {
+		mappings = identityMappings(orig)
+	}
+	smb := NewSourceMapBuilder(file, orig)
+	smb.mappings = mappings
+	ret.Map = smb.Build()
+	return ret, nil
+}
+
+func identityMappings(s *span.Source) []Mapping {
+	ms := []Mapping{}
+	li := NewLineIndex(s.Text(0, uint32(s.Len())))
+	for i := range li.starts {
+		ms = append(ms, Mapping{i, 0, i, 0})
+	}
+	return ms
+}
+
+func skipSpaces(code string, ofst uint32) uint32 {
+	for int(ofst) < len(code) && strings.IndexByte(" \t\r\n", code[ofst]) != -1 {
+		ofst++
+	}
+	return ofst
+}
+
+func skipSpacesBackward(code string, ofst uint32) uint32 {
+	for ofst > 0 && strings.IndexByte(" \t\r\n", code[ofst-1]) != -1 {
+		ofst--
+	}
+	return ofst
+}
+
+// the runtime helper which is injected into the output once it's used, the helpers are
+// written in es5 so they are untouched by the subsequent passes
+type helper struct {
+	name string
+	code string
+}
+
+// inserts the used helpers at the beginning of the program, after the directives if any
+func injectHelpers(ctx *Ctx, helpers []*helper, used map[string]bool) {
+	code := make([]string, 0)
+	for _, h := range helpers {
+		if used[h.name] {
+			code = append(code, h.code)
+		}
+	}
+	if len(code) == 0 {
+		return
+	}
+
+	ofst := directivesEnd(ctx.Ast.(*parser.Prog).Body())
+	if ofst > 0 {
+		ctx.Printer.Insert(ofst, "\n"+strings.Join(code, "\n"))
+	} else {
+		ctx.Printer.Insert(0, strings.Join(code, "\n")+"\n")
+	}
+}
+
+// the end offset of the directive prologue of the statements, it's `0` if there is no directive
+func directivesEnd(stmts []parser.Node) uint32 {
+	var ofst uint32
+	for _, stmt := range stmts {
+		if es, ok := stmt.(*parser.ExprStmt); ok && es.Dir() {
+			ofst = stmt.Range().Hi
+			continue
+		}
+		break
+	}
+	return ofst
+}
@@ -0,0 +1,1030 @@
+package transform
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hsiaosiyuan0/mole/ecma/astutil"
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/ecma/walk"
+	"github.com/hsiaosiyuan0/mole/span"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+type TsStripOpts struct {
+	// the imports which are not referenced as values are elided by default since they are
+	// likely to be used as types only, which is also what `tsc` does, turn this on to keep
+	// them for the side effects of the imported modules
+	KeepUnusedImports bool
+
+	// the local name of the jsx factory, it's considered to be referenced as value if
+	// there are jsx elements in the source
+	JsxFactory string
+}
+
+func NewTsStripOpts() *TsStripOpts {
+	return &TsStripOpts{JsxFactory: "React"}
+}
+
+// strips the typescript specific syntax to produce the plain javascript, the type-only
+// constructs are removed and the ones have runtime semantics, which are enums, parameter
+// properties and namespaces, are compiled to their javascript equivalents
+type TsStrip struct {
+	opts *TsStripOpts
+}
+
+func NewTsStrip(opts *TsStripOpts) *TsStrip {
+	if opts == nil {
+		opts = NewTsStripOpts()
+	}
+	return &TsStrip{opts}
+}
+
+func (t *TsStrip) Name() string {
+	return "ts-strip"
+}
+
+func (t *TsStrip) ParserOpts(opts *parser.ParserOpts) {
+	opts.Feature = opts.Feature.On(parser.FEAT_TS)
+}
+
+func (t *TsStrip) Transform(ctx *Ctx) error {
+	s := &tsStripper{
+		ctx:      ctx,
+		opts:     t.opts,
+		p:        ctx.Printer,
+		code:     ctx.Printer.Source().Text(0, uint32(ctx.Printer.Source().Len())),
+		typNames: map[string]bool{},
+		valRefs:  map[string]bool{},
+		imports:  make([]*parser.ImportDec, 0),
+		ns:       make([]string, 0),
+		declared: map[parser.Node]map[string]bool{},
+	}
+	s.run()
+	return nil
+}
+
+type tsStripper struct {
+	ctx  *Ctx
+	opts *TsStripOpts
+	p    *Printer
+	code string
+
+	typNames map[string]bool // the top-level names which only declare types
+	valRefs  map[string]bool // the names referenced as values
+	imports  []*parser.ImportDec
+	hasJsx   bool
+
+	esm          parser.Node // the first import or export declaration which is not type-only
+	exportAssign parser.Node // the `export = x`
+
+	ns       []string                        // names of the enclosing namespaces
+	enum     *tsEnumScope                    // the enum whose member initializers are being visited
+	declared map[parser.Node]map[string]bool // the names declared in the statement lists
+}
+
+type tsEnumScope struct {
+	name    string
+	members map[string]bool
+}
+
+func (s *tsStripper) run() {
+	s.collectTypNames()
+
+	ctx := walk.NewWalkCtx(s.ctx.Ast, s.ctx.Parser.Symtab())
+	walk.AddBeforeListener(&ctx.Listeners, &walk.Listener{
+		Id:     "ts-strip-typinfo",
+		Handle: s.stripTypInfo,
+	})
+	walk.AddNodeBeforeListener(&ctx.Listeners, parser.N_NAME, &walk.Listener{
+		Id:     "ts-strip-name",
+		Handle: s.onName,
+	})
+	walk.AddNodeBeforeListener(&ctx.Listeners, parser.N_JSX_ID, &walk.Listener{
+		Id: "ts-strip-jsx-id",
+		Handle: func(node parser.Node, key string, ctx *walk.VisitorCtx) {
+			s.hasJsx = true
+			s.valRefs[node.(*parser.JsxIdent).Val()] = true
+		},
+	})
+
+	for _, t := range []parser.NodeType{
+		parser.N_TS_INTERFACE, parser.N_TS_TYP_DEC, parser.N_TS_DEC_VAR_DEC, parser.N_TS_DEC_FN,
+		parser.N_TS_DEC_CLASS, parser.N_TS_DEC_INTERFACE, parser.N_TS_DEC_TYP_DEC, parser.N_TS_DEC_ENUM,
+		parser.N_TS_DEC_MODULE, parser.N_TS_DEC_NS, parser.N_TS_DEC_GLOBAL,
+	} {
+		walk.SetVisitor(&ctx.Visitors, t, s.visitTypDec)
+	}
+	walk.SetVisitor(&ctx.Visitors, parser.N_STMT_FN, s.visitFn)
+	walk.SetVisitor(&ctx.Visitors, parser.N_EXPR_FN, s.visitFn)
+	walk.SetVisitor(&ctx.Visitors, parser.N_STMT_CLASS, s.visitClass)
+	walk.SetVisitor(&ctx.Visitors, parser.N_EXPR_CLASS, s.visitClass)
+	walk.SetVisitor(&ctx.Visitors, parser.N_METHOD, s.visitMethod)
+	walk.SetVisitor(&ctx.Visitors, parser.N_FIELD, s.visitField)
+	walk.SetVisitor(&ctx.Visitors, parser.N_EXPR_BIN, s.visitBin)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_TYP_ASSERT, s.visitTypAssert)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_NO_NULL, s.visitNoNull)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_ENUM, s.visitEnum)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_NAMESPACE, s.visitNS)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_IMPORT_ALIAS, s.visitImportAlias)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_IMPORT_REQUIRE, s.visitImportRequire)
+	walk.SetVisitor(&ctx.Visitors, parser.N_TS_EXPORT_ASSIGN, s.visitExportAssign)
+	walk.SetVisitor(&ctx.Visitors, parser.N_STMT_IMPORT, s.visitImport)
+	walk.SetVisitor(&ctx.Visitors, parser.N_STMT_EXPORT, s.visitExport)
+
+	walk.VisitNode(s.ctx.Ast, "", ctx.VisitorCtx())
+
+	// `export =` is compiled to `module.exports = x` which does not work in the ES modules, it's
+	// the error TS1203 of tsc
+	if s.esm != nil && s.exportAssign != nil {
+		s.ctx.Warn(s.exportAssign.Range(), "export assignment can not be used in the ES modules, consider using `export default` instead")
+	}
+
+	if s.hasJsx && s.opts.JsxFactory != "" {
+		s.valRefs[s.opts.JsxFactory] = true
+	}
+	s.elideImports()
+}
+
+// collects the top-level names which are declared as types only, the export
+// specifiers which refer to them should be removed
+func (s *tsStripper) collectTypNames() {
+	for _, stmt := range s.ctx.Ast.(*parser.Prog).Body() {
+		if stmt.Type() == parser.N_STMT_EXPORT {
+			n := stmt.(*parser.ExportDec)
+			if n.Dec() == nil {
+				continue
+			}
+			stmt = n.Dec()
+		}
+
+		switch n := stmt.(type) {
+		case *parser.TsInterface:
+			s.typNames[astutil.GetName(n.Id())] = true
+		case *parser.TsTypDec:
+			s.typNames[astutil.GetName(n.Id())] = true
+		case *parser.ImportDec:
+			for _, spec := range n.Specs() {
+				sp := spec.(*parser.ImportSpec)
+				if n.TsTyp() || sp.TsTyp() {
+					s.typNames[astutil.GetName(sp.Local())] = true
+				}
+			}
+		}
+	}
+}
+
+func (s *tsStripper) isIdPart(ofst int, backward bool) bool {
+	var r rune
+	if backward {
+		if ofst <= 0 {
+			return false
+		}
+		r, _ = utf8.DecodeLastRuneInString(s.code[:ofst])
+	} else {
+		if ofst >= len(s.code) {
+			return false
+		}
+		r, _ = utf8.DecodeRuneInString(s.code[ofst:])
+	}
+	return parser.IsIdStart(r) || parser.IsIdPart(r)
+}
+
+// removes the source in range, a whitespace is left if the removal glues
+// the surrounding tokens, for example `return<T>x`
+func (s *tsStripper) erase(rng span.Range) {
+	if s.isIdPart(int(rng.Lo), true) && s.isIdPart(int(rng.Hi), false) {
+		s.p.Replace(rng, " ")
+		return
+	}
+	s.p.Remove(rng)
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func (s *tsStripper) skipSpaces(ofst uint32) uint32 {
+	return skipSpaces(s.code, ofst)
+}
+
+func (s *tsStripper) skipSpacesBackward(ofst uint32) uint32 {
+	return skipSpacesBackward(s.code, ofst)
+}
+
+func (s *tsStripper) indentOf(ofst uint32) string {
+	return util.IndentOf(s.code, ofst)
+}
+
+// removes the statement, the lines it occupies are also removed if there is
+// nothing else on them
+func (s *tsStripper) removeStmt(node parser.Node) {
+	rng := node.Range()
+	lo, hi := int(rng.Lo), int(rng.Hi)
+	for lo > 0 && isBlank(s.code[lo-1]) {
+		lo--
+	}
+	for hi < len(s.code) && isBlank(s.code[hi]) {
+		hi++
+	}
+	if (lo == 0 || s.code[lo-1] == '\n' || s.code[lo-1] == '\r') &&
+		(hi == len(s.code) || s.code[hi] == '\n' || s.code[hi] == '\r') {
+		if hi < len(s.code) && s.code[hi] == '\r' {
+			hi++
+		}
+		if hi < len(s.code) && s.code[hi] == '\n' {
+			hi++
+		}
+		s.p.Remove(span.Range{Lo: uint32(lo), Hi: uint32(hi)})
+		return
+	}
+	s.p.Remove(rng)
+}
+
+// removes the declaration with the `export` keyword before it if it's exported
+func (s *tsStripper) removeDec(node parser.Node, vc *walk.VisitorCtx) {
+	if vc.ParentNodeType() == parser.N_STMT_EXPORT {
+		node = vc.ParentNode()
+	}
+	s.removeStmt(node)
+}
+
+func (s *tsStripper) visitTypDec(node parser.Node, key string, vc *walk.VisitorCtx) {
+	s.removeDec(node, vc)
+}
+
+func (s *tsStripper) stripTypInfo(node parser.Node, key string, vc *walk.VisitorCtx) {
+	ti := parser.TypInfoOf(node)
+	if ti == nil {
+		return
+	}
+
+	if annot := ti.TypAnnot(); annot != nil {
+		rng := annot.Range()
+		// the annotation of the params parsed in the rough manner does not include the colon
+		if s.code[rng.Lo] != ':' {
+			if lo := s.skipSpacesBackward(rng.Lo); lo > 0 && s.code[lo-1] == ':' {
+				rng.Lo = lo - 1
+			}
+		}
+		s.erase(rng)
+	}
+	if tp := ti.TypParams(); tp != nil {
+		s.erase(tp.Range())
+	}
+	if ta := ti.TypArgs(); ta != nil {
+		s.erase(ta.Range())
+	}
+	if ta := ti.SuperTypArgs(); ta != nil {
+		s.erase(ta.Range())
+	}
+	if ques := ti.Ques(); !ques.Empty() {
+		s.p.Remove(ques)
+	}
+	if not := ti.Not(); !not.Empty() {
+		s.p.Remove(not)
+	}
+
+	// the modifiers of the parameter properties
+	if begin := ti.BeginRng(); !begin.Empty() && begin.Lo < node.Range().Lo {
+		switch node.Type() {
+		case parser.N_NAME, parser.N_PAT_ASSIGN, parser.N_PAT_OBJ, parser.N_PAT_ARRAY:
+			s.p.Remove(span.Range{Lo: begin.Lo, Hi: node.Range().Lo})
+		}
+	}
+}
+
+func (s *tsStripper) onName(node parser.Node, key string, vc *walk.VisitorCtx) {
+	name := node.(*parser.Ident).Val()
+	s.valRefs[name] = true
+
+	if s.enum == nil || !s.enum.members[name] {
+		return
+	}
+	switch vc.ParentNodeType() {
+	case parser.N_EXPR_MEMBER:
+		if key == "Prop" && !vc.ParentNode().(*parser.MemberExpr).Compute() {
+			return
+		}
+	case parser.N_PROP:
+		if key == "Key" && !vc.ParentNode().(*parser.Prop).Computed() {
+			return
+		}
+	}
+	s.p.Replace(node.Range(), s.enum.name+"."+name)
+}
+
+func (s *tsStripper) visitFn(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.FnDec)
+	// the overload signature
+	if n.Body() == nil {
+		s.removeDec(node, vc)
+		return
+	}
+	walk.VisitFnDec(node, key, vc)
+}
+
+var tsModifiers = map[string]bool{
+	"public":    true,
+	"private":   true,
+	"protected": true,
+	"readonly":  true,
+	"override":  true,
+	"abstract":  true,
+	"declare":   true,
+}
+
+// removes the typescript modifiers in range `[lo, hi)` which is the range between the start
+// of the class element and its key, the javascript modifiers like `static` are kept
+func (s *tsStripper) removeModifiers(node parser.Node, hi uint32) {
+	lo := node.Range().Lo
+	if decs := parser.DecoratorsOf(node); len(decs) > 0 {
+		if end := decs[len(decs)-1].Range().Hi; end > lo {
+			lo = end
+		}
+	}
+	i := int(lo)
+	for i < int(hi) {
+		if !parser.IsIdStart(rune(s.code[i])) {
+			i++
+			continue
+		}
+		j := i
+		for j < int(hi) && s.isIdPart(j, false) {
+			j++
+		}
+		if tsModifiers[s.code[i:j]] {
+			s.p.Remove(span.Range{Lo: uint32(i), Hi: s.skipSpaces(uint32(j))})
+		}
+		i = j
+	}
+}
+
+func (s *tsStripper) visitClass(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.ClassDec)
+	if n.Declare() {
+		s.removeDec(node, vc)
+		return
+	}
+
+	if n.Abstract() {
+		lo := n.Range().Lo
+		if strings.HasPrefix(s.code[lo:], "abstract") {
+			s.p.Remove(span.Range{Lo: lo, Hi: s.skipSpaces(lo + 8)})
+		}
+	}
+
+	if impls := n.Implements(); len(impls) > 0 {
+		lo := s.skipSpacesBackward(impls[0].Range().Lo)
+		if lo >= 10 && s.code[lo-10:lo] == "implements" {
+			lo = s.skipSpacesBackward(lo - 10)
+		}
+		s.p.Remove(span.Range{Lo: lo, Hi: impls[len(impls)-1].Range().Hi})
+	}
+
+	walk.VisitClassDec(node, key, vc)
+}
+
+// removes the class element with the line it occupies
+func (s *tsStripper) removeElem(node parser.Node) {
+	s.removeStmt(node)
+}
+
+func (s *tsStripper) visitMethod(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.Method)
+	// the abstract method or the overload signature
+	if !n.HasBody() {
+		s.removeElem(node)
+		return
+	}
+
+	s.removeModifiers(node, n.Key().Range().Lo)
+	if n.Kind() == "constructor" {
+		s.paramProps(n.Val().(*parser.FnDec))
+	}
+	walk.VisitMethod(node, key, vc)
+}
+
+// the parameter properties are compiled to the assignments at the beginning of
+// the constructor body or right after the `super` call if there is one
+func (s *tsStripper) paramProps(fn *parser.FnDec) {
+	names := make([]string, 0)
+	for _, param := range fn.Params() {
+		ti := parser.TypInfoOf(param)
+		if ti == nil || ti.BeginRng().Empty() || ti.BeginRng().Lo >= param.Range().Lo {
+			continue
+		}
+		id := param
+		if id.Type() == parser.N_PAT_ASSIGN {
+			id = id.(*parser.AssignPat).Lhs()
+		}
+		if id.Type() == parser.N_NAME {
+			names = append(names, id.(*parser.Ident).Val())
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	body := fn.Body().(*parser.BlockStmt)
+	ofst := body.Range().Lo + 1
+	prefix := ""
+	for _, stmt := range body.Body() {
+		if stmt.Type() != parser.N_STMT_EXPR {
+			continue
+		}
+		expr := stmt.(*parser.ExprStmt).Expr()
+		if expr.Type() == parser.N_EXPR_CALL && expr.(*parser.CallExpr).Callee().Type() == parser.N_SUPER {
+			ofst = stmt.Range().Hi
+			if s.code[ofst-1] != ';' {
+				prefix = ";"
+			}
+			break
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf(" this.%s = %s;", name, name))
+	}
+	if s.code[ofst] == '}' {
+		sb.WriteString(" ")
+	}
+	s.p.Insert(ofst, sb.String())
+}
+
+func (s *tsStripper) visitField(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.Field)
+	ti := n.TypInfo()
+	if n.IsTsSig() || (ti != nil && (ti.Declare() || ti.Abstract())) {
+		s.removeElem(node)
+		return
+	}
+
+	s.removeModifiers(node, n.Key().Range().Lo)
+	walk.VisitField(node, key, vc)
+}
+
+func outerRange(node parser.Node) span.Range {
+	if n, ok := node.(parser.InParenNode); ok {
+		if rng := n.OuterParen(); !rng.Empty() {
+			return rng
+		}
+	}
+	return node.Range()
+}
+
+func (s *tsStripper) visitBin(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.BinExpr)
+	if n.Op() != parser.T_TS_AS && n.Op() != parser.T_TS_SATISFIES {
+		walk.VisitBinExpr(node, key, vc)
+		return
+	}
+	s.erase(span.Range{Lo: outerRange(n.Lhs()).Hi, Hi: n.Range().Hi})
+	walk.VisitNode(n.Lhs(), "Lhs", vc)
+}
+
+func (s *tsStripper) visitTypAssert(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsTypAssert)
+	s.erase(span.Range{Lo: n.Range().Lo, Hi: outerRange(n.Expr()).Lo})
+	walk.VisitNode(n.Expr(), "Expr", vc)
+}
+
+func (s *tsStripper) visitNoNull(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsNoNull)
+	s.p.Remove(span.Range{Lo: outerRange(n.Arg()).Hi, Hi: n.Range().Hi})
+	walk.VisitNode(n.Arg(), "Arg", vc)
+}
+
+// returns the declaration of the name in the statement list which the node belongs to, or
+// empty if the name has been declared before, this is used to merge the declarations of
+// the enums and the namespaces
+func (s *tsStripper) declare(name string, vc *walk.VisitorCtx) string {
+	c := vc.Parent
+	if c != nil && c.Node.Type() == parser.N_STMT_EXPORT {
+		c = c.Parent
+	}
+
+	kw := "var"
+	if len(s.ns) > 0 {
+		kw = "let"
+	}
+	decl := kw + " " + name + ";"
+	if c == nil {
+		return decl
+	}
+
+	var stmts []parser.Node
+	switch n := c.Node.(type) {
+	case *parser.Prog:
+		stmts = n.Body()
+	case *parser.BlockStmt:
+		stmts = n.Body()
+	default:
+		return decl
+	}
+
+	names := s.declared[c.Node]
+	if names == nil {
+		names = map[string]bool{}
+		for _, stmt := range stmts {
+			if stmt.Type() == parser.N_STMT_EXPORT && stmt.(*parser.ExportDec).Dec() != nil {
+				stmt = stmt.(*parser.ExportDec).Dec()
+			}
+			if stmt.Type() == parser.N_STMT_FN || stmt.Type() == parser.N_STMT_CLASS {
+				ns, _ := astutil.NamesInDecNode(stmt)
+				for _, n := range ns {
+					names[n] = true
+				}
+			}
+		}
+		s.declared[c.Node] = names
+	}
+	if names[name] {
+		return ""
+	}
+	names[name] = true
+	return decl
+}
+
+// the argument of the IIFE which the enums and the namespaces are compiled to
+func (s *tsStripper) iifeArg(name string, exported bool) string {
+	if exported && len(s.ns) > 0 {
+		q := s.ns[len(s.ns)-1] + "." + name
+		return fmt.Sprintf("%s = %s || (%s = {})", name, q, q)
+	}
+	return fmt.Sprintf("%s || (%s = {})", name, name)
+}
+
+// replaces the enum or the namespace with its compiled form
+func (s *tsStripper) replaceDec(node parser.Node, decl, iife string, vc *walk.VisitorCtx) {
+	indent := s.indentOf(node.Range().Lo)
+	if vc.ParentNodeType() == parser.N_STMT_EXPORT && len(s.ns) == 0 {
+		if decl == "" {
+			// the `export` keyword should be dropped since the name has been declared
+			s.p.Replace(vc.ParentNode().Range(), iife)
+			return
+		}
+		s.p.Replace(node.Range(), decl+"\n"+indent+iife)
+		return
+	}
+	if decl == "" {
+		s.p.Replace(node.Range(), iife)
+		return
+	}
+	s.p.Replace(node.Range(), decl+"\n"+indent+iife)
+}
+
+type tsEnumVal struct {
+	num   float64
+	str   string
+	isStr bool
+}
+
+func (v *tsEnumVal) String() string {
+	if v.isStr {
+		return strconv.Quote(v.str)
+	}
+	if math.IsNaN(v.num) {
+		return "NaN"
+	}
+	if math.IsInf(v.num, 1) {
+		return "Infinity"
+	}
+	if math.IsInf(v.num, -1) {
+		return "-Infinity"
+	}
+	if v.num < 0 {
+		return "-" + strconv.FormatFloat(-v.num, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(v.num, 'f', -1, 64)
+}
+
+func toInt32(f float64) int32 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return int32(int64(math.Trunc(f)))
+}
+
+// evaluates the constant enum member initializer, nil is returned if the
+// initializer is not a constant expression
+func (s *tsStripper) evalEnum(node parser.Node, vals map[string]*tsEnumVal, enum string) *tsEnumVal {
+	switch n := node.(type) {
+	case *parser.NumLit:
+		return &tsEnumVal{num: parser.NodeToFloat(n, s.p.Source())}
+	case *parser.StrLit:
+		return &tsEnumVal{str: n.Val(), isStr: true}
+	case *parser.ParenExpr:
+		return s.evalEnum(n.Expr(), vals, enum)
+	case *parser.Ident:
+		return vals[n.Val()]
+	case *parser.MemberExpr:
+		if astutil.GetName(n.Obj()) != enum {
+			return nil
+		}
+		if n.Compute() {
+			if str, ok := n.Prop().(*parser.StrLit); ok {
+				return vals[str.Val()]
+			}
+			return nil
+		}
+		return vals[astutil.GetName(n.Prop())]
+	case *parser.UnaryExpr:
+		v := s.evalEnum(n.Arg(), vals, enum)
+		if v == nil || v.isStr {
+			return nil
+		}
+		switch n.OpText() {
+		case "+":
+			return v
+		case "-":
+			return &tsEnumVal{num: -v.num}
+		case "~":
+			return &tsEnumVal{num: float64(^toInt32(v.num))}
+		}
+	case *parser.BinExpr:
+		l := s.evalEnum(n.Lhs(), vals, enum)
+		r := s.evalEnum(n.Rhs(), vals, enum)
+		if l == nil || r == nil {
+			return nil
+		}
+		op := n.OpText()
+		if l.isStr || r.isStr {
+			if op == "+" {
+				return &tsEnumVal{str: l.rawStr() + r.rawStr(), isStr: true}
+			}
+			return nil
+		}
+		a, b := l.num, r.num
+		switch op {
+		case "+":
+			return &tsEnumVal{num: a + b}
+		case "-":
+			return &tsEnumVal{num: a - b}
+		case "*":
+			return &tsEnumVal{num: a * b}
+		case "/":
+			return &tsEnumVal{num: a / b}
+		case "%":
+			return &tsEnumVal{num: math.Mod(a, b)}
+		case "**":
+			return &tsEnumVal{num: math.Pow(a, b)}
+		case "|":
+			return &tsEnumVal{num: float64(toInt32(a) | toInt32(b))}
+		case "&":
+			return &tsEnumVal{num: float64(toInt32(a) & toInt32(b))}
+		case "^":
+			return &tsEnumVal{num: float64(toInt32(a) ^ toInt32(b))}
+		case "<<":
+			return &tsEnumVal{num: float64(toInt32(a) << (uint32(toInt32(b)) & 31))}
+		case ">>":
+			return &tsEnumVal{num: float64(toInt32(a) >> (uint32(toInt32(b)) & 31))}
+		case ">>>":
+			return &tsEnumVal{num: float64(uint32(toInt32(a)) >> (uint32(toInt32(b)) & 31))}
+		}
+	}
+	return nil
+}
+
+func (v *tsEnumVal) rawStr() string {
+	if v.isStr {
+		return v.str
+	}
+	return v.String()
+}
+
+func enumKey(node parser.Node) string {
+	switch n := node.(type) {
+	case *parser.Ident:
+		return n.Val()
+	case *parser.StrLit:
+		return n.Val()
+	}
+	return ""
+}
+
+// compiles the enum to the IIFE which populates the enum object:
+//
+//	var E;
+//	(function (E) {
+//	  E[E["A"] = 0] = "A";
+//	  E["B"] = "b";
+//	})(E || (E = {}));
+func (s *tsStripper) visitEnum(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsEnum)
+	name := astutil.GetName(n.Id())
+	indent := s.indentOf(n.Range().Lo)
+
+	scope := &tsEnumScope{name, map[string]bool{}}
+	for _, m := range n.Members() {
+		scope.members[enumKey(m.(*parser.TsEnumMember).Key())] = true
+	}
+
+	vals := map[string]*tsEnumVal{}
+	lines := make([]string, 0, len(n.Members()))
+	var prev *tsEnumVal
+	for i, mn := range n.Members() {
+		m := mn.(*parser.TsEnumMember)
+		k := enumKey(m.Key())
+		qk := strconv.Quote(k)
+
+		var v *tsEnumVal
+		expr := ""
+		if m.Val() == nil {
+			if i == 0 {
+				v = &tsEnumVal{}
+			} else if prev != nil && !prev.isStr {
+				v = &tsEnumVal{num: prev.num + 1}
+			} else {
+				s.ctx.Warn(m.Range(), "enum member must have initializer")
+				expr = "void 0"
+			}
+		} else if v = s.evalEnum(m.Val(), vals, name); v == nil {
+			s.enum = scope
+			walk.VisitNode(m.Val(), "Val", vc)
+			s.enum = nil
+			expr = s.p.NodeText(m.Val())
+		}
+
+		if v != nil {
+			vals[k] = v
+			expr = v.String()
+		}
+		if v != nil && v.isStr {
+			lines = append(lines, fmt.Sprintf("%s[%s] = %s;", name, qk, expr))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s[%s[%s] = %s] = %s;", name, name, qk, expr, qk))
+		}
+		prev = v
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("(function (%s) {\n", name))
+	for _, line := range lines {
+		sb.WriteString(indent + "  " + line + "\n")
+	}
+	exported := vc.ParentNodeType() == parser.N_STMT_EXPORT
+	sb.WriteString(fmt.Sprintf("%s})(%s);", indent, s.iifeArg(name, exported)))
+	s.replaceDec(node, s.declare(name, vc), sb.String(), vc)
+}
+
+// whether the statement has runtime semantics, the namespaces which contain only
+// types are not instantiated and should be removed entirely
+func (s *tsStripper) instantiated(node parser.Node) bool {
+	switch n := node.(type) {
+	case *parser.TsNS:
+		return s.instantiated(n.Body())
+	case *parser.BlockStmt:
+		for _, stmt := range n.Body() {
+			if s.instantiated(stmt) {
+				return true
+			}
+		}
+		return false
+	case *parser.ExportDec:
+		if n.TsTyp() {
+			return false
+		}
+		if n.Dec() != nil {
+			return s.instantiated(n.Dec())
+		}
+		return true
+	case *parser.ImportDec:
+		return !n.TsTyp()
+	case *parser.TsInterface, *parser.TsTypDec, *parser.TsDec:
+		return false
+	case *parser.FnDec:
+		return n.Body() != nil
+	case *parser.ClassDec:
+		return !n.Declare()
+	}
+	return node.Type() != parser.N_STMT_EMPTY
+}
+
+// whether the node is the direct child of the namespace body
+func inNsBody(vc *walk.VisitorCtx) bool {
+	p := vc.Parent
+	return p != nil && p.Node.Type() == parser.N_STMT_BLOCK &&
+		p.Parent != nil && p.Parent.Node.Type() == parser.N_TS_NAMESPACE
+}
+
+// compiles the namespace to the IIFE which takes the namespace object as its parameter,
+// the exported members of the namespace are assigned to that object:
+//
+//	var N;
+//	(function (N) {
+//	  function f() {} N.f = f;
+//	})(N || (N = {}));
+func (s *tsStripper) visitNS(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsNS)
+	if !s.instantiated(n) {
+		s.removeDec(node, vc)
+		return
+	}
+
+	name := astutil.GetName(n.Id())
+	exported := vc.ParentNodeType() == parser.N_STMT_EXPORT || vc.ParentNodeType() == parser.N_TS_NAMESPACE
+	decl := s.declare(name, vc)
+
+	s.ns = append(s.ns, name)
+	body := n.Body()
+	walk.VisitNode(body, "Body", vc)
+	var inner string
+	if body.Type() == parser.N_TS_NAMESPACE {
+		inner = " " + s.p.NodeText(body) + " "
+	} else {
+		rng := body.Range()
+		inner = s.p.Text(span.Range{Lo: rng.Lo + 1, Hi: rng.Hi - 1})
+	}
+	s.ns = s.ns[:len(s.ns)-1]
+
+	iife := fmt.Sprintf("(function (%s) {%s})(%s);", name, inner, s.iifeArg(name, exported))
+	s.replaceDec(node, decl, iife, vc)
+}
+
+func (s *tsStripper) visitImportAlias(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsImportAlias)
+	name := astutil.GetName(n.Name())
+	val := s.p.NodeText(n.Val())
+	walk.VisitNode(n.Val(), "Val", vc)
+
+	if len(s.ns) > 0 && n.Export() {
+		s.p.Replace(n.Range(), fmt.Sprintf("const %s = %s; %s.%s = %s;", name, val, s.ns[len(s.ns)-1], name, name))
+		return
+	}
+	kw := "var"
+	if n.Export() {
+		kw = "export var"
+	}
+	s.p.Replace(n.Range(), fmt.Sprintf("%s %s = %s;", kw, name, val))
+}
+
+func (s *tsStripper) visitImportRequire(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsImportRequire)
+	name := astutil.GetName(n.Name())
+	s.p.Replace(n.Range(), fmt.Sprintf("const %s = %s;", name, s.p.NodeText(n.Expr())))
+}
+
+func (s *tsStripper) visitExportAssign(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.TsExportAssign)
+	s.exportAssign = n
+	walk.VisitNode(n.Expr(), "Expr", vc)
+	s.p.Replace(n.Range(), fmt.Sprintf("module.exports = %s;", s.p.NodeText(n.Expr())))
+}
+
+// the imports are processed after the entire program is walked since whether the
+// imported names are referenced as values is needed
+func (s *tsStripper) visitImport(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.ImportDec)
+	if n.TsTyp() {
+		s.removeStmt(node)
+		return
+	}
+	s.imports = append(s.imports, n)
+	if s.esm == nil {
+		s.esm = n
+	}
+}
+
+func (s *tsStripper) elideImports() {
+	for _, n := range s.imports {
+		specs := n.Specs()
+		if len(specs) == 0 {
+			continue
+		}
+
+		kept := make([]*parser.ImportSpec, 0, len(specs))
+		for _, spec := range specs {
+			sp := spec.(*parser.ImportSpec)
+			if sp.TsTyp() {
+				continue
+			}
+			if !s.opts.KeepUnusedImports && !s.valRefs[astutil.GetName(sp.Local())] {
+				continue
+			}
+			kept = append(kept, sp)
+		}
+
+		if len(kept) == len(specs) {
+			continue
+		}
+		if len(kept) == 0 {
+			s.removeStmt(n)
+			continue
+		}
+
+		clauses := make([]string, 0, 2)
+		named := make([]string, 0, len(kept))
+		for _, sp := range kept {
+			if sp.Default() {
+				clauses = append(clauses, s.p.NodeText(sp.Local()))
+			} else if sp.NameSpace() {
+				clauses = append(clauses, s.p.NodeText(sp))
+			} else {
+				named = append(named, s.p.NodeText(sp))
+			}
+		}
+		if len(named) > 0 {
+			clauses = append(clauses, "{ "+strings.Join(named, ", ")+" }")
+		}
+		s.p.Replace(n.Range(), fmt.Sprintf("import %s from %s;", strings.Join(clauses, ", "), s.modSrc(n.Src(), n.AttrKw(), n.Range())))
+	}
+}
+
+func (s *tsStripper) visitExport(node parser.Node, key string, vc *walk.VisitorCtx) {
+	n := node.(*parser.ExportDec)
+	if n.TsTyp() {
+		s.removeStmt(node)
+		return
+	}
+	if s.esm == nil && !inNsBody(vc) {
+		s.esm = n
+	}
+
+	if dec := n.Dec(); dec != nil {
+		if !s.instantiated(dec) || (n.Default() && s.typNames[astutil.GetName(dec)]) {
+			s.removeStmt(node)
+			return
+		}
+
+		walk.VisitExportDec(node, key, vc)
+		if !inNsBody(vc) {
+			return
+		}
+
+		// the exported declarations in namespace are assigned to the namespace object
+		text := s.p.NodeText(dec)
+		switch dec.Type() {
+		case parser.N_STMT_VAR_DEC, parser.N_STMT_FN, parser.N_STMT_CLASS:
+			// the declaration without the trailing semicolon would be glued to the assignments
+			if dec.Type() == parser.N_STMT_VAR_DEC && !strings.HasSuffix(text, ";") {
+				text += ";"
+			}
+			names, _ := astutil.NamesInDecNode(dec)
+			ns := s.ns[len(s.ns)-1]
+			for _, name := range names {
+				text += fmt.Sprintf(" %s.%s = %s;", ns, name, name)
+			}
+		}
+		s.p.Replace(n.Range(), text)
+		return
+	}
+
+	// the type-only specifiers are not visited so that the names they
+	// refer to are not considered to be referenced as values
+	for i, spec := range n.Specs() {
+		if !spec.(*parser.ExportSpec).TsTyp() {
+			walk.VisitNode(spec, fmt.Sprintf("Specs[%d]", i), vc)
+		}
+	}
+	if n.All() || n.Src() != nil {
+		s.filterExportSpecs(n, false)
+		return
+	}
+	s.filterExportSpecs(n, true)
+}
+
+// removes the export specifiers which are type-only or refer to the local types
+func (s *tsStripper) filterExportSpecs(n *parser.ExportDec, local bool) {
+	specs := n.Specs()
+	kept := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		sp := spec.(*parser.ExportSpec)
+		if sp.TsTyp() || (local && s.typNames[astutil.GetName(sp.Local())]) {
+			continue
+		}
+		kept = append(kept, s.p.NodeText(sp))
+	}
+	if len(kept) == len(specs) {
+		return
+	}
+
+	if len(kept) == 0 {
+		if local {
+			s.p.Replace(n.Range(), "export {};")
+		} else {
+			s.removeStmt(n)
+		}
+		return
+	}
+	text := "export { " + strings.Join(kept, ", ") + " }"
+	if n.Src() != nil {
+		text += " from " + s.modSrc(n.Src(), n.AttrKw(), n.Range())
+	}
+	s.p.Replace(n.Range(), text+";")
+}
+
+// the text of the module specifier followed by its import attributes if any, like
+// `"./a.json" with { type: "json" }`, for the rewritten import and export statements
+func (s *tsStripper) modSrc(src parser.Node, kw span.Range, rng span.Range) string {
+	if kw.Empty() {
+		return s.p.NodeText(src)
+	}
+	text := s.p.Text(span.Range{Lo: src.Range().Lo, Hi: rng.Hi})
+	return strings.TrimRight(strings.TrimSpace(text), ";")
+}
@@ -0,0 +1,291 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func stripTs(t *testing.T, code string, jsx bool, opts *TsStripOpts) *Result {
+	popts := parser.NewParserOpts()
+	if !jsx {
+		popts.Feature = popts.Feature.Off(parser.FEAT_JSX)
+	}
+	ret, err := Run("a.ts", code, popts, NewTsStrip(opts))
+	AssertEqual(t, nil, err, "should be ok")
+	return ret
+}
+
+func TestTsStripAnnot(t *testing.T) {
+	ret := stripTs(t, `let a: number = 1;
+function f<T>(a?: T, ...r: number[]): Promise<void> {}
+const g = <T,>(x: T): x is string => true;
+let b = (x as any) as string;
+let c = <any>y;
+let d = z!.x;
+let e!: number;
+f<string>(1);`, false, nil)
+
+	AssertEqualString(t, `let a = 1;
+function f(a, ...r) {}
+const g = (x) => true;
+let b = (x);
+let c = y;
+let d = z.x;
+let e;
+f(1);`, ret.Code, "should be ok")
+}
+
+func TestTsStripImportAttrs(t *testing.T) {
+	ret := stripTs(t, `import data, { type T } from "./a.json" with { type: "json" };
+export { type U, b } from "./b.json" assert { type: "json" };
+let c: T = data;`, false, nil)
+
+	AssertEqualString(t, `import data from "./a.json" with { type: "json" };
+export { b } from "./b.json" assert { type: "json" };
+let c = data;`, ret.Code, "should be ok")
+}
+
+func TestTsStripTs5(t *testing.T) {
+	ret := stripTs(t, `const a = { x: 1 } satisfies Rec;
+function f<const T>(x: T) {}
+type F<in out U> = (x: U) => U;
+class C<in T> { static accessor y: number = 1; }
+export type * from "./types";
+export type * as ns from "./types";`, false, nil)
+
+	AssertEqualString(t, `const a = { x: 1 };
+function f(x) {}
+class C { static accessor y = 1; }`, ret.Code, "should be ok")
+}
+
+func TestTsStripTypDec(t *testing.T) {
+	ret := stripTs(t, `interface I { a: number }
+type A<T = string> = T;
+declare const dd: number;
+declare module "m" {}
+function ov(a: string): void;
+function ov(a: any) {}
+export default interface J {}
+export type { T } from "x";
+let v = 1;`, false, nil)
+
+	AssertEqualString(t, `function ov(a) {}
+let v = 1;`, ret.Code, "should be ok")
+}
+
+func TestTsStripClass(t *testing.T) {
+	ret := stripTs(t, `export abstract class A<T> extends B<T> implements C, D {
+  private readonly x?: number = 1;
+  static y!: string;
+  declare z: number;
+  [k: string]: any;
+  public static async foo<U>(a?: U): Promise<void> {}
+  protected abstract bar(): void;
+  constructor(private p: number, public q = 1) {
+    super();
+  }
+}`, false, nil)
+
+	AssertEqualString(t, `export class A extends B {
+  x = 1;
+  static y;
+  static async foo(a) {}
+  constructor(p, q = 1) {
+    super(); this.p = p; this.q = q;
+  }
+}`, ret.Code, "should be ok")
+}
+
+func TestTsStripParamPropWithoutSuper(t *testing.T) {
+	ret := stripTs(t, `class A { constructor(readonly a: number, b: string) {} }`, false, nil)
+	AssertEqualString(t, `class A { constructor(a, b) { this.a = a; } }`, ret.Code, "should be ok")
+}
+
+func TestTsStripEnum(t *testing.T) {
+	ret := stripTs(t, `enum E { A, B = 2, C = A | B, D, S = "s" + "t", F = f(1), G = F + 1 }`, false, nil)
+	AssertEqualString(t, `var E;
+(function (E) {
+  E[E["A"] = 0] = "A";
+  E[E["B"] = 2] = "B";
+  E[E["C"] = 2] = "C";
+  E[E["D"] = 3] = "D";
+  E["S"] = "st";
+  E[E["F"] = f(1)] = "F";
+  E[E["G"] = E.F + 1] = "G";
+})(E || (E = {}));`, ret.Code, "should be ok")
+}
+
+func TestTsStripEnumExport(t *testing.T) {
+	ret := stripTs(t, `export enum E { A = -1 }
+export enum E { B = 1 << 3 }`, false, nil)
+	AssertEqualString(t, `export var E;
+(function (E) {
+  E[E["A"] = -1] = "A";
+})(E || (E = {}));
+(function (E) {
+  E[E["B"] = 8] = "B";
+})(E || (E = {}));`, ret.Code, "should be ok")
+}
+
+func TestTsStripNamespace(t *testing.T) {
+	ret := stripTs(t, `namespace N {
+  export function f(a: number): number { return a; }
+  const priv = 2;
+  export namespace M {
+    export class K {}
+  }
+  export interface T {}
+}
+namespace N {
+  export const again = 3;
+}
+namespace TypesOnly { export type A = number; }`, false, nil)
+
+	AssertEqualString(t, `var N;
+(function (N) {
+  function f(a) { return a; } N.f = f;
+  const priv = 2;
+  let M;
+  (function (M) {
+    class K {} M.K = K;
+  })(M = N.M || (N.M = {}));
+})(N || (N = {}));
+(function (N) {
+  const again = 3; N.again = again;
+})(N || (N = {}));
+`, ret.Code, "should be ok")
+}
+
+func TestTsStripNamespaceDotted(t *testing.T) {
+	ret := stripTs(t, `namespace A.B { export const c = 1; }`, false, nil)
+	AssertEqualString(t, `var A;
+(function (A) { let B;
+(function (B) { const c = 1; B.c = c; })(B = A.B || (A.B = {})); })(A || (A = {}));`, ret.Code, "should be ok")
+}
+
+func TestTsStripNamespaceNoSemicolon(t *testing.T) {
+	ret := stripTs(t, `namespace N {
+  export namespace M { export let r = q }
+  export var a = 1, b
+}`, false, nil)
+	AssertEqualString(t, `var N;
+(function (N) {
+  let M;
+  (function (M) { let r = q; M.r = r; })(M = N.M || (N.M = {}));
+  var a = 1, b; N.a = a; N.b = b;
+})(N || (N = {}));`, ret.Code, "should be ok")
+}
+
+func TestTsStripNamespaceMergeFn(t *testing.T) {
+	ret := stripTs(t, `function f() {}
+namespace f { export const a = 1; }`, false, nil)
+	AssertEqualString(t, `function f() {}
+(function (f) { const a = 1; f.a = a; })(f || (f = {}));`, ret.Code, "should be ok")
+}
+
+func TestTsStripImportExport(t *testing.T) {
+	ret := stripTs(t, `import type X from "y";
+import { type Y, Z } from "z";
+import { Foo, Bar } from "./foo";
+import * as ns from "./ns";
+import Def, { used, unused } from "./def";
+import "./side-effect";
+import Q = R.S;
+import w = require("w");
+interface I {}
+const v: Foo = used(ns.x, Q, w);
+export { I, v };
+export { type Y as YY, Z };
+export default Def;`, false, nil)
+
+	AssertEqualString(t, `import { Z } from "z";
+import * as ns from "./ns";
+import Def, { used } from "./def";
+import "./side-effect";
+var Q = R.S;
+const w = require("w");
+const v = used(ns.x, Q, w);
+export { v };
+export { Z };
+export default Def;`, ret.Code, "should be ok")
+}
+
+func TestTsStripKeepUnusedImports(t *testing.T) {
+	opts := NewTsStripOpts()
+	opts.KeepUnusedImports = true
+	ret := stripTs(t, `import { type A, B } from "b";`, false, opts)
+	AssertEqualString(t, `import { B } from "b";`, ret.Code, "should be ok")
+}
+
+func TestTsStripExportAssign(t *testing.T) {
+	ret := stripTs(t, `const a = 1;
+export = a;`, false, nil)
+	AssertEqualString(t, `const a = 1;
+module.exports = a;`, ret.Code, "should be ok")
+	AssertEqual(t, 0, len(ret.Warnings), "should be ok")
+
+	ret = stripTs(t, `import type { T } from "t";
+const a: T = 1;
+export = a;`, false, nil)
+	AssertEqual(t, 0, len(ret.Warnings), "should be ok")
+
+	ret = stripTs(t, `import b from "b";
+const a = b;
+export = a;`, false, nil)
+	AssertEqualString(t, `import b from "b";
+const a = b;
+module.exports = a;`, ret.Code, "should be ok")
+	AssertEqual(t, 1, len(ret.Warnings), "should be ok")
+	AssertEqual(t, "export assignment can not be used in the ES modules, consider using `export default` instead at (3:0)", ret.Warnings[0].Error(), "should be ok")
+}
+
+func TestTsStripJsx(t *testing.T) {
+	ret := stripTs(t, `import React from "react";
+import { Props } from "./types";
+import { Button } from "./ui";
+const App = (p: Props) => <Button<string> onClick={() => p.x!}>{p.label as string}</Button>;`, true, nil)
+
+	AssertEqualString(t, `import React from "react";
+import { Button } from "./ui";
+const App = (p) => <Button onClick={() => p.x}>{p.label}</Button>;`, ret.Code, "should be ok")
+}
+
+func TestTsStripKeepTokensApart(t *testing.T) {
+	ret := stripTs(t, `function f() { return<any>x; }`, false, nil)
+	AssertEqualString(t, `function f() { return x; }`, ret.Code, "should be ok")
+}
+
+func TestTsStripSourceMap(t *testing.T) {
+	ret := stripTs(t, `interface I {}
+let a: number = 1;
+let b = a as any;`, false, nil)
+
+	AssertEqualString(t, `let a = 1;
+let b = a;`, ret.Code, "should be ok")
+
+	ms := DecodeMappings(ret.Map.Mappings)
+	find := func(line, col int) *Mapping {
+		var ret *Mapping
+		for i, m := range ms {
+			if m.GenLine == line && m.GenCol <= col {
+				ret = &ms[i]
+			}
+		}
+		return ret
+	}
+
+	// `let a` in the output maps to the 2nd line of the source
+	m := find(0, 0)
+	AssertEqual(t, 1, m.SrcLine, "should be ok")
+	AssertEqual(t, 0, m.SrcCol, "should be ok")
+
+	// `= 1` is after the removed annotation
+	m = find(0, 6)
+	AssertEqual(t, 1, m.SrcLine, "should be ok")
+	AssertEqual(t, 14, m.SrcCol+(6-m.GenCol), "should be ok")
+
+	m = find(1, 0)
+	AssertEqual(t, 2, m.SrcLine, "should be ok")
+}
@@ -0,0 +1,75 @@
+package tsconfig
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// the wildcard pattern of the `include` and `exclude`, `*` matches zero or more characters
+// except the separators, `?` matches one character except the separators and `**/` matches
+// any directory nested to any level
+type glob struct {
+	root string // the directory to walk, it's the longest path of the pattern without wildcards
+	re   *regexp.Regexp
+}
+
+func hasWildcard(s string) bool {
+	return strings.ContainsAny(s, "*?")
+}
+
+// `include` indicates whether the pattern is used by the `include`, the patterns of `include`
+// whose last segments have neither wildcards nor extensions are regarded as the directories,
+// the patterns of `exclude` match the paths and all the paths under them
+func newGlob(pattern string, include bool) *glob {
+	segs := strings.Split(filepath.ToSlash(pattern), "/")
+	last := segs[len(segs)-1]
+	if include && !hasWildcard(last) && !strings.Contains(last, ".") {
+		segs = append(segs, "**", "*")
+	}
+
+	root := make([]string, 0, len(segs))
+	for _, seg := range segs {
+		if hasWildcard(seg) {
+			break
+		}
+		root = append(root, seg)
+	}
+
+	var b strings.Builder
+	b.WriteString("^")
+	for i, seg := range segs {
+		if seg == "**" {
+			// `a/**/b` matches `a/b`
+			b.WriteString("(/[^/]+)*")
+			continue
+		}
+		if i > 0 {
+			b.WriteString("/")
+		}
+		for _, c := range seg {
+			switch c {
+			case '*':
+				b.WriteString("[^/]*")
+			case '?':
+				b.WriteString("[^/]")
+			default:
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+	}
+	if !include {
+		b.WriteString("(/.*)?")
+	}
+	b.WriteString("$")
+
+	rootDir := strings.Join(root, "/")
+	if rootDir == "" {
+		rootDir = "/"
+	}
+	return &glob{filepath.FromSlash(rootDir), regexp.MustCompile(b.String())}
+}
+
+func (g *glob) match(pth string) bool {
+	return g.re.MatchString(filepath.ToSlash(pth))
+}
@@ -0,0 +1,475 @@
+// Package tsconfig loads the `tsconfig.json` of the typescript projects, the `extends` chains
+// are followed and the options are mapped onto the `ParserOpts`, the file list of the project
+// is resolved from the `files`, `include` and `exclude` in the same way as tsc
+package tsconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	"github.com/hsiaosiyuan0/mole/util"
+)
+
+// the compiler options which are concerned by mole, the unknown options are ignored, the
+// paths in the options are resolved to the absolute paths relative to the config file which
+// declares them
+type CompilerOptions struct {
+	Target                 string              `json:"target"`
+	Module                 string              `json:"module"`
+	Jsx                    string              `json:"jsx"`
+	ExperimentalDecorators bool                `json:"experimentalDecorators"`
+	EmitDecoratorMetadata  bool                `json:"emitDecoratorMetadata"`
+	AllowJs                bool                `json:"allowJs"`
+	BaseUrl                string              `json:"baseUrl"`
+	Paths                  map[string][]string `json:"paths"`
+	RootDir                string              `json:"rootDir"`
+	OutDir                 string              `json:"outDir"`
+	DeclarationDir         string              `json:"declarationDir"`
+
+	// the directory which the `paths` are relative to, it's the `baseUrl` if it's specified
+	// otherwise the directory of the config file declares the `paths`
+	PathsBase string `json:"-"`
+}
+
+type Config struct {
+	File            string // the absolute path of the config file
+	CompilerOptions *CompilerOptions
+
+	// the absolute paths of the files and the patterns, they're nil if they're not specified
+	// in the config file and the ones it extends
+	Files   []string
+	Include []string
+	Exclude []string
+}
+
+func (c *Config) Dir() string {
+	return filepath.Dir(c.File)
+}
+
+// the raw config file, the fields are kept as raw to know whether they're specified
+type rawConfig struct {
+	Extends         json.RawMessage            `json:"extends"`
+	CompilerOptions map[string]json.RawMessage `json:"compilerOptions"`
+	Files           []string                   `json:"files"`
+	Include         []string                   `json:"include"`
+	Exclude         []string                   `json:"exclude"`
+}
+
+// the options whose values are the paths relative to the config file
+var pathOpts = []string{"baseUrl", "rootDir", "outDir", "declarationDir"}
+
+// searches `tsconfig.json` from the directory up to the root, it's empty if not found
+func Find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		file := filepath.Join(dir, "tsconfig.json")
+		if isFile(file) {
+			return file
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+func isFile(file string) bool {
+	info, err := os.Stat(file)
+	return err == nil && !info.IsDir()
+}
+
+// loads the config file, `file` can be the directory contains `tsconfig.json`
+func Load(file string) (*Config, error) {
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	if info, err := os.Stat(file); err == nil && info.IsDir() {
+		file = filepath.Join(file, "tsconfig.json")
+	}
+
+	l := &loader{visiting: map[string]bool{}}
+	opts, cfg, err := l.load(file)
+	if err != nil {
+		return nil, err
+	}
+
+	co := &CompilerOptions{}
+	b, _ := json.Marshal(opts)
+	if err := json.Unmarshal(b, co); err != nil {
+		return nil, fmt.Errorf("%s: invalid compilerOptions: %w", file, err)
+	}
+	co.PathsBase = l.pathsBase
+	if co.BaseUrl != "" {
+		co.PathsBase = co.BaseUrl
+	}
+	cfg.CompilerOptions = co
+	return cfg, nil
+}
+
+type loader struct {
+	visiting  map[string]bool
+	pathsBase string
+}
+
+// loads the config file and the ones it extends, the compiler options are returned as the
+// raw values merged along the chain
+func (l *loader) load(file string) (map[string]json.RawMessage, *Config, error) {
+	if l.visiting[file] {
+		return nil, nil, fmt.Errorf("circularity is detected while resolving the `extends` of %s", file)
+	}
+	l.visiting[file] = true
+	defer delete(l.visiting, file)
+
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err = util.RemoveJsonComments(string(b))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+	var raw rawConfig
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	opts := map[string]json.RawMessage{}
+	cfg := &Config{File: file}
+	for _, base := range extendsOf(raw.Extends) {
+		baseFile := resolveExtends(filepath.Dir(file), base)
+		if baseFile == "" {
+			return nil, nil, fmt.Errorf("%s: cannot find the config `%s` to extend", file, base)
+		}
+		bopts, bcfg, err := l.load(baseFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		for k, v := range bopts {
+			opts[k] = v
+		}
+		if bcfg.Files != nil {
+			cfg.Files = bcfg.Files
+		}
+		if bcfg.Include != nil {
+			cfg.Include = bcfg.Include
+		}
+		if bcfg.Exclude != nil {
+			cfg.Exclude = bcfg.Exclude
+		}
+	}
+
+	dir := filepath.Dir(file)
+	for k, v := range raw.CompilerOptions {
+		for _, po := range pathOpts {
+			if k == po {
+				var s string
+				if json.Unmarshal(v, &s) == nil {
+					v, _ = json.Marshal(absPath(dir, s))
+				}
+			}
+		}
+		if k == "paths" {
+			l.pathsBase = dir
+		}
+		opts[k] = v
+	}
+	if raw.Files != nil {
+		cfg.Files = absPaths(dir, raw.Files)
+	}
+	if raw.Include != nil {
+		cfg.Include = absPaths(dir, raw.Include)
+	}
+	if raw.Exclude != nil {
+		cfg.Exclude = absPaths(dir, raw.Exclude)
+	}
+	return opts, cfg, nil
+}
+
+// `extends` is a string or an array of strings since typescript 5.0
+func extendsOf(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return []string{s}
+	}
+	var ss []string
+	json.Unmarshal(raw, &ss)
+	return ss
+}
+
+func absPath(dir, pth string) string {
+	if filepath.IsAbs(pth) {
+		return filepath.Clean(pth)
+	}
+	return filepath.Join(dir, filepath.FromSlash(pth))
+}
+
+func absPaths(dir string, pths []string) []string {
+	ret := make([]string, len(pths))
+	for i, p := range pths {
+		ret[i] = absPath(dir, p)
+	}
+	return ret
+}
+
+// resolves the config to extend, the relative paths are resolved against the directory of the
+// config file and the others are looked up in the `node_modules`
+func resolveExtends(dir, spec string) string {
+	candidates := func(base string) []string {
+		if strings.HasSuffix(base, ".json") {
+			return []string{base}
+		}
+		return []string{base, base + ".json", filepath.Join(base, "tsconfig.json")}
+	}
+
+	if filepath.IsAbs(spec) || strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "../") {
+		for _, c := range candidates(absPath(dir, spec)) {
+			if isFile(c) {
+				return c
+			}
+		}
+		return ""
+	}
+
+	for d := dir; ; {
+		nm := filepath.Join(d, "node_modules")
+		for _, c := range candidates(filepath.Join(nm, filepath.FromSlash(spec))) {
+			if isFile(c) {
+				return c
+			}
+		}
+		// the `tsconfig` field of the `package.json` of the package
+		if b, err := os.ReadFile(filepath.Join(nm, filepath.FromSlash(spec), "package.json")); err == nil {
+			var pj struct {
+				Tsconfig string `json:"tsconfig"`
+			}
+			if json.Unmarshal(b, &pj) == nil && pj.Tsconfig != "" {
+				if c := filepath.Join(nm, filepath.FromSlash(spec), filepath.FromSlash(pj.Tsconfig)); isFile(c) {
+					return c
+				}
+			}
+		}
+		parent := filepath.Dir(d)
+		if parent == d {
+			return ""
+		}
+		d = parent
+	}
+}
+
+var targets = map[string]parser.ESVersion{
+	"es3":    parser.ES5,
+	"es5":    parser.ES5,
+	"es6":    parser.ES6,
+	"es2015": parser.ES6,
+	"es2016": parser.ES7,
+	"es2017": parser.ES8,
+	"es2018": parser.ES9,
+	"es2019": parser.ES10,
+	"es2020": parser.ES11,
+	"es2021": parser.ES12,
+	"es2022": parser.ES13,
+	"es2023": parser.ES14,
+	"es2024": parser.ES15,
+	"es2025": parser.ES16,
+	"esnext": parser.ESNEXT,
+}
+
+// the version of the `target`, the targets unknown by mole like the ones newer than the latest
+// edition are regarded as `esnext`
+func (c *Config) Version() parser.ESVersion {
+	t := strings.ToLower(c.CompilerOptions.Target)
+	if v, ok := targets[t]; ok {
+		return v
+	}
+	if t == "" {
+		// the default target of tsc
+		return parser.ES5
+	}
+	return parser.ESNEXT
+}
+
+// the options to parse the files in the project, use `ParserOptsOf` to get the ones for the
+// specific file which take its extension into account
+func (c *Config) ParserOpts() *parser.ParserOpts {
+	co := c.CompilerOptions
+	opts := parser.NewParserOpts()
+	opts.Version = c.Version()
+	opts.Feature = opts.Feature.On(parser.FEAT_TS).Turn(parser.FEAT_JSX, co.Jsx != "")
+	if co.ExperimentalDecorators {
+		opts.Feature = opts.Feature.On(parser.FEAT_DECORATOR)
+	}
+	if strings.ToLower(co.Module) == "none" {
+		opts.Feature = opts.Feature.Off(parser.FEAT_MODULE)
+	}
+	return opts
+}
+
+func (c *Config) ParserOptsOf(file string) *parser.ParserOpts {
+	opts := c.ParserOpts()
+	if isJs(file) {
+		// the javascript files are compiled to the target as well, so its syntax is not
+		// restricted by the target
+		opts.Version = 0
+	}
+	switch {
+	case isDts(file):
+		opts.Feature = opts.Feature.On(parser.FEAT_DTS).Off(parser.FEAT_JSX)
+	case strings.HasSuffix(file, ".tsx"):
+		opts.Feature = opts.Feature.On(parser.FEAT_JSX)
+	case strings.HasSuffix(file, ".jsx"):
+		opts.Feature = opts.Feature.Off(parser.FEAT_TS).On(parser.FEAT_JSX)
+	case isJs(file):
+		opts.Feature = opts.Feature.Off(parser.FEAT_TS)
+	default:
+		opts.Feature = opts.Feature.Off(parser.FEAT_JSX)
+	}
+	return opts
+}
+
+var tsExts = []string{".ts", ".tsx", ".mts", ".cts"}
+var jsExts = []string{".js", ".jsx", ".mjs", ".cjs"}
+
+func isDts(file string) bool {
+	return strings.HasSuffix(file, ".d.ts") || strings.HasSuffix(file, ".d.mts") || strings.HasSuffix(file, ".d.cts")
+}
+
+func isJs(file string) bool {
+	return util.Includes(jsExts, filepath.Ext(file))
+}
+
+// the source files of the project, the `files` are always included and the files matched by
+// the `include` are filtered by the `exclude`, the javascript files are included only if the
+// `allowJs` is on
+func (c *Config) FileNames() ([]string, error) {
+	seen := map[string]bool{}
+	ret := make([]string, 0)
+	for _, f := range c.Files {
+		if !isFile(f) {
+			return nil, fmt.Errorf("%s: file not found: %s", c.File, f)
+		}
+		if !seen[f] {
+			seen[f] = true
+			ret = append(ret, f)
+		}
+	}
+
+	include := c.Include
+	if include == nil {
+		if c.Files != nil {
+			return ret, nil
+		}
+		include = []string{filepath.Join(c.Dir(), "**", "*")}
+	}
+	exclude := c.Exclude
+	if exclude == nil {
+		exclude = []string{
+			filepath.Join(c.Dir(), "node_modules"),
+			filepath.Join(c.Dir(), "bower_components"),
+			filepath.Join(c.Dir(), "jspm_packages"),
+		}
+		if c.CompilerOptions.OutDir != "" {
+			exclude = append(exclude, c.CompilerOptions.OutDir)
+		}
+		if c.CompilerOptions.DeclarationDir != "" {
+			exclude = append(exclude, c.CompilerOptions.DeclarationDir)
+		}
+	}
+
+	exts := tsExts
+	if c.CompilerOptions.AllowJs {
+		exts = append(append([]string{}, tsExts...), jsExts...)
+	}
+
+	inc := make([]*glob, len(include))
+	for i, p := range include {
+		inc[i] = newGlob(p, true)
+	}
+	exc := make([]*glob, len(exclude))
+	for i, p := range exclude {
+		exc[i] = newGlob(p, false)
+	}
+
+	for _, g := range inc {
+		err := filepath.WalkDir(g.root, func(pth string, d os.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			}
+			for _, e := range exc {
+				if e.match(pth) {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+			}
+			if d.IsDir() {
+				// the hidden directories are skipped unless they are specified explicitly
+				if pth != g.root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if seen[pth] || !util.Includes(exts, filepath.Ext(pth)) || !g.match(pth) {
+				return nil
+			}
+			seen[pth] = true
+			ret = append(ret, pth)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// the candidates of the module specifier mapped by the `paths`, the specifier which matches
+// none of the patterns is resolved against the `baseUrl` if it's specified
+func (c *Config) ResolvePaths(spec string) []string {
+	co := c.CompilerOptions
+	best, bestLen, star := "", -1, ""
+	for pattern := range co.Paths {
+		i := strings.IndexByte(pattern, '*')
+		if i < 0 {
+			if pattern == spec && len(pattern) > bestLen {
+				best, bestLen, star = pattern, len(pattern), ""
+			}
+			continue
+		}
+		prefix, suffix := pattern[:i], pattern[i+1:]
+		if len(spec) >= len(prefix)+len(suffix) && strings.HasPrefix(spec, prefix) && strings.HasSuffix(spec, suffix) {
+			// the pattern with the longest prefix wins
+			if len(prefix) > bestLen {
+				best, bestLen, star = pattern, len(prefix), spec[len(prefix):len(spec)-len(suffix)]
+			}
+		}
+	}
+
+	if bestLen >= 0 {
+		ret := make([]string, 0, len(co.Paths[best]))
+		for _, sub := range co.Paths[best] {
+			ret = append(ret, absPath(co.PathsBase, strings.Replace(sub, "*", star, 1)))
+		}
+		return ret
+	}
+	if co.BaseUrl != "" {
+		return []string{absPath(co.BaseUrl, spec)}
+	}
+	return nil
+}
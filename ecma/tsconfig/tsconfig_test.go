@@ -0,0 +1,179 @@
+package tsconfig
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hsiaosiyuan0/mole/ecma/parser"
+	. "github.com/hsiaosiyuan0/mole/util"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir := t.TempDir()
+	for name, code := range files {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(code), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func rels(dir string, files []string) string {
+	ret := make([]string, len(files))
+	for i, f := range files {
+		rel, _ := filepath.Rel(dir, f)
+		ret[i] = filepath.ToSlash(rel)
+	}
+	return strings.Join(ret, ",")
+}
+
+func TestExtends(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"node_modules/@tsconfig/base/tsconfig.json": `{
+  // the shared config
+  "compilerOptions": { "target": "es2017", "jsx": "react", "baseUrl": "." },
+  "exclude": ["dist"],
+}`,
+		"configs/strict.json": `{
+  "extends": "@tsconfig/base/tsconfig.json",
+  "compilerOptions": {
+    /* overrides the base */
+    "target": "ES2020",
+    "paths": { "@/*": ["src/*"] },
+  },
+}`,
+		"tsconfig.json": `{
+  "extends": "./configs/strict",
+  "compilerOptions": { "experimentalDecorators": true, "module": "none" },
+  "include": ["src"]
+}`,
+	})
+
+	cfg, err := Load(dir)
+	AssertEqual(t, nil, err, "should be ok")
+	co := cfg.CompilerOptions
+	AssertEqual(t, "ES2020", co.Target, "should be ok")
+	AssertEqual(t, "react", co.Jsx, "should be ok")
+	AssertEqual(t, true, co.ExperimentalDecorators, "should be ok")
+	AssertEqual(t, filepath.Join(dir, "node_modules/@tsconfig/base"), co.BaseUrl, "should be ok")
+	AssertEqual(t, co.BaseUrl, co.PathsBase, "should be ok")
+	AssertEqual(t, filepath.Join(dir, "src"), cfg.Include[0], "should be ok")
+	AssertEqual(t, filepath.Join(dir, "node_modules/@tsconfig/base/dist"), cfg.Exclude[0], "should be ok")
+
+	opts := cfg.ParserOpts()
+	AssertEqual(t, parser.ES11, opts.Version, "should be ok")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_TS != 0, "should be ok")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_JSX != 0, "should be ok")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_MODULE == 0, "should be ok")
+
+	opts = cfg.ParserOptsOf("a.ts")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_JSX == 0, "should be ok")
+	opts = cfg.ParserOptsOf("a.d.ts")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_DTS != 0, "should be ok")
+	opts = cfg.ParserOptsOf("a.jsx")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_TS == 0, "should be ok")
+	AssertEqual(t, true, opts.Feature&parser.FEAT_JSX != 0, "should be ok")
+	AssertEqual(t, parser.ESVersion(0), opts.Version, "should be ok")
+}
+
+func TestVersion(t *testing.T) {
+	cases := map[string]parser.ESVersion{
+		"":       parser.ES5,
+		"ES3":    parser.ES5,
+		"es2015": parser.ES6,
+		"es2022": parser.ES13,
+		"es2023": parser.ES14,
+		"ES2024": parser.ES15,
+		"es2025": parser.ES16,
+		"ESNext": parser.ESNEXT,
+		"es2099": parser.ESNEXT,
+	}
+	for target, ver := range cases {
+		cfg := &Config{CompilerOptions: &CompilerOptions{Target: target}}
+		AssertEqual(t, ver, cfg.Version(), "should be ok: "+target)
+	}
+}
+
+func TestExtendsErr(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.json":        `{ "extends": "./tsconfig.json" }`,
+		"tsconfig.json": `{ "extends": "./a.json" }`,
+	})
+	_, err := Load(dir)
+	AssertEqual(t, true, strings.Contains(err.Error(), "circularity"), "should be failed")
+
+	dir = writeFiles(t, map[string]string{
+		"tsconfig.json": `{ "extends": "missing" }`,
+	})
+	_, err = Load(dir)
+	AssertEqual(t, true, strings.Contains(err.Error(), "cannot find the config `missing`"), "should be failed")
+}
+
+func TestFileNames(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"tsconfig.json": `{
+  "compilerOptions": { "outDir": "lib" },
+  "include": ["src", "types/**/*.d.ts", "scripts/*.ts"],
+  "exclude": ["src/**/*.test.ts", "src/legacy"],
+  "files": ["extra/main.ts"]
+}`,
+		"src/a.ts":          "",
+		"src/b.tsx":         "",
+		"src/c.js":          "",
+		"src/a.test.ts":     "",
+		"src/legacy/d.ts":   "",
+		"src/nested/e.mts":  "",
+		"types/g.d.ts":      "",
+		"types/h.ts":        "",
+		"scripts/i.ts":      "",
+		"scripts/sub/j.ts":  "",
+		"extra/main.ts":     "",
+		"lib/out.ts":        "",
+		"node_modules/m.ts": "",
+	})
+	cfg, err := Load(filepath.Join(dir, "tsconfig.json"))
+	AssertEqual(t, nil, err, "should be ok")
+	files, err := cfg.FileNames()
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, "extra/main.ts,src/a.ts,src/b.tsx,src/nested/e.mts,types/g.d.ts,scripts/i.ts", rels(dir, files), "should be ok")
+
+	cfg.CompilerOptions.AllowJs = true
+	cfg.Include, cfg.Exclude, cfg.Files = nil, nil, nil
+	files, err = cfg.FileNames()
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, "extra/main.ts,scripts/i.ts,scripts/sub/j.ts,src/a.test.ts,src/a.ts,src/b.tsx,src/c.js,src/legacy/d.ts,src/nested/e.mts,types/g.d.ts,types/h.ts", rels(dir, files), "should be ok")
+}
+
+func TestPaths(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"tsconfig.json": `{
+  "compilerOptions": {
+    "paths": {
+      "@/*": ["src/*", "gen/*"],
+      "@/utils/*": ["lib/utils/*"],
+      "jquery": ["vendor/jquery.d.ts"]
+    }
+  }
+}`,
+	})
+	cfg, err := Load(dir)
+	AssertEqual(t, nil, err, "should be ok")
+	AssertEqual(t, "src/a/b,gen/a/b", rels(dir, cfg.ResolvePaths("@/a/b")), "should be ok")
+	AssertEqual(t, "lib/utils/x", rels(dir, cfg.ResolvePaths("@/utils/x")), "should be ok")
+	AssertEqual(t, "vendor/jquery.d.ts", rels(dir, cfg.ResolvePaths("jquery")), "should be ok")
+	AssertEqual(t, 0, len(cfg.ResolvePaths("react")), "should be ok")
+}
+
+func TestFind(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"tsconfig.json": "{}",
+		"src/a/b.ts":    "",
+	})
+	AssertEqual(t, filepath.Join(dir, "tsconfig.json"), Find(filepath.Join(dir, "src/a")), "should be ok")
+}
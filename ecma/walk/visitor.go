@@ -34,6 +34,7 @@ const (
 	N_EXPR_UPDATE           = parser.N_EXPR_UPDATE
 	N_EXPR_YIELD            = parser.N_EXPR_YIELD
 	N_FIELD                 = parser.N_FIELD
+	N_IMPORT_ATTR           = parser.N_IMPORT_ATTR
 	N_IMPORT_CALL           = parser.N_IMPORT_CALL
 	N_IMPORT_SPEC           = parser.N_IMPORT_SPEC
 	N_JSX_ATTR              = parser.N_JSX_ATTR
@@ -97,10 +98,12 @@ const (
 	N_TS_COND               = parser.N_TS_COND
 	N_TS_DEC_CLASS          = parser.N_TS_DEC_CLASS
 	N_TS_DEC_ENUM           = parser.N_TS_DEC_ENUM
+	N_TS_DEC_EXPORT         = parser.N_TS_DEC_EXPORT
 	N_TS_DEC_FN             = parser.N_TS_DEC_FN
 	N_TS_DEC_GLOBAL         = parser.N_TS_DEC_GLOBAL
 	N_TS_DEC_INTERFACE      = parser.N_TS_DEC_INTERFACE
 	N_TS_DEC_MODULE         = parser.N_TS_DEC_MODULE
+	N_TS_DEC_MOD_EXPS       = parser.N_TS_DEC_MOD_EXPS
 	N_TS_DEC_NS             = parser.N_TS_DEC_NS
 	N_TS_DEC_TYP_DEC        = parser.N_TS_DEC_TYP_DEC
 	N_TS_DEC_VAR_DEC        = parser.N_TS_DEC_VAR_DEC
@@ -203,6 +206,8 @@ const (
 	N_EXPR_YIELD_AFTER             = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_EXPR_YIELD)*2
 	N_FIELD_BEFORE                 = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_FIELD)*2 - 1
 	N_FIELD_AFTER                  = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_FIELD)*2
+	N_IMPORT_ATTR_BEFORE           = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_ATTR)*2 - 1
+	N_IMPORT_ATTR_AFTER            = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_ATTR)*2
 	N_IMPORT_CALL_BEFORE           = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_CALL)*2 - 1
 	N_IMPORT_CALL_AFTER            = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_CALL)*2
 	N_IMPORT_SPEC_BEFORE           = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_SPEC)*2 - 1
@@ -329,6 +334,8 @@ const (
 	N_TS_DEC_CLASS_AFTER           = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_CLASS)*2
 	N_TS_DEC_ENUM_BEFORE           = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_ENUM)*2 - 1
 	N_TS_DEC_ENUM_AFTER            = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_ENUM)*2
+	N_TS_DEC_EXPORT_BEFORE         = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_EXPORT)*2 - 1
+	N_TS_DEC_EXPORT_AFTER          = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_EXPORT)*2
 	N_TS_DEC_FN_BEFORE             = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_FN)*2 - 1
 	N_TS_DEC_FN_AFTER              = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_FN)*2
 	N_TS_DEC_GLOBAL_BEFORE         = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_GLOBAL)*2 - 1
@@ -337,6 +344,8 @@ const (
 	N_TS_DEC_INTERFACE_AFTER       = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_INTERFACE)*2
 	N_TS_DEC_MODULE_BEFORE         = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MODULE)*2 - 1
 	N_TS_DEC_MODULE_AFTER          = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MODULE)*2
+	N_TS_DEC_MOD_EXPS_BEFORE       = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MOD_EXPS)*2 - 1
+	N_TS_DEC_MOD_EXPS_AFTER        = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MOD_EXPS)*2
 	N_TS_DEC_NS_BEFORE             = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_NS)*2 - 1
 	N_TS_DEC_NS_AFTER              = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_NS)*2
 	N_TS_DEC_TYP_DEC_BEFORE        = N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_TYP_DEC)*2 - 1
@@ -503,6 +512,7 @@ var NodeTypes = map[parser.NodeType]bool{
 	N_EXPR_UPDATE:           true,
 	N_EXPR_YIELD:            true,
 	N_FIELD:                 true,
+	N_IMPORT_ATTR:           true,
 	N_IMPORT_CALL:           true,
 	N_IMPORT_SPEC:           true,
 	N_JSX_ATTR:              true,
@@ -566,10 +576,12 @@ var NodeTypes = map[parser.NodeType]bool{
 	N_TS_COND:               true,
 	N_TS_DEC_CLASS:          true,
 	N_TS_DEC_ENUM:           true,
+	N_TS_DEC_EXPORT:         true,
 	N_TS_DEC_FN:             true,
 	N_TS_DEC_GLOBAL:         true,
 	N_TS_DEC_INTERFACE:      true,
 	N_TS_DEC_MODULE:         true,
+	N_TS_DEC_MOD_EXPS:       true,
 	N_TS_DEC_NS:             true,
 	N_TS_DEC_TYP_DEC:        true,
 	N_TS_DEC_VAR_DEC:        true,
@@ -671,6 +683,7 @@ var NodeBeforeEvents = map[parser.NodeType]bool{
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_EXPR_UPDATE)*2 - 1:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_EXPR_YIELD)*2 - 1:            true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_FIELD)*2 - 1:                 true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_ATTR)*2 - 1:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_CALL)*2 - 1:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_SPEC)*2 - 1:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_JSX_ATTR)*2 - 1:              true,
@@ -734,10 +747,12 @@ var NodeBeforeEvents = map[parser.NodeType]bool{
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_COND)*2 - 1:               true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_CLASS)*2 - 1:          true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_ENUM)*2 - 1:           true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_EXPORT)*2 - 1:         true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_FN)*2 - 1:             true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_GLOBAL)*2 - 1:         true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_INTERFACE)*2 - 1:      true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MODULE)*2 - 1:         true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MOD_EXPS)*2 - 1:       true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_NS)*2 - 1:             true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_TYP_DEC)*2 - 1:        true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_VAR_DEC)*2 - 1:        true,
@@ -817,6 +832,7 @@ var NodeAfterEvents = map[parser.NodeType]bool{
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_EXPR_UPDATE)*2:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_EXPR_YIELD)*2:            true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_FIELD)*2:                 true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_ATTR)*2:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_CALL)*2:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_IMPORT_SPEC)*2:           true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_JSX_ATTR)*2:              true,
@@ -880,10 +896,12 @@ var NodeAfterEvents = map[parser.NodeType]bool{
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_COND)*2:               true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_CLASS)*2:          true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_ENUM)*2:           true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_EXPORT)*2:         true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_FN)*2:             true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_GLOBAL)*2:         true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_INTERFACE)*2:      true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MODULE)*2:         true,
+	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_MOD_EXPS)*2:       true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_NS)*2:             true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_TYP_DEC)*2:        true,
 	N_BEFORE_AFTER_DEF_BEGIN + (parser.N_NODE_DEF_END-N_TS_DEC_VAR_DEC)*2:        true,
@@ -1007,487 +1025,483 @@ func AddAtomListener(ls *Listeners, impl *Listener) {
 	}
 }
 
-func VisitTsNsName(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsNsName)
+func VisitCatch(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Catch)
 
-	CallVisitor(N_TS_NS_NAME_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_NS_NAME_AFTER, n, key, ctx)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	VisitNode(n.Lhs(), "Lhs", ctx)
+	CallVisitor(N_CATCH_BEFORE, n, key, ctx)
+	defer CallVisitor(N_CATCH_AFTER, n, key, ctx)
+
+	VisitNode(n.Param(), "Param", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Rhs(), "Rhs", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsNsNameBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NS_NAME_BEFORE, node, key, ctx)
+func VisitCatchBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_CATCH_BEFORE, node, key, ctx)
 }
 
-func VisitTsNsNameAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NS_NAME_AFTER, node, key, ctx)
+func VisitCatchAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_CATCH_AFTER, node, key, ctx)
 }
 
-func VisitTsIdxSig(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsIdxSig)
-
-	CallVisitor(N_TS_IDX_SIG_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_IDX_SIG_AFTER, n, key, ctx)
+func VisitClassBody(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ClassBody)
 
-	VisitNode(n.Key(), "Key", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	VisitNode(n.KeyType(), "KeyType", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_CLASS_BODY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_CLASS_BODY_AFTER, n, key, ctx)
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsIdxSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IDX_SIG_BEFORE, node, key, ctx)
+func VisitClassBodyBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_CLASS_BODY_BEFORE, node, key, ctx)
 }
 
-func VisitTsIdxSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IDX_SIG_AFTER, node, key, ctx)
+func VisitClassBodyAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_CLASS_BODY_AFTER, node, key, ctx)
 }
 
-func VisitTsInterfaceBody(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsInterfaceBody)
+func VisitDecorator(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Decorator)
 
-	CallVisitor(N_TS_INTERFACE_BODY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_INTERFACE_BODY_AFTER, n, key, ctx)
+	CallVisitor(N_DECORATOR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_DECORATOR_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Body(), "Body", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsInterfaceBodyBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_INTERFACE_BODY_BEFORE, node, key, ctx)
+func VisitDecoratorBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_DECORATOR_BEFORE, node, key, ctx)
 }
 
-func VisitTsInterfaceBodyAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_INTERFACE_BODY_AFTER, node, key, ctx)
+func VisitDecoratorAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_DECORATOR_AFTER, node, key, ctx)
 }
 
-func VisitTsDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsDec)
+func VisitExportSpec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ExportSpec)
 
-	CallVisitor(N_TS_DEC_TYP_DEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_DEC_TYP_DEC_AFTER, n, key, ctx)
+	CallVisitor(N_EXPORT_SPEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPORT_SPEC_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Local(), "Local", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Inner(), "Inner", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_DEC_TYP_DEC_BEFORE, node, key, ctx)
+func VisitExportSpecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPORT_SPEC_BEFORE, node, key, ctx)
 }
 
-func VisitTsDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_DEC_TYP_DEC_AFTER, node, key, ctx)
+func VisitExportSpecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPORT_SPEC_AFTER, node, key, ctx)
 }
 
-func VisitSuper(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_SUPER_BEFORE, node, key, ctx)
-	CallListener(N_SUPER_AFTER, node, key, ctx)
-}
+func VisitArrowFn(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ArrowFn)
 
-func VisitJsxMember(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxMember)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	CallVisitor(N_JSX_MEMBER_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_MEMBER_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_ARROW_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_ARROW_AFTER, n, key, ctx)
 
-	VisitNode(n.Obj(), "Obj", ctx)
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Prop(), "Prop", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_MEMBER_BEFORE, node, key, ctx)
+func VisitArrowFnBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_ARROW_BEFORE, node, key, ctx)
 }
 
-func VisitJsxMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_MEMBER_AFTER, node, key, ctx)
+func VisitArrowFnAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_ARROW_AFTER, node, key, ctx)
 }
 
-func VisitTsTuple(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTuple)
+func VisitAssignExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.AssignExpr)
 
-	CallVisitor(N_TS_TUPLE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TUPLE_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_ASSIGN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_ASSIGN_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Args(), "Args", ctx)
+	VisitNode(n.Lhs(), "Lhs", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Rhs(), "Rhs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTupleBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TUPLE_BEFORE, node, key, ctx)
+func VisitAssignExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_ASSIGN_BEFORE, node, key, ctx)
 }
 
-func VisitTsTupleAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TUPLE_AFTER, node, key, ctx)
+func VisitAssignExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_ASSIGN_AFTER, node, key, ctx)
 }
 
-func VisitTsObj(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsObj)
+func VisitBinExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.BinExpr)
 
-	CallVisitor(N_TS_LIT_OBJ_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_LIT_OBJ_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_BIN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_BIN_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Props(), "Props", ctx)
+	VisitNode(n.Lhs(), "Lhs", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Rhs(), "Rhs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsObjBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_LIT_OBJ_BEFORE, node, key, ctx)
+func VisitBinExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_BIN_BEFORE, node, key, ctx)
 }
 
-func VisitTsObjAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_LIT_OBJ_AFTER, node, key, ctx)
+func VisitBinExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_BIN_AFTER, node, key, ctx)
 }
 
-func VisitTsArr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsArr)
+func VisitCallExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.CallExpr)
 
-	CallVisitor(N_TS_ARR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_ARR_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_CALL_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_CALL_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Callee(), "Callee", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNodes(n, n.Args(), "Args", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsArrBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ARR_BEFORE, node, key, ctx)
+func VisitCallExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CALL_BEFORE, node, key, ctx)
 }
 
-func VisitTsArrAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ARR_AFTER, node, key, ctx)
+func VisitCallExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CALL_AFTER, node, key, ctx)
 }
 
-func VisitProg(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Prog)
+func VisitChainExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ChainExpr)
 
-	CallVisitor(N_PROG_BEFORE, n, key, ctx)
-	defer CallVisitor(N_PROG_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_CHAIN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_CHAIN_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Body(), "Body", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitProgBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PROG_BEFORE, node, key, ctx)
+func VisitChainExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CHAIN_BEFORE, node, key, ctx)
 }
 
-func VisitProgAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PROG_AFTER, node, key, ctx)
+func VisitChainExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CHAIN_AFTER, node, key, ctx)
 }
 
-func VisitExportSpec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ExportSpec)
+func VisitClassDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ClassDec)
 
-	CallVisitor(N_EXPORT_SPEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPORT_SPEC_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_CLASS_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_CLASS_AFTER, n, key, ctx)
 
-	VisitNode(n.Local(), "Local", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Super(), "Super", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
 
-func VisitExportSpecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPORT_SPEC_BEFORE, node, key, ctx)
+	VisitNode(n.Body(), "Body", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 }
 
-func VisitExportSpecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPORT_SPEC_AFTER, node, key, ctx)
+func VisitClassDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CLASS_BEFORE, node, key, ctx)
 }
 
-func VisitTsPredef(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NULL_BEFORE, node, key, ctx)
-	CallListener(N_TS_NULL_AFTER, node, key, ctx)
+func VisitClassDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_CLASS_AFTER, node, key, ctx)
 }
 
-func VisitJsxEmpty(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_EMPTY_BEFORE, node, key, ctx)
-	CallListener(N_JSX_EMPTY_AFTER, node, key, ctx)
-}
+func VisitCondExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.CondExpr)
 
-func VisitTsNS(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsNS)
+	CallVisitor(N_EXPR_COND_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_COND_AFTER, n, key, ctx)
 
-	CallVisitor(N_TS_NAMESPACE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_NAMESPACE_AFTER, n, key, ctx)
+	VisitNode(n.Test(), "Test", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Cons(), "Cons", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNode(n.Alt(), "Alt", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsNSBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NAMESPACE_BEFORE, node, key, ctx)
+func VisitCondExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_COND_BEFORE, node, key, ctx)
 }
 
-func VisitTsNSAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NAMESPACE_AFTER, node, key, ctx)
+func VisitCondExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_COND_AFTER, node, key, ctx)
 }
 
-func VisitRetStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.RetStmt)
+func VisitFnDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.FnDec)
 
-	CallVisitor(N_STMT_RET_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_RET_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_FN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_FN_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
 
-func VisitRetStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_RET_BEFORE, node, key, ctx)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
+
+	VisitNodes(n, n.Params(), "Params", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Body(), "Body", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 }
 
-func VisitRetStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_RET_AFTER, node, key, ctx)
+func VisitFnDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_FN_BEFORE, node, key, ctx)
 }
 
-func VisitDebugStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_DEBUG_BEFORE, node, key, ctx)
-	CallListener(N_STMT_DEBUG_AFTER, node, key, ctx)
+func VisitFnDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_FN_AFTER, node, key, ctx)
 }
 
-func VisitObjPat(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ObjPat)
+func VisitMemberExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.MemberExpr)
 
-	CallVisitor(N_PAT_OBJ_BEFORE, n, key, ctx)
-	defer CallVisitor(N_PAT_OBJ_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_MEMBER_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_MEMBER_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Props(), "Props", ctx)
+	VisitNode(n.Obj(), "Obj", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Prop(), "Prop", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitObjPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_OBJ_BEFORE, node, key, ctx)
+func VisitMemberExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_MEMBER_BEFORE, node, key, ctx)
 }
 
-func VisitObjPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_OBJ_AFTER, node, key, ctx)
+func VisitMemberExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_MEMBER_AFTER, node, key, ctx)
 }
 
-func VisitVarDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.VarDec)
+func VisitNewExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.NewExpr)
 
-	CallVisitor(N_VAR_DEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_VAR_DEC_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_NEW_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_NEW_AFTER, n, key, ctx)
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Callee(), "Callee", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Init(), "Init", ctx)
+	VisitNodes(n, n.Args(), "Args", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitVarDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_VAR_DEC_BEFORE, node, key, ctx)
+func VisitNewExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_NEW_BEFORE, node, key, ctx)
 }
 
-func VisitVarDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_VAR_DEC_AFTER, node, key, ctx)
+func VisitNewExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_NEW_AFTER, node, key, ctx)
 }
 
-func VisitJsxClose(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxClose)
+func VisitParenExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ParenExpr)
 
-	CallVisitor(N_JSX_CLOSE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_CLOSE_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_PAREN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_PAREN_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxCloseBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_CLOSE_BEFORE, node, key, ctx)
+func VisitParenExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_PAREN_BEFORE, node, key, ctx)
 }
 
-func VisitJsxCloseAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_CLOSE_AFTER, node, key, ctx)
+func VisitParenExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_PAREN_AFTER, node, key, ctx)
 }
 
-func VisitTsFnTyp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsFnTyp)
-
-	CallVisitor(N_TS_FN_TYP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_FN_TYP_AFTER, n, key, ctx)
-
-	VisitNode(n.TypParams(), "TypParams", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitSeqExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.SeqExpr)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_EXPR_SEQ_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_SEQ_AFTER, n, key, ctx)
 
-	VisitNode(n.RetTyp(), "RetTyp", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsFnTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_FN_TYP_BEFORE, node, key, ctx)
+func VisitSeqExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_SEQ_BEFORE, node, key, ctx)
 }
 
-func VisitTsFnTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_FN_TYP_AFTER, node, key, ctx)
+func VisitSeqExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_SEQ_AFTER, node, key, ctx)
 }
 
-func VisitDoWhileStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.DoWhileStmt)
+func VisitThisExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_THIS_BEFORE, node, key, ctx)
+	CallListener(N_EXPR_THIS_AFTER, node, key, ctx)
+}
 
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitTplExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TplExpr)
 
-	CallVisitor(N_STMT_DO_WHILE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_DO_WHILE_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_TPL_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_TPL_AFTER, n, key, ctx)
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNode(n.Tag(), "Tag", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Test(), "Test", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitDoWhileStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_DO_WHILE_BEFORE, node, key, ctx)
+func VisitTplExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_TPL_BEFORE, node, key, ctx)
 }
 
-func VisitDoWhileStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_DO_WHILE_AFTER, node, key, ctx)
+func VisitTplExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_TPL_AFTER, node, key, ctx)
 }
 
-func VisitFnDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.FnDec)
-
-	CallVisitor(N_EXPR_FN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_FN_AFTER, n, key, ctx)
-
-	VisitNode(n.Id(), "Id", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitUnaryExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.UnaryExpr)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_EXPR_UNARY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_UNARY_AFTER, n, key, ctx)
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitFnDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_FN_BEFORE, node, key, ctx)
-}
-
-func VisitFnDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_FN_AFTER, node, key, ctx)
+func VisitUnaryExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_UNARY_BEFORE, node, key, ctx)
 }
 
-func VisitIdent(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_NAME_BEFORE, node, key, ctx)
-	CallListener(N_NAME_AFTER, node, key, ctx)
+func VisitUnaryExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_UNARY_AFTER, node, key, ctx)
 }
 
-func VisitChainExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ChainExpr)
+func VisitUpdateExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.UpdateExpr)
 
-	CallVisitor(N_EXPR_CHAIN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_CHAIN_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_UPDATE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_UPDATE_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitChainExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CHAIN_BEFORE, node, key, ctx)
+func VisitUpdateExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_UPDATE_BEFORE, node, key, ctx)
 }
 
-func VisitChainExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CHAIN_AFTER, node, key, ctx)
+func VisitUpdateExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_UPDATE_AFTER, node, key, ctx)
 }
 
-func VisitRestPat(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.RestPat)
+func VisitYieldExpr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.YieldExpr)
 
-	CallVisitor(N_PAT_REST_BEFORE, n, key, ctx)
-	defer CallVisitor(N_PAT_REST_AFTER, n, key, ctx)
+	CallVisitor(N_EXPR_YIELD_BEFORE, n, key, ctx)
+	defer CallVisitor(N_EXPR_YIELD_AFTER, n, key, ctx)
 
 	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
@@ -1495,46 +1509,46 @@ func VisitRestPat(node parser.Node, key string, ctx *VisitorCtx) {
 	}
 }
 
-func VisitRestPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_REST_BEFORE, node, key, ctx)
+func VisitYieldExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_YIELD_BEFORE, node, key, ctx)
 }
 
-func VisitRestPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_REST_AFTER, node, key, ctx)
+func VisitYieldExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_EXPR_YIELD_AFTER, node, key, ctx)
 }
 
-func VisitJsxOpen(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxOpen)
+func VisitField(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Field)
 
-	CallVisitor(N_JSX_OPEN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_OPEN_AFTER, n, key, ctx)
+	CallVisitor(N_FIELD_BEFORE, n, key, ctx)
+	defer CallVisitor(N_FIELD_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNodes(n, n.Attrs(), "Attrs", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxOpenBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_OPEN_BEFORE, node, key, ctx)
+func VisitFieldBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_FIELD_BEFORE, node, key, ctx)
 }
 
-func VisitJsxOpenAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_OPEN_AFTER, node, key, ctx)
+func VisitFieldAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_FIELD_AFTER, node, key, ctx)
 }
 
-func VisitTsImportAlias(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsImportAlias)
+func VisitImportAttr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ImportAttr)
 
-	CallVisitor(N_TS_IMPORT_ALIAS_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_IMPORT_ALIAS_AFTER, n, key, ctx)
+	CallVisitor(N_IMPORT_ATTR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_IMPORT_ATTR_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
@@ -1545,97 +1559,107 @@ func VisitTsImportAlias(node parser.Node, key string, ctx *VisitorCtx) {
 	}
 }
 
-func VisitTsImportAliasBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_ALIAS_BEFORE, node, key, ctx)
+func VisitImportAttrBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_ATTR_BEFORE, node, key, ctx)
 }
 
-func VisitTsImportAliasAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_ALIAS_AFTER, node, key, ctx)
+func VisitImportAttrAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_ATTR_AFTER, node, key, ctx)
 }
 
-func VisitCondExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.CondExpr)
+func VisitImportCall(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ImportCall)
 
-	CallVisitor(N_EXPR_COND_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_COND_AFTER, n, key, ctx)
-
-	VisitNode(n.Test(), "Test", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_IMPORT_CALL_BEFORE, n, key, ctx)
+	defer CallVisitor(N_IMPORT_CALL_AFTER, n, key, ctx)
 
-	VisitNode(n.Cons(), "Cons", ctx)
+	VisitNode(n.Src(), "Src", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Alt(), "Alt", ctx)
+	VisitNode(n.Opts(), "Opts", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitCondExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_COND_BEFORE, node, key, ctx)
+func VisitImportCallBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_CALL_BEFORE, node, key, ctx)
 }
 
-func VisitCondExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_COND_AFTER, node, key, ctx)
+func VisitImportCallAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_CALL_AFTER, node, key, ctx)
 }
 
-func VisitAssignExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.AssignExpr)
+func VisitImportSpec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ImportSpec)
 
-	CallVisitor(N_EXPR_ASSIGN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_ASSIGN_AFTER, n, key, ctx)
+	CallVisitor(N_IMPORT_SPEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_IMPORT_SPEC_AFTER, n, key, ctx)
 
-	VisitNode(n.Lhs(), "Lhs", ctx)
+	VisitNode(n.Local(), "Local", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Rhs(), "Rhs", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitAssignExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_ASSIGN_BEFORE, node, key, ctx)
+func VisitImportSpecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_SPEC_BEFORE, node, key, ctx)
 }
 
-func VisitAssignExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_ASSIGN_AFTER, node, key, ctx)
+func VisitImportSpecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_IMPORT_SPEC_AFTER, node, key, ctx)
 }
 
-func VisitClassDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ClassDec)
+func VisitJsxAttr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxAttr)
 
-	CallVisitor(N_EXPR_CLASS_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_CLASS_AFTER, n, key, ctx)
+	CallVisitor(N_JSX_ATTR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_ATTR_AFTER, n, key, ctx)
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Super(), "Super", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNode(n.Body(), "Body", ctx)
+func VisitJsxAttrBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ATTR_BEFORE, node, key, ctx)
+}
+
+func VisitJsxAttrAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ATTR_AFTER, node, key, ctx)
+}
+
+func VisitJsxSpreadAttr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxSpreadAttr)
+
+	CallVisitor(N_JSX_ATTR_SPREAD_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_ATTR_SPREAD_AFTER, n, key, ctx)
+
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitClassDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CLASS_BEFORE, node, key, ctx)
+func VisitJsxSpreadAttrBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ATTR_SPREAD_BEFORE, node, key, ctx)
 }
 
-func VisitClassDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CLASS_AFTER, node, key, ctx)
+func VisitJsxSpreadAttrAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ATTR_SPREAD_AFTER, node, key, ctx)
 }
 
 func VisitJsxSpreadChild(node parser.Node, key string, ctx *VisitorCtx) {
@@ -1658,114 +1682,109 @@ func VisitJsxSpreadChildAfter(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_JSX_CHILD_SPREAD_AFTER, node, key, ctx)
 }
 
-func VisitTsLit(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsLit)
+func VisitJsxClose(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxClose)
 
-	CallVisitor(N_TS_LIT_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_LIT_AFTER, n, key, ctx)
+	CallVisitor(N_JSX_CLOSE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_CLOSE_AFTER, n, key, ctx)
 
-	VisitNode(n.Lit(), "Lit", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_LIT_BEFORE, node, key, ctx)
+func VisitJsxCloseBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_CLOSE_BEFORE, node, key, ctx)
 }
 
-func VisitTsLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_LIT_AFTER, node, key, ctx)
+func VisitJsxCloseAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_CLOSE_AFTER, node, key, ctx)
 }
 
-func VisitArrLit(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ArrLit)
+func VisitJsxElem(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxElem)
 
-	CallVisitor(N_LIT_ARR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_LIT_ARR_AFTER, n, key, ctx)
+	CallVisitor(N_JSX_ELEM_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_ELEM_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNode(n.Open(), "Open", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
-
-func VisitArrLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_ARR_BEFORE, node, key, ctx)
-}
-
-func VisitArrLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_ARR_AFTER, node, key, ctx)
-}
-
-func VisitUnaryExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.UnaryExpr)
 
-	CallVisitor(N_EXPR_UNARY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_UNARY_AFTER, n, key, ctx)
+	VisitNodes(n, n.Children(), "Children", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Close(), "Close", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitUnaryExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_UNARY_BEFORE, node, key, ctx)
+func VisitJsxElemBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ELEM_BEFORE, node, key, ctx)
 }
 
-func VisitUnaryExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_UNARY_AFTER, node, key, ctx)
+func VisitJsxElemAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ELEM_AFTER, node, key, ctx)
 }
 
-func VisitSwitchCase(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.SwitchCase)
+func VisitJsxEmpty(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_EMPTY_BEFORE, node, key, ctx)
+	CallListener(N_JSX_EMPTY_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_SWITCH_CASE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_SWITCH_CASE_AFTER, n, key, ctx)
+func VisitJsxExprSpan(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxExprSpan)
 
-	VisitNode(n.Test(), "Test", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_JSX_EXPR_SPAN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_EXPR_SPAN_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Cons(), "Cons", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitSwitchCaseBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_SWITCH_CASE_BEFORE, node, key, ctx)
+func VisitJsxExprSpanBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_EXPR_SPAN_BEFORE, node, key, ctx)
 }
 
-func VisitSwitchCaseAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_SWITCH_CASE_AFTER, node, key, ctx)
+func VisitJsxExprSpanAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_EXPR_SPAN_AFTER, node, key, ctx)
 }
 
-func VisitTsImportRequire(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsImportRequire)
+func VisitJsxIdent(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_ID_BEFORE, node, key, ctx)
+	CallListener(N_JSX_ID_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_TS_IMPORT_REQUIRE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_IMPORT_REQUIRE_AFTER, n, key, ctx)
+func VisitJsxMember(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxMember)
 
-	VisitNode(n.Name(), "Name", ctx)
+	CallVisitor(N_JSX_MEMBER_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_MEMBER_AFTER, n, key, ctx)
+
+	VisitNode(n.Obj(), "Obj", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Prop(), "Prop", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsImportRequireBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_REQUIRE_BEFORE, node, key, ctx)
+func VisitJsxMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_MEMBER_BEFORE, node, key, ctx)
 }
 
-func VisitTsImportRequireAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_REQUIRE_AFTER, node, key, ctx)
+func VisitJsxMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_MEMBER_AFTER, node, key, ctx)
 }
 
 func VisitJsxNsName(node parser.Node, key string, ctx *VisitorCtx) {
@@ -1773,159 +1792,174 @@ func VisitJsxNsName(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_JSX_NS_AFTER, node, key, ctx)
 }
 
-func VisitTsTupleNamedMember(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTupleNamedMember)
+func VisitJsxOpen(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.JsxOpen)
 
-	CallVisitor(N_TS_TUPLE_NAMED_MEMBER_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TUPLE_NAMED_MEMBER_AFTER, n, key, ctx)
+	CallVisitor(N_JSX_OPEN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_JSX_OPEN_AFTER, n, key, ctx)
 
-	VisitNode(n.Label(), "Label", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNodes(n, n.Attrs(), "Attrs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTupleNamedMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TUPLE_NAMED_MEMBER_BEFORE, node, key, ctx)
+func VisitJsxOpenBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_OPEN_BEFORE, node, key, ctx)
 }
 
-func VisitTsTupleNamedMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TUPLE_NAMED_MEMBER_AFTER, node, key, ctx)
+func VisitJsxOpenAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_OPEN_AFTER, node, key, ctx)
 }
 
-func VisitTsEnum(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsEnum)
+func VisitJsxText(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_JSX_TXT_BEFORE, node, key, ctx)
+	CallListener(N_JSX_TXT_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_TS_ENUM_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_ENUM_AFTER, n, key, ctx)
+func VisitArrLit(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ArrLit)
 
-	VisitNode(n.Id(), "Id", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_LIT_ARR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_LIT_ARR_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Members(), "Members", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsEnumBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ENUM_BEFORE, node, key, ctx)
+func VisitArrLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_ARR_BEFORE, node, key, ctx)
 }
 
-func VisitTsEnumAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ENUM_AFTER, node, key, ctx)
+func VisitArrLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_ARR_AFTER, node, key, ctx)
 }
 
-func VisitMemberExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.MemberExpr)
+func VisitBoolLit(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_BOOL_BEFORE, node, key, ctx)
+	CallListener(N_LIT_BOOL_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_EXPR_MEMBER_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_MEMBER_AFTER, n, key, ctx)
+func VisitNullLit(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_NULL_BEFORE, node, key, ctx)
+	CallListener(N_LIT_NULL_AFTER, node, key, ctx)
+}
 
-	VisitNode(n.Obj(), "Obj", ctx)
+func VisitNumLit(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_NUM_BEFORE, node, key, ctx)
+	CallListener(N_LIT_NUM_AFTER, node, key, ctx)
+}
+
+func VisitObjLit(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ObjLit)
+
+	CallVisitor(N_LIT_OBJ_BEFORE, n, key, ctx)
+	defer CallVisitor(N_LIT_OBJ_AFTER, n, key, ctx)
+
+	VisitNodes(n, n.Props(), "Props", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNode(n.Prop(), "Prop", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitObjLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_OBJ_BEFORE, node, key, ctx)
 }
 
-func VisitMemberExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_MEMBER_BEFORE, node, key, ctx)
+func VisitObjLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_OBJ_AFTER, node, key, ctx)
 }
 
-func VisitMemberExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_MEMBER_AFTER, node, key, ctx)
+func VisitRegLit(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_REGEXP_BEFORE, node, key, ctx)
+	CallListener(N_LIT_REGEXP_AFTER, node, key, ctx)
 }
 
-func VisitCallExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.CallExpr)
+func VisitStrLit(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_LIT_STR_BEFORE, node, key, ctx)
+	CallListener(N_LIT_STR_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_EXPR_CALL_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_CALL_AFTER, n, key, ctx)
+func VisitMetaProp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.MetaProp)
 
-	VisitNode(n.Callee(), "Callee", ctx)
+	CallVisitor(N_META_PROP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_META_PROP_AFTER, n, key, ctx)
+
+	VisitNode(n.Meta(), "Meta", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNodes(n, n.Args(), "Args", ctx)
+	VisitNode(n.Prop(), "Prop", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitCallExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CALL_BEFORE, node, key, ctx)
+func VisitMetaPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_META_PROP_BEFORE, node, key, ctx)
 }
 
-func VisitCallExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_CALL_AFTER, node, key, ctx)
+func VisitMetaPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_META_PROP_AFTER, node, key, ctx)
 }
 
-func VisitTsTypDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypDec)
+func VisitMethod(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Method)
 
-	CallVisitor(N_TS_TYP_DEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_DEC_AFTER, n, key, ctx)
+	CallVisitor(N_METHOD_BEFORE, n, key, ctx)
+	defer CallVisitor(N_METHOD_AFTER, n, key, ctx)
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.TypParams(), "TypParams", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTypDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_DEC_BEFORE, node, key, ctx)
+func VisitMethodBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_METHOD_BEFORE, node, key, ctx)
 }
 
-func VisitTsTypDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_DEC_AFTER, node, key, ctx)
+func VisitMethodAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_METHOD_AFTER, node, key, ctx)
 }
 
-func VisitTsTypPredicate(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypPredicate)
+func VisitIdent(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_NAME_BEFORE, node, key, ctx)
+	CallListener(N_NAME_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_TS_TYP_PREDICATE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_PREDICATE_AFTER, n, key, ctx)
+func VisitArrPat(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ArrPat)
 
-	VisitNode(n.Name(), "Name", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_PAT_ARRAY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_PAT_ARRAY_AFTER, n, key, ctx)
 
-	VisitNode(n.Typ(), "Typ", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTypPredicateBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_PREDICATE_BEFORE, node, key, ctx)
-}
-
-func VisitTsTypPredicateAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_PREDICATE_AFTER, node, key, ctx)
+func VisitArrPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_ARRAY_BEFORE, node, key, ctx)
 }
 
-func VisitNumLit(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_NUM_BEFORE, node, key, ctx)
-	CallListener(N_LIT_NUM_AFTER, node, key, ctx)
+func VisitArrPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_ARRAY_AFTER, node, key, ctx)
 }
 
 func VisitAssignPat(node parser.Node, key string, ctx *VisitorCtx) {
@@ -1953,86 +1987,96 @@ func VisitAssignPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_PAT_ASSIGN_AFTER, node, key, ctx)
 }
 
-func VisitStaticBlock(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.StaticBlock)
+func VisitObjPat(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ObjPat)
 
-	CallVisitor(N_STATIC_BLOCK_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STATIC_BLOCK_AFTER, n, key, ctx)
+	CallVisitor(N_PAT_OBJ_BEFORE, n, key, ctx)
+	defer CallVisitor(N_PAT_OBJ_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Body(), "Body", ctx)
+	VisitNodes(n, n.Props(), "Props", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitStaticBlockBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STATIC_BLOCK_BEFORE, node, key, ctx)
+func VisitObjPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_OBJ_BEFORE, node, key, ctx)
 }
 
-func VisitStaticBlockAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STATIC_BLOCK_AFTER, node, key, ctx)
+func VisitObjPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_OBJ_AFTER, node, key, ctx)
 }
 
-func VisitMethod(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Method)
+func VisitRestPat(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.RestPat)
 
-	CallVisitor(N_METHOD_BEFORE, n, key, ctx)
-	defer CallVisitor(N_METHOD_AFTER, n, key, ctx)
+	CallVisitor(N_PAT_REST_BEFORE, n, key, ctx)
+	defer CallVisitor(N_PAT_REST_AFTER, n, key, ctx)
 
-	VisitNode(n.Key(), "Key", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNode(n.Val(), "Val", ctx)
+func VisitRestPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_REST_BEFORE, node, key, ctx)
+}
+
+func VisitRestPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PAT_REST_AFTER, node, key, ctx)
+}
+
+func VisitProg(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Prog)
+
+	CallVisitor(N_PROG_BEFORE, n, key, ctx)
+	defer CallVisitor(N_PROG_AFTER, n, key, ctx)
+
+	VisitNodes(n, n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitMethodBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_METHOD_BEFORE, node, key, ctx)
+func VisitProgBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PROG_BEFORE, node, key, ctx)
 }
 
-func VisitMethodAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_METHOD_AFTER, node, key, ctx)
+func VisitProgAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PROG_AFTER, node, key, ctx)
 }
 
-func VisitTplExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TplExpr)
+func VisitProp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Prop)
 
-	CallVisitor(N_EXPR_TPL_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_TPL_AFTER, n, key, ctx)
+	CallVisitor(N_PROP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_PROP_AFTER, n, key, ctx)
 
-	VisitNode(n.Tag(), "Tag", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTplExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_TPL_BEFORE, node, key, ctx)
-}
-
-func VisitTplExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_TPL_AFTER, node, key, ctx)
+func VisitPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PROP_BEFORE, node, key, ctx)
 }
 
-func VisitJsxIdent(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ID_BEFORE, node, key, ctx)
-	CallListener(N_JSX_ID_AFTER, node, key, ctx)
+func VisitPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_PROP_AFTER, node, key, ctx)
 }
 
-func VisitTsRest(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsRest)
+func VisitSpread(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.Spread)
 
-	CallVisitor(N_TS_REST_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_REST_AFTER, n, key, ctx)
+	CallVisitor(N_SPREAD_BEFORE, n, key, ctx)
+	defer CallVisitor(N_SPREAD_AFTER, n, key, ctx)
 
 	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
@@ -2040,179 +2084,205 @@ func VisitTsRest(node parser.Node, key string, ctx *VisitorCtx) {
 	}
 }
 
-func VisitTsRestBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_REST_BEFORE, node, key, ctx)
+func VisitSpreadBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_SPREAD_BEFORE, node, key, ctx)
 }
 
-func VisitTsRestAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_REST_AFTER, node, key, ctx)
+func VisitSpreadAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_SPREAD_AFTER, node, key, ctx)
 }
 
-func VisitTsTypOp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypOp)
+func VisitStaticBlock(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.StaticBlock)
 
-	CallVisitor(N_TS_TYP_OP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_OP_AFTER, n, key, ctx)
+	CallVisitor(N_STATIC_BLOCK_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STATIC_BLOCK_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNodes(n, n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTypOpBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_OP_BEFORE, node, key, ctx)
+func VisitStaticBlockBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STATIC_BLOCK_BEFORE, node, key, ctx)
 }
 
-func VisitTsTypOpAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_OP_AFTER, node, key, ctx)
+func VisitStaticBlockAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STATIC_BLOCK_AFTER, node, key, ctx)
 }
 
-func VisitForInOfStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ForInOfStmt)
-
-	CallVisitor(N_STMT_FOR_IN_OF_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_FOR_IN_OF_AFTER, n, key, ctx)
+func VisitBlockStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.BlockStmt)
 
-	VisitNode(n.Left(), "Left", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	VisitNode(n.Right(), "Right", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_STMT_BLOCK_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_BLOCK_AFTER, n, key, ctx)
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNodes(n, n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitForInOfStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_FOR_IN_OF_BEFORE, node, key, ctx)
+func VisitBlockStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_BLOCK_BEFORE, node, key, ctx)
 }
 
-func VisitForInOfStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_FOR_IN_OF_AFTER, node, key, ctx)
+func VisitBlockStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_BLOCK_AFTER, node, key, ctx)
 }
 
-func VisitUpdateExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.UpdateExpr)
+func VisitBrkStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.BrkStmt)
 
-	CallVisitor(N_EXPR_UPDATE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_UPDATE_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_BRK_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_BRK_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Label(), "Label", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitUpdateExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_UPDATE_BEFORE, node, key, ctx)
+func VisitBrkStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_BRK_BEFORE, node, key, ctx)
 }
 
-func VisitUpdateExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_UPDATE_AFTER, node, key, ctx)
+func VisitBrkStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_BRK_AFTER, node, key, ctx)
 }
 
-func VisitTsTypAnnot(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypAnnot)
+func VisitContStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ContStmt)
 
-	CallVisitor(N_TS_TYP_ANNOT_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_ANNOT_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_CONT_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_CONT_AFTER, n, key, ctx)
 
-	VisitNode(n.TsTyp(), "TsTyp", ctx)
+	VisitNode(n.Label(), "Label", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTypAnnotBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_ANNOT_BEFORE, node, key, ctx)
+func VisitContStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_CONT_BEFORE, node, key, ctx)
 }
 
-func VisitTsTypAnnotAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_ANNOT_AFTER, node, key, ctx)
+func VisitContStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_CONT_AFTER, node, key, ctx)
 }
 
-func VisitJsxSpreadAttr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxSpreadAttr)
+func VisitDebugStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_DEBUG_BEFORE, node, key, ctx)
+	CallListener(N_STMT_DEBUG_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_JSX_ATTR_SPREAD_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_ATTR_SPREAD_AFTER, n, key, ctx)
+func VisitDoWhileStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.DoWhileStmt)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
+
+	CallVisitor(N_STMT_DO_WHILE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_DO_WHILE_AFTER, n, key, ctx)
+
+	VisitNode(n.Body(), "Body", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Test(), "Test", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxSpreadAttrBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ATTR_SPREAD_BEFORE, node, key, ctx)
+func VisitDoWhileStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_DO_WHILE_BEFORE, node, key, ctx)
 }
 
-func VisitJsxSpreadAttrAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ATTR_SPREAD_AFTER, node, key, ctx)
+func VisitDoWhileStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_DO_WHILE_AFTER, node, key, ctx)
 }
 
-func VisitImportCall(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ImportCall)
+func VisitExportDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ExportDec)
 
-	CallVisitor(N_IMPORT_CALL_BEFORE, n, key, ctx)
-	defer CallVisitor(N_IMPORT_CALL_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_EXPORT_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_EXPORT_AFTER, n, key, ctx)
+
+	VisitNode(n.Dec(), "Dec", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNodes(n, n.Specs(), "Specs", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
 	VisitNode(n.Src(), "Src", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
 
-func VisitImportCallBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_IMPORT_CALL_BEFORE, node, key, ctx)
+	VisitNodes(n, n.Attrs(), "Attrs", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 }
 
-func VisitImportCallAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_IMPORT_CALL_AFTER, node, key, ctx)
+func VisitExportDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_EXPORT_BEFORE, node, key, ctx)
 }
 
-func VisitProp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Prop)
+func VisitExportDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_EXPORT_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_PROP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_PROP_AFTER, n, key, ctx)
+func VisitExprStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ExprStmt)
 
-	VisitNode(n.Key(), "Key", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_STMT_EXPR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_EXPR_AFTER, n, key, ctx)
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PROP_BEFORE, node, key, ctx)
+func VisitExprStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_EXPR_BEFORE, node, key, ctx)
 }
 
-func VisitPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PROP_AFTER, node, key, ctx)
+func VisitExprStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_EXPR_AFTER, node, key, ctx)
 }
 
-func VisitCatch(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Catch)
+func VisitForStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ForStmt)
 
 	ctx.WalkCtx.PushScope()
 	defer ctx.WalkCtx.PopScope()
 
-	CallVisitor(N_CATCH_BEFORE, n, key, ctx)
-	defer CallVisitor(N_CATCH_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_FOR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_FOR_AFTER, n, key, ctx)
 
-	VisitNode(n.Param(), "Param", ctx)
+	VisitNode(n.Init(), "Init", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Test(), "Test", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Update(), "Update", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
@@ -2223,648 +2293,678 @@ func VisitCatch(node parser.Node, key string, ctx *VisitorCtx) {
 	}
 }
 
-func VisitCatchBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_CATCH_BEFORE, node, key, ctx)
+func VisitForStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_FOR_BEFORE, node, key, ctx)
 }
 
-func VisitCatchAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_CATCH_AFTER, node, key, ctx)
+func VisitForStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_FOR_AFTER, node, key, ctx)
 }
 
-func VisitDecorator(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Decorator)
+func VisitForInOfStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ForInOfStmt)
 
-	CallVisitor(N_DECORATOR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_DECORATOR_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_FOR_IN_OF_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_FOR_IN_OF_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Left(), "Left", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
-
-func VisitDecoratorBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_DECORATOR_BEFORE, node, key, ctx)
-}
 
-func VisitDecoratorAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_DECORATOR_AFTER, node, key, ctx)
-}
-
-func VisitTsTypInfer(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypInfer)
-
-	CallVisitor(N_TS_TYP_INFER_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_INFER_AFTER, n, key, ctx)
+	VisitNode(n.Right(), "Right", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsTypInferBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_INFER_BEFORE, node, key, ctx)
+func VisitForInOfStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_FOR_IN_OF_BEFORE, node, key, ctx)
 }
 
-func VisitTsTypInferAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_INFER_AFTER, node, key, ctx)
+func VisitForInOfStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_FOR_IN_OF_AFTER, node, key, ctx)
 }
 
-func VisitBinExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.BinExpr)
+func VisitIfStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.IfStmt)
 
-	CallVisitor(N_EXPR_BIN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_BIN_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_IF_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_IF_AFTER, n, key, ctx)
 
-	VisitNode(n.Lhs(), "Lhs", ctx)
+	VisitNode(n.Test(), "Test", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Rhs(), "Rhs", ctx)
+	VisitNode(n.Cons(), "Cons", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Alt(), "Alt", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitBinExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_BIN_BEFORE, node, key, ctx)
+func VisitIfStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_IF_BEFORE, node, key, ctx)
 }
 
-func VisitBinExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_BIN_AFTER, node, key, ctx)
+func VisitIfStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_IF_AFTER, node, key, ctx)
 }
 
-func VisitSeqExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.SeqExpr)
+func VisitImportDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ImportDec)
 
-	CallVisitor(N_EXPR_SEQ_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_SEQ_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_IMPORT_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_IMPORT_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNodes(n, n.Specs(), "Specs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
 
-func VisitSeqExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_SEQ_BEFORE, node, key, ctx)
-}
-
-func VisitSeqExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_SEQ_AFTER, node, key, ctx)
-}
-
-func VisitArrPat(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ArrPat)
-
-	CallVisitor(N_PAT_ARRAY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_PAT_ARRAY_AFTER, n, key, ctx)
+	VisitNode(n.Src(), "Src", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNodes(n, n.Attrs(), "Attrs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitArrPatBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_ARRAY_BEFORE, node, key, ctx)
+func VisitImportDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_IMPORT_BEFORE, node, key, ctx)
 }
 
-func VisitArrPatAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_PAT_ARRAY_AFTER, node, key, ctx)
+func VisitImportDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_IMPORT_AFTER, node, key, ctx)
 }
 
-func VisitTsNoNull(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsNoNull)
+func VisitLabelStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.LabelStmt)
 
-	CallVisitor(N_TS_NO_NULL_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_NO_NULL_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_LABEL_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_LABEL_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Label(), "Label", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsNoNullBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NO_NULL_BEFORE, node, key, ctx)
+func VisitLabelStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_LABEL_BEFORE, node, key, ctx)
 }
 
-func VisitTsNoNullAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NO_NULL_AFTER, node, key, ctx)
+func VisitLabelStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_LABEL_AFTER, node, key, ctx)
 }
 
-func VisitExprStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ExprStmt)
+func VisitRetStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.RetStmt)
 
-	CallVisitor(N_STMT_EXPR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_EXPR_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_RET_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_RET_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitExprStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_EXPR_BEFORE, node, key, ctx)
+func VisitRetStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_RET_BEFORE, node, key, ctx)
 }
 
-func VisitExprStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_EXPR_AFTER, node, key, ctx)
+func VisitRetStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_RET_AFTER, node, key, ctx)
 }
 
-func VisitWhileStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.WhileStmt)
+func VisitSwitchStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.SwitchStmt)
 
 	ctx.WalkCtx.PushScope()
 	defer ctx.WalkCtx.PopScope()
 
-	CallVisitor(N_STMT_WHILE_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_WHILE_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_SWITCH_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_SWITCH_AFTER, n, key, ctx)
 
 	VisitNode(n.Test(), "Test", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNodes(n, n.Cases(), "Cases", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitWhileStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_WHILE_BEFORE, node, key, ctx)
+func VisitSwitchStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_SWITCH_BEFORE, node, key, ctx)
 }
 
-func VisitWhileStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_WHILE_AFTER, node, key, ctx)
+func VisitSwitchStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_SWITCH_AFTER, node, key, ctx)
 }
 
-func VisitImportSpec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ImportSpec)
-
-	CallVisitor(N_IMPORT_SPEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_IMPORT_SPEC_AFTER, n, key, ctx)
+func VisitThrowStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.ThrowStmt)
 
-	VisitNode(n.Local(), "Local", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_STMT_THROW_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_THROW_AFTER, n, key, ctx)
 
-	VisitNode(n.Id(), "Id", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitImportSpecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_IMPORT_SPEC_BEFORE, node, key, ctx)
+func VisitThrowStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_THROW_BEFORE, node, key, ctx)
 }
 
-func VisitImportSpecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_IMPORT_SPEC_AFTER, node, key, ctx)
+func VisitThrowStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_THROW_AFTER, node, key, ctx)
 }
 
-func VisitJsxAttr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxAttr)
+func VisitTryStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TryStmt)
 
-	CallVisitor(N_JSX_ATTR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_ATTR_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_TRY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_TRY_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Try(), "Try", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Catch(), "Catch", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Fin(), "Fin", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
+
+func VisitTryStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_TRY_BEFORE, node, key, ctx)
+}
+
+func VisitTryStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_TRY_AFTER, node, key, ctx)
+}
+
+func VisitVarDecStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.VarDecStmt)
+
+	CallVisitor(N_STMT_VAR_DEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_VAR_DEC_AFTER, n, key, ctx)
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNodes(n, n.DecList(), "DecList", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxAttrBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ATTR_BEFORE, node, key, ctx)
+func VisitVarDecStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_VAR_DEC_BEFORE, node, key, ctx)
 }
 
-func VisitJsxAttrAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ATTR_AFTER, node, key, ctx)
+func VisitVarDecStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_VAR_DEC_AFTER, node, key, ctx)
 }
 
-func VisitTsNewSig(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsNewSig)
+func VisitWhileStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.WhileStmt)
 
-	CallVisitor(N_TS_NEW_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_NEW_AFTER, n, key, ctx)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	VisitNode(n.TypParams(), "TypParams", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_STMT_WHILE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_WHILE_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
+	VisitNode(n.Test(), "Test", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.RetTyp(), "RetTyp", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsNewSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NEW_BEFORE, node, key, ctx)
+func VisitWhileStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_WHILE_BEFORE, node, key, ctx)
 }
 
-func VisitTsNewSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_NEW_AFTER, node, key, ctx)
+func VisitWhileStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_WHILE_AFTER, node, key, ctx)
 }
 
-func VisitForStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ForStmt)
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitWithStmt(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.WithStmt)
 
-	CallVisitor(N_STMT_FOR_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_FOR_AFTER, n, key, ctx)
+	CallVisitor(N_STMT_WITH_BEFORE, n, key, ctx)
+	defer CallVisitor(N_STMT_WITH_AFTER, n, key, ctx)
 
-	VisitNode(n.Init(), "Init", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Test(), "Test", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNode(n.Update(), "Update", ctx)
+func VisitWithStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_WITH_BEFORE, node, key, ctx)
+}
+
+func VisitWithStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_STMT_WITH_AFTER, node, key, ctx)
+}
+
+func VisitSuper(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_SUPER_BEFORE, node, key, ctx)
+	CallListener(N_SUPER_AFTER, node, key, ctx)
+}
+
+func VisitSwitchCase(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.SwitchCase)
+
+	CallVisitor(N_SWITCH_CASE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_SWITCH_CASE_AFTER, n, key, ctx)
+
+	VisitNode(n.Test(), "Test", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNodes(n, n.Cons(), "Cons", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitForStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_FOR_BEFORE, node, key, ctx)
+func VisitSwitchCaseBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_SWITCH_CASE_BEFORE, node, key, ctx)
 }
 
-func VisitForStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_FOR_AFTER, node, key, ctx)
+func VisitSwitchCaseAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_SWITCH_CASE_AFTER, node, key, ctx)
 }
 
-func VisitNewExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.NewExpr)
+func VisitTsPredef(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NULL_BEFORE, node, key, ctx)
+	CallListener(N_TS_NULL_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_EXPR_NEW_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_NEW_AFTER, n, key, ctx)
+func VisitTsArr(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsArr)
 
-	VisitNode(n.Callee(), "Callee", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_ARR_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_ARR_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Args(), "Args", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitNewExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_NEW_BEFORE, node, key, ctx)
+func VisitTsArrBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ARR_BEFORE, node, key, ctx)
 }
 
-func VisitNewExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_NEW_AFTER, node, key, ctx)
+func VisitTsArrAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ARR_AFTER, node, key, ctx)
 }
 
-func VisitJsxElem(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxElem)
+func VisitTsCallSig(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsCallSig)
 
-	CallVisitor(N_JSX_ELEM_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_ELEM_AFTER, n, key, ctx)
+	CallVisitor(N_TS_CALL_SIG_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_CALL_SIG_AFTER, n, key, ctx)
 
-	VisitNode(n.Open(), "Open", ctx)
+	VisitNode(n.TypParams(), "TypParams", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNodes(n, n.Children(), "Children", ctx)
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Close(), "Close", ctx)
+	VisitNode(n.RetTyp(), "RetTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxElemBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ELEM_BEFORE, node, key, ctx)
+func VisitTsCallSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_CALL_SIG_BEFORE, node, key, ctx)
 }
 
-func VisitJsxElemAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_ELEM_AFTER, node, key, ctx)
+func VisitTsCallSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_CALL_SIG_AFTER, node, key, ctx)
 }
 
-func VisitTsMapped(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsMapped)
+func VisitTsCondType(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsCondType)
 
-	CallVisitor(N_TS_MAPPED_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_MAPPED_AFTER, n, key, ctx)
+	CallVisitor(N_TS_COND_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_COND_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.CheckTyp(), "CheckTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Key(), "Key", ctx)
+	VisitNode(n.ExtTyp(), "ExtTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNode(n.TrueTyp(), "TrueTyp", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.FalseTyp(), "FalseTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsMappedBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_MAPPED_BEFORE, node, key, ctx)
+func VisitTsCondTypeBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_COND_BEFORE, node, key, ctx)
 }
 
-func VisitTsMappedAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_MAPPED_AFTER, node, key, ctx)
+func VisitTsCondTypeAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_COND_AFTER, node, key, ctx)
 }
 
-func VisitTsParamsInst(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsParamsInst)
+func VisitTsDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsDec)
 
-	CallVisitor(N_TS_PARAM_INST_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_PARAM_INST_AFTER, n, key, ctx)
+	CallVisitor(N_TS_DEC_TYP_DEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_DEC_TYP_DEC_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
 
-func VisitTsParamsInstBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_INST_BEFORE, node, key, ctx)
+	VisitNode(n.Inner(), "Inner", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 }
 
-func VisitTsParamsInstAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_INST_AFTER, node, key, ctx)
+func VisitTsDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_DEC_TYP_DEC_BEFORE, node, key, ctx)
 }
 
-func VisitBoolLit(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_BOOL_BEFORE, node, key, ctx)
-	CallListener(N_LIT_BOOL_AFTER, node, key, ctx)
+func VisitTsDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_DEC_TYP_DEC_AFTER, node, key, ctx)
 }
 
-func VisitField(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Field)
+func VisitTsEnum(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsEnum)
 
-	CallVisitor(N_FIELD_BEFORE, n, key, ctx)
-	defer CallVisitor(N_FIELD_AFTER, n, key, ctx)
+	CallVisitor(N_TS_ENUM_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_ENUM_AFTER, n, key, ctx)
 
-	VisitNode(n.Key(), "Key", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNodes(n, n.Members(), "Members", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitFieldBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_FIELD_BEFORE, node, key, ctx)
+func VisitTsEnumBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ENUM_BEFORE, node, key, ctx)
 }
 
-func VisitFieldAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_FIELD_AFTER, node, key, ctx)
+func VisitTsEnumAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ENUM_AFTER, node, key, ctx)
 }
 
-func VisitTsIdxAccess(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsIdxAccess)
+func VisitTsEnumMember(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsEnumMember)
 
-	CallVisitor(N_TS_IDX_ACCESS_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_IDX_ACCESS_AFTER, n, key, ctx)
+	CallVisitor(N_TS_ENUM_MEMBER_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_ENUM_MEMBER_AFTER, n, key, ctx)
 
-	VisitNode(n.Obj(), "Obj", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Idx(), "Idx", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsIdxAccessBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IDX_ACCESS_BEFORE, node, key, ctx)
+func VisitTsEnumMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ENUM_MEMBER_BEFORE, node, key, ctx)
 }
 
-func VisitTsIdxAccessAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IDX_ACCESS_AFTER, node, key, ctx)
+func VisitTsEnumMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ENUM_MEMBER_AFTER, node, key, ctx)
 }
 
-func VisitContStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ContStmt)
+func VisitTsExportAssign(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsExportAssign)
 
-	CallVisitor(N_STMT_CONT_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_CONT_AFTER, n, key, ctx)
+	CallVisitor(N_TS_EXPORT_ASSIGN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_EXPORT_ASSIGN_AFTER, n, key, ctx)
 
-	VisitNode(n.Label(), "Label", ctx)
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitContStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_CONT_BEFORE, node, key, ctx)
+func VisitTsExportAssignBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_EXPORT_ASSIGN_BEFORE, node, key, ctx)
 }
 
-func VisitContStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_CONT_AFTER, node, key, ctx)
+func VisitTsExportAssignAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_EXPORT_ASSIGN_AFTER, node, key, ctx)
 }
 
-func VisitMetaProp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.MetaProp)
+func VisitTsFnTyp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsFnTyp)
 
-	CallVisitor(N_META_PROP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_META_PROP_AFTER, n, key, ctx)
+	CallVisitor(N_TS_FN_TYP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_FN_TYP_AFTER, n, key, ctx)
 
-	VisitNode(n.Meta(), "Meta", ctx)
+	VisitNode(n.TypParams(), "TypParams", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Prop(), "Prop", ctx)
+	VisitNode(n.RetTyp(), "RetTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitMetaPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_META_PROP_BEFORE, node, key, ctx)
+func VisitTsFnTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_FN_TYP_BEFORE, node, key, ctx)
 }
 
-func VisitMetaPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_META_PROP_AFTER, node, key, ctx)
+func VisitTsFnTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_FN_TYP_AFTER, node, key, ctx)
 }
 
-func VisitTsTypQuery(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsTypQuery)
+func VisitTsIdxAccess(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsIdxAccess)
 
-	CallVisitor(N_TS_TYP_QUERY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_TYP_QUERY_AFTER, n, key, ctx)
+	CallVisitor(N_TS_IDX_ACCESS_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_IDX_ACCESS_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Obj(), "Obj", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
-
-func VisitTsTypQueryBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_QUERY_BEFORE, node, key, ctx)
-}
-
-func VisitTsTypQueryAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_TYP_QUERY_AFTER, node, key, ctx)
-}
-
-func VisitTsParamsDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsParamsDec)
-
-	CallVisitor(N_TS_PARAM_DEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_PARAM_DEC_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
+	VisitNode(n.Idx(), "Idx", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsParamsDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_DEC_BEFORE, node, key, ctx)
+func VisitTsIdxAccessBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IDX_ACCESS_BEFORE, node, key, ctx)
 }
 
-func VisitTsParamsDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_DEC_AFTER, node, key, ctx)
+func VisitTsIdxAccessAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IDX_ACCESS_AFTER, node, key, ctx)
 }
 
-func VisitTsImportType(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsImportType)
+func VisitTsIdxSig(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsIdxSig)
 
-	CallVisitor(N_TS_IMPORT_TYP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_IMPORT_TYP_AFTER, n, key, ctx)
+	CallVisitor(N_TS_IDX_SIG_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_IDX_SIG_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Key(), "Key", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Qualifier(), "Qualifier", ctx)
+	VisitNode(n.KeyType(), "KeyType", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.TypArg(), "TypArg", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsImportTypeBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_TYP_BEFORE, node, key, ctx)
+func VisitTsIdxSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IDX_SIG_BEFORE, node, key, ctx)
 }
 
-func VisitTsImportTypeAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_IMPORT_TYP_AFTER, node, key, ctx)
+func VisitTsIdxSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IDX_SIG_AFTER, node, key, ctx)
 }
 
-func VisitLabelStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.LabelStmt)
+func VisitTsImportAlias(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsImportAlias)
 
-	CallVisitor(N_STMT_LABEL_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_LABEL_AFTER, n, key, ctx)
+	CallVisitor(N_TS_IMPORT_ALIAS_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_IMPORT_ALIAS_AFTER, n, key, ctx)
 
-	VisitNode(n.Label(), "Label", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitLabelStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_LABEL_BEFORE, node, key, ctx)
+func VisitTsImportAliasBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_ALIAS_BEFORE, node, key, ctx)
 }
 
-func VisitLabelStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_LABEL_AFTER, node, key, ctx)
+func VisitTsImportAliasAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_ALIAS_AFTER, node, key, ctx)
 }
 
-func VisitYieldExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.YieldExpr)
+func VisitTsImportRequire(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsImportRequire)
 
-	CallVisitor(N_EXPR_YIELD_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_YIELD_AFTER, n, key, ctx)
+	CallVisitor(N_TS_IMPORT_REQUIRE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_IMPORT_REQUIRE_AFTER, n, key, ctx)
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.Name(), "Name", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Expr(), "Expr", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitYieldExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_YIELD_BEFORE, node, key, ctx)
+func VisitTsImportRequireBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_REQUIRE_BEFORE, node, key, ctx)
 }
 
-func VisitYieldExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_YIELD_AFTER, node, key, ctx)
+func VisitTsImportRequireAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_REQUIRE_AFTER, node, key, ctx)
 }
 
-func VisitTsRef(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsRef)
+func VisitTsImportType(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsImportType)
 
-	CallVisitor(N_TS_REF_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_REF_AFTER, n, key, ctx)
+	CallVisitor(N_TS_IMPORT_TYP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_IMPORT_TYP_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNodes(n, n.Args(), "Args", ctx)
+	VisitNode(n.Qualifier(), "Qualifier", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.TypArg(), "TypArg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsRefBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_REF_BEFORE, node, key, ctx)
+func VisitTsImportTypeBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_TYP_BEFORE, node, key, ctx)
 }
 
-func VisitTsRefAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_REF_AFTER, node, key, ctx)
+func VisitTsImportTypeAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_IMPORT_TYP_AFTER, node, key, ctx)
 }
 
 func VisitTsInterface(node parser.Node, key string, ctx *VisitorCtx) {
@@ -2902,174 +3002,179 @@ func VisitTsInterfaceAfter(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_TS_INTERFACE_AFTER, node, key, ctx)
 }
 
-func VisitThrowStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ThrowStmt)
+func VisitTsInterfaceBody(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsInterfaceBody)
 
-	CallVisitor(N_STMT_THROW_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_THROW_AFTER, n, key, ctx)
+	ctx.WalkCtx.PushScope()
+	defer ctx.WalkCtx.PopScope()
 
-	VisitNode(n.Arg(), "Arg", ctx)
+	CallVisitor(N_TS_INTERFACE_BODY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_INTERFACE_BODY_AFTER, n, key, ctx)
+
+	VisitNodes(n, n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitThrowStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_THROW_BEFORE, node, key, ctx)
-}
-
-func VisitThrowStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_THROW_AFTER, node, key, ctx)
+func VisitTsInterfaceBodyBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_INTERFACE_BODY_BEFORE, node, key, ctx)
 }
 
-func VisitNullLit(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_NULL_BEFORE, node, key, ctx)
-	CallListener(N_LIT_NULL_AFTER, node, key, ctx)
+func VisitTsInterfaceBodyAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_INTERFACE_BODY_AFTER, node, key, ctx)
 }
 
-func VisitObjLit(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ObjLit)
+func VisitTsIntersectTyp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsIntersectTyp)
 
-	CallVisitor(N_LIT_OBJ_BEFORE, n, key, ctx)
-	defer CallVisitor(N_LIT_OBJ_AFTER, n, key, ctx)
+	CallVisitor(N_TS_INTERSECT_TYP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_INTERSECT_TYP_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Props(), "Props", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitObjLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_OBJ_BEFORE, node, key, ctx)
+func VisitTsIntersectTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_INTERSECT_TYP_BEFORE, node, key, ctx)
 }
 
-func VisitObjLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_OBJ_AFTER, node, key, ctx)
+func VisitTsIntersectTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_INTERSECT_TYP_AFTER, node, key, ctx)
 }
 
-func VisitSwitchStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.SwitchStmt)
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitTsLit(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsLit)
 
-	CallVisitor(N_STMT_SWITCH_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_SWITCH_AFTER, n, key, ctx)
+	CallVisitor(N_TS_LIT_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_LIT_AFTER, n, key, ctx)
 
-	VisitNode(n.Test(), "Test", ctx)
+	VisitNode(n.Lit(), "Lit", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNodes(n, n.Cases(), "Cases", ctx)
+func VisitTsLitBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_LIT_BEFORE, node, key, ctx)
+}
+
+func VisitTsLitAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_LIT_AFTER, node, key, ctx)
+}
+
+func VisitTsObj(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsObj)
+
+	CallVisitor(N_TS_LIT_OBJ_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_LIT_OBJ_AFTER, n, key, ctx)
+
+	VisitNodes(n, n.Props(), "Props", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitSwitchStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_SWITCH_BEFORE, node, key, ctx)
+func VisitTsObjBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_LIT_OBJ_BEFORE, node, key, ctx)
 }
 
-func VisitSwitchStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_SWITCH_AFTER, node, key, ctx)
+func VisitTsObjAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_LIT_OBJ_AFTER, node, key, ctx)
 }
 
-func VisitTsThis(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_THIS_BEFORE, node, key, ctx)
-	CallListener(N_TS_THIS_AFTER, node, key, ctx)
-}
+func VisitTsMapped(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsMapped)
 
-func VisitTsRoughParam(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ROUGH_PARAM_BEFORE, node, key, ctx)
-	CallListener(N_TS_ROUGH_PARAM_AFTER, node, key, ctx)
-}
+	CallVisitor(N_TS_MAPPED_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_MAPPED_AFTER, n, key, ctx)
 
-func VisitTsCallSig(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsCallSig)
+	VisitNode(n.Name(), "Name", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	CallVisitor(N_TS_CALL_SIG_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_CALL_SIG_AFTER, n, key, ctx)
+	VisitNode(n.Key(), "Key", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.TypParams(), "TypParams", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
+
+func VisitTsMappedBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_MAPPED_BEFORE, node, key, ctx)
+}
+
+func VisitTsMappedAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_MAPPED_AFTER, node, key, ctx)
+}
+
+func VisitTsNS(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsNS)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
+	CallVisitor(N_TS_NAMESPACE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_NAMESPACE_AFTER, n, key, ctx)
+
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.RetTyp(), "RetTyp", ctx)
+	VisitNode(n.Body(), "Body", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsCallSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_CALL_SIG_BEFORE, node, key, ctx)
+func VisitTsNSBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NAMESPACE_BEFORE, node, key, ctx)
 }
 
-func VisitTsCallSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_CALL_SIG_AFTER, node, key, ctx)
+func VisitTsNSAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NAMESPACE_AFTER, node, key, ctx)
 }
 
-func VisitTsEnumMember(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsEnumMember)
+func VisitTsNewSig(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsNewSig)
 
-	CallVisitor(N_TS_ENUM_MEMBER_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_ENUM_MEMBER_AFTER, n, key, ctx)
+	CallVisitor(N_TS_NEW_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_NEW_AFTER, n, key, ctx)
 
-	VisitNode(n.Key(), "Key", ctx)
+	VisitNode(n.TypParams(), "TypParams", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
-
-func VisitTsEnumMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ENUM_MEMBER_BEFORE, node, key, ctx)
-}
 
-func VisitTsEnumMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_ENUM_MEMBER_AFTER, node, key, ctx)
-}
-
-func VisitTsParen(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsParen)
-
-	CallVisitor(N_TS_PAREN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_PAREN_AFTER, n, key, ctx)
-
-	VisitNode(n.Arg(), "Arg", ctx)
+	VisitNode(n.RetTyp(), "RetTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsParenBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PAREN_BEFORE, node, key, ctx)
-}
-
-func VisitTsParenAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PAREN_AFTER, node, key, ctx)
+func VisitTsNewSigBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NEW_BEFORE, node, key, ctx)
 }
 
-func VisitStrLit(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_STR_BEFORE, node, key, ctx)
-	CallListener(N_LIT_STR_AFTER, node, key, ctx)
+func VisitTsNewSigAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NEW_AFTER, node, key, ctx)
 }
 
-func VisitSpread(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.Spread)
+func VisitTsNoNull(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsNoNull)
 
-	CallVisitor(N_SPREAD_BEFORE, n, key, ctx)
-	defer CallVisitor(N_SPREAD_AFTER, n, key, ctx)
+	CallVisitor(N_TS_NO_NULL_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_NO_NULL_AFTER, n, key, ctx)
 
 	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
@@ -3077,32 +3182,37 @@ func VisitSpread(node parser.Node, key string, ctx *VisitorCtx) {
 	}
 }
 
-func VisitSpreadBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_SPREAD_BEFORE, node, key, ctx)
+func VisitTsNoNullBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NO_NULL_BEFORE, node, key, ctx)
 }
 
-func VisitSpreadAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_SPREAD_AFTER, node, key, ctx)
+func VisitTsNoNullAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NO_NULL_AFTER, node, key, ctx)
 }
 
-func VisitJsxExprSpan(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.JsxExprSpan)
+func VisitTsNsName(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsNsName)
 
-	CallVisitor(N_JSX_EXPR_SPAN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_JSX_EXPR_SPAN_AFTER, n, key, ctx)
+	CallVisitor(N_TS_NS_NAME_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_NS_NAME_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Lhs(), "Lhs", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
+
+	VisitNode(n.Rhs(), "Rhs", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitJsxExprSpanBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_EXPR_SPAN_BEFORE, node, key, ctx)
+func VisitTsNsNameBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NS_NAME_BEFORE, node, key, ctx)
 }
 
-func VisitJsxExprSpanAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_EXPR_SPAN_AFTER, node, key, ctx)
+func VisitTsNsNameAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_NS_NAME_AFTER, node, key, ctx)
 }
 
 func VisitTsOpt(node parser.Node, key string, ctx *VisitorCtx) {
@@ -3125,235 +3235,239 @@ func VisitTsOptAfter(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_TS_OPT_AFTER, node, key, ctx)
 }
 
-func VisitTsCondType(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsCondType)
-
-	CallVisitor(N_TS_COND_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_COND_AFTER, n, key, ctx)
+func VisitTsParam(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsParam)
 
-	VisitNode(n.CheckTyp(), "CheckTyp", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_PARAM_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_PARAM_AFTER, n, key, ctx)
 
-	VisitNode(n.ExtTyp(), "ExtTyp", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.TrueTyp(), "TrueTyp", ctx)
+	VisitNode(n.Cons(), "Cons", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.FalseTyp(), "FalseTyp", ctx)
+	VisitNode(n.Default(), "Default", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsCondTypeBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_COND_BEFORE, node, key, ctx)
+func VisitTsParamBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_BEFORE, node, key, ctx)
 }
 
-func VisitTsCondTypeAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_COND_AFTER, node, key, ctx)
+func VisitTsParamAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_AFTER, node, key, ctx)
 }
 
-func VisitTsParam(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsParam)
+func VisitTsParamsDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsParamsDec)
 
-	CallVisitor(N_TS_PARAM_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_PARAM_AFTER, n, key, ctx)
+	CallVisitor(N_TS_PARAM_DEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_PARAM_DEC_AFTER, n, key, ctx)
 
-	VisitNode(n.Name(), "Name", ctx)
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
+}
 
-	VisitNode(n.Cons(), "Cons", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitTsParamsDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_DEC_BEFORE, node, key, ctx)
+}
 
-	VisitNode(n.Default(), "Default", ctx)
+func VisitTsParamsDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_DEC_AFTER, node, key, ctx)
+}
+
+func VisitTsParamsInst(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsParamsInst)
+
+	CallVisitor(N_TS_PARAM_INST_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_PARAM_INST_AFTER, n, key, ctx)
+
+	VisitNodes(n, n.Params(), "Params", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsParamBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_BEFORE, node, key, ctx)
+func VisitTsParamsInstBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_INST_BEFORE, node, key, ctx)
 }
 
-func VisitTsParamAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PARAM_AFTER, node, key, ctx)
+func VisitTsParamsInstAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PARAM_INST_AFTER, node, key, ctx)
 }
 
-func VisitTsUnionTyp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsUnionTyp)
+func VisitTsParen(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsParen)
 
-	CallVisitor(N_TS_UNION_TYP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_UNION_TYP_AFTER, n, key, ctx)
+	CallVisitor(N_TS_PAREN_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_PAREN_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsUnionTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_UNION_TYP_BEFORE, node, key, ctx)
+func VisitTsParenBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PAREN_BEFORE, node, key, ctx)
 }
 
-func VisitTsUnionTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_UNION_TYP_AFTER, node, key, ctx)
+func VisitTsParenAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PAREN_AFTER, node, key, ctx)
 }
 
-func VisitThisExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_THIS_BEFORE, node, key, ctx)
-	CallListener(N_EXPR_THIS_AFTER, node, key, ctx)
-}
+func VisitTsProp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsProp)
 
-func VisitParenExpr(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ParenExpr)
+	CallVisitor(N_TS_PROP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_PROP_AFTER, n, key, ctx)
 
-	CallVisitor(N_EXPR_PAREN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_PAREN_AFTER, n, key, ctx)
+	VisitNode(n.Key(), "Key", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitParenExprBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_PAREN_BEFORE, node, key, ctx)
+func VisitTsPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PROP_BEFORE, node, key, ctx)
 }
 
-func VisitParenExprAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_PAREN_AFTER, node, key, ctx)
+func VisitTsPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_PROP_AFTER, node, key, ctx)
 }
 
-func VisitArrowFn(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ArrowFn)
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitTsRef(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsRef)
 
-	CallVisitor(N_EXPR_ARROW_BEFORE, n, key, ctx)
-	defer CallVisitor(N_EXPR_ARROW_AFTER, n, key, ctx)
+	CallVisitor(N_TS_REF_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_REF_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Params(), "Params", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNodes(n, n.Args(), "Args", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitArrowFnBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_ARROW_BEFORE, node, key, ctx)
+func VisitTsRefBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_REF_BEFORE, node, key, ctx)
 }
 
-func VisitArrowFnAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_EXPR_ARROW_AFTER, node, key, ctx)
+func VisitTsRefAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_REF_AFTER, node, key, ctx)
 }
 
-func VisitTsExportAssign(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsExportAssign)
+func VisitTsRest(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsRest)
 
-	CallVisitor(N_TS_EXPORT_ASSIGN_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_EXPORT_ASSIGN_AFTER, n, key, ctx)
+	CallVisitor(N_TS_REST_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_REST_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsExportAssignBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_EXPORT_ASSIGN_BEFORE, node, key, ctx)
+func VisitTsRestBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_REST_BEFORE, node, key, ctx)
 }
 
-func VisitTsExportAssignAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_EXPORT_ASSIGN_AFTER, node, key, ctx)
+func VisitTsRestAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_REST_AFTER, node, key, ctx)
 }
 
-func VisitImportDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ImportDec)
+func VisitTsRoughParam(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_ROUGH_PARAM_BEFORE, node, key, ctx)
+	CallListener(N_TS_ROUGH_PARAM_AFTER, node, key, ctx)
+}
+
+func VisitTsThis(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_THIS_BEFORE, node, key, ctx)
+	CallListener(N_TS_THIS_AFTER, node, key, ctx)
+}
 
-	CallVisitor(N_STMT_IMPORT_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_IMPORT_AFTER, n, key, ctx)
+func VisitTsTuple(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTuple)
 
-	VisitNodes(n, n.Specs(), "Specs", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_TUPLE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TUPLE_AFTER, n, key, ctx)
 
-	VisitNode(n.Src(), "Src", ctx)
+	VisitNodes(n, n.Args(), "Args", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitImportDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_IMPORT_BEFORE, node, key, ctx)
+func VisitTsTupleBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TUPLE_BEFORE, node, key, ctx)
 }
 
-func VisitImportDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_IMPORT_AFTER, node, key, ctx)
+func VisitTsTupleAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TUPLE_AFTER, node, key, ctx)
 }
 
-func VisitJsxText(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_JSX_TXT_BEFORE, node, key, ctx)
-	CallListener(N_JSX_TXT_AFTER, node, key, ctx)
-}
+func VisitTsTupleNamedMember(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTupleNamedMember)
 
-func VisitTsIntersectTyp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsIntersectTyp)
+	CallVisitor(N_TS_TUPLE_NAMED_MEMBER_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TUPLE_NAMED_MEMBER_AFTER, n, key, ctx)
 
-	CallVisitor(N_TS_INTERSECT_TYP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_INTERSECT_TYP_AFTER, n, key, ctx)
+	VisitNode(n.Label(), "Label", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNode(n.Val(), "Val", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsIntersectTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_INTERSECT_TYP_BEFORE, node, key, ctx)
+func VisitTsTupleNamedMemberBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TUPLE_NAMED_MEMBER_BEFORE, node, key, ctx)
 }
 
-func VisitTsIntersectTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_INTERSECT_TYP_AFTER, node, key, ctx)
+func VisitTsTupleNamedMemberAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TUPLE_NAMED_MEMBER_AFTER, node, key, ctx)
 }
 
-func VisitClassBody(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ClassBody)
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitTsTypAnnot(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypAnnot)
 
-	CallVisitor(N_CLASS_BODY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_CLASS_BODY_AFTER, n, key, ctx)
+	CallVisitor(N_TS_TYP_ANNOT_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_ANNOT_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Elems(), "Elems", ctx)
+	VisitNode(n.TsTyp(), "TsTyp", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitClassBodyBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_CLASS_BODY_BEFORE, node, key, ctx)
+func VisitTsTypAnnotBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_ANNOT_BEFORE, node, key, ctx)
 }
 
-func VisitClassBodyAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_CLASS_BODY_AFTER, node, key, ctx)
+func VisitTsTypAnnotAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_ANNOT_AFTER, node, key, ctx)
 }
 
 func VisitTsTypAssert(node parser.Node, key string, ctx *VisitorCtx) {
@@ -3381,212 +3495,164 @@ func VisitTsTypAssertAfter(node parser.Node, key string, ctx *VisitorCtx) {
 	CallListener(N_TS_TYP_ASSERT_AFTER, node, key, ctx)
 }
 
-func VisitBlockStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.BlockStmt)
-
-	ctx.WalkCtx.PushScope()
-	defer ctx.WalkCtx.PopScope()
+func VisitTsTypDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypDec)
 
-	CallVisitor(N_STMT_BLOCK_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_BLOCK_AFTER, n, key, ctx)
+	CallVisitor(N_TS_TYP_DEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_DEC_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.Body(), "Body", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
-}
-
-func VisitBlockStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_BLOCK_BEFORE, node, key, ctx)
-}
-
-func VisitBlockStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_BLOCK_AFTER, node, key, ctx)
-}
-
-func VisitBrkStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.BrkStmt)
 
-	CallVisitor(N_STMT_BRK_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_BRK_AFTER, n, key, ctx)
+	VisitNode(n.TypParams(), "TypParams", ctx)
+	if ctx.WalkCtx.Stopped() {
+		return
+	}
 
-	VisitNode(n.Label(), "Label", ctx)
+	VisitNode(n.Super(), "Super", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitBrkStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_BRK_BEFORE, node, key, ctx)
+func VisitTsTypDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_DEC_BEFORE, node, key, ctx)
 }
 
-func VisitBrkStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_BRK_AFTER, node, key, ctx)
+func VisitTsTypDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_DEC_AFTER, node, key, ctx)
 }
 
-func VisitExportDec(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.ExportDec)
-
-	CallVisitor(N_STMT_EXPORT_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_EXPORT_AFTER, n, key, ctx)
-
-	VisitNode(n.Dec(), "Dec", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitTsTypInfer(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypInfer)
 
-	VisitNodes(n, n.Specs(), "Specs", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_TYP_INFER_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_INFER_AFTER, n, key, ctx)
 
-	VisitNode(n.Src(), "Src", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitExportDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_EXPORT_BEFORE, node, key, ctx)
-}
-
-func VisitExportDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_EXPORT_AFTER, node, key, ctx)
+func VisitTsTypInferBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_INFER_BEFORE, node, key, ctx)
 }
 
-func VisitRegLit(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_LIT_REGEXP_BEFORE, node, key, ctx)
-	CallListener(N_LIT_REGEXP_AFTER, node, key, ctx)
+func VisitTsTypInferAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_INFER_AFTER, node, key, ctx)
 }
 
-func VisitVarDecStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.VarDecStmt)
+func VisitTsTypOp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypOp)
 
-	CallVisitor(N_STMT_VAR_DEC_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_VAR_DEC_AFTER, n, key, ctx)
+	CallVisitor(N_TS_TYP_OP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_OP_AFTER, n, key, ctx)
 
-	VisitNodes(n, n.DecList(), "DecList", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitVarDecStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_VAR_DEC_BEFORE, node, key, ctx)
+func VisitTsTypOpBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_OP_BEFORE, node, key, ctx)
 }
 
-func VisitVarDecStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_VAR_DEC_AFTER, node, key, ctx)
+func VisitTsTypOpAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_OP_AFTER, node, key, ctx)
 }
 
-func VisitTsProp(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TsProp)
+func VisitTsTypPredicate(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypPredicate)
 
-	CallVisitor(N_TS_PROP_BEFORE, n, key, ctx)
-	defer CallVisitor(N_TS_PROP_AFTER, n, key, ctx)
+	CallVisitor(N_TS_TYP_PREDICATE_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_PREDICATE_AFTER, n, key, ctx)
 
-	VisitNode(n.Key(), "Key", ctx)
+	VisitNode(n.Name(), "Name", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Val(), "Val", ctx)
+	VisitNode(n.Typ(), "Typ", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTsPropBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PROP_BEFORE, node, key, ctx)
+func VisitTsTypPredicateBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_PREDICATE_BEFORE, node, key, ctx)
 }
 
-func VisitTsPropAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_TS_PROP_AFTER, node, key, ctx)
+func VisitTsTypPredicateAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_PREDICATE_AFTER, node, key, ctx)
 }
 
-func VisitIfStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.IfStmt)
-
-	CallVisitor(N_STMT_IF_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_IF_AFTER, n, key, ctx)
-
-	VisitNode(n.Test(), "Test", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitTsTypQuery(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsTypQuery)
 
-	VisitNode(n.Cons(), "Cons", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_TYP_QUERY_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_TYP_QUERY_AFTER, n, key, ctx)
 
-	VisitNode(n.Alt(), "Alt", ctx)
+	VisitNode(n.Arg(), "Arg", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitIfStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_IF_BEFORE, node, key, ctx)
+func VisitTsTypQueryBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_QUERY_BEFORE, node, key, ctx)
 }
 
-func VisitIfStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_IF_AFTER, node, key, ctx)
+func VisitTsTypQueryAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_TYP_QUERY_AFTER, node, key, ctx)
 }
 
-func VisitTryStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.TryStmt)
-
-	CallVisitor(N_STMT_TRY_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_TRY_AFTER, n, key, ctx)
-
-	VisitNode(n.Try(), "Try", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+func VisitTsUnionTyp(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.TsUnionTyp)
 
-	VisitNode(n.Catch(), "Catch", ctx)
-	if ctx.WalkCtx.Stopped() {
-		return
-	}
+	CallVisitor(N_TS_UNION_TYP_BEFORE, n, key, ctx)
+	defer CallVisitor(N_TS_UNION_TYP_AFTER, n, key, ctx)
 
-	VisitNode(n.Fin(), "Fin", ctx)
+	VisitNodes(n, n.Elems(), "Elems", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitTryStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_TRY_BEFORE, node, key, ctx)
+func VisitTsUnionTypBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_UNION_TYP_BEFORE, node, key, ctx)
 }
 
-func VisitTryStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_TRY_AFTER, node, key, ctx)
+func VisitTsUnionTypAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_TS_UNION_TYP_AFTER, node, key, ctx)
 }
 
-func VisitWithStmt(node parser.Node, key string, ctx *VisitorCtx) {
-	n := node.(*parser.WithStmt)
+func VisitVarDec(node parser.Node, key string, ctx *VisitorCtx) {
+	n := node.(*parser.VarDec)
 
-	CallVisitor(N_STMT_WITH_BEFORE, n, key, ctx)
-	defer CallVisitor(N_STMT_WITH_AFTER, n, key, ctx)
+	CallVisitor(N_VAR_DEC_BEFORE, n, key, ctx)
+	defer CallVisitor(N_VAR_DEC_AFTER, n, key, ctx)
 
-	VisitNode(n.Expr(), "Expr", ctx)
+	VisitNode(n.Id(), "Id", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 
-	VisitNode(n.Body(), "Body", ctx)
+	VisitNode(n.Init(), "Init", ctx)
 	if ctx.WalkCtx.Stopped() {
 		return
 	}
 }
 
-func VisitWithStmtBefore(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_WITH_BEFORE, node, key, ctx)
+func VisitVarDecBefore(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_VAR_DEC_BEFORE, node, key, ctx)
 }
 
-func VisitWithStmtAfter(node parser.Node, key string, ctx *VisitorCtx) {
-	CallListener(N_STMT_WITH_AFTER, node, key, ctx)
+func VisitVarDecAfter(node parser.Node, key string, ctx *VisitorCtx) {
+	CallListener(N_VAR_DEC_AFTER, node, key, ctx)
 }
 
 var DefaultVisitors Visitors = [N_BEFORE_AFTER_DEF_END]Visitor{}
@@ -3657,6 +3723,9 @@ func init() {
 	DefaultVisitors[N_FIELD] = VisitField
 	DefaultVisitors[N_FIELD_BEFORE] = VisitFieldBefore
 	DefaultVisitors[N_FIELD_AFTER] = VisitFieldAfter
+	DefaultVisitors[N_IMPORT_ATTR] = VisitImportAttr
+	DefaultVisitors[N_IMPORT_ATTR_BEFORE] = VisitImportAttrBefore
+	DefaultVisitors[N_IMPORT_ATTR_AFTER] = VisitImportAttrAfter
 	DefaultVisitors[N_IMPORT_CALL] = VisitImportCall
 	DefaultVisitors[N_IMPORT_CALL_BEFORE] = VisitImportCallBefore
 	DefaultVisitors[N_IMPORT_CALL_AFTER] = VisitImportCallAfter
@@ -3816,6 +3885,9 @@ func init() {
 	DefaultVisitors[N_TS_DEC_ENUM] = VisitTsDec
 	DefaultVisitors[N_TS_DEC_ENUM_BEFORE] = VisitTsDecBefore
 	DefaultVisitors[N_TS_DEC_ENUM_AFTER] = VisitTsDecAfter
+	DefaultVisitors[N_TS_DEC_EXPORT] = VisitTsDec
+	DefaultVisitors[N_TS_DEC_EXPORT_BEFORE] = VisitTsDecBefore
+	DefaultVisitors[N_TS_DEC_EXPORT_AFTER] = VisitTsDecAfter
 	DefaultVisitors[N_TS_DEC_FN] = VisitTsDec
 	DefaultVisitors[N_TS_DEC_FN_BEFORE] = VisitTsDecBefore
 	DefaultVisitors[N_TS_DEC_FN_AFTER] = VisitTsDecAfter
@@ -3828,6 +3900,9 @@ func init() {
 	DefaultVisitors[N_TS_DEC_MODULE] = VisitTsDec
 	DefaultVisitors[N_TS_DEC_MODULE_BEFORE] = VisitTsDecBefore
 	DefaultVisitors[N_TS_DEC_MODULE_AFTER] = VisitTsDecAfter
+	DefaultVisitors[N_TS_DEC_MOD_EXPS] = VisitTsDec
+	DefaultVisitors[N_TS_DEC_MOD_EXPS_BEFORE] = VisitTsDecBefore
+	DefaultVisitors[N_TS_DEC_MOD_EXPS_AFTER] = VisitTsDecAfter
 	DefaultVisitors[N_TS_DEC_NS] = VisitTsDec
 	DefaultVisitors[N_TS_DEC_NS_BEFORE] = VisitTsDecBefore
 	DefaultVisitors[N_TS_DEC_NS_AFTER] = VisitTsDecAfter
@@ -3989,6 +4064,7 @@ func init() {
 	DefaultListeners[N_EXPR_UPDATE] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_EXPR_YIELD] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_FIELD] = util.NewOrderedMap[string, *Listener]()
+	DefaultListeners[N_IMPORT_ATTR] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_IMPORT_CALL] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_IMPORT_SPEC] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_JSX_ATTR] = util.NewOrderedMap[string, *Listener]()
@@ -4052,10 +4128,12 @@ func init() {
 	DefaultListeners[N_TS_COND] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_CLASS] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_ENUM] = util.NewOrderedMap[string, *Listener]()
+	DefaultListeners[N_TS_DEC_EXPORT] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_FN] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_GLOBAL] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_INTERFACE] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_MODULE] = util.NewOrderedMap[string, *Listener]()
+	DefaultListeners[N_TS_DEC_MOD_EXPS] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_NS] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_TYP_DEC] = util.NewOrderedMap[string, *Listener]()
 	DefaultListeners[N_TS_DEC_VAR_DEC] = util.NewOrderedMap[string, *Listener]()
@@ -211,7 +211,17 @@ func RecvName(fn *ast.FuncDecl) string {
 		return ""
 	}
 	if v, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
-		return v.X.(*ast.Ident).Name
+		// the receivers of the generic types like `*slab[T]` and `*pair[K, V]`
+		x := v.X
+		switch t := x.(type) {
+		case *ast.IndexExpr:
+			x = t.X
+		case *ast.IndexListExpr:
+			x = t.X
+		}
+		if id, ok := x.(*ast.Ident); ok {
+			return id.Name
+		}
 	}
 	return ""
 }
@@ -11,6 +11,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/hsiaosiyuan0/mole/script/macro"
@@ -155,8 +156,16 @@ func AddAtomListener(ls *Listeners, impl *Listener) {
 }
   `))
 
+	// the visitors are generated in the order of the node types to keep the output stable
+	nodeTyps := make([]string, 0, len(nodeTypStruct))
+	for nodeTyp := range nodeTypStruct {
+		nodeTyps = append(nodeTyps, nodeTyp)
+	}
+	sort.Strings(nodeTyps)
+
 	processedVisitors := map[string]bool{}
-	for _, structName := range nodeTypStruct {
+	for _, nodeTyp := range nodeTyps {
+		structName := nodeTypStruct[nodeTyp]
 		if _, ok := processedVisitors[structName]; ok {
 			continue
 		}
@@ -216,6 +216,15 @@ func (s *Source) SkipSpace() *Source {
 	return s
 }
 
+// moves the cursor to the byte offset `ofst` which should be at the beginning of a rune, it's used
+// to process the source from the middle of it
+func (s *Source) Seek(ofst uint32) {
+	s.state = newSourceState(s.code[ofst:])
+	s.state.ofst = ofst
+	s.state.pos = uint32(utf8.RuneCountInString(s.code[:ofst]))
+	s.ss = s.ss[:0]
+}
+
 // return the string in the span `[start,end)`
 func (s *Source) Text(start, end uint32) string {
 	return s.code[start:end]
@@ -0,0 +1,196 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+)
+
+type DiffOp int
+
+const (
+	DIFF_EQ DiffOp = iota
+	DIFF_DEL
+	DIFF_INS
+)
+
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// computes the shortest edit script which turns the lines `a` into the lines `b` by the
+// Myers' algorithm
+func DiffLines(a, b []string) []DiffLine {
+	n, m := len(a), len(b)
+	max := n + m
+	if max == 0 {
+		return nil
+	}
+
+	// `v[max+k]` is the furthest `x` reached on the diagonal `k`, the snapshots of `v` before
+	// each round are kept to backtrack the edits, the round `d` only reads the diagonals in
+	// `[-d, d]` so the snapshot is limited to them and `trace[d][d+k]` is `v[max+k]`, that
+	// keeps the memory in `O(D^2)` instead of `O(D*(N+M))`
+	v := make([]int, 2*max+2)
+	trace := make([][]int, 0)
+search:
+	for d := 0; d <= max; d++ {
+		snap := make([]int, 2*d+1)
+		copy(snap, v[max-d:max+d+1])
+		trace = append(trace, snap)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[max+k-1] < v[max+k+1] {
+				x = v[max+k+1]
+			} else {
+				x = v[max+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[max+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	ret := make([]DiffLine, 0, max)
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var pk int
+		if k == -d || k != d && v[d+k-1] < v[d+k+1] {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := 0
+		if d > 0 {
+			px = v[d+pk]
+		}
+		py := px - pk
+		for x > px && y > py {
+			ret = append(ret, DiffLine{DIFF_EQ, a[x-1]})
+			x--
+			y--
+		}
+		if d > 0 {
+			if x == px {
+				ret = append(ret, DiffLine{DIFF_INS, b[y-1]})
+			} else {
+				ret = append(ret, DiffLine{DIFF_DEL, a[x-1]})
+			}
+		}
+		x, y = px, py
+	}
+
+	for i, j := 0, len(ret)-1; i < j; i, j = i+1, j-1 {
+		ret[i], ret[j] = ret[j], ret[i]
+	}
+	return ret
+}
+
+// splits the text into lines, the line terminators are kept in the lines
+func splitLines(s string) []string {
+	lines := make([]string, 0)
+	for s != "" {
+		i := strings.IndexByte(s, '\n')
+		if i == -1 {
+			lines = append(lines, s)
+			break
+		}
+		lines = append(lines, s[:i+1])
+		s = s[i+1:]
+	}
+	return lines
+}
+
+// returns the unified diff between `a` and `b` with `context` lines around the changes, the
+// files are labeled by `from` and `to`, the empty string is returned if `a` equals to `b`
+func UnifiedDiff(from, to, a, b string, context int) string {
+	if a == b {
+		return ""
+	}
+	ops := DiffLines(splitLines(a), splitLines(b))
+
+	// the line numbers in `a` and `b` before each op
+	an, bn := make([]int, len(ops)+1), make([]int, len(ops)+1)
+	for i, op := range ops {
+		an[i+1], bn[i+1] = an[i], bn[i]
+		if op.Op != DIFF_INS {
+			an[i+1]++
+		}
+		if op.Op != DIFF_DEL {
+			bn[i+1]++
+		}
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", from, to)
+	for i := 0; i < len(ops); {
+		for i < len(ops) && ops[i].Op == DIFF_EQ {
+			i++
+		}
+		if i == len(ops) {
+			break
+		}
+
+		// the hunk is extended to include the subsequent changes if they are close enough to
+		// share the context lines
+		lo, hi := i-context, i
+		if lo < 0 {
+			lo = 0
+		}
+		for {
+			for hi < len(ops) && ops[hi].Op != DIFF_EQ {
+				hi++
+			}
+			j := hi
+			for j < len(ops) && ops[j].Op == DIFF_EQ {
+				j++
+			}
+			if j < len(ops) && j-hi <= 2*context {
+				hi = j
+				continue
+			}
+			if hi+context < j {
+				j = hi + context
+			}
+			hi = j
+			break
+		}
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(an[lo], an[hi]), hunkRange(bn[lo], bn[hi]))
+		for _, op := range ops[lo:hi] {
+			switch op.Op {
+			case DIFF_EQ:
+				sb.WriteByte(' ')
+			case DIFF_DEL:
+				sb.WriteByte('-')
+			case DIFF_INS:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(op.Text)
+			if !strings.HasSuffix(op.Text, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = hi
+	}
+	return sb.String()
+}
+
+func hunkRange(lo, hi int) string {
+	switch hi - lo {
+	case 0:
+		return fmt.Sprintf("%d,0", lo)
+	case 1:
+		return fmt.Sprint(lo + 1)
+	}
+	return fmt.Sprintf("%d,%d", lo+1, hi-lo)
+}
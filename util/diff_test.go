@@ -0,0 +1,93 @@
+package util
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	ops := DiffLines([]string{"a", "b", "c", "a", "b", "b", "a"}, []string{"c", "b", "a", "b", "a", "c"})
+	dels, ins := 0, 0
+	for _, op := range ops {
+		switch op.Op {
+		case DIFF_DEL:
+			dels++
+		case DIFF_INS:
+			ins++
+		}
+	}
+	AssertEqual(t, 5, dels+ins, "should be ok")
+	AssertEqual(t, 0, len(DiffLines(nil, nil)), "should be ok")
+}
+
+func TestDiffLinesLarge(t *testing.T) {
+	a := make([]string, 0, 20000)
+	for i := 0; i < 20000; i++ {
+		a = append(a, fmt.Sprint(i))
+	}
+	b := append([]string{"head"}, a[:5000]...)
+	b = append(b, a[5001:15000]...)
+	b = append(b, "mid")
+	b = append(b, a[15000:]...)
+
+	ops := DiffLines(a, b)
+	dels, ins := 0, 0
+	as, bs := make([]string, 0), make([]string, 0)
+	for _, op := range ops {
+		switch op.Op {
+		case DIFF_EQ:
+			as = append(as, op.Text)
+			bs = append(bs, op.Text)
+		case DIFF_DEL:
+			dels++
+			as = append(as, op.Text)
+		case DIFF_INS:
+			ins++
+			bs = append(bs, op.Text)
+		}
+	}
+	AssertEqual(t, 1, dels, "should be ok")
+	AssertEqual(t, 2, ins, "should be ok")
+	AssertEqual(t, a, as, "should be ok")
+	AssertEqual(t, b, bs, "should be ok")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n"
+	AssertEqualString(t, `--- a.js
++++ a.js
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -10,3 +10,4 @@
+ 10
+ 11
+ 12
++13
+`, UnifiedDiff("a.js", "a.js", a, b, 3), "should be ok")
+
+	AssertEqualString(t, "", UnifiedDiff("a.js", "a.js", a, a, 3), "should be ok")
+}
+
+func TestUnifiedDiffNoNewline(t *testing.T) {
+	AssertEqualString(t, `--- a
++++ b
+@@ -1,2 +1,2 @@
+ x
+-y
+\ No newline at end of file
++z
+`, UnifiedDiff("a", "b", "x\ny", "x\nz\n", 3), "should be ok")
+
+	AssertEqualString(t, `--- a
++++ b
+@@ -0,0 +1 @@
++x
+`, UnifiedDiff("a", "b", "", "x\n", 3), "should be ok")
+}
@@ -135,6 +135,17 @@ func RemoveJsonComments(str string) ([]byte, error) {
 	return []byte(sb.String()), nil
 }
 
+// the leading whitespaces of the line where the offset locates, it's used to indent the code
+// inserted before the offset as the code around it
+func IndentOf(code string, ofst uint32) string {
+	lo := strings.LastIndexAny(code[:ofst], "\r\n") + 1
+	hi := lo
+	for hi < len(code) && (code[hi] == ' ' || code[hi] == '\t') {
+		hi++
+	}
+	return code[lo:hi]
+}
+
 // cast `[]byte` to string by zero-copy, caller should to ensure
 // the `b` will NOT be changed in the subsequent processes
 // refer: https://github.com/golang/go/blob/a6219737e3eb062282e6483a915c395affb30c69/src/strings/builder.go#L48
@@ -44,3 +44,10 @@ func TestRemoveJsonComments4(t *testing.T) {
 	}
 	AssertEqualString(t, "{\n  \"a\":           \n      \"// not comment\"\n}", string(s), "should be ok")
 }
+
+func TestIndentOf(t *testing.T) {
+	code := "a\n  \tb\r\n    c"
+	AssertEqualString(t, "", IndentOf(code, 0), "should be ok")
+	AssertEqualString(t, "  \t", IndentOf(code, 5), "should be ok")
+	AssertEqualString(t, "    ", IndentOf(code, 13), "should be ok")
+}